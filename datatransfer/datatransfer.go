@@ -0,0 +1,190 @@
+// Contains a registry for vendor-specific DataTransfer messages, which is shared by the OCPP 1.6 and OCPP 2.0.1 facades.
+//
+// The data field of a DataTransfer message is unstructured by definition. The registry maps every
+// (vendorId, messageId) pair to concrete request and response payload types, so that incoming data is
+// decoded and validated before reaching the handler, and outgoing data can be decoded into typed responses:
+//
+//	var getFoo = datatransfer.Message{
+//		VendorID:     "com.example",
+//		MessageID:    "GetFoo",
+//		RequestType:  reflect.TypeOf(GetFooRequest{}),
+//		ResponseType: reflect.TypeOf(GetFooResponse{}),
+//	}
+//	registry := datatransfer.NewRegistry()
+//	registry.Register(getFoo, func(clientID string, data interface{}) (datatransfer.Status, interface{}, error) {
+//		request := data.(*GetFooRequest)
+//		return datatransfer.StatusAccepted, GetFooResponse{Foo: request.Bar}, nil
+//	})
+//	centralSystem.SetDataTransferRegistry(registry)
+//
+// Incoming DataTransfer requests for unregistered vendors are answered with UnknownVendorId,
+// whereas requests for unregistered messages of a known vendor are answered with UnknownMessageId.
+package datatransfer
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp"
+	"github.com/lorenzodonini/ocpp-go/ocppj"
+)
+
+// Status is the status of a DataTransfer response.
+// The values are equal to the DataTransferStatus values defined by both OCPP 1.6 and OCPP 2.0.1.
+type Status string
+
+const (
+	StatusAccepted         Status = "Accepted"
+	StatusRejected         Status = "Rejected"
+	StatusUnknownMessageId Status = "UnknownMessageId"
+	StatusUnknownVendorId  Status = "UnknownVendorId"
+)
+
+// Message describes a vendor-specific DataTransfer message.
+//
+// RequestType and ResponseType are the types of the data payloads of the request and the response.
+// If a type is nil, the respective data is passed along as is, without decoding it.
+type Message struct {
+	VendorID     string
+	MessageID    string
+	RequestType  reflect.Type
+	ResponseType reflect.Type
+}
+
+// Handler processes the data of an incoming DataTransfer request.
+//
+// The clientID is the ID of the sender, or an empty string when invoked on the charge point side.
+// The data is a pointer to a value of the registered request type, or nil if the request contained no data.
+// The returned data is sent within the response, and may be nil.
+// The OCPP 1.6 facades serialize it to a JSON string, as required by the 1.6 schema.
+// Returning an *ocpp.Error causes the request to be answered with the respective CALLERROR.
+type Handler func(clientID string, data interface{}) (Status, interface{}, error)
+
+type entry struct {
+	message Message
+	handler Handler
+}
+
+// Registry maps vendor-specific DataTransfer messages to their handlers. It is safe for concurrent use.
+type Registry struct {
+	mutex   sync.RWMutex
+	vendors map[string]map[string]entry
+}
+
+// NewRegistry creates a new empty registry.
+func NewRegistry() *Registry {
+	return &Registry{vendors: map[string]map[string]entry{}}
+}
+
+// Register adds a message to the registry, replacing any previous handler for the same vendorId and messageId.
+//
+// An empty MessageID matches DataTransfer requests that don't contain a messageId.
+func (r *Registry) Register(message Message, handler Handler) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	messages, ok := r.vendors[message.VendorID]
+	if !ok {
+		messages = map[string]entry{}
+		r.vendors[message.VendorID] = messages
+	}
+	messages[message.MessageID] = entry{message: message, handler: handler}
+}
+
+// Unregister removes a message from the registry. The vendor is removed as well, once it has no more messages.
+func (r *Registry) Unregister(vendorID string, messageID string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	messages, ok := r.vendors[vendorID]
+	if !ok {
+		return
+	}
+	delete(messages, messageID)
+	if len(messages) == 0 {
+		delete(r.vendors, vendorID)
+	}
+}
+
+// Lookup returns the registered message for the given vendorId and messageId.
+func (r *Registry) Lookup(vendorID string, messageID string) (Message, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	e, ok := r.vendors[vendorID][messageID]
+	return e.message, ok
+}
+
+// Handle decodes the data of an incoming DataTransfer request into the registered request type
+// and invokes the respective handler.
+//
+// For unregistered vendors StatusUnknownVendorId is returned, for unregistered messages StatusUnknownMessageId.
+// If the data cannot be decoded or is invalid, an *ocpp.Error is returned.
+func (r *Registry) Handle(clientID string, vendorID string, messageID string, data interface{}) (Status, interface{}, error) {
+	r.mutex.RLock()
+	messages, vendorFound := r.vendors[vendorID]
+	e, messageFound := messages[messageID]
+	r.mutex.RUnlock()
+	if !vendorFound {
+		return StatusUnknownVendorId, nil, nil
+	}
+	if !messageFound {
+		return StatusUnknownMessageId, nil, nil
+	}
+	value, err := Decode(data, e.message.RequestType)
+	if err != nil {
+		return "", nil, err
+	}
+	return e.handler(clientID, value)
+}
+
+// EncodeString serializes the data of an outgoing DataTransfer message to a JSON string.
+//
+// OCPP 1.6 defines the data field as a string, hence typed payloads must be sent as serialized JSON.
+// Strings and nil are returned as is.
+func EncodeString(data interface{}) (interface{}, error) {
+	if data == nil {
+		return nil, nil
+	}
+	if s, ok := data.(string); ok {
+		return s, nil
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, ocpp.NewHandlerError(ocppj.InternalError, fmt.Sprintf("couldn't encode data: %v", err))
+	}
+	return string(raw), nil
+}
+
+// Decode converts the raw data of a DataTransfer message into a pointer to a new value of dataType, then validates it.
+//
+// Data containing a JSON string is decoded from the string contents, unless dataType is a string itself,
+// since many OCPP 1.6 implementations send a serialized JSON object as data.
+// If data is nil, nil is returned. If dataType is nil, data is returned as is.
+//
+// Data that doesn't match dataType causes a TypeConstraintViolation error,
+// while data violating its validation constraints causes a PropertyConstraintViolation error.
+func Decode(data interface{}, dataType reflect.Type) (interface{}, error) {
+	if data == nil || dataType == nil {
+		return data, nil
+	}
+	var raw []byte
+	if s, ok := data.(string); ok && dataType.Kind() != reflect.String {
+		raw = []byte(s)
+	} else {
+		var err error
+		raw, err = json.Marshal(data)
+		if err != nil {
+			return nil, ocpp.NewHandlerError(ocppj.TypeConstraintViolation, fmt.Sprintf("couldn't encode data: %v", err))
+		}
+	}
+	value := reflect.New(dataType)
+	if err := json.Unmarshal(raw, value.Interface()); err != nil {
+		return nil, ocpp.NewHandlerError(ocppj.TypeConstraintViolation, fmt.Sprintf("invalid data for %v: %v", dataType, err))
+	}
+	if dataType.Kind() == reflect.Struct {
+		if err := ocppj.Validate.Struct(value.Interface()); err != nil {
+			return nil, ocpp.NewHandlerError(ocppj.PropertyConstraintViolation, err.Error())
+		}
+	}
+	return value.Interface(), nil
+}
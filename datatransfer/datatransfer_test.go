@@ -0,0 +1,119 @@
+package datatransfer_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lorenzodonini/ocpp-go/datatransfer"
+	"github.com/lorenzodonini/ocpp-go/ocpp"
+	"github.com/lorenzodonini/ocpp-go/ocppj"
+)
+
+type setLimitRequest struct {
+	Connector int     `json:"connector" validate:"gt=0"`
+	Limit     float64 `json:"limit" validate:"gte=0"`
+}
+
+type setLimitResponse struct {
+	Applied bool `json:"applied"`
+}
+
+var setLimit = datatransfer.Message{
+	VendorID:     "com.example",
+	MessageID:    "SetLimit",
+	RequestType:  reflect.TypeOf(setLimitRequest{}),
+	ResponseType: reflect.TypeOf(setLimitResponse{}),
+}
+
+func TestRegistryHandle(t *testing.T) {
+	registry := datatransfer.NewRegistry()
+	registry.Register(setLimit, func(clientID string, data interface{}) (datatransfer.Status, interface{}, error) {
+		assert.Equal(t, "cp1", clientID)
+		request, ok := data.(*setLimitRequest)
+		require.True(t, ok)
+		assert.Equal(t, setLimitRequest{Connector: 1, Limit: 16.5}, *request)
+		return datatransfer.StatusAccepted, setLimitResponse{Applied: true}, nil
+	})
+	// Data received as object
+	status, data, err := registry.Handle("cp1", setLimit.VendorID, setLimit.MessageID, map[string]interface{}{"connector": 1, "limit": 16.5})
+	require.NoError(t, err)
+	assert.Equal(t, datatransfer.StatusAccepted, status)
+	assert.Equal(t, setLimitResponse{Applied: true}, data)
+	// Data received as serialized JSON string
+	status, _, err = registry.Handle("cp1", setLimit.VendorID, setLimit.MessageID, `{"connector":1,"limit":16.5}`)
+	require.NoError(t, err)
+	assert.Equal(t, datatransfer.StatusAccepted, status)
+	// Unknown vendor and message
+	status, data, err = registry.Handle("cp1", "com.other", setLimit.MessageID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, datatransfer.StatusUnknownVendorId, status)
+	assert.Nil(t, data)
+	status, data, err = registry.Handle("cp1", setLimit.VendorID, "GetLimit", nil)
+	require.NoError(t, err)
+	assert.Equal(t, datatransfer.StatusUnknownMessageId, status)
+	assert.Nil(t, data)
+	// Removed messages are unknown again
+	registry.Unregister(setLimit.VendorID, setLimit.MessageID)
+	_, ok := registry.Lookup(setLimit.VendorID, setLimit.MessageID)
+	assert.False(t, ok)
+	status, _, err = registry.Handle("cp1", setLimit.VendorID, setLimit.MessageID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, datatransfer.StatusUnknownVendorId, status)
+}
+
+func TestRegistryHandleInvalidData(t *testing.T) {
+	registry := datatransfer.NewRegistry()
+	registry.Register(setLimit, func(clientID string, data interface{}) (datatransfer.Status, interface{}, error) {
+		t.Fatal("handler must not be invoked for invalid data")
+		return "", nil, nil
+	})
+	_, _, err := registry.Handle("cp1", setLimit.VendorID, setLimit.MessageID, map[string]interface{}{"connector": "one"})
+	require.Error(t, err)
+	ocppErr, ok := err.(*ocpp.Error)
+	require.True(t, ok)
+	assert.Equal(t, ocppj.TypeConstraintViolation, ocppErr.Code)
+	_, _, err = registry.Handle("cp1", setLimit.VendorID, setLimit.MessageID, map[string]interface{}{"connector": 0, "limit": 16})
+	require.Error(t, err)
+	ocppErr, ok = err.(*ocpp.Error)
+	require.True(t, ok)
+	assert.Equal(t, ocppj.PropertyConstraintViolation, ocppErr.Code)
+}
+
+func TestDecode(t *testing.T) {
+	value, err := datatransfer.Decode(map[string]interface{}{"applied": true}, setLimit.ResponseType)
+	require.NoError(t, err)
+	assert.Equal(t, &setLimitResponse{Applied: true}, value)
+	// Strings are kept as is, if a string is expected
+	value, err = datatransfer.Decode("rawData", reflect.TypeOf(""))
+	require.NoError(t, err)
+	assert.Equal(t, "rawData", *value.(*string))
+	// Missing data or type
+	value, err = datatransfer.Decode(nil, setLimit.ResponseType)
+	require.NoError(t, err)
+	assert.Nil(t, value)
+	value, err = datatransfer.Decode("rawData", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "rawData", value)
+}
+
+func TestEncodeString(t *testing.T) {
+	data, err := datatransfer.EncodeString(setLimitRequest{Connector: 1, Limit: 16.5})
+	require.NoError(t, err)
+	assert.Equal(t, `{"connector":1,"limit":16.5}`, data)
+	// Strings and nil are passed along as is
+	data, err = datatransfer.EncodeString("raw")
+	require.NoError(t, err)
+	assert.Equal(t, "raw", data)
+	data, err = datatransfer.EncodeString(nil)
+	require.NoError(t, err)
+	assert.Nil(t, data)
+	// Encoded data can be decoded again
+	decoded, err := datatransfer.Decode(`{"connector":1,"limit":16.5}`, setLimit.RequestType)
+	require.NoError(t, err)
+	assert.Equal(t, setLimitRequest{Connector: 1, Limit: 16.5}, *decoded.(*setLimitRequest))
+	_, err = datatransfer.EncodeString(make(chan int))
+	require.Error(t, err)
+}
@@ -60,3 +60,13 @@ func (cq *CallbackQueue) Dequeue(id string) (func(confirmation ocpp.Response, er
 
 	return callback, ok
 }
+
+func (cq *CallbackQueue) DequeueAll() map[string][]func(confirmation ocpp.Response, err error) {
+	cq.callbacksMutex.Lock()
+	defer cq.callbacksMutex.Unlock()
+
+	callbacks := cq.callbacks
+	cq.callbacks = make(map[string][]func(confirmation ocpp.Response, err error))
+
+	return callbacks
+}
@@ -0,0 +1,156 @@
+// The package contains a server, which accepts charging stations speaking different OCPP versions on the same listener.
+//
+// Each connection is routed to the facade of the OCPP version negotiated during the websocket handshake.
+// If a charging station offers several versions, the highest version supported by both parties is picked.
+package multiversion
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	ocpp16 "github.com/lorenzodonini/ocpp-go/ocpp1.6"
+	types16 "github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
+	ocpp2 "github.com/lorenzodonini/ocpp-go/ocpp2.0.1"
+	types2 "github.com/lorenzodonini/ocpp-go/ocpp2.0.1/types"
+	ocpp21 "github.com/lorenzodonini/ocpp-go/ocpp2.1"
+	types21 "github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+	"github.com/lorenzodonini/ocpp-go/ws"
+)
+
+// Server accepts OCPP 1.6, OCPP 2.0.1 and OCPP 2.1 connections on a single websocket server.
+//
+// The OCPP 1.6 charge points are handled by the CentralSystem, the OCPP 2.0.1 charging stations
+// are handled by the CSMS and the OCPP 2.1 charging stations are handled by the CSMS21.
+// Handlers and callbacks must be set directly on the respective facade:
+//
+//	server := multiversion.NewServer(nil)
+//	server.CentralSystem().SetCoreHandler(coreHandler16)
+//	server.CSMS().SetAuthorizationHandler(authorizationHandler201)
+//	server.CSMS21().SetAuthorizationHandler(authorizationHandler21)
+//	server.Start(8887, "/{ws}")
+//
+// Settings of the websocket layer (e.g. authentication or timeouts) are shared by all versions
+// and should be applied to the websocket server passed to NewServer.
+type Server interface {
+	// CentralSystem returns the facade handling OCPP 1.6 connections.
+	CentralSystem() ocpp16.CentralSystem
+	// CSMS returns the facade handling OCPP 2.0.1 connections.
+	CSMS() ocpp2.CSMS
+	// CSMS21 returns the facade handling OCPP 2.1 connections.
+	CSMS21() ocpp21.CSMS
+	// Router returns the router dispatching connections to the facades.
+	// It may be used to add routes for further subprotocols, which are preferred less than the existing ones.
+	Router() *ws.SubprotocolRouter
+	// Starts the underlying websocket server on a specified listenPort and listenPath.
+	//
+	// The function runs indefinitely, until the server is stopped.
+	// Invoke this function in a separate goroutine, to perform other operations on the main thread.
+	Start(listenPort int, listenPath string)
+	// Serve behaves like Start, but accepts incoming connections on the passed listener.
+	Serve(listener net.Listener, listenPath string)
+	// Handler prepares all facades for accepting incoming connections and returns an http.Handler,
+	// which may be mounted on an external HTTP server.
+	Handler() http.Handler
+	// Stops all facades and the underlying websocket server.
+	Stop()
+	// Shutdown gracefully shuts down all facades and the underlying websocket server.
+	// New connections are refused immediately. Refer to the Shutdown function of the respective facades for details.
+	//
+	// If the context is done before all pending requests completed, the context error is returned.
+	Shutdown(ctx context.Context) error
+}
+
+type server struct {
+	router        *ws.SubprotocolRouter
+	centralSystem ocpp16.CentralSystem
+	csms          ocpp2.CSMS
+	csms21        ocpp21.CSMS
+}
+
+// Creates a new multi-version server, on top of the passed websocket server.
+// If no websocket server is passed, a default one is created:
+//
+//	server := NewServer(nil)
+//
+// If a charging station offers several versions, the connection is handled by the facade of the newest one,
+// e.g. a charging station offering both OCPP 2.0.1 and OCPP 1.6 is handled by the CSMS.
+func NewServer(wsServer ws.Server) Server {
+	if wsServer == nil {
+		wsServer = ws.NewServer()
+	}
+	router := ws.NewSubprotocolRouter(wsServer)
+	// Routes are created from the newest to the oldest version, in order for newer versions to be preferred during negotiation.
+	csms21 := ocpp21.NewCSMS(nil, router.Route(types21.V21Subprotocol))
+	csms := ocpp2.NewCSMS(nil, router.Route(types2.V201Subprotocol))
+	centralSystem := ocpp16.NewCentralSystem(nil, router.Route(types16.V16Subprotocol))
+	return &server{
+		router:        router,
+		centralSystem: centralSystem,
+		csms:          csms,
+		csms21:        csms21,
+	}
+}
+
+func (s *server) CentralSystem() ocpp16.CentralSystem {
+	return s.centralSystem
+}
+
+func (s *server) CSMS() ocpp2.CSMS {
+	return s.csms
+}
+
+func (s *server) CSMS21() ocpp21.CSMS {
+	return s.csms21
+}
+
+func (s *server) Router() *ws.SubprotocolRouter {
+	return s.router
+}
+
+func (s *server) Start(listenPort int, listenPath string) {
+	s.prepare()
+	s.router.Start(listenPort, listenPath)
+}
+
+func (s *server) Serve(listener net.Listener, listenPath string) {
+	s.prepare()
+	s.router.Serve(listener, listenPath)
+}
+
+func (s *server) Handler() http.Handler {
+	s.prepare()
+	return s.router.Handler()
+}
+
+// prepare sets up the ocpp-j endpoints of all facades. The handlers of the routes don't start the websocket server.
+func (s *server) prepare() {
+	s.csms21.Handler()
+	s.csms.Handler()
+	s.centralSystem.Handler()
+}
+
+func (s *server) Stop() {
+	s.csms21.Stop()
+	s.csms.Stop()
+	s.centralSystem.Stop()
+	s.router.Stop()
+}
+
+func (s *server) Shutdown(ctx context.Context) error {
+	// The websocket server immediately stops accepting connections, then waits for the facades to drain their channels
+	shutdowns := []func(ctx context.Context) error{s.csms21.Shutdown, s.csms.Shutdown, s.centralSystem.Shutdown, s.router.Shutdown}
+	errC := make(chan error, len(shutdowns))
+	for _, shutdown := range shutdowns {
+		go func(shutdown func(ctx context.Context) error) {
+			errC <- shutdown(ctx)
+		}(shutdown)
+	}
+	var err error
+	for range shutdowns {
+		if e := <-errC; e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
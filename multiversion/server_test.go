@@ -0,0 +1,116 @@
+package multiversion
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ocpp16 "github.com/lorenzodonini/ocpp-go/ocpp1.6"
+	ocpp2 "github.com/lorenzodonini/ocpp-go/ocpp2.0.1"
+	ocpp21 "github.com/lorenzodonini/ocpp-go/ocpp2.1"
+	"github.com/lorenzodonini/ocpp-go/ws"
+)
+
+const testPort = 8889
+
+func TestServerRoutesByProtocolVersion(t *testing.T) {
+	server := NewServer(nil)
+	connected16 := make(chan string, 2)
+	connected201 := make(chan string, 2)
+	connected21 := make(chan string, 2)
+	server.CentralSystem().SetNewChargePointHandler(func(chargePoint ocpp16.ChargePointConnection) {
+		connected16 <- chargePoint.ID()
+	})
+	server.CSMS().SetNewChargingStationHandler(func(chargingStation ocpp2.ChargingStationConnection) {
+		connected201 <- chargingStation.ID()
+	})
+	server.CSMS21().SetNewChargingStationHandler(func(chargingStation ocpp21.ChargingStationConnection) {
+		connected21 <- chargingStation.ID()
+	})
+	go server.Start(testPort, "/{ws}")
+	defer server.Stop()
+	time.Sleep(100 * time.Millisecond)
+	url := fmt.Sprintf("ws://localhost:%v", testPort)
+
+	chargePoint := ocpp16.NewChargePoint("cp1", nil, nil)
+	require.NoError(t, chargePoint.Start(url))
+	defer chargePoint.Stop()
+	assert.Equal(t, "cp1", <-connected16)
+
+	chargingStation := ocpp2.NewChargingStation("cs1", nil, nil)
+	require.NoError(t, chargingStation.Start(url))
+	defer chargingStation.Stop()
+	assert.Equal(t, "cs1", <-connected201)
+
+	// A client offering both versions is routed to the newest one
+	wsClient := ws.NewClient()
+	wsClient.SetRequestedSubProtocol("ocpp1.6")
+	wsClient.SetRequestedSubProtocol("ocpp2.0.1")
+	chargingStation2 := ocpp2.NewChargingStation("cs2", nil, wsClient)
+	require.NoError(t, chargingStation2.Start(url))
+	defer chargingStation2.Stop()
+	assert.Equal(t, "cs2", <-connected201)
+
+	chargingStation3 := ocpp21.NewChargingStation("cs3", nil, nil)
+	require.NoError(t, chargingStation3.Start(url))
+	defer chargingStation3.Stop()
+	assert.Equal(t, "cs3", <-connected21)
+
+	wsClient2 := ws.NewClient()
+	wsClient2.SetRequestedSubProtocol("ocpp2.0.1")
+	wsClient2.SetRequestedSubProtocol("ocpp2.1")
+	chargingStation4 := ocpp21.NewChargingStation("cs4", nil, wsClient2)
+	require.NoError(t, chargingStation4.Start(url))
+	defer chargingStation4.Stop()
+	assert.Equal(t, "cs4", <-connected21)
+
+	assert.Equal(t, 1, server.CentralSystem().ChargePointConnectionCount())
+	assert.Equal(t, 2, server.CSMS().ChargingStationConnectionCount())
+	assert.Equal(t, 2, server.CSMS21().ChargingStationConnectionCount())
+	_, ok := server.CentralSystem().GetChargePointConnection("cs1")
+	assert.False(t, ok)
+}
+
+func TestServerInMemory(t *testing.T) {
+	network := ws.NewMemoryNetwork()
+	server := NewServer(network.NewServer())
+	connected16 := make(chan ocpp16.ChargePointConnection, 1)
+	connected201 := make(chan ocpp2.ChargingStationConnection, 1)
+	disconnected := make(chan string, 2)
+	server.CentralSystem().SetNewChargePointHandler(func(chargePoint ocpp16.ChargePointConnection) {
+		connected16 <- chargePoint
+	})
+	server.CentralSystem().SetChargePointDisconnectedHandler(func(chargePoint ocpp16.ChargePointConnection) {
+		disconnected <- chargePoint.ID()
+	})
+	server.CSMS().SetNewChargingStationHandler(func(chargingStation ocpp2.ChargingStationConnection) {
+		connected201 <- chargingStation
+	})
+	server.CSMS().SetChargingStationDisconnectedHandler(func(chargingStation ocpp2.ChargingStationConnection) {
+		disconnected <- chargingStation.ID()
+	})
+	go server.Start(testPort, "/{ws}")
+	defer server.Stop()
+	time.Sleep(50 * time.Millisecond)
+	url := fmt.Sprintf("ws://localhost:%v", testPort)
+
+	chargePoint := ocpp16.NewChargePoint("cp1", nil, network.NewClient())
+	require.NoError(t, chargePoint.Start(url))
+	defer chargePoint.Stop()
+	chargePointConn := <-connected16
+	assert.Equal(t, "cp1", chargePointConn.ID())
+
+	chargingStation := ocpp2.NewChargingStation("cs1", nil, network.NewClient())
+	require.NoError(t, chargingStation.Start(url))
+	defer chargingStation.Stop()
+	assert.Equal(t, "cs1", (<-connected201).ID())
+	assert.Equal(t, 2, network.ConnectionCount())
+
+	// Dropping the network connection is noticed by the central system
+	require.True(t, network.Disconnect(chargePointConn.RemoteAddr()))
+	assert.Equal(t, "cp1", <-disconnected)
+	assert.Equal(t, 1, server.CSMS().ChargingStationConnectionCount())
+}
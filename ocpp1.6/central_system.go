@@ -1,9 +1,13 @@
 package ocpp16
 
 import (
+	"context"
 	"fmt"
+	"net"
+	"net/http"
 	"reflect"
 
+	"github.com/lorenzodonini/ocpp-go/datatransfer"
 	"github.com/lorenzodonini/ocpp-go/internal/callbackqueue"
 	"github.com/lorenzodonini/ocpp-go/ocpp"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/certificates"
@@ -33,6 +37,8 @@ type centralSystem struct {
 	logHandler            logging.CentralSystemHandler
 	securityHandler       security.CentralSystemHandler
 	secureFirmwareHandler securefirmware.CentralSystemHandler
+	dataTransferRegistry  *datatransfer.Registry
+	customHandlers        map[string]CustomCentralSystemHandler
 	callbackQueue         callbackqueue.CallbackQueue
 	errC                  chan error
 }
@@ -43,8 +49,9 @@ func newCentralSystem(server *ocppj.Server) centralSystem {
 	}
 	server.SetDialect(ocpp.V16)
 	return centralSystem{
-		server:        server,
-		callbackQueue: callbackqueue.New(),
+		server:         server,
+		callbackQueue:  callbackqueue.New(),
+		customHandlers: map[string]CustomCentralSystemHandler{},
 	}
 }
 
@@ -121,6 +128,25 @@ func (cs *centralSystem) DataTransfer(clientId string, callback func(confirmatio
 	return cs.SendRequestAsync(clientId, request, genericCallback)
 }
 
+func (cs *centralSystem) SendDataTransfer(clientId string, callback func(confirmation *core.DataTransferConfirmation, data interface{}, err error), message datatransfer.Message, data interface{}) error {
+	decodingCallback := func(confirmation *core.DataTransferConfirmation, err error) {
+		if err != nil || confirmation.Status != core.DataTransferStatusAccepted {
+			callback(confirmation, nil, err)
+			return
+		}
+		responseData, err := datatransfer.Decode(confirmation.Data, message.ResponseType)
+		callback(confirmation, responseData, err)
+	}
+	encoded, err := datatransfer.EncodeString(data)
+	if err != nil {
+		return err
+	}
+	return cs.DataTransfer(clientId, decodingCallback, message.VendorID, func(request *core.DataTransferRequest) {
+		request.MessageId = message.MessageID
+		request.Data = encoded
+	})
+}
+
 func (cs *centralSystem) GetConfiguration(clientId string, callback func(confirmation *core.GetConfigurationConfirmation, err error), keys []string, props ...func(request *core.GetConfigurationRequest)) error {
 	request := core.NewGetConfigurationRequest(keys)
 	for _, fn := range props {
@@ -464,6 +490,27 @@ func (cs *centralSystem) SetSecureFirmwareHandler(handler securefirmware.Central
 	cs.secureFirmwareHandler = handler
 }
 
+func (cs *centralSystem) SetDataTransferRegistry(registry *datatransfer.Registry) {
+	cs.dataTransferRegistry = registry
+}
+
+func (cs *centralSystem) AddCustomFeature(feature ocpp.Feature, handler CustomCentralSystemHandler) error {
+	featureName := feature.GetFeatureName()
+	if _, found := cs.server.GetProfileForFeature(featureName); found {
+		return fmt.Errorf("feature %v is already supported on central system", featureName)
+	}
+	profile, found := cs.server.GetProfile(CustomProfileName)
+	if !found {
+		profile = ocpp.NewProfile(CustomProfileName)
+		cs.server.AddProfile(profile)
+	}
+	profile.AddFeature(feature)
+	if handler != nil {
+		cs.customHandlers[featureName] = handler
+	}
+	return nil
+}
+
 func (cs *centralSystem) SetCoreHandler(handler core.CentralSystemHandler) {
 	cs.coreHandler = handler
 }
@@ -502,6 +549,9 @@ func (cs *centralSystem) SetChargePointDisconnectedHandler(handler ChargePointCo
 	cs.server.SetDisconnectedClientHandler(func(chargePoint ws.Channel) {
 		for cb, ok := cs.callbackQueue.Dequeue(chargePoint.ID()); ok; cb, ok = cs.callbackQueue.Dequeue(chargePoint.ID()) {
 			err := ocpp.NewError(ocppj.GenericError, "client disconnected, no response received from client", "")
+			if cs.server.IsShuttingDown() {
+				err = newShutdownError()
+			}
 			cb(nil, err)
 		}
 		handler(chargePoint)
@@ -510,7 +560,8 @@ func (cs *centralSystem) SetChargePointDisconnectedHandler(handler ChargePointCo
 
 func (cs *centralSystem) SendRequestAsync(clientId string, request ocpp.Request, callback func(confirmation ocpp.Response, err error)) error {
 	featureName := request.GetFeatureName()
-	if _, found := cs.server.GetProfileForFeature(featureName); !found {
+	profile, found := cs.server.GetProfileForFeature(featureName)
+	if !found {
 		return fmt.Errorf("feature %v is unsupported on central system (missing profile), cannot send request", featureName)
 	}
 	switch featureName {
@@ -527,7 +578,9 @@ func (cs *centralSystem) SendRequestAsync(clientId string, request ocpp.Request,
 		extendedtriggermessage.ExtendedTriggerMessageFeatureName,
 		certificates.GetInstalledCertificateIdsFeatureName, certificates.DeleteCertificateFeatureName, certificates.InstallCertificateFeatureName:
 	default:
-		return fmt.Errorf("unsupported action %v on central system, cannot send request", featureName)
+		if profile.Name != CustomProfileName {
+			return fmt.Errorf("unsupported action %v on central system, cannot send request", featureName)
+		}
 	}
 
 	send := func() error {
@@ -536,15 +589,80 @@ func (cs *centralSystem) SendRequestAsync(clientId string, request ocpp.Request,
 	return cs.callbackQueue.TryQueue(clientId, send, callback)
 }
 
+func (cs *centralSystem) SetSecurityProfile(profile ws.SecurityProfile) {
+	cs.server.SetSecurityProfile(profile)
+}
+
+func (cs *centralSystem) ChargePointConnections() []ChargePointConnection {
+	channels := cs.server.Clients()
+	connections := make([]ChargePointConnection, len(channels))
+	for i, channel := range channels {
+		connections[i] = channel
+	}
+	return connections
+}
+
+func (cs *centralSystem) ChargePointConnectionCount() int {
+	return cs.server.ClientCount()
+}
+
+func (cs *centralSystem) GetChargePointConnection(chargePointId string) (ChargePointConnection, bool) {
+	channel, ok := cs.server.GetClient(chargePointId)
+	if !ok {
+		return nil, false
+	}
+	return channel, true
+}
+
+func (cs *centralSystem) DisconnectChargePoint(chargePointId string, code int, reason string) error {
+	return cs.server.DisconnectClient(chargePointId, code, reason)
+}
+
 func (cs *centralSystem) Start(listenPort int, listenPath string) {
 	// Start server
 	cs.server.Start(listenPort, listenPath)
 }
 
+func (cs *centralSystem) Serve(listener net.Listener, listenPath string) {
+	// Serve on listener
+	cs.server.Serve(listener, listenPath)
+}
+
+func (cs *centralSystem) Handler() http.Handler {
+	return cs.server.Handler()
+}
+
 func (cs *centralSystem) Stop() {
 	cs.server.Stop()
 }
 
+func (cs *centralSystem) Shutdown(ctx context.Context) error {
+	err := cs.server.Shutdown(ctx)
+	// Notify callbacks of requests, which will never receive a response
+	for _, callbacks := range cs.callbackQueue.DequeueAll() {
+		for _, cb := range callbacks {
+			cb(nil, newShutdownError())
+		}
+	}
+	return err
+}
+
+// handleClientReplaced cancels all pending requests for a charge point, whose connection was replaced by a new connection.
+// The disconnected handler for the old connection is invoked afterwards.
+func (cs *centralSystem) handleClientReplaced(client ws.Channel) {
+	for cb, ok := cs.callbackQueue.Dequeue(client.ID()); ok; cb, ok = cs.callbackQueue.Dequeue(client.ID()) {
+		cb(nil, newConnectionReplacedError())
+	}
+}
+
+func newConnectionReplacedError() *ocpp.Error {
+	return ocpp.NewError(ocppj.GenericError, "connection replaced by a new connection, no response received from client", "")
+}
+
+func newShutdownError() *ocpp.Error {
+	return ocpp.NewError(ocppj.GenericError, "central system shutting down, no response received from client", "")
+}
+
 func (cs *centralSystem) sendResponse(chargePointId string, confirmation ocpp.Response, err error, requestId string) {
 	if err != nil {
 		// Send error response
@@ -607,7 +725,7 @@ func (cs *centralSystem) handleIncomingRequest(chargePoint ChargePointConnection
 	} else {
 		switch profile.Name {
 		case core.ProfileName:
-			if cs.coreHandler == nil {
+			if cs.coreHandler == nil && !(action == core.DataTransferFeatureName && cs.dataTransferRegistry != nil) {
 				cs.notSupportedError(chargePoint.ID(), requestId, action)
 				return
 			}
@@ -651,6 +769,11 @@ func (cs *centralSystem) handleIncomingRequest(chargePoint ChargePointConnection
 				cs.notSupportedError(chargePoint.ID(), requestId, action)
 				return
 			}
+		case CustomProfileName:
+			if _, ok := cs.customHandlers[action]; !ok {
+				cs.notSupportedError(chargePoint.ID(), requestId, action)
+				return
+			}
 		}
 	}
 	var confirmation ocpp.Response
@@ -663,7 +786,11 @@ func (cs *centralSystem) handleIncomingRequest(chargePoint ChargePointConnection
 		case core.AuthorizeFeatureName:
 			confirmation, err = cs.coreHandler.OnAuthorize(chargePoint.ID(), request.(*core.AuthorizeRequest))
 		case core.DataTransferFeatureName:
-			confirmation, err = cs.coreHandler.OnDataTransfer(chargePoint.ID(), request.(*core.DataTransferRequest))
+			if cs.dataTransferRegistry != nil {
+				confirmation, err = cs.handleRegisteredDataTransfer(chargePoint.ID(), request.(*core.DataTransferRequest))
+			} else {
+				confirmation, err = cs.coreHandler.OnDataTransfer(chargePoint.ID(), request.(*core.DataTransferRequest))
+			}
 		case core.HeartbeatFeatureName:
 			confirmation, err = cs.coreHandler.OnHeartbeat(chargePoint.ID(), request.(*core.HeartbeatRequest))
 		case core.MeterValuesFeatureName:
@@ -687,8 +814,12 @@ func (cs *centralSystem) handleIncomingRequest(chargePoint ChargePointConnection
 		case securefirmware.SignedFirmwareStatusNotificationFeatureName:
 			confirmation, err = cs.secureFirmwareHandler.OnSignedFirmwareStatusNotification(chargePoint.ID(), request.(*securefirmware.SignedFirmwareStatusNotificationRequest))
 		default:
-			cs.notSupportedError(chargePoint.ID(), requestId, action)
-			return
+			handler, ok := cs.customHandlers[action]
+			if !ok {
+				cs.notSupportedError(chargePoint.ID(), requestId, action)
+				return
+			}
+			confirmation, err = handler(chargePoint.ID(), request)
 		}
 		cs.sendResponse(chargePoint.ID(), confirmation, err, requestId)
 	}()
@@ -724,3 +855,17 @@ func (cs *centralSystem) handleCanceledRequest(chargePointID string, request ocp
 		cs.error(err)
 	}
 }
+
+func (cs *centralSystem) handleRegisteredDataTransfer(chargePointId string, request *core.DataTransferRequest) (ocpp.Response, error) {
+	status, data, err := cs.dataTransferRegistry.Handle(chargePointId, request.VendorId, request.MessageId, request.Data)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := datatransfer.EncodeString(data)
+	if err != nil {
+		return nil, err
+	}
+	confirmation := core.NewDataTransferConfirmation(core.DataTransferStatus(status))
+	confirmation.Data = encoded
+	return confirmation, nil
+}
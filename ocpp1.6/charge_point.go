@@ -3,7 +3,10 @@ package ocpp16
 import (
 	"fmt"
 	"reflect"
+	"strconv"
+	"time"
 
+	"github.com/lorenzodonini/ocpp-go/datatransfer"
 	"github.com/lorenzodonini/ocpp-go/internal/callbackqueue"
 	"github.com/lorenzodonini/ocpp-go/ocpp"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/certificates"
@@ -19,6 +22,7 @@ import (
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/smartcharging"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
 	"github.com/lorenzodonini/ocpp-go/ocppj"
+	"github.com/lorenzodonini/ocpp-go/ws"
 )
 
 type chargePoint struct {
@@ -34,13 +38,19 @@ type chargePoint struct {
 	extendedTriggerMessageHandler extendedtriggermessage.ChargePointHandler
 	secureFirmwareHandler         securefirmware.ChargePointHandler
 	certificateHandler            certificates.ChargePointHandler
+	dataTransferRegistry          *datatransfer.Registry
+	customHandlers                map[string]CustomChargePointHandler
 	confirmationHandler           chan ocpp.Response
 	errorHandler                  chan error
 	callbacks                     callbackqueue.CallbackQueue
 	stopC                         chan struct{}
 	errC                          chan error // external error channel
+	pingIntervalSync              bool
 }
 
+// WebSocketPingIntervalKey is the standard configuration key for the websocket ping interval, in seconds.
+const WebSocketPingIntervalKey = "WebSocketPingInterval"
+
 func (cp *chargePoint) error(err error) {
 	if cp.errC != nil {
 		cp.errC <- err
@@ -100,6 +110,28 @@ func (cp *chargePoint) DataTransfer(vendorId string, props ...func(request *core
 	}
 }
 
+func (cp *chargePoint) SendDataTransfer(message datatransfer.Message, data interface{}) (*core.DataTransferConfirmation, interface{}, error) {
+	encoded, err := datatransfer.EncodeString(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	confirmation, err := cp.DataTransfer(message.VendorID, func(request *core.DataTransferRequest) {
+		request.MessageId = message.MessageID
+		request.Data = encoded
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if confirmation.Status != core.DataTransferStatusAccepted {
+		return confirmation, nil, nil
+	}
+	responseData, err := datatransfer.Decode(confirmation.Data, message.ResponseType)
+	if err != nil {
+		return confirmation, nil, err
+	}
+	return confirmation, responseData, nil
+}
+
 func (cp *chargePoint) Heartbeat(props ...func(request *core.HeartbeatRequest)) (*core.HeartbeatConfirmation, error) {
 	request := core.NewHeartbeatRequest()
 	for _, fn := range props {
@@ -283,6 +315,27 @@ func (cp *chargePoint) SetCertificateHandler(handler certificates.ChargePointHan
 	cp.certificateHandler = handler
 }
 
+func (cp *chargePoint) SetDataTransferRegistry(registry *datatransfer.Registry) {
+	cp.dataTransferRegistry = registry
+}
+
+func (cp *chargePoint) AddCustomFeature(feature ocpp.Feature, handler CustomChargePointHandler) error {
+	featureName := feature.GetFeatureName()
+	if _, found := cp.client.GetProfileForFeature(featureName); found {
+		return fmt.Errorf("feature %v is already supported on charge point", featureName)
+	}
+	profile, found := cp.client.GetProfile(CustomProfileName)
+	if !found {
+		profile = ocpp.NewProfile(CustomProfileName)
+		cp.client.AddProfile(profile)
+	}
+	profile.AddFeature(feature)
+	if handler != nil {
+		cp.customHandlers[featureName] = handler
+	}
+	return nil
+}
+
 func (cp *chargePoint) SendRequest(request ocpp.Request) (ocpp.Response, error) {
 	featureName := request.GetFeatureName()
 	if _, found := cp.client.GetProfileForFeature(featureName); !found {
@@ -318,7 +371,8 @@ func (cp *chargePoint) SendRequest(request ocpp.Request) (ocpp.Response, error)
 
 func (cp *chargePoint) SendRequestAsync(request ocpp.Request, callback func(confirmation ocpp.Response, err error)) error {
 	featureName := request.GetFeatureName()
-	if _, found := cp.client.GetProfileForFeature(featureName); !found {
+	profile, found := cp.client.GetProfileForFeature(featureName)
+	if !found {
 		return fmt.Errorf("feature %v is unsupported on charge point (missing profile), cannot send request", featureName)
 	}
 	switch featureName {
@@ -329,7 +383,9 @@ func (cp *chargePoint) SendRequestAsync(request ocpp.Request, callback func(conf
 		security.SecurityEventNotificationFeatureName, security.SignCertificateFeatureName:
 		break
 	default:
-		return fmt.Errorf("unsupported action %v on charge point, cannot send request", featureName)
+		if profile.Name != CustomProfileName {
+			return fmt.Errorf("unsupported action %v on charge point, cannot send request", featureName)
+		}
 	}
 	// Response will be retrieved asynchronously via asyncHandler
 	send := func() error {
@@ -413,6 +469,10 @@ func (cp *chargePoint) sendResponse(confirmation ocpp.Response, err error, reque
 	}
 }
 
+func (cp *chargePoint) SetSecurityProfile(profile ws.SecurityProfile) {
+	cp.client.SetSecurityProfile(profile)
+}
+
 func (cp *chargePoint) Start(centralSystemUrl string) error {
 	// Start client
 	cp.stopC = make(chan struct{}, 1)
@@ -424,6 +484,63 @@ func (cp *chargePoint) Start(centralSystemUrl string) error {
 	return err
 }
 
+func (cp *chargePoint) StartWithFailover(config ws.FailoverConfig) error {
+	// Start client
+	cp.stopC = make(chan struct{}, 1)
+	err := cp.client.StartWithFailover(config)
+	// Async response handler receives incoming responses/errors and triggers callbacks
+	if err == nil {
+		go cp.asyncCallbackHandler()
+	}
+	return err
+}
+
+func (cp *chargePoint) ActiveEndpoint() (ws.ServerEndpoint, int, bool) {
+	return cp.client.ActiveEndpoint()
+}
+
+func (cp *chargePoint) Reconnect() error {
+	return cp.client.Reconnect()
+}
+
+func (cp *chargePoint) SetReconnectStrategy(strategy ws.ReconnectStrategy) {
+	cp.client.SetReconnectStrategy(strategy)
+}
+
+func (cp *chargePoint) SetConnectionStateHandler(handler func(event ws.ConnectionStateEvent)) {
+	cp.client.SetConnectionStateHandler(handler)
+}
+
+func (cp *chargePoint) ConnectionState() ws.ConnectionStateEvent {
+	return cp.client.ConnectionState()
+}
+
+func (cp *chargePoint) SetDisconnectedHandler(handler func(reason *ws.DisconnectReason)) {
+	cp.client.SetOnDisconnectedReasonHandler(handler)
+}
+
+func (cp *chargePoint) SetWebSocketPingInterval(interval time.Duration) {
+	cp.client.SetTimeoutConfig(cp.client.TimeoutConfig().WithPingPeriod(interval))
+}
+
+func (cp *chargePoint) SetWebSocketPingIntervalSync(enabled bool) {
+	cp.pingIntervalSync = enabled
+}
+
+// syncPingInterval applies an accepted change of the WebSocketPingInterval configuration key to the connection.
+func (cp *chargePoint) syncPingInterval(request *core.ChangeConfigurationRequest, confirmation ocpp.Response) {
+	conf, ok := confirmation.(*core.ChangeConfigurationConfirmation)
+	if !ok || conf == nil || conf.Status != core.ConfigurationStatusAccepted || request.Key != WebSocketPingIntervalKey {
+		return
+	}
+	seconds, err := strconv.Atoi(request.Value)
+	if err != nil || seconds < 0 {
+		cp.error(fmt.Errorf("invalid value %v for %v", request.Value, WebSocketPingIntervalKey))
+		return
+	}
+	cp.SetWebSocketPingInterval(time.Duration(seconds) * time.Second)
+}
+
 func (cp *chargePoint) Stop() {
 	cp.client.Stop()
 	close(cp.stopC)
@@ -463,7 +580,7 @@ func (cp *chargePoint) handleIncomingRequest(request ocpp.Request, requestId str
 	} else {
 		switch profile.Name {
 		case core.ProfileName:
-			if cp.coreHandler == nil {
+			if cp.coreHandler == nil && !(action == core.DataTransferFeatureName && cp.dataTransferRegistry != nil) {
 				cp.notSupportedError(requestId, action)
 				return
 			}
@@ -517,6 +634,11 @@ func (cp *chargePoint) handleIncomingRequest(request ocpp.Request, requestId str
 				cp.notSupportedError(requestId, action)
 				return
 			}
+		case CustomProfileName:
+			if _, ok := cp.customHandlers[action]; !ok {
+				cp.notSupportedError(requestId, action)
+				return
+			}
 		}
 	}
 
@@ -528,10 +650,17 @@ func (cp *chargePoint) handleIncomingRequest(request ocpp.Request, requestId str
 		confirmation, err = cp.coreHandler.OnChangeAvailability(request.(*core.ChangeAvailabilityRequest))
 	case core.ChangeConfigurationFeatureName:
 		confirmation, err = cp.coreHandler.OnChangeConfiguration(request.(*core.ChangeConfigurationRequest))
+		if err == nil && cp.pingIntervalSync {
+			cp.syncPingInterval(request.(*core.ChangeConfigurationRequest), confirmation)
+		}
 	case core.ClearCacheFeatureName:
 		confirmation, err = cp.coreHandler.OnClearCache(request.(*core.ClearCacheRequest))
 	case core.DataTransferFeatureName:
-		confirmation, err = cp.coreHandler.OnDataTransfer(request.(*core.DataTransferRequest))
+		if cp.dataTransferRegistry != nil {
+			confirmation, err = cp.handleRegisteredDataTransfer(request.(*core.DataTransferRequest))
+		} else {
+			confirmation, err = cp.coreHandler.OnDataTransfer(request.(*core.DataTransferRequest))
+		}
 	case core.GetConfigurationFeatureName:
 		confirmation, err = cp.coreHandler.OnGetConfiguration(request.(*core.GetConfigurationRequest))
 	case core.RemoteStartTransactionFeatureName:
@@ -577,8 +706,26 @@ func (cp *chargePoint) handleIncomingRequest(request ocpp.Request, requestId str
 	case extendedtriggermessage.ExtendedTriggerMessageFeatureName:
 		confirmation, err = cp.extendedTriggerMessageHandler.OnExtendedTriggerMessage(request.(*extendedtriggermessage.ExtendedTriggerMessageRequest))
 	default:
-		cp.notSupportedError(requestId, action)
-		return
+		handler, ok := cp.customHandlers[action]
+		if !ok {
+			cp.notSupportedError(requestId, action)
+			return
+		}
+		confirmation, err = handler(request)
 	}
 	cp.sendResponse(confirmation, err, requestId)
 }
+
+func (cp *chargePoint) handleRegisteredDataTransfer(request *core.DataTransferRequest) (ocpp.Response, error) {
+	status, data, err := cp.dataTransferRegistry.Handle("", request.VendorId, request.MessageId, request.Data)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := datatransfer.EncodeString(data)
+	if err != nil {
+		return nil, err
+	}
+	confirmation := core.NewDataTransferConfirmation(core.DataTransferStatus(status))
+	confirmation.Data = encoded
+	return confirmation, nil
+}
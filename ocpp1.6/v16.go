@@ -2,9 +2,13 @@
 package ocpp16
 
 import (
+	"context"
 	"crypto/tls"
 	"net"
+	"net/http"
+	"time"
 
+	"github.com/lorenzodonini/ocpp-go/datatransfer"
 	"github.com/lorenzodonini/ocpp-go/internal/callbackqueue"
 	"github.com/lorenzodonini/ocpp-go/ocpp"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/certificates"
@@ -20,17 +24,59 @@ import (
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/smartcharging"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
 	"github.com/lorenzodonini/ocpp-go/ocppj"
+	"github.com/lorenzodonini/ocpp-go/ocpps"
 	"github.com/lorenzodonini/ocpp-go/ws"
 )
 
+// profiles contains all OCPP 1.6 profiles, supported out-of-the-box by the default endpoints.
+var profiles = []*ocpp.Profile{
+	core.Profile,
+	localauth.Profile,
+	firmware.Profile,
+	reservation.Profile,
+	remotetrigger.Profile,
+	smartcharging.Profile,
+	logging.Profile,
+	security.Profile,
+	extendedtriggermessage.Profile,
+	certificates.Profile,
+	securefirmware.Profile,
+}
+
 type ChargePointConnection interface {
 	ID() string
 	RemoteAddr() net.Addr
 	TLSConnectionState() *tls.ConnectionState
+	SecurityProfile() ws.SecurityProfile
+	Stats() ws.ConnectionStats
+	// TimeoutConfig returns the timeouts currently used by the connection.
+	TimeoutConfig() ws.ChannelTimeoutConfig
+	// SetTimeoutConfig changes the timeouts of the open connection, without reconnecting the charge point.
+	// E.g. after changing the WebSocketPingInterval of the charge point, the read timeout may be adjusted accordingly.
+	SetTimeoutConfig(config ws.ChannelTimeoutConfig)
+	// DisconnectReason returns the close code, close text, initiator and network error, which caused the connection to be closed.
+	// Returns nil while the connection is open.
+	DisconnectReason() *ws.DisconnectReason
+	// SetAttribute attaches an arbitrary value to the connection, e.g. the tenant or site the charge point belongs to.
+	// Attributes live as long as the connection. Passing a nil value removes the attribute.
+	SetAttribute(key string, value interface{})
+	Attribute(key string) (interface{}, bool)
+	Attributes() map[string]interface{}
 }
 
 type ChargePointConnectionHandler func(chargePoint ChargePointConnection)
 
+// CustomProfileName is the name of the profile, to which all custom features of a charge point or central system are added.
+const CustomProfileName = "Custom"
+
+// CustomChargePointHandler processes incoming requests of a custom feature on a charge point.
+// The returned response must be of the feature's response type.
+type CustomChargePointHandler func(request ocpp.Request) (ocpp.Response, error)
+
+// CustomCentralSystemHandler processes incoming requests of a custom feature on a central system.
+// The returned response must be of the feature's response type.
+type CustomCentralSystemHandler func(chargePointId string, request ocpp.Request) (ocpp.Response, error)
+
 // -------------------- v1.6 Charge Point --------------------
 
 // A Charge Point represents the physical system where an EV can be charged.
@@ -57,6 +103,9 @@ type ChargePoint interface {
 	Authorize(idTag string, props ...func(request *core.AuthorizeRequest)) (*core.AuthorizeConfirmation, error)
 	// Starts a custom data transfer request. Every vendor may implement their own proprietary logic for this message.
 	DataTransfer(vendorId string, props ...func(request *core.DataTransferRequest)) (*core.DataTransferConfirmation, error)
+	// Sends a vendor-specific data transfer request, carrying the passed data serialized as JSON string.
+	// If the central system accepts the request, the response data is decoded into the message's ResponseType and validated.
+	SendDataTransfer(message datatransfer.Message, data interface{}) (*core.DataTransferConfirmation, interface{}, error)
 	// Notifies the central system that the charge point is still online. The central system's response is used for time synchronization purposes. It is recommended to perform this operation once every 24 hours.
 	Heartbeat(props ...func(request *core.HeartbeatRequest)) (*core.HeartbeatConfirmation, error)
 	// Sends a batch of collected meter values to the central system, for billing and analysis. May be done periodically during ongoing transactions.
@@ -82,6 +131,16 @@ type ChargePoint interface {
 
 	// Registers a handler for incoming core profile messages
 	SetCoreHandler(listener core.ChargePointHandler)
+	// Registers typed handlers for incoming vendor-specific data transfer requests.
+	// If a registry is set, all data transfer requests are routed through it instead of the core handler.
+	// Response data returned by the registered handlers is serialized as JSON string.
+	SetDataTransferRegistry(registry *datatransfer.Registry)
+	// Registers a vendor-specific feature, which isn't part of any OCPP 1.6 profile.
+	// Requests of the feature may be sent via SendRequest and SendRequestAsync, like any built-in request.
+	// The handler processes incoming requests of the feature. If nil, the feature may only be sent.
+	// Returns an error if a feature with the same name is already supported.
+	// Custom features must be added before starting the charge point.
+	AddCustomFeature(feature ocpp.Feature, handler CustomChargePointHandler) error
 	// Registers a handler for incoming local authorization profile messages
 	SetLocalAuthListHandler(listener localauth.ChargePointHandler)
 	// Registers a handler for incoming firmware management profile messages
@@ -115,6 +174,11 @@ type ChargePoint interface {
 	// This result is propagated via a callback, called asynchronously.
 	// In case of network issues (i.e. the remote host couldn't be reached), the function returns an error directly. In this case, the callback is never called.
 	SendRequestAsync(request ocpp.Request, callback func(confirmation ocpp.Response, protoError error)) error
+	// SetSecurityProfile sets the OCPP security profile to enforce when connecting to the central system.
+	// Refer to ws.WithClientSecurityProfile for the requirements of each profile.
+	//
+	// The profile must be set before calling Start.
+	SetSecurityProfile(profile ws.SecurityProfile)
 	// Connects to the central system and starts the charge point routine.
 	// The function doesn't block and returns right away, after having attempted to open a connection to the central system.
 	// If the connection couldn't be opened, an error is returned.
@@ -123,6 +187,47 @@ type ChargePoint interface {
 	//
 	// No auto-reconnect logic is implemented as of now, but is planned for the future.
 	Start(centralSystemUrl string) error
+	// StartWithFailover connects to one out of several central system endpoints, ordered by priority.
+	// Each endpoint may have its own URL, TLS configuration and basic auth credentials.
+	// The charging station identifier is appended to each endpoint URL.
+	//
+	// The function blocks until a connection was established, rotating endpoints after repeated failures.
+	// Refer to ws.FailoverConfig for more information.
+	StartWithFailover(config ws.FailoverConfig) error
+	// ActiveEndpoint returns the central system endpoint the client is currently connected to, or attempting to connect to,
+	// along with its index within the failover configuration.
+	// If the client wasn't started via StartWithFailover, the function returns false.
+	ActiveEndpoint() (ws.ServerEndpoint, int, bool)
+	// Reconnect closes the current connection and immediately reconnects to the central system.
+	// Use this function to apply new credentials, e.g. after installing a new client certificate
+	// via a certificate provider (see ws.WithClientCertificateProvider).
+	//
+	// If the connection couldn't be re-established, an error is returned and the client keeps retrying automatically.
+	Reconnect() error
+	// SetReconnectStrategy sets the strategy used for automatic reconnections to the central system.
+	// If not set, the back-off parameters of the websocket client timeout configuration are used.
+	// Refer to ws.ReconnectStrategy for the available strategies.
+	SetReconnectStrategy(strategy ws.ReconnectStrategy)
+	// SetConnectionStateHandler sets a callback, which is invoked whenever the state of the connection to the central system changes,
+	// e.g. when backing off before a reconnection attempt, or when giving up reconnecting.
+	// Refer to ws.ConnectionStateEvent for the information carried by each event.
+	// Disconnection, back-off and give-up events carry the close code, close text, initiator and network error
+	// of the lost connection, or the error of the failed connection attempt (see ws.DisconnectReason).
+	//
+	// The callback is invoked synchronously and should return quickly.
+	SetConnectionStateHandler(handler func(event ws.ConnectionStateEvent))
+	// ConnectionState returns the most recent state of the connection to the central system.
+	ConnectionState() ws.ConnectionStateEvent
+	// SetDisconnectedHandler sets a callback, which is invoked when the connection to the central system is lost.
+	// The passed reason contains the close code, close text, initiator and network error of the lost connection.
+	SetDisconnectedHandler(handler func(reason *ws.DisconnectReason))
+	// SetWebSocketPingInterval changes the interval for sending websocket pings to the central system, without reconnecting.
+	// The pong wait is adjusted accordingly (see ws.ClientTimeoutConfig.WithPingPeriod). A zero interval disables pings.
+	SetWebSocketPingInterval(interval time.Duration)
+	// SetWebSocketPingIntervalSync enables or disables the automatic handling of the WebSocketPingInterval configuration key.
+	// If enabled, whenever the core handler accepts a ChangeConfiguration request for the key,
+	// the new value (in seconds) is applied to the connection via SetWebSocketPingInterval.
+	SetWebSocketPingIntervalSync(enabled bool)
 	// Stops the charge point routine, disconnecting it from the central system.
 	// Any pending requests are discarded.
 	Stop()
@@ -172,17 +277,7 @@ func NewChargePoint(id string, endpoint *ocppj.Client, client ws.Client) ChargeP
 			client,
 			dispatcher,
 			nil,
-			core.Profile,
-			localauth.Profile,
-			firmware.Profile,
-			reservation.Profile,
-			remotetrigger.Profile,
-			smartcharging.Profile,
-			logging.Profile,
-			security.Profile,
-			extendedtriggermessage.Profile,
-			certificates.Profile,
-			securefirmware.Profile,
+			profiles...,
 		)
 	}
 	endpoint.SetDialect(ocpp.V16)
@@ -192,6 +287,7 @@ func NewChargePoint(id string, endpoint *ocppj.Client, client ws.Client) ChargeP
 		confirmationHandler: make(chan ocpp.Response, 1),
 		errorHandler:        make(chan error, 1),
 		callbacks:           callbackqueue.New(),
+		customHandlers:      map[string]CustomChargePointHandler{},
 	}
 
 	// Callback invoked by dispatcher, whenever a queued request is canceled, due to timeout.
@@ -207,6 +303,25 @@ func NewChargePoint(id string, endpoint *ocppj.Client, client ws.Client) ChargeP
 	return &cp
 }
 
+// NewSOAPChargePoint creates a new ChargePoint, which communicates with the central system via OCPP-S (SOAP over HTTP).
+// If no client is passed, a default one is created. All OCPP 1.6 profiles are added to the client.
+//
+// The URL passed to Start is the OCPP-S endpoint of the central system. To receive requests from the central system,
+// the client requires an endpoint address and either a listen address or an external HTTP server:
+//
+//	client := ocpps.NewClient()
+//	client.SetEndpointAddress("http://10.0.0.5:8080/ocpp")
+//	client.SetListenAddress(":8080")
+//	cp := NewSOAPChargePoint("someUniqueId", client)
+//	err := cp.Start("http://centralsystem.example.com/ocpp")
+func NewSOAPChargePoint(id string, client *ocpps.Client) ChargePoint {
+	if client == nil {
+		client = ocpps.NewClient()
+	}
+	client.AddProfile(profiles...)
+	return NewChargePoint(id, nil, client)
+}
+
 // -------------------- v1.6 Central System --------------------
 
 // A Central System manages Charge Points and has the information for authorizing users for using its Charge Points.
@@ -240,6 +355,9 @@ type CentralSystem interface {
 	ClearCache(clientId string, callback func(*core.ClearCacheConfirmation, error), props ...func(*core.ClearCacheRequest)) error
 	// Starts a custom data transfer request. Every vendor may implement their own proprietary logic for this message.
 	DataTransfer(clientId string, callback func(*core.DataTransferConfirmation, error), vendorId string, props ...func(*core.DataTransferRequest)) error
+	// Sends a vendor-specific data transfer request, carrying the passed data serialized as JSON string.
+	// If the charge point accepts the request, the response data is decoded into the message's ResponseType and validated, before invoking the callback.
+	SendDataTransfer(clientId string, callback func(confirmation *core.DataTransferConfirmation, data interface{}, err error), message datatransfer.Message, data interface{}) error
 	// Retrieves the configuration values for the provided configuration keys.
 	GetConfiguration(clientId string, callback func(*core.GetConfigurationConfirmation, error), keys []string, props ...func(*core.GetConfigurationRequest)) error
 	// Instructs a charge point to start a transaction for a specified client on a provided connector.
@@ -288,6 +406,16 @@ type CentralSystem interface {
 
 	// Registers a handler for incoming core profile messages.
 	SetCoreHandler(handler core.CentralSystemHandler)
+	// Registers typed handlers for incoming vendor-specific data transfer requests.
+	// If a registry is set, all data transfer requests are routed through it instead of the core handler.
+	// Response data returned by the registered handlers is serialized as JSON string.
+	SetDataTransferRegistry(registry *datatransfer.Registry)
+	// Registers a vendor-specific feature, which isn't part of any OCPP 1.6 profile.
+	// Requests of the feature may be sent via SendRequestAsync, like any built-in request.
+	// The handler processes incoming requests of the feature. If nil, the feature may only be sent.
+	// Returns an error if a feature with the same name is already supported.
+	// Custom features must be added before starting the central system.
+	AddCustomFeature(feature ocpp.Feature, handler CustomCentralSystemHandler) error
 	// Registers a handler for incoming local authorization profile messages.
 	SetLocalAuthListHandler(handler localauth.CentralSystemHandler)
 	// Registers a handler for incoming firmware management profile messages.
@@ -310,19 +438,58 @@ type CentralSystem interface {
 	// Registers a handler for new incoming charge point connections.
 	SetNewChargePointHandler(handler ChargePointConnectionHandler)
 	// Registers a handler for charge point disconnections.
+	// The reason for the disconnection is available via the DisconnectReason function of the passed connection.
 	SetChargePointDisconnectedHandler(handler ChargePointConnectionHandler)
 	// Sends an asynchronous request to the charge point.
 	// The charge point will respond with a confirmation message, or with an error if the request was invalid or could not be processed.
 	// This result is propagated via a callback, called asynchronously.
 	// In case of network issues (i.e. the remote host couldn't be reached), the function returns an error directly. In this case, the callback is never called.
 	SendRequestAsync(clientId string, request ocpp.Request, callback func(ocpp.Response, error)) error
+	// SetSecurityProfile sets the OCPP security profile to enforce on all incoming charge point connections.
+	// Refer to ws.WithServerSecurityProfile for the requirements of each profile.
+	//
+	// The profile must be set before calling Start.
+	SetSecurityProfile(profile ws.SecurityProfile)
+	// ChargePointConnections returns all connected charge points, sorted by their ID.
+	ChargePointConnections() []ChargePointConnection
+	// ChargePointConnectionCount returns the number of connected charge points.
+	ChargePointConnectionCount() int
+	// GetChargePointConnection returns the connection of a charge point, identified by its ID.
+	// If the charge point isn't connected, the function returns false.
+	GetChargePointConnection(chargePointId string) (ChargePointConnection, bool)
+	// DisconnectChargePoint closes the connection to a charge point, sending a close frame with the passed close code and reason
+	// (e.g. websocket.ClosePolicyViolation). Callbacks for pending requests are invoked with an error.
+	//
+	// An error is returned, if the charge point isn't connected.
+	DisconnectChargePoint(chargePointId string, code int, reason string) error
 	// Starts running the central system on the specified port and URL.
 	// The central system runs as a daemon and handles incoming charge point connections and messages.
 
 	// The function blocks forever, so it is suggested to wrap it in a goroutine, in case other functionality needs to be executed on the main program thread.
 	Start(listenPort int, listenPath string)
+	// Serve behaves like Start, but accepts incoming charge point connections on the passed listener
+	// (e.g. a unix socket or a listener created via systemd socket activation).
+	//
+	// The function blocks forever, so it is suggested to wrap it in a goroutine.
+	Serve(listener net.Listener, listenPath string)
+	// Handler returns an http.Handler, which accepts incoming charge point connections
+	// and may be mounted on an existing HTTP server, e.g.:
+	//	router.Handle("/ocpp/{id}", centralSystem.Handler())
+	//
+	// Connections are handled exactly like with Start. The function doesn't block.
+	// The lifecycle of the external HTTP server, including TLS termination, is up to the caller.
+	Handler() http.Handler
 	// Stops the central system, clearing all pending requests.
 	Stop()
+	// Gracefully shuts down the central system.
+	//
+	// New charge point connections are refused and no new requests may be sent.
+	// The function waits for pending requests to complete (or time out), until the passed context is done.
+	// All callbacks of requests, that didn't receive a response, are then invoked with a shutdown error.
+	// Finally, all charge point connections are closed with a "going away" close frame.
+	//
+	// If the context is done before all pending requests completed, the context error is returned.
+	Shutdown(ctx context.Context) error
 	// Errors returns a channel for error messages. If it doesn't exist it es created.
 	Errors() <-chan error
 }
@@ -345,19 +512,7 @@ func NewCentralSystem(endpoint *ocppj.Server, server ws.Server) CentralSystem {
 	}
 	server.AddSupportedSubprotocol(types.V16Subprotocol)
 	if endpoint == nil {
-		endpoint = ocppj.NewServer(server, nil, nil,
-			core.Profile,
-			localauth.Profile,
-			firmware.Profile,
-			reservation.Profile,
-			remotetrigger.Profile,
-			smartcharging.Profile,
-			logging.Profile,
-			security.Profile,
-			extendedtriggermessage.Profile,
-			certificates.Profile,
-			securefirmware.Profile,
-		)
+		endpoint = ocppj.NewServer(server, nil, nil, profiles...)
 	}
 	cs := newCentralSystem(endpoint)
 	cs.server.SetRequestHandler(func(client ws.Channel, request ocpp.Request, requestId string, action string) {
@@ -372,5 +527,24 @@ func NewCentralSystem(endpoint *ocppj.Server, server ws.Server) CentralSystem {
 	cs.server.SetCanceledRequestHandler(func(clientID string, requestID string, request ocpp.Request, err *ocpp.Error) {
 		cs.handleCanceledRequest(clientID, request, err)
 	})
+	cs.server.SetClientReplacedHandler(cs.handleClientReplaced)
 	return &cs
 }
+
+// NewSOAPCentralSystem creates a new CentralSystem, which accepts charge points via OCPP-S (SOAP over HTTP)
+// as well as via OCPP-J (websocket) on the same port and path.
+// If no server is passed, a default one is created. All OCPP 1.6 profiles are added to the server.
+//
+// The same handlers serve both kinds of charge points:
+//
+//	server := ocpps.NewServer(ws.NewServer())
+//	cs := NewSOAPCentralSystem(server)
+//	cs.SetCoreHandler(handler)
+//	cs.Start(8887, "/ocpp/{ws}")
+func NewSOAPCentralSystem(server *ocpps.Server) CentralSystem {
+	if server == nil {
+		server = ocpps.NewServer(nil)
+	}
+	server.AddProfile(profiles...)
+	return NewCentralSystem(nil, server)
+}
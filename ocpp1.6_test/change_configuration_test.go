@@ -2,8 +2,11 @@ package ocpp16_test
 
 import (
 	"fmt"
+	"time"
 
+	ocpp16 "github.com/lorenzodonini/ocpp-go/ocpp1.6"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+	"github.com/lorenzodonini/ocpp-go/ws"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -74,6 +77,47 @@ func (suite *OcppV16TestSuite) TestChangeConfigurationE2EMocked() {
 	assert.True(t, result)
 }
 
+func (suite *OcppV16TestSuite) TestChangeConfigurationWebSocketPingIntervalSync() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	key := ocpp16.WebSocketPingIntervalKey
+	value := "30"
+	status := core.ConfigurationStatusAccepted
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"key":"%v","value":"%v"}]`, messageId, core.ChangeConfigurationFeatureName, key, value)
+	responseJson := fmt.Sprintf(`[3,"%v",{"status":"%v"}]`, messageId, status)
+	channel := NewMockWebSocket(wsId)
+
+	coreListener := &MockChargePointCoreListener{}
+	coreListener.On("OnChangeConfiguration", mock.Anything).Return(core.NewChangeConfigurationConfirmation(status), nil)
+	setupDefaultCentralSystemHandlers(suite, nil, expectedCentralSystemOptions{clientId: wsId, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	setupDefaultChargePointHandlers(suite, coreListener, expectedChargePointOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(responseJson), forwardWrittenMessage: true})
+	suite.mockWsClient.On("TimeoutConfig").Return(ws.NewClientTimeoutConfig())
+	appliedC := make(chan ws.ClientTimeoutConfig, 1)
+	suite.mockWsClient.On("SetTimeoutConfig", mock.Anything).Run(func(args mock.Arguments) {
+		appliedC <- args.Get(0).(ws.ClientTimeoutConfig)
+	})
+	suite.chargePoint.SetWebSocketPingIntervalSync(true)
+	// Run Test
+	suite.centralSystem.Start(8887, "somePath")
+	err := suite.chargePoint.Start(wsUrl)
+	require.Nil(t, err)
+	resultChannel := make(chan bool, 1)
+	err = suite.centralSystem.ChangeConfiguration(wsId, func(confirmation *core.ChangeConfigurationConfirmation, err error) {
+		require.Nil(t, err)
+		require.NotNil(t, confirmation)
+		assert.Equal(t, status, confirmation.Status)
+		resultChannel <- true
+	}, key, value)
+	require.Nil(t, err)
+	result := <-resultChannel
+	assert.True(t, result)
+	config := <-appliedC
+	assert.Equal(t, 30*time.Second, config.PingPeriod)
+	assert.Equal(t, 30*time.Second*10/9, config.PongWait)
+}
+
 func (suite *OcppV16TestSuite) TestChangeConfigurationInvalidEndpoint() {
 	messageId := defaultMessageId
 	key := "someKey"
@@ -0,0 +1,168 @@
+package ocpp16_test
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp"
+	ocpp16 "github.com/lorenzodonini/ocpp-go/ocpp1.6"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+	"github.com/lorenzodonini/ocpp-go/ocppj"
+)
+
+// Vendor-specific feature, not defined by OCPP 1.6
+const setLedColorFeatureName = "SetLedColor"
+
+type SetLedColorRequest struct {
+	ConnectorId int    `json:"connectorId" validate:"gte=0"`
+	Color       string `json:"color" validate:"required,oneof=red green blue"`
+}
+
+type SetLedColorConfirmation struct {
+	Accepted bool `json:"accepted"`
+}
+
+type SetLedColorFeature struct{}
+
+func (f SetLedColorFeature) GetFeatureName() string {
+	return setLedColorFeatureName
+}
+
+func (f SetLedColorFeature) GetRequestType() reflect.Type {
+	return reflect.TypeOf(SetLedColorRequest{})
+}
+
+func (f SetLedColorFeature) GetResponseType() reflect.Type {
+	return reflect.TypeOf(SetLedColorConfirmation{})
+}
+
+func (r SetLedColorRequest) GetFeatureName() string {
+	return setLedColorFeatureName
+}
+
+func (c SetLedColorConfirmation) GetFeatureName() string {
+	return setLedColorFeatureName
+}
+
+// Test
+func (suite *OcppV16TestSuite) TestAddCustomFeature() {
+	t := suite.T()
+	err := suite.chargePoint.AddCustomFeature(SetLedColorFeature{}, nil)
+	require.NoError(t, err)
+	profile, ok := suite.ocppjChargePoint.GetProfile(ocpp16.CustomProfileName)
+	require.True(t, ok)
+	assert.True(t, profile.SupportsFeature(setLedColorFeatureName))
+	// Features cannot be registered twice, nor override built-in features
+	err = suite.chargePoint.AddCustomFeature(SetLedColorFeature{}, nil)
+	assert.EqualError(t, err, fmt.Sprintf("feature %v is already supported on charge point", setLedColorFeatureName))
+	err = suite.centralSystem.AddCustomFeature(core.HeartbeatFeature{}, nil)
+	assert.EqualError(t, err, fmt.Sprintf("feature %v is already supported on central system", core.HeartbeatFeatureName))
+}
+
+func (suite *OcppV16TestSuite) TestCustomFeatureFromChargePointE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	connectorId := 1
+	color := "green"
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"connectorId":%v,"color":"%v"}]`, messageId, setLedColorFeatureName, connectorId, color)
+	responseJson := fmt.Sprintf(`[3,"%v",{"accepted":true}]`, messageId)
+	channel := NewMockWebSocket(wsId)
+
+	setupDefaultCentralSystemHandlers(suite, nil, expectedCentralSystemOptions{clientId: wsId, rawWrittenMessage: []byte(responseJson), forwardWrittenMessage: true})
+	setupDefaultChargePointHandlers(suite, nil, expectedChargePointOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	err := suite.centralSystem.AddCustomFeature(SetLedColorFeature{}, func(chargePointId string, request ocpp.Request) (ocpp.Response, error) {
+		assert.Equal(t, wsId, chargePointId)
+		setLedColor, ok := request.(*SetLedColorRequest)
+		require.True(t, ok)
+		assert.Equal(t, connectorId, setLedColor.ConnectorId)
+		assert.Equal(t, color, setLedColor.Color)
+		return &SetLedColorConfirmation{Accepted: true}, nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, suite.chargePoint.AddCustomFeature(SetLedColorFeature{}, nil))
+	// Run Test
+	suite.centralSystem.Start(8887, "somePath")
+	err = suite.chargePoint.Start(wsUrl)
+	require.Nil(t, err)
+	// Invalid requests are rejected before being sent
+	_, err = suite.chargePoint.SendRequest(&SetLedColorRequest{ConnectorId: connectorId, Color: "pink"})
+	require.Error(t, err)
+	confirmation, err := suite.chargePoint.SendRequest(&SetLedColorRequest{ConnectorId: connectorId, Color: color})
+	require.Nil(t, err)
+	require.IsType(t, &SetLedColorConfirmation{}, confirmation)
+	assert.True(t, confirmation.(*SetLedColorConfirmation).Accepted)
+}
+
+func (suite *OcppV16TestSuite) TestCustomFeatureFromCentralSystemE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	connectorId := 2
+	color := "red"
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"connectorId":%v,"color":"%v"}]`, messageId, setLedColorFeatureName, connectorId, color)
+	responseJson := fmt.Sprintf(`[3,"%v",{"accepted":false}]`, messageId)
+	channel := NewMockWebSocket(wsId)
+
+	setupDefaultCentralSystemHandlers(suite, nil, expectedCentralSystemOptions{clientId: wsId, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	setupDefaultChargePointHandlers(suite, nil, expectedChargePointOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(responseJson), forwardWrittenMessage: true})
+	err := suite.chargePoint.AddCustomFeature(SetLedColorFeature{}, func(request ocpp.Request) (ocpp.Response, error) {
+		setLedColor, ok := request.(*SetLedColorRequest)
+		require.True(t, ok)
+		assert.Equal(t, connectorId, setLedColor.ConnectorId)
+		assert.Equal(t, color, setLedColor.Color)
+		return &SetLedColorConfirmation{Accepted: false}, nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, suite.centralSystem.AddCustomFeature(SetLedColorFeature{}, nil))
+	// Run Test
+	suite.centralSystem.Start(8887, "somePath")
+	err = suite.chargePoint.Start(wsUrl)
+	require.Nil(t, err)
+	resultChannel := make(chan bool, 1)
+	err = suite.centralSystem.SendRequestAsync(wsId, &SetLedColorRequest{ConnectorId: connectorId, Color: color}, func(confirmation ocpp.Response, err error) {
+		require.Nil(t, err)
+		require.IsType(t, &SetLedColorConfirmation{}, confirmation)
+		assert.False(t, confirmation.(*SetLedColorConfirmation).Accepted)
+		resultChannel <- true
+	})
+	require.Nil(t, err)
+	result := <-resultChannel
+	assert.True(t, result)
+}
+
+func (suite *OcppV16TestSuite) TestCustomFeatureWithoutHandlerE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"connectorId":0,"color":"blue"}]`, messageId, setLedColorFeatureName)
+	errorJson := fmt.Sprintf(`[4,"%v","%v","unsupported action %v on charge point",{}]`, messageId, ocppj.NotSupported, setLedColorFeatureName)
+	channel := NewMockWebSocket(wsId)
+
+	setupDefaultCentralSystemHandlers(suite, nil, expectedCentralSystemOptions{clientId: wsId, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	setupDefaultChargePointHandlers(suite, nil, expectedChargePointOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(errorJson), forwardWrittenMessage: true})
+	require.NoError(t, suite.chargePoint.AddCustomFeature(SetLedColorFeature{}, nil))
+	require.NoError(t, suite.centralSystem.AddCustomFeature(SetLedColorFeature{}, nil))
+	// Run Test
+	suite.centralSystem.Start(8887, "somePath")
+	err := suite.chargePoint.Start(wsUrl)
+	require.Nil(t, err)
+	resultChannel := make(chan bool, 1)
+	err = suite.centralSystem.SendRequestAsync(wsId, &SetLedColorRequest{Color: "blue"}, func(confirmation ocpp.Response, err error) {
+		require.Error(t, err)
+		assert.Nil(t, confirmation)
+		ocppErr, ok := err.(*ocpp.Error)
+		require.True(t, ok)
+		assert.Equal(t, ocppj.NotSupported, ocppErr.Code)
+		resultChannel <- true
+	})
+	require.Nil(t, err)
+	result := <-resultChannel
+	assert.True(t, result)
+}
@@ -3,7 +3,9 @@ package ocpp16_test
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
 
+	"github.com/lorenzodonini/ocpp-go/datatransfer"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -134,3 +136,120 @@ func (suite *OcppV16TestSuite) TestDataTransferFromCentralSystemE2EMocked() {
 	result := <-resultChannel
 	assert.True(t, result)
 }
+
+type CustomResponseData struct {
+	Result string `json:"result" validate:"required"`
+}
+
+var customDataMessage = datatransfer.Message{
+	VendorID:     "vendor1",
+	MessageID:    "message1",
+	RequestType:  reflect.TypeOf(CustomData{}),
+	ResponseType: reflect.TypeOf(CustomResponseData{}),
+}
+
+func (suite *OcppV16TestSuite) TestDataTransferRegistryFromChargePointE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	data := CustomData{Field1: "dummyData", Field2: 42}
+	responseData := CustomResponseData{Result: "ok"}
+	status := core.DataTransferStatusAccepted
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"vendorId":"%v","messageId":"%v","data":"{\"field1\":\"%v\",\"field2\":%v}"}]`, messageId, core.DataTransferFeatureName, customDataMessage.VendorID, customDataMessage.MessageID, data.Field1, data.Field2)
+	responseJson := fmt.Sprintf(`[3,"%v",{"status":"%v","data":"{\"result\":\"%v\"}"}]`, messageId, status, responseData.Result)
+	channel := NewMockWebSocket(wsId)
+
+	registry := datatransfer.NewRegistry()
+	registry.Register(customDataMessage, func(clientID string, request interface{}) (datatransfer.Status, interface{}, error) {
+		assert.Equal(t, wsId, clientID)
+		customData, ok := request.(*CustomData)
+		require.True(t, ok)
+		assert.Equal(t, data, *customData)
+		return datatransfer.StatusAccepted, responseData, nil
+	})
+	// No core handler is needed for routing data transfer messages via the registry
+	setupDefaultCentralSystemHandlers(suite, nil, expectedCentralSystemOptions{clientId: wsId, rawWrittenMessage: []byte(responseJson), forwardWrittenMessage: true})
+	setupDefaultChargePointHandlers(suite, nil, expectedChargePointOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	suite.centralSystem.SetDataTransferRegistry(registry)
+	// Run Test
+	suite.centralSystem.Start(8887, "somePath")
+	err := suite.chargePoint.Start(wsUrl)
+	require.Nil(t, err)
+	confirmation, result, err := suite.chargePoint.SendDataTransfer(customDataMessage, data)
+	require.Nil(t, err)
+	require.NotNil(t, confirmation)
+	assert.Equal(t, status, confirmation.Status)
+	require.IsType(t, &CustomResponseData{}, result)
+	assert.Equal(t, responseData, *result.(*CustomResponseData))
+}
+
+func (suite *OcppV16TestSuite) TestDataTransferRegistryUnknownMessageE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	unknownMessage := datatransfer.Message{VendorID: customDataMessage.VendorID, MessageID: "unknownMessage"}
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"vendorId":"%v","messageId":"%v"}]`, messageId, core.DataTransferFeatureName, unknownMessage.VendorID, unknownMessage.MessageID)
+	responseJson := fmt.Sprintf(`[3,"%v",{"status":"%v"}]`, messageId, core.DataTransferStatusUnknownMessageId)
+	channel := NewMockWebSocket(wsId)
+
+	registry := datatransfer.NewRegistry()
+	registry.Register(customDataMessage, func(clientID string, request interface{}) (datatransfer.Status, interface{}, error) {
+		t.Fatal("unexpected handler invocation")
+		return "", nil, nil
+	})
+	setupDefaultCentralSystemHandlers(suite, nil, expectedCentralSystemOptions{clientId: wsId, rawWrittenMessage: []byte(responseJson), forwardWrittenMessage: true})
+	setupDefaultChargePointHandlers(suite, nil, expectedChargePointOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	suite.centralSystem.SetDataTransferRegistry(registry)
+	// Run Test
+	suite.centralSystem.Start(8887, "somePath")
+	err := suite.chargePoint.Start(wsUrl)
+	require.Nil(t, err)
+	confirmation, result, err := suite.chargePoint.SendDataTransfer(unknownMessage, nil)
+	require.Nil(t, err)
+	require.NotNil(t, confirmation)
+	assert.Equal(t, core.DataTransferStatusUnknownMessageId, confirmation.Status)
+	assert.Nil(t, result)
+}
+
+func (suite *OcppV16TestSuite) TestDataTransferRegistryFromCentralSystemE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	data := CustomData{Field1: "dummyData", Field2: 42}
+	responseData := CustomResponseData{Result: "ok"}
+	status := core.DataTransferStatusAccepted
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"vendorId":"%v","messageId":"%v","data":"{\"field1\":\"%v\",\"field2\":%v}"}]`, messageId, core.DataTransferFeatureName, customDataMessage.VendorID, customDataMessage.MessageID, data.Field1, data.Field2)
+	responseJson := fmt.Sprintf(`[3,"%v",{"status":"%v","data":"{\"result\":\"%v\"}"}]`, messageId, status, responseData.Result)
+	channel := NewMockWebSocket(wsId)
+
+	registry := datatransfer.NewRegistry()
+	registry.Register(customDataMessage, func(clientID string, request interface{}) (datatransfer.Status, interface{}, error) {
+		assert.Empty(t, clientID)
+		customData, ok := request.(*CustomData)
+		require.True(t, ok)
+		assert.Equal(t, data, *customData)
+		return datatransfer.StatusAccepted, responseData, nil
+	})
+	setupDefaultCentralSystemHandlers(suite, nil, expectedCentralSystemOptions{clientId: wsId, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	setupDefaultChargePointHandlers(suite, nil, expectedChargePointOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(responseJson), forwardWrittenMessage: true})
+	suite.chargePoint.SetDataTransferRegistry(registry)
+	// Run Test
+	suite.centralSystem.Start(8887, "somePath")
+	err := suite.chargePoint.Start(wsUrl)
+	require.Nil(t, err)
+	resultChannel := make(chan bool, 1)
+	err = suite.centralSystem.SendDataTransfer(wsId, func(confirmation *core.DataTransferConfirmation, result interface{}, err error) {
+		require.Nil(t, err)
+		require.NotNil(t, confirmation)
+		assert.Equal(t, status, confirmation.Status)
+		require.IsType(t, &CustomResponseData{}, result)
+		assert.Equal(t, responseData, *result.(*CustomResponseData))
+		resultChannel <- true
+	}, customDataMessage, data)
+	require.Nil(t, err)
+	result := <-resultChannel
+	assert.True(t, result)
+}
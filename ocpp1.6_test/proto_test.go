@@ -1,17 +1,43 @@
 package ocpp16_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/gorilla/websocket"
 	"github.com/lorenzodonini/ocpp-go/ocpp"
+	ocpp16 "github.com/lorenzodonini/ocpp-go/ocpp1.6"
 	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
 	"github.com/lorenzodonini/ocpp-go/ocppj"
+	"github.com/lorenzodonini/ocpp-go/ws"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
+func (suite *OcppV16TestSuite) TestChargePointDisconnectedHandler() {
+	t := suite.T()
+	disconnectErr := &websocket.CloseError{Code: websocket.CloseGoingAway, Text: "restarting"}
+	expectedReason := &ws.DisconnectReason{Code: websocket.CloseGoingAway, Text: "restarting", Initiator: ws.DisconnectInitiatorRemote}
+	suite.mockWsClient.On("Start", mock.AnythingOfType("string")).Return(nil)
+	suite.mockWsClient.On("ConnectionState").Return(ws.ConnectionStateEvent{State: ws.ConnectionStateDisconnected, LastError: disconnectErr, DisconnectReason: expectedReason})
+	reasonC := make(chan *ws.DisconnectReason, 1)
+	suite.chargePoint.SetDisconnectedHandler(func(reason *ws.DisconnectReason) {
+		reasonC <- reason
+	})
+	err := suite.chargePoint.Start("someUrl")
+	require.NoError(t, err)
+	suite.mockWsClient.DisconnectedHandler(disconnectErr)
+	select {
+	case reason := <-reasonC:
+		assert.Equal(t, expectedReason, reason)
+	default:
+		assert.Fail(t, "disconnected handler wasn't invoked")
+	}
+}
+
 func (suite *OcppV16TestSuite) TestChargePointSendResponseError() {
 	t := suite.T()
 	wsId := "test_id"
@@ -156,7 +182,132 @@ func (suite *OcppV16TestSuite) TestCentralSystemSendResponseError() {
 	assert.Equal(t, fmt.Sprintf("empty confirmation to %s for request 1234", wsId), ocppErr.Description)
 }
 
+func (suite *OcppV16TestSuite) TestCentralSystemShutdown() {
+	t := suite.T()
+	wsId := "test_id"
+	channel := NewMockWebSocket(wsId)
+	suite.mockWsServer.On("Start", mock.AnythingOfType("int"), mock.AnythingOfType("string")).Return(nil)
+	// Requests are written, but the charge point never responds
+	suite.mockWsServer.On("Write", mock.AnythingOfType("string"), mock.Anything).Return(nil)
+	suite.mockWsServer.On("Shutdown", mock.Anything).Return(nil)
+	// Run Test
+	suite.centralSystem.Start(8887, "somePath")
+	suite.mockWsServer.NewClientHandler(channel)
+	callbackC := make(chan error, 2)
+	callback := func(confirmation *core.ClearCacheConfirmation, err error) {
+		assert.Nil(t, confirmation)
+		callbackC <- err
+	}
+	// First request is pending, second one is queued
+	err := suite.centralSystem.ClearCache(wsId, callback)
+	require.NoError(t, err)
+	err = suite.centralSystem.ClearCache(wsId, callback)
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	err = suite.centralSystem.Shutdown(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	// All outstanding callbacks were invoked with a shutdown error
+	for i := 0; i < 2; i++ {
+		select {
+		case err = <-callbackC:
+			require.Error(t, err)
+			require.IsType(t, &ocpp.Error{}, err)
+			ocppErr := err.(*ocpp.Error)
+			assert.Equal(t, ocppj.GenericError, ocppErr.Code)
+			assert.Equal(t, "central system shutting down, no response received from client", ocppErr.Description)
+		default:
+			assert.Fail(t, "callback wasn't invoked")
+		}
+	}
+	// New requests are rejected
+	err = suite.centralSystem.ClearCache(wsId, callback)
+	assert.Error(t, err)
+}
+
+func (suite *OcppV16TestSuite) TestCentralSystemChargePointReplaced() {
+	t := suite.T()
+	wsId := "test_id"
+	oldChannel := NewMockWebSocket(wsId)
+	newChannel := NewMockWebSocket(wsId)
+	suite.mockWsServer.On("Start", mock.AnythingOfType("int"), mock.AnythingOfType("string")).Return(nil)
+	// Requests are written, but the charge point never responds
+	suite.mockWsServer.On("Write", mock.AnythingOfType("string"), mock.Anything).Return(nil)
+	var events []string
+	suite.centralSystem.SetNewChargePointHandler(func(chargePoint ocpp16.ChargePointConnection) {
+		events = append(events, "connected")
+	})
+	suite.centralSystem.SetChargePointDisconnectedHandler(func(chargePoint ocpp16.ChargePointConnection) {
+		events = append(events, "disconnected")
+	})
+	// Run Test
+	suite.centralSystem.Start(8887, "somePath")
+	suite.mockWsServer.NewClientHandler(oldChannel)
+	callbackC := make(chan error, 2)
+	callback := func(confirmation *core.ClearCacheConfirmation, err error) {
+		assert.Nil(t, confirmation)
+		callbackC <- err
+	}
+	// First request is pending, second one is queued
+	err := suite.centralSystem.ClearCache(wsId, callback)
+	require.NoError(t, err)
+	err = suite.centralSystem.ClearCache(wsId, callback)
+	require.NoError(t, err)
+	// Simulate connection takeover
+	suite.mockWsServer.ReplacedClientHandler(oldChannel)
+	suite.mockWsServer.DisconnectedClientHandler(oldChannel)
+	suite.mockWsServer.NewClientHandler(newChannel)
+	// All outstanding callbacks were invoked with a replaced connection error
+	for i := 0; i < 2; i++ {
+		select {
+		case err = <-callbackC:
+			require.Error(t, err)
+			require.IsType(t, &ocpp.Error{}, err)
+			ocppErr := err.(*ocpp.Error)
+			assert.Equal(t, ocppj.GenericError, ocppErr.Code)
+			assert.Equal(t, "connection replaced by a new connection, no response received from client", ocppErr.Description)
+		default:
+			assert.Fail(t, "callback wasn't invoked")
+		}
+	}
+	assert.Equal(t, []string{"connected", "disconnected", "connected"}, events)
+	// New requests are sent on the new connection
+	err = suite.centralSystem.ClearCache(wsId, callback)
+	assert.NoError(t, err)
+}
+
 func (suite *OcppV16TestSuite) TestErrorCodes() {
 	suite.Equal(ocppj.FormatViolationV16, ocppj.FormatErrorType(suite.ocppjCentralSystem))
 	suite.Equal(ocppj.OccurrenceConstraintViolationV16, ocppj.OccurrenceConstraintErrorType(suite.ocppjCentralSystem))
 }
+
+func (suite *OcppV16TestSuite) TestCentralSystemChargePointConnections() {
+	t := suite.T()
+	channel1 := NewMockWebSocket("cs1")
+	channel2 := NewMockWebSocket("cs2")
+	suite.mockWsServer.On("Channels").Return([]ws.Channel{channel1, channel2})
+	suite.mockWsServer.On("ChannelCount").Return(2)
+	suite.mockWsServer.On("GetChannel", "cs1").Return(channel1, true)
+	suite.mockWsServer.On("GetChannel", "unknown").Return(nil, false)
+	closeError := websocket.CloseError{Code: websocket.ClosePolicyViolation, Text: "banned"}
+	suite.mockWsServer.On("StopConnection", "cs1", closeError).Return(nil)
+	suite.mockWsServer.On("StopConnection", "unknown", mock.Anything).Return(fmt.Errorf("no connection with id unknown is open"))
+	// List and look up connections
+	connections := suite.centralSystem.ChargePointConnections()
+	require.Len(t, connections, 2)
+	assert.Equal(t, "cs1", connections[0].ID())
+	assert.Equal(t, "cs2", connections[1].ID())
+	assert.Equal(t, 2, suite.centralSystem.ChargePointConnectionCount())
+	connection, ok := suite.centralSystem.GetChargePointConnection("cs1")
+	require.True(t, ok)
+	assert.Equal(t, "cs1", connection.ID())
+	connection, ok = suite.centralSystem.GetChargePointConnection("unknown")
+	assert.False(t, ok)
+	assert.Nil(t, connection)
+	// Disconnect with a custom close code and reason
+	err := suite.centralSystem.DisconnectChargePoint("cs1", websocket.ClosePolicyViolation, "banned")
+	assert.NoError(t, err)
+	err = suite.centralSystem.DisconnectChargePoint("unknown", websocket.CloseNormalClosure, "")
+	assert.Error(t, err)
+	suite.mockWsServer.AssertCalled(t, "StopConnection", "cs1", closeError)
+}
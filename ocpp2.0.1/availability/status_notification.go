@@ -33,15 +33,17 @@ func isValidConnectorStatus(fl validator.FieldLevel) bool {
 
 // The field definition of the StatusNotification request payload sent by the Charging Station to the CSMS.
 type StatusNotificationRequest struct {
-	Timestamp       *types.DateTime `json:"timestamp" validate:"required"`
-	ConnectorStatus ConnectorStatus `json:"connectorStatus" validate:"required,connectorStatus"`
-	EvseID          int             `json:"evseId" validate:"gte=0"`
-	ConnectorID     int             `json:"connectorId" validate:"gte=0"`
+	Timestamp       *types.DateTime   `json:"timestamp" validate:"required"`
+	ConnectorStatus ConnectorStatus   `json:"connectorStatus" validate:"required,connectorStatus"`
+	EvseID          int               `json:"evseId" validate:"gte=0"`
+	ConnectorID     int               `json:"connectorId" validate:"gte=0"`
+	CustomData      *types.CustomData `json:"customData,omitempty" validate:"omitempty"`
 }
 
 // This field definition of the StatusNotification response payload, sent by the CSMS to the Charging Station in response to a StatusNotificationRequest.
 // In case the request was invalid, or couldn't be processed, an error will be sent instead.
 type StatusNotificationResponse struct {
+	CustomData *types.CustomData `json:"customData,omitempty" validate:"omitempty"`
 }
 
 // The Charging Station notifies the CSMS about a connector status change.
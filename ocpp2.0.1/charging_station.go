@@ -3,7 +3,11 @@ package ocpp2
 import (
 	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/lorenzodonini/ocpp-go/datatransfer"
 	"github.com/lorenzodonini/ocpp-go/internal/callbackqueue"
 	"github.com/lorenzodonini/ocpp-go/ocpp"
 	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/authorization"
@@ -24,6 +28,7 @@ import (
 	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/transactions"
 	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/types"
 	"github.com/lorenzodonini/ocpp-go/ocppj"
+	"github.com/lorenzodonini/ocpp-go/ws"
 )
 
 type chargingStation struct {
@@ -44,13 +49,22 @@ type chargingStation struct {
 	diagnosticsHandler   diagnostics.ChargingStationHandler
 	displayHandler       display.ChargingStationHandler
 	dataHandler          data.ChargingStationHandler
+	dataTransferRegistry *datatransfer.Registry
+	customHandlers       map[string]CustomChargingStationHandler
 	responseHandler      chan ocpp.Response
 	errorHandler         chan error
 	callbacks            callbackqueue.CallbackQueue
 	stopC                chan struct{}
 	errC                 chan error // external error channel
+	pingIntervalSync     bool
 }
 
+// Standard component and variable names of the websocket ping interval, in seconds.
+const (
+	OCPPCommCtrlrComponent        = "OCPPCommCtrlr"
+	WebSocketPingIntervalVariable = "WebSocketPingInterval"
+)
+
 func (cs *chargingStation) error(err error) {
 	if cs.errC != nil {
 		cs.errC <- err
@@ -123,6 +137,24 @@ func (cs *chargingStation) DataTransfer(vendorId string, props ...func(request *
 	}
 }
 
+func (cs *chargingStation) SendDataTransfer(message datatransfer.Message, requestData interface{}) (*data.DataTransferResponse, interface{}, error) {
+	response, err := cs.DataTransfer(message.VendorID, func(request *data.DataTransferRequest) {
+		request.MessageID = message.MessageID
+		request.Data = requestData
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if response.Status != data.DataTransferStatusAccepted {
+		return response, nil, nil
+	}
+	responseData, err := datatransfer.Decode(response.Data, message.ResponseType)
+	if err != nil {
+		return response, nil, err
+	}
+	return response, responseData, nil
+}
+
 func (cs *chargingStation) FirmwareStatusNotification(status firmware.FirmwareStatus, props ...func(request *firmware.FirmwareStatusNotificationRequest)) (*firmware.FirmwareStatusNotificationResponse, error) {
 	request := firmware.NewFirmwareStatusNotificationRequest(status)
 	for _, fn := range props {
@@ -460,6 +492,27 @@ func (cs *chargingStation) SetDataHandler(handler data.ChargingStationHandler) {
 	cs.dataHandler = handler
 }
 
+func (cs *chargingStation) AddCustomFeature(feature ocpp.Feature, handler CustomChargingStationHandler) error {
+	featureName := feature.GetFeatureName()
+	if _, found := cs.client.GetProfileForFeature(featureName); found {
+		return fmt.Errorf("feature %v is already supported on charging station", featureName)
+	}
+	profile, found := cs.client.GetProfile(CustomProfileName)
+	if !found {
+		profile = ocpp.NewProfile(CustomProfileName)
+		cs.client.AddProfile(profile)
+	}
+	profile.AddFeature(feature)
+	if handler != nil {
+		cs.customHandlers[featureName] = handler
+	}
+	return nil
+}
+
+func (cs *chargingStation) SetDataTransferRegistry(registry *datatransfer.Registry) {
+	cs.dataTransferRegistry = registry
+}
+
 func (cs *chargingStation) SendRequest(request ocpp.Request) (ocpp.Response, error) {
 	featureName := request.GetFeatureName()
 	if _, found := cs.client.GetProfileForFeature(featureName); !found {
@@ -491,7 +544,8 @@ func (cs *chargingStation) SendRequest(request ocpp.Request) (ocpp.Response, err
 
 func (cs *chargingStation) SendRequestAsync(request ocpp.Request, callback func(response ocpp.Response, err error)) error {
 	featureName := request.GetFeatureName()
-	if _, found := cs.client.GetProfileForFeature(featureName); !found {
+	profile, found := cs.client.GetProfileForFeature(featureName)
+	if !found {
 		return fmt.Errorf("feature %v is unsupported on charging station (missing profile), cannot send request", featureName)
 	}
 	switch featureName {
@@ -522,7 +576,9 @@ func (cs *chargingStation) SendRequestAsync(request ocpp.Request, callback func(
 		transactions.TransactionEventFeatureName:
 		break
 	default:
-		return fmt.Errorf("unsupported action %v on charging station, cannot send request", featureName)
+		if profile.Name != CustomProfileName {
+			return fmt.Errorf("unsupported action %v on charging station, cannot send request", featureName)
+		}
 	}
 	// Response will be retrieved asynchronously via asyncHandler
 	send := func() error {
@@ -592,6 +648,10 @@ func (cs *chargingStation) sendResponse(response ocpp.Response, err error, reque
 	}
 }
 
+func (cs *chargingStation) SetSecurityProfile(profile ws.SecurityProfile) {
+	cs.client.SetSecurityProfile(profile)
+}
+
 func (cs *chargingStation) Start(csmsUrl string) error {
 	// Start client
 	cs.stopC = make(chan struct{}, 1)
@@ -611,6 +671,79 @@ func (cs *chargingStation) StartWithRetries(csmsUrl string) {
 	go cs.asyncCallbackHandler()
 }
 
+func (cs *chargingStation) StartWithFailover(config ws.FailoverConfig) error {
+	// Start client
+	cs.stopC = make(chan struct{}, 1)
+	err := cs.client.StartWithFailover(config)
+	// Async response handler receives incoming responses/errors and triggers callbacks
+	if err == nil {
+		go cs.asyncCallbackHandler()
+	}
+	return err
+}
+
+func (cs *chargingStation) ActiveEndpoint() (ws.ServerEndpoint, int, bool) {
+	return cs.client.ActiveEndpoint()
+}
+
+func (cs *chargingStation) Reconnect() error {
+	return cs.client.Reconnect()
+}
+
+func (cs *chargingStation) SetReconnectStrategy(strategy ws.ReconnectStrategy) {
+	cs.client.SetReconnectStrategy(strategy)
+}
+
+func (cs *chargingStation) SetConnectionStateHandler(handler func(event ws.ConnectionStateEvent)) {
+	cs.client.SetConnectionStateHandler(handler)
+}
+
+func (cs *chargingStation) ConnectionState() ws.ConnectionStateEvent {
+	return cs.client.ConnectionState()
+}
+
+func (cs *chargingStation) SetDisconnectedHandler(handler func(reason *ws.DisconnectReason)) {
+	cs.client.SetOnDisconnectedReasonHandler(handler)
+}
+
+func (cs *chargingStation) SetWebSocketPingInterval(interval time.Duration) {
+	cs.client.SetTimeoutConfig(cs.client.TimeoutConfig().WithPingPeriod(interval))
+}
+
+func (cs *chargingStation) SetWebSocketPingIntervalSync(enabled bool) {
+	cs.pingIntervalSync = enabled
+}
+
+// syncPingInterval applies an accepted change of the OCPPCommCtrlr.WebSocketPingInterval variable to the connection.
+func (cs *chargingStation) syncPingInterval(request *provisioning.SetVariablesRequest, response ocpp.Response) {
+	res, ok := response.(*provisioning.SetVariablesResponse)
+	if !ok || res == nil {
+		return
+	}
+	isPingInterval := func(component types.Component, variable types.Variable, attribute types.Attribute) bool {
+		return strings.EqualFold(component.Name, OCPPCommCtrlrComponent) &&
+			strings.EqualFold(variable.Name, WebSocketPingIntervalVariable) &&
+			(attribute == "" || attribute == types.AttributeActual)
+	}
+	for _, result := range res.SetVariableResult {
+		if result.AttributeStatus != provisioning.SetVariableStatusAccepted || !isPingInterval(result.Component, result.Variable, result.AttributeType) {
+			continue
+		}
+		for _, data := range request.SetVariableData {
+			if !isPingInterval(data.Component, data.Variable, data.AttributeType) {
+				continue
+			}
+			seconds, err := strconv.Atoi(data.AttributeValue)
+			if err != nil || seconds < 0 {
+				cs.error(fmt.Errorf("invalid value %v for %v.%v", data.AttributeValue, OCPPCommCtrlrComponent, WebSocketPingIntervalVariable))
+				return
+			}
+			cs.SetWebSocketPingInterval(time.Duration(seconds) * time.Second)
+			return
+		}
+	}
+}
+
 func (cs *chargingStation) Stop() {
 	cs.client.Stop()
 }
@@ -651,7 +784,7 @@ func (cs *chargingStation) handleIncomingRequest(request ocpp.Request, requestId
 				supported = false
 			}
 		case data.ProfileName:
-			if cs.dataHandler == nil {
+			if cs.dataHandler == nil && cs.dataTransferRegistry == nil {
 				supported = false
 			}
 		case diagnostics.ProfileName:
@@ -706,6 +839,10 @@ func (cs *chargingStation) handleIncomingRequest(request ocpp.Request, requestId
 			if cs.transactionsHandler == nil {
 				supported = false
 			}
+		case CustomProfileName:
+			if _, ok := cs.customHandlers[action]; !ok {
+				supported = false
+			}
 		}
 		if !supported {
 			cs.notSupportedError(requestId, action)
@@ -735,7 +872,11 @@ func (cs *chargingStation) handleIncomingRequest(request ocpp.Request, requestId
 	case diagnostics.CustomerInformationFeatureName:
 		response, err = cs.diagnosticsHandler.OnCustomerInformation(request.(*diagnostics.CustomerInformationRequest))
 	case data.DataTransferFeatureName:
-		response, err = cs.dataHandler.OnDataTransfer(request.(*data.DataTransferRequest))
+		if cs.dataTransferRegistry != nil {
+			response, err = cs.handleRegisteredDataTransfer(request.(*data.DataTransferRequest))
+		} else {
+			response, err = cs.dataHandler.OnDataTransfer(request.(*data.DataTransferRequest))
+		}
 	case iso15118.DeleteCertificateFeatureName:
 		response, err = cs.iso15118Handler.OnDeleteCertificate(request.(*iso15118.DeleteCertificateRequest))
 	case provisioning.GetBaseReportFeatureName:
@@ -788,6 +929,9 @@ func (cs *chargingStation) handleIncomingRequest(request ocpp.Request, requestId
 		response, err = cs.diagnosticsHandler.OnSetVariableMonitoring(request.(*diagnostics.SetVariableMonitoringRequest))
 	case provisioning.SetVariablesFeatureName:
 		response, err = cs.provisioningHandler.OnSetVariables(request.(*provisioning.SetVariablesRequest))
+		if err == nil && cs.pingIntervalSync {
+			cs.syncPingInterval(request.(*provisioning.SetVariablesRequest), response)
+		}
 	case remotecontrol.TriggerMessageFeatureName:
 		response, err = cs.remoteControlHandler.OnTriggerMessage(request.(*remotecontrol.TriggerMessageRequest))
 	case remotecontrol.UnlockConnectorFeatureName:
@@ -797,8 +941,22 @@ func (cs *chargingStation) handleIncomingRequest(request ocpp.Request, requestId
 	case firmware.UpdateFirmwareFeatureName:
 		response, err = cs.firmwareHandler.OnUpdateFirmware(request.(*firmware.UpdateFirmwareRequest))
 	default:
-		cs.notSupportedError(requestId, action)
-		return
+		handler, ok := cs.customHandlers[action]
+		if !ok {
+			cs.notSupportedError(requestId, action)
+			return
+		}
+		response, err = handler(request)
 	}
 	cs.sendResponse(response, err, requestId)
 }
+
+func (cs *chargingStation) handleRegisteredDataTransfer(request *data.DataTransferRequest) (ocpp.Response, error) {
+	status, responseData, err := cs.dataTransferRegistry.Handle("", request.VendorID, request.MessageID, request.Data)
+	if err != nil {
+		return nil, err
+	}
+	response := data.NewDataTransferResponse(data.DataTransferStatus(status))
+	response.Data = responseData
+	return response, nil
+}
@@ -1,9 +1,13 @@
 package ocpp2
 
 import (
+	"context"
 	"fmt"
+	"net"
+	"net/http"
 	"reflect"
 
+	"github.com/lorenzodonini/ocpp-go/datatransfer"
 	"github.com/lorenzodonini/ocpp-go/internal/callbackqueue"
 	"github.com/lorenzodonini/ocpp-go/ocpp"
 	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/authorization"
@@ -45,6 +49,8 @@ type csms struct {
 	diagnosticsHandler   diagnostics.CSMSHandler
 	displayHandler       display.CSMSHandler
 	dataHandler          data.CSMSHandler
+	dataTransferRegistry *datatransfer.Registry
+	customHandlers       map[string]CustomCSMSHandler
 	callbackQueue        callbackqueue.CallbackQueue
 	errC                 chan error
 }
@@ -55,8 +61,9 @@ func newCSMS(server *ocppj.Server) csms {
 	}
 	server.SetDialect(ocpp.V2)
 	return csms{
-		server:        server,
-		callbackQueue: callbackqueue.New(),
+		server:         server,
+		callbackQueue:  callbackqueue.New(),
+		customHandlers: map[string]CustomCSMSHandler{},
 	}
 }
 
@@ -223,6 +230,21 @@ func (cs *csms) DataTransfer(clientId string, callback func(*data.DataTransferRe
 	return cs.SendRequestAsync(clientId, request, genericCallback)
 }
 
+func (cs *csms) SendDataTransfer(clientId string, callback func(response *data.DataTransferResponse, responseData interface{}, err error), message datatransfer.Message, requestData interface{}) error {
+	decodingCallback := func(response *data.DataTransferResponse, err error) {
+		if err != nil || response.Status != data.DataTransferStatusAccepted {
+			callback(response, nil, err)
+			return
+		}
+		responseData, err := datatransfer.Decode(response.Data, message.ResponseType)
+		callback(response, responseData, err)
+	}
+	return cs.DataTransfer(clientId, decodingCallback, message.VendorID, func(request *data.DataTransferRequest) {
+		request.MessageID = message.MessageID
+		request.Data = requestData
+	})
+}
+
 func (cs *csms) DeleteCertificate(clientId string, callback func(*iso15118.DeleteCertificateResponse, error), data types.CertificateHashData, props ...func(*iso15118.DeleteCertificateRequest)) error {
 	request := iso15118.NewDeleteCertificateRequest(data)
 	for _, fn := range props {
@@ -733,6 +755,27 @@ func (cs *csms) SetDisplayHandler(handler display.CSMSHandler) {
 	cs.displayHandler = handler
 }
 
+func (cs *csms) AddCustomFeature(feature ocpp.Feature, handler CustomCSMSHandler) error {
+	featureName := feature.GetFeatureName()
+	if _, found := cs.server.GetProfileForFeature(featureName); found {
+		return fmt.Errorf("feature %v is already supported on CSMS", featureName)
+	}
+	profile, found := cs.server.GetProfile(CustomProfileName)
+	if !found {
+		profile = ocpp.NewProfile(CustomProfileName)
+		cs.server.AddProfile(profile)
+	}
+	profile.AddFeature(feature)
+	if handler != nil {
+		cs.customHandlers[featureName] = handler
+	}
+	return nil
+}
+
+func (cs *csms) SetDataTransferRegistry(registry *datatransfer.Registry) {
+	cs.dataTransferRegistry = registry
+}
+
 func (cs *csms) SetDataHandler(handler data.CSMSHandler) {
 	cs.dataHandler = handler
 }
@@ -751,6 +794,9 @@ func (cs *csms) SetChargingStationDisconnectedHandler(handler ChargingStationCon
 	cs.server.SetDisconnectedClientHandler(func(chargingStation ws.Channel) {
 		for cb, ok := cs.callbackQueue.Dequeue(chargingStation.ID()); ok; cb, ok = cs.callbackQueue.Dequeue(chargingStation.ID()) {
 			err := ocpp.NewError(ocppj.GenericError, "client disconnected, no response received from client", "")
+			if cs.server.IsShuttingDown() {
+				err = newShutdownError()
+			}
 			cb(nil, err)
 		}
 		handler(chargingStation)
@@ -759,7 +805,8 @@ func (cs *csms) SetChargingStationDisconnectedHandler(handler ChargingStationCon
 
 func (cs *csms) SendRequestAsync(clientId string, request ocpp.Request, callback func(response ocpp.Response, err error)) error {
 	featureName := request.GetFeatureName()
-	if _, found := cs.server.GetProfileForFeature(featureName); !found {
+	profile, found := cs.server.GetProfileForFeature(featureName)
+	if !found {
 		return fmt.Errorf("feature %v is unsupported on CSMS (missing profile), cannot send request", featureName)
 	}
 	switch featureName {
@@ -805,7 +852,9 @@ func (cs *csms) SendRequestAsync(clientId string, request ocpp.Request, callback
 		firmware.UpdateFirmwareFeatureName:
 		break
 	default:
-		return fmt.Errorf("unsupported action %v on CSMS, cannot send request", featureName)
+		if profile.Name != CustomProfileName {
+			return fmt.Errorf("unsupported action %v on CSMS, cannot send request", featureName)
+		}
 	}
 
 	send := func() error {
@@ -814,15 +863,80 @@ func (cs *csms) SendRequestAsync(clientId string, request ocpp.Request, callback
 	return cs.callbackQueue.TryQueue(clientId, send, callback)
 }
 
+func (cs *csms) SetSecurityProfile(profile ws.SecurityProfile) {
+	cs.server.SetSecurityProfile(profile)
+}
+
+func (cs *csms) ChargingStationConnections() []ChargingStationConnection {
+	channels := cs.server.Clients()
+	connections := make([]ChargingStationConnection, len(channels))
+	for i, channel := range channels {
+		connections[i] = channel
+	}
+	return connections
+}
+
+func (cs *csms) ChargingStationConnectionCount() int {
+	return cs.server.ClientCount()
+}
+
+func (cs *csms) GetChargingStationConnection(chargingStationId string) (ChargingStationConnection, bool) {
+	channel, ok := cs.server.GetClient(chargingStationId)
+	if !ok {
+		return nil, false
+	}
+	return channel, true
+}
+
+func (cs *csms) DisconnectChargingStation(chargingStationId string, code int, reason string) error {
+	return cs.server.DisconnectClient(chargingStationId, code, reason)
+}
+
 func (cs *csms) Start(listenPort int, listenPath string) {
 	// Start server
 	cs.server.Start(listenPort, listenPath)
 }
 
+func (cs *csms) Serve(listener net.Listener, listenPath string) {
+	// Serve on listener
+	cs.server.Serve(listener, listenPath)
+}
+
+func (cs *csms) Handler() http.Handler {
+	return cs.server.Handler()
+}
+
 func (cs *csms) Stop() {
 	cs.server.Stop()
 }
 
+func (cs *csms) Shutdown(ctx context.Context) error {
+	err := cs.server.Shutdown(ctx)
+	// Notify callbacks of requests, which will never receive a response
+	for _, callbacks := range cs.callbackQueue.DequeueAll() {
+		for _, cb := range callbacks {
+			cb(nil, newShutdownError())
+		}
+	}
+	return err
+}
+
+// handleClientReplaced cancels all pending requests for a charging station, whose connection was replaced by a new connection.
+// The disconnected handler for the old connection is invoked afterwards.
+func (cs *csms) handleClientReplaced(client ws.Channel) {
+	for cb, ok := cs.callbackQueue.Dequeue(client.ID()); ok; cb, ok = cs.callbackQueue.Dequeue(client.ID()) {
+		cb(nil, newConnectionReplacedError())
+	}
+}
+
+func newConnectionReplacedError() *ocpp.Error {
+	return ocpp.NewError(ocppj.GenericError, "connection replaced by a new connection, no response received from client", "")
+}
+
+func newShutdownError() *ocpp.Error {
+	return ocpp.NewError(ocppj.GenericError, "CSMS shutting down, no response received from client", "")
+}
+
 func (cs *csms) sendResponse(chargingStationID string, response ocpp.Response, err error, requestId string) {
 	if err != nil {
 		// Send error response
@@ -894,7 +1008,7 @@ func (cs *csms) handleIncomingRequest(chargingStation ChargingStationConnection,
 				supported = false
 			}
 		case data.ProfileName:
-			if cs.dataHandler == nil {
+			if cs.dataHandler == nil && cs.dataTransferRegistry == nil {
 				supported = false
 			}
 		case diagnostics.ProfileName:
@@ -949,6 +1063,10 @@ func (cs *csms) handleIncomingRequest(chargingStation ChargingStationConnection,
 			if cs.transactionsHandler == nil {
 				supported = false
 			}
+		case CustomProfileName:
+			if _, ok := cs.customHandlers[action]; !ok {
+				supported = false
+			}
 		}
 		if !supported {
 			cs.notSupportedError(chargingStation.ID(), requestId, action)
@@ -967,7 +1085,11 @@ func (cs *csms) handleIncomingRequest(chargingStation ChargingStationConnection,
 		case smartcharging.ClearedChargingLimitFeatureName:
 			response, err = cs.smartChargingHandler.OnClearedChargingLimit(chargingStation.ID(), request.(*smartcharging.ClearedChargingLimitRequest))
 		case data.DataTransferFeatureName:
-			response, err = cs.dataHandler.OnDataTransfer(chargingStation.ID(), request.(*data.DataTransferRequest))
+			if cs.dataTransferRegistry != nil {
+				response, err = cs.handleRegisteredDataTransfer(chargingStation.ID(), request.(*data.DataTransferRequest))
+			} else {
+				response, err = cs.dataHandler.OnDataTransfer(chargingStation.ID(), request.(*data.DataTransferRequest))
+			}
 		case firmware.FirmwareStatusNotificationFeatureName:
 			response, err = cs.firmwareHandler.OnFirmwareStatusNotification(chargingStation.ID(), request.(*firmware.FirmwareStatusNotificationRequest))
 		case iso15118.Get15118EVCertificateFeatureName:
@@ -1011,8 +1133,12 @@ func (cs *csms) handleIncomingRequest(chargingStation ChargingStationConnection,
 		case transactions.TransactionEventFeatureName:
 			response, err = cs.transactionsHandler.OnTransactionEvent(chargingStation.ID(), request.(*transactions.TransactionEventRequest))
 		default:
-			cs.notSupportedError(chargingStation.ID(), requestId, action)
-			return
+			handler, ok := cs.customHandlers[action]
+			if !ok {
+				cs.notSupportedError(chargingStation.ID(), requestId, action)
+				return
+			}
+			response, err = handler(chargingStation.ID(), request)
 		}
 		cs.sendResponse(chargingStation.ID(), response, err, requestId)
 	}()
@@ -1047,3 +1173,13 @@ func (cs *csms) handleCanceledRequest(chargePointID string, request ocpp.Request
 		cs.error(err)
 	}
 }
+
+func (cs *csms) handleRegisteredDataTransfer(chargingStationID string, request *data.DataTransferRequest) (ocpp.Response, error) {
+	status, responseData, err := cs.dataTransferRegistry.Handle(chargingStationID, request.VendorID, request.MessageID, request.Data)
+	if err != nil {
+		return nil, err
+	}
+	response := data.NewDataTransferResponse(data.DataTransferStatus(status))
+	response.Data = responseData
+	return response, nil
+}
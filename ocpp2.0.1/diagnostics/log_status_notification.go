@@ -36,13 +36,15 @@ func isValidUploadLogStatus(fl validator.FieldLevel) bool {
 
 // The field definition of the LogStatusNotification request payload sent by a Charging Station to the CSMS.
 type LogStatusNotificationRequest struct {
-	Status    UploadLogStatus `json:"status" validate:"required,uploadLogStatus"`
-	RequestID int             `json:"requestId" validate:"gte=0"`
+	Status     UploadLogStatus   `json:"status" validate:"required,uploadLogStatus"`
+	RequestID  int               `json:"requestId" validate:"gte=0"`
+	CustomData *types.CustomData `json:"customData,omitempty" validate:"omitempty"`
 }
 
 // This field definition of the LogStatusNotification response payload, sent by the CSMS to the Charging Station in response to a LogStatusNotificationRequest.
 // In case the request was invalid, or couldn't be processed, an error will be sent instead.
 type LogStatusNotificationResponse struct {
+	CustomData *types.CustomData `json:"customData,omitempty" validate:"omitempty"`
 }
 
 // A Charging Station shall send LogStatusNotification requests to update the CSMS with the current status of a log-upload procedure.
@@ -65,19 +65,22 @@ type EventData struct {
 	EventNotificationType EventNotification `json:"eventNotificationType" validate:"required,eventNotification"`
 	Component             types.Component   `json:"component" validate:"required"`
 	Variable              types.Variable    `json:"variable" validate:"required"`
+	CustomData            *types.CustomData `json:"customData,omitempty" validate:"omitempty"`
 }
 
 // The field definition of the NotifyEvent request payload sent by a Charging Station to the CSMS.
 type NotifyEventRequest struct {
-	GeneratedAt *types.DateTime `json:"generatedAt" validate:"required"`          // Timestamp of the moment this message was generated at the Charging Station.
-	SeqNo       int             `json:"seqNo" validate:"gte=0"`                   // Sequence number of this message. First message starts at 0.
-	Tbc         bool            `json:"tbc,omitempty" validate:"omitempty"`       // “to be continued” indicator. Indicates whether another part of the monitoringData follows in an upcoming notifyMonitoringReportRequest message. Default value when omitted is false.
-	EventData   []EventData     `json:"eventData" validate:"required,min=1,dive"` // The list of EventData will usually contain one eventData element, but the Charging Station may decide to group multiple events in one notification.
+	GeneratedAt *types.DateTime   `json:"generatedAt" validate:"required"`          // Timestamp of the moment this message was generated at the Charging Station.
+	SeqNo       int               `json:"seqNo" validate:"gte=0"`                   // Sequence number of this message. First message starts at 0.
+	Tbc         bool              `json:"tbc,omitempty" validate:"omitempty"`       // “to be continued” indicator. Indicates whether another part of the monitoringData follows in an upcoming notifyMonitoringReportRequest message. Default value when omitted is false.
+	EventData   []EventData       `json:"eventData" validate:"required,min=1,dive"` // The list of EventData will usually contain one eventData element, but the Charging Station may decide to group multiple events in one notification.
+	CustomData  *types.CustomData `json:"customData,omitempty" validate:"omitempty"`
 }
 
 // This field definition of the NotifyEvent response payload, sent by the CSMS to the Charging Station in response to a NotifyEventRequest.
 // In case the request was invalid, or couldn't be processed, an error will be sent instead.
 type NotifyEventResponse struct {
+	CustomData *types.CustomData `json:"customData,omitempty" validate:"omitempty"`
 }
 
 // The NotifyEvent feature gives Charging Stations the ability to notify the CSMS (periodically) about monitoring events.
@@ -12,11 +12,12 @@ const NotifyMonitoringReportFeatureName = "NotifyMonitoringReport"
 
 // VariableMonitoring describes a monitoring setting for a variable.
 type VariableMonitoring struct {
-	ID          int         `json:"id" validate:"gte=0"`                  // Identifies the monitor.
-	Transaction bool        `json:"transaction"`                          // Monitor only active when a transaction is ongoing on a component relevant to this transaction.
-	Value       float64     `json:"value"`                                // Value for threshold or delta monitoring. For Periodic or PeriodicClockAligned this is the interval in seconds.
-	Type        MonitorType `json:"type" validate:"required,monitorType"` // The type of this monitor, e.g. a threshold, delta or periodic monitor.
-	Severity    int         `json:"severity" validate:"min=0,max=9"`      // The severity that will be assigned to an event that is triggered by this monitor. The severity range is 0-9, with 0 as the highest and 9 as the lowest severity level.
+	ID          int               `json:"id" validate:"gte=0"`                  // Identifies the monitor.
+	Transaction bool              `json:"transaction"`                          // Monitor only active when a transaction is ongoing on a component relevant to this transaction.
+	Value       float64           `json:"value"`                                // Value for threshold or delta monitoring. For Periodic or PeriodicClockAligned this is the interval in seconds.
+	Type        MonitorType       `json:"type" validate:"required,monitorType"` // The type of this monitor, e.g. a threshold, delta or periodic monitor.
+	Severity    int               `json:"severity" validate:"min=0,max=9"`      // The severity that will be assigned to an event that is triggered by this monitor. The severity range is 0-9, with 0 as the highest and 9 as the lowest severity level.
+	CustomData  *types.CustomData `json:"customData,omitempty" validate:"omitempty"`
 }
 
 // NewVariableMonitoring is a utility function for creating a VariableMonitoring struct.
@@ -29,20 +30,23 @@ type MonitoringData struct {
 	Component          types.Component      `json:"component" validate:"required"`
 	Variable           types.Variable       `json:"variable" validate:"required"`
 	VariableMonitoring []VariableMonitoring `json:"variableMonitoring" validate:"required,min=1,dive"`
+	CustomData         *types.CustomData    `json:"customData,omitempty" validate:"omitempty"`
 }
 
 // The field definition of the NotifyMonitoringReport request payload sent by a Charging Station to the CSMS.
 type NotifyMonitoringReportRequest struct {
-	RequestID   int              `json:"requestId" validate:"gte=0"`                  // The id of the GetMonitoringRequest that requested this report.
-	Tbc         bool             `json:"tbc,omitempty" validate:"omitempty"`          // “to be continued” indicator. Indicates whether another part of the monitoringData follows in an upcoming notifyMonitoringReportRequest message. Default value when omitted is false.
-	SeqNo       int              `json:"seqNo" validate:"gte=0"`                      // Sequence number of this message. First message starts at 0.
-	GeneratedAt *types.DateTime  `json:"generatedAt" validate:"required"`             // Timestamp of the moment this message was generated at the Charging Station.
-	Monitor     []MonitoringData `json:"monitor,omitempty" validate:"omitempty,dive"` // List of MonitoringData containing monitoring settings.
+	RequestID   int               `json:"requestId" validate:"gte=0"`                  // The id of the GetMonitoringRequest that requested this report.
+	Tbc         bool              `json:"tbc,omitempty" validate:"omitempty"`          // “to be continued” indicator. Indicates whether another part of the monitoringData follows in an upcoming notifyMonitoringReportRequest message. Default value when omitted is false.
+	SeqNo       int               `json:"seqNo" validate:"gte=0"`                      // Sequence number of this message. First message starts at 0.
+	GeneratedAt *types.DateTime   `json:"generatedAt" validate:"required"`             // Timestamp of the moment this message was generated at the Charging Station.
+	Monitor     []MonitoringData  `json:"monitor,omitempty" validate:"omitempty,dive"` // List of MonitoringData containing monitoring settings.
+	CustomData  *types.CustomData `json:"customData,omitempty" validate:"omitempty"`
 }
 
 // This field definition of the NotifyMonitoringReport response payload, sent by the CSMS to the Charging Station in response to a NotifyMonitoringReportRequest.
 // In case the request was invalid, or couldn't be processed, an error will be sent instead.
 type NotifyMonitoringReportResponse struct {
+	CustomData *types.CustomData `json:"customData,omitempty" validate:"omitempty"`
 }
 
 // The NotifyMonitoringReport feature is used by a Charging Station to send a report to the CSMS about configured
@@ -44,13 +44,15 @@ func isValidFirmwareStatus(fl validator.FieldLevel) bool {
 
 // The field definition of the FirmwareStatusNotification request payload sent by the Charging Station to the CSMS.
 type FirmwareStatusNotificationRequest struct {
-	Status    FirmwareStatus `json:"status" validate:"required,firmwareStatus201"`
-	RequestID *int           `json:"requestId,omitempty" validate:"omitempty,gte=0"`
+	Status     FirmwareStatus    `json:"status" validate:"required,firmwareStatus201"`
+	RequestID  *int              `json:"requestId,omitempty" validate:"omitempty,gte=0"`
+	CustomData *types.CustomData `json:"customData,omitempty" validate:"omitempty"`
 }
 
 // This field definition of the FirmwareStatusNotification response payload, sent by the CSMS to the Charging Station in response to a FirmwareStatusNotificationRequest.
 // In case the request was invalid, or couldn't be processed, an error will be sent instead.
 type FirmwareStatusNotificationResponse struct {
+	CustomData *types.CustomData `json:"customData,omitempty" validate:"omitempty"`
 }
 
 // The Charging Station sends a notification to inform the CSMS about the progress of the downloading and installation of a firmware update.
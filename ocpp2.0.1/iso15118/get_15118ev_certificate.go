@@ -35,6 +35,7 @@ type Get15118EVCertificateRequest struct {
 	SchemaVersion string            `json:"iso15118SchemaVersion" validate:"required,max=50"`
 	Action        CertificateAction `json:"action" validate:"required,certificateAction"`
 	ExiRequest    string            `json:"exiRequest" validate:"required,max=5600"` // Raw CertificateInstallationReq request from EV, Base64 encoded.
+	CustomData    *types.CustomData `json:"customData,omitempty" validate:"omitempty"`
 }
 
 // This field definition of the Get15118EVCertificate response payload, sent by the CSMS to the Charging Station in response to a Get15118EVCertificateRequest.
@@ -43,6 +44,7 @@ type Get15118EVCertificateResponse struct {
 	Status      types.Certificate15118EVStatus `json:"status" validate:"required,15118EVCertificate"`
 	ExiResponse string                         `json:"exiResponse" validate:"required,max=5600"` // Raw CertificateInstallationRes response for the EV, Base64 encoded.
 	StatusInfo  *types.StatusInfo              `json:"statusInfo,omitempty" validate:"omitempty"`
+	CustomData  *types.CustomData              `json:"customData,omitempty" validate:"omitempty"`
 }
 
 // An EV connected to a Charging Station may request a new certificate.
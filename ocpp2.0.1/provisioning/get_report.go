@@ -36,12 +36,14 @@ type GetReportRequest struct {
 	RequestID         *int                      `json:"requestId,omitempty" validate:"omitempty,gte=0"`
 	ComponentCriteria []ComponentCriterion      `json:"componentCriteria,omitempty" validate:"omitempty,max=4,dive,componentCriterion"`
 	ComponentVariable []types.ComponentVariable `json:"componentVariable,omitempty" validate:"omitempty,dive"`
+	CustomData        *types.CustomData         `json:"customData,omitempty" validate:"omitempty"`
 }
 
 // This field definition of the GetReport response payload, sent by the Charging Station to the CSMS in response to a GetReportRequest.
 // In case the request was invalid, or couldn't be processed, an error will be sent instead.
 type GetReportResponse struct {
-	Status types.GenericDeviceModelStatus `json:"status" validate:"required,genericDeviceModelStatus"`
+	Status     types.GenericDeviceModelStatus `json:"status" validate:"required,genericDeviceModelStatus"`
+	CustomData *types.CustomData              `json:"customData,omitempty" validate:"omitempty"`
 }
 
 // The CSO may trigger the CSMS to request a report from a Charging Station.
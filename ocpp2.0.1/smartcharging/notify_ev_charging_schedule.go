@@ -15,6 +15,7 @@ type NotifyEVChargingScheduleRequest struct {
 	TimeBase         *types.DateTime        `json:"timeBase" validate:"required"`
 	EvseID           int                    `json:"evseId" validate:"gt=0"`
 	ChargingSchedule types.ChargingSchedule `json:"chargingSchedule" validate:"required,dive"`
+	CustomData       *types.CustomData      `json:"customData,omitempty" validate:"omitempty"`
 }
 
 // This field definition of the NotifyEVChargingSchedule response payload, sent by the CSMS to the Charging Station in response to a NotifyEVChargingScheduleRequest.
@@ -22,6 +23,7 @@ type NotifyEVChargingScheduleRequest struct {
 type NotifyEVChargingScheduleResponse struct {
 	Status     types.GenericStatus `json:"status" validate:"required,genericStatus"`
 	StatusInfo *types.StatusInfo   `json:"statusInfo,omitempty" validate:"omitempty,dive"` // Detailed status information.
+	CustomData *types.CustomData   `json:"customData,omitempty" validate:"omitempty"`
 }
 
 // A power renegotiation, either initiated by the EV or by the CSMS, may involve the EV providing a power profile.
@@ -128,11 +128,12 @@ func isValidReason(fl validator.FieldLevel) bool {
 
 // Contains transaction specific information.
 type Transaction struct {
-	TransactionID     string        `json:"transactionId" validate:"required,max=36"`
-	ChargingState     ChargingState `json:"chargingState,omitempty" validate:"omitempty,chargingState"`
-	TimeSpentCharging *int          `json:"timeSpentCharging,omitempty" validate:"omitempty"` // Contains the total time that energy flowed from EVSE to EV during the transaction (in seconds).
-	StoppedReason     Reason        `json:"stoppedReason,omitempty" validate:"omitempty,stoppedReason"`
-	RemoteStartID     *int          `json:"remoteStartId,omitempty" validate:"omitempty"`
+	TransactionID     string            `json:"transactionId" validate:"required,max=36"`
+	ChargingState     ChargingState     `json:"chargingState,omitempty" validate:"omitempty,chargingState"`
+	TimeSpentCharging *int              `json:"timeSpentCharging,omitempty" validate:"omitempty"` // Contains the total time that energy flowed from EVSE to EV during the transaction (in seconds).
+	StoppedReason     Reason            `json:"stoppedReason,omitempty" validate:"omitempty,stoppedReason"`
+	RemoteStartID     *int              `json:"remoteStartId,omitempty" validate:"omitempty"`
+	CustomData        *types.CustomData `json:"customData,omitempty" validate:"omitempty"`
 }
 
 // The field definition of the TransactionEvent request payload sent by the Charging Station to the CSMS.
@@ -149,6 +150,7 @@ type TransactionEventRequest struct {
 	IDToken            *types.IdToken     `json:"idToken,omitempty" validate:"omitempty,dive"`
 	Evse               *types.EVSE        `json:"evse,omitempty" validate:"omitempty"`            // Identifies which evse (and connector) of the Charging Station is used.
 	MeterValue         []types.MeterValue `json:"meterValue,omitempty" validate:"omitempty,dive"` // Contains the relevant meter values.
+	CustomData         *types.CustomData  `json:"customData,omitempty" validate:"omitempty"`
 }
 
 // This field definition of the TransactionEventResponse payload, sent by the CSMS to the Charging Station in response to a TransactionEventRequest.
@@ -158,6 +160,7 @@ type TransactionEventResponse struct {
 	ChargingPriority       *int                  `json:"chargingPriority,omitempty" validate:"omitempty,min=-9,max=9"` // Priority from a business point of view. Default priority is 0, The range is from -9 to 9.
 	IDTokenInfo            *types.IdTokenInfo    `json:"idTokenInfo,omitempty" validate:"omitempty"`                   // Is required when the transactionEventRequest contained an idToken.
 	UpdatedPersonalMessage *types.MessageContent `json:"updatedPersonalMessage,omitempty" validate:"omitempty"`        // This can contain updated personal message that can be shown to the EV Driver. This can be used to provide updated tariff information.
+	CustomData             *types.CustomData     `json:"customData,omitempty" validate:"omitempty"`
 }
 
 // Gives the CSMS information that will later be used to bill a transaction.
@@ -0,0 +1,141 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+const customDataVendorIDKey = "vendorId"
+
+// CustomData may be attached to any OCPP 2.0.1 message or nested type, allowing vendors to extend it with custom properties.
+//
+// The vendorId is the only defined property. All other properties are kept as raw JSON,
+// so that they are preserved when a message is parsed and serialized again.
+// Use Get/Set to access single properties, or Decode/Encode to map all properties to a vendor-specific struct.
+type CustomData struct {
+	VendorID   string                     `json:"vendorId" validate:"required,max=255"` // Identifies the vendor who defined the custom properties.
+	Properties map[string]json.RawMessage `json:"-"`                                    // Raw custom properties, indexed by name. Doesn't contain the vendorId.
+}
+
+// Creates a new CustomData struct without any properties.
+func NewCustomData(vendorID string) *CustomData {
+	return &CustomData{VendorID: vendorID}
+}
+
+// Has returns true if a custom property with the given key exists.
+func (c *CustomData) Has(key string) bool {
+	_, ok := c.Properties[key]
+	return ok
+}
+
+// Get decodes the custom property with the given key into value, which must be a pointer.
+// If the property doesn't exist, false is returned and value is left untouched.
+func (c *CustomData) Get(key string, value interface{}) (bool, error) {
+	raw, ok := c.Properties[key]
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal(raw, value); err != nil {
+		return true, fmt.Errorf("couldn't decode custom property %v: %w", key, err)
+	}
+	return true, nil
+}
+
+// Set encodes value and stores it as a custom property with the given key, replacing any previous value.
+// The vendorId cannot be set as a custom property.
+func (c *CustomData) Set(key string, value interface{}) error {
+	if key == customDataVendorIDKey {
+		return errors.New("vendorId is not a custom property, set VendorID instead")
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("couldn't encode custom property %v: %w", key, err)
+	}
+	if c.Properties == nil {
+		c.Properties = map[string]json.RawMessage{}
+	}
+	c.Properties[key] = raw
+	return nil
+}
+
+// Delete removes the custom property with the given key, if it exists.
+func (c *CustomData) Delete(key string) {
+	delete(c.Properties, key)
+}
+
+// Decode decodes the whole custom data object, including the vendorId, into value.
+// Typically value is a pointer to a vendor-specific struct.
+func (c *CustomData) Decode(value interface{}) error {
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, value)
+}
+
+// Encode stores all fields of value, which must serialize to a JSON object, as custom properties.
+// Existing properties with the same name are replaced. If value contains a vendorId, VendorID is overwritten as well.
+func (c *CustomData) Encode(value interface{}) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return c.merge(raw)
+}
+
+func (c *CustomData) merge(raw []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return err
+	}
+	for key, value := range fields {
+		if key == customDataVendorIDKey {
+			if err := json.Unmarshal(value, &c.VendorID); err != nil {
+				return fmt.Errorf("invalid vendorId: %w", err)
+			}
+			continue
+		}
+		if c.Properties == nil {
+			c.Properties = map[string]json.RawMessage{}
+		}
+		c.Properties[key] = value
+	}
+	return nil
+}
+
+func (c *CustomData) UnmarshalJSON(input []byte) error {
+	c.VendorID = ""
+	c.Properties = nil
+	return c.merge(input)
+}
+
+// MarshalJSON serializes the vendorId first, followed by all custom properties in alphabetical order.
+func (c *CustomData) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	vendorID, err := json.Marshal(c.VendorID)
+	if err != nil {
+		return nil, err
+	}
+	buf.WriteString(`{"vendorId":`)
+	buf.Write(vendorID)
+	keys := make([]string, 0, len(c.Properties))
+	for key := range c.Properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteByte(',')
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(c.Properties[key])
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
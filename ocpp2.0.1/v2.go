@@ -2,9 +2,13 @@
 package ocpp2
 
 import (
+	"context"
 	"crypto/tls"
 	"net"
+	"net/http"
+	"time"
 
+	"github.com/lorenzodonini/ocpp-go/datatransfer"
 	"github.com/lorenzodonini/ocpp-go/internal/callbackqueue"
 	"github.com/lorenzodonini/ocpp-go/ocpp"
 	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/authorization"
@@ -32,6 +36,21 @@ type ChargingStationConnection interface {
 	ID() string
 	RemoteAddr() net.Addr
 	TLSConnectionState() *tls.ConnectionState
+	SecurityProfile() ws.SecurityProfile
+	Stats() ws.ConnectionStats
+	// TimeoutConfig returns the timeouts currently used by the connection.
+	TimeoutConfig() ws.ChannelTimeoutConfig
+	// SetTimeoutConfig changes the timeouts of the open connection, without reconnecting the charging station.
+	// E.g. after changing the WebSocketPingInterval of the charging station, the read timeout may be adjusted accordingly.
+	SetTimeoutConfig(config ws.ChannelTimeoutConfig)
+	// DisconnectReason returns the close code, close text, initiator and network error, which caused the connection to be closed.
+	// Returns nil while the connection is open.
+	DisconnectReason() *ws.DisconnectReason
+	// SetAttribute attaches an arbitrary value to the connection, e.g. the tenant or site the charging station belongs to.
+	// Attributes live as long as the connection. Passing a nil value removes the attribute.
+	SetAttribute(key string, value interface{})
+	Attribute(key string) (interface{}, bool)
+	Attributes() map[string]interface{}
 }
 
 type (
@@ -39,6 +58,17 @@ type (
 	ChargingStationConnectionHandler func(chargePoint ChargingStationConnection)
 )
 
+// CustomProfileName is the name of the profile, to which all custom features of a charging station or CSMS are added.
+const CustomProfileName = "Custom"
+
+// CustomChargingStationHandler processes incoming requests of a custom feature on a charging station.
+// The returned response must be of the feature's response type.
+type CustomChargingStationHandler func(request ocpp.Request) (ocpp.Response, error)
+
+// CustomCSMSHandler processes incoming requests of a custom feature on a CSMS.
+// The returned response must be of the feature's response type.
+type CustomCSMSHandler func(chargingStationID string, request ocpp.Request) (ocpp.Response, error)
+
 // -------------------- v2.0 Charging Station --------------------
 
 // A Charging Station represents the physical system where an EV can be charged.
@@ -71,6 +101,9 @@ type ChargingStation interface {
 	ClearedChargingLimit(chargingLimitSource types.ChargingLimitSourceType, props ...func(request *smartcharging.ClearedChargingLimitRequest)) (*smartcharging.ClearedChargingLimitResponse, error)
 	// Performs a custom data transfer to the CSMS. The message payload is not pre-defined and must be supported by the CSMS. Every vendor may implement their own proprietary logic for this message.
 	DataTransfer(vendorId string, props ...func(request *data.DataTransferRequest)) (*data.DataTransferResponse, error)
+	// Performs a vendor-specific data transfer to the CSMS, carrying the passed data.
+	// If the CSMS accepts the request, the response data is decoded into the message's ResponseType and validated.
+	SendDataTransfer(message datatransfer.Message, requestData interface{}) (*data.DataTransferResponse, interface{}, error)
 	// Notifies the CSMS of a status change during a firmware update procedure (download, installation).
 	FirmwareStatusNotification(status firmware.FirmwareStatus, props ...func(request *firmware.FirmwareStatusNotificationRequest)) (*firmware.FirmwareStatusNotificationResponse, error)
 	// Requests a new certificate, required for an ISO 15118 EV, from the CSMS.
@@ -145,6 +178,15 @@ type ChargingStation interface {
 	SetDisplayHandler(handler display.ChargingStationHandler)
 	// Registers a handler for incoming data transfer messages
 	SetDataHandler(handler data.ChargingStationHandler)
+	// Registers typed handlers for incoming vendor-specific data transfer messages.
+	// If a registry is set, all data transfer requests are routed through it instead of the data handler.
+	SetDataTransferRegistry(registry *datatransfer.Registry)
+	// Registers a vendor-specific feature, which isn't part of any OCPP 2.0.1 functional block.
+	// Requests of the feature may be sent via SendRequest and SendRequestAsync, like any built-in request.
+	// The handler processes incoming requests of the feature. If nil, the feature may only be sent.
+	// Returns an error if a feature with the same name is already supported.
+	// Custom features must be added before starting the charging station.
+	AddCustomFeature(feature ocpp.Feature, handler CustomChargingStationHandler) error
 	// Sends a request to the CSMS.
 	// The CSMS will respond with a confirmation, or with an error if the request was invalid or could not be processed.
 	// In case of network issues (i.e. the remote host couldn't be reached), the function also returns an error.
@@ -157,6 +199,11 @@ type ChargingStation interface {
 	//
 	// In case of network issues (i.e. the remote host couldn't be reached), the function returns an error directly. In this case, the callback is never invoked.
 	SendRequestAsync(request ocpp.Request, callback func(confirmation ocpp.Response, protoError error)) error
+	// SetSecurityProfile sets the OCPP security profile to enforce when connecting to the CSMS.
+	// Refer to ws.WithClientSecurityProfile for the requirements of each profile.
+	//
+	// The profile must be set before calling Start.
+	SetSecurityProfile(profile ws.SecurityProfile)
 	// Connects to the CSMS and starts the charging station routine.
 	// The function doesn't block and returns right away, after having attempted to open a connection to the CSMS.
 	// If the connection couldn't be opened, an error is returned.
@@ -172,6 +219,47 @@ type ChargingStation interface {
 	//
 	// Optional client options must be set before calling this function. Refer to NewChargingStation.
 	StartWithRetries(csmsUrl string)
+	// StartWithFailover connects to one out of several CSMS endpoints, ordered by priority.
+	// Each endpoint may have its own URL, TLS configuration and basic auth credentials.
+	// The charging station identifier is appended to each endpoint URL.
+	//
+	// The function blocks until a connection was established, rotating endpoints after repeated failures.
+	// Refer to ws.FailoverConfig for more information.
+	StartWithFailover(config ws.FailoverConfig) error
+	// ActiveEndpoint returns the CSMS endpoint the client is currently connected to, or attempting to connect to,
+	// along with its index within the failover configuration.
+	// If the client wasn't started via StartWithFailover, the function returns false.
+	ActiveEndpoint() (ws.ServerEndpoint, int, bool)
+	// Reconnect closes the current connection and immediately reconnects to the CSMS.
+	// Use this function to apply new credentials, e.g. after installing a new client certificate
+	// via a certificate provider (see ws.WithClientCertificateProvider).
+	//
+	// If the connection couldn't be re-established, an error is returned and the client keeps retrying automatically.
+	Reconnect() error
+	// SetReconnectStrategy sets the strategy used for automatic reconnections to the CSMS.
+	// If not set, the back-off parameters of the websocket client timeout configuration are used.
+	// Refer to ws.ReconnectStrategy for the available strategies.
+	SetReconnectStrategy(strategy ws.ReconnectStrategy)
+	// SetConnectionStateHandler sets a callback, which is invoked whenever the state of the connection to the CSMS changes,
+	// e.g. when backing off before a reconnection attempt, or when giving up reconnecting.
+	// Refer to ws.ConnectionStateEvent for the information carried by each event.
+	// Disconnection, back-off and give-up events carry the close code, close text, initiator and network error
+	// of the lost connection, or the error of the failed connection attempt (see ws.DisconnectReason).
+	//
+	// The callback is invoked synchronously and should return quickly.
+	SetConnectionStateHandler(handler func(event ws.ConnectionStateEvent))
+	// ConnectionState returns the most recent state of the connection to the CSMS.
+	ConnectionState() ws.ConnectionStateEvent
+	// SetDisconnectedHandler sets a callback, which is invoked when the connection to the CSMS is lost.
+	// The passed reason contains the close code, close text, initiator and network error of the lost connection.
+	SetDisconnectedHandler(handler func(reason *ws.DisconnectReason))
+	// SetWebSocketPingInterval changes the interval for sending websocket pings to the CSMS, without reconnecting.
+	// The pong wait is adjusted accordingly (see ws.ClientTimeoutConfig.WithPingPeriod). A zero interval disables pings.
+	SetWebSocketPingInterval(interval time.Duration)
+	// SetWebSocketPingIntervalSync enables or disables the automatic handling of the OCPPCommCtrlr.WebSocketPingInterval variable.
+	// If enabled, whenever the provisioning handler accepts a SetVariables request for the variable,
+	// the new value (in seconds) is applied to the connection via SetWebSocketPingInterval.
+	SetWebSocketPingIntervalSync(enabled bool)
 	// Stops the charging station routine, disconnecting it from the CSMS.
 	// Any pending requests are discarded.
 	Stop()
@@ -225,6 +313,7 @@ func NewChargingStation(id string, endpoint *ocppj.Client, client ws.Client) Cha
 		responseHandler: make(chan ocpp.Response, 1),
 		errorHandler:    make(chan error, 1),
 		callbacks:       callbackqueue.New(),
+		customHandlers:  map[string]CustomChargingStationHandler{},
 	}
 
 	// Callback invoked by dispatcher, whenever a queued request is canceled, due to timeout.
@@ -288,6 +377,9 @@ type CSMS interface {
 	CustomerInformation(clientId string, callback func(*diagnostics.CustomerInformationResponse, error), requestId int, report bool, clear bool, props ...func(*diagnostics.CustomerInformationRequest)) error
 	// Performs a custom data transfer to a charging station. The message payload is not pre-defined and must be supported by the charging station. Every vendor may implement their own proprietary logic for this message.
 	DataTransfer(clientId string, callback func(*data.DataTransferResponse, error), vendorId string, props ...func(*data.DataTransferRequest)) error
+	// Performs a vendor-specific data transfer to a charging station, carrying the passed data.
+	// If the charging station accepts the request, the response data is decoded into the message's ResponseType and validated, before invoking the callback.
+	SendDataTransfer(clientId string, callback func(response *data.DataTransferResponse, responseData interface{}, err error), message datatransfer.Message, requestData interface{}) error
 	// Deletes a previously installed certificate on a charging station.
 	DeleteCertificate(clientId string, callback func(*iso15118.DeleteCertificateResponse, error), data types.CertificateHashData, props ...func(*iso15118.DeleteCertificateRequest)) error
 	// Requests a report from a charging station. The charging station will asynchronously send the report in chunks using NotifyReportRequest messages.
@@ -381,24 +473,72 @@ type CSMS interface {
 	SetDisplayHandler(handler display.CSMSHandler)
 	// Registers a handler for incoming data transfer messages
 	SetDataHandler(handler data.CSMSHandler)
+	// Registers typed handlers for incoming vendor-specific data transfer messages.
+	// If a registry is set, all data transfer requests are routed through it instead of the data handler.
+	SetDataTransferRegistry(registry *datatransfer.Registry)
+	// Registers a vendor-specific feature, which isn't part of any OCPP 2.0.1 functional block.
+	// Requests of the feature may be sent via SendRequestAsync, like any built-in request.
+	// The handler processes incoming requests of the feature. If nil, the feature may only be sent.
+	// Returns an error if a feature with the same name is already supported.
+	// Custom features must be added before starting the CSMS.
+	AddCustomFeature(feature ocpp.Feature, handler CustomCSMSHandler) error
 	// Registers a handler for new incoming Charging station connections.
 	SetNewChargingStationValidationHandler(handler ws.CheckClientHandler)
 	// Registers a handler for new incoming Charging station connections.
 	SetNewChargingStationHandler(handler ChargingStationConnectionHandler)
 	// Registers a handler for Charging station disconnections.
+	// The reason for the disconnection is available via the DisconnectReason function of the passed connection.
 	SetChargingStationDisconnectedHandler(handler ChargingStationConnectionHandler)
 	// Sends an asynchronous request to a Charging Station, identified by the clientId.
 	// The charging station will respond with a confirmation message, or with an error if the request was invalid or could not be processed.
 	// This result is propagated via a callback, called asynchronously.
 	// In case of network issues (i.e. the remote host couldn't be reached), the function returns an error directly. In this case, the callback is never invoked.
 	SendRequestAsync(clientId string, request ocpp.Request, callback func(ocpp.Response, error)) error
+	// SetSecurityProfile sets the OCPP security profile to enforce on all incoming charging station connections.
+	// Refer to ws.WithServerSecurityProfile for the requirements of each profile.
+	//
+	// The profile must be set before calling Start.
+	SetSecurityProfile(profile ws.SecurityProfile)
+	// ChargingStationConnections returns all connected charging stations, sorted by their ID.
+	ChargingStationConnections() []ChargingStationConnection
+	// ChargingStationConnectionCount returns the number of connected charging stations.
+	ChargingStationConnectionCount() int
+	// GetChargingStationConnection returns the connection of a charging station, identified by its ID.
+	// If the charging station isn't connected, the function returns false.
+	GetChargingStationConnection(chargingStationId string) (ChargingStationConnection, bool)
+	// DisconnectChargingStation closes the connection to a charging station, sending a close frame with the passed close code and reason
+	// (e.g. websocket.ClosePolicyViolation). Callbacks for pending requests are invoked with an error.
+	//
+	// An error is returned, if the charging station isn't connected.
+	DisconnectChargingStation(chargingStationId string, code int, reason string) error
 	// Starts running the CSMS on the specified port and URL.
 	// The central system runs as a daemon and handles incoming charge point connections and messages.
 
 	// The function blocks forever, so it is suggested to wrap it in a goroutine, in case other functionality needs to be executed on the main program thread.
 	Start(listenPort int, listenPath string)
+	// Serve behaves like Start, but accepts incoming charging station connections on the passed listener
+	// (e.g. a unix socket or a listener created via systemd socket activation).
+	//
+	// The function blocks forever, so it is suggested to wrap it in a goroutine.
+	Serve(listener net.Listener, listenPath string)
+	// Handler returns an http.Handler, which accepts incoming charging station connections
+	// and may be mounted on an existing HTTP server, e.g.:
+	//	router.Handle("/ocpp/{id}", csms.Handler())
+	//
+	// Connections are handled exactly like with Start. The function doesn't block.
+	// The lifecycle of the external HTTP server, including TLS termination, is up to the caller.
+	Handler() http.Handler
 	// Stops the CSMS, clearing all pending requests.
 	Stop()
+	// Gracefully shuts down the CSMS.
+	//
+	// New charging station connections are refused and no new requests may be sent.
+	// The function waits for pending requests to complete (or time out), until the passed context is done.
+	// All callbacks of requests, that didn't receive a response, are then invoked with a shutdown error.
+	// Finally, all charging station connections are closed with a "going away" close frame.
+	//
+	// If the context is done before all pending requests completed, the context error is returned.
+	Shutdown(ctx context.Context) error
 	// Errors returns a channel for error messages. If it doesn't exist it es created.
 	Errors() <-chan error
 }
@@ -453,5 +593,6 @@ func NewCSMS(endpoint *ocppj.Server, server ws.Server) CSMS {
 	cs.server.SetCanceledRequestHandler(func(clientID string, requestID string, request ocpp.Request, err *ocpp.Error) {
 		cs.handleCanceledRequest(clientID, request, err)
 	})
+	cs.server.SetClientReplacedHandler(cs.handleClientReplaced)
 	return &cs
 }
@@ -0,0 +1,123 @@
+package ocpp2_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/availability"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/types"
+)
+
+type vendorExtension struct {
+	VendorID string `json:"vendorId"`
+	Model    string `json:"model"`
+	Slots    []int  `json:"slots"`
+}
+
+// Test
+func (suite *OcppV2TestSuite) TestCustomDataValidation() {
+	t := suite.T()
+	var testTable = []GenericTestEntry{
+		{types.CustomData{VendorID: "com.example"}, true},
+		{types.CustomData{VendorID: "com.example", Properties: map[string]json.RawMessage{"key": json.RawMessage(`"value"`)}}, true},
+		{types.CustomData{}, false},
+		{types.CustomData{VendorID: strings.Repeat("x", 256)}, false},
+		{availability.HeartbeatRequest{CustomData: types.NewCustomData("com.example")}, true},
+		{availability.HeartbeatRequest{CustomData: &types.CustomData{}}, false},
+		{types.IdToken{IdToken: "1234", Type: types.IdTokenTypeKeyCode, CustomData: &types.CustomData{}}, false},
+		{types.StatusInfo{ReasonCode: "200", CustomData: &types.CustomData{}}, false},
+	}
+	ExecuteGenericTestTable(t, testTable)
+}
+
+func (suite *OcppV2TestSuite) TestCustomDataMarshaling() {
+	t := suite.T()
+	input := `{"slots":[1,2],"vendorId":"com.example","model":"x1","nested":{"a":true}}`
+	var customData types.CustomData
+	err := json.Unmarshal([]byte(input), &customData)
+	require.NoError(t, err)
+	assert.Equal(t, "com.example", customData.VendorID)
+	assert.Len(t, customData.Properties, 3)
+	assert.False(t, customData.Has("vendorId"))
+	// Typed accessors
+	var model string
+	ok, err := customData.Get("model", &model)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "x1", model)
+	ok, err = customData.Get("missing", &model)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	var invalid int
+	ok, err = customData.Get("model", &invalid)
+	assert.True(t, ok)
+	assert.Error(t, err)
+	require.NoError(t, customData.Set("model", "x2"))
+	assert.Error(t, customData.Set("vendorId", "other"))
+	customData.Delete("nested")
+	// Unknown properties are serialized again, vendorId first
+	data, err := json.Marshal(&customData)
+	require.NoError(t, err)
+	assert.Equal(t, `{"vendorId":"com.example","model":"x2","slots":[1,2]}`, string(data))
+	// Struct mapping
+	var extension vendorExtension
+	require.NoError(t, customData.Decode(&extension))
+	assert.Equal(t, vendorExtension{VendorID: "com.example", Model: "x2", Slots: []int{1, 2}}, extension)
+	encoded := types.NewCustomData("")
+	require.NoError(t, encoded.Encode(extension))
+	assert.Equal(t, customData, *encoded)
+}
+
+func (suite *OcppV2TestSuite) TestCustomDataE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	vendorID := "com.example"
+	currentTime := types.NewDateTime(time.Now())
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"customData":{"vendorId":"%v","model":"x1"}}]`, messageId, availability.HeartbeatFeatureName, vendorID)
+	responseJson := fmt.Sprintf(`[3,"%v",{"currentTime":"%v","customData":{"vendorId":"%v","accepted":true}}]`, messageId, currentTime.FormatTimestamp(), vendorID)
+	heartbeatResponse := availability.NewHeartbeatResponse(*currentTime)
+	heartbeatResponse.CustomData = types.NewCustomData(vendorID)
+	require.NoError(t, heartbeatResponse.CustomData.Set("accepted", true))
+	channel := NewMockWebSocket(wsId)
+
+	handler := &MockCSMSAvailabilityHandler{}
+	handler.On("OnHeartbeat", mock.AnythingOfType("string"), mock.Anything).Return(heartbeatResponse, nil).Run(func(args mock.Arguments) {
+		request, ok := args.Get(1).(*availability.HeartbeatRequest)
+		require.True(t, ok)
+		require.NotNil(t, request)
+		require.NotNil(t, request.CustomData)
+		assert.Equal(t, vendorID, request.CustomData.VendorID)
+		var model string
+		found, err := request.CustomData.Get("model", &model)
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, "x1", model)
+	})
+	setupDefaultCSMSHandlers(suite, expectedCSMSOptions{clientId: wsId, rawWrittenMessage: []byte(responseJson), forwardWrittenMessage: true}, handler)
+	setupDefaultChargingStationHandlers(suite, expectedChargingStationOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	err := suite.chargingStation.Start(wsUrl)
+	require.Nil(t, err)
+	customData := types.NewCustomData(vendorID)
+	require.NoError(t, customData.Set("model", "x1"))
+	response, err := suite.chargingStation.Heartbeat(func(request *availability.HeartbeatRequest) {
+		request.CustomData = customData
+	})
+	require.Nil(t, err)
+	require.NotNil(t, response)
+	require.NotNil(t, response.CustomData)
+	var accepted bool
+	found, err := response.CustomData.Get("accepted", &accepted)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.True(t, accepted)
+}
@@ -0,0 +1,164 @@
+package ocpp2_test
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/availability"
+	"github.com/lorenzodonini/ocpp-go/ocppj"
+)
+
+// Vendor-specific feature, not defined by OCPP 2.0.1
+const setLedColorFeatureName = "SetLedColor"
+
+type SetLedColorRequest struct {
+	EvseID int    `json:"evseId" validate:"gte=0"`
+	Color  string `json:"color" validate:"required,oneof=red green blue"`
+}
+
+type SetLedColorResponse struct {
+	Accepted bool `json:"accepted"`
+}
+
+type SetLedColorFeature struct{}
+
+func (f SetLedColorFeature) GetFeatureName() string {
+	return setLedColorFeatureName
+}
+
+func (f SetLedColorFeature) GetRequestType() reflect.Type {
+	return reflect.TypeOf(SetLedColorRequest{})
+}
+
+func (f SetLedColorFeature) GetResponseType() reflect.Type {
+	return reflect.TypeOf(SetLedColorResponse{})
+}
+
+func (r SetLedColorRequest) GetFeatureName() string {
+	return setLedColorFeatureName
+}
+
+func (r SetLedColorResponse) GetFeatureName() string {
+	return setLedColorFeatureName
+}
+
+// Test
+func (suite *OcppV2TestSuite) TestAddCustomFeature() {
+	t := suite.T()
+	err := suite.chargingStation.AddCustomFeature(SetLedColorFeature{}, nil)
+	require.NoError(t, err)
+	// Features cannot be registered twice, nor override built-in features
+	err = suite.chargingStation.AddCustomFeature(SetLedColorFeature{}, nil)
+	assert.EqualError(t, err, fmt.Sprintf("feature %v is already supported on charging station", setLedColorFeatureName))
+	err = suite.csms.AddCustomFeature(availability.HeartbeatFeature{}, nil)
+	assert.EqualError(t, err, fmt.Sprintf("feature %v is already supported on CSMS", availability.HeartbeatFeatureName))
+}
+
+func (suite *OcppV2TestSuite) TestCustomFeatureFromChargingStationE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	evseId := 1
+	color := "green"
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"evseId":%v,"color":"%v"}]`, messageId, setLedColorFeatureName, evseId, color)
+	responseJson := fmt.Sprintf(`[3,"%v",{"accepted":true}]`, messageId)
+	channel := NewMockWebSocket(wsId)
+
+	setupDefaultCSMSHandlers(suite, expectedCSMSOptions{clientId: wsId, rawWrittenMessage: []byte(responseJson), forwardWrittenMessage: true})
+	setupDefaultChargingStationHandlers(suite, expectedChargingStationOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	err := suite.csms.AddCustomFeature(SetLedColorFeature{}, func(chargingStationID string, request ocpp.Request) (ocpp.Response, error) {
+		assert.Equal(t, wsId, chargingStationID)
+		setLedColor, ok := request.(*SetLedColorRequest)
+		require.True(t, ok)
+		assert.Equal(t, evseId, setLedColor.EvseID)
+		assert.Equal(t, color, setLedColor.Color)
+		return &SetLedColorResponse{Accepted: true}, nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, suite.chargingStation.AddCustomFeature(SetLedColorFeature{}, nil))
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	err = suite.chargingStation.Start(wsUrl)
+	require.Nil(t, err)
+	// Invalid requests are rejected before being sent
+	_, err = suite.chargingStation.SendRequest(&SetLedColorRequest{EvseID: evseId, Color: "pink"})
+	require.Error(t, err)
+	response, err := suite.chargingStation.SendRequest(&SetLedColorRequest{EvseID: evseId, Color: color})
+	require.Nil(t, err)
+	require.IsType(t, &SetLedColorResponse{}, response)
+	assert.True(t, response.(*SetLedColorResponse).Accepted)
+}
+
+func (suite *OcppV2TestSuite) TestCustomFeatureFromCsmsE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	evseId := 2
+	color := "red"
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"evseId":%v,"color":"%v"}]`, messageId, setLedColorFeatureName, evseId, color)
+	responseJson := fmt.Sprintf(`[3,"%v",{"accepted":false}]`, messageId)
+	channel := NewMockWebSocket(wsId)
+
+	setupDefaultCSMSHandlers(suite, expectedCSMSOptions{clientId: wsId, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	setupDefaultChargingStationHandlers(suite, expectedChargingStationOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(responseJson), forwardWrittenMessage: true})
+	err := suite.chargingStation.AddCustomFeature(SetLedColorFeature{}, func(request ocpp.Request) (ocpp.Response, error) {
+		setLedColor, ok := request.(*SetLedColorRequest)
+		require.True(t, ok)
+		assert.Equal(t, evseId, setLedColor.EvseID)
+		assert.Equal(t, color, setLedColor.Color)
+		return &SetLedColorResponse{Accepted: false}, nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, suite.csms.AddCustomFeature(SetLedColorFeature{}, nil))
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	err = suite.chargingStation.Start(wsUrl)
+	require.Nil(t, err)
+	resultChannel := make(chan bool, 1)
+	err = suite.csms.SendRequestAsync(wsId, &SetLedColorRequest{EvseID: evseId, Color: color}, func(response ocpp.Response, err error) {
+		require.Nil(t, err)
+		require.IsType(t, &SetLedColorResponse{}, response)
+		assert.False(t, response.(*SetLedColorResponse).Accepted)
+		resultChannel <- true
+	})
+	require.Nil(t, err)
+	result := <-resultChannel
+	assert.True(t, result)
+}
+
+func (suite *OcppV2TestSuite) TestCustomFeatureWithoutHandlerE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"evseId":0,"color":"blue"}]`, messageId, setLedColorFeatureName)
+	errorJson := fmt.Sprintf(`[4,"%v","%v","unsupported action %v on charging station",{}]`, messageId, ocppj.NotSupported, setLedColorFeatureName)
+	channel := NewMockWebSocket(wsId)
+
+	setupDefaultCSMSHandlers(suite, expectedCSMSOptions{clientId: wsId, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	setupDefaultChargingStationHandlers(suite, expectedChargingStationOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(errorJson), forwardWrittenMessage: true})
+	require.NoError(t, suite.chargingStation.AddCustomFeature(SetLedColorFeature{}, nil))
+	require.NoError(t, suite.csms.AddCustomFeature(SetLedColorFeature{}, nil))
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	err := suite.chargingStation.Start(wsUrl)
+	require.Nil(t, err)
+	resultChannel := make(chan bool, 1)
+	err = suite.csms.SendRequestAsync(wsId, &SetLedColorRequest{Color: "blue"}, func(response ocpp.Response, err error) {
+		require.Error(t, err)
+		assert.Nil(t, response)
+		ocppErr, ok := err.(*ocpp.Error)
+		require.True(t, ok)
+		assert.Equal(t, ocppj.NotSupported, ocppErr.Code)
+		resultChannel <- true
+	})
+	require.Nil(t, err)
+	result := <-resultChannel
+	assert.True(t, result)
+}
@@ -2,11 +2,13 @@ package ocpp2_test
 
 import (
 	"fmt"
+	"reflect"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/lorenzodonini/ocpp-go/datatransfer"
 	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/data"
 )
 
@@ -104,3 +106,120 @@ func (suite *OcppV2TestSuite) TestDataTransferFromCentralSystemE2EMocked() {
 	result := <-resultChannel
 	assert.True(t, result)
 }
+
+type limitRequestData struct {
+	EvseID int     `json:"evseId" validate:"gt=0"`
+	Limit  float64 `json:"limit" validate:"gte=0"`
+}
+
+type limitResponseData struct {
+	Applied bool `json:"applied"`
+}
+
+var setLimitMessage = datatransfer.Message{
+	VendorID:     "com.example",
+	MessageID:    "SetLimit",
+	RequestType:  reflect.TypeOf(limitRequestData{}),
+	ResponseType: reflect.TypeOf(limitResponseData{}),
+}
+
+func (suite *OcppV2TestSuite) TestDataTransferRegistryFromChargingStationE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	requestData := limitRequestData{EvseID: 1, Limit: 16.5}
+	responseData := limitResponseData{Applied: true}
+	status := data.DataTransferStatusAccepted
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"messageId":"%v","data":{"evseId":%v,"limit":%v},"vendorId":"%v"}]`, messageId, data.DataTransferFeatureName, setLimitMessage.MessageID, requestData.EvseID, requestData.Limit, setLimitMessage.VendorID)
+	responseJson := fmt.Sprintf(`[3,"%v",{"status":"%v","data":{"applied":%v}}]`, messageId, status, responseData.Applied)
+	channel := NewMockWebSocket(wsId)
+
+	registry := datatransfer.NewRegistry()
+	registry.Register(setLimitMessage, func(clientID string, request interface{}) (datatransfer.Status, interface{}, error) {
+		assert.Equal(t, wsId, clientID)
+		limit, ok := request.(*limitRequestData)
+		require.True(t, ok)
+		assert.Equal(t, requestData, *limit)
+		return datatransfer.StatusAccepted, responseData, nil
+	})
+	// No data handler is needed for routing data transfer messages via the registry
+	setupDefaultCSMSHandlers(suite, expectedCSMSOptions{clientId: wsId, rawWrittenMessage: []byte(responseJson), forwardWrittenMessage: true})
+	setupDefaultChargingStationHandlers(suite, expectedChargingStationOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	suite.csms.SetDataTransferRegistry(registry)
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	err := suite.chargingStation.Start(wsUrl)
+	require.Nil(t, err)
+	response, result, err := suite.chargingStation.SendDataTransfer(setLimitMessage, requestData)
+	require.Nil(t, err)
+	require.NotNil(t, response)
+	assert.Equal(t, status, response.Status)
+	require.IsType(t, &limitResponseData{}, result)
+	assert.Equal(t, responseData, *result.(*limitResponseData))
+}
+
+func (suite *OcppV2TestSuite) TestDataTransferRegistryInvalidDataE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	// Typed data would already be rejected by the sender, hence untyped data is sent
+	requestData := map[string]interface{}{"evseId": 0, "limit": 16.5}
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"messageId":"%v","data":{"evseId":%v,"limit":%v},"vendorId":"%v"}]`, messageId, data.DataTransferFeatureName, setLimitMessage.MessageID, requestData["evseId"], requestData["limit"], setLimitMessage.VendorID)
+	channel := NewMockWebSocket(wsId)
+
+	registry := datatransfer.NewRegistry()
+	registry.Register(setLimitMessage, func(clientID string, request interface{}) (datatransfer.Status, interface{}, error) {
+		t.Fatal("unexpected handler invocation")
+		return "", nil, nil
+	})
+	setupDefaultCSMSHandlers(suite, expectedCSMSOptions{clientId: wsId, forwardWrittenMessage: true})
+	setupDefaultChargingStationHandlers(suite, expectedChargingStationOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	suite.csms.SetDataTransferRegistry(registry)
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	err := suite.chargingStation.Start(wsUrl)
+	require.Nil(t, err)
+	response, result, err := suite.chargingStation.SendDataTransfer(setLimitMessage, requestData)
+	require.Error(t, err)
+	assert.Nil(t, response)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "PropertyConstraintViolation")
+}
+
+func (suite *OcppV2TestSuite) TestDataTransferRegistryFromCsmsE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	requestData := limitRequestData{EvseID: 1, Limit: 16.5}
+	unknownVendor := datatransfer.Message{VendorID: "com.other", MessageID: setLimitMessage.MessageID}
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"messageId":"%v","data":{"evseId":%v,"limit":%v},"vendorId":"%v"}]`, messageId, data.DataTransferFeatureName, unknownVendor.MessageID, requestData.EvseID, requestData.Limit, unknownVendor.VendorID)
+	responseJson := fmt.Sprintf(`[3,"%v",{"status":"%v"}]`, messageId, data.DataTransferStatusUnknownVendorId)
+	channel := NewMockWebSocket(wsId)
+
+	registry := datatransfer.NewRegistry()
+	registry.Register(setLimitMessage, func(clientID string, request interface{}) (datatransfer.Status, interface{}, error) {
+		t.Fatal("unexpected handler invocation")
+		return "", nil, nil
+	})
+	setupDefaultCSMSHandlers(suite, expectedCSMSOptions{clientId: wsId, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	setupDefaultChargingStationHandlers(suite, expectedChargingStationOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(responseJson), forwardWrittenMessage: true})
+	suite.chargingStation.SetDataTransferRegistry(registry)
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	err := suite.chargingStation.Start(wsUrl)
+	require.Nil(t, err)
+	resultChannel := make(chan bool, 1)
+	err = suite.csms.SendDataTransfer(wsId, func(response *data.DataTransferResponse, result interface{}, err error) {
+		require.Nil(t, err)
+		require.NotNil(t, response)
+		assert.Equal(t, data.DataTransferStatusUnknownVendorId, response.Status)
+		assert.Nil(t, result)
+		resultChannel <- true
+	}, unknownVendor, requestData)
+	require.Nil(t, err)
+	result := <-resultChannel
+	assert.True(t, result)
+}
@@ -1,6 +1,7 @@
 package ocpp2_test
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"net"
@@ -8,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gorilla/websocket"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -62,6 +64,43 @@ func (websocket MockWebSocket) IsConnected() bool {
 	return true
 }
 
+func (websocket MockWebSocket) CompressionStats() ws.CompressionStats {
+	return ws.CompressionStats{}
+}
+
+func (websocket MockWebSocket) SetCompressionEnabled(enabled bool) {
+}
+
+func (websocket MockWebSocket) SecurityProfile() ws.SecurityProfile {
+	return ws.SecurityProfileNone
+}
+
+func (websocket MockWebSocket) Stats() ws.ConnectionStats {
+	return ws.ConnectionStats{}
+}
+
+func (websocket MockWebSocket) TimeoutConfig() ws.ChannelTimeoutConfig {
+	return ws.ChannelTimeoutConfig{}
+}
+
+func (websocket MockWebSocket) SetTimeoutConfig(config ws.ChannelTimeoutConfig) {
+}
+
+func (websocket MockWebSocket) DisconnectReason() *ws.DisconnectReason {
+	return nil
+}
+
+func (websocket MockWebSocket) SetAttribute(key string, value interface{}) {
+}
+
+func (websocket MockWebSocket) Attribute(key string) (interface{}, bool) {
+	return nil, false
+}
+
+func (websocket MockWebSocket) Attributes() map[string]interface{} {
+	return map[string]interface{}{}
+}
+
 func NewMockWebSocket(id string) MockWebSocket {
 	return MockWebSocket{id: id}
 }
@@ -75,6 +114,7 @@ type MockWebsocketServer struct {
 	NewClientHandler          func(ws ws.Channel)
 	CheckClientHandler        ws.CheckClientHandler
 	DisconnectedClientHandler func(ws ws.Channel)
+	ReplacedClientHandler     func(ws ws.Channel)
 }
 
 func (websocketServer *MockWebsocketServer) Start(port int, listenPath string) {
@@ -85,6 +125,11 @@ func (websocketServer *MockWebsocketServer) Stop() {
 	websocketServer.MethodCalled("Stop")
 }
 
+func (websocketServer *MockWebsocketServer) Shutdown(ctx context.Context) error {
+	args := websocketServer.MethodCalled("Shutdown", ctx)
+	return args.Error(0)
+}
+
 func (websocketServer *MockWebsocketServer) Write(webSocketId string, data []byte) error {
 	args := websocketServer.MethodCalled("Write", webSocketId, data)
 	return args.Error(0)
@@ -102,6 +147,10 @@ func (websocketServer *MockWebsocketServer) SetDisconnectedClientHandler(handler
 	websocketServer.DisconnectedClientHandler = handler
 }
 
+func (websocketServer *MockWebsocketServer) SetReplacedClientHandler(handler func(ws ws.Channel)) {
+	websocketServer.ReplacedClientHandler = handler
+}
+
 func (websocketServer *MockWebsocketServer) AddSupportedSubprotocol(subProto string) {
 }
 
@@ -113,6 +162,27 @@ func (websocketServer *MockWebsocketServer) SetCheckClientHandler(handler ws.Che
 	websocketServer.CheckClientHandler = handler
 }
 
+func (websocketServer *MockWebsocketServer) GetChannel(websocketId string) (ws.Channel, bool) {
+	args := websocketServer.MethodCalled("GetChannel", websocketId)
+	channel, _ := args.Get(0).(ws.Channel)
+	return channel, args.Bool(1)
+}
+
+func (websocketServer *MockWebsocketServer) Channels() []ws.Channel {
+	args := websocketServer.MethodCalled("Channels")
+	return args.Get(0).([]ws.Channel)
+}
+
+func (websocketServer *MockWebsocketServer) ChannelCount() int {
+	args := websocketServer.MethodCalled("ChannelCount")
+	return args.Int(0)
+}
+
+func (websocketServer *MockWebsocketServer) StopConnection(id string, closeError websocket.CloseError) error {
+	args := websocketServer.MethodCalled("StopConnection", id, closeError)
+	return args.Error(0)
+}
+
 // ---------------------- MOCK WEBSOCKET CLIENT ----------------------
 
 type MockWebsocketClient struct {
@@ -160,6 +230,12 @@ func (websocketClient *MockWebsocketClient) SetBasicAuth(username string, passwo
 }
 
 func (websocketClient *MockWebsocketClient) SetTimeoutConfig(config ws.ClientTimeoutConfig) {
+	websocketClient.MethodCalled("SetTimeoutConfig", config)
+}
+
+func (websocketClient *MockWebsocketClient) TimeoutConfig() ws.ClientTimeoutConfig {
+	args := websocketClient.MethodCalled("TimeoutConfig")
+	return args.Get(0).(ws.ClientTimeoutConfig)
 }
 
 func (websocketClient *MockWebsocketClient) Errors() <-chan error {
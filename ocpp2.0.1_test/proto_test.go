@@ -1,12 +1,17 @@
 package ocpp2_test
 
 import (
+	"context"
 	"fmt"
+	"time"
 
+	"github.com/gorilla/websocket"
 	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/data"
 
 	"github.com/lorenzodonini/ocpp-go/ocpp"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1"
 	"github.com/lorenzodonini/ocpp-go/ocppj"
+	"github.com/lorenzodonini/ocpp-go/ws"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -158,7 +163,114 @@ func (suite *OcppV2TestSuite) TestCentralSystemSendResponseError() {
 	assert.Equal(t, fmt.Sprintf("empty response to %s for request 1234", wsId), ocppErr.Description)
 }
 
+func (suite *OcppV2TestSuite) TestCSMSShutdown() {
+	t := suite.T()
+	wsId := "test_id"
+	channel := NewMockWebSocket(wsId)
+	suite.mockWsServer.On("Start", mock.AnythingOfType("int"), mock.AnythingOfType("string")).Return(nil)
+	// Requests are written, but the charging station never responds
+	suite.mockWsServer.On("Write", mock.AnythingOfType("string"), mock.Anything).Return(nil)
+	suite.mockWsServer.On("Shutdown", mock.Anything).Return(nil)
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	suite.mockWsServer.NewClientHandler(channel)
+	callbackC := make(chan error, 1)
+	err := suite.csms.DataTransfer(wsId, func(response *data.DataTransferResponse, err error) {
+		assert.Nil(t, response)
+		callbackC <- err
+	}, "vendor1")
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	err = suite.csms.Shutdown(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	// Outstanding callback was invoked with a shutdown error
+	select {
+	case err = <-callbackC:
+		require.Error(t, err)
+		require.IsType(t, &ocpp.Error{}, err)
+		ocppErr := err.(*ocpp.Error)
+		assert.Equal(t, ocppj.GenericError, ocppErr.Code)
+		assert.Equal(t, "CSMS shutting down, no response received from client", ocppErr.Description)
+	default:
+		assert.Fail(t, "callback wasn't invoked")
+	}
+}
+
+func (suite *OcppV2TestSuite) TestCSMSChargingStationReplaced() {
+	t := suite.T()
+	wsId := "test_id"
+	oldChannel := NewMockWebSocket(wsId)
+	newChannel := NewMockWebSocket(wsId)
+	suite.mockWsServer.On("Start", mock.AnythingOfType("int"), mock.AnythingOfType("string")).Return(nil)
+	// Requests are written, but the charging station never responds
+	suite.mockWsServer.On("Write", mock.AnythingOfType("string"), mock.Anything).Return(nil)
+	var events []string
+	suite.csms.SetNewChargingStationHandler(func(chargingStation ocpp2.ChargingStationConnection) {
+		events = append(events, "connected")
+	})
+	suite.csms.SetChargingStationDisconnectedHandler(func(chargingStation ocpp2.ChargingStationConnection) {
+		events = append(events, "disconnected")
+	})
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	suite.mockWsServer.NewClientHandler(oldChannel)
+	callbackC := make(chan error, 1)
+	err := suite.csms.DataTransfer(wsId, func(response *data.DataTransferResponse, err error) {
+		assert.Nil(t, response)
+		callbackC <- err
+	}, "vendor1")
+	require.NoError(t, err)
+	// Simulate connection takeover
+	suite.mockWsServer.ReplacedClientHandler(oldChannel)
+	suite.mockWsServer.DisconnectedClientHandler(oldChannel)
+	suite.mockWsServer.NewClientHandler(newChannel)
+	// Outstanding callback was invoked with a replaced connection error
+	select {
+	case err = <-callbackC:
+		require.Error(t, err)
+		require.IsType(t, &ocpp.Error{}, err)
+		ocppErr := err.(*ocpp.Error)
+		assert.Equal(t, ocppj.GenericError, ocppErr.Code)
+		assert.Equal(t, "connection replaced by a new connection, no response received from client", ocppErr.Description)
+	default:
+		assert.Fail(t, "callback wasn't invoked")
+	}
+	assert.Equal(t, []string{"connected", "disconnected", "connected"}, events)
+}
+
 func (suite *OcppV2TestSuite) TestErrorCodes() {
 	suite.Equal(ocppj.FormatViolationV2, ocppj.FormatErrorType(suite.ocppjServer))
 	suite.Equal(ocppj.OccurrenceConstraintViolationV2, ocppj.OccurrenceConstraintErrorType(suite.ocppjServer))
 }
+
+func (suite *OcppV2TestSuite) TestCSMSChargingStationConnections() {
+	t := suite.T()
+	channel1 := NewMockWebSocket("cs1")
+	channel2 := NewMockWebSocket("cs2")
+	suite.mockWsServer.On("Channels").Return([]ws.Channel{channel1, channel2})
+	suite.mockWsServer.On("ChannelCount").Return(2)
+	suite.mockWsServer.On("GetChannel", "cs1").Return(channel1, true)
+	suite.mockWsServer.On("GetChannel", "unknown").Return(nil, false)
+	closeError := websocket.CloseError{Code: websocket.ClosePolicyViolation, Text: "banned"}
+	suite.mockWsServer.On("StopConnection", "cs1", closeError).Return(nil)
+	suite.mockWsServer.On("StopConnection", "unknown", mock.Anything).Return(fmt.Errorf("no connection with id unknown is open"))
+	// List and look up connections
+	connections := suite.csms.ChargingStationConnections()
+	require.Len(t, connections, 2)
+	assert.Equal(t, "cs1", connections[0].ID())
+	assert.Equal(t, "cs2", connections[1].ID())
+	assert.Equal(t, 2, suite.csms.ChargingStationConnectionCount())
+	connection, ok := suite.csms.GetChargingStationConnection("cs1")
+	require.True(t, ok)
+	assert.Equal(t, "cs1", connection.ID())
+	connection, ok = suite.csms.GetChargingStationConnection("unknown")
+	assert.False(t, ok)
+	assert.Nil(t, connection)
+	// Disconnect with a custom close code and reason
+	err := suite.csms.DisconnectChargingStation("cs1", websocket.ClosePolicyViolation, "banned")
+	assert.NoError(t, err)
+	err = suite.csms.DisconnectChargingStation("unknown", websocket.CloseNormalClosure, "")
+	assert.Error(t, err)
+	suite.mockWsServer.AssertCalled(t, "StopConnection", "cs1", closeError)
+}
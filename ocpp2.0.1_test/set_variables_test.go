@@ -2,13 +2,16 @@ package ocpp2_test
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	ocpp2 "github.com/lorenzodonini/ocpp-go/ocpp2.0.1"
 	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/provisioning"
 	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/types"
+	"github.com/lorenzodonini/ocpp-go/ws"
 )
 
 // Test
@@ -130,6 +133,49 @@ func (suite *OcppV2TestSuite) TestSetVariablesE2EMocked() {
 	assert.True(t, result)
 }
 
+func (suite *OcppV2TestSuite) TestSetVariablesWebSocketPingIntervalSync() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	component := types.Component{Name: ocpp2.OCPPCommCtrlrComponent}
+	variable := types.Variable{Name: ocpp2.WebSocketPingIntervalVariable}
+	status := provisioning.SetVariableStatusAccepted
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"setVariableData":[{"attributeValue":"30","component":{"name":"%v"},"variable":{"name":"%v"}}]}]`,
+		messageId, provisioning.SetVariablesFeatureName, component.Name, variable.Name)
+	responseJson := fmt.Sprintf(`[3,"%v",{"setVariableResult":[{"attributeStatus":"%v","component":{"name":"%v"},"variable":{"name":"%v"}}]}]`,
+		messageId, status, component.Name, variable.Name)
+	setVariablesResponse := provisioning.NewSetVariablesResponse([]provisioning.SetVariableResult{{AttributeStatus: status, Component: component, Variable: variable}})
+	channel := NewMockWebSocket(wsId)
+
+	handler := &MockChargingStationProvisioningHandler{}
+	handler.On("OnSetVariables", mock.Anything).Return(setVariablesResponse, nil)
+	setupDefaultCSMSHandlers(suite, expectedCSMSOptions{clientId: wsId, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	setupDefaultChargingStationHandlers(suite, expectedChargingStationOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(responseJson), forwardWrittenMessage: true}, handler)
+	suite.mockWsClient.On("TimeoutConfig").Return(ws.NewClientTimeoutConfig())
+	appliedC := make(chan ws.ClientTimeoutConfig, 1)
+	suite.mockWsClient.On("SetTimeoutConfig", mock.Anything).Run(func(args mock.Arguments) {
+		appliedC <- args.Get(0).(ws.ClientTimeoutConfig)
+	})
+	suite.chargingStation.SetWebSocketPingIntervalSync(true)
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	err := suite.chargingStation.Start(wsUrl)
+	require.Nil(t, err)
+	resultChannel := make(chan bool, 1)
+	err = suite.csms.SetVariables(wsId, func(response *provisioning.SetVariablesResponse, err error) {
+		require.Nil(t, err)
+		require.NotNil(t, response)
+		resultChannel <- true
+	}, []provisioning.SetVariableData{{AttributeValue: "30", Component: component, Variable: variable}})
+	require.Nil(t, err)
+	result := <-resultChannel
+	assert.True(t, result)
+	config := <-appliedC
+	assert.Equal(t, 30*time.Second, config.PingPeriod)
+	assert.Equal(t, 30*time.Second*10/9, config.PongWait)
+}
+
 func (suite *OcppV2TestSuite) TestSetVariablesInvalidEndpoint() {
 	messageId := defaultMessageId
 	attributeType := types.AttributeTarget
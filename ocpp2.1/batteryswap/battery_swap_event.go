@@ -0,0 +1,92 @@
+package batteryswap
+
+import (
+	"reflect"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+// -------------------- Battery Swap (CS -> CSMS) --------------------
+
+const BatterySwapFeatureName = "BatterySwap"
+
+// The type of a battery swap event.
+type BatterySwapEvent string
+
+const (
+	BatterySwapEventBatteryIn         BatterySwapEvent = "BatteryIn"         // Batteries were inserted.
+	BatterySwapEventBatteryOut        BatterySwapEvent = "BatteryOut"        // Batteries were taken out.
+	BatterySwapEventBatteryOutTimeout BatterySwapEvent = "BatteryOutTimeout" // Batteries were not taken out in time.
+)
+
+func isValidBatterySwapEvent(fl validator.FieldLevel) bool {
+	event := BatterySwapEvent(fl.Field().String())
+	switch event {
+	case BatterySwapEventBatteryIn, BatterySwapEventBatteryOut, BatterySwapEventBatteryOutTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// BatteryData contains information about a swapped battery.
+type BatteryData struct {
+	EvseID         int             `json:"evseId" validate:"gte=0"`                           // Slot number where battery is inserted or removed.
+	SerialNumber   string          `json:"serialNumber" validate:"required,max=50"`           // Serial number of battery.
+	SoC            float64         `json:"soC" validate:"gte=0,lte=100"`                      // State of charge.
+	SoH            float64         `json:"soH" validate:"gte=0,lte=100"`                      // State of health.
+	ProductionDate *types.DateTime `json:"productionDate,omitempty" validate:"omitempty"`     // Production date of battery.
+	VendorInfo     string          `json:"vendorInfo,omitempty" validate:"omitempty,max=500"` // Vendor-specific info from battery in undefined format.
+}
+
+// The field definition of the BatterySwap request payload sent by the Charging Station to the CSMS.
+type BatterySwapRequest struct {
+	BatteryData []BatteryData    `json:"batteryData" validate:"required,min=1,dive"`       // Info on batteries inserted or taken out.
+	EventType   BatterySwapEvent `json:"eventType" validate:"required,batterySwapEvent21"` // Battery in/out.
+	IdToken     types.IdToken    `json:"idToken" validate:"required"`                      // The identifier of the user who performs the swap.
+	RequestID   int              `json:"requestId"`                                        // RequestId to correlate BatteryIn/Out events and optional RequestBatterySwapRequest.
+}
+
+// This field definition of the BatterySwap response payload, sent by the CSMS to the Charging Station in response to a BatterySwapRequest.
+// In case the request was invalid, or couldn't be processed, an error will be sent instead.
+type BatterySwapResponse struct {
+}
+
+// Whenever batteries are inserted into or taken out of a battery swap station, the Charging Station informs the CSMS,
+// by sending a BatterySwapRequest. The CSMS responds with a BatterySwapResponse.
+type BatterySwapFeature struct{}
+
+func (f BatterySwapFeature) GetFeatureName() string {
+	return BatterySwapFeatureName
+}
+
+func (f BatterySwapFeature) GetRequestType() reflect.Type {
+	return reflect.TypeOf(BatterySwapRequest{})
+}
+
+func (f BatterySwapFeature) GetResponseType() reflect.Type {
+	return reflect.TypeOf(BatterySwapResponse{})
+}
+
+func (r BatterySwapRequest) GetFeatureName() string {
+	return BatterySwapFeatureName
+}
+
+func (c BatterySwapResponse) GetFeatureName() string {
+	return BatterySwapFeatureName
+}
+
+// Creates a new BatterySwapRequest, containing all required fields. There are no optional fields for this message.
+func NewBatterySwapRequest(batteryData []BatteryData, eventType BatterySwapEvent, idToken types.IdToken, requestID int) *BatterySwapRequest {
+	return &BatterySwapRequest{BatteryData: batteryData, EventType: eventType, IdToken: idToken, RequestID: requestID}
+}
+
+// Creates a new BatterySwapResponse, which doesn't contain any required or optional fields.
+func NewBatterySwapResponse() *BatterySwapResponse {
+	return &BatterySwapResponse{}
+}
+
+func init() {
+	_ = types.Validate.RegisterValidation("batterySwapEvent21", isValidBatterySwapEvent)
+}
@@ -0,0 +1,59 @@
+package der
+
+import (
+	"reflect"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+)
+
+// -------------------- Clear DER Control (CSMS -> CS) --------------------
+
+const ClearDERControlFeatureName = "ClearDERControl"
+
+// The field definition of the ClearDERControl request payload sent by the CSMS to the Charging Station.
+type ClearDERControlRequest struct {
+	IsDefault   bool           `json:"isDefault"`                                                   // True: clear default DER controls. False: clear scheduled controls.
+	ControlType DERControlType `json:"controlType,omitempty" validate:"omitempty,derControlType21"` // Type of control settings to clear. Not used when ControlID is provided.
+	ControlID   string         `json:"controlId,omitempty" validate:"omitempty,max=36"`             // Id of control setting to clear. When omitted, all settings for ControlType are cleared.
+}
+
+// This field definition of the ClearDERControl response payload, sent by the Charging Station to the CSMS in response to a ClearDERControlRequest.
+// In case the request was invalid, or couldn't be processed, an error will be sent instead.
+type ClearDERControlResponse struct {
+	Status     DERControlStatus  `json:"status" validate:"required,derControlStatus21"`
+	StatusInfo *types.StatusInfo `json:"statusInfo,omitempty" validate:"omitempty"`
+}
+
+// The CSMS may remove DER controls from a Charging Station, by sending a ClearDERControlRequest.
+// The Charging Station responds with a ClearDERControlResponse.
+type ClearDERControlFeature struct{}
+
+func (f ClearDERControlFeature) GetFeatureName() string {
+	return ClearDERControlFeatureName
+}
+
+func (f ClearDERControlFeature) GetRequestType() reflect.Type {
+	return reflect.TypeOf(ClearDERControlRequest{})
+}
+
+func (f ClearDERControlFeature) GetResponseType() reflect.Type {
+	return reflect.TypeOf(ClearDERControlResponse{})
+}
+
+func (r ClearDERControlRequest) GetFeatureName() string {
+	return ClearDERControlFeatureName
+}
+
+func (c ClearDERControlResponse) GetFeatureName() string {
+	return ClearDERControlFeatureName
+}
+
+// Creates a new ClearDERControlRequest, containing all required fields. Optional fields may be set afterwards.
+func NewClearDERControlRequest(isDefault bool) *ClearDERControlRequest {
+	return &ClearDERControlRequest{IsDefault: isDefault}
+}
+
+// Creates a new ClearDERControlResponse, containing all required fields. Optional fields may be set afterwards.
+func NewClearDERControlResponse(status DERControlStatus) *ClearDERControlResponse {
+	return &ClearDERControlResponse{Status: status}
+}
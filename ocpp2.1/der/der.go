@@ -0,0 +1,36 @@
+// The DER control functional block contains OCPP 2.1 features that allow the CSMS to configure distributed energy resource (DER) controls on a charging station, e.g. for bidirectional charging (V2X).
+package der
+
+import "github.com/lorenzodonini/ocpp-go/ocpp"
+
+// Needs to be implemented by a CSMS for handling messages part of the OCPP 2.1 DER control profile.
+type CSMSHandler interface {
+	// OnNotifyDERAlarm is called on the CSMS whenever a NotifyDERAlarmRequest is received from a charging station.
+	OnNotifyDERAlarm(chargingStationID string, request *NotifyDERAlarmRequest) (response *NotifyDERAlarmResponse, err error)
+	// OnNotifyDERStartStop is called on the CSMS whenever a NotifyDERStartStopRequest is received from a charging station.
+	OnNotifyDERStartStop(chargingStationID string, request *NotifyDERStartStopRequest) (response *NotifyDERStartStopResponse, err error)
+	// OnReportDERControl is called on the CSMS whenever a ReportDERControlRequest is received from a charging station.
+	OnReportDERControl(chargingStationID string, request *ReportDERControlRequest) (response *ReportDERControlResponse, err error)
+}
+
+// Needs to be implemented by Charging stations for handling messages part of the OCPP 2.1 DER control profile.
+type ChargingStationHandler interface {
+	// OnClearDERControl is called on a charging station whenever a ClearDERControlRequest is received from the CSMS.
+	OnClearDERControl(request *ClearDERControlRequest) (response *ClearDERControlResponse, err error)
+	// OnGetDERControl is called on a charging station whenever a GetDERControlRequest is received from the CSMS.
+	OnGetDERControl(request *GetDERControlRequest) (response *GetDERControlResponse, err error)
+	// OnSetDERControl is called on a charging station whenever a SetDERControlRequest is received from the CSMS.
+	OnSetDERControl(request *SetDERControlRequest) (response *SetDERControlResponse, err error)
+}
+
+const ProfileName = "DERControl"
+
+var Profile = ocpp.NewProfile(
+	ProfileName,
+	ClearDERControlFeature{},
+	GetDERControlFeature{},
+	NotifyDERAlarmFeature{},
+	NotifyDERStartStopFeature{},
+	ReportDERControlFeature{},
+	SetDERControlFeature{},
+)
@@ -0,0 +1,61 @@
+package der
+
+import (
+	"reflect"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+)
+
+// -------------------- Get DER Control (CSMS -> CS) --------------------
+
+const GetDERControlFeatureName = "GetDERControl"
+
+// The field definition of the GetDERControl request payload sent by the CSMS to the Charging Station.
+type GetDERControlRequest struct {
+	RequestID   int            `json:"requestId"`                                                   // RequestId to be used in ReportDERControlRequest.
+	IsDefault   *bool          `json:"isDefault,omitempty" validate:"omitempty"`                    // True: get a default DER control. False: get a scheduled control.
+	ControlType DERControlType `json:"controlType,omitempty" validate:"omitempty,derControlType21"` // Type of control settings to retrieve. Not used when ControlID is provided.
+	ControlID   string         `json:"controlId,omitempty" validate:"omitempty,max=36"`             // Id of setting to get. When omitted, all settings for ControlType are retrieved.
+}
+
+// This field definition of the GetDERControl response payload, sent by the Charging Station to the CSMS in response to a GetDERControlRequest.
+// In case the request was invalid, or couldn't be processed, an error will be sent instead.
+type GetDERControlResponse struct {
+	Status     DERControlStatus  `json:"status" validate:"required,derControlStatus21"`
+	StatusInfo *types.StatusInfo `json:"statusInfo,omitempty" validate:"omitempty"`
+}
+
+// The CSMS may request the DER controls configured on a Charging Station, by sending a GetDERControlRequest.
+// The Charging Station responds with a GetDERControlResponse, then asynchronously sends the requested controls
+// via one or more ReportDERControlRequest messages.
+type GetDERControlFeature struct{}
+
+func (f GetDERControlFeature) GetFeatureName() string {
+	return GetDERControlFeatureName
+}
+
+func (f GetDERControlFeature) GetRequestType() reflect.Type {
+	return reflect.TypeOf(GetDERControlRequest{})
+}
+
+func (f GetDERControlFeature) GetResponseType() reflect.Type {
+	return reflect.TypeOf(GetDERControlResponse{})
+}
+
+func (r GetDERControlRequest) GetFeatureName() string {
+	return GetDERControlFeatureName
+}
+
+func (c GetDERControlResponse) GetFeatureName() string {
+	return GetDERControlFeatureName
+}
+
+// Creates a new GetDERControlRequest, containing all required fields. Optional fields may be set afterwards.
+func NewGetDERControlRequest(requestID int) *GetDERControlRequest {
+	return &GetDERControlRequest{RequestID: requestID}
+}
+
+// Creates a new GetDERControlResponse, containing all required fields. Optional fields may be set afterwards.
+func NewGetDERControlResponse(status DERControlStatus) *GetDERControlResponse {
+	return &GetDERControlResponse{Status: status}
+}
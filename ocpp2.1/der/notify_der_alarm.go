@@ -0,0 +1,93 @@
+package der
+
+import (
+	"reflect"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+// -------------------- Notify DER Alarm (CS -> CSMS) --------------------
+
+const NotifyDERAlarmFeatureName = "NotifyDERAlarm"
+
+// The type of grid event, which caused a DER alarm.
+type GridEventFault string
+
+const (
+	GridEventFaultCurrentImbalance GridEventFault = "CurrentImbalance"
+	GridEventFaultLocalEmergency   GridEventFault = "LocalEmergency"
+	GridEventFaultLowInputPower    GridEventFault = "LowInputPower"
+	GridEventFaultOverCurrent      GridEventFault = "OverCurrent"
+	GridEventFaultOverFrequency    GridEventFault = "OverFrequency"
+	GridEventFaultOverVoltage      GridEventFault = "OverVoltage"
+	GridEventFaultPhaseRotation    GridEventFault = "PhaseRotation"
+	GridEventFaultRemoteEmergency  GridEventFault = "RemoteEmergency"
+	GridEventFaultUnderFrequency   GridEventFault = "UnderFrequency"
+	GridEventFaultUnderVoltage     GridEventFault = "UnderVoltage"
+	GridEventFaultVoltageImbalance GridEventFault = "VoltageImbalance"
+)
+
+func isValidGridEventFault(fl validator.FieldLevel) bool {
+	fault := GridEventFault(fl.Field().String())
+	switch fault {
+	case GridEventFaultCurrentImbalance, GridEventFaultLocalEmergency, GridEventFaultLowInputPower, GridEventFaultOverCurrent,
+		GridEventFaultOverFrequency, GridEventFaultOverVoltage, GridEventFaultPhaseRotation, GridEventFaultRemoteEmergency,
+		GridEventFaultUnderFrequency, GridEventFaultUnderVoltage, GridEventFaultVoltageImbalance:
+		return true
+	default:
+		return false
+	}
+}
+
+// The field definition of the NotifyDERAlarm request payload sent by the Charging Station to the CSMS.
+type NotifyDERAlarmRequest struct {
+	ControlType    DERControlType `json:"controlType" validate:"required,derControlType21"`               // Name of DER control, e.g. LFMustTrip.
+	GridEventFault GridEventFault `json:"gridEventFault,omitempty" validate:"omitempty,gridEventFault21"` // Type of grid event that caused this alarm.
+	AlarmEnded     bool           `json:"alarmEnded,omitempty" validate:"omitempty"`                      // True when the alarm has ended. Default value when omitted: false.
+	Timestamp      types.DateTime `json:"timestamp" validate:"required"`                                  // Time of start or end of alarm.
+	ExtraInfo      string         `json:"extraInfo,omitempty" validate:"omitempty,max=200"`               // Optional info provided by EV.
+}
+
+// This field definition of the NotifyDERAlarm response payload, sent by the CSMS to the Charging Station in response to a NotifyDERAlarmRequest.
+// In case the request was invalid, or couldn't be processed, an error will be sent instead.
+type NotifyDERAlarmResponse struct {
+}
+
+// When a DER control causes an alarm, e.g. because the EV had to trip due to a grid event,
+// the Charging Station notifies the CSMS by sending a NotifyDERAlarmRequest. The CSMS responds with a NotifyDERAlarmResponse.
+type NotifyDERAlarmFeature struct{}
+
+func (f NotifyDERAlarmFeature) GetFeatureName() string {
+	return NotifyDERAlarmFeatureName
+}
+
+func (f NotifyDERAlarmFeature) GetRequestType() reflect.Type {
+	return reflect.TypeOf(NotifyDERAlarmRequest{})
+}
+
+func (f NotifyDERAlarmFeature) GetResponseType() reflect.Type {
+	return reflect.TypeOf(NotifyDERAlarmResponse{})
+}
+
+func (r NotifyDERAlarmRequest) GetFeatureName() string {
+	return NotifyDERAlarmFeatureName
+}
+
+func (c NotifyDERAlarmResponse) GetFeatureName() string {
+	return NotifyDERAlarmFeatureName
+}
+
+// Creates a new NotifyDERAlarmRequest, containing all required fields. Optional fields may be set afterwards.
+func NewNotifyDERAlarmRequest(controlType DERControlType, timestamp types.DateTime) *NotifyDERAlarmRequest {
+	return &NotifyDERAlarmRequest{ControlType: controlType, Timestamp: timestamp}
+}
+
+// Creates a new NotifyDERAlarmResponse, which doesn't contain any required or optional fields.
+func NewNotifyDERAlarmResponse() *NotifyDERAlarmResponse {
+	return &NotifyDERAlarmResponse{}
+}
+
+func init() {
+	_ = types.Validate.RegisterValidation("gridEventFault21", isValidGridEventFault)
+}
@@ -0,0 +1,62 @@
+package der
+
+import (
+	"reflect"
+)
+
+// -------------------- Report DER Control (CS -> CSMS) --------------------
+
+const ReportDERControlFeatureName = "ReportDERControl"
+
+// The field definition of the ReportDERControl request payload sent by the Charging Station to the CSMS.
+type ReportDERControlRequest struct {
+	RequestID         int                    `json:"requestId"`                                        // RequestId from GetDERControlRequest.
+	Curve             []DERCurveGet          `json:"curve,omitempty" validate:"omitempty,max=24,dive"` // Installed curves.
+	EnterService      []EnterServiceGet      `json:"enterService,omitempty" validate:"omitempty,max=24,dive"`
+	FixedPFAbsorb     []FixedPFGet           `json:"fixedPFAbsorb,omitempty" validate:"omitempty,max=24,dive"`
+	FixedPFInject     []FixedPFGet           `json:"fixedPFInject,omitempty" validate:"omitempty,max=24,dive"`
+	FixedVar          []FixedVarGet          `json:"fixedVar,omitempty" validate:"omitempty,max=24,dive"`
+	FreqDroop         []FreqDroopGet         `json:"freqDroop,omitempty" validate:"omitempty,max=24,dive"`
+	Gradient          []GradientGet          `json:"gradient,omitempty" validate:"omitempty,max=24,dive"`
+	LimitMaxDischarge []LimitMaxDischargeGet `json:"limitMaxDischarge,omitempty" validate:"omitempty,max=24,dive"`
+	Tbc               bool                   `json:"tbc,omitempty" validate:"omitempty"` // To Be Continued. Default value when omitted: false. False indicates that there are no further messages as part of this report.
+}
+
+// This field definition of the ReportDERControl response payload, sent by the CSMS to the Charging Station in response to a ReportDERControlRequest.
+// In case the request was invalid, or couldn't be processed, an error will be sent instead.
+type ReportDERControlResponse struct {
+}
+
+// After accepting a GetDERControlRequest, the Charging Station reports the requested DER controls to the CSMS,
+// by sending one or more ReportDERControlRequest messages. The CSMS responds to each of them with a ReportDERControlResponse.
+type ReportDERControlFeature struct{}
+
+func (f ReportDERControlFeature) GetFeatureName() string {
+	return ReportDERControlFeatureName
+}
+
+func (f ReportDERControlFeature) GetRequestType() reflect.Type {
+	return reflect.TypeOf(ReportDERControlRequest{})
+}
+
+func (f ReportDERControlFeature) GetResponseType() reflect.Type {
+	return reflect.TypeOf(ReportDERControlResponse{})
+}
+
+func (r ReportDERControlRequest) GetFeatureName() string {
+	return ReportDERControlFeatureName
+}
+
+func (c ReportDERControlResponse) GetFeatureName() string {
+	return ReportDERControlFeatureName
+}
+
+// Creates a new ReportDERControlRequest, containing all required fields. Optional fields may be set afterwards.
+func NewReportDERControlRequest(requestID int) *ReportDERControlRequest {
+	return &ReportDERControlRequest{RequestID: requestID}
+}
+
+// Creates a new ReportDERControlResponse, which doesn't contain any required or optional fields.
+func NewReportDERControlResponse() *ReportDERControlResponse {
+	return &ReportDERControlResponse{}
+}
@@ -0,0 +1,69 @@
+package der
+
+import (
+	"reflect"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+)
+
+// -------------------- Set DER Control (CSMS -> CS) --------------------
+
+const SetDERControlFeatureName = "SetDERControl"
+
+// The field definition of the SetDERControl request payload sent by the CSMS to the Charging Station.
+// Exactly one of the control parameters must be set, matching the ControlType.
+type SetDERControlRequest struct {
+	IsDefault         bool               `json:"isDefault"`                                        // True if this is a default DER control.
+	ControlID         string             `json:"controlId" validate:"required,max=36"`             // Unique id of this control, e.g. a UUID.
+	ControlType       DERControlType     `json:"controlType" validate:"required,derControlType21"` // Type of control. Determines which setting field below is used.
+	Curve             *DERCurve          `json:"curve,omitempty" validate:"omitempty"`             // Curve parameters for curve-based controls.
+	EnterService      *EnterService      `json:"enterService,omitempty" validate:"omitempty"`
+	FixedPFAbsorb     *FixedPF           `json:"fixedPFAbsorb,omitempty" validate:"omitempty"`
+	FixedPFInject     *FixedPF           `json:"fixedPFInject,omitempty" validate:"omitempty"`
+	FixedVar          *FixedVar          `json:"fixedVar,omitempty" validate:"omitempty"`
+	FreqDroop         *FreqDroop         `json:"freqDroop,omitempty" validate:"omitempty"`
+	Gradient          *Gradient          `json:"gradient,omitempty" validate:"omitempty"`
+	LimitMaxDischarge *LimitMaxDischarge `json:"limitMaxDischarge,omitempty" validate:"omitempty"`
+}
+
+// This field definition of the SetDERControl response payload, sent by the Charging Station to the CSMS in response to a SetDERControlRequest.
+// In case the request was invalid, or couldn't be processed, an error will be sent instead.
+type SetDERControlResponse struct {
+	Status        DERControlStatus  `json:"status" validate:"required,derControlStatus21"`
+	SupersededIDs []string          `json:"supersededIds,omitempty" validate:"omitempty,max=24,dive,max=36"` // List of controlIds that are superseded as a result of setting this control.
+	StatusInfo    *types.StatusInfo `json:"statusInfo,omitempty" validate:"omitempty"`
+}
+
+// The CSMS may configure a DER control on a Charging Station, e.g. to manage the behavior of the EV during bidirectional charging,
+// by sending a SetDERControlRequest. The Charging Station responds with a SetDERControlResponse.
+type SetDERControlFeature struct{}
+
+func (f SetDERControlFeature) GetFeatureName() string {
+	return SetDERControlFeatureName
+}
+
+func (f SetDERControlFeature) GetRequestType() reflect.Type {
+	return reflect.TypeOf(SetDERControlRequest{})
+}
+
+func (f SetDERControlFeature) GetResponseType() reflect.Type {
+	return reflect.TypeOf(SetDERControlResponse{})
+}
+
+func (r SetDERControlRequest) GetFeatureName() string {
+	return SetDERControlFeatureName
+}
+
+func (c SetDERControlResponse) GetFeatureName() string {
+	return SetDERControlFeatureName
+}
+
+// Creates a new SetDERControlRequest, containing all required fields. The control parameters must be set afterwards.
+func NewSetDERControlRequest(isDefault bool, controlID string, controlType DERControlType) *SetDERControlRequest {
+	return &SetDERControlRequest{IsDefault: isDefault, ControlID: controlID, ControlType: controlType}
+}
+
+// Creates a new SetDERControlResponse, containing all required fields. Optional fields may be set afterwards.
+func NewSetDERControlResponse(status DERControlStatus) *SetDERControlResponse {
+	return &SetDERControlResponse{Status: status}
+}
@@ -0,0 +1,223 @@
+package der
+
+import (
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+// The type of a DER control.
+type DERControlType string
+
+const (
+	DERControlEnterService            DERControlType = "EnterService"
+	DERControlFreqDroop               DERControlType = "FreqDroop"
+	DERControlFreqWatt                DERControlType = "FreqWatt"
+	DERControlFixedPFAbsorb           DERControlType = "FixedPFAbsorb"
+	DERControlFixedPFInject           DERControlType = "FixedPFInject"
+	DERControlFixedVar                DERControlType = "FixedVar"
+	DERControlGradients               DERControlType = "Gradients"
+	DERControlHFMustTrip              DERControlType = "HFMustTrip"
+	DERControlHFMayTrip               DERControlType = "HFMayTrip"
+	DERControlHVMustTrip              DERControlType = "HVMustTrip"
+	DERControlHVMomCess               DERControlType = "HVMomCess"
+	DERControlHVMayTrip               DERControlType = "HVMayTrip"
+	DERControlLimitMaxDischarge       DERControlType = "LimitMaxDischarge"
+	DERControlLFMustTrip              DERControlType = "LFMustTrip"
+	DERControlLVMustTrip              DERControlType = "LVMustTrip"
+	DERControlLVMomCess               DERControlType = "LVMomCess"
+	DERControlLVMayTrip               DERControlType = "LVMayTrip"
+	DERControlPowerMonitoringMustTrip DERControlType = "PowerMonitoringMustTrip"
+	DERControlVoltVar                 DERControlType = "VoltVar"
+	DERControlVoltWatt                DERControlType = "VoltWatt"
+	DERControlWattPF                  DERControlType = "WattPF"
+	DERControlWattVar                 DERControlType = "WattVar"
+)
+
+func isValidDERControlType(fl validator.FieldLevel) bool {
+	control := DERControlType(fl.Field().String())
+	switch control {
+	case DERControlEnterService, DERControlFreqDroop, DERControlFreqWatt, DERControlFixedPFAbsorb, DERControlFixedPFInject,
+		DERControlFixedVar, DERControlGradients, DERControlHFMustTrip, DERControlHFMayTrip, DERControlHVMustTrip,
+		DERControlHVMomCess, DERControlHVMayTrip, DERControlLimitMaxDischarge, DERControlLFMustTrip, DERControlLVMustTrip,
+		DERControlLVMomCess, DERControlLVMayTrip, DERControlPowerMonitoringMustTrip, DERControlVoltVar, DERControlVoltWatt,
+		DERControlWattPF, DERControlWattVar:
+		return true
+	default:
+		return false
+	}
+}
+
+// Status returned by DER control messages.
+type DERControlStatus string
+
+const (
+	DERControlStatusAccepted     DERControlStatus = "Accepted"
+	DERControlStatusRejected     DERControlStatus = "Rejected"
+	DERControlStatusNotSupported DERControlStatus = "NotSupported"
+	DERControlStatusNotFound     DERControlStatus = "NotFound"
+)
+
+func isValidDERControlStatus(fl validator.FieldLevel) bool {
+	status := DERControlStatus(fl.Field().String())
+	switch status {
+	case DERControlStatusAccepted, DERControlStatusRejected, DERControlStatusNotSupported, DERControlStatusNotFound:
+		return true
+	default:
+		return false
+	}
+}
+
+// Unit of the y-axis of a DER curve, or of a setpoint.
+type DERUnit string
+
+const (
+	DERUnitNotApplicable DERUnit = "Not_Applicable"
+	DERUnitPctMaxW       DERUnit = "PctMaxW"
+	DERUnitPctMaxVar     DERUnit = "PctMaxVar"
+	DERUnitPctWAvail     DERUnit = "PctWAvail"
+	DERUnitPctVarAvail   DERUnit = "PctVarAvail"
+	DERUnitPctEffectiveV DERUnit = "PctEffectiveV"
+)
+
+func isValidDERUnit(fl validator.FieldLevel) bool {
+	unit := DERUnit(fl.Field().String())
+	switch unit {
+	case DERUnitNotApplicable, DERUnitPctMaxW, DERUnitPctMaxVar, DERUnitPctWAvail, DERUnitPctVarAvail, DERUnitPctEffectiveV:
+		return true
+	default:
+		return false
+	}
+}
+
+// A single point of a DER curve.
+type DERCurvePoint struct {
+	X float64 `json:"x"` // The data value of the X-axis (independent) variable, depending on the curve type.
+	Y float64 `json:"y"` // The data value of the Y-axis (dependent) variable, depending on the unit of the curve.
+}
+
+// DERCurve defines a curve for a curve-based DER control, e.g. VoltVar or a trip curve.
+type DERCurve struct {
+	CurveData    []DERCurvePoint `json:"curveData" validate:"required,min=1,max=10,dive"` // Coordinates of the curve.
+	Priority     int             `json:"priority" validate:"gte=0"`                       // Priority of the setting (0 = highest).
+	YUnit        DERUnit         `json:"yUnit" validate:"required,derUnit21"`             // Unit of the Y-axis of the curve.
+	ResponseTime *float64        `json:"responseTime,omitempty" validate:"omitempty"`     // Open loop response time, in seconds.
+	StartTime    *types.DateTime `json:"startTime,omitempty" validate:"omitempty"`        // Point in time when this curve will become activated.
+	Duration     *float64        `json:"duration,omitempty" validate:"omitempty"`         // Duration in seconds that this curve will be active.
+}
+
+// EnterService contains the parameters for the EnterService DER control.
+type EnterService struct {
+	Priority    int      `json:"priority" validate:"gte=0"`                  // Priority of the setting (0 = highest).
+	HighVoltage float64  `json:"highVoltage"`                                // Enter service voltage high.
+	LowVoltage  float64  `json:"lowVoltage"`                                 // Enter service voltage low.
+	HighFreq    float64  `json:"highFreq"`                                   // Enter service frequency high.
+	LowFreq     float64  `json:"lowFreq"`                                    // Enter service frequency low.
+	Delay       *float64 `json:"delay,omitempty" validate:"omitempty"`       // Enter service delay, in seconds.
+	RandomDelay *float64 `json:"randomDelay,omitempty" validate:"omitempty"` // Enter service randomized delay, in seconds.
+	RampRate    *float64 `json:"rampRate,omitempty" validate:"omitempty"`    // Enter service ramp rate, in seconds.
+}
+
+// FixedPF contains the parameters for the FixedPFAbsorb and FixedPFInject DER controls.
+type FixedPF struct {
+	Priority     int             `json:"priority" validate:"gte=0"`                // Priority of the setting (0 = highest).
+	Displacement float64         `json:"displacement"`                             // Power factor, cos(phi), as value between 0 and 1.
+	Excitation   bool            `json:"excitation"`                               // True when absorbing reactive power (under-excited), false when injecting reactive power (over-excited).
+	StartTime    *types.DateTime `json:"startTime,omitempty" validate:"omitempty"` // Time when this setting becomes active.
+	Duration     *float64        `json:"duration,omitempty" validate:"omitempty"`  // Duration in seconds that this setting is active.
+}
+
+// FixedVar contains the parameters for the FixedVar DER control.
+type FixedVar struct {
+	Priority  int             `json:"priority" validate:"gte=0"`                // Priority of the setting (0 = highest).
+	Setpoint  float64         `json:"setpoint"`                                 // The value specifies a target var output interpreted as a signed percentage (-100 to 100).
+	Unit      DERUnit         `json:"unit" validate:"required,derUnit21"`       // Unit of the setpoint.
+	StartTime *types.DateTime `json:"startTime,omitempty" validate:"omitempty"` // Time when this setting becomes active.
+	Duration  *float64        `json:"duration,omitempty" validate:"omitempty"`  // Duration in seconds that this setting is active.
+}
+
+// FreqDroop contains the parameters for the FreqDroop DER control.
+type FreqDroop struct {
+	Priority     int             `json:"priority" validate:"gte=0"`                // Priority of the setting (0 = highest).
+	OverFreq     float64         `json:"overFreq"`                                 // Over-frequency start of droop.
+	UnderFreq    float64         `json:"underFreq"`                                // Under-frequency start of droop.
+	OverDroop    float64         `json:"overDroop"`                                // Over-frequency droop per unit, oFDroop.
+	UnderDroop   float64         `json:"underDroop"`                               // Under-frequency droop per unit, uFDroop.
+	ResponseTime float64         `json:"responseTime"`                             // Open loop response time, in seconds.
+	StartTime    *types.DateTime `json:"startTime,omitempty" validate:"omitempty"` // Time when this setting becomes active.
+	Duration     *float64        `json:"duration,omitempty" validate:"omitempty"`  // Duration in seconds that this setting is active.
+}
+
+// Gradient contains the parameters for the Gradients DER control.
+type Gradient struct {
+	Priority     int     `json:"priority" validate:"gte=0"` // Priority of the setting (0 = highest).
+	Gradient     float64 `json:"gradient"`                  // Default ramp rate in seconds (0 if not applicable).
+	SoftGradient float64 `json:"softGradient"`              // Soft-start ramp rate in seconds (0 if not applicable).
+}
+
+// LimitMaxDischarge contains the parameters for the LimitMaxDischarge DER control.
+type LimitMaxDischarge struct {
+	Priority                int             `json:"priority" validate:"gte=0"`                              // Priority of the setting (0 = highest).
+	PctMaxDischargePower    *float64        `json:"pctMaxDischargePower,omitempty" validate:"omitempty"`    // Only for PowerMonitoring. The value specifies a percentage (0 to 100) of the rated maximum discharge power of EV.
+	PowerMonitoringMustTrip *DERCurve       `json:"powerMonitoringMustTrip,omitempty" validate:"omitempty"` // The curve is an interpolation of data points, where the x-axis values are time in seconds and the y-axis values refer to the percentage value of the rated maximum discharge power.
+	StartTime               *types.DateTime `json:"startTime,omitempty" validate:"omitempty"`               // Time when this setting becomes active.
+	Duration                *float64        `json:"duration,omitempty" validate:"omitempty"`                // Duration in seconds that this setting is active.
+}
+
+// DERCurveGet reports a DER curve installed on the Charging Station.
+type DERCurveGet struct {
+	ID           string         `json:"id" validate:"required,max=36"`                  // Id of DER control.
+	CurveType    DERControlType `json:"curveType" validate:"required,derControlType21"` // Type of DER curve.
+	IsDefault    bool           `json:"isDefault"`                                      // True if this is a default curve.
+	IsSuperseded bool           `json:"isSuperseded"`                                   // True if this setting is superseded by a higher priority setting.
+	Curve        DERCurve       `json:"curve" validate:"required"`                      // The curve.
+}
+
+// EnterServiceGet reports an EnterService setting installed on the Charging Station.
+type EnterServiceGet struct {
+	ID           string       `json:"id" validate:"required,max=36"`
+	EnterService EnterService `json:"enterService" validate:"required"`
+}
+
+// FixedPFGet reports a FixedPF setting installed on the Charging Station.
+type FixedPFGet struct {
+	ID           string  `json:"id" validate:"required,max=36"`
+	IsDefault    bool    `json:"isDefault"`
+	IsSuperseded bool    `json:"isSuperseded"`
+	FixedPF      FixedPF `json:"fixedPF" validate:"required"`
+}
+
+// FixedVarGet reports a FixedVar setting installed on the Charging Station.
+type FixedVarGet struct {
+	ID           string   `json:"id" validate:"required,max=36"`
+	IsDefault    bool     `json:"isDefault"`
+	IsSuperseded bool     `json:"isSuperseded"`
+	FixedVar     FixedVar `json:"fixedVar" validate:"required"`
+}
+
+// FreqDroopGet reports a FreqDroop setting installed on the Charging Station.
+type FreqDroopGet struct {
+	ID           string    `json:"id" validate:"required,max=36"`
+	IsDefault    bool      `json:"isDefault"`
+	IsSuperseded bool      `json:"isSuperseded"`
+	FreqDroop    FreqDroop `json:"freqDroop" validate:"required"`
+}
+
+// GradientGet reports a Gradient setting installed on the Charging Station.
+type GradientGet struct {
+	ID       string   `json:"id" validate:"required,max=36"`
+	Gradient Gradient `json:"gradient" validate:"required"`
+}
+
+// LimitMaxDischargeGet reports a LimitMaxDischarge setting installed on the Charging Station.
+type LimitMaxDischargeGet struct {
+	ID                string            `json:"id" validate:"required,max=36"`
+	IsDefault         bool              `json:"isDefault"`
+	IsSuperseded      bool              `json:"isSuperseded"`
+	LimitMaxDischarge LimitMaxDischarge `json:"limitMaxDischarge" validate:"required"`
+}
+
+func init() {
+	_ = types.Validate.RegisterValidation("derControlType21", isValidDERControlType)
+	_ = types.Validate.RegisterValidation("derControlStatus21", isValidDERControlStatus)
+	_ = types.Validate.RegisterValidation("derUnit21", isValidDERUnit)
+}
@@ -0,0 +1,58 @@
+package diagnostics
+
+import (
+	"reflect"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+)
+
+// -------------------- Adjust Periodic Event Stream (CSMS -> CS) --------------------
+
+const AdjustPeriodicEventStreamFeatureName = "AdjustPeriodicEventStream"
+
+// The field definition of the AdjustPeriodicEventStream request payload sent by the CSMS to the Charging Station.
+type AdjustPeriodicEventStreamRequest struct {
+	ID     int                       `json:"id" validate:"gte=0"`        // Id of the stream to adjust.
+	Params PeriodicEventStreamParams `json:"params" validate:"required"` // New parameters of the stream.
+}
+
+// This field definition of the AdjustPeriodicEventStream response payload, sent by the Charging Station to the CSMS in response to a AdjustPeriodicEventStreamRequest.
+// In case the request was invalid, or couldn't be processed, an error will be sent instead.
+type AdjustPeriodicEventStreamResponse struct {
+	Status     types.GenericStatus `json:"status" validate:"required,genericStatus21"`
+	StatusInfo *types.StatusInfo   `json:"statusInfo,omitempty" validate:"omitempty"`
+}
+
+// The CSMS may change the interval and the number of values sent together in an open periodic event stream,
+// by sending an AdjustPeriodicEventStreamRequest. The Charging Station responds with an AdjustPeriodicEventStreamResponse.
+type AdjustPeriodicEventStreamFeature struct{}
+
+func (f AdjustPeriodicEventStreamFeature) GetFeatureName() string {
+	return AdjustPeriodicEventStreamFeatureName
+}
+
+func (f AdjustPeriodicEventStreamFeature) GetRequestType() reflect.Type {
+	return reflect.TypeOf(AdjustPeriodicEventStreamRequest{})
+}
+
+func (f AdjustPeriodicEventStreamFeature) GetResponseType() reflect.Type {
+	return reflect.TypeOf(AdjustPeriodicEventStreamResponse{})
+}
+
+func (r AdjustPeriodicEventStreamRequest) GetFeatureName() string {
+	return AdjustPeriodicEventStreamFeatureName
+}
+
+func (c AdjustPeriodicEventStreamResponse) GetFeatureName() string {
+	return AdjustPeriodicEventStreamFeatureName
+}
+
+// Creates a new AdjustPeriodicEventStreamRequest, containing all required fields. There are no optional fields for this message.
+func NewAdjustPeriodicEventStreamRequest(id int, params PeriodicEventStreamParams) *AdjustPeriodicEventStreamRequest {
+	return &AdjustPeriodicEventStreamRequest{ID: id, Params: params}
+}
+
+// Creates a new AdjustPeriodicEventStreamResponse, containing all required fields. Optional fields may be set afterwards.
+func NewAdjustPeriodicEventStreamResponse(status types.GenericStatus) *AdjustPeriodicEventStreamResponse {
+	return &AdjustPeriodicEventStreamResponse{Status: status}
+}
@@ -0,0 +1,54 @@
+package diagnostics
+
+import (
+	"reflect"
+)
+
+// -------------------- Close Periodic Event Stream (CS -> CSMS) --------------------
+
+const ClosePeriodicEventStreamFeatureName = "ClosePeriodicEventStream"
+
+// The field definition of the ClosePeriodicEventStream request payload sent by the Charging Station to the CSMS.
+type ClosePeriodicEventStreamRequest struct {
+	ID int `json:"id" validate:"gte=0"` // Id of stream to close.
+}
+
+// This field definition of the ClosePeriodicEventStream response payload, sent by the CSMS to the Charging Station in response to a ClosePeriodicEventStreamRequest.
+// In case the request was invalid, or couldn't be processed, an error will be sent instead.
+type ClosePeriodicEventStreamResponse struct {
+}
+
+// When a periodic event stream is no longer needed, e.g. because the monitor was cleared,
+// the Charging Station closes it by sending a ClosePeriodicEventStreamRequest to the CSMS.
+// The CSMS responds with a ClosePeriodicEventStreamResponse.
+type ClosePeriodicEventStreamFeature struct{}
+
+func (f ClosePeriodicEventStreamFeature) GetFeatureName() string {
+	return ClosePeriodicEventStreamFeatureName
+}
+
+func (f ClosePeriodicEventStreamFeature) GetRequestType() reflect.Type {
+	return reflect.TypeOf(ClosePeriodicEventStreamRequest{})
+}
+
+func (f ClosePeriodicEventStreamFeature) GetResponseType() reflect.Type {
+	return reflect.TypeOf(ClosePeriodicEventStreamResponse{})
+}
+
+func (r ClosePeriodicEventStreamRequest) GetFeatureName() string {
+	return ClosePeriodicEventStreamFeatureName
+}
+
+func (c ClosePeriodicEventStreamResponse) GetFeatureName() string {
+	return ClosePeriodicEventStreamFeatureName
+}
+
+// Creates a new ClosePeriodicEventStreamRequest, containing all required fields. There are no optional fields for this message.
+func NewClosePeriodicEventStreamRequest(id int) *ClosePeriodicEventStreamRequest {
+	return &ClosePeriodicEventStreamRequest{ID: id}
+}
+
+// Creates a new ClosePeriodicEventStreamResponse, which doesn't contain any required or optional fields.
+func NewClosePeriodicEventStreamResponse() *ClosePeriodicEventStreamResponse {
+	return &ClosePeriodicEventStreamResponse{}
+}
@@ -0,0 +1,53 @@
+package diagnostics
+
+import (
+	"reflect"
+)
+
+// -------------------- Get Periodic Event Stream (CSMS -> CS) --------------------
+
+const GetPeriodicEventStreamFeatureName = "GetPeriodicEventStream"
+
+// The field definition of the GetPeriodicEventStream request payload sent by the CSMS to the Charging Station.
+type GetPeriodicEventStreamRequest struct {
+}
+
+// This field definition of the GetPeriodicEventStream response payload, sent by the Charging Station to the CSMS in response to a GetPeriodicEventStreamRequest.
+// In case the request was invalid, or couldn't be processed, an error will be sent instead.
+type GetPeriodicEventStreamResponse struct {
+	ConstantStreamData []ConstantStreamData `json:"constantStreamData,omitempty" validate:"omitempty,dive"` // List of open periodic event streams.
+}
+
+// The CSMS may request the list of periodic event streams currently open on a Charging Station,
+// by sending a GetPeriodicEventStreamRequest. The Charging Station responds with a GetPeriodicEventStreamResponse.
+type GetPeriodicEventStreamFeature struct{}
+
+func (f GetPeriodicEventStreamFeature) GetFeatureName() string {
+	return GetPeriodicEventStreamFeatureName
+}
+
+func (f GetPeriodicEventStreamFeature) GetRequestType() reflect.Type {
+	return reflect.TypeOf(GetPeriodicEventStreamRequest{})
+}
+
+func (f GetPeriodicEventStreamFeature) GetResponseType() reflect.Type {
+	return reflect.TypeOf(GetPeriodicEventStreamResponse{})
+}
+
+func (r GetPeriodicEventStreamRequest) GetFeatureName() string {
+	return GetPeriodicEventStreamFeatureName
+}
+
+func (c GetPeriodicEventStreamResponse) GetFeatureName() string {
+	return GetPeriodicEventStreamFeatureName
+}
+
+// Creates a new GetPeriodicEventStreamRequest, which doesn't contain any required or optional fields.
+func NewGetPeriodicEventStreamRequest() *GetPeriodicEventStreamRequest {
+	return &GetPeriodicEventStreamRequest{}
+}
+
+// Creates a new GetPeriodicEventStreamResponse, which doesn't contain any required fields. Optional fields may be set afterwards.
+func NewGetPeriodicEventStreamResponse() *GetPeriodicEventStreamResponse {
+	return &GetPeriodicEventStreamResponse{}
+}
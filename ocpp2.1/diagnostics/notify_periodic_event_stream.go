@@ -0,0 +1,54 @@
+package diagnostics
+
+import (
+	"reflect"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+)
+
+// -------------------- Notify Periodic Event Stream (CS -> CSMS) --------------------
+
+const NotifyPeriodicEventStreamFeatureName = "NotifyPeriodicEventStream"
+
+// A single value of a periodic event stream.
+type StreamDataElement struct {
+	T float64 `json:"t"`                              // Offset relative to the basetime of the message, in seconds.
+	V string  `json:"v" validate:"required,max=2500"` // The monitored value.
+}
+
+// The field definition of the NotifyPeriodicEventStream request payload sent by the Charging Station to the CSMS.
+type NotifyPeriodicEventStreamRequest struct {
+	ID       int                 `json:"id" validate:"gte=0"`                 // Id of the stream, as opened via OpenPeriodicEventStreamRequest.
+	Pending  int                 `json:"pending" validate:"gte=0"`            // Number of data elements still pending to be sent.
+	Basetime types.DateTime      `json:"basetime" validate:"required"`        // Base timestamp to add to the time offsets of the data elements.
+	Data     []StreamDataElement `json:"data" validate:"required,min=1,dive"` // The stream data.
+}
+
+// Monitors with a periodic event stream report their values via NotifyPeriodicEventStreamRequest messages,
+// after the Charging Station opened the stream via OpenPeriodicEventStreamRequest.
+//
+// NotifyPeriodicEventStream is an unconfirmed message, sent via the SEND message type introduced in OCPP 2.1:
+// the CSMS never replies to it, hence the feature has no response type.
+type NotifyPeriodicEventStreamFeature struct{}
+
+func (f NotifyPeriodicEventStreamFeature) GetFeatureName() string {
+	return NotifyPeriodicEventStreamFeatureName
+}
+
+func (f NotifyPeriodicEventStreamFeature) GetRequestType() reflect.Type {
+	return reflect.TypeOf(NotifyPeriodicEventStreamRequest{})
+}
+
+// GetResponseType returns nil, since NotifyPeriodicEventStream messages are never replied to.
+func (f NotifyPeriodicEventStreamFeature) GetResponseType() reflect.Type {
+	return nil
+}
+
+func (r NotifyPeriodicEventStreamRequest) GetFeatureName() string {
+	return NotifyPeriodicEventStreamFeatureName
+}
+
+// Creates a new NotifyPeriodicEventStreamRequest, containing all required fields. There are no optional fields for this message.
+func NewNotifyPeriodicEventStreamRequest(id int, pending int, basetime types.DateTime, data []StreamDataElement) *NotifyPeriodicEventStreamRequest {
+	return &NotifyPeriodicEventStreamRequest{ID: id, Pending: pending, Basetime: basetime, Data: data}
+}
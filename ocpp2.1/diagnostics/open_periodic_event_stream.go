@@ -0,0 +1,58 @@
+package diagnostics
+
+import (
+	"reflect"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+)
+
+// -------------------- Open Periodic Event Stream (CS -> CSMS) --------------------
+
+const OpenPeriodicEventStreamFeatureName = "OpenPeriodicEventStream"
+
+// The field definition of the OpenPeriodicEventStream request payload sent by the Charging Station to the CSMS.
+type OpenPeriodicEventStreamRequest struct {
+	ConstantStreamData ConstantStreamData `json:"constantStreamData" validate:"required"`
+}
+
+// This field definition of the OpenPeriodicEventStream response payload, sent by the CSMS to the Charging Station in response to a OpenPeriodicEventStreamRequest.
+// In case the request was invalid, or couldn't be processed, an error will be sent instead.
+type OpenPeriodicEventStreamResponse struct {
+	Status     types.GenericStatus `json:"status" validate:"required,genericStatus21"`
+	StatusInfo *types.StatusInfo   `json:"statusInfo,omitempty" validate:"omitempty"`
+}
+
+// Monitors with a periodic event stream report their values in a stream, instead of via NotifyEventRequest messages.
+// Before sending stream data, the Charging Station opens the stream by sending an OpenPeriodicEventStreamRequest to the CSMS.
+// The CSMS responds with an OpenPeriodicEventStreamResponse, indicating whether it accepts the stream.
+type OpenPeriodicEventStreamFeature struct{}
+
+func (f OpenPeriodicEventStreamFeature) GetFeatureName() string {
+	return OpenPeriodicEventStreamFeatureName
+}
+
+func (f OpenPeriodicEventStreamFeature) GetRequestType() reflect.Type {
+	return reflect.TypeOf(OpenPeriodicEventStreamRequest{})
+}
+
+func (f OpenPeriodicEventStreamFeature) GetResponseType() reflect.Type {
+	return reflect.TypeOf(OpenPeriodicEventStreamResponse{})
+}
+
+func (r OpenPeriodicEventStreamRequest) GetFeatureName() string {
+	return OpenPeriodicEventStreamFeatureName
+}
+
+func (c OpenPeriodicEventStreamResponse) GetFeatureName() string {
+	return OpenPeriodicEventStreamFeatureName
+}
+
+// Creates a new OpenPeriodicEventStreamRequest, containing all required fields. There are no optional fields for this message.
+func NewOpenPeriodicEventStreamRequest(constantStreamData ConstantStreamData) *OpenPeriodicEventStreamRequest {
+	return &OpenPeriodicEventStreamRequest{ConstantStreamData: constantStreamData}
+}
+
+// Creates a new OpenPeriodicEventStreamResponse, containing all required fields. Optional fields may be set afterwards.
+func NewOpenPeriodicEventStreamResponse(status types.GenericStatus) *OpenPeriodicEventStreamResponse {
+	return &OpenPeriodicEventStreamResponse{Status: status}
+}
@@ -0,0 +1,16 @@
+package diagnostics
+
+// Parameters of a periodic event stream. When both fields are absent, every event is sent immediately.
+type PeriodicEventStreamParams struct {
+	Interval *int `json:"interval,omitempty" validate:"omitempty,gte=0"` // Time in seconds after which stream data is sent.
+	Values   *int `json:"values,omitempty" validate:"omitempty,gte=0"`   // Number of items to be sent together in stream.
+}
+
+// ConstantStreamData describes a periodic event stream, which was opened by the Charging Station for a variable monitor.
+//
+// The stream data itself is sent via NotifyPeriodicEventStream, which is a SEND message without response.
+type ConstantStreamData struct {
+	ID                   int                       `json:"id" validate:"gte=0"`                   // Uniquely identifies the stream.
+	Params               PeriodicEventStreamParams `json:"params" validate:"required"`            // Parameters of the stream.
+	VariableMonitoringID int                       `json:"variableMonitoringId" validate:"gte=0"` // Id of monitor used to report the event. It can be a preconfigured or hardwired monitor.
+}
@@ -0,0 +1,117 @@
+package iso15118
+
+import (
+	"reflect"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+// -------------------- Get Certificate Chain Status (CS -> CSMS) --------------------
+
+const GetCertificateChainStatusFeatureName = "GetCertificateChainStatus"
+
+// The source of a certificate status.
+type CertificateStatusSource string
+
+const (
+	CertificateStatusSourceCRL  CertificateStatusSource = "CRL"
+	CertificateStatusSourceOCSP CertificateStatusSource = "OCSP"
+)
+
+func isValidCertificateStatusSource(fl validator.FieldLevel) bool {
+	source := CertificateStatusSource(fl.Field().String())
+	switch source {
+	case CertificateStatusSourceCRL, CertificateStatusSourceOCSP:
+		return true
+	default:
+		return false
+	}
+}
+
+// The revocation status of a certificate.
+type CertificateRevocationStatus string
+
+const (
+	CertificateRevocationStatusGood    CertificateRevocationStatus = "Good"
+	CertificateRevocationStatusRevoked CertificateRevocationStatus = "Revoked"
+	CertificateRevocationStatusUnknown CertificateRevocationStatus = "Unknown"
+	CertificateRevocationStatusFailed  CertificateRevocationStatus = "Failed" // The status couldn't be retrieved.
+)
+
+func isValidCertificateRevocationStatus(fl validator.FieldLevel) bool {
+	status := CertificateRevocationStatus(fl.Field().String())
+	switch status {
+	case CertificateRevocationStatusGood, CertificateRevocationStatusRevoked, CertificateRevocationStatusUnknown, CertificateRevocationStatusFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// Identifies a certificate, whose status shall be retrieved from the given source.
+type CertificateStatusRequestInfo struct {
+	CertificateHashData types.CertificateHashData `json:"certificateHashData" validate:"required"`
+	Source              CertificateStatusSource   `json:"source" validate:"required,certificateStatusSource21"` // Source of status: OCSP, CRL.
+	Urls                []string                  `json:"urls" validate:"required,min=1,max=5,dive,max=2000"`   // URL(s) of source.
+}
+
+// The revocation status of a certificate, as retrieved by the CSMS.
+type CertificateStatusInfo struct {
+	CertificateHashData types.CertificateHashData   `json:"certificateHashData" validate:"required"`
+	Source              CertificateStatusSource     `json:"source" validate:"required,certificateStatusSource21"`     // Source of status: OCSP, CRL.
+	Status              CertificateRevocationStatus `json:"status" validate:"required,certificateRevocationStatus21"` // Status of certificate: good, revoked or unknown.
+	NextUpdate          types.DateTime              `json:"nextUpdate" validate:"required"`                           // Time at which the status must be retrieved again.
+}
+
+// The field definition of the GetCertificateChainStatus request payload sent by the Charging Station to the CSMS.
+type GetCertificateChainStatusRequest struct {
+	CertificateStatusRequests []CertificateStatusRequestInfo `json:"certificateStatusRequests" validate:"required,min=1,max=4,dive"`
+}
+
+// This field definition of the GetCertificateChainStatus response payload, sent by the CSMS to the Charging Station in response to a GetCertificateChainStatusRequest.
+// In case the request was invalid, or couldn't be processed, an error will be sent instead.
+type GetCertificateChainStatusResponse struct {
+	CertificateStatus []CertificateStatusInfo `json:"certificateStatus" validate:"required,min=1,max=4,dive"`
+}
+
+// For validating a certificate chain, e.g. of a contract certificate used for Plug & Charge, the Charging Station
+// requests the CSMS to retrieve the revocation status of the certificates in the chain, via OCSP or CRL.
+// The Charging Station sends a GetCertificateChainStatusRequest, to which the CSMS responds with a GetCertificateChainStatusResponse,
+// containing the status of every requested certificate.
+type GetCertificateChainStatusFeature struct{}
+
+func (f GetCertificateChainStatusFeature) GetFeatureName() string {
+	return GetCertificateChainStatusFeatureName
+}
+
+func (f GetCertificateChainStatusFeature) GetRequestType() reflect.Type {
+	return reflect.TypeOf(GetCertificateChainStatusRequest{})
+}
+
+func (f GetCertificateChainStatusFeature) GetResponseType() reflect.Type {
+	return reflect.TypeOf(GetCertificateChainStatusResponse{})
+}
+
+func (r GetCertificateChainStatusRequest) GetFeatureName() string {
+	return GetCertificateChainStatusFeatureName
+}
+
+func (c GetCertificateChainStatusResponse) GetFeatureName() string {
+	return GetCertificateChainStatusFeatureName
+}
+
+// Creates a new GetCertificateChainStatusRequest, containing all required fields. There are no optional fields for this message.
+func NewGetCertificateChainStatusRequest(certificateStatusRequests []CertificateStatusRequestInfo) *GetCertificateChainStatusRequest {
+	return &GetCertificateChainStatusRequest{CertificateStatusRequests: certificateStatusRequests}
+}
+
+// Creates a new GetCertificateChainStatusResponse, containing all required fields. There are no optional fields for this message.
+func NewGetCertificateChainStatusResponse(certificateStatus []CertificateStatusInfo) *GetCertificateChainStatusResponse {
+	return &GetCertificateChainStatusResponse{CertificateStatus: certificateStatus}
+}
+
+func init() {
+	_ = types.Validate.RegisterValidation("certificateStatusSource21", isValidCertificateStatusSource)
+	_ = types.Validate.RegisterValidation("certificateRevocationStatus21", isValidCertificateRevocationStatus)
+}
@@ -0,0 +1,55 @@
+package payment
+
+import (
+	"reflect"
+)
+
+// -------------------- Notify QR Code Scanned (CS -> CSMS) --------------------
+
+const NotifyQRCodeScannedFeatureName = "NotifyQRCodeScanned"
+
+// The field definition of the NotifyQRCodeScanned request payload sent by the Charging Station to the CSMS.
+type NotifyQRCodeScannedRequest struct {
+	EvseID  int `json:"evseId" validate:"gte=0"` // EVSE for which the QR code was scanned.
+	Timeout int `json:"timeout"`                 // Timeout in seconds, after which the web payment process is considered failed.
+}
+
+// This field definition of the NotifyQRCodeScanned response payload, sent by the CSMS to the Charging Station in response to a NotifyQRCodeScannedRequest.
+// In case the request was invalid, or couldn't be processed, an error will be sent instead.
+type NotifyQRCodeScannedResponse struct {
+}
+
+// When a driver scans a dynamic QR code shown on the Charging Station, and the Charging Station is informed about it,
+// e.g. because the URL contained in the QR code points to the Charging Station itself, the Charging Station
+// notifies the CSMS by sending a NotifyQRCodeScannedRequest. The CSMS responds with a NotifyQRCodeScannedResponse.
+type NotifyQRCodeScannedFeature struct{}
+
+func (f NotifyQRCodeScannedFeature) GetFeatureName() string {
+	return NotifyQRCodeScannedFeatureName
+}
+
+func (f NotifyQRCodeScannedFeature) GetRequestType() reflect.Type {
+	return reflect.TypeOf(NotifyQRCodeScannedRequest{})
+}
+
+func (f NotifyQRCodeScannedFeature) GetResponseType() reflect.Type {
+	return reflect.TypeOf(NotifyQRCodeScannedResponse{})
+}
+
+func (r NotifyQRCodeScannedRequest) GetFeatureName() string {
+	return NotifyQRCodeScannedFeatureName
+}
+
+func (c NotifyQRCodeScannedResponse) GetFeatureName() string {
+	return NotifyQRCodeScannedFeatureName
+}
+
+// Creates a new NotifyQRCodeScannedRequest, containing all required fields. There are no optional fields for this message.
+func NewNotifyQRCodeScannedRequest(evseID int, timeout int) *NotifyQRCodeScannedRequest {
+	return &NotifyQRCodeScannedRequest{EvseID: evseID, Timeout: timeout}
+}
+
+// Creates a new NotifyQRCodeScannedResponse, which doesn't contain any required or optional fields.
+func NewNotifyQRCodeScannedResponse() *NotifyQRCodeScannedResponse {
+	return &NotifyQRCodeScannedResponse{}
+}
@@ -0,0 +1,67 @@
+package payment
+
+import (
+	"reflect"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+)
+
+// -------------------- Notify Settlement (CS -> CSMS) --------------------
+
+const NotifySettlementFeatureName = "NotifySettlement"
+
+// The field definition of the NotifySettlement request payload sent by the Charging Station to the CSMS.
+type NotifySettlementRequest struct {
+	TransactionID    string         `json:"transactionId,omitempty" validate:"omitempty,max=36"` // The transaction for which the payment was settled.
+	PspRef           string         `json:"pspRef" validate:"required,max=255"`                  // The payment reference received from the payment terminal or the payment service provider.
+	Status           PaymentStatus  `json:"status" validate:"required,paymentStatus21"`          // The status of the settlement attempt.
+	StatusInfo       string         `json:"statusInfo,omitempty" validate:"omitempty,max=500"`   // Additional information from the payment terminal/payment service provider.
+	SettlementAmount float64        `json:"settlementAmount"`                                    // The amount that was settled, or attempted to be settled.
+	SettlementTime   types.DateTime `json:"settlementTime" validate:"required"`                  // The time when the settlement was done.
+	ReceiptID        string         `json:"receiptId,omitempty" validate:"omitempty,max=50"`     // Id of the receipt, if created by the Charging Station.
+	ReceiptURL       string         `json:"receiptUrl,omitempty" validate:"omitempty,max=2000"`  // URL of the receipt, if created by the Charging Station.
+	VatCompany       *Address       `json:"vatCompany,omitempty" validate:"omitempty"`           // Company address of the VAT number, if provided by the driver.
+	VatNumber        string         `json:"vatNumber,omitempty" validate:"omitempty,max=20"`     // VAT number for a company receipt.
+}
+
+// This field definition of the NotifySettlement response payload, sent by the CSMS to the Charging Station in response to a NotifySettlementRequest.
+// In case the request was invalid, or couldn't be processed, an error will be sent instead.
+type NotifySettlementResponse struct {
+	ReceiptURL string `json:"receiptUrl,omitempty" validate:"omitempty,max=2000"` // URL of the receipt, if created by the CSMS.
+	ReceiptID  string `json:"receiptId,omitempty" validate:"omitempty,max=50"`    // Id of the receipt, if created by the CSMS.
+}
+
+// After a payment via a local payment terminal was settled, the Charging Station reports the result of the settlement
+// to the CSMS by sending a NotifySettlementRequest. The CSMS responds with a NotifySettlementResponse,
+// optionally containing a receipt, which the Charging Station may show to the driver.
+type NotifySettlementFeature struct{}
+
+func (f NotifySettlementFeature) GetFeatureName() string {
+	return NotifySettlementFeatureName
+}
+
+func (f NotifySettlementFeature) GetRequestType() reflect.Type {
+	return reflect.TypeOf(NotifySettlementRequest{})
+}
+
+func (f NotifySettlementFeature) GetResponseType() reflect.Type {
+	return reflect.TypeOf(NotifySettlementResponse{})
+}
+
+func (r NotifySettlementRequest) GetFeatureName() string {
+	return NotifySettlementFeatureName
+}
+
+func (c NotifySettlementResponse) GetFeatureName() string {
+	return NotifySettlementFeatureName
+}
+
+// Creates a new NotifySettlementRequest, containing all required fields. Optional fields may be set afterwards.
+func NewNotifySettlementRequest(pspRef string, status PaymentStatus, settlementAmount float64, settlementTime types.DateTime) *NotifySettlementRequest {
+	return &NotifySettlementRequest{PspRef: pspRef, Status: status, SettlementAmount: settlementAmount, SettlementTime: settlementTime}
+}
+
+// Creates a new NotifySettlementResponse, which doesn't contain any required fields. Optional fields may be set afterwards.
+func NewNotifySettlementResponse() *NotifySettlementResponse {
+	return &NotifySettlementResponse{}
+}
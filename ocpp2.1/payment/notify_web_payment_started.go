@@ -0,0 +1,55 @@
+package payment
+
+import (
+	"reflect"
+)
+
+// -------------------- Notify Web Payment Started (CSMS -> CS) --------------------
+
+const NotifyWebPaymentStartedFeatureName = "NotifyWebPaymentStarted"
+
+// The field definition of the NotifyWebPaymentStarted request payload sent by the CSMS to the Charging Station.
+type NotifyWebPaymentStartedRequest struct {
+	EvseID  int `json:"evseId" validate:"gte=0"` // EVSE for which a web payment was started.
+	Timeout int `json:"timeout"`                 // Timeout in seconds, after which the web payment process is considered failed.
+}
+
+// This field definition of the NotifyWebPaymentStarted response payload, sent by the Charging Station to the CSMS in response to a NotifyWebPaymentStartedRequest.
+// In case the request was invalid, or couldn't be processed, an error will be sent instead.
+type NotifyWebPaymentStartedResponse struct {
+}
+
+// When a driver opens the web payment page for an EVSE, e.g. after scanning a QR code, the CSMS notifies the Charging Station
+// by sending a NotifyWebPaymentStartedRequest, so that the Charging Station can inform the driver and block the EVSE for other payments.
+// The Charging Station responds with a NotifyWebPaymentStartedResponse.
+type NotifyWebPaymentStartedFeature struct{}
+
+func (f NotifyWebPaymentStartedFeature) GetFeatureName() string {
+	return NotifyWebPaymentStartedFeatureName
+}
+
+func (f NotifyWebPaymentStartedFeature) GetRequestType() reflect.Type {
+	return reflect.TypeOf(NotifyWebPaymentStartedRequest{})
+}
+
+func (f NotifyWebPaymentStartedFeature) GetResponseType() reflect.Type {
+	return reflect.TypeOf(NotifyWebPaymentStartedResponse{})
+}
+
+func (r NotifyWebPaymentStartedRequest) GetFeatureName() string {
+	return NotifyWebPaymentStartedFeatureName
+}
+
+func (c NotifyWebPaymentStartedResponse) GetFeatureName() string {
+	return NotifyWebPaymentStartedFeatureName
+}
+
+// Creates a new NotifyWebPaymentStartedRequest, containing all required fields. There are no optional fields for this message.
+func NewNotifyWebPaymentStartedRequest(evseID int, timeout int) *NotifyWebPaymentStartedRequest {
+	return &NotifyWebPaymentStartedRequest{EvseID: evseID, Timeout: timeout}
+}
+
+// Creates a new NotifyWebPaymentStartedResponse, which doesn't contain any required or optional fields.
+func NewNotifyWebPaymentStartedResponse() *NotifyWebPaymentStartedResponse {
+	return &NotifyWebPaymentStartedResponse{}
+}
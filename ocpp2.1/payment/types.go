@@ -0,0 +1,40 @@
+package payment
+
+import (
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+// The status of a payment settlement.
+type PaymentStatus string
+
+const (
+	PaymentStatusSettled  PaymentStatus = "Settled"
+	PaymentStatusCanceled PaymentStatus = "Canceled"
+	PaymentStatusRejected PaymentStatus = "Rejected"
+	PaymentStatusFailed   PaymentStatus = "Failed"
+)
+
+func isValidPaymentStatus(fl validator.FieldLevel) bool {
+	status := PaymentStatus(fl.Field().String())
+	switch status {
+	case PaymentStatusSettled, PaymentStatusCanceled, PaymentStatusRejected, PaymentStatusFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// A postal address, e.g. of a company.
+type Address struct {
+	Name       string `json:"name" validate:"required,max=50"`                  // Name of person/company.
+	Address1   string `json:"address1" validate:"required,max=100"`             // Address line 1.
+	Address2   string `json:"address2,omitempty" validate:"omitempty,max=100"`  // Address line 2.
+	City       string `json:"city" validate:"required,max=100"`                 // City.
+	PostalCode string `json:"postalCode,omitempty" validate:"omitempty,max=20"` // Postal code.
+	Country    string `json:"country" validate:"required,max=50"`               // Country name.
+}
+
+func init() {
+	_ = types.Validate.RegisterValidation("paymentStatus21", isValidPaymentStatus)
+}
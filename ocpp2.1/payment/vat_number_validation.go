@@ -0,0 +1,62 @@
+package payment
+
+import (
+	"reflect"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+)
+
+// -------------------- VAT Number Validation (CS -> CSMS) --------------------
+
+const VatNumberValidationFeatureName = "VatNumberValidation"
+
+// The field definition of the VatNumberValidation request payload sent by the Charging Station to the CSMS.
+type VatNumberValidationRequest struct {
+	VatNumber string `json:"vatNumber" validate:"required,max=20"`        // VAT number to check.
+	EvseID    *int   `json:"evseId,omitempty" validate:"omitempty,gte=0"` // EVSE for which the check is done.
+}
+
+// This field definition of the VatNumberValidation response payload, sent by the CSMS to the Charging Station in response to a VatNumberValidationRequest.
+// In case the request was invalid, or couldn't be processed, an error will be sent instead.
+type VatNumberValidationResponse struct {
+	Company    *Address            `json:"company,omitempty" validate:"omitempty"`      // Company address associated with the VAT number.
+	StatusInfo *types.StatusInfo   `json:"statusInfo,omitempty" validate:"omitempty"`   // Detailed status information.
+	VatNumber  string              `json:"vatNumber" validate:"required,max=20"`        // VAT number that was requested.
+	EvseID     *int                `json:"evseId,omitempty" validate:"omitempty,gte=0"` // EVSE for which the check was done.
+	Status     types.GenericStatus `json:"status" validate:"required,genericStatus21"`  // Result of the validation.
+}
+
+// A driver may request a company receipt at a Charging Station with a local payment terminal, by entering a VAT number.
+// The Charging Station asks the CSMS to validate the VAT number by sending a VatNumberValidationRequest.
+// The CSMS responds with a VatNumberValidationResponse, containing the company address associated with a valid VAT number.
+type VatNumberValidationFeature struct{}
+
+func (f VatNumberValidationFeature) GetFeatureName() string {
+	return VatNumberValidationFeatureName
+}
+
+func (f VatNumberValidationFeature) GetRequestType() reflect.Type {
+	return reflect.TypeOf(VatNumberValidationRequest{})
+}
+
+func (f VatNumberValidationFeature) GetResponseType() reflect.Type {
+	return reflect.TypeOf(VatNumberValidationResponse{})
+}
+
+func (r VatNumberValidationRequest) GetFeatureName() string {
+	return VatNumberValidationFeatureName
+}
+
+func (c VatNumberValidationResponse) GetFeatureName() string {
+	return VatNumberValidationFeatureName
+}
+
+// Creates a new VatNumberValidationRequest, containing all required fields. Optional fields may be set afterwards.
+func NewVatNumberValidationRequest(vatNumber string) *VatNumberValidationRequest {
+	return &VatNumberValidationRequest{VatNumber: vatNumber}
+}
+
+// Creates a new VatNumberValidationResponse, containing all required fields. Optional fields may be set afterwards.
+func NewVatNumberValidationResponse(vatNumber string, status types.GenericStatus) *VatNumberValidationResponse {
+	return &VatNumberValidationResponse{VatNumber: vatNumber, Status: status}
+}
@@ -0,0 +1,83 @@
+package smartcharging
+
+import (
+	"reflect"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+// -------------------- Notify Allowed Energy Transfer (CSMS -> CS) --------------------
+
+const NotifyAllowedEnergyTransferFeatureName = "NotifyAllowedEnergyTransfer"
+
+// Status returned in response to NotifyAllowedEnergyTransferRequest.
+type NotifyAllowedEnergyTransferStatus string
+
+const (
+	NotifyAllowedEnergyTransferStatusAccepted NotifyAllowedEnergyTransferStatus = "Accepted"
+	NotifyAllowedEnergyTransferStatusRejected NotifyAllowedEnergyTransferStatus = "Rejected"
+)
+
+func isValidNotifyAllowedEnergyTransferStatus(fl validator.FieldLevel) bool {
+	status := NotifyAllowedEnergyTransferStatus(fl.Field().String())
+	switch status {
+	case NotifyAllowedEnergyTransferStatusAccepted, NotifyAllowedEnergyTransferStatusRejected:
+		return true
+	default:
+		return false
+	}
+}
+
+// The field definition of the NotifyAllowedEnergyTransfer request payload sent by the CSMS to the Charging Station.
+type NotifyAllowedEnergyTransferRequest struct {
+	TransactionID         string               `json:"transactionId" validate:"required,max=36"`                                  // The transaction for which the allowed energy transfer is set.
+	AllowedEnergyTransfer []EnergyTransferMode `json:"allowedEnergyTransfer" validate:"required,min=1,dive,energyTransferMode21"` // Modes of energy transfer that are accepted by the CSMS.
+}
+
+// This field definition of the NotifyAllowedEnergyTransfer response payload, sent by the Charging Station to the CSMS in response to a NotifyAllowedEnergyTransferRequest.
+// In case the request was invalid, or couldn't be processed, an error will be sent instead.
+type NotifyAllowedEnergyTransferResponse struct {
+	Status     NotifyAllowedEnergyTransferStatus `json:"status" validate:"required,notifyAllowedEnergyTransferStatus21"`
+	StatusInfo *types.StatusInfo                 `json:"statusInfo,omitempty" validate:"omitempty"`
+}
+
+// When the CSMS does not accept the energy transfer mode requested by the EV (e.g. bidirectional charging),
+// it informs the Charging Station about the allowed energy transfer modes for the transaction,
+// by sending a NotifyAllowedEnergyTransferRequest. The Charging Station responds with a NotifyAllowedEnergyTransferResponse
+// and renegotiates the energy transfer mode with the EV.
+type NotifyAllowedEnergyTransferFeature struct{}
+
+func (f NotifyAllowedEnergyTransferFeature) GetFeatureName() string {
+	return NotifyAllowedEnergyTransferFeatureName
+}
+
+func (f NotifyAllowedEnergyTransferFeature) GetRequestType() reflect.Type {
+	return reflect.TypeOf(NotifyAllowedEnergyTransferRequest{})
+}
+
+func (f NotifyAllowedEnergyTransferFeature) GetResponseType() reflect.Type {
+	return reflect.TypeOf(NotifyAllowedEnergyTransferResponse{})
+}
+
+func (r NotifyAllowedEnergyTransferRequest) GetFeatureName() string {
+	return NotifyAllowedEnergyTransferFeatureName
+}
+
+func (c NotifyAllowedEnergyTransferResponse) GetFeatureName() string {
+	return NotifyAllowedEnergyTransferFeatureName
+}
+
+// Creates a new NotifyAllowedEnergyTransferRequest, containing all required fields. There are no optional fields for this message.
+func NewNotifyAllowedEnergyTransferRequest(transactionID string, allowedEnergyTransfer ...EnergyTransferMode) *NotifyAllowedEnergyTransferRequest {
+	return &NotifyAllowedEnergyTransferRequest{TransactionID: transactionID, AllowedEnergyTransfer: allowedEnergyTransfer}
+}
+
+// Creates a new NotifyAllowedEnergyTransferResponse, containing all required fields. Optional fields may be set afterwards.
+func NewNotifyAllowedEnergyTransferResponse(status NotifyAllowedEnergyTransferStatus) *NotifyAllowedEnergyTransferResponse {
+	return &NotifyAllowedEnergyTransferResponse{Status: status}
+}
+
+func init() {
+	_ = types.Validate.RegisterValidation("notifyAllowedEnergyTransferStatus21", isValidNotifyAllowedEnergyTransferStatus)
+}
@@ -0,0 +1,55 @@
+package smartcharging
+
+import (
+	"reflect"
+)
+
+// -------------------- Notify Priority Charging (CS -> CSMS) --------------------
+
+const NotifyPriorityChargingFeatureName = "NotifyPriorityCharging"
+
+// The field definition of the NotifyPriorityCharging request payload sent by the Charging Station to the CSMS.
+type NotifyPriorityChargingRequest struct {
+	TransactionID string `json:"transactionId" validate:"required,max=36"` // The transaction for which priority charging was activated or deactivated.
+	Activated     bool   `json:"activated"`                                // True if priority charging was activated. False if it has stopped using the priority charging profile.
+}
+
+// This field definition of the NotifyPriorityCharging response payload, sent by the CSMS to the Charging Station in response to a NotifyPriorityChargingRequest.
+// In case the request was invalid, or couldn't be processed, an error will be sent instead.
+type NotifyPriorityChargingResponse struct {
+}
+
+// Whenever priority charging is activated or deactivated locally, e.g. via a button on the Charging Station,
+// the Charging Station notifies the CSMS by sending a NotifyPriorityChargingRequest.
+// The CSMS responds with a NotifyPriorityChargingResponse.
+type NotifyPriorityChargingFeature struct{}
+
+func (f NotifyPriorityChargingFeature) GetFeatureName() string {
+	return NotifyPriorityChargingFeatureName
+}
+
+func (f NotifyPriorityChargingFeature) GetRequestType() reflect.Type {
+	return reflect.TypeOf(NotifyPriorityChargingRequest{})
+}
+
+func (f NotifyPriorityChargingFeature) GetResponseType() reflect.Type {
+	return reflect.TypeOf(NotifyPriorityChargingResponse{})
+}
+
+func (r NotifyPriorityChargingRequest) GetFeatureName() string {
+	return NotifyPriorityChargingFeatureName
+}
+
+func (c NotifyPriorityChargingResponse) GetFeatureName() string {
+	return NotifyPriorityChargingFeatureName
+}
+
+// Creates a new NotifyPriorityChargingRequest, containing all required fields. There are no optional fields for this message.
+func NewNotifyPriorityChargingRequest(transactionID string, activated bool) *NotifyPriorityChargingRequest {
+	return &NotifyPriorityChargingRequest{TransactionID: transactionID, Activated: activated}
+}
+
+// Creates a new NotifyPriorityChargingResponse, which doesn't contain any required or optional fields.
+func NewNotifyPriorityChargingResponse() *NotifyPriorityChargingResponse {
+	return &NotifyPriorityChargingResponse{}
+}
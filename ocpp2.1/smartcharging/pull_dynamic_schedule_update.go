@@ -0,0 +1,59 @@
+package smartcharging
+
+import (
+	"reflect"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+)
+
+// -------------------- Pull Dynamic Schedule Update (CS -> CSMS) --------------------
+
+const PullDynamicScheduleUpdateFeatureName = "PullDynamicScheduleUpdate"
+
+// The field definition of the PullDynamicScheduleUpdate request payload sent by the Charging Station to the CSMS.
+type PullDynamicScheduleUpdateRequest struct {
+	ChargingProfileID int `json:"chargingProfileId"` // Id of the dynamic charging profile, for which an update is requested.
+}
+
+// This field definition of the PullDynamicScheduleUpdate response payload, sent by the CSMS to the Charging Station in response to a PullDynamicScheduleUpdateRequest.
+// In case the request was invalid, or couldn't be processed, an error will be sent instead.
+type PullDynamicScheduleUpdateResponse struct {
+	ScheduleUpdate *ChargingScheduleUpdate `json:"scheduleUpdate,omitempty" validate:"omitempty"` // Updated values for the schedule. Absent if no update is available.
+	Status         ChargingProfileStatus   `json:"status" validate:"required,chargingProfileStatus21"`
+	StatusInfo     *types.StatusInfo       `json:"statusInfo,omitempty" validate:"omitempty"`
+}
+
+// A Charging Station executing a dynamic charging profile periodically requests updated values from the CSMS,
+// by sending a PullDynamicScheduleUpdateRequest. The CSMS responds with a PullDynamicScheduleUpdateResponse,
+// optionally containing the updated values.
+type PullDynamicScheduleUpdateFeature struct{}
+
+func (f PullDynamicScheduleUpdateFeature) GetFeatureName() string {
+	return PullDynamicScheduleUpdateFeatureName
+}
+
+func (f PullDynamicScheduleUpdateFeature) GetRequestType() reflect.Type {
+	return reflect.TypeOf(PullDynamicScheduleUpdateRequest{})
+}
+
+func (f PullDynamicScheduleUpdateFeature) GetResponseType() reflect.Type {
+	return reflect.TypeOf(PullDynamicScheduleUpdateResponse{})
+}
+
+func (r PullDynamicScheduleUpdateRequest) GetFeatureName() string {
+	return PullDynamicScheduleUpdateFeatureName
+}
+
+func (c PullDynamicScheduleUpdateResponse) GetFeatureName() string {
+	return PullDynamicScheduleUpdateFeatureName
+}
+
+// Creates a new PullDynamicScheduleUpdateRequest, containing all required fields. There are no optional fields for this message.
+func NewPullDynamicScheduleUpdateRequest(chargingProfileID int) *PullDynamicScheduleUpdateRequest {
+	return &PullDynamicScheduleUpdateRequest{ChargingProfileID: chargingProfileID}
+}
+
+// Creates a new PullDynamicScheduleUpdateResponse, containing all required fields. Optional fields may be set afterwards.
+func NewPullDynamicScheduleUpdateResponse(status ChargingProfileStatus) *PullDynamicScheduleUpdateResponse {
+	return &PullDynamicScheduleUpdateResponse{Status: status}
+}
@@ -0,0 +1,76 @@
+package smartcharging
+
+import (
+	"reflect"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+)
+
+// -------------------- Update Dynamic Schedule (CSMS -> CS) --------------------
+
+const UpdateDynamicScheduleFeatureName = "UpdateDynamicSchedule"
+
+// ChargingScheduleUpdate contains updated values for a dynamic charging schedule.
+// Limits and setpoints are expressed in the charging rate unit of the charging schedule.
+// Fields suffixed with L2 and L3 apply to the respective phase.
+type ChargingScheduleUpdate struct {
+	Limit              *float64 `json:"limit,omitempty" validate:"omitempty"`
+	LimitL2            *float64 `json:"limit_L2,omitempty" validate:"omitempty"`
+	LimitL3            *float64 `json:"limit_L3,omitempty" validate:"omitempty"`
+	DischargeLimit     *float64 `json:"dischargeLimit,omitempty" validate:"omitempty,lte=0"`
+	DischargeLimitL2   *float64 `json:"dischargeLimit_L2,omitempty" validate:"omitempty,lte=0"`
+	DischargeLimitL3   *float64 `json:"dischargeLimit_L3,omitempty" validate:"omitempty,lte=0"`
+	Setpoint           *float64 `json:"setpoint,omitempty" validate:"omitempty"`
+	SetpointL2         *float64 `json:"setpoint_L2,omitempty" validate:"omitempty"`
+	SetpointL3         *float64 `json:"setpoint_L3,omitempty" validate:"omitempty"`
+	SetpointReactive   *float64 `json:"setpointReactive,omitempty" validate:"omitempty"`
+	SetpointReactiveL2 *float64 `json:"setpointReactive_L2,omitempty" validate:"omitempty"`
+	SetpointReactiveL3 *float64 `json:"setpointReactive_L3,omitempty" validate:"omitempty"`
+}
+
+// The field definition of the UpdateDynamicSchedule request payload sent by the CSMS to the Charging Station.
+type UpdateDynamicScheduleRequest struct {
+	ChargingProfileID int                    `json:"chargingProfileId"`                  // Id of the dynamic charging profile to update.
+	ScheduleUpdate    ChargingScheduleUpdate `json:"scheduleUpdate" validate:"required"` // Updated values for the schedule.
+}
+
+// This field definition of the UpdateDynamicSchedule response payload, sent by the Charging Station to the CSMS in response to a UpdateDynamicScheduleRequest.
+// In case the request was invalid, or couldn't be processed, an error will be sent instead.
+type UpdateDynamicScheduleResponse struct {
+	Status     ChargingProfileStatus `json:"status" validate:"required,chargingProfileStatus21"`
+	StatusInfo *types.StatusInfo     `json:"statusInfo,omitempty" validate:"omitempty"`
+}
+
+// The CSMS may push updated values of a dynamic charging profile to a Charging Station, by sending an UpdateDynamicScheduleRequest.
+// The Charging Station responds with an UpdateDynamicScheduleResponse, indicating whether the update was applied.
+type UpdateDynamicScheduleFeature struct{}
+
+func (f UpdateDynamicScheduleFeature) GetFeatureName() string {
+	return UpdateDynamicScheduleFeatureName
+}
+
+func (f UpdateDynamicScheduleFeature) GetRequestType() reflect.Type {
+	return reflect.TypeOf(UpdateDynamicScheduleRequest{})
+}
+
+func (f UpdateDynamicScheduleFeature) GetResponseType() reflect.Type {
+	return reflect.TypeOf(UpdateDynamicScheduleResponse{})
+}
+
+func (r UpdateDynamicScheduleRequest) GetFeatureName() string {
+	return UpdateDynamicScheduleFeatureName
+}
+
+func (c UpdateDynamicScheduleResponse) GetFeatureName() string {
+	return UpdateDynamicScheduleFeatureName
+}
+
+// Creates a new UpdateDynamicScheduleRequest, containing all required fields. There are no optional fields for this message.
+func NewUpdateDynamicScheduleRequest(chargingProfileID int, scheduleUpdate ChargingScheduleUpdate) *UpdateDynamicScheduleRequest {
+	return &UpdateDynamicScheduleRequest{ChargingProfileID: chargingProfileID, ScheduleUpdate: scheduleUpdate}
+}
+
+// Creates a new UpdateDynamicScheduleResponse, containing all required fields. Optional fields may be set afterwards.
+func NewUpdateDynamicScheduleResponse(status ChargingProfileStatus) *UpdateDynamicScheduleResponse {
+	return &UpdateDynamicScheduleResponse{Status: status}
+}
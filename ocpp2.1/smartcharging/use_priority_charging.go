@@ -0,0 +1,83 @@
+package smartcharging
+
+import (
+	"reflect"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+// -------------------- Use Priority Charging (CSMS -> CS) --------------------
+
+const UsePriorityChargingFeatureName = "UsePriorityCharging"
+
+// Status returned in response to UsePriorityChargingRequest.
+type PriorityChargingStatus string
+
+const (
+	PriorityChargingStatusAccepted  PriorityChargingStatus = "Accepted"
+	PriorityChargingStatusRejected  PriorityChargingStatus = "Rejected"
+	PriorityChargingStatusNoProfile PriorityChargingStatus = "NoProfile" // No priority charging profile is installed for the transaction.
+)
+
+func isValidPriorityChargingStatus(fl validator.FieldLevel) bool {
+	status := PriorityChargingStatus(fl.Field().String())
+	switch status {
+	case PriorityChargingStatusAccepted, PriorityChargingStatusRejected, PriorityChargingStatusNoProfile:
+		return true
+	default:
+		return false
+	}
+}
+
+// The field definition of the UsePriorityCharging request payload sent by the CSMS to the Charging Station.
+type UsePriorityChargingRequest struct {
+	TransactionID string `json:"transactionId" validate:"required,max=36"` // The transaction for which priority charging is requested.
+	Activate      bool   `json:"activate"`                                 // True to request priority charging. False to request stopping priority charging.
+}
+
+// This field definition of the UsePriorityCharging response payload, sent by the Charging Station to the CSMS in response to a UsePriorityChargingRequest.
+// In case the request was invalid, or couldn't be processed, an error will be sent instead.
+type UsePriorityChargingResponse struct {
+	Status     PriorityChargingStatus `json:"status" validate:"required,priorityChargingStatus21"`
+	StatusInfo *types.StatusInfo      `json:"statusInfo,omitempty" validate:"omitempty"`
+}
+
+// The CSMS may request the Charging Station to switch a transaction to priority charging, e.g. on behalf of the driver,
+// by sending a UsePriorityChargingRequest. The Charging Station responds with a UsePriorityChargingResponse,
+// indicating whether a priority charging profile was activated (or deactivated) for the transaction.
+type UsePriorityChargingFeature struct{}
+
+func (f UsePriorityChargingFeature) GetFeatureName() string {
+	return UsePriorityChargingFeatureName
+}
+
+func (f UsePriorityChargingFeature) GetRequestType() reflect.Type {
+	return reflect.TypeOf(UsePriorityChargingRequest{})
+}
+
+func (f UsePriorityChargingFeature) GetResponseType() reflect.Type {
+	return reflect.TypeOf(UsePriorityChargingResponse{})
+}
+
+func (r UsePriorityChargingRequest) GetFeatureName() string {
+	return UsePriorityChargingFeatureName
+}
+
+func (c UsePriorityChargingResponse) GetFeatureName() string {
+	return UsePriorityChargingFeatureName
+}
+
+// Creates a new UsePriorityChargingRequest, containing all required fields. There are no optional fields for this message.
+func NewUsePriorityChargingRequest(transactionID string, activate bool) *UsePriorityChargingRequest {
+	return &UsePriorityChargingRequest{TransactionID: transactionID, Activate: activate}
+}
+
+// Creates a new UsePriorityChargingResponse, containing all required fields. Optional fields may be set afterwards.
+func NewUsePriorityChargingResponse(status PriorityChargingStatus) *UsePriorityChargingResponse {
+	return &UsePriorityChargingResponse{Status: status}
+}
+
+func init() {
+	_ = types.Validate.RegisterValidation("priorityChargingStatus21", isValidPriorityChargingStatus)
+}
@@ -0,0 +1,110 @@
+package tariffcost
+
+import (
+	"reflect"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+// -------------------- Get Tariffs (CSMS -> CS) --------------------
+
+const GetTariffsFeatureName = "GetTariffs"
+
+// Status returned in response to GetTariffsRequest.
+type TariffGetStatus string
+
+const (
+	TariffGetStatusAccepted TariffGetStatus = "Accepted"
+	TariffGetStatusRejected TariffGetStatus = "Rejected"
+	TariffGetStatusNoTariff TariffGetStatus = "NoTariff"
+)
+
+func isValidTariffGetStatus(fl validator.FieldLevel) bool {
+	status := TariffGetStatus(fl.Field().String())
+	switch status {
+	case TariffGetStatusAccepted, TariffGetStatusRejected, TariffGetStatusNoTariff:
+		return true
+	default:
+		return false
+	}
+}
+
+// The kind of an installed tariff.
+type TariffKind string
+
+const (
+	TariffKindDefaultTariff TariffKind = "DefaultTariff"
+	TariffKindDriverTariff  TariffKind = "DriverTariff"
+)
+
+func isValidTariffKind(fl validator.FieldLevel) bool {
+	kind := TariffKind(fl.Field().String())
+	switch kind {
+	case TariffKindDefaultTariff, TariffKindDriverTariff:
+		return true
+	default:
+		return false
+	}
+}
+
+// TariffAssignment describes a tariff installed on the Charging Station, and what it is assigned to.
+type TariffAssignment struct {
+	TariffID   string          `json:"tariffId" validate:"required,max=60"`                  // Tariff id.
+	TariffKind TariffKind      `json:"tariffKind" validate:"required,tariffKind21"`          // Kind of tariff (driver/default).
+	ValidFrom  *types.DateTime `json:"validFrom,omitempty" validate:"omitempty"`             // Date/time when this tariff become active.
+	EvseIDs    []int           `json:"evseIds,omitempty" validate:"omitempty,dive,gte=0"`    // EVSEs, to which the tariff applies.
+	IdTokens   []string        `json:"idTokens,omitempty" validate:"omitempty,dive,max=255"` // IdTokens related to the tariff.
+}
+
+// The field definition of the GetTariffs request payload sent by the CSMS to the Charging Station.
+type GetTariffsRequest struct {
+	EvseID int `json:"evseId" validate:"gte=0"` // EVSE id to get tariff from. When EvseID = 0, this gets tariffs from all EVSEs.
+}
+
+// This field definition of the GetTariffs response payload, sent by the Charging Station to the CSMS in response to a GetTariffsRequest.
+// In case the request was invalid, or couldn't be processed, an error will be sent instead.
+type GetTariffsResponse struct {
+	Status            TariffGetStatus    `json:"status" validate:"required,tariffGetStatus21"`
+	StatusInfo        *types.StatusInfo  `json:"statusInfo,omitempty" validate:"omitempty"`
+	TariffAssignments []TariffAssignment `json:"tariffAssignments,omitempty" validate:"omitempty,dive"`
+}
+
+// The CSMS may request the tariffs installed on a Charging Station, by sending a GetTariffsRequest.
+// The Charging Station responds with a GetTariffsResponse, containing an assignment entry for every installed tariff.
+type GetTariffsFeature struct{}
+
+func (f GetTariffsFeature) GetFeatureName() string {
+	return GetTariffsFeatureName
+}
+
+func (f GetTariffsFeature) GetRequestType() reflect.Type {
+	return reflect.TypeOf(GetTariffsRequest{})
+}
+
+func (f GetTariffsFeature) GetResponseType() reflect.Type {
+	return reflect.TypeOf(GetTariffsResponse{})
+}
+
+func (r GetTariffsRequest) GetFeatureName() string {
+	return GetTariffsFeatureName
+}
+
+func (c GetTariffsResponse) GetFeatureName() string {
+	return GetTariffsFeatureName
+}
+
+// Creates a new GetTariffsRequest, containing all required fields. There are no optional fields for this message.
+func NewGetTariffsRequest(evseID int) *GetTariffsRequest {
+	return &GetTariffsRequest{EvseID: evseID}
+}
+
+// Creates a new GetTariffsResponse, containing all required fields. Optional fields may be set afterwards.
+func NewGetTariffsResponse(status TariffGetStatus) *GetTariffsResponse {
+	return &GetTariffsResponse{Status: status}
+}
+
+func init() {
+	_ = types.Validate.RegisterValidation("tariffGetStatus21", isValidTariffGetStatus)
+	_ = types.Validate.RegisterValidation("tariffKind21", isValidTariffKind)
+}
@@ -0,0 +1,85 @@
+package tariffcost
+
+import (
+	"reflect"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+// -------------------- Set Default Tariff (CSMS -> CS) --------------------
+
+const SetDefaultTariffFeatureName = "SetDefaultTariff"
+
+// Status returned in response to SetDefaultTariffRequest or ChangeTransactionTariffRequest.
+type TariffSetStatus string
+
+const (
+	TariffSetStatusAccepted              TariffSetStatus = "Accepted"
+	TariffSetStatusRejected              TariffSetStatus = "Rejected"
+	TariffSetStatusTooManyElements       TariffSetStatus = "TooManyElements"       // The tariff contains more elements than the Charging Station supports.
+	TariffSetStatusConditionNotSupported TariffSetStatus = "ConditionNotSupported" // The tariff contains a condition, which the Charging Station doesn't support.
+	TariffSetStatusDuplicateTariffId     TariffSetStatus = "DuplicateTariffId"     // A tariff with the same ID already exists.
+)
+
+func isValidTariffSetStatus(fl validator.FieldLevel) bool {
+	status := TariffSetStatus(fl.Field().String())
+	switch status {
+	case TariffSetStatusAccepted, TariffSetStatusRejected, TariffSetStatusTooManyElements, TariffSetStatusConditionNotSupported, TariffSetStatusDuplicateTariffId:
+		return true
+	default:
+		return false
+	}
+}
+
+// The field definition of the SetDefaultTariff request payload sent by the CSMS to the Charging Station.
+type SetDefaultTariffRequest struct {
+	EvseID int    `json:"evseId" validate:"gte=0"`    // EVSE that tariff applies to. When EvseID = 0, then tariff applies to all EVSEs.
+	Tariff Tariff `json:"tariff" validate:"required"` // The tariff to use as default tariff.
+}
+
+// This field definition of the SetDefaultTariff response payload, sent by the Charging Station to the CSMS in response to a SetDefaultTariffRequest.
+// In case the request was invalid, or couldn't be processed, an error will be sent instead.
+type SetDefaultTariffResponse struct {
+	Status     TariffSetStatus   `json:"status" validate:"required,tariffSetStatus21"`
+	StatusInfo *types.StatusInfo `json:"statusInfo,omitempty" validate:"omitempty"`
+}
+
+// The CSMS may set a default tariff on a Charging Station, which is used for calculating the cost of transactions locally,
+// whenever no driver-specific tariff was provided. The CSMS sends a SetDefaultTariffRequest to the Charging Station,
+// which responds with a SetDefaultTariffResponse, indicating whether the tariff was accepted.
+type SetDefaultTariffFeature struct{}
+
+func (f SetDefaultTariffFeature) GetFeatureName() string {
+	return SetDefaultTariffFeatureName
+}
+
+func (f SetDefaultTariffFeature) GetRequestType() reflect.Type {
+	return reflect.TypeOf(SetDefaultTariffRequest{})
+}
+
+func (f SetDefaultTariffFeature) GetResponseType() reflect.Type {
+	return reflect.TypeOf(SetDefaultTariffResponse{})
+}
+
+func (r SetDefaultTariffRequest) GetFeatureName() string {
+	return SetDefaultTariffFeatureName
+}
+
+func (c SetDefaultTariffResponse) GetFeatureName() string {
+	return SetDefaultTariffFeatureName
+}
+
+// Creates a new SetDefaultTariffRequest, containing all required fields. There are no optional fields for this message.
+func NewSetDefaultTariffRequest(evseID int, tariff Tariff) *SetDefaultTariffRequest {
+	return &SetDefaultTariffRequest{EvseID: evseID, Tariff: tariff}
+}
+
+// Creates a new SetDefaultTariffResponse, containing all required fields. Optional fields may be set afterwards.
+func NewSetDefaultTariffResponse(status TariffSetStatus) *SetDefaultTariffResponse {
+	return &SetDefaultTariffResponse{Status: status}
+}
+
+func init() {
+	_ = types.Validate.RegisterValidation("tariffSetStatus21", isValidTariffSetStatus)
+}
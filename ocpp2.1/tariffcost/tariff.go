@@ -0,0 +1,145 @@
+package tariffcost
+
+import (
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+// Day of the week, used by tariff conditions.
+type DayOfWeek string
+
+const (
+	DayOfWeekMonday    DayOfWeek = "Monday"
+	DayOfWeekTuesday   DayOfWeek = "Tuesday"
+	DayOfWeekWednesday DayOfWeek = "Wednesday"
+	DayOfWeekThursday  DayOfWeek = "Thursday"
+	DayOfWeekFriday    DayOfWeek = "Friday"
+	DayOfWeekSaturday  DayOfWeek = "Saturday"
+	DayOfWeekSunday    DayOfWeek = "Sunday"
+)
+
+func isValidDayOfWeek(fl validator.FieldLevel) bool {
+	day := DayOfWeek(fl.Field().String())
+	switch day {
+	case DayOfWeekMonday, DayOfWeekTuesday, DayOfWeekWednesday, DayOfWeekThursday, DayOfWeekFriday, DayOfWeekSaturday, DayOfWeekSunday:
+		return true
+	default:
+		return false
+	}
+}
+
+// Type of EVSE (AC or DC), to which a tariff element applies.
+type EvseKind string
+
+const (
+	EvseKindAC EvseKind = "AC"
+	EvseKindDC EvseKind = "DC"
+)
+
+func isValidEvseKind(fl validator.FieldLevel) bool {
+	kind := EvseKind(fl.Field().String())
+	switch kind {
+	case EvseKindAC, EvseKindDC:
+		return true
+	default:
+		return false
+	}
+}
+
+// Conditions that must be met for a tariff price to apply.
+// Times of day are given as local time in the format hh:mm, dates in the format YYYY-MM-DD.
+// Energy values are in Wh, current values in A, power values in W and durations in seconds.
+type TariffConditions struct {
+	StartTimeOfDay  string      `json:"startTimeOfDay,omitempty" validate:"omitempty"`
+	EndTimeOfDay    string      `json:"endTimeOfDay,omitempty" validate:"omitempty"`
+	DayOfWeek       []DayOfWeek `json:"dayOfWeek,omitempty" validate:"omitempty,max=7,dive,dayOfWeek21"`
+	ValidFromDate   string      `json:"validFromDate,omitempty" validate:"omitempty"`
+	ValidToDate     string      `json:"validToDate,omitempty" validate:"omitempty"`
+	EvseKind        EvseKind    `json:"evseKind,omitempty" validate:"omitempty,evseKind21"`
+	MinEnergy       *float64    `json:"minEnergy,omitempty" validate:"omitempty"`
+	MaxEnergy       *float64    `json:"maxEnergy,omitempty" validate:"omitempty"`
+	MinCurrent      *float64    `json:"minCurrent,omitempty" validate:"omitempty"`
+	MaxCurrent      *float64    `json:"maxCurrent,omitempty" validate:"omitempty"`
+	MinPower        *float64    `json:"minPower,omitempty" validate:"omitempty"`
+	MaxPower        *float64    `json:"maxPower,omitempty" validate:"omitempty"`
+	MinTime         *float64    `json:"minTime,omitempty" validate:"omitempty"`
+	MaxTime         *float64    `json:"maxTime,omitempty" validate:"omitempty"`
+	MinChargingTime *float64    `json:"minChargingTime,omitempty" validate:"omitempty"`
+	MaxChargingTime *float64    `json:"maxChargingTime,omitempty" validate:"omitempty"`
+	MinIdleTime     *float64    `json:"minIdleTime,omitempty" validate:"omitempty"`
+	MaxIdleTime     *float64    `json:"maxIdleTime,omitempty" validate:"omitempty"`
+}
+
+// Conditions that must be met for a fixed tariff price to apply.
+type TariffConditionsFixed struct {
+	StartTimeOfDay     string      `json:"startTimeOfDay,omitempty" validate:"omitempty"`
+	EndTimeOfDay       string      `json:"endTimeOfDay,omitempty" validate:"omitempty"`
+	DayOfWeek          []DayOfWeek `json:"dayOfWeek,omitempty" validate:"omitempty,max=7,dive,dayOfWeek21"`
+	ValidFromDate      string      `json:"validFromDate,omitempty" validate:"omitempty"`
+	ValidToDate        string      `json:"validToDate,omitempty" validate:"omitempty"`
+	EvseKind           EvseKind    `json:"evseKind,omitempty" validate:"omitempty,evseKind21"`
+	PaymentBrand       string      `json:"paymentBrand,omitempty" validate:"omitempty,max=20"`       // For which payment brand this (adhoc) tariff applies.
+	PaymentRecognition string      `json:"paymentRecognition,omitempty" validate:"omitempty,max=20"` // Type of adhoc payment, e.g. CC, Debit.
+}
+
+// Price per kWh, applying under the given conditions.
+type TariffEnergyPrice struct {
+	PriceKwh   float64           `json:"priceKwh"`                                  // Price per kWh (excl. tax) for this element.
+	Conditions *TariffConditions `json:"conditions,omitempty" validate:"omitempty"` // Conditions when this tariff element applies.
+}
+
+// Price elements and tax for energy.
+type TariffEnergy struct {
+	Prices   []TariffEnergyPrice `json:"prices" validate:"required,min=1,dive"`
+	TaxRates []types.TaxRate     `json:"taxRates,omitempty" validate:"omitempty,max=5,dive"`
+}
+
+// Price per minute, applying under the given conditions.
+type TariffTimePrice struct {
+	PriceMinute float64           `json:"priceMinute"`                               // Price per minute (excl. tax) for this element.
+	Conditions  *TariffConditions `json:"conditions,omitempty" validate:"omitempty"` // Conditions when this tariff element applies.
+}
+
+// Price elements and tax for time.
+type TariffTime struct {
+	Prices   []TariffTimePrice `json:"prices" validate:"required,min=1,dive"`
+	TaxRates []types.TaxRate   `json:"taxRates,omitempty" validate:"omitempty,max=5,dive"`
+}
+
+// Fixed price, applying under the given conditions.
+type TariffFixedPrice struct {
+	PriceFixed float64                `json:"priceFixed"`                                // Fixed price (excl. tax) for this element.
+	Conditions *TariffConditionsFixed `json:"conditions,omitempty" validate:"omitempty"` // Conditions when this tariff element applies.
+}
+
+// Price elements and tax for fixed fees.
+type TariffFixed struct {
+	Prices   []TariffFixedPrice `json:"prices" validate:"required,min=1,dive"`
+	TaxRates []types.TaxRate    `json:"taxRates,omitempty" validate:"omitempty,max=5,dive"`
+}
+
+// Tariff contains the price elements of a tariff, which can be used by a Charging Station for calculating the cost of a transaction locally.
+type Tariff struct {
+	TariffID         string                 `json:"tariffId" validate:"required,max=60"`                    // Unique id of tariff.
+	Description      []types.MessageContent `json:"description,omitempty" validate:"omitempty,max=10,dive"` // Description of the tariff, in multiple languages.
+	Currency         string                 `json:"currency" validate:"required,max=3"`                     // Currency code according to ISO 4217.
+	Energy           *TariffEnergy          `json:"energy,omitempty" validate:"omitempty"`
+	ValidFrom        *types.DateTime        `json:"validFrom,omitempty" validate:"omitempty"` // Time when this tariff becomes active. When absent, it is immediately active.
+	ChargingTime     *TariffTime            `json:"chargingTime,omitempty" validate:"omitempty"`
+	IdleTime         *TariffTime            `json:"idleTime,omitempty" validate:"omitempty"`
+	FixedFee         *TariffFixed           `json:"fixedFee,omitempty" validate:"omitempty"`
+	ReservationTime  *TariffTime            `json:"reservationTime,omitempty" validate:"omitempty"`
+	ReservationFixed *TariffFixed           `json:"reservationFixed,omitempty" validate:"omitempty"`
+	MinCost          *types.Price           `json:"minCost,omitempty" validate:"omitempty"` // When absent, there is no minimal cost.
+	MaxCost          *types.Price           `json:"maxCost,omitempty" validate:"omitempty"` // When absent, there is no maximum cost.
+}
+
+// NewTariff creates a new Tariff, containing all required fields. Price elements may be set afterwards.
+func NewTariff(tariffID string, currency string) Tariff {
+	return Tariff{TariffID: tariffID, Currency: currency}
+}
+
+func init() {
+	_ = types.Validate.RegisterValidation("dayOfWeek21", isValidDayOfWeek)
+	_ = types.Validate.RegisterValidation("evseKind21", isValidEvseKind)
+}
@@ -0,0 +1,882 @@
+// Contains common and shared data types between OCPP 2.1 messages.
+package types
+
+import (
+	"gopkg.in/go-playground/validator.v9"
+
+	"github.com/lorenzodonini/ocpp-go/ocppj"
+)
+
+const (
+	V21Subprotocol = "ocpp2.1"
+)
+
+type PropertyViolation struct {
+	error
+	Property string
+}
+
+func (e *PropertyViolation) Error() string {
+	return ""
+}
+
+type AuthorizationStatus string
+
+const (
+	AuthorizationStatusAccepted           AuthorizationStatus = "Accepted"
+	AuthorizationStatusBlocked            AuthorizationStatus = "Blocked"
+	AuthorizationStatusExpired            AuthorizationStatus = "Expired"
+	AuthorizationStatusInvalid            AuthorizationStatus = "Invalid"
+	AuthorizationStatusConcurrentTx       AuthorizationStatus = "ConcurrentTx"
+	AuthorizationStatusNoCredit           AuthorizationStatus = "NoCredit"
+	AuthorizationStatusNotAllowedTypeEVSE AuthorizationStatus = "NotAllowedTypeEVSE"
+	AuthorizationStatusNotAtThisLocation  AuthorizationStatus = "NotAtThisLocation"
+	AuthorizationStatusNotAtThisTime      AuthorizationStatus = "NotAtThisTime"
+	AuthorizationStatusUnknown            AuthorizationStatus = "Unknown"
+)
+
+func isValidAuthorizationStatus(fl validator.FieldLevel) bool {
+	status := AuthorizationStatus(fl.Field().String())
+	switch status {
+	case AuthorizationStatusAccepted, AuthorizationStatusBlocked, AuthorizationStatusExpired, AuthorizationStatusInvalid, AuthorizationStatusConcurrentTx, AuthorizationStatusNoCredit, AuthorizationStatusNotAllowedTypeEVSE, AuthorizationStatusNotAtThisLocation, AuthorizationStatusNotAtThisTime, AuthorizationStatusUnknown:
+		return true
+	default:
+		return false
+	}
+}
+
+// ID Token
+//
+// In OCPP 2.1 the token type is an open string (IdTokenEnumStringType) of at most 20 characters.
+// The constants below are the values defined by the specification; other values may be used by bilateral agreement.
+type IdTokenType string
+
+const (
+	IdTokenTypeCentral         IdTokenType = "Central"
+	IdTokenTypeDirectPayment   IdTokenType = "DirectPayment"
+	IdTokenTypeEMAID           IdTokenType = "eMAID"
+	IdTokenTypeEVCCID          IdTokenType = "EVCCID"
+	IdTokenTypeISO14443        IdTokenType = "ISO14443"
+	IdTokenTypeISO15693        IdTokenType = "ISO15693"
+	IdTokenTypeKeyCode         IdTokenType = "KeyCode"
+	IdTokenTypeLocal           IdTokenType = "Local"
+	IdTokenTypeMacAddress      IdTokenType = "MacAddress"
+	IdTokenTypeNoAuthorization IdTokenType = "NoAuthorization"
+	IdTokenTypeVIN             IdTokenType = "VIN"
+)
+
+func isValidIdToken(sl validator.StructLevel) {
+	idToken := sl.Current().Interface().(IdToken)
+	// validate required idToken value except `NoAuthorization` type
+	if idToken.Type != IdTokenTypeNoAuthorization && idToken.IdToken == "" {
+		sl.ReportError(idToken.IdToken, "IdToken", "IdToken", "required", "")
+	}
+}
+
+type AdditionalInfo struct {
+	AdditionalIdToken string `json:"additionalIdToken" validate:"required,max=36"`
+	Type              string `json:"type" validate:"required,max=50"`
+}
+
+type IdToken struct {
+	IdToken        string           `json:"idToken" validate:"max=36"`
+	Type           IdTokenType      `json:"type" validate:"required,max=20"`
+	AdditionalInfo []AdditionalInfo `json:"additionalInfo,omitempty" validate:"omitempty,dive"`
+}
+
+// Generic Device Model Status
+type GenericDeviceModelStatus string
+
+const (
+	GenericDeviceModelStatusAccepted       GenericDeviceModelStatus = "Accepted"
+	GenericDeviceModelStatusRejected       GenericDeviceModelStatus = "Rejected"
+	GenericDeviceModelStatusNotSupported   GenericDeviceModelStatus = "NotSupported"
+	GenericDeviceModelStatusEmptyResultSet GenericDeviceModelStatus = "EmptyResultSet" // If the combination of received criteria result in an empty result set.
+)
+
+func isValidGenericDeviceModelStatus(fl validator.FieldLevel) bool {
+	status := GenericDeviceModelStatus(fl.Field().String())
+	switch status {
+	case GenericDeviceModelStatusAccepted, GenericDeviceModelStatusRejected, GenericDeviceModelStatusNotSupported, GenericDeviceModelStatusEmptyResultSet:
+		return true
+	default:
+		return false
+	}
+}
+
+// Generic Status
+type GenericStatus string
+
+const (
+	GenericStatusAccepted GenericStatus = "Accepted"
+	GenericStatusRejected GenericStatus = "Rejected"
+)
+
+func isValidGenericStatus(fl validator.FieldLevel) bool {
+	status := GenericStatus(fl.Field().String())
+	switch status {
+	case GenericStatusAccepted, GenericStatusRejected:
+		return true
+	default:
+		return false
+	}
+}
+
+// Hash Algorithms
+type HashAlgorithmType string
+
+const (
+	SHA256 HashAlgorithmType = "SHA256"
+	SHA384 HashAlgorithmType = "SHA384"
+	SHA512 HashAlgorithmType = "SHA512"
+)
+
+func isValidHashAlgorithmType(fl validator.FieldLevel) bool {
+	algorithm := HashAlgorithmType(fl.Field().String())
+	switch algorithm {
+	case SHA256, SHA384, SHA512:
+		return true
+	default:
+		return false
+	}
+}
+
+// OCSPRequestDataType
+type OCSPRequestDataType struct {
+	HashAlgorithm  HashAlgorithmType `json:"hashAlgorithm" validate:"required,hashAlgorithm21"`
+	IssuerNameHash string            `json:"issuerNameHash" validate:"required,max=128"`
+	IssuerKeyHash  string            `json:"issuerKeyHash" validate:"required,max=128"`
+	SerialNumber   string            `json:"serialNumber" validate:"required,max=40"`
+	ResponderURL   string            `json:"responderURL,omitempty" validate:"max=512"`
+}
+
+// CertificateHashDataType
+type CertificateHashData struct {
+	HashAlgorithm  HashAlgorithmType `json:"hashAlgorithm" validate:"required,hashAlgorithm21"`
+	IssuerNameHash string            `json:"issuerNameHash" validate:"required,max=128"`
+	IssuerKeyHash  string            `json:"issuerKeyHash" validate:"required,max=128"`
+	SerialNumber   string            `json:"serialNumber" validate:"required,max=40"`
+}
+
+// CertificateHashDataChain
+type CertificateHashDataChain struct {
+	CertificateType          CertificateUse        `json:"certificateType" validate:"required,certificateUse21"`
+	CertificateHashData      CertificateHashData   `json:"certificateHashData" validate:"required"`
+	ChildCertificateHashData []CertificateHashData `json:"childCertificateHashData,omitempty" validate:"omitempty,dive"`
+}
+
+// Certificate15118EVStatus
+type Certificate15118EVStatus string
+
+const (
+	Certificate15188EVStatusAccepted Certificate15118EVStatus = "Accepted"
+	Certificate15118EVStatusFailed   Certificate15118EVStatus = "Failed"
+)
+
+func isValidCertificate15118EVStatus(fl validator.FieldLevel) bool {
+	status := Certificate15118EVStatus(fl.Field().String())
+	switch status {
+	case Certificate15188EVStatusAccepted, Certificate15118EVStatusFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// Indicates the type of the signed certificate that is returned.
+// When omitted the certificate is used for both the 15118 connection (if implemented) and the Charging Station to CSMS connection.
+// This field is required when a typeOfCertificate was included in the SignCertificateRequest that requested this certificate to be signed AND both the 15118 connection and the Charging Station connection are implemented.
+type CertificateSigningUse string
+
+const (
+	ChargingStationCert CertificateSigningUse = "ChargingStationCertificate"
+	V2GCertificate      CertificateSigningUse = "V2GCertificate"
+	V2G20Certificate    CertificateSigningUse = "V2G20Certificate"
+)
+
+func isValidCertificateSigningUse(fl validator.FieldLevel) bool {
+	status := CertificateSigningUse(fl.Field().String())
+	switch status {
+	case ChargingStationCert, V2GCertificate, V2G20Certificate:
+		return true
+	default:
+		return false
+	}
+}
+
+// Indicates the type of the requested certificate.
+// It is used in GetInstalledCertificateIdsRequest and InstallCertificateRequest messages.
+type CertificateUse string
+
+const (
+	V2GRootCertificate          CertificateUse = "V2GRootCertificate"
+	MORootCertificate           CertificateUse = "MORootCertificate"
+	CSMSRootCertificate         CertificateUse = "CSMSRootCertificate"
+	V2GCertificateChain         CertificateUse = "V2GCertificateChain"
+	ManufacturerRootCertificate CertificateUse = "ManufacturerRootCertificate"
+	OEMRootCertificate          CertificateUse = "OEMRootCertificate"
+)
+
+func isValidCertificateUse(fl validator.FieldLevel) bool {
+	use := CertificateUse(fl.Field().String())
+	switch use {
+	case V2GRootCertificate, MORootCertificate, CSMSRootCertificate, V2GCertificateChain, ManufacturerRootCertificate, OEMRootCertificate:
+		return true
+	default:
+		return false
+	}
+}
+
+// ID Token Info
+type MessageFormatType string
+
+const (
+	MessageFormatASCII  MessageFormatType = "ASCII"
+	MessageFormatHTML   MessageFormatType = "HTML"
+	MessageFormatURI    MessageFormatType = "URI"
+	MessageFormatUTF8   MessageFormatType = "UTF8"
+	MessageFormatQRCode MessageFormatType = "QRCODE"
+)
+
+func isValidMessageFormatType(fl validator.FieldLevel) bool {
+	algorithm := MessageFormatType(fl.Field().String())
+	switch algorithm {
+	case MessageFormatASCII, MessageFormatHTML, MessageFormatURI, MessageFormatUTF8, MessageFormatQRCode:
+		return true
+	default:
+		return false
+	}
+}
+
+type MessageContent struct {
+	Format   MessageFormatType `json:"format" validate:"required,messageFormat21"`
+	Language string            `json:"language,omitempty" validate:"max=8"`
+	Content  string            `json:"content" validate:"required,max=512"`
+}
+
+type GroupIdToken struct {
+	IdToken string      `json:"idToken" validate:"max=36"`
+	Type    IdTokenType `json:"type" validate:"required,max=20"`
+}
+
+func isValidGroupIdToken(sl validator.StructLevel) {
+	groupIdToken := sl.Current().Interface().(GroupIdToken)
+	// validate required idToken value except `NoAuthorization` type
+	if groupIdToken.Type != IdTokenTypeNoAuthorization && groupIdToken.IdToken == "" {
+		sl.ReportError(groupIdToken.IdToken, "IdToken", "IdToken", "required", "")
+	}
+}
+
+type IdTokenInfo struct {
+	Status              AuthorizationStatus `json:"status" validate:"required,authorizationStatus21"`
+	CacheExpiryDateTime *DateTime           `json:"cacheExpiryDateTime,omitempty" validate:"omitempty"`
+	ChargingPriority    int                 `json:"chargingPriority,omitempty" validate:"min=-9,max=9"`
+	Language1           string              `json:"language1,omitempty" validate:"max=8"`
+	Language2           string              `json:"language2,omitempty" validate:"max=8"`
+	GroupIdToken        *GroupIdToken       `json:"groupIdToken,omitempty"`
+	PersonalMessage     *MessageContent     `json:"personalMessage,omitempty"`
+}
+
+// NewIdTokenInfo creates an IdTokenInfo. Optional parameters may be set afterwards on the initialized struct.
+func NewIdTokenInfo(status AuthorizationStatus) *IdTokenInfo {
+	return &IdTokenInfo{Status: status}
+}
+
+// StatusInfo is an element providing more information about the message status.
+type StatusInfo struct {
+	ReasonCode     string `json:"reasonCode" validate:"required,max=20"`                 // A predefined code for the reason why the status is returned in this response. The string is case- insensitive.
+	AdditionalInfo string `json:"additionalInfo,omitempty" validate:"omitempty,max=512"` // Additional text to provide detailed information.
+}
+
+// NewStatusInfo creates a StatusInfo struct.
+// If no additional info need to be set, an empty string may be passed.
+func NewStatusInfo(reasonCode string, additionalInfo string) *StatusInfo {
+	return &StatusInfo{ReasonCode: reasonCode, AdditionalInfo: additionalInfo}
+}
+
+// EVSE represents the Electric Vehicle Supply Equipment, formerly referred to as connector(s).
+type EVSE struct {
+	ID          int  `json:"id" validate:"gte=0"`                              // The EVSE Identifier. When 0, the ID references the Charging Station as a whole.
+	ConnectorID *int `json:"connectorId,omitempty" validate:"omitempty,gte=0"` // An id to designate a specific connector (on an EVSE) by connector index number.
+}
+
+// Component represents a physical or logical component.
+type Component struct {
+	Name     string `json:"name" validate:"required,max=50"`                // Name of the component. Name should be taken from the list of standardized component names whenever possible. Case Insensitive. strongly advised to use Camel Case.
+	Instance string `json:"instance,omitempty" validate:"omitempty,max=50"` // Name of instance in case the component exists as multiple instances. Case Insensitive. strongly advised to use Camel Case.
+	EVSE     *EVSE  `json:"evse,omitempty" validate:"omitempty"`            // Specifies the EVSE when component is located at EVSE level, also specifies the connector when component is located at Connector level.
+}
+
+// Variable is a reference key to a component-variable.
+type Variable struct {
+	Name     string `json:"name" validate:"required,max=50"`                // Name of the variable. Name should be taken from the list of standardized variable names whenever possible. Case Insensitive. strongly advised to use Camel Case.
+	Instance string `json:"instance,omitempty" validate:"omitempty,max=50"` // Name of instance in case the variable exists as multiple instances. Case Insensitive. strongly advised to use Camel Case.
+}
+
+// ComponentVariable is used to report components, variables and variable attributes and characteristics.
+type ComponentVariable struct {
+	Component Component `json:"component" validate:"required"` // Component for which a report of Variable is requested.
+	Variable  Variable  `json:"variable" validate:"required"`  // Variable for which report is requested.
+}
+
+// Attribute is an enumeration type used when requesting a variable value.
+type Attribute string
+
+const (
+	AttributeActual Attribute = "Actual" // The actual value of the variable.
+	AttributeTarget Attribute = "Target" // The target value for this variable.
+	AttributeMinSet Attribute = "MinSet" // The minimal allowed value for this variable.
+	AttributeMaxSet Attribute = "MaxSet" // The maximum allowed value for this variable
+)
+
+func isValidAttribute(fl validator.FieldLevel) bool {
+	purposeType := Attribute(fl.Field().String())
+	switch purposeType {
+	case AttributeActual, AttributeTarget, AttributeMinSet, AttributeMaxSet:
+		return true
+	default:
+		return false
+	}
+}
+
+// Sales Tariff
+
+// The kind of cost referred to in a CostType.
+type CostKind string
+
+const (
+	CostKindCarbonDioxideEmission         CostKind = "CarbonDioxideEmission"         // Carbon Dioxide emissions, in grams per kWh.
+	CostKindRelativePricePercentage       CostKind = "RelativePricePercentage"       // Price per kWh, as percentage relative to the maximum price stated in any of all tariffs indicated to the EV.
+	CostKindRenewableGenerationPercentage CostKind = "RenewableGenerationPercentage" // Percentage of renewable generation within total generation.
+)
+
+func isValidCostKind(fl validator.FieldLevel) bool {
+	purposeType := CostKind(fl.Field().String())
+	switch purposeType {
+	case CostKindCarbonDioxideEmission, CostKindRelativePricePercentage, CostKindRenewableGenerationPercentage:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines the time interval the SalesTariffEntry is valid for, based upon relative times.
+type RelativeTimeInterval struct {
+	Start    int  `json:"start"`                                         // Start of the interval, in seconds from NOW.
+	Duration *int `json:"duration,omitempty" validate:"omitempty,gte=0"` // Duration of the interval, in seconds.
+}
+
+// Cost details.
+type CostType struct {
+	CostKind         CostKind `json:"costKind" validate:"required,costKind21"`                      // The kind of cost referred to in the message element amount.
+	Amount           int      `json:"amount" validate:"gte=0"`                                      // The estimated or actual cost per kWh.
+	AmountMultiplier *int     `json:"amountMultiplier,omitempty" validate:"omitempty,min=-3,max=3"` // The exponent to base 10 (dec).
+}
+
+// Contains price information and/or alternative costs.
+type ConsumptionCost struct {
+	StartValue float64    `json:"startValue"`                          // The lowest level of consumption that defines the starting point of this consumption block
+	Cost       []CostType `json:"cost" validate:"required,max=3,dive"` // Contains the cost details.
+}
+
+// NewConsumptionCost instantiates a new ConsumptionCost struct. No additional parameters need to be set.
+func NewConsumptionCost(startValue float64, cost []CostType) ConsumptionCost {
+	return ConsumptionCost{
+		StartValue: startValue,
+		Cost:       cost,
+	}
+}
+
+// Element describing all relevant details for one time interval of the SalesTariff.
+type SalesTariffEntry struct {
+	EPriceLevel          *int                 `json:"ePriceLevel,omitempty" validate:"omitempty,gte=0"`          // The price level of this SalesTariffEntry (referring to NumEPriceLevels). Small values for the EPriceLevel represent a cheaper TariffEntry.
+	RelativeTimeInterval RelativeTimeInterval `json:"relativeTimeInterval" validate:"required"`                  // The time interval the SalesTariffEntry is valid for, based upon relative times.
+	ConsumptionCost      []ConsumptionCost    `json:"consumptionCost,omitempty" validate:"omitempty,max=3,dive"` // Additional means for further relative price information and/or alternative costs.
+}
+
+// Sales tariff associated with this charging schedule.
+type SalesTariff struct {
+	ID                     int                `json:"id"`                                                           // Identifier used to identify one sales tariff.
+	SalesTariffDescription string             `json:"salesTariffDescription,omitempty" validate:"omitempty,max=32"` // A human readable title/short description of the sales tariff e.g. for HMI display purposes.
+	NumEPriceLevels        *int               `json:"numEPriceLevels,omitempty" validate:"omitempty"`               // Defines the overall number of distinct price levels used across all provided SalesTariff elements.
+	SalesTariffEntry       []SalesTariffEntry `json:"salesTariffEntry" validate:"required,min=1,max=1024,dive"`     // Encapsulates elements describing all relevant details for one time interval of the SalesTariff.
+}
+
+// NewSalesTariff instantiates a new SalesTariff struct. Only required fields are passed as parameters.
+func NewSalesTariff(id int, salesTariffEntries []SalesTariffEntry) *SalesTariff {
+	return &SalesTariff{
+		ID:               id,
+		SalesTariffEntry: salesTariffEntries,
+	}
+}
+
+// Tariffs and costs
+
+// Tax percentage, applied to a price.
+type TaxRate struct {
+	Type  string  `json:"type" validate:"required,max=20"`            // Type of this tax, e.g. "Federal", "State", for information on receipt.
+	Tax   float64 `json:"tax"`                                        // Tax percentage.
+	Stack *int    `json:"stack,omitempty" validate:"omitempty,gte=0"` // Stack level for this type of tax. Default value, when absent, is 0.
+}
+
+// Price with and without tax. At least one of ExclTax and InclTax must be set.
+type Price struct {
+	ExclTax  *float64  `json:"exclTax,omitempty" validate:"required_without=InclTax"` // Price/cost excluding tax.
+	InclTax  *float64  `json:"inclTax,omitempty" validate:"required_without=ExclTax"` // Price/cost including tax.
+	TaxRates []TaxRate `json:"taxRates,omitempty" validate:"omitempty,max=5,dive"`    // Tax percentages that were used to calculate InclTax from ExclTax.
+}
+
+// Total cost with and without tax.
+type TotalPrice struct {
+	ExclTax *float64 `json:"exclTax,omitempty" validate:"omitempty"` // Price/cost excluding tax.
+	InclTax *float64 `json:"inclTax,omitempty" validate:"omitempty"` // Price/cost including tax.
+}
+
+// The kind of a total cost.
+type TariffCost string
+
+const (
+	TariffCostNormalCost TariffCost = "NormalCost" // Total cost is calculated normally.
+	TariffCostMinCost    TariffCost = "MinCost"    // Total cost is the minimum cost of the tariff.
+	TariffCostMaxCost    TariffCost = "MaxCost"    // Total cost is the maximum cost of the tariff.
+)
+
+func isValidTariffCost(fl validator.FieldLevel) bool {
+	cost := TariffCost(fl.Field().String())
+	switch cost {
+	case TariffCostNormalCost, TariffCostMinCost, TariffCostMaxCost:
+		return true
+	default:
+		return false
+	}
+}
+
+// Total cost of a transaction, split per tariff dimension.
+type TotalCost struct {
+	Currency         string     `json:"currency" validate:"required,max=3"`          // Currency of the costs in ISO 4217 code.
+	TypeOfCost       TariffCost `json:"typeOfCost" validate:"required,tariffCost21"` // Type of cost: normal or the minimum or maximum cost.
+	Fixed            *Price     `json:"fixed,omitempty" validate:"omitempty"`        // Total sum of all fixed costs.
+	Energy           *Price     `json:"energy,omitempty" validate:"omitempty"`       // Total sum of all the cost of all energy during this transaction.
+	ChargingTime     *Price     `json:"chargingTime,omitempty" validate:"omitempty"` // Total sum of all the cost related to duration of charging during this transaction.
+	IdleTime         *Price     `json:"idleTime,omitempty" validate:"omitempty"`     // Total sum of all the cost related to idle time during this transaction.
+	ReservationTime  *Price     `json:"reservationTime,omitempty" validate:"omitempty"`
+	ReservationFixed *Price     `json:"reservationFixed,omitempty" validate:"omitempty"`
+	Total            TotalPrice `json:"total" validate:"required"` // Total cost of the transaction.
+}
+
+// Total usage of energy and time during a transaction.
+type TotalUsage struct {
+	Energy          float64 `json:"energy"`                                               // Total energy, in Wh.
+	ChargingTime    int     `json:"chargingTime" validate:"gte=0"`                        // Total duration of the charging session (including idle time), in seconds.
+	IdleTime        int     `json:"idleTime" validate:"gte=0"`                            // Total duration of the charging session where the EV was not charging, in seconds.
+	ReservationTime *int    `json:"reservationTime,omitempty" validate:"omitempty,gte=0"` // Total time of reservation, in seconds.
+}
+
+// The dimension of a cost volume.
+type CostDimensionType string
+
+const (
+	CostDimensionEnergy       CostDimensionType = "Energy"       // Defined in kWh, step size 1 Wh.
+	CostDimensionMaxCurrent   CostDimensionType = "MaxCurrent"   // Defined in A, step size 0.001 A.
+	CostDimensionMinCurrent   CostDimensionType = "MinCurrent"   // Defined in A, step size 0.001 A.
+	CostDimensionMaxPower     CostDimensionType = "MaxPower"     // Defined in kW, step size 0.001 kW.
+	CostDimensionMinPower     CostDimensionType = "MinPower"     // Defined in kW, step size 0.001 kW.
+	CostDimensionIdleTime     CostDimensionType = "IdleTime"     // Defined in seconds, step size 1 second.
+	CostDimensionChargingTime CostDimensionType = "ChargingTime" // Defined in seconds, step size 1 second.
+)
+
+func isValidCostDimension(fl validator.FieldLevel) bool {
+	dimension := CostDimensionType(fl.Field().String())
+	switch dimension {
+	case CostDimensionEnergy, CostDimensionMaxCurrent, CostDimensionMinCurrent, CostDimensionMaxPower, CostDimensionMinPower, CostDimensionIdleTime, CostDimensionChargingTime:
+		return true
+	default:
+		return false
+	}
+}
+
+// Volume consumed of a cost dimension.
+type CostDimension struct {
+	Type   CostDimensionType `json:"type" validate:"required,costDimension21"`
+	Volume float64           `json:"volume"`
+}
+
+// A period of a transaction, during which the same tariff applied.
+type ChargingPeriod struct {
+	Dimensions  []CostDimension `json:"dimensions,omitempty" validate:"omitempty,dive"` // List of relevant values for this charging period.
+	TariffID    string          `json:"tariffId,omitempty" validate:"omitempty,max=60"` // Unique identifier of the tariff that was used to calculate the cost.
+	StartPeriod DateTime        `json:"startPeriod" validate:"required"`                // Start timestamp of the charging period.
+}
+
+// Cost details of a transaction, as calculated locally by the Charging Station.
+type CostDetails struct {
+	ChargingPeriods    []ChargingPeriod `json:"chargingPeriods,omitempty" validate:"omitempty,dive"`  // List of charging periods that make up this charging session.
+	TotalCost          TotalCost        `json:"totalCost" validate:"required"`                        // Total sum of all the costs of this transaction.
+	TotalUsage         TotalUsage       `json:"totalUsage" validate:"required"`                       // Total usage of energy and time.
+	FailureToCalculate *bool            `json:"failureToCalculate,omitempty" validate:"omitempty"`    // If set to true, then Charging Station has failed to calculate the cost.
+	FailureReason      string           `json:"failureReason,omitempty" validate:"omitempty,max=500"` // Optional human-readable reason text in case of failure to calculate.
+}
+
+// Charging Profiles
+
+type ChargingProfilePurposeType string
+type ChargingProfileKindType string
+type RecurrencyKindType string
+type ChargingRateUnitType string
+
+// Source that has installed a charging limit. In OCPP 2.1 this is an open string (ChargingLimitSourceEnumStringType) of at most 20 characters.
+type ChargingLimitSourceType string
+
+const (
+	ChargingProfilePurposeChargingStationExternalConstraints ChargingProfilePurposeType = "ChargingStationExternalConstraints"
+	ChargingProfilePurposeChargingStationMaxProfile          ChargingProfilePurposeType = "ChargingStationMaxProfile"
+	ChargingProfilePurposeTxDefaultProfile                   ChargingProfilePurposeType = "TxDefaultProfile"
+	ChargingProfilePurposeTxProfile                          ChargingProfilePurposeType = "TxProfile"
+	ChargingProfilePurposePriorityCharging                   ChargingProfilePurposeType = "PriorityCharging"
+	ChargingProfilePurposeLocalGeneration                    ChargingProfilePurposeType = "LocalGeneration"
+	ChargingProfileKindAbsolute                              ChargingProfileKindType    = "Absolute"
+	ChargingProfileKindRecurring                             ChargingProfileKindType    = "Recurring"
+	ChargingProfileKindRelative                              ChargingProfileKindType    = "Relative"
+	ChargingProfileKindDynamic                               ChargingProfileKindType    = "Dynamic"
+	RecurrencyKindDaily                                      RecurrencyKindType         = "Daily"
+	RecurrencyKindWeekly                                     RecurrencyKindType         = "Weekly"
+	ChargingRateUnitWatts                                    ChargingRateUnitType       = "W"
+	ChargingRateUnitAmperes                                  ChargingRateUnitType       = "A"
+	ChargingLimitSourceEMS                                   ChargingLimitSourceType    = "EMS"
+	ChargingLimitSourceOther                                 ChargingLimitSourceType    = "Other"
+	ChargingLimitSourceSO                                    ChargingLimitSourceType    = "SO"
+	ChargingLimitSourceCSO                                   ChargingLimitSourceType    = "CSO"
+)
+
+func isValidChargingProfilePurpose(fl validator.FieldLevel) bool {
+	purposeType := ChargingProfilePurposeType(fl.Field().String())
+	switch purposeType {
+	case ChargingProfilePurposeChargingStationExternalConstraints, ChargingProfilePurposeChargingStationMaxProfile, ChargingProfilePurposeTxDefaultProfile, ChargingProfilePurposeTxProfile, ChargingProfilePurposePriorityCharging, ChargingProfilePurposeLocalGeneration:
+		return true
+	default:
+		return false
+	}
+}
+
+func isValidChargingProfileKind(fl validator.FieldLevel) bool {
+	purposeType := ChargingProfileKindType(fl.Field().String())
+	switch purposeType {
+	case ChargingProfileKindAbsolute, ChargingProfileKindRecurring, ChargingProfileKindRelative, ChargingProfileKindDynamic:
+		return true
+	default:
+		return false
+	}
+}
+
+func isValidRecurrencyKind(fl validator.FieldLevel) bool {
+	purposeType := RecurrencyKindType(fl.Field().String())
+	switch purposeType {
+	case RecurrencyKindDaily, RecurrencyKindWeekly:
+		return true
+	default:
+		return false
+	}
+}
+
+func isValidChargingRateUnit(fl validator.FieldLevel) bool {
+	purposeType := ChargingRateUnitType(fl.Field().String())
+	switch purposeType {
+	case ChargingRateUnitWatts, ChargingRateUnitAmperes:
+		return true
+	default:
+		return false
+	}
+}
+
+type ChargingSchedulePeriod struct {
+	StartPeriod  int     `json:"startPeriod" validate:"gte=0"`
+	Limit        float64 `json:"limit" validate:"gte=0"`
+	NumberPhases *int    `json:"numberPhases,omitempty" validate:"omitempty,gte=0"`
+}
+
+func NewChargingSchedulePeriod(startPeriod int, limit float64) ChargingSchedulePeriod {
+	return ChargingSchedulePeriod{StartPeriod: startPeriod, Limit: limit}
+}
+
+type ChargingSchedule struct {
+	ID                     int                      `json:"id" validate:"gte=0"` // Identifies the ChargingSchedule.
+	StartSchedule          *DateTime                `json:"startSchedule,omitempty" validate:"omitempty"`
+	Duration               *int                     `json:"duration,omitempty" validate:"omitempty,gte=0"`
+	ChargingRateUnit       ChargingRateUnitType     `json:"chargingRateUnit" validate:"required,chargingRateUnit21"`
+	MinChargingRate        *float64                 `json:"minChargingRate,omitempty" validate:"omitempty,gte=0"`
+	ChargingSchedulePeriod []ChargingSchedulePeriod `json:"chargingSchedulePeriod" validate:"required,min=1,max=1024"`
+	SalesTariff            *SalesTariff             `json:"salesTariff,omitempty" validate:"omitempty"` // Sales tariff associated with this charging schedule.
+}
+
+func NewChargingSchedule(id int, chargingRateUnit ChargingRateUnitType, schedulePeriod ...ChargingSchedulePeriod) *ChargingSchedule {
+	return &ChargingSchedule{ID: id, ChargingRateUnit: chargingRateUnit, ChargingSchedulePeriod: schedulePeriod}
+}
+
+type ChargingProfile struct {
+	ID                     int                        `json:"id" validate:"gte=0"`
+	StackLevel             int                        `json:"stackLevel" validate:"gte=0"`
+	ChargingProfilePurpose ChargingProfilePurposeType `json:"chargingProfilePurpose" validate:"required,chargingProfilePurpose21"`
+	ChargingProfileKind    ChargingProfileKindType    `json:"chargingProfileKind" validate:"required,chargingProfileKind21"`
+	RecurrencyKind         RecurrencyKindType         `json:"recurrencyKind,omitempty" validate:"omitempty,recurrencyKind21"`
+	ValidFrom              *DateTime                  `json:"validFrom,omitempty"`
+	ValidTo                *DateTime                  `json:"validTo,omitempty"`
+	TransactionID          string                     `json:"transactionId,omitempty" validate:"omitempty,max=36"`
+	ChargingSchedule       []ChargingSchedule         `json:"chargingSchedule" validate:"required,min=1,max=3,dive"`
+}
+
+func NewChargingProfile(id int, stackLevel int, chargingProfilePurpose ChargingProfilePurposeType, chargingProfileKind ChargingProfileKindType, schedule []ChargingSchedule) *ChargingProfile {
+	return &ChargingProfile{ID: id, StackLevel: stackLevel, ChargingProfilePurpose: chargingProfilePurpose, ChargingProfileKind: chargingProfileKind, ChargingSchedule: schedule}
+}
+
+// Remote Start/Stop
+type RemoteStartStopStatus string
+
+const (
+	RemoteStartStopStatusAccepted RemoteStartStopStatus = "Accepted"
+	RemoteStartStopStatusRejected RemoteStartStopStatus = "Rejected"
+)
+
+func isValidRemoteStartStopStatus(fl validator.FieldLevel) bool {
+	status := RemoteStartStopStatus(fl.Field().String())
+	switch status {
+	case RemoteStartStopStatusAccepted, RemoteStartStopStatusRejected:
+		return true
+	default:
+		return false
+	}
+}
+
+// Meter Value
+
+type ReadingContext string
+type Measurand string
+type Phase string
+type Location string
+
+const (
+	ReadingContextInterruptionBegin                    ReadingContext = "Interruption.Begin"
+	ReadingContextInterruptionEnd                      ReadingContext = "Interruption.End"
+	ReadingContextOther                                ReadingContext = "Other"
+	ReadingContextSampleClock                          ReadingContext = "Sample.Clock"
+	ReadingContextSamplePeriodic                       ReadingContext = "Sample.Periodic"
+	ReadingContextTransactionBegin                     ReadingContext = "Transaction.Begin"
+	ReadingContextTransactionEnd                       ReadingContext = "Transaction.End"
+	ReadingContextTrigger                              ReadingContext = "Trigger"
+	MeasurandCurrentExport                             Measurand      = "Current.Export"
+	MeasurandCurrentExportOffered                      Measurand      = "Current.Export.Offered"
+	MeasurandCurrentExportMinimum                      Measurand      = "Current.Export.Minimum"
+	MeasurandCurrentImport                             Measurand      = "Current.Import"
+	MeasurandCurrentImportOffered                      Measurand      = "Current.Import.Offered"
+	MeasurandCurrentImportMinimum                      Measurand      = "Current.Import.Minimum"
+	MeasurandCurrentOffered                            Measurand      = "Current.Offered"
+	MeasurandDisplayPresentSOC                         Measurand      = "Display.PresentSOC"
+	MeasurandDisplayMinimumSOC                         Measurand      = "Display.MinimumSOC"
+	MeasurandDisplayTargetSOC                          Measurand      = "Display.TargetSOC"
+	MeasurandDisplayMaximumSOC                         Measurand      = "Display.MaximumSOC"
+	MeasurandDisplayRemainingTimeToMinimumSOC          Measurand      = "Display.RemainingTimeToMinimumSOC"
+	MeasurandDisplayRemainingTimeToTargetSOC           Measurand      = "Display.RemainingTimeToTargetSOC"
+	MeasurandDisplayRemainingTimeToMaximumSOC          Measurand      = "Display.RemainingTimeToMaximumSOC"
+	MeasurandDisplayChargingComplete                   Measurand      = "Display.ChargingComplete"
+	MeasurandDisplayBatteryEnergyCapacity              Measurand      = "Display.BatteryEnergyCapacity"
+	MeasurandDisplayInletHot                           Measurand      = "Display.InletHot"
+	MeasurandEnergyActiveExportInterval                Measurand      = "Energy.Active.Export.Interval"
+	MeasurandEnergyActiveExportRegister                Measurand      = "Energy.Active.Export.Register"
+	MeasurandEnergyActiveImportInterval                Measurand      = "Energy.Active.Import.Interval"
+	MeasurandEnergyActiveImportRegister                Measurand      = "Energy.Active.Import.Register"
+	MeasurandEnergyActiveImportCableLoss               Measurand      = "Energy.Active.Import.CableLoss"
+	MeasurandEnergyActiveImportLocalGenerationRegister Measurand      = "Energy.Active.Import.LocalGeneration.Register"
+	MeasurandEnergyActiveNet                           Measurand      = "Energy.Active.Net"
+	MeasurandEnergyActiveSetpointInterval              Measurand      = "Energy.Active.Setpoint.Interval"
+	MeasurandEnergyApparentExport                      Measurand      = "Energy.Apparent.Export"
+	MeasurandEnergyApparentImport                      Measurand      = "Energy.Apparent.Import"
+	MeasurandEnergyApparentNet                         Measurand      = "Energy.Apparent.Net"
+	MeasurandEnergyReactiveExportInterval              Measurand      = "Energy.Reactive.Export.Interval"
+	MeasurandEnergyReactiveExportRegister              Measurand      = "Energy.Reactive.Export.Register"
+	MeasurandEnergyReactiveImportInterval              Measurand      = "Energy.Reactive.Import.Interval"
+	MeasurandEnergyReactiveImportRegister              Measurand      = "Energy.Reactive.Import.Register"
+	MeasurandEnergyReactiveNet                         Measurand      = "Energy.Reactive.Net"
+	MeasurandEnergyRequestTarget                       Measurand      = "EnergyRequest.Target"
+	MeasurandEnergyRequestMinimum                      Measurand      = "EnergyRequest.Minimum"
+	MeasurandEnergyRequestMaximum                      Measurand      = "EnergyRequest.Maximum"
+	MeasurandEnergyRequestMinimumV2X                   Measurand      = "EnergyRequest.Minimum.V2X"
+	MeasurandEnergyRequestMaximumV2X                   Measurand      = "EnergyRequest.Maximum.V2X"
+	MeasurandEnergyRequestBulk                         Measurand      = "EnergyRequest.Bulk"
+	MeasurandFrequency                                 Measurand      = "Frequency"
+	MeasurandPowerActiveExport                         Measurand      = "Power.Active.Export"
+	MeasurandPowerActiveImport                         Measurand      = "Power.Active.Import"
+	MeasurandPowerActiveSetpoint                       Measurand      = "Power.Active.Setpoint"
+	MeasurandPowerActiveResidual                       Measurand      = "Power.Active.Residual"
+	MeasurandPowerExportMinimum                        Measurand      = "Power.Export.Minimum"
+	MeasurandPowerExportOffered                        Measurand      = "Power.Export.Offered"
+	MeasurandPowerFactor                               Measurand      = "Power.Factor"
+	MeasurandPowerImportOffered                        Measurand      = "Power.Import.Offered"
+	MeasurandPowerImportMinimum                        Measurand      = "Power.Import.Minimum"
+	MeasurandPowerOffered                              Measurand      = "Power.Offered"
+	MeasurandPowerReactiveExport                       Measurand      = "Power.Reactive.Export"
+	MeasurandPowerReactiveImport                       Measurand      = "Power.Reactive.Import"
+	MeasurandSoC                                       Measurand      = "SoC"
+	MeasurandVoltage                                   Measurand      = "Voltage"
+	MeasurandVoltageMinimum                            Measurand      = "Voltage.Minimum"
+	MeasurandVoltageMaximum                            Measurand      = "Voltage.Maximum"
+	PhaseL1                                            Phase          = "L1"
+	PhaseL2                                            Phase          = "L2"
+	PhaseL3                                            Phase          = "L3"
+	PhaseN                                             Phase          = "N"
+	PhaseL1N                                           Phase          = "L1-N"
+	PhaseL2N                                           Phase          = "L2-N"
+	PhaseL3N                                           Phase          = "L3-N"
+	PhaseL1L2                                          Phase          = "L1-L2"
+	PhaseL2L3                                          Phase          = "L2-L3"
+	PhaseL3L1                                          Phase          = "L3-L1"
+	LocationBody                                       Location       = "Body"
+	LocationCable                                      Location       = "Cable"
+	LocationEV                                         Location       = "EV"
+	LocationInlet                                      Location       = "Inlet"
+	LocationOutlet                                     Location       = "Outlet"
+	LocationUpstream                                   Location       = "Upstream"
+)
+
+func isValidReadingContext(fl validator.FieldLevel) bool {
+	readingContext := ReadingContext(fl.Field().String())
+	switch readingContext {
+	case ReadingContextInterruptionBegin, ReadingContextInterruptionEnd, ReadingContextOther, ReadingContextSampleClock, ReadingContextSamplePeriodic, ReadingContextTransactionBegin, ReadingContextTransactionEnd, ReadingContextTrigger:
+		return true
+	default:
+		return false
+	}
+}
+
+func isValidMeasurand(fl validator.FieldLevel) bool {
+	measurand := Measurand(fl.Field().String())
+	switch measurand {
+	case MeasurandCurrentExport, MeasurandCurrentExportOffered, MeasurandCurrentExportMinimum, MeasurandCurrentImport, MeasurandCurrentImportOffered, MeasurandCurrentImportMinimum, MeasurandCurrentOffered, MeasurandDisplayPresentSOC, MeasurandDisplayMinimumSOC, MeasurandDisplayTargetSOC, MeasurandDisplayMaximumSOC, MeasurandDisplayRemainingTimeToMinimumSOC, MeasurandDisplayRemainingTimeToTargetSOC, MeasurandDisplayRemainingTimeToMaximumSOC, MeasurandDisplayChargingComplete, MeasurandDisplayBatteryEnergyCapacity, MeasurandDisplayInletHot, MeasurandEnergyActiveExportInterval, MeasurandEnergyActiveExportRegister, MeasurandEnergyActiveImportInterval, MeasurandEnergyActiveImportRegister, MeasurandEnergyActiveImportCableLoss, MeasurandEnergyActiveImportLocalGenerationRegister, MeasurandEnergyActiveNet, MeasurandEnergyActiveSetpointInterval, MeasurandEnergyApparentExport, MeasurandEnergyApparentImport, MeasurandEnergyApparentNet, MeasurandEnergyReactiveExportInterval, MeasurandEnergyReactiveExportRegister, MeasurandEnergyReactiveImportInterval, MeasurandEnergyReactiveImportRegister, MeasurandEnergyReactiveNet, MeasurandEnergyRequestTarget, MeasurandEnergyRequestMinimum, MeasurandEnergyRequestMaximum, MeasurandEnergyRequestMinimumV2X, MeasurandEnergyRequestMaximumV2X, MeasurandEnergyRequestBulk, MeasurandFrequency, MeasurandPowerActiveExport, MeasurandPowerActiveImport, MeasurandPowerActiveSetpoint, MeasurandPowerActiveResidual, MeasurandPowerExportMinimum, MeasurandPowerExportOffered, MeasurandPowerFactor, MeasurandPowerImportOffered, MeasurandPowerImportMinimum, MeasurandPowerOffered, MeasurandPowerReactiveExport, MeasurandPowerReactiveImport, MeasurandSoC, MeasurandVoltage, MeasurandVoltageMinimum, MeasurandVoltageMaximum:
+		return true
+	default:
+		return false
+	}
+}
+
+func isValidPhase(fl validator.FieldLevel) bool {
+	phase := Phase(fl.Field().String())
+	switch phase {
+	case PhaseL1, PhaseL2, PhaseL3, PhaseN, PhaseL1N, PhaseL2N, PhaseL3N, PhaseL1L2, PhaseL2L3, PhaseL3L1:
+		return true
+	default:
+		return false
+	}
+}
+
+func isValidLocation(fl validator.FieldLevel) bool {
+	location := Location(fl.Field().String())
+	switch location {
+	case LocationBody, LocationCable, LocationEV, LocationInlet, LocationOutlet, LocationUpstream:
+		return true
+	default:
+		return false
+	}
+}
+
+type UnitOfMeasure struct {
+	Unit       string `json:"unit,omitempty" validate:"omitempty,max=20"`
+	Multiplier *int   `json:"multiplier,omitempty" validate:"omitempty,gte=0"`
+}
+
+//TODO: remove SignatureMethod (obsolete from 2.0.1 onwards)
+
+// Enumeration of the cryptographic method used to create the digital signature.
+// The list is expected to grow in future OCPP releases to allow other signature methods used by Smart Meters.
+type SignatureMethod string
+
+const (
+	SignatureECDSAP256SHA256 SignatureMethod = "ECDSAP256SHA256" // The encoded data is hashed with the SHA-256 hash function, and the hash value is then signed with the ECDSA algorithm using the NIST P-256 elliptic curve.
+	SignatureECDSAP384SHA384 SignatureMethod = "ECDSAP384SHA384" // The encoded data is hashed with the SHA-384 hash function, and the hash value is then signed with the ECDSA algorithm using the NIST P-384 elliptic curve.
+	SignatureECDSA192SHA256  SignatureMethod = "ECDSA192SHA256"  // The encoded data is hashed with the SHA-256 hash function, and the hash value is then signed with the ECDSA algorithm using a 192-bit elliptic curve.
+)
+
+func isValidSignatureMethod(fl validator.FieldLevel) bool {
+	signature := SignatureMethod(fl.Field().String())
+	switch signature {
+	case SignatureECDSA192SHA256, SignatureECDSAP256SHA256, SignatureECDSAP384SHA384:
+		return true
+	default:
+		return false
+	}
+}
+
+//TODO: remove EncodingMethod (obsolete from 2.0.1 onwards)
+
+// Enumeration of the method used to encode the meter value into binary data before applying the digital signature algorithm.
+// If the EncodingMethod is set to Other, the CSMS MAY try to determine the encoding method from the encodedMeterValue field.
+type EncodingMethod string
+
+const (
+	EncodingOther              EncodingMethod = "Other"                // Encoding method is not included in the enumeration.
+	EncodingDLMSMessage        EncodingMethod = "DLMS Message"         // The data is encoded in a digitally signed DLMS message, as described in the DLMS Green Book 8.
+	EncodingCOSEMProtectedData EncodingMethod = "COSEM Protected Data" // The data is encoded according to the COSEM data protection methods, as described in the DLMS Blue Book 12.
+	EncodingEDL                EncodingMethod = "EDL"                  // The data is encoded in the format used by EDL meters.
+)
+
+func isValidEncodingMethod(fl validator.FieldLevel) bool {
+	encoding := EncodingMethod(fl.Field().String())
+	switch encoding {
+	case EncodingCOSEMProtectedData, EncodingEDL, EncodingDLMSMessage, EncodingOther:
+		return true
+	default:
+		return false
+	}
+}
+
+type SignedMeterValue struct {
+	SignedMeterData string `json:"signedMeterData" validate:"required,max=2500"` // Base64 encoded, contains the signed data which might contain more then just the meter value. It can contain information like timestamps, reference to a customer etc.
+	SigningMethod   string `json:"signingMethod" validate:"required,max=50"`     // Method used to create the digital signature.
+	EncodingMethod  string `json:"encodingMethod" validate:"required,max=50"`    // Method used to encode the meter values before applying the digital signature algorithm.
+	PublicKey       string `json:"publicKey" validate:"required,max=2500"`       // Base64 encoded, sending depends on configuration variable PublicKeyWithSignedMeterValue.
+}
+
+type SampledValue struct {
+	Value            float64           `json:"value"`                                                   // Indicates the measured value. This value is required.
+	Context          ReadingContext    `json:"context,omitempty" validate:"omitempty,readingContext21"` // Type of detail value: start, end or sample. Default = "Sample.Periodic"
+	Measurand        Measurand         `json:"measurand,omitempty" validate:"omitempty,measurand21"`    // Type of measurement. Default = "Energy.Active.Import.Register"
+	Phase            Phase             `json:"phase,omitempty" validate:"omitempty,phase21"`            // Indicates how the measured value is to be interpreted. For instance between L1 and neutral (L1-N) Please note that not all values of phase are applicable to all Measurands. When phase is absent, the measured value is interpreted as an overall value.
+	Location         Location          `json:"location,omitempty" validate:"omitempty,location21"`      // Indicates where the measured value has been sampled.
+	SignedMeterValue *SignedMeterValue `json:"signedMeterValue,omitempty" validate:"omitempty"`         // Contains the MeterValueSignature with sign/encoding method information.
+	UnitOfMeasure    *UnitOfMeasure    `json:"unitOfMeasure,omitempty" validate:"omitempty"`            // Represents a UnitOfMeasure including a multiplier.
+}
+
+type MeterValue struct {
+	Timestamp    DateTime       `json:"timestamp" validate:"required"`
+	SampledValue []SampledValue `json:"sampledValue" validate:"required,min=1,dive"`
+}
+
+// Validator used for validating all OCPP 2.1 messages.
+// Any additional custom validations must be added to this object for automatic validation.
+var Validate = ocppj.Validate
+
+func init() {
+	_ = Validate.RegisterValidation("genericDeviceModelStatus21", isValidGenericDeviceModelStatus)
+	_ = Validate.RegisterValidation("genericStatus21", isValidGenericStatus)
+	_ = Validate.RegisterValidation("hashAlgorithm21", isValidHashAlgorithmType)
+	_ = Validate.RegisterValidation("messageFormat21", isValidMessageFormatType)
+	_ = Validate.RegisterValidation("authorizationStatus21", isValidAuthorizationStatus)
+	_ = Validate.RegisterValidation("attribute21", isValidAttribute)
+	_ = Validate.RegisterValidation("chargingProfilePurpose21", isValidChargingProfilePurpose)
+	_ = Validate.RegisterValidation("chargingProfileKind21", isValidChargingProfileKind)
+	_ = Validate.RegisterValidation("recurrencyKind21", isValidRecurrencyKind)
+	_ = Validate.RegisterValidation("chargingRateUnit21", isValidChargingRateUnit)
+	_ = Validate.RegisterValidation("remoteStartStopStatus21", isValidRemoteStartStopStatus)
+	_ = Validate.RegisterValidation("readingContext21", isValidReadingContext)
+	_ = Validate.RegisterValidation("measurand21", isValidMeasurand)
+	_ = Validate.RegisterValidation("phase21", isValidPhase)
+	_ = Validate.RegisterValidation("location21", isValidLocation)
+	_ = Validate.RegisterValidation("signatureMethod21", isValidSignatureMethod)
+	_ = Validate.RegisterValidation("encodingMethod21", isValidEncodingMethod)
+	_ = Validate.RegisterValidation("certificateSigningUse21", isValidCertificateSigningUse)
+	_ = Validate.RegisterValidation("certificateUse21", isValidCertificateUse)
+	_ = Validate.RegisterValidation("15118EVCertificate21", isValidCertificate15118EVStatus)
+	_ = Validate.RegisterValidation("costKind21", isValidCostKind)
+	_ = Validate.RegisterValidation("tariffCost21", isValidTariffCost)
+	_ = Validate.RegisterValidation("costDimension21", isValidCostDimension)
+
+	Validate.RegisterStructValidation(isValidIdToken, IdToken{})
+	Validate.RegisterStructValidation(isValidGroupIdToken, GroupIdToken{})
+}
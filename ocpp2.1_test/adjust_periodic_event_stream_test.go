@@ -0,0 +1,87 @@
+package ocpp21_test
+
+import (
+	"fmt"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/diagnostics"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+)
+
+// Test
+func (suite *OcppV2TestSuite) TestAdjustPeriodicEventStreamRequestValidation() {
+	t := suite.T()
+	var requestTable = []GenericTestEntry{
+		{diagnostics.AdjustPeriodicEventStreamRequest{ID: 1, Params: diagnostics.PeriodicEventStreamParams{Interval: newInt(60), Values: newInt(10)}}, true},
+		{diagnostics.AdjustPeriodicEventStreamRequest{ID: 1}, true},
+		{diagnostics.AdjustPeriodicEventStreamRequest{}, true},
+		{diagnostics.AdjustPeriodicEventStreamRequest{ID: -1}, false},
+		{diagnostics.AdjustPeriodicEventStreamRequest{ID: 1, Params: diagnostics.PeriodicEventStreamParams{Interval: newInt(-1)}}, false},
+	}
+	ExecuteGenericTestTable(t, requestTable)
+}
+
+func (suite *OcppV2TestSuite) TestAdjustPeriodicEventStreamResponseValidation() {
+	t := suite.T()
+	var confirmationTable = []GenericTestEntry{
+		{diagnostics.AdjustPeriodicEventStreamResponse{Status: types.GenericStatusAccepted, StatusInfo: types.NewStatusInfo("200", "")}, true},
+		{diagnostics.AdjustPeriodicEventStreamResponse{Status: types.GenericStatusRejected}, true},
+		{diagnostics.AdjustPeriodicEventStreamResponse{}, false},
+		{diagnostics.AdjustPeriodicEventStreamResponse{Status: "invalidStatus"}, false},
+		{diagnostics.AdjustPeriodicEventStreamResponse{Status: types.GenericStatusAccepted, StatusInfo: types.NewStatusInfo("", "")}, false},
+	}
+	ExecuteGenericTestTable(t, confirmationTable)
+}
+
+func (suite *OcppV2TestSuite) TestAdjustPeriodicEventStreamE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	id := 1
+	params := diagnostics.PeriodicEventStreamParams{Interval: newInt(30), Values: newInt(5)}
+	status := types.GenericStatusAccepted
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"id":%v,"params":{"interval":%v,"values":%v}}]`,
+		messageId, diagnostics.AdjustPeriodicEventStreamFeatureName, id, *params.Interval, *params.Values)
+	responseJson := fmt.Sprintf(`[3,"%v",{"status":"%v"}]`, messageId, status)
+	adjustPeriodicEventStreamResponse := diagnostics.NewAdjustPeriodicEventStreamResponse(status)
+	channel := NewMockWebSocket(wsId)
+
+	handler := &MockChargingStationDiagnosticsHandler{}
+	handler.On("OnAdjustPeriodicEventStream", mock.Anything).Return(adjustPeriodicEventStreamResponse, nil).Run(func(args mock.Arguments) {
+		request, ok := args.Get(0).(*diagnostics.AdjustPeriodicEventStreamRequest)
+		require.True(t, ok)
+		require.NotNil(t, request)
+		assert.Equal(t, id, request.ID)
+		assert.Equal(t, params, request.Params)
+	})
+	setupDefaultCSMSHandlers(suite, expectedCSMSOptions{clientId: wsId, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	setupDefaultChargingStationHandlers(suite, expectedChargingStationOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(responseJson), forwardWrittenMessage: true}, handler)
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	err := suite.chargingStation.Start(wsUrl)
+	require.Nil(t, err)
+	resultChannel := make(chan bool, 1)
+	err = suite.csms.AdjustPeriodicEventStream(wsId, func(response *diagnostics.AdjustPeriodicEventStreamResponse, err error) {
+		require.Nil(t, err)
+		require.NotNil(t, response)
+		assert.Equal(t, status, response.Status)
+		resultChannel <- true
+	}, id, params)
+	require.Nil(t, err)
+	result := <-resultChannel
+	assert.True(t, result)
+}
+
+func (suite *OcppV2TestSuite) TestAdjustPeriodicEventStreamInvalidEndpoint() {
+	messageId := defaultMessageId
+	id := 1
+	params := diagnostics.PeriodicEventStreamParams{Interval: newInt(30)}
+	request := diagnostics.NewAdjustPeriodicEventStreamRequest(id, params)
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"id":%v,"params":{"interval":%v}}]`,
+		messageId, diagnostics.AdjustPeriodicEventStreamFeatureName, id, *params.Interval)
+	testUnsupportedRequestFromChargingStation(suite, request, requestJson, messageId)
+}
@@ -0,0 +1,86 @@
+package ocpp21_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/smartcharging"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+)
+
+// Test
+func (suite *OcppV2TestSuite) TestAFRRSignalRequestValidation() {
+	t := suite.T()
+	var requestTable = []GenericTestEntry{
+		{smartcharging.AFRRSignalRequest{Timestamp: *types.NewDateTime(time.Now()), Signal: 1}, true},
+		{smartcharging.AFRRSignalRequest{Timestamp: *types.NewDateTime(time.Now()), Signal: -1}, true},
+		{smartcharging.AFRRSignalRequest{Timestamp: *types.NewDateTime(time.Now())}, true},
+	}
+	ExecuteGenericTestTable(t, requestTable)
+}
+
+func (suite *OcppV2TestSuite) TestAFRRSignalResponseValidation() {
+	t := suite.T()
+	var confirmationTable = []GenericTestEntry{
+		{smartcharging.AFRRSignalResponse{Status: types.GenericStatusAccepted, StatusInfo: types.NewStatusInfo("200", "")}, true},
+		{smartcharging.AFRRSignalResponse{Status: types.GenericStatusRejected}, true},
+		{smartcharging.AFRRSignalResponse{}, false},
+		{smartcharging.AFRRSignalResponse{Status: "invalidStatus"}, false},
+		{smartcharging.AFRRSignalResponse{Status: types.GenericStatusAccepted, StatusInfo: types.NewStatusInfo("", "")}, false},
+	}
+	ExecuteGenericTestTable(t, confirmationTable)
+}
+
+func (suite *OcppV2TestSuite) TestAFRRSignalE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	timestamp := types.NewDateTime(time.Now())
+	signal := -1
+	status := types.GenericStatusAccepted
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"timestamp":"%v","signal":%v}]`,
+		messageId, smartcharging.AFRRSignalFeatureName, timestamp.FormatTimestamp(), signal)
+	responseJson := fmt.Sprintf(`[3,"%v",{"status":"%v"}]`, messageId, status)
+	afrrSignalResponse := smartcharging.NewAFRRSignalResponse(status)
+	channel := NewMockWebSocket(wsId)
+
+	handler := &MockChargingStationSmartChargingHandler{}
+	handler.On("OnAFRRSignal", mock.Anything).Return(afrrSignalResponse, nil).Run(func(args mock.Arguments) {
+		request, ok := args.Get(0).(*smartcharging.AFRRSignalRequest)
+		require.True(t, ok)
+		require.NotNil(t, request)
+		assert.Equal(t, timestamp.FormatTimestamp(), request.Timestamp.FormatTimestamp())
+		assert.Equal(t, signal, request.Signal)
+	})
+	setupDefaultCSMSHandlers(suite, expectedCSMSOptions{clientId: wsId, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	setupDefaultChargingStationHandlers(suite, expectedChargingStationOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(responseJson), forwardWrittenMessage: true}, handler)
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	err := suite.chargingStation.Start(wsUrl)
+	require.Nil(t, err)
+	resultChannel := make(chan bool, 1)
+	err = suite.csms.AFRRSignal(wsId, func(response *smartcharging.AFRRSignalResponse, err error) {
+		require.Nil(t, err)
+		require.NotNil(t, response)
+		assert.Equal(t, status, response.Status)
+		resultChannel <- true
+	}, *timestamp, signal)
+	require.Nil(t, err)
+	result := <-resultChannel
+	assert.True(t, result)
+}
+
+func (suite *OcppV2TestSuite) TestAFRRSignalInvalidEndpoint() {
+	messageId := defaultMessageId
+	timestamp := types.NewDateTime(time.Now())
+	signal := 1
+	request := smartcharging.NewAFRRSignalRequest(*timestamp, signal)
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"timestamp":"%v","signal":%v}]`,
+		messageId, smartcharging.AFRRSignalFeatureName, timestamp.FormatTimestamp(), signal)
+	testUnsupportedRequestFromChargingStation(suite, request, requestJson, messageId)
+}
@@ -0,0 +1,92 @@
+package ocpp21_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/batteryswap"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+)
+
+// Test
+func (suite *OcppV2TestSuite) TestBatterySwapRequestValidation() {
+	t := suite.T()
+	idToken := types.IdToken{IdToken: "1234", Type: types.IdTokenTypeKeyCode}
+	battery := batteryswap.BatteryData{EvseID: 1, SerialNumber: "serial1", SoC: 80.0, SoH: 95.0}
+	var requestTable = []GenericTestEntry{
+		{batteryswap.BatterySwapRequest{BatteryData: []batteryswap.BatteryData{{EvseID: 1, SerialNumber: "serial1", SoC: 80.0, SoH: 95.0, ProductionDate: types.NewDateTime(time.Now()), VendorInfo: "someInfo"}}, EventType: batteryswap.BatterySwapEventBatteryIn, IdToken: idToken, RequestID: 1}, true},
+		{batteryswap.BatterySwapRequest{BatteryData: []batteryswap.BatteryData{battery}, EventType: batteryswap.BatterySwapEventBatteryOutTimeout, IdToken: idToken}, true},
+		{batteryswap.BatterySwapRequest{BatteryData: []batteryswap.BatteryData{}, EventType: batteryswap.BatterySwapEventBatteryIn, IdToken: idToken}, false},
+		{batteryswap.BatterySwapRequest{EventType: batteryswap.BatterySwapEventBatteryIn, IdToken: idToken}, false},
+		{batteryswap.BatterySwapRequest{BatteryData: []batteryswap.BatteryData{battery}, IdToken: idToken}, false},
+		{batteryswap.BatterySwapRequest{BatteryData: []batteryswap.BatteryData{battery}, EventType: "invalidEvent", IdToken: idToken}, false},
+		{batteryswap.BatterySwapRequest{BatteryData: []batteryswap.BatteryData{battery}, EventType: batteryswap.BatterySwapEventBatteryIn, IdToken: types.IdToken{IdToken: "1234", Type: ">20.................."}}, false},
+		{batteryswap.BatterySwapRequest{BatteryData: []batteryswap.BatteryData{{EvseID: -1, SerialNumber: "serial1"}}, EventType: batteryswap.BatterySwapEventBatteryIn, IdToken: idToken}, false},
+		{batteryswap.BatterySwapRequest{BatteryData: []batteryswap.BatteryData{{EvseID: 1}}, EventType: batteryswap.BatterySwapEventBatteryIn, IdToken: idToken}, false},
+		{batteryswap.BatterySwapRequest{BatteryData: []batteryswap.BatteryData{{EvseID: 1, SerialNumber: "serial1", SoC: 101.0}}, EventType: batteryswap.BatterySwapEventBatteryIn, IdToken: idToken}, false},
+		{batteryswap.BatterySwapRequest{BatteryData: []batteryswap.BatteryData{{EvseID: 1, SerialNumber: "serial1", SoH: -1.0}}, EventType: batteryswap.BatterySwapEventBatteryIn, IdToken: idToken}, false},
+	}
+	ExecuteGenericTestTable(t, requestTable)
+}
+
+func (suite *OcppV2TestSuite) TestBatterySwapResponseValidation() {
+	t := suite.T()
+	var confirmationTable = []GenericTestEntry{
+		{batteryswap.BatterySwapResponse{}, true},
+	}
+	ExecuteGenericTestTable(t, confirmationTable)
+}
+
+func (suite *OcppV2TestSuite) TestBatterySwapE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	battery := batteryswap.BatteryData{EvseID: 1, SerialNumber: "serial1", SoC: 80.5, SoH: 95.5}
+	eventType := batteryswap.BatterySwapEventBatteryOut
+	idToken := types.IdToken{IdToken: "1234", Type: types.IdTokenTypeKeyCode}
+	requestID := 42
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"batteryData":[{"evseId":%v,"serialNumber":"%v","soC":%v,"soH":%v}],"eventType":"%v","idToken":{"idToken":"%v","type":"%v"},"requestId":%v}]`,
+		messageId, batteryswap.BatterySwapFeatureName, battery.EvseID, battery.SerialNumber, battery.SoC, battery.SoH, eventType, idToken.IdToken, idToken.Type, requestID)
+	responseJson := fmt.Sprintf(`[3,"%v",{}]`, messageId)
+	batterySwapResponse := batteryswap.NewBatterySwapResponse()
+	channel := NewMockWebSocket(wsId)
+
+	handler := &MockCSMSBatterySwapHandler{}
+	handler.On("OnBatterySwap", mock.AnythingOfType("string"), mock.Anything).Return(batterySwapResponse, nil).Run(func(args mock.Arguments) {
+		request, ok := args.Get(1).(*batteryswap.BatterySwapRequest)
+		require.True(t, ok)
+		require.NotNil(t, request)
+		require.Len(t, request.BatteryData, 1)
+		assert.Equal(t, battery, request.BatteryData[0])
+		assert.Equal(t, eventType, request.EventType)
+		assert.Equal(t, idToken.IdToken, request.IdToken.IdToken)
+		assert.Equal(t, idToken.Type, request.IdToken.Type)
+		assert.Equal(t, requestID, request.RequestID)
+	})
+	setupDefaultCSMSHandlers(suite, expectedCSMSOptions{clientId: wsId, rawWrittenMessage: []byte(responseJson), forwardWrittenMessage: true}, handler)
+	setupDefaultChargingStationHandlers(suite, expectedChargingStationOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	err := suite.chargingStation.Start(wsUrl)
+	require.Nil(t, err)
+	response, err := suite.chargingStation.BatterySwap([]batteryswap.BatteryData{battery}, eventType, idToken, requestID)
+	require.Nil(t, err)
+	require.NotNil(t, response)
+}
+
+func (suite *OcppV2TestSuite) TestBatterySwapInvalidEndpoint() {
+	messageId := defaultMessageId
+	battery := batteryswap.BatteryData{EvseID: 1, SerialNumber: "serial1", SoC: 80.5, SoH: 95.5}
+	eventType := batteryswap.BatterySwapEventBatteryIn
+	idToken := types.IdToken{IdToken: "1234", Type: types.IdTokenTypeKeyCode}
+	requestID := 42
+	request := batteryswap.NewBatterySwapRequest([]batteryswap.BatteryData{battery}, eventType, idToken, requestID)
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"batteryData":[{"evseId":%v,"serialNumber":"%v","soC":%v,"soH":%v}],"eventType":"%v","idToken":{"idToken":"%v","type":"%v"},"requestId":%v}]`,
+		messageId, batteryswap.BatterySwapFeatureName, battery.EvseID, battery.SerialNumber, battery.SoC, battery.SoH, eventType, idToken.IdToken, idToken.Type, requestID)
+	testUnsupportedRequestFromCentralSystem(suite, request, requestJson, messageId)
+}
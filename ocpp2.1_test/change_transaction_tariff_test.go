@@ -0,0 +1,90 @@
+package ocpp21_test
+
+import (
+	"fmt"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/tariffcost"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+)
+
+// Test
+func (suite *OcppV2TestSuite) TestChangeTransactionTariffRequestValidation() {
+	t := suite.T()
+	idleTime := &tariffcost.TariffTime{Prices: []tariffcost.TariffTimePrice{{PriceMinute: 0.1, Conditions: &tariffcost.TariffConditions{MinIdleTime: newFloat(600)}}}}
+	var requestTable = []GenericTestEntry{
+		{tariffcost.ChangeTransactionTariffRequest{TransactionID: "1234", Tariff: tariffcost.Tariff{TariffID: "tariff1", Currency: "EUR", IdleTime: idleTime}}, true},
+		{tariffcost.ChangeTransactionTariffRequest{TransactionID: "1234", Tariff: tariffcost.Tariff{TariffID: "tariff1", Currency: "EUR"}}, true},
+		{tariffcost.ChangeTransactionTariffRequest{Tariff: tariffcost.Tariff{TariffID: "tariff1", Currency: "EUR"}}, false},
+		{tariffcost.ChangeTransactionTariffRequest{TransactionID: "1234"}, false},
+		{tariffcost.ChangeTransactionTariffRequest{}, false},
+		{tariffcost.ChangeTransactionTariffRequest{TransactionID: ">36..................................", Tariff: tariffcost.Tariff{TariffID: "tariff1", Currency: "EUR"}}, false},
+		{tariffcost.ChangeTransactionTariffRequest{TransactionID: "1234", Tariff: tariffcost.Tariff{TariffID: "tariff1", Currency: "EUR", IdleTime: &tariffcost.TariffTime{}}}, false},
+	}
+	ExecuteGenericTestTable(t, requestTable)
+}
+
+func (suite *OcppV2TestSuite) TestChangeTransactionTariffResponseValidation() {
+	t := suite.T()
+	var confirmationTable = []GenericTestEntry{
+		{tariffcost.ChangeTransactionTariffResponse{Status: tariffcost.TariffChangeStatusAccepted, StatusInfo: types.NewStatusInfo("200", "")}, true},
+		{tariffcost.ChangeTransactionTariffResponse{Status: tariffcost.TariffChangeStatusNoCurrencyChange}, true},
+		{tariffcost.ChangeTransactionTariffResponse{Status: tariffcost.TariffChangeStatusTxNotFound}, true},
+		{tariffcost.ChangeTransactionTariffResponse{}, false},
+		{tariffcost.ChangeTransactionTariffResponse{Status: "invalidStatus"}, false},
+	}
+	ExecuteGenericTestTable(t, confirmationTable)
+}
+
+func (suite *OcppV2TestSuite) TestChangeTransactionTariffE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	transactionID := "1234"
+	tariff := tariffcost.NewTariff("tariff1", "EUR")
+	status := tariffcost.TariffChangeStatusAccepted
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"tariff":{"tariffId":"%v","currency":"%v"},"transactionId":"%v"}]`,
+		messageId, tariffcost.ChangeTransactionTariffFeatureName, tariff.TariffID, tariff.Currency, transactionID)
+	responseJson := fmt.Sprintf(`[3,"%v",{"status":"%v"}]`, messageId, status)
+	changeTransactionTariffResponse := tariffcost.NewChangeTransactionTariffResponse(status)
+	channel := NewMockWebSocket(wsId)
+
+	handler := &MockChargingStationTariffCostHandler{}
+	handler.On("OnChangeTransactionTariff", mock.Anything).Return(changeTransactionTariffResponse, nil).Run(func(args mock.Arguments) {
+		request, ok := args.Get(0).(*tariffcost.ChangeTransactionTariffRequest)
+		require.True(t, ok)
+		require.NotNil(t, request)
+		assert.Equal(t, transactionID, request.TransactionID)
+		assert.Equal(t, tariff, request.Tariff)
+	})
+	setupDefaultCSMSHandlers(suite, expectedCSMSOptions{clientId: wsId, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	setupDefaultChargingStationHandlers(suite, expectedChargingStationOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(responseJson), forwardWrittenMessage: true}, handler)
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	err := suite.chargingStation.Start(wsUrl)
+	require.Nil(t, err)
+	resultChannel := make(chan bool, 1)
+	err = suite.csms.ChangeTransactionTariff(wsId, func(response *tariffcost.ChangeTransactionTariffResponse, err error) {
+		require.Nil(t, err)
+		require.NotNil(t, response)
+		assert.Equal(t, status, response.Status)
+		resultChannel <- true
+	}, transactionID, tariff)
+	require.Nil(t, err)
+	result := <-resultChannel
+	assert.True(t, result)
+}
+
+func (suite *OcppV2TestSuite) TestChangeTransactionTariffInvalidEndpoint() {
+	messageId := defaultMessageId
+	transactionID := "1234"
+	tariff := tariffcost.NewTariff("tariff1", "EUR")
+	request := tariffcost.NewChangeTransactionTariffRequest(transactionID, tariff)
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"tariff":{"tariffId":"%v","currency":"%v"},"transactionId":"%v"}]`,
+		messageId, tariffcost.ChangeTransactionTariffFeatureName, tariff.TariffID, tariff.Currency, transactionID)
+	testUnsupportedRequestFromChargingStation(suite, request, requestJson, messageId)
+}
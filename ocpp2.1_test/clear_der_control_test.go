@@ -0,0 +1,88 @@
+package ocpp21_test
+
+import (
+	"fmt"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/der"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+)
+
+// Test
+func (suite *OcppV2TestSuite) TestClearDERControlRequestValidation() {
+	t := suite.T()
+	var requestTable = []GenericTestEntry{
+		{der.ClearDERControlRequest{IsDefault: true, ControlType: der.DERControlHVMustTrip, ControlID: "control1"}, true},
+		{der.ClearDERControlRequest{IsDefault: false, ControlType: der.DERControlLVMayTrip}, true},
+		{der.ClearDERControlRequest{IsDefault: true}, true},
+		{der.ClearDERControlRequest{}, true},
+		{der.ClearDERControlRequest{IsDefault: true, ControlType: "invalidControlType"}, false},
+		{der.ClearDERControlRequest{IsDefault: true, ControlID: ">36.................................."}, false},
+	}
+	ExecuteGenericTestTable(t, requestTable)
+}
+
+func (suite *OcppV2TestSuite) TestClearDERControlResponseValidation() {
+	t := suite.T()
+	var confirmationTable = []GenericTestEntry{
+		{der.ClearDERControlResponse{Status: der.DERControlStatusAccepted, StatusInfo: types.NewStatusInfo("200", "")}, true},
+		{der.ClearDERControlResponse{Status: der.DERControlStatusNotFound}, true},
+		{der.ClearDERControlResponse{}, false},
+		{der.ClearDERControlResponse{Status: "invalidStatus"}, false},
+		{der.ClearDERControlResponse{Status: der.DERControlStatusAccepted, StatusInfo: types.NewStatusInfo("", "")}, false},
+	}
+	ExecuteGenericTestTable(t, confirmationTable)
+}
+
+func (suite *OcppV2TestSuite) TestClearDERControlE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	isDefault := true
+	controlID := "control1"
+	status := der.DERControlStatusAccepted
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"isDefault":%v,"controlId":"%v"}]`,
+		messageId, der.ClearDERControlFeatureName, isDefault, controlID)
+	responseJson := fmt.Sprintf(`[3,"%v",{"status":"%v"}]`, messageId, status)
+	clearDERControlResponse := der.NewClearDERControlResponse(status)
+	channel := NewMockWebSocket(wsId)
+
+	handler := &MockChargingStationDERControlHandler{}
+	handler.On("OnClearDERControl", mock.Anything).Return(clearDERControlResponse, nil).Run(func(args mock.Arguments) {
+		request, ok := args.Get(0).(*der.ClearDERControlRequest)
+		require.True(t, ok)
+		require.NotNil(t, request)
+		assert.Equal(t, isDefault, request.IsDefault)
+		assert.Equal(t, controlID, request.ControlID)
+	})
+	setupDefaultCSMSHandlers(suite, expectedCSMSOptions{clientId: wsId, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	setupDefaultChargingStationHandlers(suite, expectedChargingStationOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(responseJson), forwardWrittenMessage: true}, handler)
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	err := suite.chargingStation.Start(wsUrl)
+	require.Nil(t, err)
+	resultChannel := make(chan bool, 1)
+	err = suite.csms.ClearDERControl(wsId, func(response *der.ClearDERControlResponse, err error) {
+		require.Nil(t, err)
+		require.NotNil(t, response)
+		assert.Equal(t, status, response.Status)
+		resultChannel <- true
+	}, isDefault, func(request *der.ClearDERControlRequest) {
+		request.ControlID = controlID
+	})
+	require.Nil(t, err)
+	result := <-resultChannel
+	assert.True(t, result)
+}
+
+func (suite *OcppV2TestSuite) TestClearDERControlInvalidEndpoint() {
+	messageId := defaultMessageId
+	isDefault := false
+	request := der.NewClearDERControlRequest(isDefault)
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"isDefault":%v}]`, messageId, der.ClearDERControlFeatureName, isDefault)
+	testUnsupportedRequestFromChargingStation(suite, request, requestJson, messageId)
+}
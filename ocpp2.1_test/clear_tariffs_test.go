@@ -0,0 +1,91 @@
+package ocpp21_test
+
+import (
+	"fmt"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/tariffcost"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+)
+
+// Test
+func (suite *OcppV2TestSuite) TestClearTariffsRequestValidation() {
+	t := suite.T()
+	var requestTable = []GenericTestEntry{
+		{tariffcost.ClearTariffsRequest{TariffIDs: []string{"tariff1", "tariff2"}, EvseID: newInt(1)}, true},
+		{tariffcost.ClearTariffsRequest{TariffIDs: []string{"tariff1"}}, true},
+		{tariffcost.ClearTariffsRequest{EvseID: newInt(0)}, true},
+		{tariffcost.ClearTariffsRequest{}, true},
+		{tariffcost.ClearTariffsRequest{EvseID: newInt(-1)}, false},
+		{tariffcost.ClearTariffsRequest{TariffIDs: []string{">60..........................................................."}}, false},
+	}
+	ExecuteGenericTestTable(t, requestTable)
+}
+
+func (suite *OcppV2TestSuite) TestClearTariffsResponseValidation() {
+	t := suite.T()
+	var confirmationTable = []GenericTestEntry{
+		{tariffcost.ClearTariffsResponse{ClearTariffsResult: []tariffcost.ClearTariffsResult{{TariffID: "tariff1", Status: tariffcost.TariffClearStatusAccepted}}}, true},
+		{tariffcost.ClearTariffsResponse{ClearTariffsResult: []tariffcost.ClearTariffsResult{{Status: tariffcost.TariffClearStatusNoTariff, StatusInfo: types.NewStatusInfo("200", "")}}}, true},
+		{tariffcost.ClearTariffsResponse{ClearTariffsResult: []tariffcost.ClearTariffsResult{}}, false},
+		{tariffcost.ClearTariffsResponse{}, false},
+		{tariffcost.ClearTariffsResponse{ClearTariffsResult: []tariffcost.ClearTariffsResult{{TariffID: "tariff1"}}}, false},
+		{tariffcost.ClearTariffsResponse{ClearTariffsResult: []tariffcost.ClearTariffsResult{{TariffID: "tariff1", Status: "invalidStatus"}}}, false},
+	}
+	ExecuteGenericTestTable(t, confirmationTable)
+}
+
+func (suite *OcppV2TestSuite) TestClearTariffsE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	tariffID := "tariff1"
+	evseID := 1
+	status := tariffcost.TariffClearStatusAccepted
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"tariffIds":["%v"],"evseId":%v}]`, messageId, tariffcost.ClearTariffsFeatureName, tariffID, evseID)
+	responseJson := fmt.Sprintf(`[3,"%v",{"clearTariffsResult":[{"tariffId":"%v","status":"%v"}]}]`, messageId, tariffID, status)
+	clearTariffsResponse := tariffcost.NewClearTariffsResponse([]tariffcost.ClearTariffsResult{{TariffID: tariffID, Status: status}})
+	channel := NewMockWebSocket(wsId)
+
+	handler := &MockChargingStationTariffCostHandler{}
+	handler.On("OnClearTariffs", mock.Anything).Return(clearTariffsResponse, nil).Run(func(args mock.Arguments) {
+		request, ok := args.Get(0).(*tariffcost.ClearTariffsRequest)
+		require.True(t, ok)
+		require.NotNil(t, request)
+		assert.Equal(t, []string{tariffID}, request.TariffIDs)
+		require.NotNil(t, request.EvseID)
+		assert.Equal(t, evseID, *request.EvseID)
+	})
+	setupDefaultCSMSHandlers(suite, expectedCSMSOptions{clientId: wsId, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	setupDefaultChargingStationHandlers(suite, expectedChargingStationOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(responseJson), forwardWrittenMessage: true}, handler)
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	err := suite.chargingStation.Start(wsUrl)
+	require.Nil(t, err)
+	resultChannel := make(chan bool, 1)
+	err = suite.csms.ClearTariffs(wsId, func(response *tariffcost.ClearTariffsResponse, err error) {
+		require.Nil(t, err)
+		require.NotNil(t, response)
+		require.Len(t, response.ClearTariffsResult, 1)
+		assert.Equal(t, tariffID, response.ClearTariffsResult[0].TariffID)
+		assert.Equal(t, status, response.ClearTariffsResult[0].Status)
+		resultChannel <- true
+	}, func(request *tariffcost.ClearTariffsRequest) {
+		request.TariffIDs = []string{tariffID}
+		request.EvseID = newInt(evseID)
+	})
+	require.Nil(t, err)
+	result := <-resultChannel
+	assert.True(t, result)
+}
+
+func (suite *OcppV2TestSuite) TestClearTariffsInvalidEndpoint() {
+	messageId := defaultMessageId
+	request := tariffcost.NewClearTariffsRequest()
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{}]`, messageId, tariffcost.ClearTariffsFeatureName)
+	testUnsupportedRequestFromChargingStation(suite, request, requestJson, messageId)
+}
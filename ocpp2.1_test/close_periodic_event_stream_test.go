@@ -0,0 +1,67 @@
+package ocpp21_test
+
+import (
+	"fmt"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/diagnostics"
+)
+
+// Test
+func (suite *OcppV2TestSuite) TestClosePeriodicEventStreamRequestValidation() {
+	t := suite.T()
+	var requestTable = []GenericTestEntry{
+		{diagnostics.ClosePeriodicEventStreamRequest{ID: 1}, true},
+		{diagnostics.ClosePeriodicEventStreamRequest{}, true},
+		{diagnostics.ClosePeriodicEventStreamRequest{ID: -1}, false},
+	}
+	ExecuteGenericTestTable(t, requestTable)
+}
+
+func (suite *OcppV2TestSuite) TestClosePeriodicEventStreamResponseValidation() {
+	t := suite.T()
+	var confirmationTable = []GenericTestEntry{
+		{diagnostics.ClosePeriodicEventStreamResponse{}, true},
+	}
+	ExecuteGenericTestTable(t, confirmationTable)
+}
+
+func (suite *OcppV2TestSuite) TestClosePeriodicEventStreamE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	id := 1
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"id":%v}]`, messageId, diagnostics.ClosePeriodicEventStreamFeatureName, id)
+	responseJson := fmt.Sprintf(`[3,"%v",{}]`, messageId)
+	closePeriodicEventStreamResponse := diagnostics.NewClosePeriodicEventStreamResponse()
+	channel := NewMockWebSocket(wsId)
+
+	handler := &MockCSMSDiagnosticsHandler{}
+	handler.On("OnClosePeriodicEventStream", mock.AnythingOfType("string"), mock.Anything).Return(closePeriodicEventStreamResponse, nil).Run(func(args mock.Arguments) {
+		request, ok := args.Get(1).(*diagnostics.ClosePeriodicEventStreamRequest)
+		require.True(t, ok)
+		require.NotNil(t, request)
+		assert.Equal(t, id, request.ID)
+	})
+	setupDefaultCSMSHandlers(suite, expectedCSMSOptions{clientId: wsId, rawWrittenMessage: []byte(responseJson), forwardWrittenMessage: true}, handler)
+	setupDefaultChargingStationHandlers(suite, expectedChargingStationOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	err := suite.chargingStation.Start(wsUrl)
+	require.Nil(t, err)
+	response, err := suite.chargingStation.ClosePeriodicEventStream(id)
+	require.Nil(t, err)
+	require.NotNil(t, response)
+}
+
+func (suite *OcppV2TestSuite) TestClosePeriodicEventStreamInvalidEndpoint() {
+	messageId := defaultMessageId
+	id := 1
+	request := diagnostics.NewClosePeriodicEventStreamRequest(id)
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"id":%v}]`, messageId, diagnostics.ClosePeriodicEventStreamFeatureName, id)
+	testUnsupportedRequestFromCentralSystem(suite, request, requestJson, messageId)
+}
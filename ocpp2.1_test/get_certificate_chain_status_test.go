@@ -0,0 +1,105 @@
+package ocpp21_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/iso15118"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+)
+
+// Test
+func (suite *OcppV2TestSuite) TestGetCertificateChainStatusRequestValidation() {
+	t := suite.T()
+	hashData := types.CertificateHashData{HashAlgorithm: types.SHA256, IssuerNameHash: "hash00", IssuerKeyHash: "hash01", SerialNumber: "serial0"}
+	info := iso15118.CertificateStatusRequestInfo{CertificateHashData: hashData, Source: iso15118.CertificateStatusSourceOCSP, Urls: []string{"http://ocsp"}}
+	var requestTable = []GenericTestEntry{
+		{iso15118.GetCertificateChainStatusRequest{CertificateStatusRequests: []iso15118.CertificateStatusRequestInfo{info}}, true},
+		{iso15118.GetCertificateChainStatusRequest{CertificateStatusRequests: []iso15118.CertificateStatusRequestInfo{info, info, info, info}}, true},
+		{iso15118.GetCertificateChainStatusRequest{CertificateStatusRequests: []iso15118.CertificateStatusRequestInfo{{CertificateHashData: hashData, Source: iso15118.CertificateStatusSourceCRL, Urls: []string{"http://crl1", "http://crl2"}}}}, true},
+		{iso15118.GetCertificateChainStatusRequest{CertificateStatusRequests: []iso15118.CertificateStatusRequestInfo{info, info, info, info, info}}, false},
+		{iso15118.GetCertificateChainStatusRequest{CertificateStatusRequests: []iso15118.CertificateStatusRequestInfo{}}, false},
+		{iso15118.GetCertificateChainStatusRequest{}, false},
+		{iso15118.GetCertificateChainStatusRequest{CertificateStatusRequests: []iso15118.CertificateStatusRequestInfo{{CertificateHashData: hashData, Source: "invalidSource", Urls: []string{"http://ocsp"}}}}, false},
+		{iso15118.GetCertificateChainStatusRequest{CertificateStatusRequests: []iso15118.CertificateStatusRequestInfo{{CertificateHashData: hashData, Source: iso15118.CertificateStatusSourceOCSP}}}, false},
+		{iso15118.GetCertificateChainStatusRequest{CertificateStatusRequests: []iso15118.CertificateStatusRequestInfo{{CertificateHashData: types.CertificateHashData{HashAlgorithm: "invalidHashAlgorithm", IssuerNameHash: "hash00", IssuerKeyHash: "hash01", SerialNumber: "serial0"}, Source: iso15118.CertificateStatusSourceOCSP, Urls: []string{"http://ocsp"}}}}, false},
+	}
+	ExecuteGenericTestTable(t, requestTable)
+}
+
+func (suite *OcppV2TestSuite) TestGetCertificateChainStatusResponseValidation() {
+	t := suite.T()
+	hashData := types.CertificateHashData{HashAlgorithm: types.SHA256, IssuerNameHash: "hash00", IssuerKeyHash: "hash01", SerialNumber: "serial0"}
+	nextUpdate := *types.NewDateTime(time.Now())
+	info := iso15118.CertificateStatusInfo{CertificateHashData: hashData, Source: iso15118.CertificateStatusSourceOCSP, Status: iso15118.CertificateRevocationStatusGood, NextUpdate: nextUpdate}
+	var confirmationTable = []GenericTestEntry{
+		{iso15118.GetCertificateChainStatusResponse{CertificateStatus: []iso15118.CertificateStatusInfo{info}}, true},
+		{iso15118.GetCertificateChainStatusResponse{CertificateStatus: []iso15118.CertificateStatusInfo{{CertificateHashData: hashData, Source: iso15118.CertificateStatusSourceCRL, Status: iso15118.CertificateRevocationStatusRevoked, NextUpdate: nextUpdate}}}, true},
+		{iso15118.GetCertificateChainStatusResponse{CertificateStatus: []iso15118.CertificateStatusInfo{{CertificateHashData: hashData, Source: iso15118.CertificateStatusSourceOCSP, Status: iso15118.CertificateRevocationStatusUnknown, NextUpdate: nextUpdate}}}, true},
+		{iso15118.GetCertificateChainStatusResponse{CertificateStatus: []iso15118.CertificateStatusInfo{{CertificateHashData: hashData, Source: iso15118.CertificateStatusSourceOCSP, Status: iso15118.CertificateRevocationStatusFailed, NextUpdate: nextUpdate}}}, true},
+		{iso15118.GetCertificateChainStatusResponse{CertificateStatus: []iso15118.CertificateStatusInfo{info, info, info, info, info}}, false},
+		{iso15118.GetCertificateChainStatusResponse{CertificateStatus: []iso15118.CertificateStatusInfo{}}, false},
+		{iso15118.GetCertificateChainStatusResponse{}, false},
+		{iso15118.GetCertificateChainStatusResponse{CertificateStatus: []iso15118.CertificateStatusInfo{{CertificateHashData: hashData, Source: iso15118.CertificateStatusSourceOCSP, Status: "invalidStatus", NextUpdate: nextUpdate}}}, false},
+		{iso15118.GetCertificateChainStatusResponse{CertificateStatus: []iso15118.CertificateStatusInfo{{CertificateHashData: hashData, Source: "invalidSource", Status: iso15118.CertificateRevocationStatusGood, NextUpdate: nextUpdate}}}, false},
+	}
+	ExecuteGenericTestTable(t, confirmationTable)
+}
+
+func (suite *OcppV2TestSuite) TestGetCertificateChainStatusE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	hashData := types.CertificateHashData{HashAlgorithm: types.SHA256, IssuerNameHash: "hash00", IssuerKeyHash: "hash01", SerialNumber: "serial0"}
+	source := iso15118.CertificateStatusSourceOCSP
+	url := "http://ocsp"
+	status := iso15118.CertificateRevocationStatusGood
+	nextUpdate := types.NewDateTime(time.Now())
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"certificateStatusRequests":[{"certificateHashData":{"hashAlgorithm":"%v","issuerNameHash":"%v","issuerKeyHash":"%v","serialNumber":"%v"},"source":"%v","urls":["%v"]}]}]`,
+		messageId, iso15118.GetCertificateChainStatusFeatureName, hashData.HashAlgorithm, hashData.IssuerNameHash, hashData.IssuerKeyHash, hashData.SerialNumber, source, url)
+	responseJson := fmt.Sprintf(`[3,"%v",{"certificateStatus":[{"certificateHashData":{"hashAlgorithm":"%v","issuerNameHash":"%v","issuerKeyHash":"%v","serialNumber":"%v"},"source":"%v","status":"%v","nextUpdate":"%v"}]}]`,
+		messageId, hashData.HashAlgorithm, hashData.IssuerNameHash, hashData.IssuerKeyHash, hashData.SerialNumber, source, status, nextUpdate.FormatTimestamp())
+	getCertificateChainStatusResponse := iso15118.NewGetCertificateChainStatusResponse([]iso15118.CertificateStatusInfo{{CertificateHashData: hashData, Source: source, Status: status, NextUpdate: *nextUpdate}})
+	channel := NewMockWebSocket(wsId)
+
+	handler := &MockCSMSIso15118Handler{}
+	handler.On("OnGetCertificateChainStatus", mock.AnythingOfType("string"), mock.Anything).Return(getCertificateChainStatusResponse, nil).Run(func(args mock.Arguments) {
+		request, ok := args.Get(1).(*iso15118.GetCertificateChainStatusRequest)
+		require.True(t, ok)
+		require.NotNil(t, request)
+		require.Len(t, request.CertificateStatusRequests, 1)
+		assert.Equal(t, hashData, request.CertificateStatusRequests[0].CertificateHashData)
+		assert.Equal(t, source, request.CertificateStatusRequests[0].Source)
+		assert.Equal(t, []string{url}, request.CertificateStatusRequests[0].Urls)
+	})
+	setupDefaultCSMSHandlers(suite, expectedCSMSOptions{clientId: wsId, rawWrittenMessage: []byte(responseJson), forwardWrittenMessage: true}, handler)
+	setupDefaultChargingStationHandlers(suite, expectedChargingStationOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	err := suite.chargingStation.Start(wsUrl)
+	require.Nil(t, err)
+	response, err := suite.chargingStation.GetCertificateChainStatus([]iso15118.CertificateStatusRequestInfo{{CertificateHashData: hashData, Source: source, Urls: []string{url}}})
+	require.Nil(t, err)
+	require.NotNil(t, response)
+	require.Len(t, response.CertificateStatus, 1)
+	assert.Equal(t, hashData, response.CertificateStatus[0].CertificateHashData)
+	assert.Equal(t, source, response.CertificateStatus[0].Source)
+	assert.Equal(t, status, response.CertificateStatus[0].Status)
+	assert.Equal(t, nextUpdate.FormatTimestamp(), response.CertificateStatus[0].NextUpdate.FormatTimestamp())
+}
+
+func (suite *OcppV2TestSuite) TestGetCertificateChainStatusInvalidEndpoint() {
+	messageId := defaultMessageId
+	hashData := types.CertificateHashData{HashAlgorithm: types.SHA256, IssuerNameHash: "hash00", IssuerKeyHash: "hash01", SerialNumber: "serial0"}
+	source := iso15118.CertificateStatusSourceCRL
+	url := "http://crl"
+	request := iso15118.NewGetCertificateChainStatusRequest([]iso15118.CertificateStatusRequestInfo{{CertificateHashData: hashData, Source: source, Urls: []string{url}}})
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"certificateStatusRequests":[{"certificateHashData":{"hashAlgorithm":"%v","issuerNameHash":"%v","issuerKeyHash":"%v","serialNumber":"%v"},"source":"%v","urls":["%v"]}]}]`,
+		messageId, iso15118.GetCertificateChainStatusFeatureName, hashData.HashAlgorithm, hashData.IssuerNameHash, hashData.IssuerKeyHash, hashData.SerialNumber, source, url)
+	testUnsupportedRequestFromCentralSystem(suite, request, requestJson, messageId)
+}
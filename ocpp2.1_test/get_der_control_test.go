@@ -0,0 +1,92 @@
+package ocpp21_test
+
+import (
+	"fmt"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/der"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+)
+
+// Test
+func (suite *OcppV2TestSuite) TestGetDERControlRequestValidation() {
+	t := suite.T()
+	var requestTable = []GenericTestEntry{
+		{der.GetDERControlRequest{RequestID: 1, IsDefault: newBool(true), ControlType: der.DERControlVoltVar, ControlID: "control1"}, true},
+		{der.GetDERControlRequest{RequestID: 1, ControlType: der.DERControlFreqDroop}, true},
+		{der.GetDERControlRequest{RequestID: 1}, true},
+		{der.GetDERControlRequest{}, true},
+		{der.GetDERControlRequest{RequestID: 1, ControlType: "invalidControlType"}, false},
+		{der.GetDERControlRequest{RequestID: 1, ControlID: ">36.................................."}, false},
+	}
+	ExecuteGenericTestTable(t, requestTable)
+}
+
+func (suite *OcppV2TestSuite) TestGetDERControlResponseValidation() {
+	t := suite.T()
+	var confirmationTable = []GenericTestEntry{
+		{der.GetDERControlResponse{Status: der.DERControlStatusAccepted, StatusInfo: types.NewStatusInfo("200", "")}, true},
+		{der.GetDERControlResponse{Status: der.DERControlStatusNotFound}, true},
+		{der.GetDERControlResponse{}, false},
+		{der.GetDERControlResponse{Status: "invalidStatus"}, false},
+		{der.GetDERControlResponse{Status: der.DERControlStatusAccepted, StatusInfo: types.NewStatusInfo("", "")}, false},
+	}
+	ExecuteGenericTestTable(t, confirmationTable)
+}
+
+func (suite *OcppV2TestSuite) TestGetDERControlE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	requestID := 42
+	isDefault := false
+	controlType := der.DERControlVoltWatt
+	status := der.DERControlStatusAccepted
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"requestId":%v,"isDefault":%v,"controlType":"%v"}]`,
+		messageId, der.GetDERControlFeatureName, requestID, isDefault, controlType)
+	responseJson := fmt.Sprintf(`[3,"%v",{"status":"%v"}]`, messageId, status)
+	getDERControlResponse := der.NewGetDERControlResponse(status)
+	channel := NewMockWebSocket(wsId)
+
+	handler := &MockChargingStationDERControlHandler{}
+	handler.On("OnGetDERControl", mock.Anything).Return(getDERControlResponse, nil).Run(func(args mock.Arguments) {
+		request, ok := args.Get(0).(*der.GetDERControlRequest)
+		require.True(t, ok)
+		require.NotNil(t, request)
+		assert.Equal(t, requestID, request.RequestID)
+		require.NotNil(t, request.IsDefault)
+		assert.Equal(t, isDefault, *request.IsDefault)
+		assert.Equal(t, controlType, request.ControlType)
+	})
+	setupDefaultCSMSHandlers(suite, expectedCSMSOptions{clientId: wsId, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	setupDefaultChargingStationHandlers(suite, expectedChargingStationOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(responseJson), forwardWrittenMessage: true}, handler)
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	err := suite.chargingStation.Start(wsUrl)
+	require.Nil(t, err)
+	resultChannel := make(chan bool, 1)
+	err = suite.csms.GetDERControl(wsId, func(response *der.GetDERControlResponse, err error) {
+		require.Nil(t, err)
+		require.NotNil(t, response)
+		assert.Equal(t, status, response.Status)
+		resultChannel <- true
+	}, requestID, func(request *der.GetDERControlRequest) {
+		request.IsDefault = newBool(isDefault)
+		request.ControlType = controlType
+	})
+	require.Nil(t, err)
+	result := <-resultChannel
+	assert.True(t, result)
+}
+
+func (suite *OcppV2TestSuite) TestGetDERControlInvalidEndpoint() {
+	messageId := defaultMessageId
+	requestID := 42
+	request := der.NewGetDERControlRequest(requestID)
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"requestId":%v}]`, messageId, der.GetDERControlFeatureName, requestID)
+	testUnsupportedRequestFromChargingStation(suite, request, requestJson, messageId)
+}
@@ -0,0 +1,77 @@
+package ocpp21_test
+
+import (
+	"fmt"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/diagnostics"
+)
+
+// Test
+func (suite *OcppV2TestSuite) TestGetPeriodicEventStreamRequestValidation() {
+	t := suite.T()
+	var requestTable = []GenericTestEntry{
+		{diagnostics.GetPeriodicEventStreamRequest{}, true},
+	}
+	ExecuteGenericTestTable(t, requestTable)
+}
+
+func (suite *OcppV2TestSuite) TestGetPeriodicEventStreamResponseValidation() {
+	t := suite.T()
+	var confirmationTable = []GenericTestEntry{
+		{diagnostics.GetPeriodicEventStreamResponse{ConstantStreamData: []diagnostics.ConstantStreamData{{ID: 1, Params: diagnostics.PeriodicEventStreamParams{Interval: newInt(60)}, VariableMonitoringID: 2}}}, true},
+		{diagnostics.GetPeriodicEventStreamResponse{ConstantStreamData: []diagnostics.ConstantStreamData{}}, true},
+		{diagnostics.GetPeriodicEventStreamResponse{}, true},
+		{diagnostics.GetPeriodicEventStreamResponse{ConstantStreamData: []diagnostics.ConstantStreamData{{ID: -1, VariableMonitoringID: 2}}}, false},
+		{diagnostics.GetPeriodicEventStreamResponse{ConstantStreamData: []diagnostics.ConstantStreamData{{ID: 1, Params: diagnostics.PeriodicEventStreamParams{Values: newInt(-1)}, VariableMonitoringID: 2}}}, false},
+	}
+	ExecuteGenericTestTable(t, confirmationTable)
+}
+
+func (suite *OcppV2TestSuite) TestGetPeriodicEventStreamE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	streamData := diagnostics.ConstantStreamData{ID: 1, Params: diagnostics.PeriodicEventStreamParams{Interval: newInt(60)}, VariableMonitoringID: 2}
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{}]`, messageId, diagnostics.GetPeriodicEventStreamFeatureName)
+	responseJson := fmt.Sprintf(`[3,"%v",{"constantStreamData":[{"id":%v,"params":{"interval":%v},"variableMonitoringId":%v}]}]`,
+		messageId, streamData.ID, *streamData.Params.Interval, streamData.VariableMonitoringID)
+	getPeriodicEventStreamResponse := diagnostics.NewGetPeriodicEventStreamResponse()
+	getPeriodicEventStreamResponse.ConstantStreamData = []diagnostics.ConstantStreamData{streamData}
+	channel := NewMockWebSocket(wsId)
+
+	handler := &MockChargingStationDiagnosticsHandler{}
+	handler.On("OnGetPeriodicEventStream", mock.Anything).Return(getPeriodicEventStreamResponse, nil).Run(func(args mock.Arguments) {
+		request, ok := args.Get(0).(*diagnostics.GetPeriodicEventStreamRequest)
+		require.True(t, ok)
+		require.NotNil(t, request)
+	})
+	setupDefaultCSMSHandlers(suite, expectedCSMSOptions{clientId: wsId, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	setupDefaultChargingStationHandlers(suite, expectedChargingStationOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(responseJson), forwardWrittenMessage: true}, handler)
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	err := suite.chargingStation.Start(wsUrl)
+	require.Nil(t, err)
+	resultChannel := make(chan bool, 1)
+	err = suite.csms.GetPeriodicEventStream(wsId, func(response *diagnostics.GetPeriodicEventStreamResponse, err error) {
+		require.Nil(t, err)
+		require.NotNil(t, response)
+		require.Len(t, response.ConstantStreamData, 1)
+		assert.Equal(t, streamData, response.ConstantStreamData[0])
+		resultChannel <- true
+	})
+	require.Nil(t, err)
+	result := <-resultChannel
+	assert.True(t, result)
+}
+
+func (suite *OcppV2TestSuite) TestGetPeriodicEventStreamInvalidEndpoint() {
+	messageId := defaultMessageId
+	request := diagnostics.NewGetPeriodicEventStreamRequest()
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{}]`, messageId, diagnostics.GetPeriodicEventStreamFeatureName)
+	testUnsupportedRequestFromChargingStation(suite, request, requestJson, messageId)
+}
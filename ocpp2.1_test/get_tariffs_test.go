@@ -0,0 +1,88 @@
+package ocpp21_test
+
+import (
+	"fmt"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/tariffcost"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+)
+
+// Test
+func (suite *OcppV2TestSuite) TestGetTariffsRequestValidation() {
+	t := suite.T()
+	var requestTable = []GenericTestEntry{
+		{tariffcost.GetTariffsRequest{EvseID: 1}, true},
+		{tariffcost.GetTariffsRequest{}, true},
+		{tariffcost.GetTariffsRequest{EvseID: -1}, false},
+	}
+	ExecuteGenericTestTable(t, requestTable)
+}
+
+func (suite *OcppV2TestSuite) TestGetTariffsResponseValidation() {
+	t := suite.T()
+	var confirmationTable = []GenericTestEntry{
+		{tariffcost.GetTariffsResponse{Status: tariffcost.TariffGetStatusAccepted, TariffAssignments: []tariffcost.TariffAssignment{{TariffID: "tariff1", TariffKind: tariffcost.TariffKindDefaultTariff, EvseIDs: []int{1, 2}}}}, true},
+		{tariffcost.GetTariffsResponse{Status: tariffcost.TariffGetStatusAccepted, TariffAssignments: []tariffcost.TariffAssignment{{TariffID: "tariff1", TariffKind: tariffcost.TariffKindDriverTariff, IdTokens: []string{"token1"}}}}, true},
+		{tariffcost.GetTariffsResponse{Status: tariffcost.TariffGetStatusNoTariff, StatusInfo: types.NewStatusInfo("200", "")}, true},
+		{tariffcost.GetTariffsResponse{}, false},
+		{tariffcost.GetTariffsResponse{Status: "invalidStatus"}, false},
+		{tariffcost.GetTariffsResponse{Status: tariffcost.TariffGetStatusAccepted, TariffAssignments: []tariffcost.TariffAssignment{{TariffKind: tariffcost.TariffKindDefaultTariff}}}, false},
+		{tariffcost.GetTariffsResponse{Status: tariffcost.TariffGetStatusAccepted, TariffAssignments: []tariffcost.TariffAssignment{{TariffID: "tariff1", TariffKind: "invalidKind"}}}, false},
+		{tariffcost.GetTariffsResponse{Status: tariffcost.TariffGetStatusAccepted, TariffAssignments: []tariffcost.TariffAssignment{{TariffID: "tariff1", TariffKind: tariffcost.TariffKindDefaultTariff, EvseIDs: []int{-1}}}}, false},
+	}
+	ExecuteGenericTestTable(t, confirmationTable)
+}
+
+func (suite *OcppV2TestSuite) TestGetTariffsE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	evseID := 1
+	status := tariffcost.TariffGetStatusAccepted
+	assignment := tariffcost.TariffAssignment{TariffID: "tariff1", TariffKind: tariffcost.TariffKindDefaultTariff, EvseIDs: []int{1}}
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"evseId":%v}]`, messageId, tariffcost.GetTariffsFeatureName, evseID)
+	responseJson := fmt.Sprintf(`[3,"%v",{"status":"%v","tariffAssignments":[{"tariffId":"%v","tariffKind":"%v","evseIds":[%v]}]}]`,
+		messageId, status, assignment.TariffID, assignment.TariffKind, assignment.EvseIDs[0])
+	getTariffsResponse := tariffcost.NewGetTariffsResponse(status)
+	getTariffsResponse.TariffAssignments = []tariffcost.TariffAssignment{assignment}
+	channel := NewMockWebSocket(wsId)
+
+	handler := &MockChargingStationTariffCostHandler{}
+	handler.On("OnGetTariffs", mock.Anything).Return(getTariffsResponse, nil).Run(func(args mock.Arguments) {
+		request, ok := args.Get(0).(*tariffcost.GetTariffsRequest)
+		require.True(t, ok)
+		require.NotNil(t, request)
+		assert.Equal(t, evseID, request.EvseID)
+	})
+	setupDefaultCSMSHandlers(suite, expectedCSMSOptions{clientId: wsId, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	setupDefaultChargingStationHandlers(suite, expectedChargingStationOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(responseJson), forwardWrittenMessage: true}, handler)
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	err := suite.chargingStation.Start(wsUrl)
+	require.Nil(t, err)
+	resultChannel := make(chan bool, 1)
+	err = suite.csms.GetTariffs(wsId, func(response *tariffcost.GetTariffsResponse, err error) {
+		require.Nil(t, err)
+		require.NotNil(t, response)
+		assert.Equal(t, status, response.Status)
+		require.Len(t, response.TariffAssignments, 1)
+		assert.Equal(t, assignment, response.TariffAssignments[0])
+		resultChannel <- true
+	}, evseID)
+	require.Nil(t, err)
+	result := <-resultChannel
+	assert.True(t, result)
+}
+
+func (suite *OcppV2TestSuite) TestGetTariffsInvalidEndpoint() {
+	messageId := defaultMessageId
+	evseID := 1
+	request := tariffcost.NewGetTariffsRequest(evseID)
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"evseId":%v}]`, messageId, tariffcost.GetTariffsFeatureName, evseID)
+	testUnsupportedRequestFromChargingStation(suite, request, requestJson, messageId)
+}
@@ -0,0 +1,89 @@
+package ocpp21_test
+
+import (
+	"fmt"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/smartcharging"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+)
+
+// Test
+func (suite *OcppV2TestSuite) TestNotifyAllowedEnergyTransferRequestValidation() {
+	t := suite.T()
+	var requestTable = []GenericTestEntry{
+		{smartcharging.NotifyAllowedEnergyTransferRequest{TransactionID: "1234", AllowedEnergyTransfer: []smartcharging.EnergyTransferMode{smartcharging.EnergyTransferModeACBPT, smartcharging.EnergyTransferModeDCBPT}}, true},
+		{smartcharging.NotifyAllowedEnergyTransferRequest{TransactionID: "1234", AllowedEnergyTransfer: []smartcharging.EnergyTransferMode{smartcharging.EnergyTransferModeWPT}}, true},
+		{smartcharging.NotifyAllowedEnergyTransferRequest{TransactionID: "1234", AllowedEnergyTransfer: []smartcharging.EnergyTransferMode{}}, false},
+		{smartcharging.NotifyAllowedEnergyTransferRequest{TransactionID: "1234"}, false},
+		{smartcharging.NotifyAllowedEnergyTransferRequest{AllowedEnergyTransfer: []smartcharging.EnergyTransferMode{smartcharging.EnergyTransferModeDC}}, false},
+		{smartcharging.NotifyAllowedEnergyTransferRequest{}, false},
+		{smartcharging.NotifyAllowedEnergyTransferRequest{TransactionID: ">36..................................", AllowedEnergyTransfer: []smartcharging.EnergyTransferMode{smartcharging.EnergyTransferModeDC}}, false},
+		{smartcharging.NotifyAllowedEnergyTransferRequest{TransactionID: "1234", AllowedEnergyTransfer: []smartcharging.EnergyTransferMode{"invalidMode"}}, false},
+	}
+	ExecuteGenericTestTable(t, requestTable)
+}
+
+func (suite *OcppV2TestSuite) TestNotifyAllowedEnergyTransferResponseValidation() {
+	t := suite.T()
+	var confirmationTable = []GenericTestEntry{
+		{smartcharging.NotifyAllowedEnergyTransferResponse{Status: smartcharging.NotifyAllowedEnergyTransferStatusAccepted, StatusInfo: types.NewStatusInfo("200", "")}, true},
+		{smartcharging.NotifyAllowedEnergyTransferResponse{Status: smartcharging.NotifyAllowedEnergyTransferStatusRejected}, true},
+		{smartcharging.NotifyAllowedEnergyTransferResponse{}, false},
+		{smartcharging.NotifyAllowedEnergyTransferResponse{Status: "invalidStatus"}, false},
+		{smartcharging.NotifyAllowedEnergyTransferResponse{Status: smartcharging.NotifyAllowedEnergyTransferStatusAccepted, StatusInfo: types.NewStatusInfo("", "")}, false},
+	}
+	ExecuteGenericTestTable(t, confirmationTable)
+}
+
+func (suite *OcppV2TestSuite) TestNotifyAllowedEnergyTransferE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	transactionID := "1234"
+	allowedEnergyTransfer := []smartcharging.EnergyTransferMode{smartcharging.EnergyTransferModeACBPT, smartcharging.EnergyTransferModeAC3Phase}
+	status := smartcharging.NotifyAllowedEnergyTransferStatusAccepted
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"transactionId":"%v","allowedEnergyTransfer":["%v","%v"]}]`,
+		messageId, smartcharging.NotifyAllowedEnergyTransferFeatureName, transactionID, allowedEnergyTransfer[0], allowedEnergyTransfer[1])
+	responseJson := fmt.Sprintf(`[3,"%v",{"status":"%v"}]`, messageId, status)
+	notifyAllowedEnergyTransferResponse := smartcharging.NewNotifyAllowedEnergyTransferResponse(status)
+	channel := NewMockWebSocket(wsId)
+
+	handler := &MockChargingStationSmartChargingHandler{}
+	handler.On("OnNotifyAllowedEnergyTransfer", mock.Anything).Return(notifyAllowedEnergyTransferResponse, nil).Run(func(args mock.Arguments) {
+		request, ok := args.Get(0).(*smartcharging.NotifyAllowedEnergyTransferRequest)
+		require.True(t, ok)
+		require.NotNil(t, request)
+		assert.Equal(t, transactionID, request.TransactionID)
+		assert.Equal(t, allowedEnergyTransfer, request.AllowedEnergyTransfer)
+	})
+	setupDefaultCSMSHandlers(suite, expectedCSMSOptions{clientId: wsId, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	setupDefaultChargingStationHandlers(suite, expectedChargingStationOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(responseJson), forwardWrittenMessage: true}, handler)
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	err := suite.chargingStation.Start(wsUrl)
+	require.Nil(t, err)
+	resultChannel := make(chan bool, 1)
+	err = suite.csms.NotifyAllowedEnergyTransfer(wsId, func(response *smartcharging.NotifyAllowedEnergyTransferResponse, err error) {
+		require.Nil(t, err)
+		require.NotNil(t, response)
+		assert.Equal(t, status, response.Status)
+		resultChannel <- true
+	}, transactionID, allowedEnergyTransfer)
+	require.Nil(t, err)
+	result := <-resultChannel
+	assert.True(t, result)
+}
+
+func (suite *OcppV2TestSuite) TestNotifyAllowedEnergyTransferInvalidEndpoint() {
+	messageId := defaultMessageId
+	transactionID := "1234"
+	request := smartcharging.NewNotifyAllowedEnergyTransferRequest(transactionID, smartcharging.EnergyTransferModeDCBPT)
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"transactionId":"%v","allowedEnergyTransfer":["%v"]}]`,
+		messageId, smartcharging.NotifyAllowedEnergyTransferFeatureName, transactionID, smartcharging.EnergyTransferModeDCBPT)
+	testUnsupportedRequestFromChargingStation(suite, request, requestJson, messageId)
+}
@@ -0,0 +1,82 @@
+package ocpp21_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/der"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+)
+
+// Test
+func (suite *OcppV2TestSuite) TestNotifyDERAlarmRequestValidation() {
+	t := suite.T()
+	var requestTable = []GenericTestEntry{
+		{der.NotifyDERAlarmRequest{ControlType: der.DERControlHVMustTrip, GridEventFault: der.GridEventFaultOverVoltage, AlarmEnded: true, Timestamp: *types.NewDateTime(time.Now()), ExtraInfo: "someInfo"}, true},
+		{der.NotifyDERAlarmRequest{ControlType: der.DERControlLFMustTrip, Timestamp: *types.NewDateTime(time.Now())}, true},
+		{der.NotifyDERAlarmRequest{Timestamp: *types.NewDateTime(time.Now())}, false},
+		{der.NotifyDERAlarmRequest{ControlType: "invalidControlType", Timestamp: *types.NewDateTime(time.Now())}, false},
+		{der.NotifyDERAlarmRequest{ControlType: der.DERControlHVMustTrip, GridEventFault: "invalidFault", Timestamp: *types.NewDateTime(time.Now())}, false},
+		{der.NotifyDERAlarmRequest{ControlType: der.DERControlHVMustTrip, Timestamp: *types.NewDateTime(time.Now()), ExtraInfo: ">200....................................................................................................................................................................................................."}, false},
+	}
+	ExecuteGenericTestTable(t, requestTable)
+}
+
+func (suite *OcppV2TestSuite) TestNotifyDERAlarmResponseValidation() {
+	t := suite.T()
+	var confirmationTable = []GenericTestEntry{
+		{der.NotifyDERAlarmResponse{}, true},
+	}
+	ExecuteGenericTestTable(t, confirmationTable)
+}
+
+func (suite *OcppV2TestSuite) TestNotifyDERAlarmE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	controlType := der.DERControlHVMustTrip
+	gridEventFault := der.GridEventFaultOverVoltage
+	timestamp := types.NewDateTime(time.Now())
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"controlType":"%v","gridEventFault":"%v","timestamp":"%v"}]`,
+		messageId, der.NotifyDERAlarmFeatureName, controlType, gridEventFault, timestamp.FormatTimestamp())
+	responseJson := fmt.Sprintf(`[3,"%v",{}]`, messageId)
+	notifyDERAlarmResponse := der.NewNotifyDERAlarmResponse()
+	channel := NewMockWebSocket(wsId)
+
+	handler := &MockCSMSDERControlHandler{}
+	handler.On("OnNotifyDERAlarm", mock.AnythingOfType("string"), mock.Anything).Return(notifyDERAlarmResponse, nil).Run(func(args mock.Arguments) {
+		request, ok := args.Get(1).(*der.NotifyDERAlarmRequest)
+		require.True(t, ok)
+		require.NotNil(t, request)
+		assert.Equal(t, controlType, request.ControlType)
+		assert.Equal(t, gridEventFault, request.GridEventFault)
+		assert.False(t, request.AlarmEnded)
+		assert.Equal(t, timestamp.FormatTimestamp(), request.Timestamp.FormatTimestamp())
+	})
+	setupDefaultCSMSHandlers(suite, expectedCSMSOptions{clientId: wsId, rawWrittenMessage: []byte(responseJson), forwardWrittenMessage: true}, handler)
+	setupDefaultChargingStationHandlers(suite, expectedChargingStationOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	err := suite.chargingStation.Start(wsUrl)
+	require.Nil(t, err)
+	response, err := suite.chargingStation.NotifyDERAlarm(controlType, *timestamp, func(request *der.NotifyDERAlarmRequest) {
+		request.GridEventFault = gridEventFault
+	})
+	require.Nil(t, err)
+	require.NotNil(t, response)
+}
+
+func (suite *OcppV2TestSuite) TestNotifyDERAlarmInvalidEndpoint() {
+	messageId := defaultMessageId
+	controlType := der.DERControlHVMustTrip
+	timestamp := types.NewDateTime(time.Now())
+	request := der.NewNotifyDERAlarmRequest(controlType, *timestamp)
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"controlType":"%v","timestamp":"%v"}]`,
+		messageId, der.NotifyDERAlarmFeatureName, controlType, timestamp.FormatTimestamp())
+	testUnsupportedRequestFromCentralSystem(suite, request, requestJson, messageId)
+}
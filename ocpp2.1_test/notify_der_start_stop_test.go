@@ -0,0 +1,83 @@
+package ocpp21_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/der"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+)
+
+// Test
+func (suite *OcppV2TestSuite) TestNotifyDERStartStopRequestValidation() {
+	t := suite.T()
+	var requestTable = []GenericTestEntry{
+		{der.NotifyDERStartStopRequest{ControlID: "control1", Started: true, Timestamp: *types.NewDateTime(time.Now()), SupersededIDs: []string{"control0"}}, true},
+		{der.NotifyDERStartStopRequest{ControlID: "control1", Timestamp: *types.NewDateTime(time.Now())}, true},
+		{der.NotifyDERStartStopRequest{Started: true, Timestamp: *types.NewDateTime(time.Now())}, false},
+		{der.NotifyDERStartStopRequest{ControlID: ">36..................................", Timestamp: *types.NewDateTime(time.Now())}, false},
+		{der.NotifyDERStartStopRequest{ControlID: "control1", Timestamp: *types.NewDateTime(time.Now()), SupersededIDs: []string{">36.................................."}}, false},
+	}
+	ExecuteGenericTestTable(t, requestTable)
+}
+
+func (suite *OcppV2TestSuite) TestNotifyDERStartStopResponseValidation() {
+	t := suite.T()
+	var confirmationTable = []GenericTestEntry{
+		{der.NotifyDERStartStopResponse{}, true},
+	}
+	ExecuteGenericTestTable(t, confirmationTable)
+}
+
+func (suite *OcppV2TestSuite) TestNotifyDERStartStopE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	controlID := "control1"
+	started := true
+	timestamp := types.NewDateTime(time.Now())
+	supersededID := "control0"
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"controlId":"%v","started":%v,"timestamp":"%v","supersededIds":["%v"]}]`,
+		messageId, der.NotifyDERStartStopFeatureName, controlID, started, timestamp.FormatTimestamp(), supersededID)
+	responseJson := fmt.Sprintf(`[3,"%v",{}]`, messageId)
+	notifyDERStartStopResponse := der.NewNotifyDERStartStopResponse()
+	channel := NewMockWebSocket(wsId)
+
+	handler := &MockCSMSDERControlHandler{}
+	handler.On("OnNotifyDERStartStop", mock.AnythingOfType("string"), mock.Anything).Return(notifyDERStartStopResponse, nil).Run(func(args mock.Arguments) {
+		request, ok := args.Get(1).(*der.NotifyDERStartStopRequest)
+		require.True(t, ok)
+		require.NotNil(t, request)
+		assert.Equal(t, controlID, request.ControlID)
+		assert.Equal(t, started, request.Started)
+		assert.Equal(t, timestamp.FormatTimestamp(), request.Timestamp.FormatTimestamp())
+		assert.Equal(t, []string{supersededID}, request.SupersededIDs)
+	})
+	setupDefaultCSMSHandlers(suite, expectedCSMSOptions{clientId: wsId, rawWrittenMessage: []byte(responseJson), forwardWrittenMessage: true}, handler)
+	setupDefaultChargingStationHandlers(suite, expectedChargingStationOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	err := suite.chargingStation.Start(wsUrl)
+	require.Nil(t, err)
+	response, err := suite.chargingStation.NotifyDERStartStop(controlID, started, *timestamp, func(request *der.NotifyDERStartStopRequest) {
+		request.SupersededIDs = []string{supersededID}
+	})
+	require.Nil(t, err)
+	require.NotNil(t, response)
+}
+
+func (suite *OcppV2TestSuite) TestNotifyDERStartStopInvalidEndpoint() {
+	messageId := defaultMessageId
+	controlID := "control1"
+	started := false
+	timestamp := types.NewDateTime(time.Now())
+	request := der.NewNotifyDERStartStopRequest(controlID, started, *timestamp)
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"controlId":"%v","started":%v,"timestamp":"%v"}]`,
+		messageId, der.NotifyDERStartStopFeatureName, controlID, started, timestamp.FormatTimestamp())
+	testUnsupportedRequestFromCentralSystem(suite, request, requestJson, messageId)
+}
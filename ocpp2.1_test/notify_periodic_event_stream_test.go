@@ -0,0 +1,84 @@
+package ocpp21_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/diagnostics"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+)
+
+// Test
+func (suite *OcppV2TestSuite) TestNotifyPeriodicEventStreamRequestValidation() {
+	t := suite.T()
+	basetime := *types.NewDateTime(time.Now())
+	data := []diagnostics.StreamDataElement{{T: 0.5, V: "230.1"}}
+	var requestTable = []GenericTestEntry{
+		{diagnostics.NotifyPeriodicEventStreamRequest{ID: 1, Pending: 2, Basetime: basetime, Data: data}, true},
+		{diagnostics.NotifyPeriodicEventStreamRequest{Basetime: basetime, Data: data}, true},
+		{diagnostics.NotifyPeriodicEventStreamRequest{ID: 1, Pending: 2, Basetime: basetime}, false},
+		{diagnostics.NotifyPeriodicEventStreamRequest{ID: 1, Pending: 2, Basetime: basetime, Data: []diagnostics.StreamDataElement{}}, false},
+		{diagnostics.NotifyPeriodicEventStreamRequest{ID: -1, Pending: 2, Basetime: basetime, Data: data}, false},
+		{diagnostics.NotifyPeriodicEventStreamRequest{ID: 1, Pending: -1, Basetime: basetime, Data: data}, false},
+		{diagnostics.NotifyPeriodicEventStreamRequest{ID: 1, Pending: 2, Basetime: basetime, Data: []diagnostics.StreamDataElement{{T: 0.5}}}, false},
+	}
+	ExecuteGenericTestTable(t, requestTable)
+}
+
+func (suite *OcppV2TestSuite) TestNotifyPeriodicEventStreamE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	id := 1
+	pending := 2
+	basetime := types.NewDateTime(time.Now())
+	element := diagnostics.StreamDataElement{T: 0.5, V: "230.1"}
+	requestJson := fmt.Sprintf(`[6,"%v","%v",{"id":%v,"pending":%v,"basetime":"%v","data":[{"t":%v,"v":"%v"}]}]`,
+		messageId, diagnostics.NotifyPeriodicEventStreamFeatureName, id, pending, basetime.FormatTimestamp(), element.T, element.V)
+	channel := NewMockWebSocket(wsId)
+
+	resultChannel := make(chan bool, 1)
+	handler := &MockCSMSDiagnosticsHandler{}
+	handler.On("OnNotifyPeriodicEventStream", mock.AnythingOfType("string"), mock.Anything).Run(func(args mock.Arguments) {
+		request, ok := args.Get(1).(*diagnostics.NotifyPeriodicEventStreamRequest)
+		require.True(t, ok)
+		require.NotNil(t, request)
+		assert.Equal(t, id, request.ID)
+		assert.Equal(t, pending, request.Pending)
+		assert.Equal(t, basetime.FormatTimestamp(), request.Basetime.FormatTimestamp())
+		assert.Equal(t, []diagnostics.StreamDataElement{element}, request.Data)
+		resultChannel <- true
+	})
+	setupDefaultCSMSHandlers(suite, expectedCSMSOptions{clientId: wsId, forwardWrittenMessage: true}, handler)
+	setupDefaultChargingStationHandlers(suite, expectedChargingStationOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	err := suite.chargingStation.Start(wsUrl)
+	require.Nil(t, err)
+	err = suite.chargingStation.NotifyPeriodicEventStream(id, pending, *basetime, []diagnostics.StreamDataElement{element})
+	require.Nil(t, err)
+	result := <-resultChannel
+	assert.True(t, result)
+	// Unconfirmed requests are never replied to
+	suite.mockWsServer.AssertNotCalled(t, "Write", mock.Anything, mock.Anything)
+}
+
+func (suite *OcppV2TestSuite) TestNotifyPeriodicEventStreamInvalidEndpoint() {
+	t := suite.T()
+	wsId := "test_id"
+	basetime := types.NewDateTime(time.Now())
+	request := diagnostics.NewNotifyPeriodicEventStreamRequest(1, 0, *basetime, []diagnostics.StreamDataElement{{T: 0.5, V: "230.1"}})
+	setupDefaultCSMSHandlers(suite, expectedCSMSOptions{clientId: wsId})
+	suite.csms.Start(8887, "somePath")
+	err := suite.csms.SendRequestAsync(wsId, request, func(response ocpp.Response, err error) {
+		t.Fail()
+	})
+	require.Error(t, err)
+	assert.Equal(t, fmt.Sprintf("unsupported action %v on CSMS, cannot send request", request.GetFeatureName()), err.Error())
+}
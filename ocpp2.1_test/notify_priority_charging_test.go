@@ -0,0 +1,73 @@
+package ocpp21_test
+
+import (
+	"fmt"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/smartcharging"
+)
+
+// Test
+func (suite *OcppV2TestSuite) TestNotifyPriorityChargingRequestValidation() {
+	t := suite.T()
+	var requestTable = []GenericTestEntry{
+		{smartcharging.NotifyPriorityChargingRequest{TransactionID: "1234", Activated: true}, true},
+		{smartcharging.NotifyPriorityChargingRequest{TransactionID: "1234"}, true},
+		{smartcharging.NotifyPriorityChargingRequest{Activated: true}, false},
+		{smartcharging.NotifyPriorityChargingRequest{TransactionID: ">36..................................", Activated: true}, false},
+	}
+	ExecuteGenericTestTable(t, requestTable)
+}
+
+func (suite *OcppV2TestSuite) TestNotifyPriorityChargingResponseValidation() {
+	t := suite.T()
+	var confirmationTable = []GenericTestEntry{
+		{smartcharging.NotifyPriorityChargingResponse{}, true},
+	}
+	ExecuteGenericTestTable(t, confirmationTable)
+}
+
+func (suite *OcppV2TestSuite) TestNotifyPriorityChargingE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	transactionID := "1234"
+	activated := true
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"transactionId":"%v","activated":%v}]`,
+		messageId, smartcharging.NotifyPriorityChargingFeatureName, transactionID, activated)
+	responseJson := fmt.Sprintf(`[3,"%v",{}]`, messageId)
+	notifyPriorityChargingResponse := smartcharging.NewNotifyPriorityChargingResponse()
+	channel := NewMockWebSocket(wsId)
+
+	handler := &MockCSMSSmartChargingHandler{}
+	handler.On("OnNotifyPriorityCharging", mock.AnythingOfType("string"), mock.Anything).Return(notifyPriorityChargingResponse, nil).Run(func(args mock.Arguments) {
+		request, ok := args.Get(1).(*smartcharging.NotifyPriorityChargingRequest)
+		require.True(t, ok)
+		require.NotNil(t, request)
+		assert.Equal(t, transactionID, request.TransactionID)
+		assert.Equal(t, activated, request.Activated)
+	})
+	setupDefaultCSMSHandlers(suite, expectedCSMSOptions{clientId: wsId, rawWrittenMessage: []byte(responseJson), forwardWrittenMessage: true}, handler)
+	setupDefaultChargingStationHandlers(suite, expectedChargingStationOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	err := suite.chargingStation.Start(wsUrl)
+	require.Nil(t, err)
+	response, err := suite.chargingStation.NotifyPriorityCharging(transactionID, activated)
+	require.Nil(t, err)
+	require.NotNil(t, response)
+}
+
+func (suite *OcppV2TestSuite) TestNotifyPriorityChargingInvalidEndpoint() {
+	messageId := defaultMessageId
+	transactionID := "1234"
+	activated := false
+	request := smartcharging.NewNotifyPriorityChargingRequest(transactionID, activated)
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"transactionId":"%v","activated":%v}]`,
+		messageId, smartcharging.NotifyPriorityChargingFeatureName, transactionID, activated)
+	testUnsupportedRequestFromCentralSystem(suite, request, requestJson, messageId)
+}
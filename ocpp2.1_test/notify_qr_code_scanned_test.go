@@ -0,0 +1,72 @@
+package ocpp21_test
+
+import (
+	"fmt"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/payment"
+)
+
+// Test
+func (suite *OcppV2TestSuite) TestNotifyQRCodeScannedRequestValidation() {
+	t := suite.T()
+	var requestTable = []GenericTestEntry{
+		{payment.NotifyQRCodeScannedRequest{EvseID: 1, Timeout: 60}, true},
+		{payment.NotifyQRCodeScannedRequest{}, true},
+		{payment.NotifyQRCodeScannedRequest{EvseID: -1, Timeout: 60}, false},
+	}
+	ExecuteGenericTestTable(t, requestTable)
+}
+
+func (suite *OcppV2TestSuite) TestNotifyQRCodeScannedResponseValidation() {
+	t := suite.T()
+	var confirmationTable = []GenericTestEntry{
+		{payment.NotifyQRCodeScannedResponse{}, true},
+	}
+	ExecuteGenericTestTable(t, confirmationTable)
+}
+
+func (suite *OcppV2TestSuite) TestNotifyQRCodeScannedE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	evseID := 1
+	timeout := 60
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"evseId":%v,"timeout":%v}]`,
+		messageId, payment.NotifyQRCodeScannedFeatureName, evseID, timeout)
+	responseJson := fmt.Sprintf(`[3,"%v",{}]`, messageId)
+	notifyQRCodeScannedResponse := payment.NewNotifyQRCodeScannedResponse()
+	channel := NewMockWebSocket(wsId)
+
+	handler := &MockCSMSPaymentHandler{}
+	handler.On("OnNotifyQRCodeScanned", mock.AnythingOfType("string"), mock.Anything).Return(notifyQRCodeScannedResponse, nil).Run(func(args mock.Arguments) {
+		request, ok := args.Get(1).(*payment.NotifyQRCodeScannedRequest)
+		require.True(t, ok)
+		require.NotNil(t, request)
+		assert.Equal(t, evseID, request.EvseID)
+		assert.Equal(t, timeout, request.Timeout)
+	})
+	setupDefaultCSMSHandlers(suite, expectedCSMSOptions{clientId: wsId, rawWrittenMessage: []byte(responseJson), forwardWrittenMessage: true}, handler)
+	setupDefaultChargingStationHandlers(suite, expectedChargingStationOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	err := suite.chargingStation.Start(wsUrl)
+	require.Nil(t, err)
+	response, err := suite.chargingStation.NotifyQRCodeScanned(evseID, timeout)
+	require.Nil(t, err)
+	require.NotNil(t, response)
+}
+
+func (suite *OcppV2TestSuite) TestNotifyQRCodeScannedInvalidEndpoint() {
+	messageId := defaultMessageId
+	evseID := 1
+	timeout := 60
+	request := payment.NewNotifyQRCodeScannedRequest(evseID, timeout)
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"evseId":%v,"timeout":%v}]`,
+		messageId, payment.NotifyQRCodeScannedFeatureName, evseID, timeout)
+	testUnsupportedRequestFromCentralSystem(suite, request, requestJson, messageId)
+}
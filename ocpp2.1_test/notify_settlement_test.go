@@ -0,0 +1,99 @@
+package ocpp21_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/payment"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+)
+
+// Test
+func (suite *OcppV2TestSuite) TestNotifySettlementRequestValidation() {
+	t := suite.T()
+	settlementTime := *types.NewDateTime(time.Now())
+	company := &payment.Address{Name: "company", Address1: "street 1", City: "city", Country: "country"}
+	var requestTable = []GenericTestEntry{
+		{payment.NotifySettlementRequest{TransactionID: "1234", PspRef: "psp1", Status: payment.PaymentStatusSettled, StatusInfo: "info", SettlementAmount: 12.5, SettlementTime: settlementTime, ReceiptID: "receipt1", ReceiptURL: "http://receipt", VatCompany: company, VatNumber: "NL123"}, true},
+		{payment.NotifySettlementRequest{PspRef: "psp1", Status: payment.PaymentStatusFailed, SettlementTime: settlementTime}, true},
+		{payment.NotifySettlementRequest{PspRef: "psp1", Status: payment.PaymentStatusCanceled, SettlementTime: settlementTime}, true},
+		{payment.NotifySettlementRequest{PspRef: "psp1", Status: payment.PaymentStatusRejected, SettlementTime: settlementTime}, true},
+		{payment.NotifySettlementRequest{Status: payment.PaymentStatusSettled, SettlementTime: settlementTime}, false},
+		{payment.NotifySettlementRequest{PspRef: "psp1", SettlementTime: settlementTime}, false},
+		{payment.NotifySettlementRequest{PspRef: "psp1", Status: "invalidStatus", SettlementTime: settlementTime}, false},
+		{payment.NotifySettlementRequest{TransactionID: ">36..................................", PspRef: "psp1", Status: payment.PaymentStatusSettled, SettlementTime: settlementTime}, false},
+		{payment.NotifySettlementRequest{PspRef: "psp1", Status: payment.PaymentStatusSettled, SettlementTime: settlementTime, ReceiptID: ">50................................................"}, false},
+		{payment.NotifySettlementRequest{PspRef: "psp1", Status: payment.PaymentStatusSettled, SettlementTime: settlementTime, VatNumber: ">20.................."}, false},
+		{payment.NotifySettlementRequest{PspRef: "psp1", Status: payment.PaymentStatusSettled, SettlementTime: settlementTime, VatCompany: &payment.Address{Name: "company"}}, false},
+	}
+	ExecuteGenericTestTable(t, requestTable)
+}
+
+func (suite *OcppV2TestSuite) TestNotifySettlementResponseValidation() {
+	t := suite.T()
+	var confirmationTable = []GenericTestEntry{
+		{payment.NotifySettlementResponse{ReceiptURL: "http://receipt", ReceiptID: "receipt1"}, true},
+		{payment.NotifySettlementResponse{}, true},
+		{payment.NotifySettlementResponse{ReceiptID: ">50................................................"}, false},
+	}
+	ExecuteGenericTestTable(t, confirmationTable)
+}
+
+func (suite *OcppV2TestSuite) TestNotifySettlementE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	pspRef := "psp1"
+	status := payment.PaymentStatusSettled
+	settlementAmount := 12.5
+	settlementTime := types.NewDateTime(time.Now())
+	transactionID := "1234"
+	receiptURL := "http://receipt"
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"transactionId":"%v","pspRef":"%v","status":"%v","settlementAmount":%v,"settlementTime":"%v"}]`,
+		messageId, payment.NotifySettlementFeatureName, transactionID, pspRef, status, settlementAmount, settlementTime.FormatTimestamp())
+	responseJson := fmt.Sprintf(`[3,"%v",{"receiptUrl":"%v"}]`, messageId, receiptURL)
+	notifySettlementResponse := payment.NewNotifySettlementResponse()
+	notifySettlementResponse.ReceiptURL = receiptURL
+	channel := NewMockWebSocket(wsId)
+
+	handler := &MockCSMSPaymentHandler{}
+	handler.On("OnNotifySettlement", mock.AnythingOfType("string"), mock.Anything).Return(notifySettlementResponse, nil).Run(func(args mock.Arguments) {
+		request, ok := args.Get(1).(*payment.NotifySettlementRequest)
+		require.True(t, ok)
+		require.NotNil(t, request)
+		assert.Equal(t, transactionID, request.TransactionID)
+		assert.Equal(t, pspRef, request.PspRef)
+		assert.Equal(t, status, request.Status)
+		assert.Equal(t, settlementAmount, request.SettlementAmount)
+		assert.Equal(t, settlementTime.FormatTimestamp(), request.SettlementTime.FormatTimestamp())
+	})
+	setupDefaultCSMSHandlers(suite, expectedCSMSOptions{clientId: wsId, rawWrittenMessage: []byte(responseJson), forwardWrittenMessage: true}, handler)
+	setupDefaultChargingStationHandlers(suite, expectedChargingStationOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	err := suite.chargingStation.Start(wsUrl)
+	require.Nil(t, err)
+	response, err := suite.chargingStation.NotifySettlement(pspRef, status, settlementAmount, *settlementTime, func(request *payment.NotifySettlementRequest) {
+		request.TransactionID = transactionID
+	})
+	require.Nil(t, err)
+	require.NotNil(t, response)
+	assert.Equal(t, receiptURL, response.ReceiptURL)
+}
+
+func (suite *OcppV2TestSuite) TestNotifySettlementInvalidEndpoint() {
+	messageId := defaultMessageId
+	pspRef := "psp1"
+	status := payment.PaymentStatusSettled
+	settlementAmount := 12.5
+	settlementTime := types.NewDateTime(time.Now())
+	request := payment.NewNotifySettlementRequest(pspRef, status, settlementAmount, *settlementTime)
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"pspRef":"%v","status":"%v","settlementAmount":%v,"settlementTime":"%v"}]`,
+		messageId, payment.NotifySettlementFeatureName, pspRef, status, settlementAmount, settlementTime.FormatTimestamp())
+	testUnsupportedRequestFromCentralSystem(suite, request, requestJson, messageId)
+}
@@ -0,0 +1,80 @@
+package ocpp21_test
+
+import (
+	"fmt"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/diagnostics"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+)
+
+// Test
+func (suite *OcppV2TestSuite) TestOpenPeriodicEventStreamRequestValidation() {
+	t := suite.T()
+	var requestTable = []GenericTestEntry{
+		{diagnostics.OpenPeriodicEventStreamRequest{ConstantStreamData: diagnostics.ConstantStreamData{ID: 1, Params: diagnostics.PeriodicEventStreamParams{Interval: newInt(60), Values: newInt(10)}, VariableMonitoringID: 2}}, true},
+		{diagnostics.OpenPeriodicEventStreamRequest{ConstantStreamData: diagnostics.ConstantStreamData{ID: 1, VariableMonitoringID: 2}}, true},
+		{diagnostics.OpenPeriodicEventStreamRequest{}, true},
+		{diagnostics.OpenPeriodicEventStreamRequest{ConstantStreamData: diagnostics.ConstantStreamData{ID: -1, VariableMonitoringID: 2}}, false},
+		{diagnostics.OpenPeriodicEventStreamRequest{ConstantStreamData: diagnostics.ConstantStreamData{ID: 1, VariableMonitoringID: -1}}, false},
+		{diagnostics.OpenPeriodicEventStreamRequest{ConstantStreamData: diagnostics.ConstantStreamData{ID: 1, Params: diagnostics.PeriodicEventStreamParams{Interval: newInt(-1)}, VariableMonitoringID: 2}}, false},
+		{diagnostics.OpenPeriodicEventStreamRequest{ConstantStreamData: diagnostics.ConstantStreamData{ID: 1, Params: diagnostics.PeriodicEventStreamParams{Values: newInt(-1)}, VariableMonitoringID: 2}}, false},
+	}
+	ExecuteGenericTestTable(t, requestTable)
+}
+
+func (suite *OcppV2TestSuite) TestOpenPeriodicEventStreamResponseValidation() {
+	t := suite.T()
+	var confirmationTable = []GenericTestEntry{
+		{diagnostics.OpenPeriodicEventStreamResponse{Status: types.GenericStatusAccepted, StatusInfo: types.NewStatusInfo("200", "")}, true},
+		{diagnostics.OpenPeriodicEventStreamResponse{Status: types.GenericStatusRejected}, true},
+		{diagnostics.OpenPeriodicEventStreamResponse{}, false},
+		{diagnostics.OpenPeriodicEventStreamResponse{Status: "invalidStatus"}, false},
+		{diagnostics.OpenPeriodicEventStreamResponse{Status: types.GenericStatusAccepted, StatusInfo: types.NewStatusInfo("", "")}, false},
+	}
+	ExecuteGenericTestTable(t, confirmationTable)
+}
+
+func (suite *OcppV2TestSuite) TestOpenPeriodicEventStreamE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	streamData := diagnostics.ConstantStreamData{ID: 1, Params: diagnostics.PeriodicEventStreamParams{Interval: newInt(60), Values: newInt(10)}, VariableMonitoringID: 2}
+	status := types.GenericStatusAccepted
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"constantStreamData":{"id":%v,"params":{"interval":%v,"values":%v},"variableMonitoringId":%v}}]`,
+		messageId, diagnostics.OpenPeriodicEventStreamFeatureName, streamData.ID, *streamData.Params.Interval, *streamData.Params.Values, streamData.VariableMonitoringID)
+	responseJson := fmt.Sprintf(`[3,"%v",{"status":"%v"}]`, messageId, status)
+	openPeriodicEventStreamResponse := diagnostics.NewOpenPeriodicEventStreamResponse(status)
+	channel := NewMockWebSocket(wsId)
+
+	handler := &MockCSMSDiagnosticsHandler{}
+	handler.On("OnOpenPeriodicEventStream", mock.AnythingOfType("string"), mock.Anything).Return(openPeriodicEventStreamResponse, nil).Run(func(args mock.Arguments) {
+		request, ok := args.Get(1).(*diagnostics.OpenPeriodicEventStreamRequest)
+		require.True(t, ok)
+		require.NotNil(t, request)
+		assert.Equal(t, streamData, request.ConstantStreamData)
+	})
+	setupDefaultCSMSHandlers(suite, expectedCSMSOptions{clientId: wsId, rawWrittenMessage: []byte(responseJson), forwardWrittenMessage: true}, handler)
+	setupDefaultChargingStationHandlers(suite, expectedChargingStationOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	err := suite.chargingStation.Start(wsUrl)
+	require.Nil(t, err)
+	response, err := suite.chargingStation.OpenPeriodicEventStream(streamData)
+	require.Nil(t, err)
+	require.NotNil(t, response)
+	assert.Equal(t, status, response.Status)
+}
+
+func (suite *OcppV2TestSuite) TestOpenPeriodicEventStreamInvalidEndpoint() {
+	messageId := defaultMessageId
+	streamData := diagnostics.ConstantStreamData{ID: 1, VariableMonitoringID: 2}
+	request := diagnostics.NewOpenPeriodicEventStreamRequest(streamData)
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"constantStreamData":{"id":%v,"params":{},"variableMonitoringId":%v}}]`,
+		messageId, diagnostics.OpenPeriodicEventStreamFeatureName, streamData.ID, streamData.VariableMonitoringID)
+	testUnsupportedRequestFromCentralSystem(suite, request, requestJson, messageId)
+}
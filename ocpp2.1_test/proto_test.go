@@ -0,0 +1,276 @@
+package ocpp21_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/data"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1"
+	"github.com/lorenzodonini/ocpp-go/ocppj"
+	"github.com/lorenzodonini/ocpp-go/ws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func (suite *OcppV2TestSuite) TestChargePointSendResponseError() {
+	t := suite.T()
+	wsId := "test_id"
+	channel := NewMockWebSocket(wsId)
+	var ocppErr *ocpp.Error
+	// Setup internal communication and listeners
+	dataListener := &MockChargingStationDataHandler{}
+	suite.chargingStation.SetDataHandler(dataListener)
+	suite.mockWsClient.On("Start", mock.AnythingOfType("string")).Return(nil).Run(func(args mock.Arguments) {
+		// Notify server of incoming connection
+		suite.mockWsServer.NewClientHandler(channel)
+	})
+	suite.mockWsClient.On("Write", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		rawMsg := args.Get(0)
+		bytes := rawMsg.([]byte)
+		err := suite.mockWsServer.MessageHandler(channel, bytes)
+		assert.Nil(t, err)
+	})
+	suite.mockWsServer.On("Start", mock.AnythingOfType("int"), mock.AnythingOfType("string")).Return(nil)
+	suite.mockWsServer.On("Write", mock.AnythingOfType("string"), mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		rawMsg := args.Get(1)
+		bytes := rawMsg.([]byte)
+		err := suite.mockWsClient.MessageHandler(bytes)
+		assert.NoError(t, err)
+	})
+	// Run Tests
+	suite.csms.Start(8887, "somePath")
+	err := suite.chargingStation.Start("someUrl")
+	require.Nil(t, err)
+	resultChannel := make(chan error, 1)
+	// Test 1: occurrence validation error
+	dataTransferResponse := data.NewDataTransferResponse(data.DataTransferStatusAccepted)
+	dataTransferResponse.Data = struct {
+		Field1 string `validate:"required"`
+	}{Field1: ""}
+	dataListener.On("OnDataTransfer", mock.Anything).Return(dataTransferResponse, nil)
+	err = suite.csms.DataTransfer(wsId, func(response *data.DataTransferResponse, err error) {
+		require.Nil(t, response)
+		require.Error(t, err)
+		resultChannel <- err
+	}, "vendor1")
+	require.Nil(t, err)
+	result := <-resultChannel
+	require.IsType(t, &ocpp.Error{}, result)
+	ocppErr = result.(*ocpp.Error)
+	assert.Equal(t, ocppj.OccurrenceConstraintViolationV2, ocppErr.Code)
+	assert.Equal(t, "Field CallResult.Payload.Data.Field1 required but not found for feature DataTransfer", ocppErr.Description)
+	// Test 2: marshaling error
+	dataTransferResponse = data.NewDataTransferResponse(data.DataTransferStatusAccepted)
+	dataTransferResponse.Data = make(chan struct{})
+	dataListener.ExpectedCalls = nil
+	dataListener.On("OnDataTransfer", mock.Anything).Return(dataTransferResponse, nil)
+	err = suite.csms.DataTransfer(wsId, func(response *data.DataTransferResponse, err error) {
+		require.Nil(t, response)
+		require.Error(t, err)
+		resultChannel <- err
+	}, "vendor1")
+	require.Nil(t, err)
+	result = <-resultChannel
+	require.IsType(t, &ocpp.Error{}, result)
+	ocppErr = result.(*ocpp.Error)
+	assert.Equal(t, ocppj.GenericError, ocppErr.Code)
+	assert.Equal(t, "json: unsupported type: chan struct {}", ocppErr.Description)
+	// Test 3: no results in callback
+	dataListener.ExpectedCalls = nil
+	dataListener.On("OnDataTransfer", mock.Anything).Return(nil, nil)
+	err = suite.csms.DataTransfer(wsId, func(response *data.DataTransferResponse, err error) {
+		require.Nil(t, response)
+		require.Error(t, err)
+		resultChannel <- err
+	}, "vendor1")
+	require.Nil(t, err)
+	result = <-resultChannel
+	require.IsType(t, &ocpp.Error{}, result)
+	ocppErr = result.(*ocpp.Error)
+	assert.Equal(t, ocppj.GenericError, ocppErr.Code)
+	assert.Equal(t, "empty response to request 1234", ocppErr.Description)
+}
+
+func (suite *OcppV2TestSuite) TestCentralSystemSendResponseError() {
+	t := suite.T()
+	wsId := "test_id"
+	channel := NewMockWebSocket(wsId)
+	var ocppErr *ocpp.Error
+	var response *data.DataTransferResponse
+	// Setup internal communication and listeners
+	dataListener := &MockCSMSDataHandler{}
+	suite.csms.SetDataHandler(dataListener)
+	suite.mockWsClient.On("Start", mock.AnythingOfType("string")).Return(nil).Run(func(args mock.Arguments) {
+		// Notify server of incoming connection
+		suite.mockWsServer.NewClientHandler(channel)
+	})
+	suite.mockWsClient.On("Write", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		rawMsg := args.Get(0)
+		bytes := rawMsg.([]byte)
+		err := suite.mockWsServer.MessageHandler(channel, bytes)
+		assert.Nil(t, err)
+	})
+	suite.mockWsServer.On("Start", mock.AnythingOfType("int"), mock.AnythingOfType("string")).Return(nil)
+	suite.mockWsServer.On("Write", mock.AnythingOfType("string"), mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		rawMsg := args.Get(1)
+		bytes := rawMsg.([]byte)
+		err := suite.mockWsClient.MessageHandler(bytes)
+		assert.NoError(t, err)
+	})
+	// Run Tests
+	suite.csms.Start(8887, "somePath")
+	err := suite.chargingStation.Start("someUrl")
+	require.Nil(t, err)
+	// Test 1: occurrence validation error
+	dataTransferResponse := data.NewDataTransferResponse(data.DataTransferStatusAccepted)
+	dataTransferResponse.Data = struct {
+		Field1 string `validate:"required"`
+	}{Field1: ""}
+	dataListener.On("OnDataTransfer", mock.AnythingOfType("string"), mock.Anything).Return(dataTransferResponse, nil)
+	response, err = suite.chargingStation.DataTransfer("vendor1")
+	require.Nil(t, response)
+	require.Error(t, err)
+	require.IsType(t, &ocpp.Error{}, err)
+	ocppErr = err.(*ocpp.Error)
+	assert.Equal(t, ocppj.OccurrenceConstraintViolationV2, ocppErr.Code)
+	assert.Equal(t, "Field CallResult.Payload.Data.Field1 required but not found for feature DataTransfer", ocppErr.Description)
+	// Test 2: marshaling error
+	dataTransferResponse = data.NewDataTransferResponse(data.DataTransferStatusAccepted)
+	dataTransferResponse.Data = make(chan struct{})
+	dataListener.ExpectedCalls = nil
+	dataListener.On("OnDataTransfer", mock.AnythingOfType("string"), mock.Anything).Return(dataTransferResponse, nil)
+	response, err = suite.chargingStation.DataTransfer("vendor1")
+	require.Nil(t, response)
+	require.Error(t, err)
+	require.IsType(t, &ocpp.Error{}, err)
+	ocppErr = err.(*ocpp.Error)
+	assert.Equal(t, ocppj.GenericError, ocppErr.Code)
+	assert.Equal(t, "json: unsupported type: chan struct {}", ocppErr.Description)
+	// Test 3: no results in callback
+	dataListener.ExpectedCalls = nil
+	dataListener.On("OnDataTransfer", mock.AnythingOfType("string"), mock.Anything).Return(nil, nil)
+	response, err = suite.chargingStation.DataTransfer("vendor1")
+	require.Nil(t, response)
+	require.Error(t, err)
+	require.IsType(t, &ocpp.Error{}, err)
+	ocppErr = err.(*ocpp.Error)
+	assert.Equal(t, ocppj.GenericError, ocppErr.Code)
+	assert.Equal(t, fmt.Sprintf("empty response to %s for request 1234", wsId), ocppErr.Description)
+}
+
+func (suite *OcppV2TestSuite) TestCSMSShutdown() {
+	t := suite.T()
+	wsId := "test_id"
+	channel := NewMockWebSocket(wsId)
+	suite.mockWsServer.On("Start", mock.AnythingOfType("int"), mock.AnythingOfType("string")).Return(nil)
+	// Requests are written, but the charging station never responds
+	suite.mockWsServer.On("Write", mock.AnythingOfType("string"), mock.Anything).Return(nil)
+	suite.mockWsServer.On("Shutdown", mock.Anything).Return(nil)
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	suite.mockWsServer.NewClientHandler(channel)
+	callbackC := make(chan error, 1)
+	err := suite.csms.DataTransfer(wsId, func(response *data.DataTransferResponse, err error) {
+		assert.Nil(t, response)
+		callbackC <- err
+	}, "vendor1")
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	err = suite.csms.Shutdown(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	// Outstanding callback was invoked with a shutdown error
+	select {
+	case err = <-callbackC:
+		require.Error(t, err)
+		require.IsType(t, &ocpp.Error{}, err)
+		ocppErr := err.(*ocpp.Error)
+		assert.Equal(t, ocppj.GenericError, ocppErr.Code)
+		assert.Equal(t, "CSMS shutting down, no response received from client", ocppErr.Description)
+	default:
+		assert.Fail(t, "callback wasn't invoked")
+	}
+}
+
+func (suite *OcppV2TestSuite) TestCSMSChargingStationReplaced() {
+	t := suite.T()
+	wsId := "test_id"
+	oldChannel := NewMockWebSocket(wsId)
+	newChannel := NewMockWebSocket(wsId)
+	suite.mockWsServer.On("Start", mock.AnythingOfType("int"), mock.AnythingOfType("string")).Return(nil)
+	// Requests are written, but the charging station never responds
+	suite.mockWsServer.On("Write", mock.AnythingOfType("string"), mock.Anything).Return(nil)
+	var events []string
+	suite.csms.SetNewChargingStationHandler(func(chargingStation ocpp21.ChargingStationConnection) {
+		events = append(events, "connected")
+	})
+	suite.csms.SetChargingStationDisconnectedHandler(func(chargingStation ocpp21.ChargingStationConnection) {
+		events = append(events, "disconnected")
+	})
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	suite.mockWsServer.NewClientHandler(oldChannel)
+	callbackC := make(chan error, 1)
+	err := suite.csms.DataTransfer(wsId, func(response *data.DataTransferResponse, err error) {
+		assert.Nil(t, response)
+		callbackC <- err
+	}, "vendor1")
+	require.NoError(t, err)
+	// Simulate connection takeover
+	suite.mockWsServer.ReplacedClientHandler(oldChannel)
+	suite.mockWsServer.DisconnectedClientHandler(oldChannel)
+	suite.mockWsServer.NewClientHandler(newChannel)
+	// Outstanding callback was invoked with a replaced connection error
+	select {
+	case err = <-callbackC:
+		require.Error(t, err)
+		require.IsType(t, &ocpp.Error{}, err)
+		ocppErr := err.(*ocpp.Error)
+		assert.Equal(t, ocppj.GenericError, ocppErr.Code)
+		assert.Equal(t, "connection replaced by a new connection, no response received from client", ocppErr.Description)
+	default:
+		assert.Fail(t, "callback wasn't invoked")
+	}
+	assert.Equal(t, []string{"connected", "disconnected", "connected"}, events)
+}
+
+func (suite *OcppV2TestSuite) TestErrorCodes() {
+	suite.Equal(ocppj.FormatViolationV2, ocppj.FormatErrorType(suite.ocppjServer))
+	suite.Equal(ocppj.OccurrenceConstraintViolationV2, ocppj.OccurrenceConstraintErrorType(suite.ocppjServer))
+}
+
+func (suite *OcppV2TestSuite) TestCSMSChargingStationConnections() {
+	t := suite.T()
+	channel1 := NewMockWebSocket("cs1")
+	channel2 := NewMockWebSocket("cs2")
+	suite.mockWsServer.On("Channels").Return([]ws.Channel{channel1, channel2})
+	suite.mockWsServer.On("ChannelCount").Return(2)
+	suite.mockWsServer.On("GetChannel", "cs1").Return(channel1, true)
+	suite.mockWsServer.On("GetChannel", "unknown").Return(nil, false)
+	closeError := websocket.CloseError{Code: websocket.ClosePolicyViolation, Text: "banned"}
+	suite.mockWsServer.On("StopConnection", "cs1", closeError).Return(nil)
+	suite.mockWsServer.On("StopConnection", "unknown", mock.Anything).Return(fmt.Errorf("no connection with id unknown is open"))
+	// List and look up connections
+	connections := suite.csms.ChargingStationConnections()
+	require.Len(t, connections, 2)
+	assert.Equal(t, "cs1", connections[0].ID())
+	assert.Equal(t, "cs2", connections[1].ID())
+	assert.Equal(t, 2, suite.csms.ChargingStationConnectionCount())
+	connection, ok := suite.csms.GetChargingStationConnection("cs1")
+	require.True(t, ok)
+	assert.Equal(t, "cs1", connection.ID())
+	connection, ok = suite.csms.GetChargingStationConnection("unknown")
+	assert.False(t, ok)
+	assert.Nil(t, connection)
+	// Disconnect with a custom close code and reason
+	err := suite.csms.DisconnectChargingStation("cs1", websocket.ClosePolicyViolation, "banned")
+	assert.NoError(t, err)
+	err = suite.csms.DisconnectChargingStation("unknown", websocket.CloseNormalClosure, "")
+	assert.Error(t, err)
+	suite.mockWsServer.AssertCalled(t, "StopConnection", "cs1", closeError)
+}
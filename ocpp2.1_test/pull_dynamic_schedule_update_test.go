@@ -0,0 +1,80 @@
+package ocpp21_test
+
+import (
+	"fmt"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/smartcharging"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+)
+
+// Test
+func (suite *OcppV2TestSuite) TestPullDynamicScheduleUpdateRequestValidation() {
+	t := suite.T()
+	var requestTable = []GenericTestEntry{
+		{smartcharging.PullDynamicScheduleUpdateRequest{ChargingProfileID: 1}, true},
+		{smartcharging.PullDynamicScheduleUpdateRequest{}, true},
+	}
+	ExecuteGenericTestTable(t, requestTable)
+}
+
+func (suite *OcppV2TestSuite) TestPullDynamicScheduleUpdateResponseValidation() {
+	t := suite.T()
+	var confirmationTable = []GenericTestEntry{
+		{smartcharging.PullDynamicScheduleUpdateResponse{ScheduleUpdate: &smartcharging.ChargingScheduleUpdate{Limit: newFloat(16.0)}, Status: smartcharging.ChargingProfileStatusAccepted, StatusInfo: types.NewStatusInfo("200", "")}, true},
+		{smartcharging.PullDynamicScheduleUpdateResponse{Status: smartcharging.ChargingProfileStatusRejected}, true},
+		{smartcharging.PullDynamicScheduleUpdateResponse{}, false},
+		{smartcharging.PullDynamicScheduleUpdateResponse{Status: "invalidStatus"}, false},
+		{smartcharging.PullDynamicScheduleUpdateResponse{ScheduleUpdate: &smartcharging.ChargingScheduleUpdate{DischargeLimit: newFloat(1.0)}, Status: smartcharging.ChargingProfileStatusAccepted}, false},
+		{smartcharging.PullDynamicScheduleUpdateResponse{Status: smartcharging.ChargingProfileStatusAccepted, StatusInfo: types.NewStatusInfo("", "")}, false},
+	}
+	ExecuteGenericTestTable(t, confirmationTable)
+}
+
+func (suite *OcppV2TestSuite) TestPullDynamicScheduleUpdateE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	chargingProfileID := 1
+	scheduleUpdate := &smartcharging.ChargingScheduleUpdate{Limit: newFloat(16.0)}
+	status := smartcharging.ChargingProfileStatusAccepted
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"chargingProfileId":%v}]`,
+		messageId, smartcharging.PullDynamicScheduleUpdateFeatureName, chargingProfileID)
+	responseJson := fmt.Sprintf(`[3,"%v",{"scheduleUpdate":{"limit":%v},"status":"%v"}]`, messageId, *scheduleUpdate.Limit, status)
+	pullDynamicScheduleUpdateResponse := smartcharging.NewPullDynamicScheduleUpdateResponse(status)
+	pullDynamicScheduleUpdateResponse.ScheduleUpdate = scheduleUpdate
+	channel := NewMockWebSocket(wsId)
+
+	handler := &MockCSMSSmartChargingHandler{}
+	handler.On("OnPullDynamicScheduleUpdate", mock.AnythingOfType("string"), mock.Anything).Return(pullDynamicScheduleUpdateResponse, nil).Run(func(args mock.Arguments) {
+		request, ok := args.Get(1).(*smartcharging.PullDynamicScheduleUpdateRequest)
+		require.True(t, ok)
+		require.NotNil(t, request)
+		assert.Equal(t, chargingProfileID, request.ChargingProfileID)
+	})
+	setupDefaultCSMSHandlers(suite, expectedCSMSOptions{clientId: wsId, rawWrittenMessage: []byte(responseJson), forwardWrittenMessage: true}, handler)
+	setupDefaultChargingStationHandlers(suite, expectedChargingStationOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	err := suite.chargingStation.Start(wsUrl)
+	require.Nil(t, err)
+	response, err := suite.chargingStation.PullDynamicScheduleUpdate(chargingProfileID)
+	require.Nil(t, err)
+	require.NotNil(t, response)
+	assert.Equal(t, status, response.Status)
+	require.NotNil(t, response.ScheduleUpdate)
+	assert.Equal(t, *scheduleUpdate.Limit, *response.ScheduleUpdate.Limit)
+}
+
+func (suite *OcppV2TestSuite) TestPullDynamicScheduleUpdateInvalidEndpoint() {
+	messageId := defaultMessageId
+	chargingProfileID := 1
+	request := smartcharging.NewPullDynamicScheduleUpdateRequest(chargingProfileID)
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"chargingProfileId":%v}]`,
+		messageId, smartcharging.PullDynamicScheduleUpdateFeatureName, chargingProfileID)
+	testUnsupportedRequestFromCentralSystem(suite, request, requestJson, messageId)
+}
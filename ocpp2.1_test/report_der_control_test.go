@@ -0,0 +1,81 @@
+package ocpp21_test
+
+import (
+	"fmt"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/der"
+)
+
+// Test
+func (suite *OcppV2TestSuite) TestReportDERControlRequestValidation() {
+	t := suite.T()
+	curve := der.DERCurve{CurveData: []der.DERCurvePoint{{X: 0.9, Y: 100.0}}, YUnit: der.DERUnitPctMaxW}
+	var requestTable = []GenericTestEntry{
+		{der.ReportDERControlRequest{RequestID: 1, Curve: []der.DERCurveGet{{ID: "control1", CurveType: der.DERControlVoltWatt, IsDefault: true, Curve: curve}}, Tbc: true}, true},
+		{der.ReportDERControlRequest{RequestID: 1, Gradient: []der.GradientGet{{ID: "control2", Gradient: der.Gradient{Gradient: 10}}}}, true},
+		{der.ReportDERControlRequest{RequestID: 1, FixedVar: []der.FixedVarGet{{ID: "control3", FixedVar: der.FixedVar{Setpoint: 5, Unit: der.DERUnitPctMaxVar}}}}, true},
+		{der.ReportDERControlRequest{RequestID: 1}, true},
+		{der.ReportDERControlRequest{}, true},
+		{der.ReportDERControlRequest{RequestID: 1, Curve: []der.DERCurveGet{{CurveType: der.DERControlVoltWatt, Curve: curve}}}, false},
+		{der.ReportDERControlRequest{RequestID: 1, Curve: []der.DERCurveGet{{ID: "control1", CurveType: "invalidControlType", Curve: curve}}}, false},
+		{der.ReportDERControlRequest{RequestID: 1, Curve: []der.DERCurveGet{{ID: "control1", CurveType: der.DERControlVoltWatt, Curve: der.DERCurve{YUnit: der.DERUnitPctMaxW}}}}, false},
+		{der.ReportDERControlRequest{RequestID: 1, FixedVar: []der.FixedVarGet{{ID: "control3", FixedVar: der.FixedVar{Setpoint: 5}}}}, false},
+	}
+	ExecuteGenericTestTable(t, requestTable)
+}
+
+func (suite *OcppV2TestSuite) TestReportDERControlResponseValidation() {
+	t := suite.T()
+	var confirmationTable = []GenericTestEntry{
+		{der.ReportDERControlResponse{}, true},
+	}
+	ExecuteGenericTestTable(t, confirmationTable)
+}
+
+func (suite *OcppV2TestSuite) TestReportDERControlE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	requestID := 42
+	gradient := der.GradientGet{ID: "control1", Gradient: der.Gradient{Priority: 1, Gradient: 10.0, SoftGradient: 5.0}}
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"requestId":%v,"gradient":[{"id":"%v","gradient":{"priority":%v,"gradient":%v,"softGradient":%v}}]}]`,
+		messageId, der.ReportDERControlFeatureName, requestID, gradient.ID, gradient.Gradient.Priority, gradient.Gradient.Gradient, gradient.Gradient.SoftGradient)
+	responseJson := fmt.Sprintf(`[3,"%v",{}]`, messageId)
+	reportDERControlResponse := der.NewReportDERControlResponse()
+	channel := NewMockWebSocket(wsId)
+
+	handler := &MockCSMSDERControlHandler{}
+	handler.On("OnReportDERControl", mock.AnythingOfType("string"), mock.Anything).Return(reportDERControlResponse, nil).Run(func(args mock.Arguments) {
+		request, ok := args.Get(1).(*der.ReportDERControlRequest)
+		require.True(t, ok)
+		require.NotNil(t, request)
+		assert.Equal(t, requestID, request.RequestID)
+		require.Len(t, request.Gradient, 1)
+		assert.Equal(t, gradient, request.Gradient[0])
+		assert.False(t, request.Tbc)
+	})
+	setupDefaultCSMSHandlers(suite, expectedCSMSOptions{clientId: wsId, rawWrittenMessage: []byte(responseJson), forwardWrittenMessage: true}, handler)
+	setupDefaultChargingStationHandlers(suite, expectedChargingStationOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	err := suite.chargingStation.Start(wsUrl)
+	require.Nil(t, err)
+	response, err := suite.chargingStation.ReportDERControl(requestID, func(request *der.ReportDERControlRequest) {
+		request.Gradient = []der.GradientGet{gradient}
+	})
+	require.Nil(t, err)
+	require.NotNil(t, response)
+}
+
+func (suite *OcppV2TestSuite) TestReportDERControlInvalidEndpoint() {
+	messageId := defaultMessageId
+	requestID := 42
+	request := der.NewReportDERControlRequest(requestID)
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"requestId":%v}]`, messageId, der.ReportDERControlFeatureName, requestID)
+	testUnsupportedRequestFromCentralSystem(suite, request, requestJson, messageId)
+}
@@ -0,0 +1,87 @@
+package ocpp21_test
+
+import (
+	"fmt"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/batteryswap"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+)
+
+// Test
+func (suite *OcppV2TestSuite) TestRequestBatterySwapRequestValidation() {
+	t := suite.T()
+	var requestTable = []GenericTestEntry{
+		{batteryswap.RequestBatterySwapRequest{IdToken: types.IdToken{IdToken: "1234", Type: types.IdTokenTypeISO14443}, RequestID: 1}, true},
+		{batteryswap.RequestBatterySwapRequest{IdToken: types.IdToken{IdToken: "1234", Type: types.IdTokenTypeISO14443}}, true},
+		{batteryswap.RequestBatterySwapRequest{}, false},
+		{batteryswap.RequestBatterySwapRequest{IdToken: types.IdToken{IdToken: "1234", Type: ">20.................."}, RequestID: 1}, false},
+	}
+	ExecuteGenericTestTable(t, requestTable)
+}
+
+func (suite *OcppV2TestSuite) TestRequestBatterySwapResponseValidation() {
+	t := suite.T()
+	var confirmationTable = []GenericTestEntry{
+		{batteryswap.RequestBatterySwapResponse{Status: types.GenericStatusAccepted, StatusInfo: types.NewStatusInfo("200", "")}, true},
+		{batteryswap.RequestBatterySwapResponse{Status: types.GenericStatusRejected}, true},
+		{batteryswap.RequestBatterySwapResponse{}, false},
+		{batteryswap.RequestBatterySwapResponse{Status: "invalidStatus"}, false},
+		{batteryswap.RequestBatterySwapResponse{Status: types.GenericStatusAccepted, StatusInfo: types.NewStatusInfo("", "")}, false},
+	}
+	ExecuteGenericTestTable(t, confirmationTable)
+}
+
+func (suite *OcppV2TestSuite) TestRequestBatterySwapE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	idToken := types.IdToken{IdToken: "1234", Type: types.IdTokenTypeISO14443}
+	requestID := 42
+	status := types.GenericStatusAccepted
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"idToken":{"idToken":"%v","type":"%v"},"requestId":%v}]`,
+		messageId, batteryswap.RequestBatterySwapFeatureName, idToken.IdToken, idToken.Type, requestID)
+	responseJson := fmt.Sprintf(`[3,"%v",{"status":"%v"}]`, messageId, status)
+	requestBatterySwapResponse := batteryswap.NewRequestBatterySwapResponse(status)
+	channel := NewMockWebSocket(wsId)
+
+	handler := &MockChargingStationBatterySwapHandler{}
+	handler.On("OnRequestBatterySwap", mock.Anything).Return(requestBatterySwapResponse, nil).Run(func(args mock.Arguments) {
+		request, ok := args.Get(0).(*batteryswap.RequestBatterySwapRequest)
+		require.True(t, ok)
+		require.NotNil(t, request)
+		assert.Equal(t, idToken.IdToken, request.IdToken.IdToken)
+		assert.Equal(t, idToken.Type, request.IdToken.Type)
+		assert.Equal(t, requestID, request.RequestID)
+	})
+	setupDefaultCSMSHandlers(suite, expectedCSMSOptions{clientId: wsId, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	setupDefaultChargingStationHandlers(suite, expectedChargingStationOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(responseJson), forwardWrittenMessage: true}, handler)
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	err := suite.chargingStation.Start(wsUrl)
+	require.Nil(t, err)
+	resultChannel := make(chan bool, 1)
+	err = suite.csms.RequestBatterySwap(wsId, func(response *batteryswap.RequestBatterySwapResponse, err error) {
+		require.Nil(t, err)
+		require.NotNil(t, response)
+		assert.Equal(t, status, response.Status)
+		resultChannel <- true
+	}, idToken, requestID)
+	require.Nil(t, err)
+	result := <-resultChannel
+	assert.True(t, result)
+}
+
+func (suite *OcppV2TestSuite) TestRequestBatterySwapInvalidEndpoint() {
+	messageId := defaultMessageId
+	idToken := types.IdToken{IdToken: "1234", Type: types.IdTokenTypeISO14443}
+	requestID := 42
+	request := batteryswap.NewRequestBatterySwapRequest(idToken, requestID)
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"idToken":{"idToken":"%v","type":"%v"},"requestId":%v}]`,
+		messageId, batteryswap.RequestBatterySwapFeatureName, idToken.IdToken, idToken.Type, requestID)
+	testUnsupportedRequestFromChargingStation(suite, request, requestJson, messageId)
+}
@@ -0,0 +1,96 @@
+package ocpp21_test
+
+import (
+	"fmt"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/tariffcost"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+)
+
+// Test
+func (suite *OcppV2TestSuite) TestSetDefaultTariffRequestValidation() {
+	t := suite.T()
+	energy := &tariffcost.TariffEnergy{Prices: []tariffcost.TariffEnergyPrice{{PriceKwh: 0.3}}}
+	var requestTable = []GenericTestEntry{
+		{tariffcost.SetDefaultTariffRequest{EvseID: 1, Tariff: tariffcost.Tariff{TariffID: "tariff1", Currency: "EUR", Energy: energy}}, true},
+		{tariffcost.SetDefaultTariffRequest{EvseID: 0, Tariff: tariffcost.Tariff{TariffID: "tariff1", Currency: "EUR"}}, true},
+		{tariffcost.SetDefaultTariffRequest{Tariff: tariffcost.Tariff{TariffID: "tariff1", Currency: "EUR", Energy: energy, MinCost: &types.Price{ExclTax: newFloat(1.0)}, MaxCost: &types.Price{InclTax: newFloat(10.0), TaxRates: []types.TaxRate{{Type: "VAT", Tax: 20}}}}}, true},
+		{tariffcost.SetDefaultTariffRequest{Tariff: tariffcost.Tariff{TariffID: "tariff1", Currency: "EUR", FixedFee: &tariffcost.TariffFixed{Prices: []tariffcost.TariffFixedPrice{{PriceFixed: 1.5, Conditions: &tariffcost.TariffConditionsFixed{DayOfWeek: []tariffcost.DayOfWeek{tariffcost.DayOfWeekMonday}, EvseKind: tariffcost.EvseKindDC}}}}}}, true},
+		{tariffcost.SetDefaultTariffRequest{}, false},
+		{tariffcost.SetDefaultTariffRequest{EvseID: -1, Tariff: tariffcost.Tariff{TariffID: "tariff1", Currency: "EUR"}}, false},
+		{tariffcost.SetDefaultTariffRequest{Tariff: tariffcost.Tariff{Currency: "EUR"}}, false},
+		{tariffcost.SetDefaultTariffRequest{Tariff: tariffcost.Tariff{TariffID: "tariff1", Currency: "EURO"}}, false},
+		{tariffcost.SetDefaultTariffRequest{Tariff: tariffcost.Tariff{TariffID: "tariff1", Currency: "EUR", Energy: &tariffcost.TariffEnergy{}}}, false},
+		{tariffcost.SetDefaultTariffRequest{Tariff: tariffcost.Tariff{TariffID: "tariff1", Currency: "EUR", MinCost: &types.Price{}}}, false},
+		{tariffcost.SetDefaultTariffRequest{Tariff: tariffcost.Tariff{TariffID: "tariff1", Currency: "EUR", Energy: &tariffcost.TariffEnergy{Prices: []tariffcost.TariffEnergyPrice{{PriceKwh: 0.3, Conditions: &tariffcost.TariffConditions{DayOfWeek: []tariffcost.DayOfWeek{"invalidDay"}}}}}}}, false},
+		{tariffcost.SetDefaultTariffRequest{Tariff: tariffcost.Tariff{TariffID: "tariff1", Currency: "EUR", Energy: &tariffcost.TariffEnergy{Prices: []tariffcost.TariffEnergyPrice{{PriceKwh: 0.3, Conditions: &tariffcost.TariffConditions{EvseKind: "invalidKind"}}}}}}, false},
+	}
+	ExecuteGenericTestTable(t, requestTable)
+}
+
+func (suite *OcppV2TestSuite) TestSetDefaultTariffResponseValidation() {
+	t := suite.T()
+	var confirmationTable = []GenericTestEntry{
+		{tariffcost.SetDefaultTariffResponse{Status: tariffcost.TariffSetStatusAccepted, StatusInfo: types.NewStatusInfo("200", "")}, true},
+		{tariffcost.SetDefaultTariffResponse{Status: tariffcost.TariffSetStatusDuplicateTariffId}, true},
+		{tariffcost.SetDefaultTariffResponse{}, false},
+		{tariffcost.SetDefaultTariffResponse{Status: "invalidStatus"}, false},
+		{tariffcost.SetDefaultTariffResponse{Status: tariffcost.TariffSetStatusAccepted, StatusInfo: types.NewStatusInfo("", "")}, false},
+	}
+	ExecuteGenericTestTable(t, confirmationTable)
+}
+
+func (suite *OcppV2TestSuite) TestSetDefaultTariffE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	evseID := 1
+	tariff := tariffcost.NewTariff("tariff1", "EUR")
+	tariff.Energy = &tariffcost.TariffEnergy{Prices: []tariffcost.TariffEnergyPrice{{PriceKwh: 0.3}}}
+	status := tariffcost.TariffSetStatusAccepted
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"evseId":%v,"tariff":{"tariffId":"%v","currency":"%v","energy":{"prices":[{"priceKwh":%v}]}}}]`,
+		messageId, tariffcost.SetDefaultTariffFeatureName, evseID, tariff.TariffID, tariff.Currency, tariff.Energy.Prices[0].PriceKwh)
+	responseJson := fmt.Sprintf(`[3,"%v",{"status":"%v"}]`, messageId, status)
+	setDefaultTariffResponse := tariffcost.NewSetDefaultTariffResponse(status)
+	channel := NewMockWebSocket(wsId)
+
+	handler := &MockChargingStationTariffCostHandler{}
+	handler.On("OnSetDefaultTariff", mock.Anything).Return(setDefaultTariffResponse, nil).Run(func(args mock.Arguments) {
+		request, ok := args.Get(0).(*tariffcost.SetDefaultTariffRequest)
+		require.True(t, ok)
+		require.NotNil(t, request)
+		assert.Equal(t, evseID, request.EvseID)
+		assert.Equal(t, tariff, request.Tariff)
+	})
+	setupDefaultCSMSHandlers(suite, expectedCSMSOptions{clientId: wsId, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	setupDefaultChargingStationHandlers(suite, expectedChargingStationOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(responseJson), forwardWrittenMessage: true}, handler)
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	err := suite.chargingStation.Start(wsUrl)
+	require.Nil(t, err)
+	resultChannel := make(chan bool, 1)
+	err = suite.csms.SetDefaultTariff(wsId, func(response *tariffcost.SetDefaultTariffResponse, err error) {
+		require.Nil(t, err)
+		require.NotNil(t, response)
+		assert.Equal(t, status, response.Status)
+		resultChannel <- true
+	}, evseID, tariff)
+	require.Nil(t, err)
+	result := <-resultChannel
+	assert.True(t, result)
+}
+
+func (suite *OcppV2TestSuite) TestSetDefaultTariffInvalidEndpoint() {
+	messageId := defaultMessageId
+	evseID := 1
+	tariff := tariffcost.NewTariff("tariff1", "EUR")
+	request := tariffcost.NewSetDefaultTariffRequest(evseID, tariff)
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"evseId":%v,"tariff":{"tariffId":"%v","currency":"%v"}}]`,
+		messageId, tariffcost.SetDefaultTariffFeatureName, evseID, tariff.TariffID, tariff.Currency)
+	testUnsupportedRequestFromChargingStation(suite, request, requestJson, messageId)
+}
@@ -0,0 +1,109 @@
+package ocpp21_test
+
+import (
+	"fmt"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/der"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+)
+
+// Test
+func (suite *OcppV2TestSuite) TestSetDERControlRequestValidation() {
+	t := suite.T()
+	curve := &der.DERCurve{CurveData: []der.DERCurvePoint{{X: 0.9, Y: 100.0}, {X: 1.1, Y: -100.0}}, Priority: 1, YUnit: der.DERUnitPctMaxVar}
+	var requestTable = []GenericTestEntry{
+		{der.SetDERControlRequest{IsDefault: true, ControlID: "control1", ControlType: der.DERControlVoltVar, Curve: curve}, true},
+		{der.SetDERControlRequest{IsDefault: false, ControlID: "control1", ControlType: der.DERControlFixedPFAbsorb, FixedPFAbsorb: &der.FixedPF{Priority: 0, Displacement: 0.9, Excitation: true}}, true},
+		{der.SetDERControlRequest{ControlID: "control1", ControlType: der.DERControlFixedVar, FixedVar: &der.FixedVar{Setpoint: 10.0, Unit: der.DERUnitPctMaxVar}}, true},
+		{der.SetDERControlRequest{ControlID: "control1", ControlType: der.DERControlGradients, Gradient: &der.Gradient{Gradient: 10, SoftGradient: 5}}, true},
+		{der.SetDERControlRequest{ControlID: "control1", ControlType: der.DERControlLimitMaxDischarge, LimitMaxDischarge: &der.LimitMaxDischarge{PctMaxDischargePower: newFloat(50.0)}}, true},
+		{der.SetDERControlRequest{ControlID: "control1", ControlType: der.DERControlEnterService}, true},
+		{der.SetDERControlRequest{}, false},
+		{der.SetDERControlRequest{ControlType: der.DERControlVoltVar}, false},
+		{der.SetDERControlRequest{ControlID: "control1"}, false},
+		{der.SetDERControlRequest{ControlID: "control1", ControlType: "invalidControlType"}, false},
+		{der.SetDERControlRequest{ControlID: ">36..................................", ControlType: der.DERControlVoltVar}, false},
+		{der.SetDERControlRequest{ControlID: "control1", ControlType: der.DERControlVoltVar, Curve: &der.DERCurve{YUnit: der.DERUnitPctMaxVar}}, false},
+		{der.SetDERControlRequest{ControlID: "control1", ControlType: der.DERControlVoltVar, Curve: &der.DERCurve{CurveData: []der.DERCurvePoint{{X: 1, Y: 1}}, YUnit: "invalidUnit"}}, false},
+		{der.SetDERControlRequest{ControlID: "control1", ControlType: der.DERControlVoltVar, Curve: &der.DERCurve{CurveData: []der.DERCurvePoint{{X: 1, Y: 1}}, Priority: -1, YUnit: der.DERUnitPctMaxVar}}, false},
+		{der.SetDERControlRequest{ControlID: "control1", ControlType: der.DERControlFixedVar, FixedVar: &der.FixedVar{Setpoint: 10.0}}, false},
+	}
+	ExecuteGenericTestTable(t, requestTable)
+}
+
+func (suite *OcppV2TestSuite) TestSetDERControlResponseValidation() {
+	t := suite.T()
+	var confirmationTable = []GenericTestEntry{
+		{der.SetDERControlResponse{Status: der.DERControlStatusAccepted, SupersededIDs: []string{"control0"}, StatusInfo: types.NewStatusInfo("200", "")}, true},
+		{der.SetDERControlResponse{Status: der.DERControlStatusNotSupported}, true},
+		{der.SetDERControlResponse{}, false},
+		{der.SetDERControlResponse{Status: "invalidStatus"}, false},
+		{der.SetDERControlResponse{Status: der.DERControlStatusAccepted, SupersededIDs: []string{">36.................................."}}, false},
+		{der.SetDERControlResponse{Status: der.DERControlStatusAccepted, StatusInfo: types.NewStatusInfo("", "")}, false},
+	}
+	ExecuteGenericTestTable(t, confirmationTable)
+}
+
+func (suite *OcppV2TestSuite) TestSetDERControlE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	isDefault := true
+	controlID := "control1"
+	controlType := der.DERControlGradients
+	gradient := &der.Gradient{Priority: 1, Gradient: 10.0, SoftGradient: 5.0}
+	status := der.DERControlStatusAccepted
+	supersededID := "control0"
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"isDefault":%v,"controlId":"%v","controlType":"%v","gradient":{"priority":%v,"gradient":%v,"softGradient":%v}}]`,
+		messageId, der.SetDERControlFeatureName, isDefault, controlID, controlType, gradient.Priority, gradient.Gradient, gradient.SoftGradient)
+	responseJson := fmt.Sprintf(`[3,"%v",{"status":"%v","supersededIds":["%v"]}]`, messageId, status, supersededID)
+	setDERControlResponse := der.NewSetDERControlResponse(status)
+	setDERControlResponse.SupersededIDs = []string{supersededID}
+	channel := NewMockWebSocket(wsId)
+
+	handler := &MockChargingStationDERControlHandler{}
+	handler.On("OnSetDERControl", mock.Anything).Return(setDERControlResponse, nil).Run(func(args mock.Arguments) {
+		request, ok := args.Get(0).(*der.SetDERControlRequest)
+		require.True(t, ok)
+		require.NotNil(t, request)
+		assert.Equal(t, isDefault, request.IsDefault)
+		assert.Equal(t, controlID, request.ControlID)
+		assert.Equal(t, controlType, request.ControlType)
+		assert.Equal(t, gradient, request.Gradient)
+	})
+	setupDefaultCSMSHandlers(suite, expectedCSMSOptions{clientId: wsId, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	setupDefaultChargingStationHandlers(suite, expectedChargingStationOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(responseJson), forwardWrittenMessage: true}, handler)
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	err := suite.chargingStation.Start(wsUrl)
+	require.Nil(t, err)
+	resultChannel := make(chan bool, 1)
+	err = suite.csms.SetDERControl(wsId, func(response *der.SetDERControlResponse, err error) {
+		require.Nil(t, err)
+		require.NotNil(t, response)
+		assert.Equal(t, status, response.Status)
+		assert.Equal(t, []string{supersededID}, response.SupersededIDs)
+		resultChannel <- true
+	}, isDefault, controlID, controlType, func(request *der.SetDERControlRequest) {
+		request.Gradient = gradient
+	})
+	require.Nil(t, err)
+	result := <-resultChannel
+	assert.True(t, result)
+}
+
+func (suite *OcppV2TestSuite) TestSetDERControlInvalidEndpoint() {
+	messageId := defaultMessageId
+	isDefault := false
+	controlID := "control1"
+	controlType := der.DERControlEnterService
+	request := der.NewSetDERControlRequest(isDefault, controlID, controlType)
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"isDefault":%v,"controlId":"%v","controlType":"%v"}]`,
+		messageId, der.SetDERControlFeatureName, isDefault, controlID, controlType)
+	testUnsupportedRequestFromChargingStation(suite, request, requestJson, messageId)
+}
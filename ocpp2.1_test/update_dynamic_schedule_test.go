@@ -0,0 +1,89 @@
+package ocpp21_test
+
+import (
+	"fmt"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/smartcharging"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+)
+
+// Test
+func (suite *OcppV2TestSuite) TestUpdateDynamicScheduleRequestValidation() {
+	t := suite.T()
+	var requestTable = []GenericTestEntry{
+		{smartcharging.UpdateDynamicScheduleRequest{ChargingProfileID: 1, ScheduleUpdate: smartcharging.ChargingScheduleUpdate{Limit: newFloat(16.0), LimitL2: newFloat(16.0), LimitL3: newFloat(16.0)}}, true},
+		{smartcharging.UpdateDynamicScheduleRequest{ChargingProfileID: 1, ScheduleUpdate: smartcharging.ChargingScheduleUpdate{DischargeLimit: newFloat(-10.0), Setpoint: newFloat(-5.0), SetpointReactive: newFloat(2.0)}}, true},
+		{smartcharging.UpdateDynamicScheduleRequest{ChargingProfileID: 1, ScheduleUpdate: smartcharging.ChargingScheduleUpdate{}}, true},
+		{smartcharging.UpdateDynamicScheduleRequest{}, true},
+		{smartcharging.UpdateDynamicScheduleRequest{ChargingProfileID: 1, ScheduleUpdate: smartcharging.ChargingScheduleUpdate{DischargeLimit: newFloat(10.0)}}, false},
+		{smartcharging.UpdateDynamicScheduleRequest{ChargingProfileID: 1, ScheduleUpdate: smartcharging.ChargingScheduleUpdate{DischargeLimitL2: newFloat(1.0)}}, false},
+		{smartcharging.UpdateDynamicScheduleRequest{ChargingProfileID: 1, ScheduleUpdate: smartcharging.ChargingScheduleUpdate{DischargeLimitL3: newFloat(1.0)}}, false},
+	}
+	ExecuteGenericTestTable(t, requestTable)
+}
+
+func (suite *OcppV2TestSuite) TestUpdateDynamicScheduleResponseValidation() {
+	t := suite.T()
+	var confirmationTable = []GenericTestEntry{
+		{smartcharging.UpdateDynamicScheduleResponse{Status: smartcharging.ChargingProfileStatusAccepted, StatusInfo: types.NewStatusInfo("200", "")}, true},
+		{smartcharging.UpdateDynamicScheduleResponse{Status: smartcharging.ChargingProfileStatusRejected}, true},
+		{smartcharging.UpdateDynamicScheduleResponse{}, false},
+		{smartcharging.UpdateDynamicScheduleResponse{Status: "invalidStatus"}, false},
+		{smartcharging.UpdateDynamicScheduleResponse{Status: smartcharging.ChargingProfileStatusAccepted, StatusInfo: types.NewStatusInfo("", "")}, false},
+	}
+	ExecuteGenericTestTable(t, confirmationTable)
+}
+
+func (suite *OcppV2TestSuite) TestUpdateDynamicScheduleE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	chargingProfileID := 1
+	scheduleUpdate := smartcharging.ChargingScheduleUpdate{Limit: newFloat(16.0), DischargeLimit: newFloat(-8.0)}
+	status := smartcharging.ChargingProfileStatusAccepted
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"chargingProfileId":%v,"scheduleUpdate":{"limit":%v,"dischargeLimit":%v}}]`,
+		messageId, smartcharging.UpdateDynamicScheduleFeatureName, chargingProfileID, *scheduleUpdate.Limit, *scheduleUpdate.DischargeLimit)
+	responseJson := fmt.Sprintf(`[3,"%v",{"status":"%v"}]`, messageId, status)
+	updateDynamicScheduleResponse := smartcharging.NewUpdateDynamicScheduleResponse(status)
+	channel := NewMockWebSocket(wsId)
+
+	handler := &MockChargingStationSmartChargingHandler{}
+	handler.On("OnUpdateDynamicSchedule", mock.Anything).Return(updateDynamicScheduleResponse, nil).Run(func(args mock.Arguments) {
+		request, ok := args.Get(0).(*smartcharging.UpdateDynamicScheduleRequest)
+		require.True(t, ok)
+		require.NotNil(t, request)
+		assert.Equal(t, chargingProfileID, request.ChargingProfileID)
+		assert.Equal(t, scheduleUpdate, request.ScheduleUpdate)
+	})
+	setupDefaultCSMSHandlers(suite, expectedCSMSOptions{clientId: wsId, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	setupDefaultChargingStationHandlers(suite, expectedChargingStationOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(responseJson), forwardWrittenMessage: true}, handler)
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	err := suite.chargingStation.Start(wsUrl)
+	require.Nil(t, err)
+	resultChannel := make(chan bool, 1)
+	err = suite.csms.UpdateDynamicSchedule(wsId, func(response *smartcharging.UpdateDynamicScheduleResponse, err error) {
+		require.Nil(t, err)
+		require.NotNil(t, response)
+		assert.Equal(t, status, response.Status)
+		resultChannel <- true
+	}, chargingProfileID, scheduleUpdate)
+	require.Nil(t, err)
+	result := <-resultChannel
+	assert.True(t, result)
+}
+
+func (suite *OcppV2TestSuite) TestUpdateDynamicScheduleInvalidEndpoint() {
+	messageId := defaultMessageId
+	chargingProfileID := 1
+	scheduleUpdate := smartcharging.ChargingScheduleUpdate{Limit: newFloat(16.0)}
+	request := smartcharging.NewUpdateDynamicScheduleRequest(chargingProfileID, scheduleUpdate)
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"chargingProfileId":%v,"scheduleUpdate":{"limit":%v}}]`,
+		messageId, smartcharging.UpdateDynamicScheduleFeatureName, chargingProfileID, *scheduleUpdate.Limit)
+	testUnsupportedRequestFromChargingStation(suite, request, requestJson, messageId)
+}
@@ -0,0 +1,87 @@
+package ocpp21_test
+
+import (
+	"fmt"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/smartcharging"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+)
+
+// Test
+func (suite *OcppV2TestSuite) TestUsePriorityChargingRequestValidation() {
+	t := suite.T()
+	var requestTable = []GenericTestEntry{
+		{smartcharging.UsePriorityChargingRequest{TransactionID: "1234", Activate: true}, true},
+		{smartcharging.UsePriorityChargingRequest{TransactionID: "1234"}, true},
+		{smartcharging.UsePriorityChargingRequest{Activate: true}, false},
+		{smartcharging.UsePriorityChargingRequest{TransactionID: ">36..................................", Activate: true}, false},
+	}
+	ExecuteGenericTestTable(t, requestTable)
+}
+
+func (suite *OcppV2TestSuite) TestUsePriorityChargingResponseValidation() {
+	t := suite.T()
+	var confirmationTable = []GenericTestEntry{
+		{smartcharging.UsePriorityChargingResponse{Status: smartcharging.PriorityChargingStatusAccepted, StatusInfo: types.NewStatusInfo("200", "")}, true},
+		{smartcharging.UsePriorityChargingResponse{Status: smartcharging.PriorityChargingStatusRejected}, true},
+		{smartcharging.UsePriorityChargingResponse{Status: smartcharging.PriorityChargingStatusNoProfile}, true},
+		{smartcharging.UsePriorityChargingResponse{}, false},
+		{smartcharging.UsePriorityChargingResponse{Status: "invalidStatus"}, false},
+		{smartcharging.UsePriorityChargingResponse{Status: smartcharging.PriorityChargingStatusAccepted, StatusInfo: types.NewStatusInfo("", "")}, false},
+	}
+	ExecuteGenericTestTable(t, confirmationTable)
+}
+
+func (suite *OcppV2TestSuite) TestUsePriorityChargingE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	transactionID := "1234"
+	activate := true
+	status := smartcharging.PriorityChargingStatusAccepted
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"transactionId":"%v","activate":%v}]`,
+		messageId, smartcharging.UsePriorityChargingFeatureName, transactionID, activate)
+	responseJson := fmt.Sprintf(`[3,"%v",{"status":"%v"}]`, messageId, status)
+	usePriorityChargingResponse := smartcharging.NewUsePriorityChargingResponse(status)
+	channel := NewMockWebSocket(wsId)
+
+	handler := &MockChargingStationSmartChargingHandler{}
+	handler.On("OnUsePriorityCharging", mock.Anything).Return(usePriorityChargingResponse, nil).Run(func(args mock.Arguments) {
+		request, ok := args.Get(0).(*smartcharging.UsePriorityChargingRequest)
+		require.True(t, ok)
+		require.NotNil(t, request)
+		assert.Equal(t, transactionID, request.TransactionID)
+		assert.Equal(t, activate, request.Activate)
+	})
+	setupDefaultCSMSHandlers(suite, expectedCSMSOptions{clientId: wsId, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	setupDefaultChargingStationHandlers(suite, expectedChargingStationOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(responseJson), forwardWrittenMessage: true}, handler)
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	err := suite.chargingStation.Start(wsUrl)
+	require.Nil(t, err)
+	resultChannel := make(chan bool, 1)
+	err = suite.csms.UsePriorityCharging(wsId, func(response *smartcharging.UsePriorityChargingResponse, err error) {
+		require.Nil(t, err)
+		require.NotNil(t, response)
+		assert.Equal(t, status, response.Status)
+		resultChannel <- true
+	}, transactionID, activate)
+	require.Nil(t, err)
+	result := <-resultChannel
+	assert.True(t, result)
+}
+
+func (suite *OcppV2TestSuite) TestUsePriorityChargingInvalidEndpoint() {
+	messageId := defaultMessageId
+	transactionID := "1234"
+	activate := true
+	request := smartcharging.NewUsePriorityChargingRequest(transactionID, activate)
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"transactionId":"%v","activate":%v}]`,
+		messageId, smartcharging.UsePriorityChargingFeatureName, transactionID, activate)
+	testUnsupportedRequestFromChargingStation(suite, request, requestJson, messageId)
+}
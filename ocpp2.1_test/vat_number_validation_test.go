@@ -0,0 +1,93 @@
+package ocpp21_test
+
+import (
+	"fmt"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/payment"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.1/types"
+)
+
+// Test
+func (suite *OcppV2TestSuite) TestVatNumberValidationRequestValidation() {
+	t := suite.T()
+	var requestTable = []GenericTestEntry{
+		{payment.VatNumberValidationRequest{VatNumber: "NL123", EvseID: newInt(1)}, true},
+		{payment.VatNumberValidationRequest{VatNumber: "NL123"}, true},
+		{payment.VatNumberValidationRequest{}, false},
+		{payment.VatNumberValidationRequest{VatNumber: ">20.................."}, false},
+		{payment.VatNumberValidationRequest{VatNumber: "NL123", EvseID: newInt(-1)}, false},
+	}
+	ExecuteGenericTestTable(t, requestTable)
+}
+
+func (suite *OcppV2TestSuite) TestVatNumberValidationResponseValidation() {
+	t := suite.T()
+	company := &payment.Address{Name: "company", Address1: "street 1", City: "city", Country: "country"}
+	var confirmationTable = []GenericTestEntry{
+		{payment.VatNumberValidationResponse{Company: company, StatusInfo: types.NewStatusInfo("200", ""), VatNumber: "NL123", EvseID: newInt(1), Status: types.GenericStatusAccepted}, true},
+		{payment.VatNumberValidationResponse{VatNumber: "NL123", Status: types.GenericStatusRejected}, true},
+		{payment.VatNumberValidationResponse{Status: types.GenericStatusAccepted}, false},
+		{payment.VatNumberValidationResponse{VatNumber: "NL123"}, false},
+		{payment.VatNumberValidationResponse{VatNumber: "NL123", Status: "invalidStatus"}, false},
+		{payment.VatNumberValidationResponse{VatNumber: "NL123", Status: types.GenericStatusAccepted, Company: &payment.Address{Name: "company"}}, false},
+		{payment.VatNumberValidationResponse{VatNumber: "NL123", Status: types.GenericStatusAccepted, EvseID: newInt(-1)}, false},
+	}
+	ExecuteGenericTestTable(t, confirmationTable)
+}
+
+func (suite *OcppV2TestSuite) TestVatNumberValidationE2EMocked() {
+	t := suite.T()
+	wsId := "test_id"
+	messageId := defaultMessageId
+	wsUrl := "someUrl"
+	vatNumber := "NL123"
+	evseID := 1
+	status := types.GenericStatusAccepted
+	company := payment.Address{Name: "company", Address1: "street 1", City: "city", Country: "country"}
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"vatNumber":"%v","evseId":%v}]`,
+		messageId, payment.VatNumberValidationFeatureName, vatNumber, evseID)
+	responseJson := fmt.Sprintf(`[3,"%v",{"company":{"name":"%v","address1":"%v","city":"%v","country":"%v"},"vatNumber":"%v","evseId":%v,"status":"%v"}]`,
+		messageId, company.Name, company.Address1, company.City, company.Country, vatNumber, evseID, status)
+	vatNumberValidationResponse := payment.NewVatNumberValidationResponse(vatNumber, status)
+	vatNumberValidationResponse.Company = &company
+	vatNumberValidationResponse.EvseID = newInt(evseID)
+	channel := NewMockWebSocket(wsId)
+
+	handler := &MockCSMSPaymentHandler{}
+	handler.On("OnVatNumberValidation", mock.AnythingOfType("string"), mock.Anything).Return(vatNumberValidationResponse, nil).Run(func(args mock.Arguments) {
+		request, ok := args.Get(1).(*payment.VatNumberValidationRequest)
+		require.True(t, ok)
+		require.NotNil(t, request)
+		assert.Equal(t, vatNumber, request.VatNumber)
+		require.NotNil(t, request.EvseID)
+		assert.Equal(t, evseID, *request.EvseID)
+	})
+	setupDefaultCSMSHandlers(suite, expectedCSMSOptions{clientId: wsId, rawWrittenMessage: []byte(responseJson), forwardWrittenMessage: true}, handler)
+	setupDefaultChargingStationHandlers(suite, expectedChargingStationOptions{serverUrl: wsUrl, clientId: wsId, createChannelOnStart: true, channel: channel, rawWrittenMessage: []byte(requestJson), forwardWrittenMessage: true})
+	// Run Test
+	suite.csms.Start(8887, "somePath")
+	err := suite.chargingStation.Start(wsUrl)
+	require.Nil(t, err)
+	response, err := suite.chargingStation.VatNumberValidation(vatNumber, func(request *payment.VatNumberValidationRequest) {
+		request.EvseID = newInt(evseID)
+	})
+	require.Nil(t, err)
+	require.NotNil(t, response)
+	assert.Equal(t, vatNumber, response.VatNumber)
+	assert.Equal(t, status, response.Status)
+	require.NotNil(t, response.Company)
+	assert.Equal(t, company, *response.Company)
+}
+
+func (suite *OcppV2TestSuite) TestVatNumberValidationInvalidEndpoint() {
+	messageId := defaultMessageId
+	vatNumber := "NL123"
+	request := payment.NewVatNumberValidationRequest(vatNumber)
+	requestJson := fmt.Sprintf(`[2,"%v","%v",{"vatNumber":"%v"}]`,
+		messageId, payment.VatNumberValidationFeatureName, vatNumber)
+	testUnsupportedRequestFromCentralSystem(suite, request, requestJson, messageId)
+}
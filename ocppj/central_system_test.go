@@ -1,8 +1,11 @@
 package ocppj_test
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"net"
+	"net/http"
 	"strconv"
 	"sync"
 	"time"
@@ -508,6 +511,218 @@ func (suite *OcppJTestSuite) TestCentralSystemErrorHandler() {
 	assert.Nil(t, err)
 }
 
+func (suite *OcppJTestSuite) TestCentralSystemSendRequestTooLarge() {
+	mockChargePointId := "1234"
+	suite.mockServer.On("Start", mock.AnythingOfType("int"), mock.AnythingOfType("string")).Return(nil)
+	suite.centralSystem.SetMaxMessageSize(20)
+	suite.centralSystem.Start(8887, "/{ws}")
+	suite.serverDispatcher.CreateClient(mockChargePointId)
+	mockRequest := newMockRequest("mockValue")
+	err := suite.centralSystem.SendRequest(mockChargePointId, mockRequest)
+	require.Error(suite.T(), err)
+	assert.Contains(suite.T(), err.Error(), "exceeds maximum message size of 20 bytes")
+	suite.mockServer.AssertNotCalled(suite.T(), "Write", mock.Anything, mock.Anything)
+}
+
+func (suite *OcppJTestSuite) TestCentralSystemIncomingCallTooLarge() {
+	t := suite.T()
+	mockChargePointId := "1234"
+	mockUniqueId := "5678"
+	mockCall := fmt.Sprintf(`[2,"%v","%v",{"mockValue":"someValue"}]`, mockUniqueId, MockFeatureName)
+	maxSize := len(mockCall) - 1
+	expectedError := fmt.Sprintf(`[4,"%v","%v","message size of %v bytes exceeds maximum message size of %v bytes",{}]`, mockUniqueId, ocppj.FormatViolationV16, len(mockCall), maxSize)
+	writeC := make(chan []byte, 1)
+	suite.mockServer.On("Write", mockChargePointId, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		writeC <- args.Get(1).([]byte)
+	})
+	suite.mockServer.On("Start", mock.AnythingOfType("int"), mock.AnythingOfType("string")).Return(nil)
+	suite.centralSystem.SetRequestHandler(func(client ws.Channel, request ocpp.Request, requestId string, action string) {
+		assert.Fail(t, "unexpected request handler invocation")
+	})
+	suite.centralSystem.SetMaxMessageSize(maxSize)
+	suite.centralSystem.Start(8887, "somePath")
+	// Simulate charge point message
+	channel := NewMockWebSocket(mockChargePointId)
+	err := suite.mockServer.MessageHandler(channel, []byte(mockCall))
+	require.Error(t, err)
+	ocppErr, ok := err.(*ocpp.Error)
+	require.True(t, ok)
+	assert.Equal(t, ocppj.FormatViolationV16, ocppErr.Code)
+	assert.Equal(t, mockUniqueId, ocppErr.MessageId)
+	select {
+	case data := <-writeC:
+		assert.Equal(t, expectedError, string(data))
+	case <-time.After(time.Second):
+		assert.Fail(t, "timeout waiting for call error")
+	}
+}
+
+func (suite *OcppJTestSuite) TestCentralSystemIncomingCallResultTooLarge() {
+	t := suite.T()
+	mockChargePointId := "1234"
+	mockUniqueId := "5678"
+	mockRequest := newMockRequest("testValue")
+	mockCallResult := fmt.Sprintf(`[3,"%v",{"mockValue":"someValue"}]`, mockUniqueId)
+	errorC := make(chan *ocpp.Error, 1)
+	suite.centralSystem.SetResponseHandler(func(client ws.Channel, response ocpp.Response, requestId string) {
+		assert.Fail(t, "unexpected response handler invocation")
+	})
+	suite.centralSystem.SetErrorHandler(func(client ws.Channel, err *ocpp.Error, details interface{}) {
+		assert.Equal(t, mockChargePointId, client.ID())
+		errorC <- err
+	})
+	suite.mockServer.On("Start", mock.AnythingOfType("int"), mock.AnythingOfType("string")).Return(nil)
+	suite.centralSystem.SetMaxMessageSize(10)
+	suite.centralSystem.Start(8887, "somePath")
+	suite.serverDispatcher.CreateClient(mockChargePointId)
+	addMockPendingRequest(suite, mockRequest, mockUniqueId, mockChargePointId)
+	// Simulate charge point message
+	channel := NewMockWebSocket(mockChargePointId)
+	err := suite.mockServer.MessageHandler(channel, []byte(mockCallResult))
+	require.Error(t, err)
+	ocppErr := <-errorC
+	assert.Equal(t, ocppj.FormatViolationV16, ocppErr.Code)
+	assert.Equal(t, mockUniqueId, ocppErr.MessageId)
+	assert.False(t, suite.centralSystem.RequestState.HasPendingRequest(mockChargePointId))
+}
+
+func (suite *OcppJTestSuite) TestCentralSystemServe() {
+	t := suite.T()
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	suite.mockServer.On("Serve", listener, "/{ws}").Return()
+	suite.centralSystem.Serve(listener, "/{ws}")
+	suite.mockServer.AssertCalled(t, "Serve", listener, "/{ws}")
+	assert.True(t, suite.serverDispatcher.IsRunning())
+	assert.NotNil(t, suite.mockServer.MessageHandler)
+	assert.NotNil(t, suite.mockServer.NewClientHandler)
+	assert.NotNil(t, suite.mockServer.DisconnectedClientHandler)
+}
+
+func (suite *OcppJTestSuite) TestCentralSystemHandler() {
+	t := suite.T()
+	mockHandler := http.NotFoundHandler()
+	suite.mockServer.On("Handler").Return(mockHandler)
+	handler := suite.centralSystem.Handler()
+	assert.NotNil(t, handler)
+	assert.True(t, suite.serverDispatcher.IsRunning())
+	assert.NotNil(t, suite.mockServer.MessageHandler)
+	// Connections accepted through the handler are processed as usual
+	mockChargePointId := "1234"
+	suite.mockServer.NewClientHandler(NewMockWebSocket(mockChargePointId))
+	suite.mockServer.On("Write", mockChargePointId, mock.Anything).Return(nil)
+	err := suite.centralSystem.SendRequest(mockChargePointId, newMockRequest("mockValue"))
+	assert.NoError(t, err)
+}
+
+func (suite *OcppJTestSuite) TestCentralSystemShutdown() {
+	t := suite.T()
+	mockChargePointId := "1234"
+	mockUniqueId := "5678"
+	mockRequest := newMockRequest("testValue")
+	mockCallResult := fmt.Sprintf(`[3,"%v",{"mockValue":"someValue"}]`, mockUniqueId)
+	suite.mockServer.On("Start", mock.AnythingOfType("int"), mock.AnythingOfType("string")).Return(nil)
+	suite.mockServer.On("Shutdown", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		// Connections are kept open until the passed context is done
+		ctx := args.Get(0).(context.Context)
+		<-ctx.Done()
+	})
+	suite.centralSystem.SetResponseHandler(func(client ws.Channel, response ocpp.Response, requestId string) {
+		assert.Equal(t, mockUniqueId, requestId)
+	})
+	suite.centralSystem.Start(8887, "somePath")
+	suite.serverDispatcher.CreateClient(mockChargePointId)
+	addMockPendingRequest(suite, mockRequest, mockUniqueId, mockChargePointId)
+	// Shutdown, while a request is pending
+	resultC := make(chan error, 1)
+	go func() {
+		resultC <- suite.centralSystem.Shutdown(context.Background())
+	}()
+	time.Sleep(100 * time.Millisecond)
+	assert.True(t, suite.centralSystem.IsShuttingDown())
+	select {
+	case <-resultC:
+		assert.Fail(t, "shutdown returned before pending request completed")
+	default:
+	}
+	// New requests are rejected while shutting down
+	err := suite.centralSystem.SendRequest(mockChargePointId, newMockRequest("someValue"))
+	require.Error(t, err)
+	assert.Equal(t, "ocppj server is shutting down, couldn't send request", err.Error())
+	// Complete pending request
+	channel := NewMockWebSocket(mockChargePointId)
+	err = suite.mockServer.MessageHandler(channel, []byte(mockCallResult))
+	require.NoError(t, err)
+	select {
+	case err = <-resultC:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		assert.Fail(t, "timeout waiting for shutdown")
+	}
+	assert.False(t, suite.serverDispatcher.IsRunning())
+	suite.mockServer.AssertCalled(t, "Shutdown", mock.Anything)
+}
+
+func (suite *OcppJTestSuite) TestCentralSystemShutdownTimeout() {
+	t := suite.T()
+	mockChargePointId := "1234"
+	mockUniqueId := "5678"
+	mockRequest := newMockRequest("testValue")
+	suite.mockServer.On("Start", mock.AnythingOfType("int"), mock.AnythingOfType("string")).Return(nil)
+	suite.mockServer.On("Shutdown", mock.Anything).Return(nil)
+	suite.centralSystem.Start(8887, "somePath")
+	suite.serverDispatcher.CreateClient(mockChargePointId)
+	addMockPendingRequest(suite, mockRequest, mockUniqueId, mockChargePointId)
+	// Shutdown, while a request is pending. The request never completes.
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	err := suite.centralSystem.Shutdown(ctx)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.False(t, suite.centralSystem.RequestState.HasPendingRequests())
+	assert.False(t, suite.serverDispatcher.IsRunning())
+}
+
+// Custom dispatcher, which doesn't report queued requests.
+type customServerDispatcher struct {
+	ocppj.ServerDispatcher
+}
+
+func (suite *OcppJTestSuite) TestCentralSystemShutdownCustomDispatcher() {
+	t := suite.T()
+	mockChargePointId := "1234"
+	mockUniqueId := "5678"
+	mockRequest := newMockRequest("testValue")
+	mockCallResult := fmt.Sprintf(`[3,"%v",{"mockValue":"someValue"}]`, mockUniqueId)
+	suite.mockServer.On("Start", mock.AnythingOfType("int"), mock.AnythingOfType("string")).Return(nil)
+	suite.mockServer.On("Shutdown", mock.Anything).Return(nil)
+	suite.centralSystem = ocppj.NewServer(suite.mockServer, customServerDispatcher{suite.serverDispatcher}, nil, ocpp.NewProfile("mock", &MockFeature{}))
+	suite.centralSystem.SetDialect(ocpp.V16)
+	suite.centralSystem.Start(8887, "somePath")
+	suite.serverDispatcher.CreateClient(mockChargePointId)
+	addMockPendingRequest(suite, mockRequest, mockUniqueId, mockChargePointId)
+	// Shutdown waits for the pending request only
+	resultC := make(chan error, 1)
+	go func() {
+		resultC <- suite.centralSystem.Shutdown(context.Background())
+	}()
+	time.Sleep(100 * time.Millisecond)
+	select {
+	case <-resultC:
+		assert.Fail(t, "shutdown returned before pending request completed")
+	default:
+	}
+	err := suite.mockServer.MessageHandler(NewMockWebSocket(mockChargePointId), []byte(mockCallResult))
+	require.NoError(t, err)
+	select {
+	case err = <-resultC:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		assert.Fail(t, "timeout waiting for shutdown")
+	}
+}
+
 func addMockPendingRequest(suite *OcppJTestSuite, mockRequest ocpp.Request, mockUniqueID string, mockChargePointID string) {
 	mockCall, _ := suite.centralSystem.CreateCall(mockRequest)
 	mockCall.UniqueId = mockUniqueID
@@ -749,3 +964,123 @@ func (suite *OcppJTestSuite) TestServerRequestFlow() {
 	q, _ = suite.serverRequestMap.Get(mockChargePoint2)
 	assert.True(t, q.IsEmpty())
 }
+
+// ----------------- Raw mode tests -----------------
+
+func (suite *OcppJTestSuite) TestCentralSystemRawRequestHandler() {
+	t := suite.T()
+	mockChargePointId := "1234"
+	mockUniqueId := "5678"
+	mockAction := "VendorAction"
+	mockPayload := `{"b": 2, "a": [1, 2.50]}`
+	mockRequest := fmt.Sprintf(`[2,"%v","%v",%v]`, mockUniqueId, mockAction, mockPayload)
+	mockResponse := fmt.Sprintf(`[3,"%v",{"accepted":true}]`, mockUniqueId)
+	writeC := make(chan []byte, 1)
+	suite.centralSystem.SetRawRequestHandler(func(chargePoint ws.Channel, payload json.RawMessage, requestId string, action string) {
+		assert.Equal(t, mockChargePointId, chargePoint.ID())
+		assert.Equal(t, mockUniqueId, requestId)
+		assert.Equal(t, mockAction, action)
+		// The payload is passed along as is
+		assert.Equal(t, mockPayload, string(payload))
+		err := suite.centralSystem.SendRawResponse(chargePoint.ID(), requestId, json.RawMessage(`{"accepted":true}`))
+		assert.Nil(t, err)
+	})
+	suite.mockServer.On("Start", mock.AnythingOfType("int"), mock.AnythingOfType("string")).Return()
+	suite.mockServer.On("Write", mockChargePointId, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		writeC <- args.Get(1).([]byte)
+	})
+	suite.centralSystem.Start(8887, "somePath")
+	suite.serverDispatcher.CreateClient(mockChargePointId)
+	// Simulate charge point message
+	channel := NewMockWebSocket(mockChargePointId)
+	err := suite.mockServer.MessageHandler(channel, []byte(mockRequest))
+	assert.Nil(t, err)
+	assert.Equal(t, mockResponse, string(<-writeC))
+}
+
+func (suite *OcppJTestSuite) TestCentralSystemSendRawRequest() {
+	t := suite.T()
+	mockChargePointId := "1234"
+	mockAction := "VendorAction"
+	mockResponsePayload := `{"result": "ok"}`
+	writeC := make(chan []byte, 1)
+	responseC := make(chan ocpp.Response, 1)
+	suite.centralSystem.SetResponseHandler(func(chargePoint ws.Channel, response ocpp.Response, requestId string) {
+		assert.Equal(t, mockChargePointId, chargePoint.ID())
+		responseC <- response
+	})
+	suite.mockServer.On("Start", mock.AnythingOfType("int"), mock.AnythingOfType("string")).Return()
+	suite.mockServer.On("Write", mockChargePointId, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		writeC <- args.Get(1).([]byte)
+	})
+	suite.centralSystem.Start(8887, "somePath")
+	suite.serverDispatcher.CreateClient(mockChargePointId)
+	err := suite.centralSystem.SendRawRequest(mockChargePointId, mockAction, json.RawMessage(`{"x":1}`))
+	require.Nil(t, err)
+	var fields []json.RawMessage
+	require.NoError(t, json.Unmarshal(<-writeC, &fields))
+	require.Len(t, fields, 4)
+	var uniqueId string
+	require.NoError(t, json.Unmarshal(fields[1], &uniqueId))
+	assert.Equal(t, fmt.Sprintf(`"%v"`, mockAction), string(fields[2]))
+	assert.Equal(t, `{"x":1}`, string(fields[3]))
+	// Simulate charge point response
+	channel := NewMockWebSocket(mockChargePointId)
+	err = suite.mockServer.MessageHandler(channel, []byte(fmt.Sprintf(`[3,"%v",%v]`, uniqueId, mockResponsePayload)))
+	require.Nil(t, err)
+	response := <-responseC
+	rawResponse, ok := response.(*ocppj.RawResponse)
+	require.True(t, ok)
+	assert.Equal(t, mockAction, rawResponse.Action)
+	assert.Equal(t, mockResponsePayload, string(rawResponse.Payload))
+}
+
+func (suite *OcppJTestSuite) TestCentralSystemSendHandler() {
+	t := suite.T()
+	mockChargePointId := "1234"
+	mockUniqueId := "5678"
+	mockValue := "someValue"
+	requestC := make(chan ocpp.Request, 1)
+	suite.centralSystem.SetSendHandler(func(chargePoint ws.Channel, request ocpp.Request, requestId string, action string) {
+		assert.Equal(t, mockChargePointId, chargePoint.ID())
+		assert.Equal(t, mockUniqueId, requestId)
+		assert.Equal(t, MockFeatureName, action)
+		requestC <- request
+	})
+	suite.mockServer.On("Start", mock.AnythingOfType("int"), mock.AnythingOfType("string")).Return()
+	suite.centralSystem.Start(8887, "somePath")
+	suite.serverDispatcher.CreateClient(mockChargePointId)
+	// Simulate charge point message
+	channel := NewMockWebSocket(mockChargePointId)
+	err := suite.mockServer.MessageHandler(channel, []byte(fmt.Sprintf(`[6,"%v","%v",{"mockValue":"%v"}]`, mockUniqueId, MockFeatureName, mockValue)))
+	require.Nil(t, err)
+	request := <-requestC
+	require.IsType(t, &MockRequest{}, request)
+	assert.Equal(t, mockValue, request.(*MockRequest).MockValue)
+	// Unconfirmed requests for unsupported actions are never replied to
+	err = suite.mockServer.MessageHandler(channel, []byte(fmt.Sprintf(`[6,"%v","UnknownAction",{}]`, mockUniqueId)))
+	require.Error(t, err)
+	suite.mockServer.AssertNotCalled(t, "Write", mock.Anything, mock.Anything)
+}
+
+func (suite *OcppJTestSuite) TestCentralSystemSendUnconfirmedRequest() {
+	t := suite.T()
+	mockChargePointId := "1234"
+	writeC := make(chan []byte, 1)
+	suite.mockServer.On("Start", mock.AnythingOfType("int"), mock.AnythingOfType("string")).Return()
+	suite.mockServer.On("Write", mockChargePointId, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		writeC <- args.Get(1).([]byte)
+	})
+	suite.centralSystem.Start(8887, "somePath")
+	suite.serverDispatcher.CreateClient(mockChargePointId)
+	err := suite.centralSystem.SendUnconfirmedRequest(mockChargePointId, newMockRequest("someValue"))
+	require.Nil(t, err)
+	var fields []json.RawMessage
+	require.NoError(t, json.Unmarshal(<-writeC, &fields))
+	require.Len(t, fields, 4)
+	assert.Equal(t, "6", string(fields[0]))
+	assert.Equal(t, fmt.Sprintf(`"%v"`, MockFeatureName), string(fields[2]))
+	// Invalid requests aren't sent
+	err = suite.centralSystem.SendUnconfirmedRequest(mockChargePointId, newMockRequest(""))
+	assert.Error(t, err)
+}
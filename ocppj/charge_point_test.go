@@ -13,6 +13,7 @@ import (
 
 	"github.com/lorenzodonini/ocpp-go/ocpp"
 	"github.com/lorenzodonini/ocpp-go/ocppj"
+	"github.com/lorenzodonini/ocpp-go/ws"
 )
 
 // ----------------- Start tests -----------------
@@ -37,6 +38,46 @@ func (suite *OcppJTestSuite) TestChargePointStartFailed() {
 	assert.NotNil(suite.T(), err)
 }
 
+func (suite *OcppJTestSuite) TestChargePointStartWithFailover() {
+	t := suite.T()
+	suite.mockClient.On("StartWithFailover", mock.AnythingOfType("ws.FailoverConfig")).Return(nil).Run(func(args mock.Arguments) {
+		config := args.Get(0).(ws.FailoverConfig)
+		require.Len(t, config.Endpoints, 2)
+		// Client ID is appended to every endpoint
+		assert.Equal(t, "ws://primary/ocpp/mock_id", config.Endpoints[0].URL)
+		assert.Equal(t, "ws://backup/ocpp/mock_id", config.Endpoints[1].URL)
+		assert.Equal(t, "backupUser", config.Endpoints[1].Username)
+		assert.Equal(t, 3, config.MaxAttemptsPerEndpoint)
+	})
+	suite.mockClient.On("ConnectionState").Return(ws.ConnectionStateEvent{State: ws.ConnectionStateConnected})
+	config := ws.FailoverConfig{
+		Endpoints: []ws.ServerEndpoint{
+			{URL: "ws://primary/ocpp"},
+			{URL: "ws://backup/ocpp", Username: "backupUser", Password: "backupPassword"},
+		},
+		MaxAttemptsPerEndpoint: 3,
+	}
+	err := suite.chargePoint.StartWithFailover(config)
+	require.NoError(t, err)
+	assert.True(t, suite.clientDispatcher.IsRunning())
+	// Original configuration is not modified
+	assert.Equal(t, "ws://primary/ocpp", config.Endpoints[0].URL)
+}
+
+func (suite *OcppJTestSuite) TestChargePointStartWithFailoverGaveUp() {
+	t := suite.T()
+	lastErr := fmt.Errorf("connection refused")
+	suite.mockClient.On("StartWithFailover", mock.AnythingOfType("ws.FailoverConfig")).Return(nil)
+	suite.mockClient.On("ConnectionState").Return(ws.ConnectionStateEvent{State: ws.ConnectionStateGaveUp, Attempt: 3, LastError: lastErr})
+	config := ws.FailoverConfig{
+		Endpoints: []ws.ServerEndpoint{{URL: "ws://primary/ocpp"}},
+	}
+	err := suite.chargePoint.StartWithFailover(config)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, lastErr)
+	assert.False(t, suite.clientDispatcher.IsRunning())
+}
+
 func (suite *OcppJTestSuite) TestClientNotStartedError() {
 	t := suite.T()
 	// Start normally
@@ -422,6 +463,44 @@ func (suite *OcppJTestSuite) TestChargePointCallErrorHandler() {
 	assert.Nil(t, err)
 }
 
+func (suite *OcppJTestSuite) TestChargePointSendRequestTooLarge() {
+	suite.mockClient.On("Start", mock.AnythingOfType("string")).Return(nil)
+	suite.chargePoint.SetMaxMessageSize(20)
+	_ = suite.chargePoint.Start("someUrl")
+	mockRequest := newMockRequest("mockValue")
+	err := suite.chargePoint.SendRequest(mockRequest)
+	require.Error(suite.T(), err)
+	assert.Contains(suite.T(), err.Error(), "exceeds maximum message size of 20 bytes")
+	suite.mockClient.AssertNotCalled(suite.T(), "Write", mock.Anything)
+}
+
+func (suite *OcppJTestSuite) TestChargePointIncomingCallTooLarge() {
+	t := suite.T()
+	mockUniqueId := "5678"
+	mockCall := fmt.Sprintf(`[2,"%v","%v",{"mockValue":"someValue"}]`, mockUniqueId, MockFeatureName)
+	maxSize := len(mockCall) - 1
+	suite.chargePoint.SetDialect(ocpp.V2)
+	expectedError := fmt.Sprintf(`[4,"%v","%v","message size of %v bytes exceeds maximum message size of %v bytes",{}]`, mockUniqueId, ocppj.FormatViolationV2, len(mockCall), maxSize)
+	suite.mockClient.On("Start", mock.AnythingOfType("string")).Return(nil)
+	suite.mockClient.On("Write", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		data := args.Get(0).([]byte)
+		assert.Equal(t, expectedError, string(data))
+	})
+	suite.chargePoint.SetRequestHandler(func(request ocpp.Request, requestId string, action string) {
+		assert.Fail(t, "unexpected request handler invocation")
+	})
+	suite.chargePoint.SetMaxMessageSize(maxSize)
+	err := suite.chargePoint.Start("someUrl")
+	require.NoError(t, err)
+	// Simulate central system message
+	err = suite.mockClient.MessageHandler([]byte(mockCall))
+	require.Error(t, err)
+	ocppErr, ok := err.(*ocpp.Error)
+	require.True(t, ok)
+	assert.Equal(t, ocppj.FormatViolationV2, ocppErr.Code)
+	suite.mockClient.AssertCalled(t, "Write", mock.Anything)
+}
+
 // ----------------- Queue processing tests -----------------
 
 func (suite *OcppJTestSuite) TestClientEnqueueRequest() {
@@ -824,3 +903,176 @@ func (suite *OcppJTestSuite) TestStopDisconnectedClient() {
 	assert.True(t, suite.clientDispatcher.IsPaused())
 	assert.False(t, suite.chargePoint.IsConnected())
 }
+
+func (suite *OcppJTestSuite) TestClientDisconnectedReasonHandler() {
+	t := suite.T()
+	suite.mockClient.On("Start", mock.AnythingOfType("string")).Return(nil)
+	suite.mockClient.On("IsConnected").Return(true)
+	disconnectError := fmt.Errorf("some error")
+	expectedReason := &ws.DisconnectReason{Code: 1006, Initiator: ws.DisconnectInitiatorRemote, Err: disconnectError}
+	suite.mockClient.On("ConnectionState").Return(ws.ConnectionStateEvent{State: ws.ConnectionStateDisconnected, LastError: disconnectError, DisconnectReason: expectedReason})
+	err := suite.chargePoint.Start("someUrl")
+	require.NoError(t, err)
+	var handlerErr error
+	var reason *ws.DisconnectReason
+	suite.chargePoint.SetOnDisconnectedHandler(func(err error) {
+		handlerErr = err
+	})
+	suite.chargePoint.SetOnDisconnectedReasonHandler(func(r *ws.DisconnectReason) {
+		reason = r
+	})
+	// Trigger network disconnect
+	suite.mockClient.DisconnectedHandler(disconnectError)
+	assert.Equal(t, disconnectError, handlerErr)
+	assert.Equal(t, expectedReason, reason)
+	assert.True(t, suite.clientDispatcher.IsPaused())
+}
+
+// ----------------- Raw mode tests -----------------
+
+func (suite *OcppJTestSuite) TestChargePointRawRequestHandler() {
+	t := suite.T()
+	mockUniqueId := "5678"
+	mockAction := "VendorAction"
+	mockPayload := `{"b": 2, "a": [1, 2.50]}`
+	mockRequest := fmt.Sprintf(`[2,"%v","%v",%v]`, mockUniqueId, mockAction, mockPayload)
+	mockResponse := fmt.Sprintf(`[3,"%v",{"accepted":true}]`, mockUniqueId)
+	writeC := make(chan []byte, 1)
+	suite.chargePoint.SetRequestHandler(func(request ocpp.Request, requestId string, action string) {
+		t.Fatal("regular request handler must not be invoked for unknown actions")
+	})
+	suite.chargePoint.SetRawRequestHandler(func(payload json.RawMessage, requestId string, action string) {
+		assert.Equal(t, mockUniqueId, requestId)
+		assert.Equal(t, mockAction, action)
+		// The payload is passed along as is
+		assert.Equal(t, mockPayload, string(payload))
+		err := suite.chargePoint.SendRawResponse(requestId, json.RawMessage(`{"accepted":true}`))
+		assert.Nil(t, err)
+	})
+	suite.mockClient.On("Start", mock.AnythingOfType("string")).Return(nil)
+	suite.mockClient.On("Write", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		writeC <- args.Get(0).([]byte)
+	})
+	err := suite.chargePoint.Start("someUrl")
+	require.Nil(t, err)
+	// Simulate central system message
+	err = suite.mockClient.MessageHandler([]byte(mockRequest))
+	assert.Nil(t, err)
+	assert.Equal(t, mockResponse, string(<-writeC))
+}
+
+func (suite *OcppJTestSuite) TestChargePointUnknownActionWithoutRawRequestHandler() {
+	t := suite.T()
+	mockUniqueId := "5678"
+	mockRequest := fmt.Sprintf(`[2,"%v","VendorAction",{}]`, mockUniqueId)
+	writeC := make(chan []byte, 1)
+	suite.mockClient.On("Start", mock.AnythingOfType("string")).Return(nil)
+	suite.mockClient.On("Write", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		writeC <- args.Get(0).([]byte)
+	})
+	err := suite.chargePoint.Start("someUrl")
+	require.Nil(t, err)
+	// Simulate central system message
+	err = suite.mockClient.MessageHandler([]byte(mockRequest))
+	require.Error(t, err)
+	assert.Equal(t, fmt.Sprintf(`[4,"%v","%v","Unsupported feature VendorAction",{}]`, mockUniqueId, ocppj.NotSupported), string(<-writeC))
+}
+
+func (suite *OcppJTestSuite) TestChargePointSendRawRequest() {
+	t := suite.T()
+	mockAction := "VendorAction"
+	mockResponsePayload := `{"result": "ok"}`
+	writeC := make(chan []byte, 1)
+	responseC := make(chan ocpp.Response, 1)
+	suite.chargePoint.SetResponseHandler(func(response ocpp.Response, requestId string) {
+		responseC <- response
+	})
+	suite.mockClient.On("Start", mock.AnythingOfType("string")).Return(nil)
+	suite.mockClient.On("Write", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		writeC <- args.Get(0).([]byte)
+	})
+	err := suite.chargePoint.Start("someUrl")
+	require.Nil(t, err)
+	err = suite.chargePoint.SendRawRequest(mockAction, json.RawMessage(`{"x":1}`))
+	require.Nil(t, err)
+	var fields []json.RawMessage
+	require.NoError(t, json.Unmarshal(<-writeC, &fields))
+	require.Len(t, fields, 4)
+	var uniqueId string
+	require.NoError(t, json.Unmarshal(fields[1], &uniqueId))
+	assert.Equal(t, fmt.Sprintf(`"%v"`, mockAction), string(fields[2]))
+	assert.Equal(t, `{"x":1}`, string(fields[3]))
+	// Simulate central system response
+	err = suite.mockClient.MessageHandler([]byte(fmt.Sprintf(`[3,"%v",%v]`, uniqueId, mockResponsePayload)))
+	require.Nil(t, err)
+	response := <-responseC
+	rawResponse, ok := response.(*ocppj.RawResponse)
+	require.True(t, ok)
+	assert.Equal(t, mockAction, rawResponse.Action)
+	assert.Equal(t, mockResponsePayload, string(rawResponse.Payload))
+}
+
+func (suite *OcppJTestSuite) TestChargePointSendInvalidRawRequest() {
+	t := suite.T()
+	suite.mockClient.On("Start", mock.AnythingOfType("string")).Return(nil)
+	err := suite.chargePoint.Start("someUrl")
+	require.Nil(t, err)
+	err = suite.chargePoint.SendRawRequest("VendorAction", json.RawMessage(`{"x":`))
+	assert.Error(t, err)
+	err = suite.chargePoint.SendRawResponse("1234", json.RawMessage(`{"x":`))
+	assert.Error(t, err)
+}
+
+func (suite *OcppJTestSuite) TestChargePointSendUnconfirmedRequest() {
+	t := suite.T()
+	mockValue := "someValue"
+	writeC := make(chan []byte, 2)
+	suite.mockClient.On("Start", mock.AnythingOfType("string")).Return(nil)
+	suite.mockClient.On("Write", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		writeC <- args.Get(0).([]byte)
+	})
+	err := suite.chargePoint.Start("someUrl")
+	require.Nil(t, err)
+	// A pending request doesn't hold back the unconfirmed request
+	err = suite.chargePoint.SendRequest(newMockRequest("pending"))
+	require.Nil(t, err)
+	<-writeC
+	err = suite.chargePoint.SendUnconfirmedRequest(newMockRequest(mockValue))
+	require.Nil(t, err)
+	var fields []json.RawMessage
+	require.NoError(t, json.Unmarshal(<-writeC, &fields))
+	require.Len(t, fields, 4)
+	assert.Equal(t, "6", string(fields[0]))
+	assert.Equal(t, fmt.Sprintf(`"%v"`, MockFeatureName), string(fields[2]))
+	assert.Contains(t, string(fields[3]), fmt.Sprintf(`"mockValue":"%v"`, mockValue))
+	// The unconfirmed request isn't pending
+	var uniqueId string
+	require.NoError(t, json.Unmarshal(fields[1], &uniqueId))
+	_, ok := suite.chargePoint.RequestState.GetPendingRequest(uniqueId)
+	assert.False(t, ok)
+}
+
+func (suite *OcppJTestSuite) TestChargePointSendHandler() {
+	t := suite.T()
+	mockUniqueId := "5678"
+	mockValue := "someValue"
+	requestC := make(chan ocpp.Request, 1)
+	suite.chargePoint.SetSendHandler(func(request ocpp.Request, requestId string, action string) {
+		assert.Equal(t, mockUniqueId, requestId)
+		assert.Equal(t, MockFeatureName, action)
+		requestC <- request
+	})
+	suite.mockClient.On("Start", mock.AnythingOfType("string")).Return(nil)
+	err := suite.chargePoint.Start("someUrl")
+	require.Nil(t, err)
+	// Simulate central system message
+	err = suite.mockClient.MessageHandler([]byte(fmt.Sprintf(`[6,"%v","%v",{"mockValue":"%v"}]`, mockUniqueId, MockFeatureName, mockValue)))
+	require.Nil(t, err)
+	request := <-requestC
+	require.IsType(t, &MockRequest{}, request)
+	assert.Equal(t, mockValue, request.(*MockRequest).MockValue)
+	// Invalid unconfirmed requests are never replied to
+	err = suite.mockClient.MessageHandler([]byte(fmt.Sprintf(`[6,"%v","%v",{}]`, mockUniqueId, MockFeatureName)))
+	require.Error(t, err)
+	suite.mockClient.AssertNotCalled(t, "Write", mock.Anything)
+}
@@ -1,6 +1,7 @@
 package ocppj
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -17,9 +18,12 @@ type Client struct {
 	client                ws.Client
 	Id                    string
 	requestHandler        func(request ocpp.Request, requestId string, action string)
+	rawRequestHandler     func(payload json.RawMessage, requestId string, action string)
+	sendHandler           func(request ocpp.Request, requestId string, action string)
 	responseHandler       func(response ocpp.Response, requestId string)
 	errorHandler          func(err *ocpp.Error, details interface{})
 	onDisconnectedHandler func(err error)
+	onDisconnectedReason  func(reason *ws.DisconnectReason)
 	onReconnectedHandler  func()
 	invalidMessageHook    func(err *ocpp.Error, rawMessage string, parsedFields []interface{}) *ocpp.Error
 	dispatcher            ClientDispatcher
@@ -65,6 +69,22 @@ func (c *Client) SetRequestHandler(handler func(request ocpp.Request, requestId
 	c.requestHandler = handler
 }
 
+// SetSendHandler registers a handler for incoming unconfirmed requests, received via SEND messages (OCPP 2.1).
+// The handler must not reply to the request. If no handler is set, incoming SEND messages are discarded.
+func (c *Client) SetSendHandler(handler func(request ocpp.Request, requestId string, action string)) {
+	c.sendHandler = handler
+}
+
+// SetRawRequestHandler enables raw mode, by registering a handler for incoming requests
+// whose action isn't supported by any profile of the client. Instead of replying with a NotSupported error,
+// the client passes the raw JSON payload to the handler, along with the request ID and the action.
+//
+// The handler is expected to reply to the request via SendRawResponse or SendError.
+// Passing a nil handler disables raw mode.
+func (c *Client) SetRawRequestHandler(handler func(payload json.RawMessage, requestId string, action string)) {
+	c.rawRequestHandler = handler
+}
+
 // Return incoming responses handler.
 func (c *Client) GetResponseHandler() func(response ocpp.Response, requestId string) {
 	return c.responseHandler
@@ -103,10 +123,56 @@ func (c *Client) SetInvalidMessageHook(hook func(err *ocpp.Error, rawMessage str
 	c.invalidMessageHook = hook
 }
 
+// SetSecurityProfile sets the OCPP security profile, which the underlying websocket client enforces when connecting.
+// Refer to ws.WithClientSecurityProfile for more information.
+func (c *Client) SetSecurityProfile(profile ws.SecurityProfile) {
+	c.client.SetSecurityProfile(profile)
+}
+
+// SetReconnectStrategy sets the strategy, which the underlying websocket client uses for automatic reconnections.
+// Refer to ws.ReconnectStrategy for more information.
+func (c *Client) SetReconnectStrategy(strategy ws.ReconnectStrategy) {
+	c.client.SetReconnectStrategy(strategy)
+}
+
+// SetConnectionStateHandler sets a callback, which is invoked whenever the state of the underlying connection changes.
+// Refer to ws.ConnectionState for the possible states.
+func (c *Client) SetConnectionStateHandler(handler func(event ws.ConnectionStateEvent)) {
+	c.client.SetConnectionStateHandler(handler)
+}
+
+// ConnectionState returns the most recent state of the underlying connection.
+func (c *Client) ConnectionState() ws.ConnectionStateEvent {
+	return c.client.ConnectionState()
+}
+
+// SetTimeoutConfig sets the timeout configuration of the underlying websocket client.
+// If the client is connected, the ping period, pong wait and write wait are applied to the open connection immediately.
+func (c *Client) SetTimeoutConfig(config ws.ClientTimeoutConfig) {
+	c.client.SetTimeoutConfig(config)
+}
+
+// TimeoutConfig returns the timeout configuration of the underlying websocket client.
+func (c *Client) TimeoutConfig() ws.ClientTimeoutConfig {
+	return c.client.TimeoutConfig()
+}
+
+// SetOnDisconnectedHandler sets a callback, which is invoked when the connection to the server is lost.
+// The passed error is the raw error returned by the websocket layer.
+// To receive the structured reason for the disconnection, use SetOnDisconnectedReasonHandler instead.
 func (c *Client) SetOnDisconnectedHandler(handler func(err error)) {
 	c.onDisconnectedHandler = handler
 }
 
+// SetOnDisconnectedReasonHandler sets a callback, which is invoked when the connection to the server is lost.
+// The passed reason contains the close code, close text, initiator and network error of the lost connection.
+// The callback is invoked after the handler set via SetOnDisconnectedHandler, if any.
+//
+// Failed reconnection attempts don't trigger the callback; their reasons are carried by the connection state events instead.
+func (c *Client) SetOnDisconnectedReasonHandler(handler func(reason *ws.DisconnectReason)) {
+	c.onDisconnectedReason = handler
+}
+
 func (c *Client) SetOnReconnectedHandler(handler func()) {
 	c.onReconnectedHandler = handler
 }
@@ -147,9 +213,47 @@ func (c *Client) StartWithRetries(serverURL string) {
 	// Connect & run
 	fullUrl := fmt.Sprintf("%v/%v", serverURL, c.Id)
 	c.client.StartWithRetries(fullUrl)
+	if c.client.ConnectionState().State == ws.ConnectionStateGaveUp {
+		return
+	}
 	c.dispatcher.Start()
 }
 
+// StartWithFailover connects to one out of several server endpoints, ordered by priority.
+// The client identifier is appended to each endpoint URL, as for Start.
+//
+// The function returns only once a connection was established. Refer to ws.FailoverConfig for the rotation rules.
+// If the reconnect strategy gives up before a connection is established, an error is returned.
+func (c *Client) StartWithFailover(config ws.FailoverConfig) error {
+	// Set internal message handler
+	c.client.SetMessageHandler(c.ocppMessageHandler)
+	c.client.SetDisconnectedHandler(c.onDisconnected)
+	c.client.SetReconnectedHandler(c.onReconnected)
+	// Connect & run
+	endpoints := make([]ws.ServerEndpoint, len(config.Endpoints))
+	for i, endpoint := range config.Endpoints {
+		endpoint.URL = fmt.Sprintf("%v/%v", endpoint.URL, c.Id)
+		endpoints[i] = endpoint
+	}
+	config.Endpoints = endpoints
+	if err := c.client.StartWithFailover(config); err != nil {
+		return err
+	}
+	if c.client.ConnectionState().State == ws.ConnectionStateGaveUp {
+		return fmt.Errorf("couldn't connect to any endpoint: %w", c.client.ConnectionState().LastError)
+	}
+	c.dispatcher.Start()
+	return nil
+}
+
+// ActiveEndpoint returns the server endpoint the client is currently connected to, or attempting to connect to.
+// The returned URL includes the client identifier.
+//
+// If the client wasn't started via StartWithFailover, the function returns false.
+func (c *Client) ActiveEndpoint() (ws.ServerEndpoint, int, bool) {
+	return c.client.ActiveEndpoint()
+}
+
 // Stops the client.
 // The underlying I/O loop is stopped and all pending requests are cleared.
 func (c *Client) Stop() {
@@ -170,6 +274,14 @@ func (c *Client) Stop() {
 	<-cleanupC
 }
 
+// Reconnect closes the current connection and immediately reconnects to the server,
+// e.g. for applying a new client certificate. Refer to ws.Client for more information.
+//
+// Pending requests are kept and sent once the connection is re-established.
+func (c *Client) Reconnect() error {
+	return c.client.Reconnect()
+}
+
 func (c *Client) IsConnected() bool {
 	return c.client.IsConnected()
 }
@@ -195,10 +307,78 @@ func (c *Client) SendRequest(request ocpp.Request) error {
 	if err != nil {
 		return err
 	}
+	return c.sendCall(call)
+}
+
+// Sends a request with an arbitrary JSON payload to the server. The action doesn't need to be supported by the client,
+// so that messages may be forwarded without defining the respective types.
+//
+// Requests are queued and processed like regular requests. The response is passed to the response handler as a *RawResponse,
+// containing the raw JSON payload of the CALLRESULT. Errors are passed to the error handler as usual.
+//
+// Returns an error in the following cases:
+//
+// - the client wasn't started
+//
+// - the payload isn't valid JSON
+//
+// - the output queue is full
+func (c *Client) SendRawRequest(action string, payload json.RawMessage) error {
+	if !c.dispatcher.IsRunning() {
+		return fmt.Errorf("ocppj client is not started, couldn't send request")
+	}
+	call, err := c.createRawCall(action, payload)
+	if err != nil {
+		return err
+	}
+	return c.sendCall(call)
+}
+
+// Sends an unconfirmed OCPP request to the server, via a SEND message (OCPP 2.1).
+// The server never replies to the request, hence it isn't queued and is written immediately,
+// even while a regular request is pending.
+//
+// Returns an error in the following cases:
+//
+// - the client wasn't started
+//
+// - message validation fails (request is malformed)
+//
+// - the endpoint doesn't support the feature
+//
+// - a network error occurred
+func (c *Client) SendUnconfirmedRequest(request ocpp.Request) error {
+	if !c.dispatcher.IsRunning() {
+		return fmt.Errorf("ocppj client is not started, couldn't send request")
+	}
+	send, err := c.CreateSend(request)
+	if err != nil {
+		return err
+	}
+	jsonMessage, err := send.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	if err = c.checkOutgoingMessageSize(jsonMessage); err != nil {
+		return fmt.Errorf("couldn't send request %s: %w", send.Action, err)
+	}
+	if err = c.client.Write(jsonMessage); err != nil {
+		log.Errorf("error sending request [%s, %s]: %v", send.UniqueId, send.Action, err)
+		return err
+	}
+	log.Debugf("sent SEND [%s, %s]", send.UniqueId, send.Action)
+	log.Debugf("sent JSON message to server: %s", string(jsonMessage))
+	return nil
+}
+
+func (c *Client) sendCall(call *Call) error {
 	jsonMessage, err := call.MarshalJSON()
 	if err != nil {
 		return err
 	}
+	if err = c.checkOutgoingMessageSize(jsonMessage); err != nil {
+		return fmt.Errorf("couldn't send request %s: %w", call.Action, err)
+	}
 	// Message will be processed by dispatcher. A dedicated mechanism allows to delegate the message queue handling.
 	if err = c.dispatcher.SendRequest(RequestBundle{Call: call, Data: jsonMessage}); err != nil {
 		log.Errorf("error dispatching request [%s, %s]: %v", call.UniqueId, call.Action, err)
@@ -223,6 +403,27 @@ func (c *Client) SendResponse(requestId string, response ocpp.Response) error {
 	if err != nil {
 		return err
 	}
+	return c.sendCallResult(callResult)
+}
+
+// Sends a response with an arbitrary JSON payload to the server, typically as a reply to a request received in raw mode.
+// The requestID parameter is required and identifies the previously received request.
+//
+// Returns an error in the following cases:
+//
+// - the payload isn't valid JSON
+//
+// - a network error occurred
+func (c *Client) SendRawResponse(requestId string, payload json.RawMessage) error {
+	callResult, err := c.createRawCallResult(payload, requestId)
+	if err != nil {
+		return err
+	}
+	return c.sendCallResult(callResult)
+}
+
+func (c *Client) sendCallResult(callResult *CallResult) error {
+	requestId := callResult.UniqueId
 	jsonMessage, err := callResult.MarshalJSON()
 	if err != nil {
 		return ocpp.NewError(GenericError, err.Error(), requestId)
@@ -269,7 +470,13 @@ func (c *Client) ocppMessageHandler(data []byte) error {
 		return err
 	}
 	log.Debugf("received JSON message from server: %s", string(data))
-	message, err := c.ParseMessage(parsedJson, c.RequestState)
+	if typeId, sizeErr := c.checkIncomingMessageSize(data, parsedJson); sizeErr != nil {
+		return c.handleOversizedMessage(typeId, sizeErr)
+	}
+	message := c.parseRawMessage(data, parsedJson, c.RequestState, c.rawRequestHandler != nil)
+	if message == nil {
+		message, err = c.ParseMessage(parsedJson, c.RequestState)
+	}
 	if err != nil {
 		ocppErr := err.(*ocpp.Error)
 		messageID := ocppErr.MessageId
@@ -298,7 +505,17 @@ func (c *Client) ocppMessageHandler(data []byte) error {
 		case CALL:
 			call := message.(*Call)
 			log.Debugf("handling incoming CALL [%s, %s]", call.UniqueId, call.Action)
-			c.requestHandler(call.Payload, call.UniqueId, call.Action)
+			if rawRequest, ok := call.Payload.(*RawRequest); ok {
+				c.rawRequestHandler(rawRequest.Payload, call.UniqueId, call.Action)
+			} else {
+				c.requestHandler(call.Payload, call.UniqueId, call.Action)
+			}
+		case SEND:
+			send := message.(*Send)
+			log.Debugf("handling incoming SEND [%s, %s]", send.UniqueId, send.Action)
+			if c.sendHandler != nil {
+				c.sendHandler(send.Payload, send.UniqueId, send.Action)
+			}
 		case CALL_RESULT:
 			callResult := message.(*CallResult)
 			log.Debugf("handling incoming CALL RESULT [%s]", callResult.UniqueId)
@@ -318,6 +535,19 @@ func (c *Client) ocppMessageHandler(data []byte) error {
 	return nil
 }
 
+func (c *Client) handleOversizedMessage(typeId MessageType, sizeErr *ocpp.Error) error {
+	sendError := func(sizeErr *ocpp.Error) error {
+		return c.SendError(sizeErr.MessageId, sizeErr.Code, sizeErr.Description, nil)
+	}
+	failRequest := func(sizeErr *ocpp.Error) {
+		c.dispatcher.CompleteRequest(sizeErr.MessageId)
+		if c.errorHandler != nil {
+			c.errorHandler(sizeErr, nil)
+		}
+	}
+	return c.Endpoint.handleOversizedMessage(typeId, sizeErr, c.RequestState, sendError, failRequest)
+}
+
 // HandleFailedResponseError allows to handle failures while sending responses (either CALL_RESULT or CALL_ERROR).
 // It internally analyzes and creates an ocpp.Error based on the given error.
 // It will the attempt to send it to the server.
@@ -352,6 +582,10 @@ func (c *Client) onDisconnected(err error) {
 	if c.onDisconnectedHandler != nil {
 		c.onDisconnectedHandler(err)
 	}
+	if c.onDisconnectedReason != nil {
+		// The websocket client updates its state before notifying the disconnection
+		c.onDisconnectedReason(c.client.ConnectionState().DisconnectReason)
+	}
 }
 
 func (c *Client) onReconnected() {
@@ -357,6 +357,12 @@ type ServerDispatcher interface {
 	DeleteClient(clientID string)
 }
 
+// Optionally implemented by a ServerDispatcher or a ServerQueueMap, for reporting whether
+// any requests are still waiting to be sent. The check is used while gracefully shutting down a server.
+type queuedRequestsChecker interface {
+	HasQueuedRequests() bool
+}
+
 // DefaultServerDispatcher is a default implementation of the ServerDispatcher interface.
 //
 // The dispatcher implements the ClientState as well for simplicity.
@@ -442,6 +448,16 @@ func (d *DefaultServerDispatcher) DeleteClient(clientID string) {
 	}
 }
 
+// HasQueuedRequests returns true if at least one client has requests waiting to be sent, false otherwise.
+//
+// If the queue map doesn't implement a HasQueuedRequests() bool method, false is always returned.
+func (d *DefaultServerDispatcher) HasQueuedRequests() bool {
+	if checker, ok := d.queueMap.(queuedRequestsChecker); ok {
+		return checker.HasQueuedRequests()
+	}
+	return false
+}
+
 func (d *DefaultServerDispatcher) SetNetworkServer(server ws.Server) {
 	d.network = server
 }
@@ -68,6 +68,7 @@ const (
 	CALL        MessageType = 2
 	CALL_RESULT MessageType = 3
 	CALL_ERROR  MessageType = 4
+	SEND        MessageType = 6 // Unconfirmed request, introduced in OCPP 2.1
 )
 
 // An OCPP-J message.
@@ -123,6 +124,35 @@ func (call *Call) MarshalJSON() ([]byte, error) {
 	return jsonMarshal(fields)
 }
 
+// -------------------- Send --------------------
+
+// An OCPP-J Send message, containing an OCPP Request that is never replied to.
+// The message type was introduced in OCPP 2.1.
+type Send struct {
+	Message       `validate:"-"`
+	MessageTypeId MessageType  `json:"messageTypeId" validate:"required,eq=6"`
+	UniqueId      string       `json:"uniqueId" validate:"required,max=36"`
+	Action        string       `json:"action" validate:"required,max=36"`
+	Payload       ocpp.Request `json:"payload" validate:"required"`
+}
+
+func (send *Send) GetMessageTypeId() MessageType {
+	return send.MessageTypeId
+}
+
+func (send *Send) GetUniqueId() string {
+	return send.UniqueId
+}
+
+func (send *Send) MarshalJSON() ([]byte, error) {
+	fields := make([]interface{}, 4)
+	fields[0] = int(send.MessageTypeId)
+	fields[1] = send.UniqueId
+	fields[2] = send.Action
+	fields[3] = send.Payload
+	return jsonMarshal(fields)
+}
+
 // -------------------- Call Result --------------------
 
 // An OCPP-J CallResult message, containing an OCPP Response.
@@ -331,8 +361,9 @@ func jsonMarshal(t interface{}) ([]byte, error) {
 // An OCPP-J endpoint is one of the two entities taking part in the communication.
 // The endpoint keeps state for supported OCPP profiles and current pending requests.
 type Endpoint struct {
-	dialect  ocpp.Dialect
-	Profiles []*ocpp.Profile
+	dialect        ocpp.Dialect
+	maxMessageSize int
+	Profiles       []*ocpp.Profile
 }
 
 // Sets endpoint dialect.
@@ -345,6 +376,22 @@ func (endpoint *Endpoint) Dialect() ocpp.Dialect {
 	return endpoint.dialect
 }
 
+// Sets the maximum size in bytes of a single OCPP-J message, exchanged with the other endpoint.
+//
+// Incoming CALL messages exceeding the limit are rejected with a FormatViolation CALLERROR.
+// Incoming responses exceeding the limit are discarded, and the respective pending request is failed.
+// Outgoing CALL messages exceeding the limit are not sent and an error is returned to the caller.
+//
+// If set to 0 (default), no limit is enforced.
+func (endpoint *Endpoint) SetMaxMessageSize(size int) {
+	endpoint.maxMessageSize = size
+}
+
+// Gets the maximum size in bytes of a single OCPP-J message. A value of 0 means that no limit is enforced.
+func (endpoint *Endpoint) GetMaxMessageSize() int {
+	return endpoint.maxMessageSize
+}
+
 // Adds support for a new profile on the endpoint.
 func (endpoint *Endpoint) AddProfile(profile *ocpp.Profile) {
 	endpoint.Profiles = append(endpoint.Profiles, profile)
@@ -455,6 +502,34 @@ func (endpoint *Endpoint) ParseMessage(arr []interface{}, pendingRequestState Cl
 			return nil, errorFromValidation(endpoint, err.(validator.ValidationErrors), uniqueId, action)
 		}
 		return &call, nil
+	} else if typeId == SEND {
+		// Send messages are never replied to, not even with an error, hence no message ID is set on errors
+		if len(arr) != 4 {
+			return nil, ocpp.NewError(FormatErrorType(endpoint), "Invalid Send message. Expected array length 4", "")
+		}
+		action, ok := arr[2].(string)
+		if !ok {
+			return nil, ocpp.NewError(FormatErrorType(endpoint), fmt.Sprintf("Invalid element %v at 2, expected action (string)", arr[2]), "")
+		}
+		profile, ok := endpoint.GetProfileForFeature(action)
+		if !ok {
+			return nil, ocpp.NewError(NotSupported, fmt.Sprintf("Unsupported feature %v", action), "")
+		}
+		request, err := profile.ParseRequest(action, arr[3], parseRawJsonRequest)
+		if err != nil {
+			return nil, ocpp.NewError(FormatErrorType(endpoint), err.Error(), "")
+		}
+		send := Send{
+			MessageTypeId: SEND,
+			UniqueId:      uniqueId,
+			Action:        action,
+			Payload:       request,
+		}
+		err = Validate.Struct(send)
+		if err != nil {
+			return nil, errorFromValidation(endpoint, err.(validator.ValidationErrors), "", action)
+		}
+		return &send, nil
 	} else if typeId == CALL_RESULT {
 		request, ok := pendingRequestState.GetPendingRequest(uniqueId)
 		if !ok {
@@ -515,6 +590,56 @@ func (endpoint *Endpoint) ParseMessage(arr []interface{}, pendingRequestState Cl
 	}
 }
 
+// Verifies that an outgoing message doesn't exceed the maximum message size configured on the endpoint.
+func (endpoint *Endpoint) checkOutgoingMessageSize(data []byte) error {
+	if endpoint.maxMessageSize > 0 && len(data) > endpoint.maxMessageSize {
+		return fmt.Errorf("message size of %d bytes exceeds maximum message size of %d bytes", len(data), endpoint.maxMessageSize)
+	}
+	return nil
+}
+
+// Verifies that an incoming message doesn't exceed the maximum message size configured on the endpoint.
+// The parsed fields are used for extracting the message type and unique ID of the message, if available.
+//
+// If the message is too large, an error is returned, along with the message type of the oversized message.
+func (endpoint *Endpoint) checkIncomingMessageSize(data []byte, parsedFields []interface{}) (MessageType, *ocpp.Error) {
+	if endpoint.maxMessageSize <= 0 || len(data) <= endpoint.maxMessageSize {
+		return 0, nil
+	}
+	var typeId MessageType
+	var uniqueId string
+	if len(parsedFields) > 1 {
+		if rawTypeId, ok := parsedFields[0].(float64); ok {
+			typeId = MessageType(rawTypeId)
+		}
+		uniqueId, _ = parsedFields[1].(string)
+	}
+	description := fmt.Sprintf("message size of %d bytes exceeds maximum message size of %d bytes", len(data), endpoint.maxMessageSize)
+	return typeId, ocpp.NewError(FormatErrorType(endpoint), description, uniqueId)
+}
+
+// Handles an incoming message, which exceeds the maximum message size.
+//
+// An oversized request is replied to with a CALLERROR, sent via the passed sendError function.
+// An oversized response cannot be processed, hence the respective pending request is failed via the passed failRequest function.
+// The size error is always returned.
+func (endpoint *Endpoint) handleOversizedMessage(typeId MessageType, sizeErr *ocpp.Error, pending ClientState, sendError func(sizeErr *ocpp.Error) error, failRequest func(sizeErr *ocpp.Error)) error {
+	switch typeId {
+	case CALL:
+		if sizeErr.MessageId != "" {
+			if err := sendError(sizeErr); err != nil {
+				return err
+			}
+		}
+	case CALL_RESULT, CALL_ERROR:
+		if _, ok := pending.GetPendingRequest(sizeErr.MessageId); ok {
+			failRequest(sizeErr)
+		}
+	}
+	log.Error(sizeErr)
+	return sizeErr
+}
+
 // Creates a Call message, given an OCPP request. A unique ID for the message is automatically generated.
 // Returns an error in case the request's feature is not supported on this endpoint.
 //
@@ -542,6 +667,31 @@ func (endpoint *Endpoint) CreateCall(request ocpp.Request) (*Call, error) {
 	return &call, nil
 }
 
+// Creates a Send message, given an OCPP request. A unique ID for the message is automatically generated.
+// Returns an error in case the request's feature is not supported on this endpoint.
+//
+// The created message is not automatically sent.
+func (endpoint *Endpoint) CreateSend(request ocpp.Request) (*Send, error) {
+	action := request.GetFeatureName()
+	profile, _ := endpoint.GetProfileForFeature(action)
+	if profile == nil {
+		return nil, fmt.Errorf("Couldn't create Send for unsupported action %v", action)
+	}
+	send := Send{
+		MessageTypeId: SEND,
+		UniqueId:      messageIdGenerator(),
+		Action:        action,
+		Payload:       request,
+	}
+	if validationEnabled {
+		err := Validate.Struct(send)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &send, nil
+}
+
 // Creates a CallResult message, given an OCPP response and the message's unique ID.
 //
 // Returns an error in case the response's feature is not supported on this endpoint.
@@ -1,9 +1,11 @@
 package ocppj_test
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"net"
+	"net/http"
 	"reflect"
 	"testing"
 
@@ -45,6 +47,43 @@ func (websocket MockWebSocket) IsConnected() bool {
 	return true
 }
 
+func (websocket MockWebSocket) CompressionStats() ws.CompressionStats {
+	return ws.CompressionStats{}
+}
+
+func (websocket MockWebSocket) SetCompressionEnabled(enabled bool) {
+}
+
+func (websocket MockWebSocket) SecurityProfile() ws.SecurityProfile {
+	return ws.SecurityProfileNone
+}
+
+func (websocket MockWebSocket) Stats() ws.ConnectionStats {
+	return ws.ConnectionStats{}
+}
+
+func (websocket MockWebSocket) TimeoutConfig() ws.ChannelTimeoutConfig {
+	return ws.ChannelTimeoutConfig{}
+}
+
+func (websocket MockWebSocket) SetTimeoutConfig(config ws.ChannelTimeoutConfig) {
+}
+
+func (websocket MockWebSocket) DisconnectReason() *ws.DisconnectReason {
+	return nil
+}
+
+func (websocket MockWebSocket) SetAttribute(key string, value interface{}) {
+}
+
+func (websocket MockWebSocket) Attribute(key string) (interface{}, bool) {
+	return nil, false
+}
+
+func (websocket MockWebSocket) Attributes() map[string]interface{} {
+	return map[string]interface{}{}
+}
+
 func NewMockWebSocket(id string) MockWebSocket {
 	return MockWebSocket{id: id}
 }
@@ -58,6 +97,7 @@ type MockWebsocketServer struct {
 	NewClientHandler          func(ws ws.Channel)
 	CheckClientHandler        ws.CheckClientHandler
 	DisconnectedClientHandler func(ws ws.Channel)
+	ReplacedClientHandler     func(ws ws.Channel)
 	errC                      chan error
 }
 
@@ -65,10 +105,24 @@ func (websocketServer *MockWebsocketServer) Start(port int, listenPath string) {
 	websocketServer.MethodCalled("Start", port, listenPath)
 }
 
+func (websocketServer *MockWebsocketServer) Serve(listener net.Listener, listenPath string) {
+	websocketServer.MethodCalled("Serve", listener, listenPath)
+}
+
+func (websocketServer *MockWebsocketServer) Handler() http.Handler {
+	args := websocketServer.MethodCalled("Handler")
+	return args.Get(0).(http.Handler)
+}
+
 func (websocketServer *MockWebsocketServer) Stop() {
 	websocketServer.MethodCalled("Stop")
 }
 
+func (websocketServer *MockWebsocketServer) Shutdown(ctx context.Context) error {
+	args := websocketServer.MethodCalled("Shutdown", ctx)
+	return args.Error(0)
+}
+
 func (websocketServer *MockWebsocketServer) Write(webSocketId string, data []byte) error {
 	args := websocketServer.MethodCalled("Write", webSocketId, data)
 	return args.Error(0)
@@ -86,6 +140,10 @@ func (websocketServer *MockWebsocketServer) SetDisconnectedClientHandler(handler
 	websocketServer.DisconnectedClientHandler = handler
 }
 
+func (websocketServer *MockWebsocketServer) SetReplacedClientHandler(handler func(ws ws.Channel)) {
+	websocketServer.ReplacedClientHandler = handler
+}
+
 func (websocketServer *MockWebsocketServer) AddSupportedSubprotocol(subProto string) {
 }
 
@@ -126,6 +184,11 @@ func (websocketClient *MockWebsocketClient) Start(url string) error {
 	return args.Error(0)
 }
 
+func (websocketClient *MockWebsocketClient) StartWithFailover(config ws.FailoverConfig) error {
+	args := websocketClient.MethodCalled("StartWithFailover", config)
+	return args.Error(0)
+}
+
 func (websocketClient *MockWebsocketClient) Stop() {
 	websocketClient.MethodCalled("Stop")
 }
@@ -177,6 +240,11 @@ func (websocketClient *MockWebsocketClient) IsConnected() bool {
 	return args.Bool(0)
 }
 
+func (websocketClient *MockWebsocketClient) ConnectionState() ws.ConnectionStateEvent {
+	args := websocketClient.MethodCalled("ConnectionState")
+	return args.Get(0).(ws.ConnectionStateEvent)
+}
+
 // ---------------------- MOCK FEATURE ----------------------
 const (
 	MockFeatureName = "Mock"
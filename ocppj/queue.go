@@ -174,6 +174,18 @@ func (f *FIFOQueueMap) Add(clientID string, queue RequestQueue) {
 	f.data[clientID] = queue
 }
 
+// HasQueuedRequests returns true if at least one queue in the map contains elements, false otherwise.
+func (f *FIFOQueueMap) HasQueuedRequests() bool {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	for _, q := range f.data {
+		if !q.IsEmpty() {
+			return true
+		}
+	}
+	return false
+}
+
 // NewFIFOQueueMap creates a new FIFOQueueMap, which will automatically create queues with the specified capacity.
 //
 // Passing capacity = 0 will generate queues without a maximum capacity.
@@ -171,3 +171,18 @@ func (suite *ServerQueueMapTestSuite) TestRemove() {
 	assert.False(t, ok)
 	assert.Nil(t, q)
 }
+
+func (suite *ServerQueueMapTestSuite) TestHasQueuedRequests() {
+	t := suite.T()
+	queueMap, ok := suite.queueMap.(*ocppj.FIFOQueueMap)
+	require.True(t, ok)
+	assert.False(t, queueMap.HasQueuedRequests())
+	q := suite.queueMap.GetOrCreate("first")
+	_ = suite.queueMap.GetOrCreate("second")
+	assert.False(t, queueMap.HasQueuedRequests())
+	err := q.Push(ocppj.RequestBundle{})
+	require.NoError(t, err)
+	assert.True(t, queueMap.HasQueuedRequests())
+	suite.queueMap.Remove("first")
+	assert.False(t, queueMap.HasQueuedRequests())
+}
@@ -0,0 +1,156 @@
+package ocppj
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// RawRequest is a request with an arbitrary JSON payload, for actions which aren't supported by any profile of the endpoint.
+//
+// Raw requests are created when receiving a CALL for an unknown action in raw mode, or when sending a CALL via SendRawRequest.
+type RawRequest struct {
+	Action  string
+	Payload json.RawMessage
+}
+
+func (r *RawRequest) GetFeatureName() string {
+	return r.Action
+}
+
+// MarshalJSON returns the raw payload as is.
+func (r *RawRequest) MarshalJSON() ([]byte, error) {
+	return rawPayload(r.Payload), nil
+}
+
+// RawResponse is a response with an arbitrary JSON payload, for actions which aren't supported by any profile of the endpoint.
+//
+// Raw responses are created when receiving a CALLRESULT to a request sent via SendRawRequest,
+// or when replying to a raw request via SendRawResponse.
+// The Action is empty for outgoing raw responses.
+type RawResponse struct {
+	Action  string
+	Payload json.RawMessage
+}
+
+func (r *RawResponse) GetFeatureName() string {
+	return r.Action
+}
+
+// MarshalJSON returns the raw payload as is.
+func (r *RawResponse) MarshalJSON() ([]byte, error) {
+	return rawPayload(r.Payload), nil
+}
+
+func rawPayload(payload json.RawMessage) []byte {
+	if len(payload) == 0 {
+		return []byte("{}")
+	}
+	return payload
+}
+
+func validateRawPayload(payload json.RawMessage) error {
+	if len(payload) > 0 && !json.Valid(payload) {
+		return fmt.Errorf("invalid raw payload, expected JSON")
+	}
+	return nil
+}
+
+// Creates a Call message with a raw payload. A unique ID for the message is automatically generated.
+// The action doesn't need to be supported by the endpoint.
+func (endpoint *Endpoint) createRawCall(action string, payload json.RawMessage) (*Call, error) {
+	if err := validateRawPayload(payload); err != nil {
+		return nil, err
+	}
+	call := Call{
+		MessageTypeId: CALL,
+		UniqueId:      messageIdGenerator(),
+		Action:        action,
+		Payload:       &RawRequest{Action: action, Payload: payload},
+	}
+	if validationEnabled {
+		err := Validate.Struct(call)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &call, nil
+}
+
+// Creates a CallResult message with a raw payload, given the message's unique ID.
+func (endpoint *Endpoint) createRawCallResult(payload json.RawMessage, uniqueId string) (*CallResult, error) {
+	if err := validateRawPayload(payload); err != nil {
+		return nil, err
+	}
+	callResult := CallResult{
+		MessageTypeId: CALL_RESULT,
+		UniqueId:      uniqueId,
+		Payload:       &RawResponse{Payload: payload},
+	}
+	if validationEnabled {
+		err := Validate.Struct(callResult)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &callResult, nil
+}
+
+// Parses messages that are processed in raw mode, i.e. CALLs for actions unsupported by the endpoint (only if rawCalls is set),
+// and CALLRESULTs to previously sent raw requests. The payload is extracted from the original message as is.
+//
+// Returns nil if the message must be parsed regularly via ParseMessage.
+func (endpoint *Endpoint) parseRawMessage(data []byte, parsedFields []interface{}, pendingRequestState ClientState, rawCalls bool) Message {
+	if len(parsedFields) < 3 {
+		return nil
+	}
+	rawTypeId, ok := parsedFields[0].(float64)
+	if !ok {
+		return nil
+	}
+	uniqueId, ok := parsedFields[1].(string)
+	if !ok || uniqueId == "" {
+		return nil
+	}
+	switch MessageType(rawTypeId) {
+	case CALL:
+		if !rawCalls || len(parsedFields) != 4 {
+			return nil
+		}
+		action, ok := parsedFields[2].(string)
+		if !ok || action == "" {
+			return nil
+		}
+		if _, supported := endpoint.GetProfileForFeature(action); supported {
+			return nil
+		}
+		var fields []json.RawMessage
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return nil
+		}
+		return &Call{
+			MessageTypeId: CALL,
+			UniqueId:      uniqueId,
+			Action:        action,
+			Payload:       &RawRequest{Action: action, Payload: fields[3]},
+		}
+	case CALL_RESULT:
+		request, ok := pendingRequestState.GetPendingRequest(uniqueId)
+		if !ok {
+			return nil
+		}
+		rawRequest, ok := request.(*RawRequest)
+		if !ok {
+			return nil
+		}
+		var fields []json.RawMessage
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return nil
+		}
+		return &CallResult{
+			MessageTypeId: CALL_RESULT,
+			UniqueId:      uniqueId,
+			Payload:       &RawResponse{Action: rawRequest.Action, Payload: fields[2]},
+		}
+	}
+	return nil
+}
@@ -1,9 +1,16 @@
 package ocppj
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
 
+	"github.com/gorilla/websocket"
 	"gopkg.in/go-playground/validator.v9"
 
 	"github.com/lorenzodonini/ocpp-go/ocpp"
@@ -18,16 +25,25 @@ type Server struct {
 	checkClientHandler        ws.CheckClientHandler
 	newClientHandler          ClientHandler
 	disconnectedClientHandler ClientHandler
+	replacedClientHandler     ClientHandler
 	requestHandler            RequestHandler
+	rawRequestHandler         RawRequestHandler
+	sendHandler               RequestHandler
 	responseHandler           ResponseHandler
 	errorHandler              ErrorHandler
 	invalidMessageHook        InvalidMessageHook
 	dispatcher                ServerDispatcher
 	RequestState              ServerState
+	shuttingDown              bool
+	shutdownMutex             sync.RWMutex
 }
 
+// Interval at which pending requests are checked, while the server is being shut down.
+const shutdownPollInterval = 50 * time.Millisecond
+
 type ClientHandler func(client ws.Channel)
 type RequestHandler func(client ws.Channel, request ocpp.Request, requestId string, action string)
+type RawRequestHandler func(client ws.Channel, payload json.RawMessage, requestId string, action string)
 type ResponseHandler func(client ws.Channel, response ocpp.Response, requestId string)
 type ErrorHandler func(client ws.Channel, err *ocpp.Error, details interface{})
 type InvalidMessageHook func(client ws.Channel, err *ocpp.Error, rawJson string, parsedFields []interface{}) *ocpp.Error
@@ -72,6 +88,22 @@ func (s *Server) SetRequestHandler(handler RequestHandler) {
 	s.requestHandler = handler
 }
 
+// SetSendHandler registers a handler for incoming unconfirmed requests, received via SEND messages (OCPP 2.1).
+// The handler must not reply to the request. If no handler is set, incoming SEND messages are discarded.
+func (s *Server) SetSendHandler(handler RequestHandler) {
+	s.sendHandler = handler
+}
+
+// SetRawRequestHandler enables raw mode, by registering a handler for incoming requests
+// whose action isn't supported by any profile of the server. Instead of replying with a NotSupported error,
+// the server passes the raw JSON payload to the handler, along with the request ID and the action.
+//
+// The handler is expected to reply to the request via SendRawResponse or SendError.
+// Passing a nil handler disables raw mode.
+func (s *Server) SetRawRequestHandler(handler RawRequestHandler) {
+	s.rawRequestHandler = handler
+}
+
 // Registers a handler for incoming responses.
 func (s *Server) SetResponseHandler(handler ResponseHandler) {
 	s.responseHandler = handler
@@ -120,6 +152,45 @@ func (s *Server) SetDisconnectedClientHandler(handler ClientHandler) {
 	s.disconnectedClientHandler = handler
 }
 
+// Registers a handler for clients, whose connection was replaced by a new connection with the same ID.
+// The handler is invoked for the old channel, right before the disconnected client handler.
+//
+// Duplicate connections are only replaced, if the websocket server was configured accordingly,
+// see ws.WithDuplicateConnectionPolicy.
+func (s *Server) SetClientReplacedHandler(handler ClientHandler) {
+	s.replacedClientHandler = handler
+}
+
+// SetSecurityProfile sets the OCPP security profile, which the underlying websocket server enforces on incoming connections.
+// Refer to ws.WithServerSecurityProfile for more information.
+func (s *Server) SetSecurityProfile(profile ws.SecurityProfile) {
+	s.server.SetSecurityProfile(profile)
+}
+
+// GetClient returns the channel of a connected client, identified by its ID.
+// If the client isn't connected, the function returns false.
+func (s *Server) GetClient(clientID string) (ws.Channel, bool) {
+	return s.server.GetChannel(clientID)
+}
+
+// Clients returns the channels of all connected clients, sorted by their ID.
+func (s *Server) Clients() []ws.Channel {
+	return s.server.Channels()
+}
+
+// ClientCount returns the number of connected clients.
+func (s *Server) ClientCount() int {
+	return s.server.ChannelCount()
+}
+
+// DisconnectClient closes the connection to a client, sending a close frame with the passed close code and reason.
+// Pending requests to the client are canceled, once the disconnection was processed.
+//
+// An error is returned, if the client isn't connected.
+func (s *Server) DisconnectClient(clientID string, code int, reason string) error {
+	return s.server.StopConnection(clientID, websocket.CloseError{Code: code, Text: reason})
+}
+
 // Starts the underlying Websocket server on a specified listenPort and listenPath.
 //
 // The function runs indefinitely, until the server is stopped.
@@ -127,24 +198,123 @@ func (s *Server) SetDisconnectedClientHandler(handler ClientHandler) {
 //
 // An error may be returned, if the websocket server couldn't be started.
 func (s *Server) Start(listenPort int, listenPath string) {
+	s.prepare()
+	// Serve & run
+	s.server.Start(listenPort, listenPath)
+	// TODO: return error?
+}
+
+// Serve behaves like Start, but accepts incoming connections on the passed listener.
+//
+// The function runs indefinitely, until the server is stopped.
+// Invoke this function in a separate goroutine, to perform other operations on the main thread.
+func (s *Server) Serve(listener net.Listener, listenPath string) {
+	s.prepare()
+	// Serve & run
+	s.server.Serve(listener, listenPath)
+}
+
+// Handler prepares the server for accepting incoming connections and returns an http.Handler,
+// which may be mounted on an external HTTP server.
+// Accepted connections are handled exactly like connections accepted via Start.
+//
+// The function doesn't block. To stop handling connections, call Stop or Shutdown.
+func (s *Server) Handler() http.Handler {
+	s.prepare()
+	return s.server.Handler()
+}
+
+// prepare sets the internal handlers on the websocket server and starts the dispatcher.
+func (s *Server) prepare() {
+	s.shutdownMutex.Lock()
+	s.shuttingDown = false
+	s.shutdownMutex.Unlock()
 	// Set internal message handler
 	s.server.SetCheckClientHandler(s.checkClientHandler)
 	s.server.SetNewClientHandler(s.onClientConnected)
 	s.server.SetDisconnectedClientHandler(s.onClientDisconnected)
+	s.server.SetReplacedClientHandler(s.onClientReplaced)
 	s.server.SetMessageHandler(s.ocppMessageHandler)
-	s.dispatcher.Start()
-	// Serve & run
-	s.server.Start(listenPort, listenPath)
-	// TODO: return error?
+	if !s.dispatcher.IsRunning() {
+		s.dispatcher.Start()
+	}
 }
 
 // Stops the server.
 // This clears all pending requests and causes the Start function to return.
 func (s *Server) Stop() {
-	s.dispatcher.Stop()
+	if s.dispatcher.IsRunning() {
+		s.dispatcher.Stop()
+	}
 	s.server.Stop()
 }
 
+// Shutdown gracefully shuts down the server.
+//
+// The server immediately stops accepting new connections, and new requests can't be sent anymore.
+// The function then waits until all pending requests have completed (or timed out), or until the passed context is done.
+// Afterwards, the remaining pending requests are cleared and all open connections are closed with a "going away" close frame.
+// Once the function returns, the previously invoked Start function returns as well.
+//
+// If the context is done before all pending requests have completed, the context error is returned.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.shutdownMutex.Lock()
+	s.shuttingDown = true
+	s.shutdownMutex.Unlock()
+	log.Info("shutting down ocppj server")
+	// Open connections are kept alive until draining is over
+	drainCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	wsErrC := make(chan error, 1)
+	go func() {
+		wsErrC <- s.server.Shutdown(drainCtx)
+	}()
+	err := s.waitForPendingRequests(ctx)
+	// Stop processing requests and close all connections
+	if s.dispatcher.IsRunning() {
+		s.dispatcher.Stop()
+	}
+	s.RequestState.ClearAllPendingRequests()
+	cancel()
+	if wsErr := <-wsErrC; wsErr != nil {
+		return wsErr
+	}
+	return err
+}
+
+// IsShuttingDown returns true if the server is being (or was) gracefully shut down, false otherwise.
+func (s *Server) IsShuttingDown() bool {
+	s.shutdownMutex.RLock()
+	defer s.shutdownMutex.RUnlock()
+	return s.shuttingDown
+}
+
+func (s *Server) hasOutstandingRequests() bool {
+	if s.RequestState.HasPendingRequests() {
+		return true
+	}
+	// Requests may also be waiting in a queue, before being dispatched.
+	// Custom dispatchers, which cannot report their queues, are only checked for pending requests.
+	if checker, ok := s.dispatcher.(queuedRequestsChecker); ok {
+		return checker.HasQueuedRequests()
+	}
+	return false
+}
+
+func (s *Server) waitForPendingRequests(ctx context.Context) error {
+	ticker := time.NewTicker(shutdownPollInterval)
+	defer ticker.Stop()
+	for s.hasOutstandingRequests() {
+		select {
+		case <-ctx.Done():
+			log.Infof("shutdown interrupted, pending requests will be canceled: %v", ctx.Err())
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
 // Sends an OCPP Request to a client, identified by the clientID parameter.
 //
 // Returns an error in the following cases:
@@ -160,14 +330,88 @@ func (s *Server) SendRequest(clientID string, request ocpp.Request) error {
 	if !s.dispatcher.IsRunning() {
 		return fmt.Errorf("ocppj server is not started, couldn't send request")
 	}
+	if s.IsShuttingDown() {
+		return fmt.Errorf("ocppj server is shutting down, couldn't send request")
+	}
 	call, err := s.CreateCall(request)
 	if err != nil {
 		return err
 	}
+	return s.sendCall(clientID, call)
+}
+
+// Sends a request with an arbitrary JSON payload to a client, identified by the clientID parameter.
+// The action doesn't need to be supported by the server, so that messages may be forwarded without defining the respective types.
+//
+// Requests are queued and processed like regular requests. The response is passed to the response handler as a *RawResponse,
+// containing the raw JSON payload of the CALLRESULT. Errors are passed to the error handler as usual.
+//
+// Returns an error in the following cases:
+//
+// - the server wasn't started
+//
+// - the payload isn't valid JSON
+//
+// - the output queue is full
+func (s *Server) SendRawRequest(clientID string, action string, payload json.RawMessage) error {
+	if !s.dispatcher.IsRunning() {
+		return fmt.Errorf("ocppj server is not started, couldn't send request")
+	}
+	if s.IsShuttingDown() {
+		return fmt.Errorf("ocppj server is shutting down, couldn't send request")
+	}
+	call, err := s.createRawCall(action, payload)
+	if err != nil {
+		return err
+	}
+	return s.sendCall(clientID, call)
+}
+
+// Sends an unconfirmed OCPP request to a client, via a SEND message (OCPP 2.1).
+// The client never replies to the request, hence it isn't queued and is written immediately,
+// even while a regular request is pending.
+//
+// Returns an error in the following cases:
+//
+// - the server wasn't started
+//
+// - message validation fails (request is malformed)
+//
+// - the endpoint doesn't support the feature
+//
+// - a network error occurred
+func (s *Server) SendUnconfirmedRequest(clientID string, request ocpp.Request) error {
+	if !s.dispatcher.IsRunning() {
+		return fmt.Errorf("ocppj server is not started, couldn't send request")
+	}
+	send, err := s.CreateSend(request)
+	if err != nil {
+		return err
+	}
+	jsonMessage, err := send.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	if err = s.checkOutgoingMessageSize(jsonMessage); err != nil {
+		return fmt.Errorf("couldn't send request %s to %s: %w", send.Action, clientID, err)
+	}
+	if err = s.server.Write(clientID, jsonMessage); err != nil {
+		log.Errorf("error sending request [%s, %s] to %s: %v", send.UniqueId, send.Action, clientID, err)
+		return err
+	}
+	log.Debugf("sent SEND [%s, %s] to %s", send.UniqueId, send.Action, clientID)
+	log.Debugf("sent JSON message to %s: %s", clientID, string(jsonMessage))
+	return nil
+}
+
+func (s *Server) sendCall(clientID string, call *Call) error {
 	jsonMessage, err := call.MarshalJSON()
 	if err != nil {
 		return err
 	}
+	if err = s.checkOutgoingMessageSize(jsonMessage); err != nil {
+		return fmt.Errorf("couldn't send request %s to %s: %w", call.Action, clientID, err)
+	}
 	// Will not send right away. Queuing message and let it be processed by dedicated requestPump routine
 	if err = s.dispatcher.SendRequest(clientID, RequestBundle{call, jsonMessage}); err != nil {
 		log.Errorf("error dispatching request [%s, %s] to %s: %v", call.UniqueId, call.Action, clientID, err)
@@ -192,6 +436,27 @@ func (s *Server) SendResponse(clientID string, requestId string, response ocpp.R
 	if err != nil {
 		return err
 	}
+	return s.sendCallResult(clientID, callResult)
+}
+
+// Sends a response with an arbitrary JSON payload to a client, typically as a reply to a request received in raw mode.
+// The requestID parameter is required and identifies the previously received request.
+//
+// Returns an error in the following cases:
+//
+// - the payload isn't valid JSON
+//
+// - a network error occurred
+func (s *Server) SendRawResponse(clientID string, requestId string, payload json.RawMessage) error {
+	callResult, err := s.createRawCallResult(payload, requestId)
+	if err != nil {
+		return err
+	}
+	return s.sendCallResult(clientID, callResult)
+}
+
+func (s *Server) sendCallResult(clientID string, callResult *CallResult) error {
+	requestId := callResult.UniqueId
 	jsonMessage, err := callResult.MarshalJSON()
 	if err != nil {
 		return ocpp.NewError(GenericError, err.Error(), requestId)
@@ -240,7 +505,13 @@ func (s *Server) ocppMessageHandler(wsChannel ws.Channel, data []byte) error {
 	log.Debugf("received JSON message from %s: %s", wsChannel.ID(), string(data))
 	// Get pending requests for client
 	pending := s.RequestState.GetClientState(wsChannel.ID())
-	message, err := s.ParseMessage(parsedJson, pending)
+	if typeId, sizeErr := s.checkIncomingMessageSize(data, parsedJson); sizeErr != nil {
+		return s.handleOversizedMessage(wsChannel, typeId, sizeErr, pending)
+	}
+	message := s.parseRawMessage(data, parsedJson, pending, s.rawRequestHandler != nil)
+	if message == nil {
+		message, err = s.ParseMessage(parsedJson, pending)
+	}
 	if err != nil {
 		ocppErr := err.(*ocpp.Error)
 		messageID := ocppErr.MessageId
@@ -269,9 +540,17 @@ func (s *Server) ocppMessageHandler(wsChannel ws.Channel, data []byte) error {
 		case CALL:
 			call := message.(*Call)
 			log.Debugf("handling incoming CALL [%s, %s] from %s", call.UniqueId, call.Action, wsChannel.ID())
-			if s.requestHandler != nil {
+			if rawRequest, ok := call.Payload.(*RawRequest); ok {
+				s.rawRequestHandler(wsChannel, rawRequest.Payload, call.UniqueId, call.Action)
+			} else if s.requestHandler != nil {
 				s.requestHandler(wsChannel, call.Payload, call.UniqueId, call.Action)
 			}
+		case SEND:
+			send := message.(*Send)
+			log.Debugf("handling incoming SEND [%s, %s] from %s", send.UniqueId, send.Action, wsChannel.ID())
+			if s.sendHandler != nil {
+				s.sendHandler(wsChannel, send.Payload, send.UniqueId, send.Action)
+			}
 		case CALL_RESULT:
 			callResult := message.(*CallResult)
 			log.Debugf("handling incoming CALL RESULT [%s] from %s", callResult.UniqueId, wsChannel.ID())
@@ -291,6 +570,19 @@ func (s *Server) ocppMessageHandler(wsChannel ws.Channel, data []byte) error {
 	return nil
 }
 
+func (s *Server) handleOversizedMessage(wsChannel ws.Channel, typeId MessageType, sizeErr *ocpp.Error, pending ClientState) error {
+	sendError := func(sizeErr *ocpp.Error) error {
+		return s.SendError(wsChannel.ID(), sizeErr.MessageId, sizeErr.Code, sizeErr.Description, nil)
+	}
+	failRequest := func(sizeErr *ocpp.Error) {
+		s.dispatcher.CompleteRequest(wsChannel.ID(), sizeErr.MessageId)
+		if s.errorHandler != nil {
+			s.errorHandler(wsChannel, sizeErr, nil)
+		}
+	}
+	return s.Endpoint.handleOversizedMessage(typeId, sizeErr, pending, sendError, failRequest)
+}
+
 // HandleFailedResponseError allows to handle failures while sending responses (either CALL_RESULT or CALL_ERROR).
 // It internally analyzes and creates an ocpp.Error based on the given error.
 // It will the attempt to send it to the client.
@@ -328,6 +620,13 @@ func (s *Server) onClientConnected(ws ws.Channel) {
 	}
 }
 
+func (s *Server) onClientReplaced(ws ws.Channel) {
+	// State for the client is cleared once the disconnection is processed
+	if s.replacedClientHandler != nil {
+		s.replacedClientHandler(ws)
+	}
+}
+
 func (s *Server) onClientDisconnected(ws ws.Channel) {
 	// Clear state for disconnected client
 	s.dispatcher.DeleteClient(ws.ID())
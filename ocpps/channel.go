@@ -0,0 +1,183 @@
+package ocpps
+
+import (
+	"crypto/tls"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lorenzodonini/ocpp-go/ws"
+)
+
+// channel represents an OCPP-S charge point, known to a Server.
+//
+// Since OCPP-S is connectionless, a channel is created when the charge point sends its first request
+// (or when its address is registered manually), and lives until it is closed explicitly via StopConnection
+// or until the server is stopped.
+type channel struct {
+	id               string
+	address          string
+	remoteAddr       net.Addr
+	tlsState         *tls.ConnectionState
+	securityProfile  ws.SecurityProfile
+	connectedAt      time.Time
+	disconnectReason *ws.DisconnectReason
+	attributes       map[string]interface{}
+	mutex            sync.RWMutex
+	// Statistics, accessed atomically
+	messagesIn    uint64
+	messagesOut   uint64
+	bytesIn       uint64
+	bytesOut      uint64
+	lastMessageIn int64
+}
+
+func newChannel(id string) *channel {
+	return &channel{
+		id:          id,
+		connectedAt: time.Now(),
+		attributes:  map[string]interface{}{},
+	}
+}
+
+func (c *channel) ID() string {
+	return c.id
+}
+
+func (c *channel) RemoteAddr() net.Addr {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.remoteAddr
+}
+
+func (c *channel) TLSConnectionState() *tls.ConnectionState {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.tlsState
+}
+
+func (c *channel) IsConnected() bool {
+	return c.DisconnectReason() == nil
+}
+
+// CompressionStats only returns the payload sizes, since OCPP-S messages aren't compressed.
+func (c *channel) CompressionStats() ws.CompressionStats {
+	return ws.CompressionStats{
+		PayloadBytesIn:  atomic.LoadUint64(&c.bytesIn),
+		PayloadBytesOut: atomic.LoadUint64(&c.bytesOut),
+	}
+}
+
+func (c *channel) SetCompressionEnabled(_ bool) {
+}
+
+// SecurityProfile returns the security profile, which was enforced on the last request of the charge point.
+func (c *channel) SecurityProfile() ws.SecurityProfile {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.securityProfile
+}
+
+// TimeoutConfig always returns an empty configuration, since OCPP-S channels don't keep a connection open.
+func (c *channel) TimeoutConfig() ws.ChannelTimeoutConfig {
+	return ws.ChannelTimeoutConfig{}
+}
+
+func (c *channel) SetTimeoutConfig(_ ws.ChannelTimeoutConfig) {
+}
+
+func (c *channel) DisconnectReason() *ws.DisconnectReason {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.disconnectReason
+}
+
+func (c *channel) Stats() ws.ConnectionStats {
+	var lastMessageIn time.Time
+	if t := atomic.LoadInt64(&c.lastMessageIn); t != 0 {
+		lastMessageIn = time.Unix(0, t)
+	}
+	return ws.ConnectionStats{
+		ConnectedAt:   c.connectedAt,
+		MessagesIn:    atomic.LoadUint64(&c.messagesIn),
+		MessagesOut:   atomic.LoadUint64(&c.messagesOut),
+		LastMessageIn: lastMessageIn,
+	}
+}
+
+func (c *channel) SetAttribute(key string, value interface{}) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if value == nil {
+		delete(c.attributes, key)
+		return
+	}
+	c.attributes[key] = value
+}
+
+func (c *channel) Attribute(key string) (interface{}, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	value, ok := c.attributes[key]
+	return value, ok
+}
+
+func (c *channel) Attributes() map[string]interface{} {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	attributes := make(map[string]interface{}, len(c.attributes))
+	for k, v := range c.attributes {
+		attributes[k] = v
+	}
+	return attributes
+}
+
+func (c *channel) getAddress() string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.address
+}
+
+func (c *channel) setAddress(address string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.address = address
+}
+
+// update refreshes the network information of the channel, after receiving an authenticated request from the charge point.
+//
+// The address of the charge point may only be changed, if the identity of the sender was verified.
+// Otherwise, the address is only set if it wasn't known yet.
+func (c *channel) update(remoteAddr net.Addr, tlsState *tls.ConnectionState, profile ws.SecurityProfile, address string, identityVerified bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.remoteAddr = remoteAddr
+	c.tlsState = tlsState
+	c.securityProfile = profile
+	if address == "" || address == AnonymousAddress || address == c.address {
+		return
+	}
+	if c.address != "" && !identityVerified {
+		log.Errorf("ignoring new address %s of OCPP-S charge point %s, since its identity wasn't verified", address, c.id)
+		return
+	}
+	c.address = address
+}
+
+func (c *channel) close(reason *ws.DisconnectReason) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.disconnectReason = reason
+}
+
+func (c *channel) recordMessageIn(size int) {
+	atomic.AddUint64(&c.messagesIn, 1)
+	atomic.AddUint64(&c.bytesIn, uint64(size))
+	atomic.StoreInt64(&c.lastMessageIn, time.Now().UnixNano())
+}
+
+func (c *channel) recordMessageOut(size int) {
+	atomic.AddUint64(&c.messagesOut, 1)
+	atomic.AddUint64(&c.bytesOut, uint64(size))
+}
@@ -0,0 +1,364 @@
+package ocpps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/lorenzodonini/ocpp-go/ocppj"
+	"github.com/lorenzodonini/ocpp-go/ws"
+)
+
+// Client is the charge point side of OCPP-S. It implements the ws.Client interface,
+// so that it can be passed to any component expecting a websocket client, e.g. to a charge point:
+//
+//	client := ocpps.NewClient()
+//	client.SetEndpointAddress("http://10.0.0.5:8080/ocpp")
+//	client.SetListenAddress(":8080")
+//	chargePoint := ocpp16.NewSOAPChargePoint("CP-1", client)
+//	err := chargePoint.Start("http://centralsystem.example.com/ocpp")
+//
+// Requests of the charge point are posted to the URL of the central system, passed to Start.
+// The charge point ID is sent via the chargeBoxIdentity header.
+//
+// Requests of the central system are received on the endpoint address of the client.
+// The endpoint is either served by the client itself (see SetListenAddress), or by an external HTTP server via Handler.
+// If no endpoint address is set, the central system cannot send any requests to the charge point.
+//
+// Since OCPP-S is connectionless, the client is considered connected as long as it is started.
+// Reconnection and failover functions have no effect.
+type Client struct {
+	endpoint
+	id                     string
+	serverURL              string
+	listenAddress          string
+	httpServer             *http.Server
+	messageHandler         func(data []byte) error
+	connectionStateHandler func(event ws.ConnectionStateEvent)
+	disconnectedHandler    func(err error)
+	username               string
+	password               string
+	headers                http.Header
+	timeoutConfig          ws.ClientTimeoutConfig
+	securityProfile        ws.SecurityProfile
+	connected              bool
+	errC                   chan error
+	stateMutex             sync.RWMutex
+}
+
+// NewClient creates a new OCPP-S client.
+//
+// The profiles of all supported messages must be added via AddProfile, before starting the client.
+func NewClient() *Client {
+	return &Client{
+		endpoint:      newEndpoint(ChargePointNamespace, CentralSystemNamespace),
+		headers:       http.Header{},
+		timeoutConfig: ws.NewClientTimeoutConfig(),
+	}
+}
+
+// SetListenAddress sets the local network address (e.g. ":8080"), on which the client receives requests from the central system.
+// If set, an HTTP server is started together with the client. Otherwise, the Handler must be mounted on an external HTTP server.
+//
+// The address advertised to the central system must be set separately via SetEndpointAddress.
+func (c *Client) SetListenAddress(address string) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+	c.listenAddress = address
+}
+
+// Handler returns an http.Handler, which processes requests sent by the central system.
+func (c *Client) Handler() http.Handler {
+	return http.HandlerFunc(c.handleHTTP)
+}
+
+// Start prepares the client for exchanging messages with the central system at the passed URL.
+//
+// The last path element of the URL is interpreted as the charge point ID, as appended by the ocppj layer,
+// and is sent via the chargeBoxIdentity header instead. Websocket schemes are replaced by the respective HTTP schemes.
+func (c *Client) Start(urlStr string) error {
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		return err
+	}
+	switch u.Scheme {
+	case "ws":
+		u.Scheme = "http"
+	case "wss":
+		u.Scheme = "https"
+	case "http", "https":
+	default:
+		return fmt.Errorf("unsupported URL scheme %v for OCPP-S client", u.Scheme)
+	}
+	if (c.securityProfile == ws.SecurityProfile2 || c.securityProfile == ws.SecurityProfile3) && u.Scheme != "https" {
+		return fmt.Errorf("%w: %v requires TLS", ws.ErrSecurityProfileViolation, c.securityProfile)
+	}
+	id := path.Base(u.Path)
+	if id == "/" || id == "." {
+		return fmt.Errorf("missing charge point ID in URL %v", urlStr)
+	}
+	u.Path = strings.TrimSuffix(strings.TrimSuffix(u.Path, id), "/")
+	u.RawPath = ""
+	c.stateMutex.Lock()
+	if c.connected {
+		c.stateMutex.Unlock()
+		return fmt.Errorf("client is already started")
+	}
+	c.id = id
+	c.serverURL = u.String()
+	if c.listenAddress != "" {
+		ln, err := net.Listen("tcp", c.listenAddress)
+		if err != nil {
+			c.stateMutex.Unlock()
+			return err
+		}
+		c.httpServer = &http.Server{Handler: c.Handler()}
+		go func(httpServer *http.Server) {
+			if err := httpServer.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+				c.error(fmt.Errorf("failed to serve OCPP-S endpoint: %w", err))
+			}
+		}(c.httpServer)
+	}
+	c.connected = true
+	handler := c.connectionStateHandler
+	c.stateMutex.Unlock()
+	log.Infof("started OCPP-S client %s for %v", id, c.serverURL)
+	if handler != nil {
+		handler(ws.ConnectionStateEvent{State: ws.ConnectionStateConnected})
+	}
+	return nil
+}
+
+func (c *Client) StartWithRetries(urlStr string) {
+	if err := c.Start(urlStr); err != nil {
+		c.error(err)
+	}
+}
+
+// StartWithFailover isn't supported by OCPP-S clients.
+func (c *Client) StartWithFailover(_ ws.FailoverConfig) error {
+	return fmt.Errorf("failover is not supported by OCPP-S clients")
+}
+
+func (c *Client) ActiveEndpoint() (ws.ServerEndpoint, int, bool) {
+	return ws.ServerEndpoint{}, 0, false
+}
+
+func (c *Client) Stop() {
+	c.stateMutex.Lock()
+	if !c.connected {
+		c.stateMutex.Unlock()
+		return
+	}
+	c.connected = false
+	httpServer := c.httpServer
+	c.httpServer = nil
+	handler := c.connectionStateHandler
+	disconnectedHandler := c.disconnectedHandler
+	c.stateMutex.Unlock()
+	if httpServer != nil {
+		if err := httpServer.Shutdown(context.Background()); err != nil {
+			c.error(fmt.Errorf("shutdown failed: %w", err))
+		}
+	}
+	log.Infof("stopped OCPP-S client %s", c.id)
+	if disconnectedHandler != nil {
+		disconnectedHandler(nil)
+	}
+	if handler != nil {
+		handler(ws.ConnectionStateEvent{State: ws.ConnectionStateDisconnected})
+	}
+}
+
+func (c *Client) Errors() <-chan error {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+	if c.errC == nil {
+		c.errC = make(chan error, 1)
+	}
+	return c.errC
+}
+
+func (c *Client) SetMessageHandler(handler func(data []byte) error) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+	c.messageHandler = handler
+}
+
+// SetTimeoutConfig sets the timeout configuration of the client.
+// The read limit applies to the SOAP envelopes received from the central system.
+// If no read limit is set, envelopes are limited to 1 MiB.
+func (c *Client) SetTimeoutConfig(config ws.ClientTimeoutConfig) {
+	c.setReadLimit(config.ReadLimit)
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+	c.timeoutConfig = config
+}
+
+func (c *Client) TimeoutConfig() ws.ClientTimeoutConfig {
+	c.stateMutex.RLock()
+	defer c.stateMutex.RUnlock()
+	return c.timeoutConfig
+}
+
+// SetDisconnectedHandler sets a handler, which is invoked when the client is stopped.
+// OCPP-S clients never lose their connection otherwise.
+func (c *Client) SetDisconnectedHandler(handler func(err error)) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+	c.disconnectedHandler = handler
+}
+
+// SetReconnectedHandler has no effect, since OCPP-S clients never lose their connection.
+func (c *Client) SetReconnectedHandler(_ func()) {
+}
+
+func (c *Client) IsConnected() bool {
+	c.stateMutex.RLock()
+	defer c.stateMutex.RUnlock()
+	return c.connected
+}
+
+func (c *Client) Write(data []byte) error {
+	if isReply, err := c.reply(data); isReply {
+		return err
+	}
+	c.stateMutex.RLock()
+	connected := c.connected
+	serverURL := c.serverURL
+	id := c.id
+	c.stateMutex.RUnlock()
+	if !connected {
+		return fmt.Errorf("client is not started, couldn't send message")
+	}
+	// Requests are sent asynchronously, the response is passed to the message handler
+	go func() {
+		response := c.sendRequest(serverURL, id, data, c.prepareRequest)
+		_ = c.dispatch(response)
+	}()
+	return nil
+}
+
+// AddOption has no effect on OCPP-S clients. Refer to SetHTTPClient for customizing the HTTP client.
+func (c *Client) AddOption(_ interface{}) {
+}
+
+// SetRequestedSubProtocol has no effect, since OCPP-S doesn't use subprotocols.
+func (c *Client) SetRequestedSubProtocol(_ string) {
+}
+
+func (c *Client) SetBasicAuth(username string, password string) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+	c.username = username
+	c.password = password
+}
+
+func (c *Client) SetHeaderValue(key string, value string) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+	c.headers.Set(key, value)
+}
+
+// SetSecurityProfile sets the security profile of the client.
+// Profiles 2 and 3 require the central system URL to use TLS. The TLS configuration must be set via SetHTTPClient.
+func (c *Client) SetSecurityProfile(profile ws.SecurityProfile) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+	c.securityProfile = profile
+}
+
+// Reconnect has no effect, since OCPP-S clients never lose their connection.
+func (c *Client) Reconnect() error {
+	return nil
+}
+
+// SetReconnectStrategy has no effect, since OCPP-S clients never lose their connection.
+func (c *Client) SetReconnectStrategy(_ ws.ReconnectStrategy) {
+}
+
+func (c *Client) SetConnectionStateHandler(handler func(event ws.ConnectionStateEvent)) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+	c.connectionStateHandler = handler
+}
+
+func (c *Client) ConnectionState() ws.ConnectionStateEvent {
+	if c.IsConnected() {
+		return ws.ConnectionStateEvent{State: ws.ConnectionStateConnected}
+	}
+	return ws.ConnectionStateEvent{State: ws.ConnectionStateDisconnected}
+}
+
+func (c *Client) prepareRequest(r *http.Request) {
+	c.stateMutex.RLock()
+	defer c.stateMutex.RUnlock()
+	for key, values := range c.headers {
+		for _, value := range values {
+			r.Header.Add(key, value)
+		}
+	}
+	if c.username != "" {
+		r.SetBasicAuth(c.username, c.password)
+	}
+}
+
+func (c *Client) handleHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	limit := c.getReadLimit()
+	// One extra byte allows readEnvelope to detect and report oversized envelopes
+	r.Body = http.MaxBytesReader(w, r.Body, limit+1)
+	env, err := readEnvelope(r.Body, limit)
+	if err != nil {
+		c.writeFault(w, header{}, ocppj.FormatViolationV16, err.Error())
+		return
+	}
+	h := env.header()
+	c.stateMutex.RLock()
+	id := c.id
+	connected := c.connected
+	c.stateMutex.RUnlock()
+	if !connected {
+		c.writeFault(w, h, ocppj.GenericError, "charge point is not started")
+		return
+	}
+	if h.ChargeBoxIdentity != "" && h.ChargeBoxIdentity != id {
+		c.writeFault(w, h, ocppj.SecurityError, fmt.Sprintf("identity mismatch: expected %v, got %v", id, h.ChargeBoxIdentity))
+		return
+	}
+	c.handleRequest(w, r, env, c.dispatch)
+}
+
+// dispatch passes an OCPP-J message, translated from an OCPP-S message, to the message handler.
+func (c *Client) dispatch(frame []byte) error {
+	c.stateMutex.RLock()
+	handler := c.messageHandler
+	c.stateMutex.RUnlock()
+	if handler == nil {
+		return fmt.Errorf("no message handler set")
+	}
+	err := handler(frame)
+	if err != nil {
+		c.error(err)
+	}
+	return err
+}
+
+func (c *Client) error(err error) {
+	log.Error(err)
+	c.stateMutex.RLock()
+	errC := c.errC
+	c.stateMutex.RUnlock()
+	if errC != nil {
+		errC <- err
+	}
+}
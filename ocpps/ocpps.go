@@ -0,0 +1,553 @@
+// Contains an implementation of the OCPP 1.6 SOAP transport (OCPP-S), i.e. SOAP 1.2 over HTTP with WS-Addressing headers.
+//
+// The package doesn't process OCPP messages by itself. Instead, the Server and Client types implement the
+// ws.Server and ws.Client interfaces respectively, and translate every OCPP-S exchange into the equivalent
+// OCPP-J message (and vice versa). This allows using the ocppj package and the OCPP 1.6 facades on top of OCPP-S,
+// with the same handlers serving both SOAP and JSON charge points:
+//
+//	server := ocpps.NewServer(nil)
+//	centralSystem := ocpp16.NewSOAPCentralSystem(server)
+//	centralSystem.SetCoreHandler(coreHandler)
+//	centralSystem.Start(8887, "/{ws}")
+//
+// Payloads are converted via their JSON representation, since the element names of the OCPP-S schemas
+// are equal to the field names of the OCPP-J schemas. The profiles passed via AddProfile
+// are required for converting incoming XML payloads into the respective OCPP message types.
+package ocpps
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lorenzodonini/ocpp-go/logging"
+	"github.com/lorenzodonini/ocpp-go/ocpp"
+	"github.com/lorenzodonini/ocpp-go/ocppj"
+)
+
+const (
+	SoapEnvelopeNamespace  = "http://www.w3.org/2003/05/soap-envelope"
+	AddressingNamespace    = "http://www.w3.org/2005/08/addressing"
+	AnonymousAddress       = "http://www.w3.org/2005/08/addressing/anonymous"
+	FaultAction            = "http://www.w3.org/2005/08/addressing/soap/fault"
+	CentralSystemNamespace = "urn://Ocpp/Cs/2015/10/"
+	ChargePointNamespace   = "urn://Ocpp/Cp/2015/10/"
+	ContentType            = "application/soap+xml; charset=utf-8"
+)
+
+// The default timeout for waiting on the response to an incoming request, or to an outgoing HTTP request.
+const defaultRequestTimeout = 30 * time.Second
+
+// The maximum size in bytes of a SOAP envelope, if no read limit is configured.
+const defaultReadLimit = 1 << 20
+
+// The internal verbose logger
+var log logging.Logger
+
+func init() {
+	log = &logging.VoidLogger{}
+}
+
+// Sets a custom Logger implementation, allowing the ocpp-s package to log events.
+// By default, a VoidLogger is used, so no logs will be sent to any output.
+//
+// The function panics, if a nil logger is passed.
+func SetLogger(logger logging.Logger) {
+	if logger == nil {
+		panic("cannot set a nil logger")
+	}
+	log = logger
+}
+
+// header contains the SOAP headers of an OCPP-S message.
+type header struct {
+	ChargeBoxIdentity string
+	MessageID         string
+	RelatesTo         string
+	Action            string
+	To                string
+	From              string
+	ReplyTo           string
+}
+
+type addressingEndpoint struct {
+	Address string `xml:"http://www.w3.org/2005/08/addressing Address"`
+}
+
+type soapFault struct {
+	Code struct {
+		Value   string `xml:"http://www.w3.org/2003/05/soap-envelope Value"`
+		Subcode struct {
+			Value string `xml:"http://www.w3.org/2003/05/soap-envelope Value"`
+		} `xml:"http://www.w3.org/2003/05/soap-envelope Subcode"`
+	} `xml:"http://www.w3.org/2003/05/soap-envelope Code"`
+	Reason struct {
+		Text string `xml:"http://www.w3.org/2003/05/soap-envelope Text"`
+	} `xml:"http://www.w3.org/2003/05/soap-envelope Reason"`
+}
+
+// errorCode returns the OCPP error code contained in the subcode of the fault.
+func (f *soapFault) errorCode() ocpp.ErrorCode {
+	value := f.Code.Subcode.Value
+	if i := strings.LastIndex(value, ":"); i >= 0 {
+		value = value[i+1:]
+	}
+	if value == "" {
+		return ocppj.GenericError
+	}
+	return ocpp.ErrorCode(value)
+}
+
+type envelope struct {
+	XMLName xml.Name `xml:"http://www.w3.org/2003/05/soap-envelope Envelope"`
+	Header  struct {
+		ChargeBoxIdentity string             `xml:"chargeBoxIdentity"`
+		MessageID         string             `xml:"http://www.w3.org/2005/08/addressing MessageID"`
+		RelatesTo         string             `xml:"http://www.w3.org/2005/08/addressing RelatesTo"`
+		Action            string             `xml:"http://www.w3.org/2005/08/addressing Action"`
+		To                string             `xml:"http://www.w3.org/2005/08/addressing To"`
+		From              addressingEndpoint `xml:"http://www.w3.org/2005/08/addressing From"`
+		ReplyTo           addressingEndpoint `xml:"http://www.w3.org/2005/08/addressing ReplyTo"`
+	} `xml:"http://www.w3.org/2003/05/soap-envelope Header"`
+	Body struct {
+		Fault   *soapFault `xml:"http://www.w3.org/2003/05/soap-envelope Fault"`
+		Content []byte     `xml:",innerxml"`
+	} `xml:"http://www.w3.org/2003/05/soap-envelope Body"`
+}
+
+func (e *envelope) header() header {
+	return header{
+		ChargeBoxIdentity: strings.TrimSpace(e.Header.ChargeBoxIdentity),
+		MessageID:         strings.TrimSpace(e.Header.MessageID),
+		RelatesTo:         strings.TrimSpace(e.Header.RelatesTo),
+		Action:            strings.TrimSpace(e.Header.Action),
+		To:                strings.TrimSpace(e.Header.To),
+		From:              strings.TrimSpace(e.Header.From.Address),
+		ReplyTo:           strings.TrimSpace(e.Header.ReplyTo.Address),
+	}
+}
+
+// readEnvelope decodes a SOAP envelope, which may not exceed limit bytes.
+func readEnvelope(r io.Reader, limit int64) (*envelope, error) {
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read SOAP envelope: %w", err)
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("SOAP envelope exceeds read limit of %d bytes", limit)
+	}
+	var env envelope
+	if err = xml.NewDecoder(bytes.NewReader(data)).Decode(&env); err != nil {
+		return nil, fmt.Errorf("invalid SOAP envelope: %w", err)
+	}
+	return &env, nil
+}
+
+// marshalEnvelope creates a SOAP envelope. The chargeBoxIdentity header is qualified with the passed namespace.
+func marshalEnvelope(namespace string, h header, body []byte) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString(fmt.Sprintf(`<soap:Envelope xmlns:soap="%s" xmlns:wsa="%s">`, SoapEnvelopeNamespace, AddressingNamespace))
+	buf.WriteString("<soap:Header>")
+	if h.ChargeBoxIdentity != "" {
+		buf.WriteString(fmt.Sprintf(`<chargeBoxIdentity xmlns="%s" soap:mustUnderstand="true">`, namespace))
+		_ = xml.EscapeText(&buf, []byte(h.ChargeBoxIdentity))
+		buf.WriteString("</chargeBoxIdentity>")
+	}
+	writeHeaderElement(&buf, "MessageID", h.MessageID)
+	writeHeaderElement(&buf, "RelatesTo", h.RelatesTo)
+	if h.From != "" {
+		writeHeaderElement(&buf, "From", "<wsa:Address>"+escape(h.From)+"</wsa:Address>")
+	}
+	if h.ReplyTo != "" {
+		writeHeaderElement(&buf, "ReplyTo", "<wsa:Address>"+escape(h.ReplyTo)+"</wsa:Address>")
+	}
+	writeHeaderElement(&buf, "To", escape(h.To))
+	writeHeaderElement(&buf, "Action", escape(h.Action))
+	buf.WriteString("</soap:Header><soap:Body>")
+	buf.Write(body)
+	buf.WriteString("</soap:Body></soap:Envelope>")
+	return buf.Bytes()
+}
+
+func writeHeaderElement(buf *bytes.Buffer, name string, content string) {
+	if content == "" {
+		return
+	}
+	buf.WriteString(fmt.Sprintf("<wsa:%s>%s</wsa:%s>", name, content, name))
+}
+
+func escape(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+// isSenderFault returns true, if an error was caused by the sender of a message (e.g. an invalid payload).
+func isSenderFault(code ocpp.ErrorCode) bool {
+	switch code {
+	case ocppj.FormatViolationV16, ocppj.FormatViolationV2, ocppj.ProtocolError, ocppj.SecurityError,
+		ocppj.PropertyConstraintViolation, ocppj.OccurrenceConstraintViolationV16, ocppj.OccurrenceConstraintViolationV2,
+		ocppj.TypeConstraintViolation, ocppj.NotImplemented, ocppj.MessageTypeNotSupported:
+		return true
+	default:
+		return false
+	}
+}
+
+// marshalFault creates the body of a SOAP fault, carrying an OCPP error code as subcode.
+// Errors caused by the sender of a message are reported with a Sender code, all other errors with a Receiver code.
+func marshalFault(namespace string, code ocpp.ErrorCode, description string) []byte {
+	faultCode := "soap:Receiver"
+	if isSenderFault(code) {
+		faultCode = "soap:Sender"
+	}
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf(`<soap:Fault><soap:Code><soap:Value>%s</soap:Value>`, faultCode))
+	buf.WriteString(fmt.Sprintf(`<soap:Subcode><soap:Value xmlns:ocpp="%s">ocpp:%s</soap:Value></soap:Subcode></soap:Code>`, namespace, escape(string(code))))
+	buf.WriteString(fmt.Sprintf(`<soap:Reason><soap:Text xml:lang="en">%s</soap:Text></soap:Reason></soap:Fault>`, escape(description)))
+	return buf.Bytes()
+}
+
+// bodyElementName returns the name of the body element for an action, e.g. bootNotificationRequest.
+func bodyElementName(action string, suffix string) string {
+	if action == "" {
+		return suffix
+	}
+	return strings.ToLower(action[:1]) + action[1:] + suffix
+}
+
+// newMessageID creates a random WS-Addressing message ID.
+func newMessageID() string {
+	return "urn:uuid:" + newUUID()
+}
+
+func newUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	// Version 4, variant RFC 4122
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// parseFrame parses an OCPP-J message into its fields, keeping the payload as raw JSON.
+func parseFrame(data []byte) (ocppj.MessageType, string, []json.RawMessage, error) {
+	var fields []json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return 0, "", nil, fmt.Errorf("invalid OCPP-J message: %w", err)
+	}
+	if len(fields) < 3 {
+		return 0, "", nil, fmt.Errorf("invalid OCPP-J message: expected at least 3 fields, got %d", len(fields))
+	}
+	var typeId ocppj.MessageType
+	var uniqueId string
+	if err := json.Unmarshal(fields[0], &typeId); err != nil {
+		return 0, "", nil, fmt.Errorf("invalid OCPP-J message type: %w", err)
+	}
+	if err := json.Unmarshal(fields[1], &uniqueId); err != nil {
+		return 0, "", nil, fmt.Errorf("invalid OCPP-J message ID: %w", err)
+	}
+	return typeId, uniqueId, fields, nil
+}
+
+func callFrame(uniqueId string, action string, payload json.RawMessage) []byte {
+	frame, _ := json.Marshal([]interface{}{ocppj.CALL, uniqueId, action, payload})
+	return frame
+}
+
+func callResultFrame(uniqueId string, payload json.RawMessage) []byte {
+	frame, _ := json.Marshal([]interface{}{ocppj.CALL_RESULT, uniqueId, payload})
+	return frame
+}
+
+func callErrorFrame(uniqueId string, code ocpp.ErrorCode, description string) []byte {
+	frame, _ := json.Marshal([]interface{}{ocppj.CALL_ERROR, uniqueId, code, description, struct{}{}})
+	return frame
+}
+
+// exchange is an incoming request, waiting for the OCPP-J response of the local endpoint.
+type exchange struct {
+	header header
+	action string
+	replyC chan []byte
+}
+
+// endpoint contains the logic shared by servers and clients, for translating between OCPP-S and OCPP-J messages.
+type endpoint struct {
+	profiles       []*ocpp.Profile
+	namespace      string // The namespace of the local service
+	peerNamespace  string // The namespace of the remote service
+	address        string // The address of the local service, advertised to the peer
+	httpClient     *http.Client
+	requestTimeout time.Duration
+	readLimit      int64
+	pending        map[string]*exchange
+	mutex          sync.RWMutex
+}
+
+func newEndpoint(namespace string, peerNamespace string) endpoint {
+	return endpoint{
+		namespace:      namespace,
+		peerNamespace:  peerNamespace,
+		httpClient:     &http.Client{Timeout: defaultRequestTimeout},
+		requestTimeout: defaultRequestTimeout,
+		pending:        map[string]*exchange{},
+	}
+}
+
+// AddProfile registers profiles, whose messages may be exchanged via the endpoint.
+func (e *endpoint) AddProfile(profiles ...*ocpp.Profile) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.profiles = append(e.profiles, profiles...)
+}
+
+// SetEndpointAddress sets the address of the local service, which is advertised to the peer
+// via the WS-Addressing From header. The peer sends its own requests to this address.
+func (e *endpoint) SetEndpointAddress(address string) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.address = address
+}
+
+// SetHTTPClient sets the HTTP client used for sending requests to the peer, e.g. to configure TLS.
+func (e *endpoint) SetHTTPClient(client *http.Client) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.httpClient = client
+}
+
+// SetRequestTimeout sets the timeout for waiting on the local response to an incoming request.
+// If the timeout elapses, a fault is returned to the peer.
+func (e *endpoint) SetRequestTimeout(timeout time.Duration) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.requestTimeout = timeout
+}
+
+// setReadLimit sets the maximum size in bytes of the SOAP envelopes received from the peer.
+// If zero or negative, the default limit of 1 MiB applies, since SOAP messages are received via plain HTTP.
+func (e *endpoint) setReadLimit(limit int64) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.readLimit = limit
+}
+
+func (e *endpoint) getReadLimit() int64 {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	if e.readLimit <= 0 {
+		return defaultReadLimit
+	}
+	return e.readLimit
+}
+
+func (e *endpoint) feature(action string) ocpp.Feature {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	for _, profile := range e.profiles {
+		if profile.SupportsFeature(action) {
+			return profile.GetFeature(action)
+		}
+	}
+	return nil
+}
+
+// handleRequest translates an incoming OCPP-S request into an OCPP-J message and passes it to the dispatch function.
+// The function then waits for the OCPP-J response, which is written back to the peer.
+func (e *endpoint) handleRequest(w http.ResponseWriter, r *http.Request, env *envelope, dispatch func(frame []byte) error) {
+	h := env.header()
+	action := strings.TrimPrefix(h.Action, "/")
+	feature := e.feature(action)
+	if feature == nil {
+		e.writeFault(w, h, ocppj.NotImplemented, fmt.Sprintf("unsupported action %v", h.Action))
+		return
+	}
+	if env.Body.Fault != nil {
+		e.writeFault(w, h, ocppj.ProtocolError, "unexpected fault in request")
+		return
+	}
+	node, err := parseXMLNode(env.Body.Content)
+	if err != nil {
+		e.writeFault(w, h, ocppj.FormatViolationV16, err.Error())
+		return
+	}
+	payload, err := xmlToJSON(node, feature.GetRequestType())
+	if err != nil {
+		e.writeFault(w, h, ocppj.FormatViolationV16, err.Error())
+		return
+	}
+	uniqueId := newUUID()
+	ex := &exchange{header: h, action: action, replyC: make(chan []byte, 1)}
+	e.mutex.Lock()
+	e.pending[uniqueId] = ex
+	timeout := e.requestTimeout
+	e.mutex.Unlock()
+	defer func() {
+		e.mutex.Lock()
+		delete(e.pending, uniqueId)
+		e.mutex.Unlock()
+	}()
+	if err = dispatch(callFrame(uniqueId, action, payload)); err != nil {
+		log.Errorf("error dispatching %v request %v: %v", action, uniqueId, err)
+	}
+	select {
+	case frame := <-ex.replyC:
+		e.writeReply(w, ex, frame)
+	case <-time.After(timeout):
+		e.writeFault(w, h, ocppj.GenericError, "timeout while waiting for response")
+	case <-r.Context().Done():
+		log.Debugf("request %v canceled by peer", uniqueId)
+	}
+}
+
+// reply passes an OCPP-J response or error to the incoming request it belongs to.
+// Returns false if the message isn't a response, or if no matching request is pending.
+func (e *endpoint) reply(data []byte) (bool, error) {
+	typeId, uniqueId, _, err := parseFrame(data)
+	if err != nil {
+		return false, err
+	}
+	if typeId != ocppj.CALL_RESULT && typeId != ocppj.CALL_ERROR {
+		return false, nil
+	}
+	e.mutex.RLock()
+	ex, ok := e.pending[uniqueId]
+	e.mutex.RUnlock()
+	if !ok {
+		return true, fmt.Errorf("no pending request with ID %v, the response may have timed out", uniqueId)
+	}
+	select {
+	case ex.replyC <- data:
+	default:
+		return true, fmt.Errorf("duplicate response for request %v", uniqueId)
+	}
+	return true, nil
+}
+
+func (e *endpoint) writeReply(w http.ResponseWriter, ex *exchange, frame []byte) {
+	typeId, _, fields, err := parseFrame(frame)
+	if err != nil {
+		e.writeFault(w, ex.header, ocppj.InternalError, err.Error())
+		return
+	}
+	if typeId == ocppj.CALL_ERROR {
+		var code ocpp.ErrorCode
+		var description string
+		_ = json.Unmarshal(fields[2], &code)
+		if len(fields) > 3 {
+			_ = json.Unmarshal(fields[3], &description)
+		}
+		e.writeFault(w, ex.header, code, description)
+		return
+	}
+	var body bytes.Buffer
+	if err = writeXMLPayload(&body, bodyElementName(ex.action, "Response"), e.namespace, fields[2]); err != nil {
+		e.writeFault(w, ex.header, ocppj.InternalError, err.Error())
+		return
+	}
+	h := header{
+		MessageID: newMessageID(),
+		RelatesTo: ex.header.MessageID,
+		Action:    fmt.Sprintf("/%sResponse", ex.action),
+		To:        AnonymousAddress,
+	}
+	e.writeEnvelope(w, http.StatusOK, h, body.Bytes())
+}
+
+func (e *endpoint) writeFault(w http.ResponseWriter, request header, code ocpp.ErrorCode, description string) {
+	h := header{
+		MessageID: newMessageID(),
+		RelatesTo: request.MessageID,
+		Action:    FaultAction,
+		To:        AnonymousAddress,
+	}
+	status := http.StatusInternalServerError
+	if isSenderFault(code) {
+		status = http.StatusBadRequest
+	}
+	e.writeEnvelope(w, status, h, marshalFault(e.namespace, code, description))
+}
+
+func (e *endpoint) writeEnvelope(w http.ResponseWriter, status int, h header, body []byte) {
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(status)
+	if _, err := w.Write(marshalEnvelope(e.namespace, h, body)); err != nil {
+		log.Errorf("error writing SOAP response: %v", err)
+	}
+}
+
+// sendRequest translates an outgoing OCPP-J request into an OCPP-S request and posts it to the passed URL.
+// The response of the peer is returned as an OCPP-J response. Failures are returned as OCPP-J errors.
+func (e *endpoint) sendRequest(url string, identity string, data []byte, prepare func(r *http.Request)) []byte {
+	_, uniqueId, fields, err := parseFrame(data)
+	if err != nil || len(fields) < 4 {
+		return callErrorFrame(uniqueId, ocppj.FormatViolationV16, "invalid request")
+	}
+	var action string
+	_ = json.Unmarshal(fields[2], &action)
+	feature := e.feature(action)
+	if feature == nil {
+		return callErrorFrame(uniqueId, ocppj.NotImplemented, fmt.Sprintf("unsupported action %v", action))
+	}
+	var body bytes.Buffer
+	if err = writeXMLPayload(&body, bodyElementName(action, "Request"), e.peerNamespace, fields[3]); err != nil {
+		return callErrorFrame(uniqueId, ocppj.FormatViolationV16, err.Error())
+	}
+	e.mutex.RLock()
+	from := e.address
+	client := e.httpClient
+	e.mutex.RUnlock()
+	if from == "" {
+		from = AnonymousAddress
+	}
+	h := header{
+		ChargeBoxIdentity: identity,
+		MessageID:         newMessageID(),
+		Action:            "/" + action,
+		To:                url,
+		From:              from,
+		ReplyTo:           AnonymousAddress,
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(marshalEnvelope(e.peerNamespace, h, body.Bytes())))
+	if err != nil {
+		return callErrorFrame(uniqueId, ocppj.GenericError, err.Error())
+	}
+	req.Header.Set("Content-Type", fmt.Sprintf(`%s; action="/%s"`, ContentType, action))
+	if prepare != nil {
+		prepare(req)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return callErrorFrame(uniqueId, ocppj.GenericError, err.Error())
+	}
+	defer resp.Body.Close()
+	env, err := readEnvelope(resp.Body, e.getReadLimit())
+	if err != nil {
+		return callErrorFrame(uniqueId, ocppj.GenericError, fmt.Sprintf("HTTP status %v: %v", resp.StatusCode, err))
+	}
+	// Faults may not relate to the request, if the peer couldn't parse it
+	if relatesTo := env.header().RelatesTo; relatesTo != h.MessageID && (env.Body.Fault == nil || relatesTo != "") {
+		return callErrorFrame(uniqueId, ocppj.ProtocolError, fmt.Sprintf("response relates to message %q, expected %q", relatesTo, h.MessageID))
+	}
+	if env.Body.Fault != nil {
+		return callErrorFrame(uniqueId, env.Body.Fault.errorCode(), strings.TrimSpace(env.Body.Fault.Reason.Text))
+	}
+	node, err := parseXMLNode(env.Body.Content)
+	if err != nil {
+		return callErrorFrame(uniqueId, ocppj.FormatViolationV16, err.Error())
+	}
+	payload, err := xmlToJSON(node, feature.GetResponseType())
+	if err != nil {
+		return callErrorFrame(uniqueId, ocppj.FormatViolationV16, err.Error())
+	}
+	return callResultFrame(uniqueId, payload)
+}
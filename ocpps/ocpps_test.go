@@ -0,0 +1,348 @@
+package ocpps_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	ocpp16 "github.com/lorenzodonini/ocpp-go/ocpp1.6"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
+	"github.com/lorenzodonini/ocpp-go/ocppj"
+	"github.com/lorenzodonini/ocpp-go/ocpps"
+	"github.com/lorenzodonini/ocpp-go/ws"
+)
+
+const (
+	serverPort   = 8890
+	clientPort   = 8891
+	serverPath   = "/{ws}"
+	soapPath     = "/ocpp"
+	soapClientId = "soapCP"
+	jsonClientId = "jsonCP"
+)
+
+type centralSystemHandler struct {
+	core.CentralSystemHandler
+	mutex       sync.Mutex
+	boots       map[string]*core.BootNotificationRequest
+	meterValues map[string]*core.MeterValuesRequest
+}
+
+func (h *centralSystemHandler) OnBootNotification(chargePointId string, request *core.BootNotificationRequest) (*core.BootNotificationConfirmation, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.boots[chargePointId] = request
+	return core.NewBootNotificationConfirmation(types.NewDateTime(time.Now()), 60, core.RegistrationStatusAccepted), nil
+}
+
+func (h *centralSystemHandler) OnMeterValues(chargePointId string, request *core.MeterValuesRequest) (*core.MeterValuesConfirmation, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.meterValues[chargePointId] = request
+	return core.NewMeterValuesConfirmation(), nil
+}
+
+func (h *centralSystemHandler) OnHeartbeat(chargePointId string, request *core.HeartbeatRequest) (*core.HeartbeatConfirmation, error) {
+	return core.NewHeartbeatConfirmation(types.NewDateTime(time.Now())), nil
+}
+
+type chargePointHandler struct {
+	core.ChargePointHandler
+}
+
+func (h *chargePointHandler) OnChangeAvailability(request *core.ChangeAvailabilityRequest) (*core.ChangeAvailabilityConfirmation, error) {
+	if request.ConnectorId != 1 {
+		return core.NewChangeAvailabilityConfirmation(core.AvailabilityStatusRejected), nil
+	}
+	return core.NewChangeAvailabilityConfirmation(core.AvailabilityStatusScheduled), nil
+}
+
+type OcppSTestSuite struct {
+	suite.Suite
+	server        *ocpps.Server
+	centralSystem ocpp16.CentralSystem
+	handler       *centralSystemHandler
+}
+
+func (suite *OcppSTestSuite) SetupTest() {
+	suite.server = ocpps.NewServer(ws.NewServer())
+	suite.server.SetRequestTimeout(2 * time.Second)
+	suite.centralSystem = ocpp16.NewSOAPCentralSystem(suite.server)
+	suite.handler = &centralSystemHandler{
+		boots:       map[string]*core.BootNotificationRequest{},
+		meterValues: map[string]*core.MeterValuesRequest{},
+	}
+	suite.centralSystem.SetCoreHandler(suite.handler)
+	go suite.centralSystem.Start(serverPort, serverPath)
+	time.Sleep(100 * time.Millisecond)
+}
+
+func (suite *OcppSTestSuite) TearDownTest() {
+	suite.centralSystem.Stop()
+}
+
+func (suite *OcppSTestSuite) newSOAPChargePoint(listen bool) (ocpp16.ChargePoint, *ocpps.Client) {
+	client := ocpps.NewClient()
+	if listen {
+		client.SetListenAddress(fmt.Sprintf(":%d", clientPort))
+		client.SetEndpointAddress(fmt.Sprintf("http://localhost:%d/", clientPort))
+	}
+	chargePoint := ocpp16.NewSOAPChargePoint(soapClientId, client)
+	chargePoint.SetCoreHandler(&chargePointHandler{})
+	return chargePoint, client
+}
+
+func (suite *OcppSTestSuite) TestSOAPAndJSONChargePoints() {
+	t := suite.T()
+	connected := make(chan string, 2)
+	suite.centralSystem.SetNewChargePointHandler(func(chargePoint ocpp16.ChargePointConnection) {
+		connected <- chargePoint.ID()
+	})
+	// SOAP charge point, posting to a fixed URL
+	soapCP, _ := suite.newSOAPChargePoint(false)
+	require.NoError(t, soapCP.Start(fmt.Sprintf("http://localhost:%d%s", serverPort, soapPath)))
+	defer soapCP.Stop()
+	assert.True(t, soapCP.IsConnected())
+	// JSON charge point on the same port
+	jsonCP := ocpp16.NewChargePoint(jsonClientId, nil, nil)
+	require.NoError(t, jsonCP.Start(fmt.Sprintf("ws://localhost:%d", serverPort)))
+	defer jsonCP.Stop()
+	for _, cp := range []ocpp16.ChargePoint{soapCP, jsonCP} {
+		confirmation, err := cp.BootNotification("model1", "vendor1", func(request *core.BootNotificationRequest) {
+			request.FirmwareVersion = "1.0 <beta>"
+		})
+		require.NoError(t, err)
+		require.NotNil(t, confirmation)
+		assert.Equal(t, core.RegistrationStatusAccepted, confirmation.Status)
+		assert.Equal(t, 60, confirmation.Interval)
+		require.NotNil(t, confirmation.CurrentTime)
+	}
+	ids := []string{<-connected, <-connected}
+	assert.ElementsMatch(t, []string{soapClientId, jsonClientId}, ids)
+	suite.handler.mutex.Lock()
+	assert.Equal(t, suite.handler.boots[jsonClientId], suite.handler.boots[soapClientId])
+	assert.Equal(t, "1.0 <beta>", suite.handler.boots[soapClientId].FirmwareVersion)
+	suite.handler.mutex.Unlock()
+	// Arrays and nested types
+	meterValues := []types.MeterValue{
+		{Timestamp: types.NewDateTime(time.Now()), SampledValue: []types.SampledValue{{Value: "100", Measurand: types.MeasurandEnergyActiveImportRegister}}},
+		{Timestamp: types.NewDateTime(time.Now()), SampledValue: []types.SampledValue{{Value: "110"}, {Value: "16", Measurand: types.MeasurandCurrentImport, Unit: types.UnitOfMeasureA}}},
+	}
+	_, err := soapCP.MeterValues(1, meterValues)
+	require.NoError(t, err)
+	suite.handler.mutex.Lock()
+	request := suite.handler.meterValues[soapClientId]
+	suite.handler.mutex.Unlock()
+	require.NotNil(t, request)
+	assert.Equal(t, 1, request.ConnectorId)
+	require.Len(t, request.MeterValue, 2)
+	require.Len(t, request.MeterValue[0].SampledValue, 1)
+	require.Len(t, request.MeterValue[1].SampledValue, 2)
+	assert.Equal(t, meterValues[1].SampledValue[1], request.MeterValue[1].SampledValue[1])
+	assert.Equal(t, meterValues[0].Timestamp.FormatTimestamp(), request.MeterValue[0].Timestamp.FormatTimestamp())
+	// Channels of both charge points are visible
+	assert.Equal(t, 2, suite.server.ChannelCount())
+	channel, ok := suite.server.GetChannel(soapClientId)
+	require.True(t, ok)
+	assert.Equal(t, uint64(2), channel.Stats().MessagesIn)
+}
+
+func (suite *OcppSTestSuite) TestCentralSystemRequest() {
+	t := suite.T()
+	soapCP, _ := suite.newSOAPChargePoint(true)
+	require.NoError(t, soapCP.Start(fmt.Sprintf("http://localhost:%d%s", serverPort, soapPath)))
+	defer soapCP.Stop()
+	_, err := soapCP.BootNotification("model1", "vendor1")
+	require.NoError(t, err)
+	address, ok := suite.server.ChargePointAddress(soapClientId)
+	require.True(t, ok)
+	assert.Equal(t, fmt.Sprintf("http://localhost:%d/", clientPort), address)
+	type result struct {
+		confirmation *core.ChangeAvailabilityConfirmation
+		err          error
+	}
+	resultC := make(chan result, 1)
+	for connectorId, expectedStatus := range map[int]core.AvailabilityStatus{1: core.AvailabilityStatusScheduled, 2: core.AvailabilityStatusRejected} {
+		err = suite.centralSystem.ChangeAvailability(soapClientId, func(confirmation *core.ChangeAvailabilityConfirmation, err error) {
+			resultC <- result{confirmation, err}
+		}, connectorId, core.AvailabilityTypeInoperative)
+		require.NoError(t, err)
+		select {
+		case r := <-resultC:
+			require.NoError(t, r.err)
+			require.NotNil(t, r.confirmation)
+			assert.Equal(t, expectedStatus, r.confirmation.Status)
+		case <-time.After(2 * time.Second):
+			t.Fatal("timeout waiting for response")
+		}
+	}
+}
+
+func (suite *OcppSTestSuite) TestUnsupportedAction() {
+	t := suite.T()
+	soapCP, _ := suite.newSOAPChargePoint(false)
+	require.NoError(t, soapCP.Start(fmt.Sprintf("http://localhost:%d%s", serverPort, soapPath)))
+	defer soapCP.Stop()
+	// The charge point isn't listening, so requests can't be delivered
+	err := suite.centralSystem.ChangeAvailability(soapClientId, func(confirmation *core.ChangeAvailabilityConfirmation, err error) {}, 1, core.AvailabilityTypeInoperative)
+	assert.Error(t, err)
+	// Raw request with unknown action
+	body := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<soap:Envelope xmlns:soap="%s" xmlns:wsa="%s">
+<soap:Header><chargeBoxIdentity xmlns="%s">%s</chargeBoxIdentity><wsa:MessageID>urn:uuid:1234</wsa:MessageID><wsa:Action>/Unknown</wsa:Action></soap:Header>
+<soap:Body><unknownRequest xmlns="%s"/></soap:Body>
+</soap:Envelope>`, ocpps.SoapEnvelopeNamespace, ocpps.AddressingNamespace, ocpps.CentralSystemNamespace, soapClientId, ocpps.CentralSystemNamespace)
+	resp, err := http.Post(fmt.Sprintf("http://localhost:%d%s", serverPort, soapPath), ocpps.ContentType, bytes.NewBufferString(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.True(t, strings.Contains(string(data), "<wsa:RelatesTo>urn:uuid:1234</wsa:RelatesTo>"))
+	assert.True(t, strings.Contains(string(data), "ocpp:NotImplemented"))
+}
+
+func (suite *OcppSTestSuite) TestBasicAuth() {
+	t := suite.T()
+	suite.server.SetBasicAuthHandler(func(username string, password string) bool {
+		return username == "user" && password == "pass"
+	})
+	soapCP, _ := suite.newSOAPChargePoint(false)
+	require.NoError(t, soapCP.Start(fmt.Sprintf("http://localhost:%d%s", serverPort, soapPath)))
+	_, err := soapCP.BootNotification("model1", "vendor1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid credentials")
+	soapCP.Stop()
+	assert.Equal(t, 0, suite.server.ChannelCount())
+	soapCP, client := suite.newSOAPChargePoint(false)
+	client.SetBasicAuth("user", "pass")
+	require.NoError(t, soapCP.Start(fmt.Sprintf("http://localhost:%d%s", serverPort, soapPath)))
+	defer soapCP.Stop()
+	_, err = soapCP.BootNotification("model1", "vendor1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, suite.server.ChannelCount())
+}
+
+func (suite *OcppSTestSuite) TestSecurityProfileIdentity() {
+	t := suite.T()
+	suite.server.SetSecurityProfile(ws.SecurityProfile1)
+	suite.server.SetBasicAuthHandler(func(username string, password string) bool {
+		return password == "pass"
+	})
+	// Valid credentials of a different charge point are rejected
+	soapCP, client := suite.newSOAPChargePoint(false)
+	client.SetBasicAuth("otherCP", "pass")
+	require.NoError(t, soapCP.Start(fmt.Sprintf("http://localhost:%d%s", serverPort, soapPath)))
+	_, err := soapCP.BootNotification("model1", "vendor1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid credentials")
+	soapCP.Stop()
+	assert.Equal(t, 0, suite.server.ChannelCount())
+	soapCP, client = suite.newSOAPChargePoint(false)
+	client.SetBasicAuth(soapClientId, "pass")
+	require.NoError(t, soapCP.Start(fmt.Sprintf("http://localhost:%d%s", serverPort, soapPath)))
+	defer soapCP.Stop()
+	_, err = soapCP.BootNotification("model1", "vendor1")
+	require.NoError(t, err)
+	channel, ok := suite.server.GetChannel(soapClientId)
+	require.True(t, ok)
+	assert.Equal(t, ws.SecurityProfile1, channel.SecurityProfile())
+}
+
+func (suite *OcppSTestSuite) TestAddressUpdateRequiresIdentity() {
+	t := suite.T()
+	soapCP, _ := suite.newSOAPChargePoint(true)
+	require.NoError(t, soapCP.Start(fmt.Sprintf("http://localhost:%d%s", serverPort, soapPath)))
+	defer soapCP.Stop()
+	_, err := soapCP.BootNotification("model1", "vendor1")
+	require.NoError(t, err)
+	expectedAddress := fmt.Sprintf("http://localhost:%d/", clientPort)
+	address, _ := suite.server.ChargePointAddress(soapClientId)
+	require.Equal(t, expectedAddress, address)
+	// A request on behalf of the same charge point, with an unverified identity, doesn't redirect the charge point
+	body := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<soap:Envelope xmlns:soap="%s" xmlns:wsa="%s">
+<soap:Header><chargeBoxIdentity xmlns="%s">%s</chargeBoxIdentity><wsa:MessageID>urn:uuid:5678</wsa:MessageID><wsa:Action>/Heartbeat</wsa:Action><wsa:From><wsa:Address>http://attacker.example/</wsa:Address></wsa:From></soap:Header>
+<soap:Body><heartbeatRequest xmlns="%s"/></soap:Body>
+</soap:Envelope>`, ocpps.SoapEnvelopeNamespace, ocpps.AddressingNamespace, ocpps.CentralSystemNamespace, soapClientId, ocpps.CentralSystemNamespace)
+	resp, err := http.Post(fmt.Sprintf("http://localhost:%d%s", serverPort, soapPath), ocpps.ContentType, bytes.NewBufferString(body))
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	address, _ = suite.server.ChargePointAddress(soapClientId)
+	assert.Equal(t, expectedAddress, address)
+	// With a basic auth username matching the identity, the address is updated
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("http://localhost:%d%s", serverPort, soapPath), bytes.NewBufferString(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", ocpps.ContentType)
+	req.SetBasicAuth(soapClientId, "pass")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	address, _ = suite.server.ChargePointAddress(soapClientId)
+	assert.Equal(t, "http://attacker.example/", address)
+}
+
+func (suite *OcppSTestSuite) TestReadLimit() {
+	t := suite.T()
+	suite.server.SetTimeoutConfig(ws.ServerTimeoutConfig{ReadLimit: 512})
+	body := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<soap:Envelope xmlns:soap="%s" xmlns:wsa="%s">
+<soap:Header><chargeBoxIdentity xmlns="%s">%s</chargeBoxIdentity><wsa:MessageID>urn:uuid:1234</wsa:MessageID><wsa:Action>/Heartbeat</wsa:Action></soap:Header>
+<soap:Body><heartbeatRequest xmlns="%s"/></soap:Body>
+</soap:Envelope>`, ocpps.SoapEnvelopeNamespace, ocpps.AddressingNamespace, ocpps.CentralSystemNamespace, strings.Repeat("a", 1024), ocpps.CentralSystemNamespace)
+	resp, err := http.Post(fmt.Sprintf("http://localhost:%d%s", serverPort, soapPath), ocpps.ContentType, bytes.NewBufferString(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.True(t, strings.Contains(string(data), fmt.Sprintf("ocpp:%v", ocppj.FormatViolationV16)))
+	assert.True(t, strings.Contains(string(data), "read limit"))
+	assert.Equal(t, 0, suite.server.ChannelCount())
+}
+
+func (suite *OcppSTestSuite) TestResponseRelatesTo() {
+	t := suite.T()
+	// Proxy rewriting the RelatesTo header of all responses
+	target, err := url.Parse(fmt.Sprintf("http://localhost:%d", serverPort))
+	require.NoError(t, err)
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	relatesTo := regexp.MustCompile(`<wsa:RelatesTo>[^<]*</wsa:RelatesTo>`)
+	proxy.ModifyResponse = func(resp *http.Response) error {
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		data = relatesTo.ReplaceAll(data, []byte("<wsa:RelatesTo>urn:uuid:other</wsa:RelatesTo>"))
+		resp.Body = io.NopCloser(bytes.NewReader(data))
+		resp.ContentLength = int64(len(data))
+		resp.Header.Del("Content-Length")
+		return nil
+	}
+	proxyServer := httptest.NewServer(proxy)
+	defer proxyServer.Close()
+	soapCP, _ := suite.newSOAPChargePoint(false)
+	require.NoError(t, soapCP.Start(proxyServer.URL+soapPath))
+	defer soapCP.Stop()
+	_, err = soapCP.BootNotification("model1", "vendor1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "response relates to message \"urn:uuid:other\"")
+}
+
+func TestOcppS(t *testing.T) {
+	suite.Run(t, new(OcppSTestSuite))
+}
@@ -0,0 +1,323 @@
+package ocpps
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/lorenzodonini/ocpp-go/ocppj"
+	"github.com/lorenzodonini/ocpp-go/ws"
+)
+
+// Server is the central system side of OCPP-S. It implements the ws.Server interface on top of a websocket server,
+// so that OCPP-J charge points connecting via websocket and OCPP-S charge points sending SOAP requests via HTTP POST
+// are accepted on the same port and path.
+//
+// Each OCPP-S charge point is represented by a ws.Channel, identified by the chargeBoxIdentity header.
+// The channel is created when the charge point sends its first request, which also triggers the NewClientHandler.
+// Requests to the charge point are posted to the address contained in the WS-Addressing From header of its requests.
+// If the central system needs to send requests before the charge point contacted it, the address may be registered via SetChargePointAddress.
+//
+// OCPP-S requests are accepted on the listen path of the websocket server. Since OCPP-S charge points usually post
+// all requests to a fixed URL, the listen path must match that URL as well, e.g. "/{ws}" matches "/ocpp".
+// Alternatively, the Handler may be mounted on an external HTTP server.
+//
+// Admission control, the security profile, basic auth and check-client handlers apply to both websocket and
+// OCPP-S charge points. Since OCPP-S doesn't keep a connection open, OCPP-S requests are authenticated one by one,
+// on behalf of their chargeBoxIdentity header.
+//
+// The address contained in the From header may only replace a known address, if the identity of the charge point
+// was verified, i.e. if a security profile is enforced or the basic auth username matches the chargeBoxIdentity.
+type Server struct {
+	ws.Server
+	endpoint
+	channels            map[string]*channel
+	messageHandler      ws.MessageHandler
+	newClientHandler    ws.ConnectedHandler
+	disconnectedHandler func(ws ws.Channel)
+	handlerMutex        sync.RWMutex
+	channelMutex        sync.RWMutex
+}
+
+// NewServer creates a new OCPP-S server, on top of the passed websocket server.
+// If no websocket server is passed, a default one is created.
+//
+// The profiles of all supported messages must be added via AddProfile, before starting the server.
+func NewServer(wsServer ws.Server) *Server {
+	if wsServer == nil {
+		wsServer = ws.NewServer()
+	}
+	s := &Server{
+		Server:   wsServer,
+		endpoint: newEndpoint(CentralSystemNamespace, ChargePointNamespace),
+		channels: map[string]*channel{},
+	}
+	wsServer.SetFallbackHandler(s.Handler())
+	return s
+}
+
+// SetTimeoutConfig sets the timeout configuration of the underlying websocket server.
+// The read limit applies to the SOAP envelopes received from OCPP-S charge points as well.
+// If no read limit is set, envelopes are limited to 1 MiB.
+func (s *Server) SetTimeoutConfig(config ws.ServerTimeoutConfig) {
+	s.setReadLimit(config.ReadLimit)
+	s.Server.SetTimeoutConfig(config)
+}
+
+// Handler returns an http.Handler, which processes requests sent by OCPP-S charge points.
+func (s *Server) Handler() http.Handler {
+	return http.HandlerFunc(s.handleHTTP)
+}
+
+// SetChargePointAddress registers the address of the OCPP-S endpoint of a charge point.
+// If the charge point isn't known yet, a new channel is created and the NewClientHandler is invoked.
+//
+// The address is updated automatically, whenever the charge point sends a request containing a From header,
+// as long as the identity of the charge point was verified.
+func (s *Server) SetChargePointAddress(chargePointId string, address string) {
+	c, created := s.getOrCreateChannel(chargePointId)
+	c.setAddress(address)
+	if created {
+		s.onNewChannel(c)
+	}
+}
+
+// ChargePointAddress returns the address of the OCPP-S endpoint of a charge point, if known.
+func (s *Server) ChargePointAddress(chargePointId string) (string, bool) {
+	c, ok := s.getChannel(chargePointId)
+	if !ok {
+		return "", false
+	}
+	address := c.getAddress()
+	return address, address != ""
+}
+
+func (s *Server) SetMessageHandler(handler ws.MessageHandler) {
+	s.handlerMutex.Lock()
+	s.messageHandler = handler
+	s.handlerMutex.Unlock()
+	s.Server.SetMessageHandler(handler)
+}
+
+func (s *Server) SetNewClientHandler(handler ws.ConnectedHandler) {
+	s.handlerMutex.Lock()
+	s.newClientHandler = handler
+	s.handlerMutex.Unlock()
+	s.Server.SetNewClientHandler(handler)
+}
+
+func (s *Server) SetDisconnectedClientHandler(handler func(ws ws.Channel)) {
+	s.handlerMutex.Lock()
+	s.disconnectedHandler = handler
+	s.handlerMutex.Unlock()
+	s.Server.SetDisconnectedClientHandler(handler)
+}
+
+// SetFallbackHandler has no effect, since the server handles all non-websocket requests itself.
+func (s *Server) SetFallbackHandler(_ http.Handler) {
+	log.Error("cannot set fallback handler on OCPP-S server")
+}
+
+func (s *Server) Write(webSocketId string, data []byte) error {
+	c, ok := s.getChannel(webSocketId)
+	if !ok {
+		return s.Server.Write(webSocketId, data)
+	}
+	c.recordMessageOut(len(data))
+	if isReply, err := s.reply(data); isReply {
+		return err
+	}
+	typeId, _, _, err := parseFrame(data)
+	if err != nil {
+		return err
+	}
+	if typeId != ocppj.CALL {
+		return fmt.Errorf("unsupported message type %v for OCPP-S charge point %s", typeId, c.id)
+	}
+	address := c.getAddress()
+	if address == "" {
+		return fmt.Errorf("couldn't send request to OCPP-S charge point %s: endpoint address unknown", c.id)
+	}
+	// Requests are sent asynchronously, the response is passed to the message handler
+	go func() {
+		response := s.sendRequest(address, c.id, data, nil)
+		s.dispatch(c, response)
+	}()
+	return nil
+}
+
+func (s *Server) StopConnection(id string, closeError websocket.CloseError) error {
+	c, ok := s.removeChannel(id)
+	if !ok {
+		return s.Server.StopConnection(id, closeError)
+	}
+	s.onChannelClosed(c, closeError)
+	return nil
+}
+
+func (s *Server) GetChannel(websocketId string) (ws.Channel, bool) {
+	if c, ok := s.getChannel(websocketId); ok {
+		return c, true
+	}
+	return s.Server.GetChannel(websocketId)
+}
+
+func (s *Server) Channels() []ws.Channel {
+	channels := s.Server.Channels()
+	s.channelMutex.RLock()
+	for _, c := range s.channels {
+		channels = append(channels, c)
+	}
+	s.channelMutex.RUnlock()
+	sort.Slice(channels, func(i, j int) bool {
+		return channels[i].ID() < channels[j].ID()
+	})
+	return channels
+}
+
+func (s *Server) ChannelCount() int {
+	s.channelMutex.RLock()
+	defer s.channelMutex.RUnlock()
+	return s.Server.ChannelCount() + len(s.channels)
+}
+
+// Stop stops the underlying websocket server and closes all OCPP-S channels.
+func (s *Server) Stop() {
+	s.Server.Stop()
+	s.closeChannels(websocket.CloseError{Code: websocket.CloseNormalClosure})
+}
+
+// Shutdown gracefully shuts down the underlying websocket server, then closes all OCPP-S channels.
+func (s *Server) Shutdown(ctx context.Context) error {
+	err := s.Server.Shutdown(ctx)
+	s.closeChannels(websocket.CloseError{Code: websocket.CloseGoingAway, Text: "server shutting down"})
+	return err
+}
+
+func (s *Server) handleHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	limit := s.getReadLimit()
+	// One extra byte allows readEnvelope to detect and report oversized envelopes
+	r.Body = http.MaxBytesReader(w, r.Body, limit+1)
+	env, err := readEnvelope(r.Body, limit)
+	if err != nil {
+		s.writeFault(w, header{}, ocppj.FormatViolationV16, err.Error())
+		return
+	}
+	h := env.header()
+	id := h.ChargeBoxIdentity
+	if id == "" {
+		s.writeFault(w, h, ocppj.ProtocolError, "missing chargeBoxIdentity header")
+		return
+	}
+	// Every request is authenticated on behalf of the charge point identity
+	if status, err := s.Server.AuthenticateRequest(id, r); err != nil {
+		log.Errorf("OCPP-S request from %s rejected: %v", r.RemoteAddr, err)
+		description := fmt.Sprintf("charge point %s was rejected", id)
+		if status == http.StatusUnauthorized {
+			w.Header().Set("WWW-Authenticate", `Basic realm="Access to the OCPP-S endpoint"`)
+			description = "invalid credentials"
+		}
+		s.writeEnvelope(w, status, header{RelatesTo: h.MessageID, Action: FaultAction}, marshalFault(s.namespace, ocppj.SecurityError, description))
+		return
+	}
+	if _, ok := s.Server.GetChannel(id); ok {
+		s.writeFault(w, h, ocppj.SecurityError, fmt.Sprintf("charge point %s is connected via websocket", id))
+		return
+	}
+	profile := s.Server.SecurityProfile()
+	username, _, hasBasicAuth := r.BasicAuth()
+	identityVerified := profile != ws.SecurityProfileNone || (hasBasicAuth && username == id)
+	c, created := s.getOrCreateChannel(id)
+	remoteAddr, _ := net.ResolveTCPAddr("tcp", r.RemoteAddr)
+	c.update(remoteAddr, r.TLS, profile, h.From, identityVerified)
+	if created {
+		s.onNewChannel(c)
+	}
+	s.handleRequest(w, r, env, func(frame []byte) error {
+		return s.dispatch(c, frame)
+	})
+}
+
+// dispatch passes an OCPP-J message, translated from an OCPP-S message, to the message handler.
+func (s *Server) dispatch(c *channel, frame []byte) error {
+	c.recordMessageIn(len(frame))
+	s.handlerMutex.RLock()
+	handler := s.messageHandler
+	s.handlerMutex.RUnlock()
+	if handler == nil {
+		return fmt.Errorf("no message handler set")
+	}
+	err := handler(c, frame)
+	if err != nil {
+		log.Errorf("error handling message from %s: %v", c.id, err)
+	}
+	return err
+}
+
+func (s *Server) getChannel(id string) (*channel, bool) {
+	s.channelMutex.RLock()
+	defer s.channelMutex.RUnlock()
+	c, ok := s.channels[id]
+	return c, ok
+}
+
+func (s *Server) getOrCreateChannel(id string) (*channel, bool) {
+	s.channelMutex.Lock()
+	defer s.channelMutex.Unlock()
+	if c, ok := s.channels[id]; ok {
+		return c, false
+	}
+	c := newChannel(id)
+	s.channels[id] = c
+	return c, true
+}
+
+func (s *Server) removeChannel(id string) (*channel, bool) {
+	s.channelMutex.Lock()
+	defer s.channelMutex.Unlock()
+	c, ok := s.channels[id]
+	if ok {
+		delete(s.channels, id)
+	}
+	return c, ok
+}
+
+func (s *Server) onNewChannel(c *channel) {
+	log.Infof("new OCPP-S charge point %s", c.id)
+	s.handlerMutex.RLock()
+	handler := s.newClientHandler
+	s.handlerMutex.RUnlock()
+	if handler != nil {
+		handler(c)
+	}
+}
+
+func (s *Server) onChannelClosed(c *channel, closeError websocket.CloseError) {
+	c.close(&ws.DisconnectReason{Code: closeError.Code, Text: closeError.Text, Initiator: ws.DisconnectInitiatorLocal})
+	log.Infof("closed OCPP-S charge point %s", c.id)
+	s.handlerMutex.RLock()
+	handler := s.disconnectedHandler
+	s.handlerMutex.RUnlock()
+	if handler != nil {
+		handler(c)
+	}
+}
+
+func (s *Server) closeChannels(closeError websocket.CloseError) {
+	s.channelMutex.Lock()
+	channels := s.channels
+	s.channels = map[string]*channel{}
+	s.channelMutex.Unlock()
+	for _, c := range channels {
+		s.onChannelClosed(c, closeError)
+	}
+}
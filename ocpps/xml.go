@@ -0,0 +1,310 @@
+package ocpps
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// The element names of OCPP-S payloads are equal to the field names of the OCPP-J payloads.
+// Payloads are therefore converted between both formats via JSON, which allows reusing
+// the JSON tags, custom marshalers and validation rules of all OCPP message types.
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// writeXMLPayload writes a JSON payload as an XML element with the passed name and namespace.
+// Object fields are written as child elements in the order they appear in, while array items are written as repeated elements.
+func writeXMLPayload(buf *bytes.Buffer, name string, namespace string, payload json.RawMessage) error {
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	buf.WriteString(fmt.Sprintf(`<%s xmlns="%s">`, name, namespace))
+	if err := writeXMLChildren(buf, dec); err != nil {
+		return err
+	}
+	buf.WriteString(fmt.Sprintf("</%s>", name))
+	return nil
+}
+
+// writeXMLChildren writes the fields of the next JSON object as XML elements.
+func writeXMLChildren(buf *bytes.Buffer, dec *json.Decoder) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("expected JSON object, got %v", tok)
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if err = writeXMLValue(buf, key.(string), dec); err != nil {
+			return err
+		}
+	}
+	// Closing delimiter
+	_, err = dec.Token()
+	return err
+}
+
+// writeXMLValue writes the next JSON value as one or more XML elements with the passed name.
+func writeXMLValue(buf *bytes.Buffer, name string, dec *json.Decoder) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			buf.WriteString(fmt.Sprintf("<%s>", name))
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				if err = writeXMLValue(buf, key.(string), dec); err != nil {
+					return err
+				}
+			}
+			buf.WriteString(fmt.Sprintf("</%s>", name))
+		case '[':
+			for dec.More() {
+				if err = writeXMLValue(buf, name, dec); err != nil {
+					return err
+				}
+			}
+		}
+		// Closing delimiter
+		_, err = dec.Token()
+		return err
+	case nil:
+		// Null values are omitted
+		return nil
+	case string:
+		return writeXMLText(buf, name, t)
+	case json.Number:
+		return writeXMLText(buf, name, t.String())
+	case bool:
+		return writeXMLText(buf, name, strconv.FormatBool(t))
+	default:
+		return fmt.Errorf("unexpected JSON token %v", tok)
+	}
+}
+
+func writeXMLText(buf *bytes.Buffer, name string, text string) error {
+	buf.WriteString(fmt.Sprintf("<%s>", name))
+	if err := xml.EscapeText(buf, []byte(text)); err != nil {
+		return err
+	}
+	buf.WriteString(fmt.Sprintf("</%s>", name))
+	return nil
+}
+
+// xmlNode is a generic XML element, without attributes.
+type xmlNode struct {
+	name     xml.Name
+	text     string
+	children []*xmlNode
+}
+
+// childrenNamed returns all child elements with the passed local name.
+func (n *xmlNode) childrenNamed(name string) []*xmlNode {
+	var result []*xmlNode
+	for _, c := range n.children {
+		if c.name.Local == name {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// parseXMLNode parses the first element contained in data. Leading whitespace and comments are skipped.
+func parseXMLNode(data []byte) (*xmlNode, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil, fmt.Errorf("no XML element found")
+		} else if err != nil {
+			return nil, err
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			return parseXMLElement(dec, start)
+		}
+	}
+}
+
+func parseXMLElement(dec *xml.Decoder, start xml.StartElement) (*xmlNode, error) {
+	node := &xmlNode{name: start.Name}
+	var text strings.Builder
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			child, err := parseXMLElement(dec, t)
+			if err != nil {
+				return nil, err
+			}
+			node.children = append(node.children, child)
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			node.text = text.String()
+			return node, nil
+		}
+	}
+}
+
+// xmlToJSON converts an XML element into the JSON representation of the passed type.
+// The type is required, since XML doesn't distinguish between strings, numbers and single-item arrays.
+func xmlToJSON(node *xmlNode, t reflect.Type) (json.RawMessage, error) {
+	var buf bytes.Buffer
+	if err := writeJSONValue(&buf, node, t); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeJSONValue(buf *bytes.Buffer, node *xmlNode, t reflect.Type) error {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if reflect.PtrTo(t).Implements(jsonUnmarshalerType) {
+		// Custom types (e.g. date-times) are represented as JSON strings
+		return writeJSONString(buf, node.text)
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		buf.WriteByte('{')
+		first := true
+		if err := writeJSONFields(buf, node, t, &first); err != nil {
+			return err
+		}
+		buf.WriteByte('}')
+	case reflect.String:
+		return writeJSONString(buf, node.text)
+	case reflect.Slice:
+		if t.Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("unsupported type %v for element %v", t, node.name.Local)
+		}
+		// Binary data is base64 encoded in both formats
+		return writeJSONString(buf, strings.TrimSpace(node.text))
+	case reflect.Bool:
+		value, err := strconv.ParseBool(strings.TrimSpace(node.text))
+		if err != nil {
+			return fmt.Errorf("invalid boolean value %v for element %v", node.text, node.name.Local)
+		}
+		buf.WriteString(strconv.FormatBool(value))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		text := strings.TrimSpace(node.text)
+		if _, err := strconv.ParseFloat(text, 64); err != nil {
+			return fmt.Errorf("invalid numeric value %v for element %v", node.text, node.name.Local)
+		}
+		buf.WriteString(text)
+	case reflect.Interface, reflect.Map:
+		return writeJSONGeneric(buf, node)
+	default:
+		return fmt.Errorf("unsupported type %v for element %v", t, node.name.Local)
+	}
+	return nil
+}
+
+// writeJSONFields writes all fields of a struct type, which are contained in the XML element.
+// Fields of embedded structs are written as if they belonged to the outer struct.
+func writeJSONFields(buf *bytes.Buffer, node *xmlNode, t reflect.Type, first *bool) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := field.Name
+		if tag, ok := field.Tag.Lookup("json"); ok {
+			tagName := strings.Split(tag, ",")[0]
+			if tagName == "-" {
+				continue
+			} else if tagName != "" {
+				name = tagName
+			}
+		} else if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if err := writeJSONFields(buf, node, field.Type, first); err != nil {
+				return err
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			// Unexported field
+			continue
+		}
+		children := node.childrenNamed(name)
+		if len(children) == 0 {
+			continue
+		}
+		if !*first {
+			buf.WriteByte(',')
+		}
+		*first = false
+		if err := writeJSONString(buf, name); err != nil {
+			return err
+		}
+		buf.WriteByte(':')
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Slice && fieldType.Elem().Kind() != reflect.Uint8 {
+			buf.WriteByte('[')
+			for j, child := range children {
+				if j > 0 {
+					buf.WriteByte(',')
+				}
+				if err := writeJSONValue(buf, child, fieldType.Elem()); err != nil {
+					return err
+				}
+			}
+			buf.WriteByte(']')
+		} else if err := writeJSONValue(buf, children[0], fieldType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeJSONGeneric writes an element of unknown type: simple elements become strings, complex elements become objects.
+func writeJSONGeneric(buf *bytes.Buffer, node *xmlNode) error {
+	if len(node.children) == 0 {
+		return writeJSONString(buf, node.text)
+	}
+	buf.WriteByte('{')
+	for i, child := range node.children {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := writeJSONString(buf, child.name.Local); err != nil {
+			return err
+		}
+		buf.WriteByte(':')
+		if err := writeJSONGeneric(buf, child); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+func writeJSONString(buf *bytes.Buffer, s string) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	buf.Write(data)
+	return nil
+}
@@ -0,0 +1,257 @@
+package ocsp
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/authorization"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/iso15118"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/types"
+)
+
+const (
+	defaultTimeout      = 10 * time.Second
+	maxResponseSize     = 1 << 20
+	maxOcspResultLength = 5500
+	maxAdditionalInfo   = 512
+	reasonResponderErr  = "OCSPResponderError"
+	reasonResultTooLong = "OCSPResultTooLong"
+)
+
+// Client queries OCSP responders on behalf of the CSMS. It is safe for concurrent use.
+//
+// Responses are verified against the issuer certificates passed via WithIssuers, and cached until their nextUpdate time.
+// Responses without a nextUpdate time are never cached.
+type Client struct {
+	responderURL string
+	allowedURLs  []string
+	issuers      []*x509.Certificate
+	httpClient   *http.Client
+	mutex        sync.Mutex
+	cache        map[string]*Response
+	now          func() time.Time
+}
+
+// ClientOpt is a function that can be used to set options on a client during creation.
+type ClientOpt func(c *Client)
+
+// WithResponderURL sets the URL of the OCSP responder to query for all requests,
+// overriding the responderURL contained in the OCPP request data.
+func WithResponderURL(url string) ClientOpt {
+	return func(c *Client) {
+		c.responderURL = url
+	}
+}
+
+// WithAllowedResponderURLs allows querying the passed responder URLs, if they are contained in the OCPP request data.
+//
+// The responderURL contained in the request data is chosen by the charging station. Without a responder URL set via
+// WithResponderURL, it is only queried if it was allowed via this option, or is listed as OCSP server of the issuer.
+func WithAllowedResponderURLs(urls ...string) ClientOpt {
+	return func(c *Client) {
+		c.allowedURLs = append(c.allowedURLs, urls...)
+	}
+}
+
+// WithIssuers sets the trusted issuer certificates, against which the OCSP responses are verified.
+//
+// The issuer of a certificate is looked up via the issuer name hash and issuer key hash contained in the OCPP request data.
+// Queries for certificates of unknown issuers fail, since their responses cannot be verified.
+func WithIssuers(issuers ...*x509.Certificate) ClientOpt {
+	return func(c *Client) {
+		c.issuers = append(c.issuers, issuers...)
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for querying the OCSP responder.
+// By default, an HTTP client with a timeout of 10 seconds is used.
+func WithHTTPClient(httpClient *http.Client) ClientOpt {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.httpClient = httpClient
+		}
+	}
+}
+
+// NewClient creates a new OCSP client with an empty response cache.
+func NewClient(opts ...ClientOpt) *Client {
+	c := &Client{
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		cache:      map[string]*Response{},
+		now:        time.Now,
+	}
+	for _, o := range opts {
+		o(c)
+	}
+	return c
+}
+
+// Query returns the status of the certificate identified by the passed OCPP request data.
+//
+// A cached response is returned if it is still valid, otherwise the OCSP responder is queried via HTTP POST.
+// The responder URL set via WithResponderURL takes precedence over the responderURL contained in the request data,
+// which is only queried if it was allowed via WithAllowedResponderURLs or is listed as OCSP server of the issuer.
+// A response is only returned and cached after its signature was verified against the issuer of the certificate.
+func (c *Client) Query(data types.OCSPRequestDataType) (*Response, error) {
+	key := cacheKey(data)
+	if response := c.cachedResponse(key); response != nil {
+		return response, nil
+	}
+	issuer, err := c.issuer(data)
+	if err != nil {
+		return nil, err
+	}
+	url, err := c.queryURL(data, issuer)
+	if err != nil {
+		return nil, err
+	}
+	req, err := CreateRequest(data)
+	if err != nil {
+		return nil, err
+	}
+	httpRequest, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(req))
+	if err != nil {
+		return nil, err
+	}
+	httpRequest.Header.Set("Content-Type", "application/ocsp-request")
+	httpRequest.Header.Set("Accept", "application/ocsp-response")
+	httpResponse, err := c.httpClient.Do(httpRequest)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't query OCSP responder: %w", err)
+	}
+	defer httpResponse.Body.Close()
+	if httpResponse.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OCSP responder returned HTTP status %v", httpResponse.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(httpResponse.Body, maxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read OCSP response: %w", err)
+	}
+	response, err := parseResponse(body, data, issuer, c.now())
+	if err != nil {
+		return nil, err
+	}
+	if response.NextUpdate.After(c.now()) {
+		c.mutex.Lock()
+		c.cache[key] = response
+		c.mutex.Unlock()
+	}
+	return response, nil
+}
+
+// GetCertificateStatus queries the OCSP responder for the certificate contained in a GetCertificateStatus request,
+// and returns the respective response, which may be directly returned by the CSMS handler.
+//
+// If the responder was queried successfully, the response is accepted and contains the base64-encoded OCSP response,
+// regardless of the certificate status. Otherwise, the response is rejected and the reason is contained in the status info.
+func (c *Client) GetCertificateStatus(request *iso15118.GetCertificateStatusRequest) *iso15118.GetCertificateStatusResponse {
+	response, err := c.Query(request.OcspRequestData)
+	if err != nil {
+		return rejectedCertificateStatus(reasonResponderErr, err.Error())
+	}
+	result := base64.StdEncoding.EncodeToString(response.Raw)
+	if len(result) > maxOcspResultLength {
+		return rejectedCertificateStatus(reasonResultTooLong, fmt.Sprintf("OCSP result exceeds %d characters", maxOcspResultLength))
+	}
+	statusResponse := iso15118.NewGetCertificateStatusResponse(types.GenericStatusAccepted)
+	statusResponse.OcspResult = result
+	return statusResponse
+}
+
+// AuthorizeCertificateStatus queries the OCSP responder for all certificates contained in the
+// iso15118CertificateHashData of an Authorize request, and returns the resulting certificate status:
+//
+//   - NoCertificateAvailable, if no certificate hash data was passed
+//   - CertificateRevoked, if any certificate was revoked
+//   - CertChainError, if the status of any certificate is unknown to the responder
+//   - Accepted, if all certificates are good
+//
+// If any responder couldn't be queried, an error is returned and the CSMS may apply its own policy.
+func (c *Client) AuthorizeCertificateStatus(hashData []types.OCSPRequestDataType) (authorization.AuthorizeCertificateStatus, error) {
+	if len(hashData) == 0 {
+		return authorization.CertificateStatusNoCertificateAvailable, nil
+	}
+	status := authorization.CertificateStatusAccepted
+	for _, data := range hashData {
+		response, err := c.Query(data)
+		if err != nil {
+			return "", err
+		}
+		switch response.Status {
+		case CertificateStatusRevoked:
+			return authorization.CertificateStatusCertificateRevoked, nil
+		case CertificateStatusUnknown:
+			status = authorization.CertificateStatusCertChainError
+		}
+	}
+	return status, nil
+}
+
+func (c *Client) issuer(data types.OCSPRequestDataType) (*x509.Certificate, error) {
+	id, err := newCertID(data)
+	if err != nil {
+		return nil, err
+	}
+	for _, issuer := range c.issuers {
+		if id.issuedBy(issuer) {
+			return issuer, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown issuer of certificate with serial number %v", data.SerialNumber)
+}
+
+// Returns the URL of the responder to query for the passed request data.
+// URLs chosen by the charging station are never queried, unless they are trusted.
+func (c *Client) queryURL(data types.OCSPRequestDataType, issuer *x509.Certificate) (string, error) {
+	if c.responderURL != "" {
+		return c.responderURL, nil
+	}
+	if data.ResponderURL == "" {
+		return "", fmt.Errorf("missing OCSP responder URL")
+	}
+	for _, url := range c.allowedURLs {
+		if url == data.ResponderURL {
+			return url, nil
+		}
+	}
+	for _, url := range issuer.OCSPServer {
+		if url == data.ResponderURL {
+			return url, nil
+		}
+	}
+	return "", fmt.Errorf("untrusted OCSP responder URL %v", data.ResponderURL)
+}
+
+func (c *Client) cachedResponse(key string) *Response {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	response, ok := c.cache[key]
+	if !ok {
+		return nil
+	}
+	if !response.NextUpdate.After(c.now()) {
+		delete(c.cache, key)
+		return nil
+	}
+	return response
+}
+
+func cacheKey(data types.OCSPRequestDataType) string {
+	return strings.ToLower(strings.Join([]string{string(data.HashAlgorithm), data.IssuerNameHash, data.IssuerKeyHash, data.SerialNumber}, "|"))
+}
+
+func rejectedCertificateStatus(reasonCode string, additionalInfo string) *iso15118.GetCertificateStatusResponse {
+	if len(additionalInfo) > maxAdditionalInfo {
+		additionalInfo = additionalInfo[:maxAdditionalInfo]
+	}
+	response := iso15118.NewGetCertificateStatusResponse(types.GenericStatusRejected)
+	response.StatusInfo = types.NewStatusInfo(reasonCode, additionalInfo)
+	return response
+}
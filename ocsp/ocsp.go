@@ -0,0 +1,386 @@
+// Contains CSMS-side helpers for OCSP (Online Certificate Status Protocol, RFC 6960) requests in OCPP 2.0.1.
+//
+// Charging stations send the hash data of ISO 15118 certificates within GetCertificateStatus and Authorize requests.
+// The CSMS is expected to query the respective OCSP responder and, for GetCertificateStatus, to forward the
+// DER-encoded OCSP response to the charging station. A Client builds the OCSP requests, sends them to the responder,
+// caches the responses until their nextUpdate time and maps them to the respective OCPP responses:
+//
+//	client := ocsp.NewClient(ocsp.WithIssuers(issuerCertificates...))
+//	handler.OnGetCertificateStatus = func(chargingStationID string, request *iso15118.GetCertificateStatusRequest) (*iso15118.GetCertificateStatusResponse, error) {
+//		return client.GetCertificateStatus(request), nil
+//	}
+//
+// Every OCSP response is verified before its status is used or cached: the signature must have been created by the
+// issuer of the certificate or by a delegated responder certified by the issuer, and the response must be neither expired
+// nor issued in the future.
+// The trusted issuer certificates are passed via WithIssuers. For GetCertificateStatus, the charging station additionally
+// validates the forwarded response, whereas the status returned by AuthorizeCertificateStatus is only checked by the client.
+//
+// The responder URL contained in the request data is chosen by the charging station. Unless a responder URL is configured
+// via WithResponderURL, it is only queried if it was allowed via WithAllowedResponderURLs or is listed as an OCSP server
+// of the issuer certificate.
+package ocsp
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/types"
+)
+
+// CertificateStatus is the revocation status of a certificate, as reported by an OCSP responder.
+type CertificateStatus string
+
+const (
+	CertificateStatusGood    CertificateStatus = "Good"
+	CertificateStatusRevoked CertificateStatus = "Revoked"
+	CertificateStatusUnknown CertificateStatus = "Unknown"
+)
+
+// Maximum tolerated offset between the clocks of the OCSP responder and the CSMS,
+// when checking that the thisUpdate time of a response doesn't lie in the future.
+const maxClockSkew = 5 * time.Minute
+
+// Response is a parsed OCSP response for a single certificate.
+//
+// Raw contains the complete DER-encoded OCSP response, as received by the responder.
+type Response struct {
+	Status           CertificateStatus
+	SerialNumber     *big.Int
+	ProducedAt       time.Time
+	ThisUpdate       time.Time
+	NextUpdate       time.Time // Zero if the responder didn't specify when newer information will be available.
+	RevokedAt        time.Time
+	RevocationReason int
+	Raw              []byte
+}
+
+var (
+	oidSHA256            = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidSHA384            = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 2}
+	oidSHA512            = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 3}
+	oidOCSPBasicResponse = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1, 1}
+)
+
+// Signature algorithms supported for OCSP responses.
+var signatureAlgorithms = map[string]x509.SignatureAlgorithm{
+	"1.2.840.10045.4.3.2":   x509.ECDSAWithSHA256,
+	"1.2.840.10045.4.3.3":   x509.ECDSAWithSHA384,
+	"1.2.840.10045.4.3.4":   x509.ECDSAWithSHA512,
+	"1.2.840.113549.1.1.11": x509.SHA256WithRSA,
+	"1.2.840.113549.1.1.12": x509.SHA384WithRSA,
+	"1.2.840.113549.1.1.13": x509.SHA512WithRSA,
+	"1.3.101.112":           x509.PureEd25519,
+}
+
+// ASN.1 structures, as defined by RFC 6960.
+
+type certID struct {
+	HashAlgorithm pkix.AlgorithmIdentifier
+	NameHash      []byte
+	IssuerKeyHash []byte
+	SerialNumber  *big.Int
+}
+
+type ocspRequest struct {
+	TBSRequest tbsRequest
+}
+
+type tbsRequest struct {
+	Version       int              `asn1:"explicit,tag:0,default:0,optional"`
+	RequestorName pkix.RDNSequence `asn1:"explicit,tag:1,optional"`
+	RequestList   []request
+}
+
+type request struct {
+	Cert certID
+}
+
+type ocspResponse struct {
+	Status   asn1.Enumerated
+	Response responseBytes `asn1:"explicit,tag:0,optional"`
+}
+
+type responseBytes struct {
+	ResponseType asn1.ObjectIdentifier
+	Response     []byte
+}
+
+type basicResponse struct {
+	TBSResponseData    responseData
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Signature          asn1.BitString
+	Certificates       []asn1.RawValue `asn1:"explicit,tag:0,optional"`
+}
+
+type responseData struct {
+	Raw            asn1.RawContent
+	Version        int `asn1:"optional,default:0,explicit,tag:0"`
+	RawResponderID asn1.RawValue
+	ProducedAt     time.Time `asn1:"generalized"`
+	Responses      []singleResponse
+}
+
+type singleResponse struct {
+	CertID           certID
+	Good             asn1.Flag        `asn1:"tag:0,optional"`
+	Revoked          revokedInfo      `asn1:"tag:1,optional"`
+	Unknown          asn1.Flag        `asn1:"tag:2,optional"`
+	ThisUpdate       time.Time        `asn1:"generalized"`
+	NextUpdate       time.Time        `asn1:"generalized,explicit,tag:0,optional"`
+	SingleExtensions []pkix.Extension `asn1:"explicit,tag:1,optional"`
+}
+
+type revokedInfo struct {
+	RevocationTime time.Time       `asn1:"generalized"`
+	Reason         asn1.Enumerated `asn1:"explicit,tag:0,optional"`
+}
+
+// Response status values, as defined by RFC 6960.
+var responseStatusDescriptions = map[asn1.Enumerated]string{
+	1: "malformed request",
+	2: "internal error",
+	3: "try later",
+	5: "signature required",
+	6: "unauthorized",
+}
+
+func hashAlgorithmOID(algorithm types.HashAlgorithmType) (asn1.ObjectIdentifier, error) {
+	switch algorithm {
+	case types.SHA256:
+		return oidSHA256, nil
+	case types.SHA384:
+		return oidSHA384, nil
+	case types.SHA512:
+		return oidSHA512, nil
+	default:
+		return nil, fmt.Errorf("unsupported hash algorithm %v", algorithm)
+	}
+}
+
+func hashForOID(oid asn1.ObjectIdentifier) (crypto.Hash, error) {
+	switch {
+	case oid.Equal(oidSHA256):
+		return crypto.SHA256, nil
+	case oid.Equal(oidSHA384):
+		return crypto.SHA384, nil
+	case oid.Equal(oidSHA512):
+		return crypto.SHA512, nil
+	default:
+		return 0, fmt.Errorf("unsupported hash algorithm %v", oid)
+	}
+}
+
+func newCertID(data types.OCSPRequestDataType) (certID, error) {
+	oid, err := hashAlgorithmOID(data.HashAlgorithm)
+	if err != nil {
+		return certID{}, err
+	}
+	nameHash, err := hex.DecodeString(data.IssuerNameHash)
+	if err != nil {
+		return certID{}, fmt.Errorf("invalid issuer name hash: %w", err)
+	}
+	keyHash, err := hex.DecodeString(data.IssuerKeyHash)
+	if err != nil {
+		return certID{}, fmt.Errorf("invalid issuer key hash: %w", err)
+	}
+	serialNumber, ok := new(big.Int).SetString(strings.TrimPrefix(strings.ToLower(data.SerialNumber), "0x"), 16)
+	if !ok {
+		return certID{}, fmt.Errorf("invalid serial number %v", data.SerialNumber)
+	}
+	return certID{
+		HashAlgorithm: pkix.AlgorithmIdentifier{Algorithm: oid, Parameters: asn1.NullRawValue},
+		NameHash:      nameHash,
+		IssuerKeyHash: keyHash,
+		SerialNumber:  serialNumber,
+	}, nil
+}
+
+func (id certID) matches(other certID) bool {
+	return id.HashAlgorithm.Algorithm.Equal(other.HashAlgorithm.Algorithm) &&
+		string(id.NameHash) == string(other.NameHash) &&
+		string(id.IssuerKeyHash) == string(other.IssuerKeyHash) &&
+		id.SerialNumber.Cmp(other.SerialNumber) == 0
+}
+
+// Returns whether the certificate was issued by the passed issuer, by comparing the hashes of its name and public key.
+func (id certID) issuedBy(issuer *x509.Certificate) bool {
+	hash, err := hashForOID(id.HashAlgorithm.Algorithm)
+	if err != nil {
+		return false
+	}
+	var publicKeyInfo struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if _, err = asn1.Unmarshal(issuer.RawSubjectPublicKeyInfo, &publicKeyInfo); err != nil {
+		return false
+	}
+	h := hash.New()
+	h.Write(issuer.RawSubject)
+	nameHash := h.Sum(nil)
+	h.Reset()
+	h.Write(publicKeyInfo.PublicKey.RightAlign())
+	keyHash := h.Sum(nil)
+	return bytes.Equal(nameHash, id.NameHash) && bytes.Equal(keyHash, id.IssuerKeyHash)
+}
+
+// CreateRequest builds a DER-encoded OCSP request for the certificate identified by the passed OCPP request data.
+//
+// The issuer name hash and issuer key hash are expected as hex strings, the serial number as a hex-encoded integer.
+func CreateRequest(data types.OCSPRequestDataType) ([]byte, error) {
+	id, err := newCertID(data)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(ocspRequest{TBSRequest: tbsRequest{RequestList: []request{{Cert: id}}}})
+}
+
+// ParseResponse parses a DER-encoded OCSP response and returns the status of the certificate identified by the passed OCPP request data.
+//
+// The response must be signed by the passed issuer of the certificate, or by a delegated responder whose certificate
+// was issued by the issuer for OCSP signing and is contained in the response.
+//
+// An error is returned if the response is malformed, if the responder didn't answer successfully, if the signature is invalid,
+// if the response doesn't contain the requested certificate, if its thisUpdate time lies in the future,
+// or if its nextUpdate time already passed.
+func ParseResponse(der []byte, data types.OCSPRequestDataType, issuer *x509.Certificate) (*Response, error) {
+	return parseResponse(der, data, issuer, time.Now())
+}
+
+func parseResponse(der []byte, data types.OCSPRequestDataType, issuer *x509.Certificate, now time.Time) (*Response, error) {
+	id, err := newCertID(data)
+	if err != nil {
+		return nil, err
+	}
+	if issuer == nil || !id.issuedBy(issuer) {
+		return nil, fmt.Errorf("issuer certificate doesn't match the OCSP request data")
+	}
+	var resp ocspResponse
+	rest, err := asn1.Unmarshal(der, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OCSP response: %w", err)
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("invalid OCSP response: trailing data")
+	}
+	if resp.Status != 0 {
+		description, ok := responseStatusDescriptions[resp.Status]
+		if !ok {
+			description = fmt.Sprintf("status %d", resp.Status)
+		}
+		return nil, fmt.Errorf("OCSP responder error: %v", description)
+	}
+	if !resp.Response.ResponseType.Equal(oidOCSPBasicResponse) {
+		return nil, fmt.Errorf("unsupported OCSP response type %v", resp.Response.ResponseType)
+	}
+	var basic basicResponse
+	rest, err = asn1.Unmarshal(resp.Response.Response, &basic)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OCSP basic response: %w", err)
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("invalid OCSP basic response: trailing data")
+	}
+	if err = verifySignature(&basic, issuer, now); err != nil {
+		return nil, err
+	}
+	for _, single := range basic.TBSResponseData.Responses {
+		if !id.matches(single.CertID) {
+			continue
+		}
+		if single.ThisUpdate.After(now.Add(maxClockSkew)) {
+			return nil, fmt.Errorf("OCSP response isn't valid before %v", single.ThisUpdate)
+		}
+		if !single.NextUpdate.IsZero() && single.NextUpdate.Before(now) {
+			return nil, fmt.Errorf("OCSP response expired at %v", single.NextUpdate)
+		}
+		result := &Response{
+			SerialNumber: single.CertID.SerialNumber,
+			ProducedAt:   basic.TBSResponseData.ProducedAt,
+			ThisUpdate:   single.ThisUpdate,
+			NextUpdate:   single.NextUpdate,
+			Raw:          der,
+		}
+		switch {
+		case bool(single.Good):
+			result.Status = CertificateStatusGood
+		case bool(single.Unknown):
+			result.Status = CertificateStatusUnknown
+		default:
+			result.Status = CertificateStatusRevoked
+			result.RevokedAt = single.Revoked.RevocationTime
+			result.RevocationReason = int(single.Revoked.Reason)
+		}
+		return result, nil
+	}
+	return nil, fmt.Errorf("OCSP response doesn't contain certificate with serial number %v", data.SerialNumber)
+}
+
+// Verifies the signature of a basic OCSP response. The signer is the issuer itself, if the response doesn't contain
+// any certificates. Otherwise, the signer is one of the contained certificates, which is either the issuer,
+// or a delegated responder certified by the issuer.
+func verifySignature(basic *basicResponse, issuer *x509.Certificate, now time.Time) error {
+	algorithm, ok := signatureAlgorithms[basic.SignatureAlgorithm.Algorithm.String()]
+	if !ok {
+		return fmt.Errorf("unsupported OCSP signature algorithm %v", basic.SignatureAlgorithm.Algorithm)
+	}
+	if len(basic.Certificates) == 0 {
+		return checkResponseSignature(basic, issuer, algorithm)
+	}
+	// The certificates may contain further intermediates, so each one is checked
+	var err error
+	for _, raw := range basic.Certificates {
+		var responder *x509.Certificate
+		responder, err = x509.ParseCertificate(raw.FullBytes)
+		if err != nil {
+			return fmt.Errorf("invalid OCSP responder certificate: %w", err)
+		}
+		if !responder.Equal(issuer) {
+			if err = checkDelegatedResponder(responder, issuer, now); err != nil {
+				continue
+			}
+		}
+		if err = checkResponseSignature(basic, responder, algorithm); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+func checkDelegatedResponder(responder *x509.Certificate, issuer *x509.Certificate, now time.Time) error {
+	if err := responder.CheckSignatureFrom(issuer); err != nil {
+		return fmt.Errorf("OCSP responder certificate wasn't issued by the issuer: %w", err)
+	}
+	if !hasOCSPSigningUsage(responder) {
+		return fmt.Errorf("OCSP responder certificate isn't authorized for OCSP signing")
+	}
+	if now.Before(responder.NotBefore) || now.After(responder.NotAfter) {
+		return fmt.Errorf("OCSP responder certificate isn't valid at %v", now)
+	}
+	return nil
+}
+
+func checkResponseSignature(basic *basicResponse, signer *x509.Certificate, algorithm x509.SignatureAlgorithm) error {
+	if err := signer.CheckSignature(algorithm, basic.TBSResponseData.Raw, basic.Signature.RightAlign()); err != nil {
+		return fmt.Errorf("invalid OCSP response signature: %w", err)
+	}
+	return nil
+}
+
+func hasOCSPSigningUsage(certificate *x509.Certificate) bool {
+	for _, usage := range certificate.ExtKeyUsage {
+		if usage == x509.ExtKeyUsageOCSPSigning {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,481 @@
+package ocsp
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/authorization"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/iso15118"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/types"
+)
+
+var referenceTime = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+// Test PKI, consisting of the issuer of the queried certificates and a delegated OCSP responder.
+type testPKI struct {
+	issuer       *x509.Certificate
+	issuerKey    *ecdsa.PrivateKey
+	responder    *x509.Certificate
+	responderKey *ecdsa.PrivateKey
+}
+
+func newCertificate(t *testing.T, template *x509.Certificate, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	template.NotBefore = referenceTime.Add(-24 * time.Hour)
+	template.NotAfter = time.Now().Add(24 * time.Hour)
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	certificate, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return certificate, key
+}
+
+func newTestPKI(t *testing.T) *testPKI {
+	pki := &testPKI{}
+	pki.issuer, pki.issuerKey = newCertificate(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "V2G Sub-CA"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil, nil)
+	pki.responder, pki.responderKey = newCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "V2G OCSP Responder"},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageOCSPSigning},
+	}, pki.issuer, pki.issuerKey)
+	return pki
+}
+
+// Returns the request data for a certificate issued by the passed issuer.
+func newRequestData(t *testing.T, issuer *x509.Certificate, serialNumber string) types.OCSPRequestDataType {
+	var publicKeyInfo struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	_, err := asn1.Unmarshal(issuer.RawSubjectPublicKeyInfo, &publicKeyInfo)
+	require.NoError(t, err)
+	nameHash := sha256.Sum256(issuer.RawSubject)
+	keyHash := sha256.Sum256(publicKeyInfo.PublicKey.RightAlign())
+	return types.OCSPRequestDataType{
+		HashAlgorithm:  types.SHA256,
+		IssuerNameHash: hex.EncodeToString(nameHash[:]),
+		IssuerKeyHash:  hex.EncodeToString(keyHash[:]),
+		SerialNumber:   serialNumber,
+	}
+}
+
+// Builds an OCSP response signed by the passed key. If a responder certificate is passed, it is embedded in the response.
+func createResponse(t *testing.T, id certID, status CertificateStatus, nextUpdate time.Time, key *ecdsa.PrivateKey, responder *x509.Certificate) []byte {
+	var certificates []*x509.Certificate
+	if responder != nil {
+		certificates = append(certificates, responder)
+	}
+	return createResponseWithCertificates(t, id, status, referenceTime, nextUpdate, key, certificates...)
+}
+
+// Builds an OCSP response signed by the passed key, embedding all passed certificates.
+func createResponseWithCertificates(t *testing.T, id certID, status CertificateStatus, thisUpdate time.Time, nextUpdate time.Time, key *ecdsa.PrivateKey, certificates ...*x509.Certificate) []byte {
+	single := singleResponse{
+		CertID:     id,
+		ThisUpdate: thisUpdate,
+		NextUpdate: nextUpdate,
+	}
+	switch status {
+	case CertificateStatusGood:
+		single.Good = true
+	case CertificateStatusUnknown:
+		single.Unknown = true
+	case CertificateStatusRevoked:
+		single.Revoked = revokedInfo{RevocationTime: referenceTime.Add(-time.Hour), Reason: 1}
+	}
+	tbs := responseData{
+		RawResponderID: asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 2, IsCompound: true, Bytes: []byte{0x04, 0x01, 0x00}},
+		ProducedAt:     referenceTime,
+		Responses:      []singleResponse{single},
+	}
+	tbsDER, err := asn1.Marshal(tbs)
+	require.NoError(t, err)
+	tbs.Raw = tbsDER
+	digest := sha256.Sum256(tbsDER)
+	signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+	response := basicResponse{
+		TBSResponseData:    tbs,
+		SignatureAlgorithm: pkix.AlgorithmIdentifier{Algorithm: asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}},
+		Signature:          asn1.BitString{Bytes: signature, BitLength: 8 * len(signature)},
+	}
+	for _, certificate := range certificates {
+		response.Certificates = append(response.Certificates, asn1.RawValue{FullBytes: certificate.Raw})
+	}
+	basic, err := asn1.Marshal(response)
+	require.NoError(t, err)
+	der, err := asn1.Marshal(ocspResponse{Response: responseBytes{ResponseType: oidOCSPBasicResponse, Response: basic}})
+	require.NoError(t, err)
+	return der
+}
+
+// Local stand-in for an OCSP responder, answering with the configured status for each serial number.
+// Responses are signed by the delegated responder of the test PKI, and valid until the time returned by nextUpdate.
+func newResponder(t *testing.T, pki *testPKI, statuses map[int64]CertificateStatus, nextUpdate func() time.Time, requests *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/ocsp-request", r.Header.Get("Content-Type"))
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		var req ocspRequest
+		_, err = asn1.Unmarshal(body, &req)
+		if !assert.NoError(t, err) || !assert.Len(t, req.TBSRequest.RequestList, 1) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		id := req.TBSRequest.RequestList[0].Cert
+		status, ok := statuses[id.SerialNumber.Int64()]
+		if !ok {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/ocsp-response")
+		_, _ = w.Write(createResponse(t, id, status, nextUpdate(), pki.responderKey, pki.responder))
+	}))
+}
+
+func nextUpdateAt(nextUpdate time.Time) func() time.Time {
+	return func() time.Time { return nextUpdate }
+}
+
+func TestCreateRequest(t *testing.T) {
+	pki := newTestPKI(t)
+	der, err := CreateRequest(newRequestData(t, pki.issuer, "1a2B"))
+	require.NoError(t, err)
+	var req ocspRequest
+	rest, err := asn1.Unmarshal(der, &req)
+	require.NoError(t, err)
+	assert.Empty(t, rest)
+	require.Len(t, req.TBSRequest.RequestList, 1)
+	id := req.TBSRequest.RequestList[0].Cert
+	assert.True(t, oidSHA256.Equal(id.HashAlgorithm.Algorithm))
+	assert.Equal(t, big.NewInt(0x1a2b), id.SerialNumber)
+	assert.Len(t, id.NameHash, 32)
+	assert.Len(t, id.IssuerKeyHash, 32)
+	assert.True(t, id.issuedBy(pki.issuer))
+	assert.False(t, id.issuedBy(pki.responder))
+	// Invalid request data
+	data := newRequestData(t, pki.issuer, "1a2b")
+	data.HashAlgorithm = "MD5"
+	_, err = CreateRequest(data)
+	assert.Error(t, err)
+	data = newRequestData(t, pki.issuer, "1a2b")
+	data.IssuerKeyHash = "xyz"
+	_, err = CreateRequest(data)
+	assert.Error(t, err)
+	_, err = CreateRequest(newRequestData(t, pki.issuer, "serial"))
+	assert.Error(t, err)
+}
+
+func TestParseResponse(t *testing.T) {
+	pki := newTestPKI(t)
+	data := newRequestData(t, pki.issuer, "1a2b")
+	id, err := newCertID(data)
+	require.NoError(t, err)
+	nextUpdate := referenceTime.Add(time.Hour)
+	response, err := parseResponse(createResponse(t, id, CertificateStatusRevoked, nextUpdate, pki.issuerKey, nil), data, pki.issuer, referenceTime)
+	require.NoError(t, err)
+	assert.Equal(t, CertificateStatusRevoked, response.Status)
+	assert.True(t, referenceTime.Equal(response.ProducedAt))
+	assert.True(t, referenceTime.Equal(response.ThisUpdate))
+	assert.True(t, nextUpdate.Equal(response.NextUpdate))
+	assert.True(t, referenceTime.Add(-time.Hour).Equal(response.RevokedAt))
+	assert.Equal(t, 1, response.RevocationReason)
+	// Response signed by a delegated responder
+	response, err = parseResponse(createResponse(t, id, CertificateStatusGood, nextUpdate, pki.responderKey, pki.responder), data, pki.issuer, referenceTime)
+	require.NoError(t, err)
+	assert.Equal(t, CertificateStatusGood, response.Status)
+	// Response without nextUpdate
+	_, err = ParseResponse(createResponse(t, id, CertificateStatusGood, time.Time{}, pki.issuerKey, nil), data, pki.issuer)
+	require.NoError(t, err)
+	// Response for another certificate
+	_, err = parseResponse(createResponse(t, id, CertificateStatusGood, nextUpdate, pki.issuerKey, nil), newRequestData(t, pki.issuer, "1a2c"), pki.issuer, referenceTime)
+	assert.Error(t, err)
+	// Unsuccessful response
+	der, err := asn1.Marshal(ocspResponse{Status: 6})
+	require.NoError(t, err)
+	_, err = ParseResponse(der, data, pki.issuer)
+	assert.EqualError(t, err, "OCSP responder error: unauthorized")
+	_, err = ParseResponse([]byte{0x01, 0x02}, data, pki.issuer)
+	assert.Error(t, err)
+}
+
+func TestParseResponseVerification(t *testing.T) {
+	pki := newTestPKI(t)
+	data := newRequestData(t, pki.issuer, "1a2b")
+	id, err := newCertID(data)
+	require.NoError(t, err)
+	nextUpdate := referenceTime.Add(time.Hour)
+	// Missing or wrong issuer
+	der := createResponse(t, id, CertificateStatusGood, nextUpdate, pki.issuerKey, nil)
+	_, err = parseResponse(der, data, nil, referenceTime)
+	assert.Error(t, err)
+	_, err = parseResponse(der, data, pki.responder, referenceTime)
+	assert.Error(t, err)
+	// Signed by an unrelated key
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, err = parseResponse(createResponse(t, id, CertificateStatusGood, nextUpdate, otherKey, nil), data, pki.issuer, referenceTime)
+	assert.Error(t, err)
+	// Embedded responder certificate not matching the signing key
+	_, err = parseResponse(createResponse(t, id, CertificateStatusGood, nextUpdate, pki.issuerKey, pki.responder), data, pki.issuer, referenceTime)
+	assert.Error(t, err)
+	// Delegated responder without OCSP signing usage
+	responder, responderKey := newCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "V2G Leaf"},
+	}, pki.issuer, pki.issuerKey)
+	_, err = parseResponse(createResponse(t, id, CertificateStatusGood, nextUpdate, responderKey, responder), data, pki.issuer, referenceTime)
+	assert.Error(t, err)
+	// Delegated responder issued by another CA
+	other := newTestPKI(t)
+	_, err = parseResponse(createResponse(t, id, CertificateStatusGood, nextUpdate, other.responderKey, other.responder), data, pki.issuer, referenceTime)
+	assert.Error(t, err)
+	// Delegated responder certificate not yet valid
+	_, err = parseResponse(createResponse(t, id, CertificateStatusGood, nextUpdate, pki.responderKey, pki.responder), data, pki.issuer, referenceTime.Add(-48*time.Hour))
+	assert.Error(t, err)
+	// Expired response
+	_, err = parseResponse(createResponse(t, id, CertificateStatusGood, nextUpdate, pki.issuerKey, nil), data, pki.issuer, nextUpdate.Add(time.Second))
+	assert.Error(t, err)
+	// Response from the future, tolerating a small clock skew
+	thisUpdate := referenceTime.Add(time.Hour)
+	der = createResponseWithCertificates(t, id, CertificateStatusGood, thisUpdate, thisUpdate.Add(time.Hour), pki.issuerKey)
+	_, err = parseResponse(der, data, pki.issuer, referenceTime)
+	assert.EqualError(t, err, fmt.Sprintf("OCSP response isn't valid before %v", thisUpdate))
+	_, err = parseResponse(der, data, pki.issuer, thisUpdate.Add(-time.Minute))
+	require.NoError(t, err)
+}
+
+func TestParseResponseCertificates(t *testing.T) {
+	pki := newTestPKI(t)
+	other := newTestPKI(t)
+	data := newRequestData(t, pki.issuer, "1a2b")
+	id, err := newCertID(data)
+	require.NoError(t, err)
+	nextUpdate := referenceTime.Add(time.Hour)
+	// Delegated responder isn't the first certificate
+	der := createResponseWithCertificates(t, id, CertificateStatusGood, referenceTime, nextUpdate, pki.responderKey, other.responder, pki.issuer, pki.responder)
+	response, err := parseResponse(der, data, pki.issuer, referenceTime)
+	require.NoError(t, err)
+	assert.Equal(t, CertificateStatusGood, response.Status)
+	// Issuer contained in the certificates
+	der = createResponseWithCertificates(t, id, CertificateStatusGood, referenceTime, nextUpdate, pki.issuerKey, pki.responder, pki.issuer)
+	_, err = parseResponse(der, data, pki.issuer, referenceTime)
+	require.NoError(t, err)
+	// Only wrong responders contained in the certificates
+	der = createResponseWithCertificates(t, id, CertificateStatusGood, referenceTime, nextUpdate, pki.responderKey, other.responder, other.issuer)
+	_, err = parseResponse(der, data, pki.issuer, referenceTime)
+	assert.Error(t, err)
+	der = createResponseWithCertificates(t, id, CertificateStatusGood, referenceTime, nextUpdate, other.responderKey, pki.responder, other.responder)
+	_, err = parseResponse(der, data, pki.issuer, referenceTime)
+	assert.Error(t, err)
+}
+
+func TestParseResponseMalformed(t *testing.T) {
+	pki := newTestPKI(t)
+	data := newRequestData(t, pki.issuer, "1a2b")
+	id, err := newCertID(data)
+	require.NoError(t, err)
+	der := createResponse(t, id, CertificateStatusGood, referenceTime.Add(time.Hour), pki.responderKey, pki.responder)
+	// Truncated response and trailing data
+	_, err = parseResponse(der[:len(der)/2], data, pki.issuer, referenceTime)
+	assert.Error(t, err)
+	_, err = parseResponse(append(append([]byte{}, der...), 0x00), data, pki.issuer, referenceTime)
+	assert.EqualError(t, err, "invalid OCSP response: trailing data")
+	_, err = parseResponse(nil, data, pki.issuer, referenceTime)
+	assert.Error(t, err)
+	// Unsupported response type
+	malformed, err := asn1.Marshal(ocspResponse{Response: responseBytes{ResponseType: asn1.ObjectIdentifier{1, 2, 3}, Response: []byte{0x05, 0x00}}})
+	require.NoError(t, err)
+	_, err = parseResponse(malformed, data, pki.issuer, referenceTime)
+	assert.Error(t, err)
+	// Malformed basic response, with trailing data
+	malformed, err = asn1.Marshal(ocspResponse{Response: responseBytes{ResponseType: oidOCSPBasicResponse, Response: []byte{0x30, 0x03, 0x02, 0x01}}})
+	require.NoError(t, err)
+	_, err = parseResponse(malformed, data, pki.issuer, referenceTime)
+	assert.Error(t, err)
+	var resp ocspResponse
+	_, err = asn1.Unmarshal(der, &resp)
+	require.NoError(t, err)
+	malformed, err = asn1.Marshal(ocspResponse{Response: responseBytes{ResponseType: oidOCSPBasicResponse, Response: append(resp.Response.Response, 0x05, 0x00)}})
+	require.NoError(t, err)
+	_, err = parseResponse(malformed, data, pki.issuer, referenceTime)
+	assert.EqualError(t, err, "invalid OCSP basic response: trailing data")
+	// Malformed responder certificate
+	var basic basicResponse
+	_, err = asn1.Unmarshal(resp.Response.Response, &basic)
+	require.NoError(t, err)
+	basic.Certificates = []asn1.RawValue{{Class: asn1.ClassUniversal, Tag: asn1.TagSequence, IsCompound: true, Bytes: []byte{0x02, 0x01, 0x01}}}
+	basicDER, err := asn1.Marshal(basic)
+	require.NoError(t, err)
+	malformed, err = asn1.Marshal(ocspResponse{Response: responseBytes{ResponseType: oidOCSPBasicResponse, Response: basicDER}})
+	require.NoError(t, err)
+	_, err = parseResponse(malformed, data, pki.issuer, referenceTime)
+	assert.Error(t, err)
+}
+
+func TestQueryCache(t *testing.T) {
+	pki := newTestPKI(t)
+	var requests int32
+	nextUpdate := referenceTime.Add(time.Hour)
+	responder := newResponder(t, pki, map[int64]CertificateStatus{0x01: CertificateStatusGood}, func() time.Time { return nextUpdate }, &requests)
+	defer responder.Close()
+	client := NewClient(WithResponderURL(responder.URL), WithIssuers(pki.issuer))
+	now := referenceTime
+	client.now = func() time.Time { return now }
+	data := newRequestData(t, pki.issuer, "01")
+	response, err := client.Query(data)
+	require.NoError(t, err)
+	assert.Equal(t, CertificateStatusGood, response.Status)
+	// Cached until nextUpdate
+	_, err = client.Query(data)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	// Expired responses are rejected
+	now = referenceTime.Add(2 * time.Hour)
+	_, err = client.Query(data)
+	assert.Error(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+	nextUpdate = referenceTime.Add(3 * time.Hour)
+	_, err = client.Query(data)
+	require.NoError(t, err)
+	_, err = client.Query(data)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+}
+
+func TestQueryResponderURL(t *testing.T) {
+	pki := newTestPKI(t)
+	var requests int32
+	responder := newResponder(t, pki, map[int64]CertificateStatus{0x01: CertificateStatusGood}, nextUpdateAt(time.Time{}), &requests)
+	defer responder.Close()
+	client := NewClient(WithIssuers(pki.issuer))
+	data := newRequestData(t, pki.issuer, "01")
+	_, err := client.Query(data)
+	assert.EqualError(t, err, "missing OCSP responder URL")
+	// Responder URL contained in request data isn't trusted by default
+	data.ResponderURL = responder.URL
+	_, err = client.Query(data)
+	assert.EqualError(t, err, fmt.Sprintf("untrusted OCSP responder URL %v", responder.URL))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&requests))
+	// Allowed responder URL
+	client = NewClient(WithIssuers(pki.issuer), WithAllowedResponderURLs("http://ocsp.example.com", responder.URL))
+	_, err = client.Query(data)
+	require.NoError(t, err)
+	// Responses without nextUpdate aren't cached
+	_, err = client.Query(data)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+	// Responder URL listed by the issuer
+	pki.issuer.OCSPServer = []string{responder.URL}
+	client = NewClient(WithIssuers(pki.issuer))
+	_, err = client.Query(data)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+}
+
+func TestQueryUnknownIssuer(t *testing.T) {
+	pki := newTestPKI(t)
+	var requests int32
+	responder := newResponder(t, pki, map[int64]CertificateStatus{0x01: CertificateStatusGood}, nextUpdateAt(time.Time{}), &requests)
+	defer responder.Close()
+	client := NewClient(WithResponderURL(responder.URL), WithIssuers(newTestPKI(t).issuer))
+	_, err := client.Query(newRequestData(t, pki.issuer, "01"))
+	assert.EqualError(t, err, "unknown issuer of certificate with serial number 01")
+	assert.Equal(t, int32(0), atomic.LoadInt32(&requests))
+}
+
+func TestGetCertificateStatus(t *testing.T) {
+	pki := newTestPKI(t)
+	var requests int32
+	responder := newResponder(t, pki, map[int64]CertificateStatus{0x01: CertificateStatusRevoked}, nextUpdateAt(referenceTime.Add(time.Hour)), &requests)
+	defer responder.Close()
+	client := NewClient(WithResponderURL(responder.URL), WithIssuers(pki.issuer))
+	client.now = func() time.Time { return referenceTime }
+	response := client.GetCertificateStatus(iso15118.NewGetCertificateStatusRequest(newRequestData(t, pki.issuer, "01")))
+	assert.Equal(t, types.GenericStatusAccepted, response.Status)
+	assert.Nil(t, response.StatusInfo)
+	der, err := base64.StdEncoding.DecodeString(response.OcspResult)
+	require.NoError(t, err)
+	parsed, err := parseResponse(der, newRequestData(t, pki.issuer, "01"), pki.issuer, referenceTime)
+	require.NoError(t, err)
+	assert.Equal(t, CertificateStatusRevoked, parsed.Status)
+	require.NoError(t, types.Validate.Struct(response))
+	// Responder error
+	response = client.GetCertificateStatus(iso15118.NewGetCertificateStatusRequest(newRequestData(t, pki.issuer, "02")))
+	assert.Equal(t, types.GenericStatusRejected, response.Status)
+	assert.Empty(t, response.OcspResult)
+	require.NotNil(t, response.StatusInfo)
+	assert.Equal(t, reasonResponderErr, response.StatusInfo.ReasonCode)
+	assert.Equal(t, "OCSP responder returned HTTP status 500", response.StatusInfo.AdditionalInfo)
+	require.NoError(t, types.Validate.Struct(response))
+}
+
+func TestAuthorizeCertificateStatus(t *testing.T) {
+	pki := newTestPKI(t)
+	var requests int32
+	responder := newResponder(t, pki, map[int64]CertificateStatus{
+		0x01: CertificateStatusGood,
+		0x02: CertificateStatusGood,
+		0x03: CertificateStatusUnknown,
+		0x04: CertificateStatusRevoked,
+	}, nextUpdateAt(referenceTime.Add(time.Hour)), &requests)
+	defer responder.Close()
+	client := NewClient(WithResponderURL(responder.URL), WithIssuers(pki.issuer))
+	client.now = func() time.Time { return referenceTime }
+	testTable := []struct {
+		serialNumbers  []string
+		expectedStatus authorization.AuthorizeCertificateStatus
+	}{
+		{nil, authorization.CertificateStatusNoCertificateAvailable},
+		{[]string{"01", "02"}, authorization.CertificateStatusAccepted},
+		{[]string{"01", "03"}, authorization.CertificateStatusCertChainError},
+		{[]string{"03", "04"}, authorization.CertificateStatusCertificateRevoked},
+	}
+	for _, tc := range testTable {
+		var hashData []types.OCSPRequestDataType
+		for _, serialNumber := range tc.serialNumbers {
+			hashData = append(hashData, newRequestData(t, pki.issuer, serialNumber))
+		}
+		status, err := client.AuthorizeCertificateStatus(hashData)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expectedStatus, status, tc.serialNumbers)
+	}
+	// Responder error
+	_, err := client.AuthorizeCertificateStatus([]types.OCSPRequestDataType{newRequestData(t, pki.issuer, "01"), newRequestData(t, pki.issuer, "05")})
+	assert.Error(t, err)
+	// Expired response
+	client = NewClient(WithResponderURL(responder.URL), WithIssuers(pki.issuer))
+	client.now = func() time.Time { return referenceTime.Add(2 * time.Hour) }
+	_, err = client.AuthorizeCertificateStatus([]types.OCSPRequestDataType{newRequestData(t, pki.issuer, "01")})
+	assert.Error(t, err)
+}
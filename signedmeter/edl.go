@@ -0,0 +1,82 @@
+package signedmeter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	edlDataLength      = 320
+	edlSignatureLength = 48
+	edlContractIDStart = 41
+	edlContractIDEnd   = 169
+)
+
+// Units of EDL readings, as defined by DLMS/COSEM.
+var edlUnits = map[byte]string{
+	27: "W",
+	30: "Wh",
+	32: "varh",
+	33: "A",
+	35: "V",
+}
+
+// ParseEDL parses a binary EDL signature record, as produced by EDL40 meters with SML interface.
+//
+// The record consists of the 320 bytes signed by the meter, followed by the signature.
+// The signed data is laid out as reconstructed by the S.A.F.E. transparency software,
+// with big-endian fields and zero padding up to 320 bytes:
+//
+//	server ID           10 bytes
+//	timestamp            4 bytes, seconds since the Unix epoch
+//	status               1 byte
+//	seconds index        4 bytes
+//	pagination counter   4 bytes
+//	OBIS code            6 bytes
+//	unit                 1 byte, DLMS unit code
+//	scaler               1 byte, signed power of ten applied to the value
+//	value                8 bytes, signed
+//	logbook              2 bytes
+//	contract ID        128 bytes, zero padded
+//	contract timestamp   4 bytes, seconds since the Unix epoch
+//	padding            147 bytes
+//
+// EDL40 meters sign with ECDSA-secp192r1-SHA256, the 48-byte signature being the raw concatenation of r and s.
+func ParseEDL(data []byte) (*SignedData, error) {
+	if len(data) != edlDataLength+edlSignatureLength {
+		return nil, fmt.Errorf("invalid EDL data: unexpected length %d", len(data))
+	}
+	record := data[:edlDataLength]
+	obis := record[23:29]
+	unit, ok := edlUnits[record[29]]
+	if !ok {
+		unit = fmt.Sprintf("%d", record[29])
+	}
+	scaler := int8(record[30])
+	value := int64(binary.BigEndian.Uint64(record[31:39]))
+	contractID := bytes.TrimRight(record[edlContractIDStart:edlContractIDEnd], "\x00")
+	return &SignedData{
+		Format:             FormatEDL,
+		MeterID:            strings.ToUpper(fmt.Sprintf("%x", record[0:10])),
+		Pagination:         Pagination{Counter: uint64(binary.BigEndian.Uint32(record[19:23]))},
+		IdentificationData: string(contractID),
+		Logbook:            binary.BigEndian.Uint16(record[39:41]),
+		Readings: []Reading{
+			{
+				Timestamp:  time.Unix(int64(binary.BigEndian.Uint32(record[10:14])), 0).UTC(),
+				Value:      float64(value) * math.Pow10(int(scaler)),
+				Identifier: fmt.Sprintf("%d-%d:%d.%d.%d*%d", obis[0], obis[1], obis[2], obis[3], obis[4], obis[5]),
+				Unit:       unit,
+				Status:     fmt.Sprintf("%02X", record[14]),
+			},
+		},
+		SignatureAlgorithm: AlgorithmECDSAP192SHA256,
+		Payload:            record,
+		Signature:          data[edlDataLength:],
+		rawSignature:       true,
+	}, nil
+}
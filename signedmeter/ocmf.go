@@ -0,0 +1,167 @@
+package signedmeter
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ocmfHeader         = "OCMF|"
+	ocmfTimeLayout     = "2006-01-02T15:04:05-0700"
+	ocmfDefaultMime    = "application/x-der"
+	ocmfEncodingHex    = "hex"
+	ocmfEncodingBase64 = "base64"
+)
+
+type ocmfPayload struct {
+	FormatVersion             string        `json:"FV"`
+	GatewayIdentification     string        `json:"GI"`
+	GatewaySerial             string        `json:"GS"`
+	GatewayVersion            string        `json:"GV"`
+	Pagination                string        `json:"PG"`
+	MeterVendor               string        `json:"MV"`
+	MeterModel                string        `json:"MM"`
+	MeterSerial               string        `json:"MS"`
+	MeterFirmware             string        `json:"MF"`
+	IdentificationStatus      bool          `json:"IS"`
+	IdentificationLevel       string        `json:"IL"`
+	IdentificationFlags       []string      `json:"IF"`
+	IdentificationType        string        `json:"IT"`
+	IdentificationData        string        `json:"ID"`
+	ChargePointIdentifierType string        `json:"CT"`
+	ChargePointIdentifier     string        `json:"CI"`
+	Readings                  []ocmfReading `json:"RD"`
+}
+
+type ocmfReading struct {
+	Time       string  `json:"TM"`
+	Type       string  `json:"TX"`
+	Value      float64 `json:"RV"`
+	Identifier string  `json:"RI"`
+	Unit       string  `json:"RU"`
+	ErrorFlags string  `json:"EF"`
+	Status     string  `json:"ST"`
+}
+
+type ocmfSignature struct {
+	Algorithm string `json:"SA"`
+	Encoding  string `json:"SE"`
+	MimeType  string `json:"SM"`
+	Data      string `json:"SD"`
+}
+
+// ParseOCMF parses a signed meter value in the Open Charge Metering Format, i.e. OCMF|{payload}|{signature}.
+//
+// The signature is expected to be DER encoded, as hex (default) or base64.
+// If no signature algorithm is specified, ECDSA-secp192r1-SHA256 is assumed, as defined by OCMF.
+func ParseOCMF(data string) (*SignedData, error) {
+	if !strings.HasPrefix(data, ocmfHeader) {
+		return nil, fmt.Errorf("invalid OCMF data: missing %v header", strings.TrimSuffix(ocmfHeader, "|"))
+	}
+	body := data[len(ocmfHeader):]
+	separator := strings.LastIndex(body, "|")
+	if separator < 0 {
+		return nil, fmt.Errorf("invalid OCMF data: missing signature section")
+	}
+	rawPayload := body[:separator]
+	var payload ocmfPayload
+	if err := json.Unmarshal([]byte(rawPayload), &payload); err != nil {
+		return nil, fmt.Errorf("invalid OCMF payload: %w", err)
+	}
+	var signature ocmfSignature
+	if err := json.Unmarshal([]byte(body[separator+1:]), &signature); err != nil {
+		return nil, fmt.Errorf("invalid OCMF signature: %w", err)
+	}
+	signedData := &SignedData{
+		Format:             FormatOCMF,
+		MeterID:            payload.MeterSerial,
+		Manufacturer:       payload.MeterVendor,
+		Model:              payload.MeterModel,
+		FirmwareVersion:    payload.MeterFirmware,
+		IdentificationType: payload.IdentificationType,
+		IdentificationData: payload.IdentificationData,
+		ChargePointID:      payload.ChargePointIdentifier,
+		SignatureAlgorithm: signature.Algorithm,
+		Payload:            []byte(rawPayload),
+	}
+	if signedData.SignatureAlgorithm == "" {
+		signedData.SignatureAlgorithm = AlgorithmECDSAP192SHA256
+	}
+	if payload.Pagination != "" {
+		pagination, err := parseOCMFPagination(payload.Pagination)
+		if err != nil {
+			return nil, err
+		}
+		signedData.Pagination = pagination
+	}
+	for i, r := range payload.Readings {
+		timestamp, timeStatus, err := parseOCMFTime(r.Time)
+		if err != nil {
+			return nil, fmt.Errorf("invalid OCMF reading %d: %w", i, err)
+		}
+		signedData.Readings = append(signedData.Readings, Reading{
+			Timestamp:  timestamp,
+			TimeStatus: timeStatus,
+			Type:       r.Type,
+			Value:      r.Value,
+			Identifier: r.Identifier,
+			Unit:       r.Unit,
+			Status:     r.Status,
+			ErrorFlags: r.ErrorFlags,
+		})
+	}
+	if signature.MimeType != "" && signature.MimeType != ocmfDefaultMime {
+		return nil, fmt.Errorf("unsupported OCMF signature mime type %v", signature.MimeType)
+	}
+	switch signature.Encoding {
+	case "", ocmfEncodingHex:
+		sig, err := hex.DecodeString(signature.Data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid OCMF signature data: %w", err)
+		}
+		signedData.Signature = sig
+	case ocmfEncodingBase64:
+		sig, err := base64.StdEncoding.DecodeString(signature.Data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid OCMF signature data: %w", err)
+		}
+		signedData.Signature = sig
+	default:
+		return nil, fmt.Errorf("unsupported OCMF signature encoding %v", signature.Encoding)
+	}
+	return signedData, nil
+}
+
+// Parses a pagination string, e.g. T12, into its context and counter.
+func parseOCMFPagination(pagination string) (Pagination, error) {
+	if len(pagination) < 2 {
+		return Pagination{}, fmt.Errorf("invalid OCMF pagination %v", pagination)
+	}
+	counter, err := strconv.ParseUint(pagination[1:], 10, 64)
+	if err != nil {
+		return Pagination{}, fmt.Errorf("invalid OCMF pagination %v", pagination)
+	}
+	return Pagination{Context: pagination[:1], Counter: counter}, nil
+}
+
+// Parses an OCMF timestamp, e.g. 2018-07-24T13:22:04,000+0200 S, into the time and the time status.
+func parseOCMFTime(value string) (time.Time, string, error) {
+	parts := strings.Fields(value)
+	if len(parts) == 0 || len(parts) > 2 {
+		return time.Time{}, "", fmt.Errorf("invalid OCMF time %v", value)
+	}
+	timestamp, err := time.Parse(ocmfTimeLayout, strings.Replace(parts[0], ",", ".", 1))
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("invalid OCMF time %v", value)
+	}
+	status := ""
+	if len(parts) == 2 {
+		status = parts[1]
+	}
+	return timestamp, status, nil
+}
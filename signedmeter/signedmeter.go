@@ -0,0 +1,431 @@
+// Contains parsers and signature verification for signed meter values, as required by the German calibration law (Eichrecht).
+//
+// Signed meter values are sent by charging stations in the OCPP 1.6 SampledValue (with format SignedData),
+// or in the OCPP 2.0.1 SignedMeterValue type. Two data formats are supported:
+//
+//   - OCMF (Open Charge Metering Format): a text format composed of a header, a JSON payload and a JSON signature,
+//     separated by a pipe, e.g. `OCMF|{"MS":"0815",...,"RD":[...]}|{"SA":"ECDSA-secp256r1-SHA256","SD":"3045..."}`.
+//   - EDL: a binary signature record, as produced by EDL40 meters with SML interface (see ParseEDL for the expected layout).
+//
+// Signatures are verified using ECDSA with SHA-256, on the secp192r1 (P-192), secp256r1 (P-256) and secp384r1 (P-384) curves.
+// The public key may be passed along with the meter value, or pinned for a specific meter via a Verifier:
+//
+//	verifier := signedmeter.NewVerifier(signedmeter.RequirePinnedKeys())
+//	verifier.PinPublicKey("0815", meterPublicKey)
+//	handler.OnTransactionEvent = func(chargingStationID string, request *transactions.TransactionEventRequest) (*transactions.TransactionEventResponse, error) {
+//		for _, meterValue := range request.MeterValue {
+//			for _, sampledValue := range meterValue.SampledValue {
+//				if sampledValue.SignedMeterValue == nil {
+//					continue
+//				}
+//				signedData, err := verifier.VerifySignedMeterValue(*sampledValue.SignedMeterValue)
+//				// Check signedData.MeterID against the meter of the charging station, then store signedData or reject the reading
+//			}
+//		}
+//		...
+//	}
+//
+// The meter ID is taken from the signed data itself. A valid signature only proves that the data was signed
+// by the holder of the key, so callers must check that the MeterID matches the meter installed in the charging station.
+// Keys sent along with meter values are chosen by the charging station: use RequirePinnedKeys,
+// unless the keys are authenticated by other means.
+package signedmeter
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	types16 "github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
+	types2 "github.com/lorenzodonini/ocpp-go/ocpp2.0.1/types"
+)
+
+// Format is the data format of a signed meter value.
+type Format string
+
+const (
+	FormatOCMF Format = "OCMF"
+	FormatEDL  Format = "EDL"
+)
+
+// Supported signature algorithms, as named by the OCMF specification.
+const (
+	AlgorithmECDSAP192SHA256 = "ECDSA-secp192r1-SHA256"
+	AlgorithmECDSAP256SHA256 = "ECDSA-secp256r1-SHA256"
+	AlgorithmECDSAP384SHA256 = "ECDSA-secp384r1-SHA256"
+)
+
+var (
+	oidPublicKeyECDSA = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidNamedCurveP192 = asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 1}
+)
+
+var p192 = &elliptic.CurveParams{
+	P:       fromHex("fffffffffffffffffffffffffffffffeffffffffffffffff"),
+	N:       fromHex("ffffffffffffffffffffffff99def836146bc9b1b4d22831"),
+	B:       fromHex("64210519e59c80e70fa7e9ab72243049feb8deecc146b9b1"),
+	Gx:      fromHex("188da80eb03090f67cbf20eb43a18800f4ff0afd82ff1012"),
+	Gy:      fromHex("07192b95ffc8da78631011ed6b24cdd573f977a11e794811"),
+	BitSize: 192,
+	Name:    "P-192",
+}
+
+// P192 returns the NIST P-192 (secp192r1) curve.
+//
+// The curve is not provided by crypto/elliptic, but it is the default curve of OCMF and the curve used by EDL40 meters.
+// It is backed by the generic, non constant-time implementation of elliptic.CurveParams,
+// which is fine for verifying signatures.
+func P192() elliptic.Curve {
+	return p192
+}
+
+var (
+	// ErrInvalidSignature is returned when the signature doesn't match the signed data.
+	ErrInvalidSignature = errors.New("invalid signature")
+	// ErrMissingPublicKey is returned when neither a public key was provided, nor a key was pinned for the meter.
+	ErrMissingPublicKey = errors.New("missing public key")
+	// ErrPublicKeyMismatch is returned when the provided public key differs from the key pinned for the meter.
+	ErrPublicKeyMismatch = errors.New("public key doesn't match pinned key")
+	// ErrPublicKeyNotPinned is returned by a verifier requiring pinned keys, when no key was pinned for the meter.
+	ErrPublicKeyNotPinned = errors.New("no public key pinned for meter")
+	// ErrUnsupportedAlgorithm is returned for signature algorithms which cannot be verified.
+	ErrUnsupportedAlgorithm = errors.New("unsupported signature algorithm")
+)
+
+// Pagination identifies a signed data set within the sequence of data sets produced by a meter.
+// The context is "T" for transaction-related data and "F" for fiscal data in OCMF, and empty for EDL.
+type Pagination struct {
+	Context string
+	Counter uint64
+}
+
+func (p Pagination) String() string {
+	return fmt.Sprintf("%v%v", p.Context, p.Counter)
+}
+
+// Reading is a single meter reading contained in a signed data set.
+type Reading struct {
+	Timestamp  time.Time
+	TimeStatus string  // Synchronization state of the meter clock (OCMF only): U (unknown), I (informative), S (synchronized), R (relative).
+	Type       string  // Type of the reading within the transaction (OCMF only), e.g. B (begin), E (end), C (charging), T (tariff change).
+	Value      float64 // Reading value, scaled to the unit.
+	Identifier string  // OBIS code of the reading, e.g. 1-b:1.8.0.
+	Unit       string
+	Status     string // Meter status, e.g. G (good) for OCMF, or the hex-encoded status byte for EDL.
+	ErrorFlags string // Meter error flags (OCMF only).
+}
+
+// SignedData is a parsed signed meter value.
+//
+// The Payload contains the exact bytes covered by the signature,
+// so the data may be stored and verified again at a later point in time.
+type SignedData struct {
+	Format             Format
+	MeterID            string // Serial number of the meter (OCMF), or the hex-encoded server ID (EDL).
+	Manufacturer       string
+	Model              string
+	FirmwareVersion    string
+	Pagination         Pagination
+	IdentificationType string // Type of the identification used to authorize the transaction, e.g. ISO14443 or EMAID.
+	IdentificationData string
+	ChargePointID      string
+	Readings           []Reading
+	Logbook            uint16 // Logbook entry of the meter (EDL only).
+	SignatureAlgorithm string
+	Payload            []byte
+	Signature          []byte
+	rawSignature       bool
+}
+
+// Verify checks the signature of the signed data against the passed public key.
+func (s *SignedData) Verify(publicKey *ecdsa.PublicKey) error {
+	if publicKey == nil {
+		return ErrMissingPublicKey
+	}
+	curve, err := curveForAlgorithm(s.SignatureAlgorithm)
+	if err != nil {
+		return err
+	}
+	if publicKey.Curve != curve {
+		return fmt.Errorf("public key curve %v doesn't match signature algorithm %v", publicKey.Curve.Params().Name, s.SignatureAlgorithm)
+	}
+	digest := sha256.Sum256(s.Payload)
+	if s.rawSignature {
+		size := (curve.Params().BitSize + 7) / 8
+		if len(s.Signature) != 2*size {
+			return ErrInvalidSignature
+		}
+		r := new(big.Int).SetBytes(s.Signature[:size])
+		sig := new(big.Int).SetBytes(s.Signature[size:])
+		if !ecdsa.Verify(publicKey, digest[:], r, sig) {
+			return ErrInvalidSignature
+		}
+		return nil
+	}
+	if !ecdsa.VerifyASN1(publicKey, digest[:], s.Signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func curveForAlgorithm(algorithm string) (elliptic.Curve, error) {
+	switch algorithm {
+	case AlgorithmECDSAP192SHA256:
+		return p192, nil
+	case AlgorithmECDSAP256SHA256:
+		return elliptic.P256(), nil
+	case AlgorithmECDSAP384SHA256:
+		return elliptic.P384(), nil
+	default:
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedAlgorithm, algorithm)
+	}
+}
+
+// Parse parses a signed meter value, detecting its format automatically.
+//
+// OCMF data is expected as plain text, while EDL data may be hex or base64 encoded.
+func Parse(data string) (*SignedData, error) {
+	data = strings.TrimSpace(data)
+	if strings.HasPrefix(data, ocmfHeader) {
+		return ParseOCMF(data)
+	}
+	raw, err := decodeBinary(data)
+	if err != nil {
+		return nil, fmt.Errorf("unknown signed meter value format")
+	}
+	return parseBytes(raw)
+}
+
+func parseBytes(raw []byte) (*SignedData, error) {
+	text := strings.TrimSpace(string(raw))
+	if strings.HasPrefix(text, ocmfHeader) {
+		return ParseOCMF(text)
+	}
+	if isHex(text) {
+		if decoded, err := hex.DecodeString(text); err == nil {
+			raw = decoded
+		}
+	}
+	return ParseEDL(raw)
+}
+
+// ParsePublicKey parses an ECDSA public key in DER-encoded SubjectPublicKeyInfo format.
+//
+// The key may be passed as PEM, or DER encoded as hex or base64, as commonly used by meters.
+// Base64 encoded keys may in turn contain a hex or PEM encoded key, as sent within OCPP 2.0.1 SignedMeterValue.
+func ParsePublicKey(key string) (*ecdsa.PublicKey, error) {
+	return parsePublicKey(strings.TrimSpace(key), true)
+}
+
+func parsePublicKey(key string, nested bool) (*ecdsa.PublicKey, error) {
+	var der []byte
+	if block, _ := pem.Decode([]byte(key)); block != nil {
+		der = block.Bytes
+	} else {
+		decoded, err := decodeBinary(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key encoding")
+		}
+		der = decoded
+	}
+	if publicKey, ok := parseP192PublicKey(der); ok {
+		return publicKey, nil
+	}
+	parsed, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		if nested && isPrintable(der) {
+			return parsePublicKey(strings.TrimSpace(string(der)), false)
+		}
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	publicKey, ok := parsed.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("invalid public key: expected ECDSA key, got %T", parsed)
+	}
+	return publicKey, nil
+}
+
+// Parses a DER-encoded SubjectPublicKeyInfo containing a P-192 key, which isn't supported by crypto/x509.
+func parseP192PublicKey(der []byte) (*ecdsa.PublicKey, bool) {
+	var info struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if rest, err := asn1.Unmarshal(der, &info); err != nil || len(rest) > 0 {
+		return nil, false
+	}
+	if !info.Algorithm.Algorithm.Equal(oidPublicKeyECDSA) {
+		return nil, false
+	}
+	var namedCurve asn1.ObjectIdentifier
+	if _, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &namedCurve); err != nil || !namedCurve.Equal(oidNamedCurveP192) {
+		return nil, false
+	}
+	x, y := elliptic.Unmarshal(p192, info.PublicKey.RightAlign())
+	if x == nil {
+		return nil, false
+	}
+	return &ecdsa.PublicKey{Curve: p192, X: x, Y: y}, true
+}
+
+func fromHex(s string) *big.Int {
+	n, _ := new(big.Int).SetString(s, 16)
+	return n
+}
+
+// Verifier verifies signed meter values, using either the public key sent along with the meter value,
+// or a public key pinned for the respective meter. It is safe for concurrent use.
+//
+// Pinned keys always take precedence: a meter value sent along with a different public key is rejected.
+type Verifier struct {
+	mutex         sync.RWMutex
+	pinnedKeys    map[string]*ecdsa.PublicKey
+	requirePinned bool
+}
+
+// VerifierOpt is a function that can be used to set options on a verifier during creation.
+type VerifierOpt func(v *Verifier)
+
+// RequirePinnedKeys rejects meter values of meters without a pinned key with ErrPublicKeyNotPinned,
+// so that only keys pinned via PinPublicKey are trusted. Public keys sent along with meter values
+// must still match the pinned key.
+func RequirePinnedKeys() VerifierOpt {
+	return func(v *Verifier) {
+		v.requirePinned = true
+	}
+}
+
+// NewVerifier creates a new verifier without any pinned keys.
+func NewVerifier(opts ...VerifierOpt) *Verifier {
+	v := &Verifier{pinnedKeys: map[string]*ecdsa.PublicKey{}}
+	for _, o := range opts {
+		o(v)
+	}
+	return v
+}
+
+// PinPublicKey pins a public key to the meter with the given ID. Passing a nil key removes the pinned key.
+func (v *Verifier) PinPublicKey(meterID string, publicKey *ecdsa.PublicKey) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	if publicKey == nil {
+		delete(v.pinnedKeys, meterID)
+		return
+	}
+	v.pinnedKeys[meterID] = publicKey
+}
+
+// Verify parses a signed meter value and verifies its signature.
+// The publicKey is optional and may be encoded in any format accepted by ParsePublicKey.
+//
+// If the data could be parsed, it is returned even if the verification fails.
+func (v *Verifier) Verify(data string, publicKey string) (*SignedData, error) {
+	signedData, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	return signedData, v.verify(signedData, publicKey)
+}
+
+// VerifySignedMeterValue parses and verifies an OCPP 2.0.1 signed meter value.
+//
+// The signed meter data is expected to be base64 encoded. If the encoding method is either OCMF or EDL,
+// the data has to match the respective format. The signature algorithm is always taken from the signed data itself.
+//
+// If the data could be parsed, it is returned even if the verification fails.
+func (v *Verifier) VerifySignedMeterValue(value types2.SignedMeterValue) (*SignedData, error) {
+	var signedData *SignedData
+	var err error
+	if raw, decodeErr := base64.StdEncoding.DecodeString(strings.TrimSpace(value.SignedMeterData)); decodeErr == nil {
+		signedData, err = parseBytes(raw)
+	} else {
+		signedData, err = Parse(value.SignedMeterData)
+	}
+	if err != nil {
+		return nil, err
+	}
+	encoding := Format(strings.ToUpper(value.EncodingMethod))
+	if (encoding == FormatOCMF || encoding == FormatEDL) && encoding != signedData.Format {
+		return nil, fmt.Errorf("encoding method %v doesn't match signed data format %v", value.EncodingMethod, signedData.Format)
+	}
+	return signedData, v.verify(signedData, value.PublicKey)
+}
+
+// VerifySampledValue parses and verifies an OCPP 1.6 sampled value with format SignedData.
+// The publicKey is optional, since OCPP 1.6 doesn't send it along with meter values.
+//
+// If the data could be parsed, it is returned even if the verification fails.
+func (v *Verifier) VerifySampledValue(value types16.SampledValue, publicKey string) (*SignedData, error) {
+	if value.Format != types16.ValueFormatSignedData {
+		return nil, fmt.Errorf("invalid sampled value format %v, expected %v", value.Format, types16.ValueFormatSignedData)
+	}
+	return v.Verify(value.Value, publicKey)
+}
+
+func (v *Verifier) verify(signedData *SignedData, publicKey string) error {
+	var providedKey *ecdsa.PublicKey
+	if publicKey != "" {
+		var err error
+		providedKey, err = ParsePublicKey(publicKey)
+		if err != nil {
+			return err
+		}
+	}
+	v.mutex.RLock()
+	pinnedKey, pinned := v.pinnedKeys[signedData.MeterID]
+	v.mutex.RUnlock()
+	key := providedKey
+	if pinned {
+		if providedKey != nil && !pinnedKey.Equal(providedKey) {
+			return ErrPublicKeyMismatch
+		}
+		key = pinnedKey
+	} else if v.requirePinned {
+		return ErrPublicKeyNotPinned
+	}
+	if key == nil {
+		return ErrMissingPublicKey
+	}
+	return signedData.Verify(key)
+}
+
+func decodeBinary(data string) ([]byte, error) {
+	if isHex(data) {
+		return hex.DecodeString(data)
+	}
+	return base64.StdEncoding.DecodeString(data)
+}
+
+func isHex(data string) bool {
+	if data == "" || len(data)%2 != 0 {
+		return false
+	}
+	for _, c := range data {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return true
+}
+
+func isPrintable(data []byte) bool {
+	for _, c := range string(data) {
+		if !unicode.IsPrint(c) && !unicode.IsSpace(c) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,316 @@
+package signedmeter_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
+	types2 "github.com/lorenzodonini/ocpp-go/ocpp2.0.1/types"
+	"github.com/lorenzodonini/ocpp-go/signedmeter"
+)
+
+const ocmfPayload = `{"FV":"1.0","GI":"ABL SBC-301","GS":"808829900001","GV":"1.4p3","PG":"T12","MV":"Phoenix Contact","MM":"EEM-350-D-MCB","MS":"BQ27400330016","MF":"1.0","IS":true,"IL":"VERIFIED","IF":["RFID_PLAIN","OCPP_RS_TLS"],"IT":"ISO14443","ID":"1F2D3A4F5506C7","CT":"EVSEID","CI":"DE*ABC*E123","RD":[{"TM":"2018-07-24T13:22:04,000+0200 S","TX":"B","RV":2935.6,"RI":"1-b:1.8.0","RU":"kWh","RT":"AC","EF":"","ST":"G"},{"TM":"2018-07-24T14:42:11,000+0200 S","TX":"E","RV":2944.1,"RI":"1-b:1.8.0","RU":"kWh","RT":"AC","EF":"","ST":"G"}]}`
+
+func newKey(t *testing.T, curve elliptic.Curve) (*ecdsa.PrivateKey, []byte) {
+	key, err := ecdsa.GenerateKey(curve, rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	return key, der
+}
+
+func signOCMF(t *testing.T, key *ecdsa.PrivateKey, payload string) string {
+	digest := sha256.Sum256([]byte(payload))
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+	return fmt.Sprintf(`OCMF|%v|{"SA":"ECDSA-secp256r1-SHA256","SD":"%X"}`, payload, sig)
+}
+
+// Signed data of an EDL40 meter, laid out as reconstructed by the S.A.F.E. transparency software.
+var edlSignedData = strings.Join([]string{
+	"0a01454d4800007a3c11", // server ID
+	"5e5b8ea8",             // timestamp, 2020-03-01T10:30:00Z
+	"04",                   // status
+	"000004d2",             // seconds index
+	"0000002a",             // pagination counter
+	"0100010800ff",         // OBIS code 1-0:1.8.0*255
+	"1e",                   // unit Wh
+	"ff",                   // scaler -1
+	"000000000001e240",     // value 123456
+	"0008",                 // logbook
+	hex.EncodeToString([]byte("DE*ABC*C12345678*9")) + strings.Repeat("00", 128-18), // contract ID
+	"5e5b8e6c",                // contract timestamp, 2020-03-01T10:29:00Z
+	strings.Repeat("00", 147), // padding
+}, "")
+
+func signEDL(t *testing.T, key *ecdsa.PrivateKey, signedData string) []byte {
+	record, err := hex.DecodeString(signedData)
+	require.NoError(t, err)
+	require.Len(t, record, 320)
+	digest := sha256.Sum256(record)
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+	sig := make([]byte, 48)
+	r.FillBytes(sig[:24])
+	s.FillBytes(sig[24:])
+	return append(record, sig...)
+}
+
+// Encodes a P-192 public key as SubjectPublicKeyInfo, which isn't supported by crypto/x509.
+func marshalP192PublicKey(t *testing.T, key *ecdsa.PublicKey) []byte {
+	curve, err := asn1.Marshal(asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 1})
+	require.NoError(t, err)
+	point := elliptic.Marshal(key.Curve, key.X, key.Y)
+	der, err := asn1.Marshal(struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}{
+		Algorithm: pkix.AlgorithmIdentifier{Algorithm: asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}, Parameters: asn1.RawValue{FullBytes: curve}},
+		PublicKey: asn1.BitString{Bytes: point, BitLength: 8 * len(point)},
+	})
+	require.NoError(t, err)
+	return der
+}
+
+func TestParseOCMF(t *testing.T) {
+	key, _ := newKey(t, elliptic.P256())
+	signedData, err := signedmeter.Parse(signOCMF(t, key, ocmfPayload))
+	require.NoError(t, err)
+	assert.Equal(t, signedmeter.FormatOCMF, signedData.Format)
+	assert.Equal(t, "BQ27400330016", signedData.MeterID)
+	assert.Equal(t, "Phoenix Contact", signedData.Manufacturer)
+	assert.Equal(t, "EEM-350-D-MCB", signedData.Model)
+	assert.Equal(t, signedmeter.Pagination{Context: "T", Counter: 12}, signedData.Pagination)
+	assert.Equal(t, "T12", signedData.Pagination.String())
+	assert.Equal(t, "ISO14443", signedData.IdentificationType)
+	assert.Equal(t, "1F2D3A4F5506C7", signedData.IdentificationData)
+	assert.Equal(t, "DE*ABC*E123", signedData.ChargePointID)
+	assert.Equal(t, signedmeter.AlgorithmECDSAP256SHA256, signedData.SignatureAlgorithm)
+	assert.Equal(t, []byte(ocmfPayload), signedData.Payload)
+	require.Len(t, signedData.Readings, 2)
+	begin := signedData.Readings[0]
+	assert.True(t, time.Date(2018, 7, 24, 11, 22, 4, 0, time.UTC).Equal(begin.Timestamp))
+	assert.Equal(t, "S", begin.TimeStatus)
+	assert.Equal(t, "B", begin.Type)
+	assert.Equal(t, 2935.6, begin.Value)
+	assert.Equal(t, "1-b:1.8.0", begin.Identifier)
+	assert.Equal(t, "kWh", begin.Unit)
+	assert.Equal(t, "G", begin.Status)
+	assert.Equal(t, "E", signedData.Readings[1].Type)
+	assert.NoError(t, signedData.Verify(&key.PublicKey))
+	// Invalid data
+	_, err = signedmeter.ParseOCMF(`OCMF|{"MS":"1"}`)
+	assert.Error(t, err)
+	_, err = signedmeter.ParseOCMF(`OCMF|{"MS":"1","PG":"T"}|{"SD":"00"}`)
+	assert.Error(t, err)
+	_, err = signedmeter.ParseOCMF(`OCMF|{"MS":"1","RD":[{"TM":"yesterday"}]}|{"SD":"00"}`)
+	assert.Error(t, err)
+	_, err = signedmeter.ParseOCMF(`OCMF|{"MS":"1"}|{"SE":"morse","SD":"00"}`)
+	assert.Error(t, err)
+	_, err = signedmeter.Parse(`not a meter value`)
+	assert.Error(t, err)
+}
+
+func TestVerifyOCMF(t *testing.T) {
+	key, der := newKey(t, elliptic.P256())
+	otherKey, otherDer := newKey(t, elliptic.P256())
+	data := signOCMF(t, key, ocmfPayload)
+	verifier := signedmeter.NewVerifier()
+	// Provided key, encoded as hex or base64
+	signedData, err := verifier.Verify(data, hex.EncodeToString(der))
+	require.NoError(t, err)
+	assert.Equal(t, "BQ27400330016", signedData.MeterID)
+	_, err = verifier.Verify(data, base64.StdEncoding.EncodeToString(der))
+	require.NoError(t, err)
+	// Wrong key and tampered payload
+	_, err = verifier.Verify(data, hex.EncodeToString(otherDer))
+	assert.True(t, errors.Is(err, signedmeter.ErrInvalidSignature))
+	tampered := signOCMF(t, key, ocmfPayload)
+	tampered = tampered[:len("OCMF|")] + `{"MS":"BQ27400330016","RD":[]}` + tampered[len("OCMF|")+len(ocmfPayload):]
+	signedData, err = verifier.Verify(tampered, hex.EncodeToString(der))
+	assert.True(t, errors.Is(err, signedmeter.ErrInvalidSignature))
+	assert.NotNil(t, signedData)
+	// Missing key
+	_, err = verifier.Verify(data, "")
+	assert.True(t, errors.Is(err, signedmeter.ErrMissingPublicKey))
+	// Pinned key is used if no key is provided, and must match the provided key
+	verifier.PinPublicKey("BQ27400330016", &key.PublicKey)
+	_, err = verifier.Verify(data, "")
+	require.NoError(t, err)
+	_, err = verifier.Verify(data, hex.EncodeToString(otherDer))
+	assert.True(t, errors.Is(err, signedmeter.ErrPublicKeyMismatch))
+	verifier.PinPublicKey("BQ27400330016", &otherKey.PublicKey)
+	_, err = verifier.Verify(data, "")
+	assert.True(t, errors.Is(err, signedmeter.ErrInvalidSignature))
+	verifier.PinPublicKey("BQ27400330016", nil)
+	_, err = verifier.Verify(data, "")
+	assert.True(t, errors.Is(err, signedmeter.ErrMissingPublicKey))
+	// Missing algorithm defaults to ECDSA-secp192r1-SHA256
+	p192Key, err := ecdsa.GenerateKey(signedmeter.P192(), rand.Reader)
+	require.NoError(t, err)
+	digest := sha256.Sum256([]byte(ocmfPayload))
+	sig, err := ecdsa.SignASN1(rand.Reader, p192Key, digest[:])
+	require.NoError(t, err)
+	defaultData := fmt.Sprintf(`OCMF|%v|{"SD":"%X"}`, ocmfPayload, sig)
+	signedData, err = verifier.Verify(defaultData, hex.EncodeToString(marshalP192PublicKey(t, &p192Key.PublicKey)))
+	require.NoError(t, err)
+	assert.Equal(t, signedmeter.AlgorithmECDSAP192SHA256, signedData.SignatureAlgorithm)
+	assert.Error(t, signedData.Verify(&key.PublicKey))
+	// Unsupported algorithm and curve mismatch
+	signedData, err = signedmeter.ParseOCMF(`OCMF|{"MS":"1"}|{"SA":"ECDSA-brainpool256r1-SHA256","SD":"00"}`)
+	require.NoError(t, err)
+	assert.True(t, errors.Is(signedData.Verify(&key.PublicKey), signedmeter.ErrUnsupportedAlgorithm))
+	p384Key, _ := newKey(t, elliptic.P384())
+	signedData, err = signedmeter.ParseOCMF(data)
+	require.NoError(t, err)
+	assert.Error(t, signedData.Verify(&p384Key.PublicKey))
+}
+
+func TestParseEDL(t *testing.T) {
+	key, err := ecdsa.GenerateKey(signedmeter.P192(), rand.Reader)
+	require.NoError(t, err)
+	der := marshalP192PublicKey(t, &key.PublicKey)
+	data := signEDL(t, key, edlSignedData)
+	signedData, err := signedmeter.Parse(hex.EncodeToString(data))
+	require.NoError(t, err)
+	assert.Equal(t, signedmeter.FormatEDL, signedData.Format)
+	assert.Equal(t, "0A01454D4800007A3C11", signedData.MeterID)
+	assert.Equal(t, signedmeter.Pagination{Counter: 42}, signedData.Pagination)
+	assert.Equal(t, "DE*ABC*C12345678*9", signedData.IdentificationData)
+	assert.Equal(t, uint16(8), signedData.Logbook)
+	assert.Equal(t, signedmeter.AlgorithmECDSAP192SHA256, signedData.SignatureAlgorithm)
+	assert.Len(t, signedData.Payload, 320)
+	require.Len(t, signedData.Readings, 1)
+	reading := signedData.Readings[0]
+	assert.Equal(t, time.Date(2020, 3, 1, 10, 30, 0, 0, time.UTC), reading.Timestamp)
+	assert.InDelta(t, 12345.6, reading.Value, 0.0001)
+	assert.Equal(t, "1-0:1.8.0*255", reading.Identifier)
+	assert.Equal(t, "Wh", reading.Unit)
+	assert.Equal(t, "04", reading.Status)
+	assert.NoError(t, signedData.Verify(&key.PublicKey))
+	// Base64 encoding
+	_, err = signedmeter.NewVerifier().Verify(base64.StdEncoding.EncodeToString(data), hex.EncodeToString(der))
+	require.NoError(t, err)
+	// Tampered contract ID, which is covered by the signature as well
+	data[41]++
+	_, err = signedmeter.NewVerifier().Verify(hex.EncodeToString(data), hex.EncodeToString(der))
+	assert.True(t, errors.Is(err, signedmeter.ErrInvalidSignature))
+	// Invalid length, e.g. the bare reading without logbook and contract ID
+	_, err = signedmeter.ParseEDL(append(data[:39], data[320:]...))
+	assert.Error(t, err)
+	_, err = signedmeter.ParseEDL(append(data, make([]byte, 16)...))
+	assert.Error(t, err)
+}
+
+func TestVerifySignedMeterValue(t *testing.T) {
+	key, der := newKey(t, elliptic.P256())
+	verifier := signedmeter.NewVerifier()
+	value := types2.SignedMeterValue{
+		SignedMeterData: base64.StdEncoding.EncodeToString([]byte(signOCMF(t, key, ocmfPayload))),
+		SigningMethod:   signedmeter.AlgorithmECDSAP256SHA256,
+		EncodingMethod:  "OCMF",
+		PublicKey:       base64.StdEncoding.EncodeToString([]byte(hex.EncodeToString(der))),
+	}
+	signedData, err := verifier.VerifySignedMeterValue(value)
+	require.NoError(t, err)
+	assert.Equal(t, "BQ27400330016", signedData.MeterID)
+	// Key containing the base64 encoded DER
+	value.PublicKey = base64.StdEncoding.EncodeToString(der)
+	_, err = verifier.VerifySignedMeterValue(value)
+	require.NoError(t, err)
+	// EDL data
+	p192Key, err := ecdsa.GenerateKey(signedmeter.P192(), rand.Reader)
+	require.NoError(t, err)
+	value.SignedMeterData = base64.StdEncoding.EncodeToString(signEDL(t, p192Key, edlSignedData))
+	value.SigningMethod = signedmeter.AlgorithmECDSAP192SHA256
+	value.EncodingMethod = "EDL"
+	value.PublicKey = base64.StdEncoding.EncodeToString(marshalP192PublicKey(t, &p192Key.PublicKey))
+	signedData, err = verifier.VerifySignedMeterValue(value)
+	require.NoError(t, err)
+	assert.Equal(t, signedmeter.FormatEDL, signedData.Format)
+	// Encoding method mismatch
+	value.EncodingMethod = "OCMF"
+	_, err = verifier.VerifySignedMeterValue(value)
+	assert.Error(t, err)
+}
+
+func TestVerifySampledValue(t *testing.T) {
+	key, der := newKey(t, elliptic.P256())
+	verifier := signedmeter.NewVerifier()
+	verifier.PinPublicKey("BQ27400330016", &key.PublicKey)
+	value := types.SampledValue{
+		Value:     signOCMF(t, key, ocmfPayload),
+		Context:   types.ReadingContextTransactionEnd,
+		Format:    types.ValueFormatSignedData,
+		Measurand: types.MeasurandEnergyActiveImportRegister,
+	}
+	signedData, err := verifier.VerifySampledValue(value, "")
+	require.NoError(t, err)
+	assert.Len(t, signedData.Readings, 2)
+	_, err = verifier.VerifySampledValue(value, hex.EncodeToString(der))
+	require.NoError(t, err)
+	// Only signed data is accepted
+	value.Format = types.ValueFormatRaw
+	_, err = verifier.VerifySampledValue(value, "")
+	assert.Error(t, err)
+}
+
+func TestParsePublicKey(t *testing.T) {
+	key, der := newKey(t, elliptic.P256())
+	pemKey := fmt.Sprintf("-----BEGIN PUBLIC KEY-----\n%v\n-----END PUBLIC KEY-----\n", base64.StdEncoding.EncodeToString(der))
+	for _, encoded := range []string{
+		hex.EncodeToString(der),
+		base64.StdEncoding.EncodeToString(der),
+		pemKey,
+		base64.StdEncoding.EncodeToString([]byte(pemKey)),
+	} {
+		publicKey, err := signedmeter.ParsePublicKey(encoded)
+		require.NoError(t, err)
+		assert.True(t, key.PublicKey.Equal(publicKey))
+	}
+	_, err := signedmeter.ParsePublicKey("invalid key")
+	assert.Error(t, err)
+	_, err = signedmeter.ParsePublicKey("00112233")
+	assert.Error(t, err)
+}
+
+func TestRequirePinnedKeys(t *testing.T) {
+	key, der := newKey(t, elliptic.P256())
+	_, otherDer := newKey(t, elliptic.P256())
+	data := signOCMF(t, key, ocmfPayload)
+	verifier := signedmeter.NewVerifier(signedmeter.RequirePinnedKeys())
+	// Provided keys aren't trusted without a pinned key
+	signedData, err := verifier.Verify(data, hex.EncodeToString(der))
+	assert.True(t, errors.Is(err, signedmeter.ErrPublicKeyNotPinned))
+	require.NotNil(t, signedData)
+	assert.Equal(t, "BQ27400330016", signedData.MeterID)
+	_, err = verifier.Verify(data, "")
+	assert.True(t, errors.Is(err, signedmeter.ErrPublicKeyNotPinned))
+	// Pinned key is used, and must match the provided key
+	verifier.PinPublicKey("BQ27400330016", &key.PublicKey)
+	_, err = verifier.Verify(data, "")
+	require.NoError(t, err)
+	_, err = verifier.Verify(data, hex.EncodeToString(der))
+	require.NoError(t, err)
+	_, err = verifier.Verify(data, hex.EncodeToString(otherDer))
+	assert.True(t, errors.Is(err, signedmeter.ErrPublicKeyMismatch))
+	// Removing the pinned key rejects the meter again
+	verifier.PinPublicKey("BQ27400330016", nil)
+	_, err = verifier.Verify(data, hex.EncodeToString(der))
+	assert.True(t, errors.Is(err, signedmeter.ErrPublicKeyNotPinned))
+}
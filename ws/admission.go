@@ -0,0 +1,381 @@
+package ws
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Default value of the Retry-After header, when a client exceeds the maximum amount of connections per IP.
+const defaultAdmissionRetryAfter = 30 * time.Second
+
+// AdmissionRejectReason describes why an incoming connection was rejected by the admission control.
+type AdmissionRejectReason string
+
+const (
+	// The source IP matches one of the denied networks.
+	AdmissionRejectDenied AdmissionRejectReason = "denied"
+	// The source IP doesn't match any of the allowed networks.
+	AdmissionRejectNotAllowed AdmissionRejectReason = "notAllowed"
+	// The source IP already holds the maximum amount of connections.
+	AdmissionRejectConnectionLimit AdmissionRejectReason = "connectionLimit"
+	// The global handshake rate limit was exceeded.
+	AdmissionRejectRateLimit AdmissionRejectReason = "rateLimit"
+)
+
+// AdmissionConfig contains the policies for admitting incoming connections to a server.
+//
+// Admission checks are performed by the listener of the server, whenever a connection is accepted,
+// before the TLS handshake and before receiving any request.
+// Source IPs are checked against the network lists first, followed by the per-IP limit and the rate limit.
+// The per-IP limit refers to open network connections.
+//
+// Connections from denied sources are closed right away. When the server is over capacity
+// (per-IP limit or rate limit exceeded), the behavior depends on the listener:
+//
+//   - on plain-HTTP listeners, the connection is kept open long enough to reject the request
+//     with HTTP 503 and a Retry-After header.
+//   - on TLS listeners, the connection is closed before the TLS handshake as well. Replying with HTTP 503
+//     would require completing the handshake first, which is exactly the load to shed during a reconnection storm.
+//     Clients therefore don't receive a Retry-After header and rely on their own back-off.
+//
+// To send HTTP 503 responses over TLS regardless, set a SourceIPResolver or mount the server handler
+// on a separate HTTP server, so that the checks are performed on the request.
+//
+// If a SourceIPResolver is set, or if the server handler is mounted on a separate HTTP server,
+// the source IP is only known after receiving the request. In that case, the checks are performed
+// for every HTTP upgrade request instead, and denied sources are rejected with HTTP 403.
+type AdmissionConfig struct {
+	// If not empty, only connections from these networks are admitted. Use ParseNetworks to build the list.
+	AllowedNetworks []*net.IPNet
+	// Connections from these networks are always rejected. Takes precedence over AllowedNetworks.
+	DeniedNetworks []*net.IPNet
+	// The maximum amount of open connections per source IP. If zero, no limit is enforced.
+	MaxConnectionsPerIP int
+	// The maximum amount of handshakes per second, across all clients. If zero, no limit is enforced.
+	HandshakeRate float64
+	// The maximum amount of handshakes, which may be accepted in a single burst. Defaults to the handshake rate (at least 1).
+	HandshakeBurst int
+	// The value of the Retry-After header, when a source IP exceeds MaxConnectionsPerIP. Defaults to 30 seconds.
+	// For rate-limited handshakes, the header contains the time until the next handshake may be accepted.
+	RetryAfter time.Duration
+	// Optional function for determining the source IP of a request, e.g. from a header set by a trusted reverse proxy.
+	// By default, the IP is taken from the remote address of the request.
+	SourceIPResolver func(r *http.Request) (net.IP, error)
+	// Optional callback, invoked whenever a connection is rejected. Useful for exporting metrics.
+	// The callback is invoked synchronously and should return quickly.
+	// The request is nil, if the connection was rejected by the listener, before receiving a request.
+	OnRejected func(reason AdmissionRejectReason, sourceIP net.IP, r *http.Request)
+}
+
+// AdmissionStats contains counters about the connections admitted and rejected by the admission control.
+type AdmissionStats struct {
+	Admitted                uint64 // The amount of requests, which passed the admission checks.
+	RejectedDenied          uint64 // The amount of requests rejected, because the source matched a denied network.
+	RejectedNotAllowed      uint64 // The amount of requests rejected, because the source didn't match any allowed network.
+	RejectedConnectionLimit uint64 // The amount of requests rejected, because the source IP reached the maximum amount of connections.
+	RejectedRateLimit       uint64 // The amount of requests rejected, because the handshake rate limit was exceeded.
+}
+
+// Rejected returns the total amount of rejected requests.
+func (s AdmissionStats) Rejected() uint64 {
+	return s.RejectedDenied + s.RejectedNotAllowed + s.RejectedConnectionLimit + s.RejectedRateLimit
+}
+
+// ParseNetworks parses a list of IP addresses and CIDR blocks (e.g. "10.0.0.0/8" or "192.168.1.10").
+// Single IP addresses are converted to a network containing only that address.
+func ParseNetworks(networks ...string) ([]*net.IPNet, error) {
+	result := make([]*net.IPNet, 0, len(networks))
+	for _, n := range networks {
+		n = strings.TrimSpace(n)
+		if strings.Contains(n, "/") {
+			_, ipNet, err := net.ParseCIDR(n)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, ipNet)
+			continue
+		}
+		ip := net.ParseIP(n)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid IP address %s", n)
+		}
+		bits := 8 * net.IPv6len
+		if ip4 := ip.To4(); ip4 != nil {
+			ip = ip4
+			bits = 8 * net.IPv4len
+		}
+		result = append(result, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+	}
+	return result, nil
+}
+
+// WithServerAdmissionControl enables admission control for incoming connections.
+// Refer to AdmissionConfig for the available policies.
+func WithServerAdmissionControl(config AdmissionConfig) ServerOpt {
+	return func(s *server) {
+		s.admission = newAdmissionControl(config)
+	}
+}
+
+// admissionControl enforces an AdmissionConfig and keeps track of open connections per source IP.
+type admissionControl struct {
+	config      AdmissionConfig
+	stats       AdmissionStats
+	connections map[string]int
+	limiter     *tokenBucket
+	mutex       sync.Mutex
+}
+
+func newAdmissionControl(config AdmissionConfig) *admissionControl {
+	a := &admissionControl{config: config, connections: map[string]int{}}
+	if config.RetryAfter <= 0 {
+		a.config.RetryAfter = defaultAdmissionRetryAfter
+	}
+	if config.HandshakeRate > 0 {
+		burst := config.HandshakeBurst
+		if burst <= 0 {
+			burst = int(math.Max(1, config.HandshakeRate))
+		}
+		a.limiter = newTokenBucket(config.HandshakeRate, burst)
+	}
+	return a
+}
+
+// admissionRejection contains the HTTP response to send to a rejected client.
+type admissionRejection struct {
+	reason     AdmissionRejectReason
+	status     int
+	retryAfter time.Duration
+}
+
+func (e *admissionRejection) Error() string {
+	return fmt.Sprintf("connection rejected by admission control: %s", e.reason)
+}
+
+// admit checks whether an incoming request may be accepted.
+// If so, a connection slot is reserved for the source IP, and must be freed via release.
+func (a *admissionControl) admit(r *http.Request) (string, *admissionRejection) {
+	ip, err := a.sourceIP(r)
+	if err != nil {
+		// Without a source IP, only the rate limit can be enforced
+		log.Errorf("couldn't determine source IP of %s: %v", r.RemoteAddr, err)
+	}
+	if rejection := a.check(ip); rejection != nil {
+		a.reject(rejection, ip, r.RemoteAddr, r)
+		return "", rejection
+	}
+	atomic.AddUint64(&a.stats.Admitted, 1)
+	if ip == nil {
+		return "", nil
+	}
+	return ip.String(), nil
+}
+
+func (a *admissionControl) check(ip net.IP) *admissionRejection {
+	if ip != nil {
+		if containsIP(a.config.DeniedNetworks, ip) {
+			return &admissionRejection{reason: AdmissionRejectDenied, status: http.StatusForbidden}
+		}
+		if len(a.config.AllowedNetworks) > 0 && !containsIP(a.config.AllowedNetworks, ip) {
+			return &admissionRejection{reason: AdmissionRejectNotAllowed, status: http.StatusForbidden}
+		}
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if ip != nil && a.config.MaxConnectionsPerIP > 0 && a.connections[ip.String()] >= a.config.MaxConnectionsPerIP {
+		return &admissionRejection{reason: AdmissionRejectConnectionLimit, status: http.StatusServiceUnavailable, retryAfter: a.config.RetryAfter}
+	}
+	if a.limiter != nil {
+		if ok, wait := a.limiter.take(time.Now()); !ok {
+			return &admissionRejection{reason: AdmissionRejectRateLimit, status: http.StatusServiceUnavailable, retryAfter: wait}
+		}
+	}
+	if ip != nil {
+		a.connections[ip.String()]++
+	}
+	return nil
+}
+
+func (a *admissionControl) reject(rejection *admissionRejection, ip net.IP, remoteAddr string, r *http.Request) {
+	switch rejection.reason {
+	case AdmissionRejectDenied:
+		atomic.AddUint64(&a.stats.RejectedDenied, 1)
+	case AdmissionRejectNotAllowed:
+		atomic.AddUint64(&a.stats.RejectedNotAllowed, 1)
+	case AdmissionRejectConnectionLimit:
+		atomic.AddUint64(&a.stats.RejectedConnectionLimit, 1)
+	case AdmissionRejectRateLimit:
+		atomic.AddUint64(&a.stats.RejectedRateLimit, 1)
+	}
+	log.Infof("rejected connection from %s: %s", remoteAddr, rejection.reason)
+	if a.config.OnRejected != nil {
+		a.config.OnRejected(rejection.reason, ip, r)
+	}
+}
+
+// release frees the connection slot reserved for a source IP.
+func (a *admissionControl) release(ip string) {
+	if ip == "" {
+		return
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if a.connections[ip] <= 1 {
+		delete(a.connections, ip)
+	} else {
+		a.connections[ip]--
+	}
+}
+
+func (a *admissionControl) getStats() AdmissionStats {
+	return AdmissionStats{
+		Admitted:                atomic.LoadUint64(&a.stats.Admitted),
+		RejectedDenied:          atomic.LoadUint64(&a.stats.RejectedDenied),
+		RejectedNotAllowed:      atomic.LoadUint64(&a.stats.RejectedNotAllowed),
+		RejectedConnectionLimit: atomic.LoadUint64(&a.stats.RejectedConnectionLimit),
+		RejectedRateLimit:       atomic.LoadUint64(&a.stats.RejectedRateLimit),
+	}
+}
+
+func (a *admissionControl) sourceIP(r *http.Request) (net.IP, error) {
+	if a.config.SourceIPResolver != nil {
+		return a.config.SourceIPResolver(r)
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid remote address %s", r.RemoteAddr)
+	}
+	return ip, nil
+}
+
+// writeResponse sends the HTTP response for a rejected request.
+func (e *admissionRejection) writeResponse(w http.ResponseWriter) {
+	if e.retryAfter > 0 {
+		seconds := int(math.Ceil(e.retryAfter.Seconds()))
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	}
+	http.Error(w, http.StatusText(e.status), e.status)
+}
+
+// admissionListener wraps a net.Listener and performs the admission checks on every accepted connection.
+// Must be the innermost wrapper, so that the checks happen before the TLS handshake.
+type admissionListener struct {
+	net.Listener
+	admission *admissionControl
+	useTLS    bool // if set, connections over capacity are closed instead of being rejected via HTTP
+}
+
+func (l admissionListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+		ip := remoteIP(conn.RemoteAddr())
+		rejection := l.admission.check(ip)
+		if rejection == nil {
+			atomic.AddUint64(&l.admission.stats.Admitted, 1)
+			ac := &admissionConn{Conn: conn, admission: l.admission}
+			if ip != nil {
+				ac.ip = ip.String()
+			}
+			return ac, nil
+		}
+		l.admission.reject(rejection, ip, conn.RemoteAddr().String(), nil)
+		if rejection.status == http.StatusForbidden || l.useTLS {
+			// Denied sources are dropped right away, as are clients over capacity on TLS listeners,
+			// since the handshake would have to be completed before sending any response.
+			_ = conn.Close()
+			continue
+		}
+		// Clients over capacity on plain-HTTP listeners are told when to retry, via the HTTP response
+		return &admissionConn{Conn: conn, admission: l.admission, rejection: rejection}, nil
+	}
+}
+
+// admissionConn is a connection accepted by an admissionListener.
+// The connection slot reserved for the source IP is freed once the connection is closed.
+type admissionConn struct {
+	net.Conn
+	admission *admissionControl
+	ip        string
+	rejection *admissionRejection // set if the requests on this connection must be rejected
+	closeOnce sync.Once
+}
+
+func (c *admissionConn) Close() error {
+	c.closeOnce.Do(func() {
+		if c.rejection == nil {
+			c.admission.release(c.ip)
+		}
+	})
+	return c.Conn.Close()
+}
+
+// admissionConnFromContext retrieves the admissionConn underlying a request, if the request was accepted by an admissionListener.
+func admissionConnFromContext(ctx context.Context) *admissionConn {
+	cc := countingConnFromContext(ctx)
+	if cc == nil {
+		return nil
+	}
+	ac, _ := cc.Conn.(*admissionConn)
+	return ac
+}
+
+func remoteIP(addr net.Addr) net.IP {
+	if tcpAddr, ok := addr.(*net.TCPAddr); ok {
+		return tcpAddr.IP
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		host = addr.String()
+	}
+	return net.ParseIP(host)
+}
+
+func containsIP(networks []*net.IPNet, ip net.IP) bool {
+	for _, n := range networks {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenBucket is a simple token bucket rate limiter. It is not safe for concurrent use.
+type tokenBucket struct {
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst)}
+}
+
+// take consumes a token, if available. Otherwise, the time until the next token becomes available is returned.
+func (b *tokenBucket) take(now time.Time) (bool, time.Duration) {
+	if !b.last.IsZero() {
+		b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	}
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+	return false, wait
+}
@@ -0,0 +1,31 @@
+package ws
+
+func (w *webSocket) SetAttribute(key string, value interface{}) {
+	w.attributeMutex.Lock()
+	defer w.attributeMutex.Unlock()
+	if value == nil {
+		delete(w.attributes, key)
+		return
+	}
+	if w.attributes == nil {
+		w.attributes = map[string]interface{}{}
+	}
+	w.attributes[key] = value
+}
+
+func (w *webSocket) Attribute(key string) (interface{}, bool) {
+	w.attributeMutex.RLock()
+	defer w.attributeMutex.RUnlock()
+	value, ok := w.attributes[key]
+	return value, ok
+}
+
+func (w *webSocket) Attributes() map[string]interface{} {
+	w.attributeMutex.RLock()
+	defer w.attributeMutex.RUnlock()
+	result := make(map[string]interface{}, len(w.attributes))
+	for key, value := range w.attributes {
+		result[key] = value
+	}
+	return result
+}
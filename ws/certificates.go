@@ -0,0 +1,214 @@
+package ws
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// CertificateProvider supplies the certificate to present during a TLS handshake.
+//
+// The provider is queried on every handshake, hence certificates may be rotated at runtime
+// without restarting a server or recreating a client.
+// Refer to WithServerCertificateProvider and WithClientCertificateProvider.
+type CertificateProvider interface {
+	// Certificate returns the certificate to present to the peer.
+	Certificate() (*tls.Certificate, error)
+}
+
+// ErrNoCertificate is returned by a certificate provider, which doesn't hold any certificate.
+var ErrNoCertificate = errors.New("no certificate available")
+
+// StaticCertificateProvider holds a single certificate in memory, which may be swapped at runtime.
+//
+// Use NewStaticCertificateProvider to create a new provider.
+type StaticCertificateProvider struct {
+	certificate *tls.Certificate
+	mutex       sync.RWMutex
+}
+
+// NewStaticCertificateProvider creates a provider, initially holding the passed certificate.
+// The certificate may be nil and set at a later point via SetCertificate.
+func NewStaticCertificateProvider(certificate *tls.Certificate) *StaticCertificateProvider {
+	return &StaticCertificateProvider{certificate: certificate}
+}
+
+func (p *StaticCertificateProvider) Certificate() (*tls.Certificate, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	if p.certificate == nil {
+		return nil, ErrNoCertificate
+	}
+	return p.certificate, nil
+}
+
+// SetCertificate swaps the current certificate. The new certificate is used starting from the next handshake.
+func (p *StaticCertificateProvider) SetCertificate(certificate *tls.Certificate) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.certificate = certificate
+}
+
+// SetCertificatePEM parses a PEM encoded certificate chain and private key, and swaps the current certificate.
+// This is useful for installing a certificate received via a CertificateSigned request.
+func (p *StaticCertificateProvider) SetCertificatePEM(certPEM []byte, keyPEM []byte) error {
+	certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return err
+	}
+	p.SetCertificate(&certificate)
+	return nil
+}
+
+// FileCertificateProvider loads a certificate and private key from files on disk,
+// and reloads them whenever the files are modified.
+//
+// Files are checked for modifications at most once per check interval, during a handshake.
+// If reloading fails (e.g. because only one of the two files was replaced so far),
+// the previously loaded certificate is kept until the next check.
+//
+// Use NewFileCertificateProvider to create a new provider.
+type FileCertificateProvider struct {
+	certFile      string
+	keyFile       string
+	checkInterval time.Duration
+	certificate   *tls.Certificate
+	certModTime   time.Time
+	keyModTime    time.Time
+	lastCheck     time.Time
+	mutex         sync.Mutex
+}
+
+// NewFileCertificateProvider creates a provider, which loads the certificate from the passed files.
+// The files must be PEM encoded. If the initial load fails, an error is returned.
+//
+// If checkInterval is zero, files are checked for modifications on every handshake.
+func NewFileCertificateProvider(certFile string, keyFile string, checkInterval time.Duration) (*FileCertificateProvider, error) {
+	p := &FileCertificateProvider{
+		certFile:      certFile,
+		keyFile:       keyFile,
+		checkInterval: checkInterval,
+	}
+	if err := p.Reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *FileCertificateProvider) Certificate() (*tls.Certificate, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if time.Since(p.lastCheck) >= p.checkInterval {
+		p.lastCheck = time.Now()
+		if modified, err := p.isModified(); err != nil {
+			log.Errorf("couldn't check certificate files %s: %v", p.certFile, err)
+		} else if modified {
+			if err = p.load(); err != nil {
+				log.Errorf("couldn't reload certificate from %s, keeping previous certificate: %v", p.certFile, err)
+			} else {
+				log.Infof("reloaded certificate from %s", p.certFile)
+			}
+		}
+	}
+	if p.certificate == nil {
+		return nil, ErrNoCertificate
+	}
+	return p.certificate, nil
+}
+
+// Reload forces the certificate to be loaded from disk, regardless of the file modification times.
+func (p *FileCertificateProvider) Reload() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.lastCheck = time.Now()
+	return p.load()
+}
+
+func (p *FileCertificateProvider) isModified() (bool, error) {
+	certInfo, err := os.Stat(p.certFile)
+	if err != nil {
+		return false, err
+	}
+	keyInfo, err := os.Stat(p.keyFile)
+	if err != nil {
+		return false, err
+	}
+	return !certInfo.ModTime().Equal(p.certModTime) || !keyInfo.ModTime().Equal(p.keyModTime), nil
+}
+
+func (p *FileCertificateProvider) load() error {
+	certInfo, err := os.Stat(p.certFile)
+	if err != nil {
+		return err
+	}
+	keyInfo, err := os.Stat(p.keyFile)
+	if err != nil {
+		return err
+	}
+	certificate, err := tls.LoadX509KeyPair(p.certFile, p.keyFile)
+	if err != nil {
+		return fmt.Errorf("couldn't load certificate: %w", err)
+	}
+	p.certificate = &certificate
+	p.certModTime = certInfo.ModTime()
+	p.keyModTime = keyInfo.ModTime()
+	return nil
+}
+
+// WithServerCertificateProvider sets a provider for the server certificate.
+// The provider is queried on every TLS handshake, so the certificate may be rotated without restarting the server.
+//
+// If set, the provider takes precedence over the certificate files passed to WithServerTLSConfig.
+// Other TLS settings, such as the trusted client CAs, may still be set via WithServerTLSConfig.
+func WithServerCertificateProvider(provider CertificateProvider) ServerOpt {
+	return func(s *server) {
+		s.certificateProvider = provider
+	}
+}
+
+// WithClientCertificateProvider sets a provider for the client certificate.
+// The provider is queried on every TLS handshake, hence a new certificate is used when reconnecting to the server.
+//
+// To force a reconnection using the new certificate, refer to the Reconnect method of the client.
+func WithClientCertificateProvider(provider CertificateProvider) ClientOpt {
+	return func(c *client) {
+		c.certificateProvider = provider
+	}
+}
+
+// withServerCertificate returns a copy of the passed TLS configuration, which retrieves the server certificate from the provider.
+func withServerCertificate(config *tls.Config, provider CertificateProvider) *tls.Config {
+	var result *tls.Config
+	if config != nil {
+		result = config.Clone()
+	} else {
+		result = &tls.Config{}
+	}
+	result.GetCertificate = func(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+		return provider.Certificate()
+	}
+	return result
+}
+
+// withClientCertificate returns a copy of the passed TLS configuration, which retrieves the client certificate from the provider.
+func withClientCertificate(config *tls.Config, provider CertificateProvider) *tls.Config {
+	var result *tls.Config
+	if config != nil {
+		result = config.Clone()
+	} else {
+		result = &tls.Config{}
+	}
+	result.GetClientCertificate = func(_ *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+		certificate, err := provider.Certificate()
+		if err != nil {
+			log.Errorf("couldn't retrieve client certificate: %v", err)
+			// Continue the handshake without a certificate, the server decides whether to accept the connection
+			return &tls.Certificate{}, nil
+		}
+		return certificate, nil
+	}
+	return result
+}
@@ -1,14 +1,16 @@
 package ws
 
 import (
+	"context"
 	"crypto/tls"
 	"encoding/base64"
 	"fmt"
 	"io"
-	"math/rand"
+	"net"
 	"net/http"
 	"net/url"
 	"path"
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -63,16 +65,29 @@ type Client interface {
 	// To stop a running client, call the Stop function.
 	Start(url string) error
 	// Starts the client and attempts to connect to the server on a specified URL.
-	// If the connection fails, it keeps retrying with Backoff strategy from TimeoutConfig.
+	// If the connection fails, it keeps retrying according to the reconnect strategy.
+	// By default, the back-off parameters from TimeoutConfig are used.
 	//
 	// For example:
 	//	client.StartWithRetries("ws://localhost:8887/ws/1234")
 	//
-	// The function returns only when the connection has been established.
+	// The function returns only when the connection has been established, or when the reconnect strategy gave up.
 	// Incoming messages are passed automatically to the callback function, so no explicit read operation is required.
 	//
 	// To stop a running client, call the Stop function.
 	StartWithRetries(url string)
+	// StartWithFailover starts the client and attempts to connect to one out of several server endpoints,
+	// ordered by priority. Refer to FailoverConfig for the rotation rules.
+	//
+	// Like StartWithRetries, the function returns only when the connection has been established.
+	// Automatic reconnections are performed according to the same rules.
+	// An error is returned only if the configuration is invalid.
+	StartWithFailover(config FailoverConfig) error
+	// ActiveEndpoint returns the endpoint the client is currently connected to, or attempting to connect to,
+	// along with its index within the failover configuration.
+	//
+	// If the client wasn't started via StartWithFailover, the function returns false.
+	ActiveEndpoint() (ServerEndpoint, int, bool)
 	// Stop closes the output of the websocket Channel, effectively closing the connection to the server with a normal closure.
 	Stop()
 	// Errors returns a channel for error messages. If it doesn't exist it es created.
@@ -85,8 +100,11 @@ type Client interface {
 	SetMessageHandler(handler func(data []byte) error)
 	// Set custom timeout configuration parameters. If not passed, a default ClientTimeoutConfig struct will be used.
 	//
-	// This function must be called before connecting to the server, otherwise it may lead to unexpected behavior.
+	// If the client is connected, the ping period, pong wait and write wait are applied to the open connection immediately.
+	// All other parameters only take effect for future connections.
 	SetTimeoutConfig(config ClientTimeoutConfig)
+	// TimeoutConfig returns the current timeout configuration of the client.
+	TimeoutConfig() ClientTimeoutConfig
 	// Sets a callback function for receiving notifications about an unexpected disconnection from the server.
 	// The callback is invoked even if the automatic reconnection mechanism is active.
 	//
@@ -118,22 +136,61 @@ type Client interface {
 	//
 	// The function overwrites previous header fields with the same key.
 	SetHeaderValue(key string, value string)
+	// SetSecurityProfile sets the OCPP security profile to enforce when connecting to the server.
+	// Refer to WithClientSecurityProfile for more information.
+	//
+	// The profile must be set before starting the client.
+	SetSecurityProfile(profile SecurityProfile)
+	// Reconnect closes the current connection and immediately opens a new connection to the same URL.
+	// This is useful to apply new credentials, such as a renewed client certificate or new basic auth credentials.
+	//
+	// If the new connection couldn't be established, an error is returned and the automatic reconnection mechanism takes over.
+	// If the client is already attempting to reconnect automatically, the next attempt is performed immediately
+	// and the function returns without waiting for its result.
+	//
+	// The disconnected and reconnected handlers are invoked as for any other reconnection.
+	Reconnect() error
+	// SetReconnectStrategy sets the strategy used for automatic reconnections.
+	// Refer to WithReconnectStrategy for more information.
+	SetReconnectStrategy(strategy ReconnectStrategy)
+	// SetConnectionStateHandler sets a callback function, which is invoked whenever the connection state changes.
+	// Refer to ConnectionState for the possible states.
+	//
+	// The callback is invoked synchronously by the client and should return quickly.
+	SetConnectionStateHandler(handler func(event ConnectionStateEvent))
+	// ConnectionState returns the most recent connection state event.
+	ConnectionState() ConnectionStateEvent
 }
 
 // client is the default implementation of a Websocket client.
 //
 // Use the NewClient function to create a new client.
 type client struct {
-	webSocket      *webSocket
-	url            url.URL
-	messageHandler func(data []byte) error
-	dialOptions    []func(*websocket.Dialer)
-	header         http.Header
-	timeoutConfig  ClientTimeoutConfig
-	onDisconnected func(err error)
-	onReconnected  func()
-	errC           chan error
-	reconnectC     chan struct{} // used for signaling, that a reconnection attempt should be interrupted
+	webSocket           *webSocket
+	url                 url.URL
+	messageHandler      func(data []byte) error
+	dialOptions         []func(*websocket.Dialer)
+	header              http.Header
+	timeoutConfig       ClientTimeoutConfig
+	compression         CompressionConfig
+	securityProfile     SecurityProfile
+	certificateProvider CertificateProvider
+	netDialContext      NetDialContextFunc
+	proxy               func(*http.Request) (*url.URL, error)
+	proxyConfig         ProxyConfig
+	onDisconnected      func(err error)
+	onReconnected       func()
+	errC                chan error
+	reconnectC          chan struct{} // used for signaling, that a reconnection attempt should be interrupted
+	reconnectNowC       chan struct{} // used for signaling, that a pending reconnection attempt should be performed immediately
+	closedC             chan struct{} // used for signaling, that the connection was closed for a manual reconnection
+	autoReconnecting    bool
+	failover            *failoverState
+	reconnectStrategy   ReconnectStrategy
+	onStateChanged      func(event ConnectionStateEvent)
+	state               ConnectionStateEvent
+	connectionCount     uint64
+	mutex               sync.Mutex
 }
 
 // ClientOpt is a function that can be used to set options on a client during creation.
@@ -182,6 +239,7 @@ func NewClient(opts ...ClientOpt) Client {
 		dialOptions:   []func(*websocket.Dialer){},
 		timeoutConfig: NewClientTimeoutConfig(),
 		reconnectC:    make(chan struct{}, 1),
+		reconnectNowC: make(chan struct{}, 1),
 		header:        http.Header{},
 	}
 	for _, o := range opts {
@@ -195,7 +253,30 @@ func (c *client) SetMessageHandler(handler func(data []byte) error) {
 }
 
 func (c *client) SetTimeoutConfig(config ClientTimeoutConfig) {
+	c.mutex.Lock()
 	c.timeoutConfig = config
+	ws := c.webSocket
+	c.mutex.Unlock()
+	if ws != nil && ws.IsConnected() {
+		ws.SetTimeoutConfig(ChannelTimeoutConfig{
+			WriteWait:  config.WriteWait,
+			PingPeriod: config.PingPeriod,
+			PongWait:   config.PongWait,
+		})
+	}
+}
+
+func (c *client) TimeoutConfig() ClientTimeoutConfig {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.timeoutConfig
+}
+
+// getWebSocket returns the current websocket, which may be nil if the client never connected.
+func (c *client) getWebSocket() *webSocket {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.webSocket
 }
 
 func (c *client) SetDisconnectedHandler(handler func(err error)) {
@@ -237,28 +318,68 @@ func (c *client) SetHeaderValue(key string, value string) {
 	c.header.Set(key, value)
 }
 
+func (c *client) SetSecurityProfile(profile SecurityProfile) {
+	c.securityProfile = profile
+}
+
+// applySecurityProfile verifies that the client configuration satisfies the security profile and restricts the TLS configuration accordingly.
+func (c *client) applySecurityProfile(u *url.URL, dialer *websocket.Dialer, header http.Header) error {
+	p := c.securityProfile
+	id := path.Base(u.Path)
+	username, _, ok := (&http.Request{Header: header}).BasicAuth()
+	if err := p.checkBasicAuthIdentity(id, username, ok); err != nil {
+		return err
+	}
+	if !p.requiresTLS() {
+		return nil
+	}
+	if u.Scheme != "wss" {
+		return fmt.Errorf("%w: %v requires a wss URL", ErrSecurityProfileViolation, p)
+	}
+	dialer.TLSClientConfig = p.hardenTLSConfig(dialer.TLSClientConfig, false)
+	if p == SecurityProfile3 && len(dialer.TLSClientConfig.Certificates) == 0 && dialer.TLSClientConfig.GetClientCertificate == nil {
+		return fmt.Errorf("%w: %v requires a client certificate", ErrSecurityProfileViolation, p)
+	}
+	return nil
+}
+
 func (c *client) getReadTimeout() time.Time {
-	if c.timeoutConfig.PongWait == 0 {
+	pongWait := c.TimeoutConfig().PongWait
+	if pongWait == 0 {
 		return time.Time{}
 	}
-	return time.Now().Add(c.timeoutConfig.PongWait)
+	return time.Now().Add(pongWait)
 }
 
-func (c *client) handleReconnection() {
+// handleReconnection keeps attempting to reconnect to the server, according to the reconnect strategy.
+// lastErr and lastReason describe why the previous connection was lost or couldn't be established.
+func (c *client) handleReconnection(lastErr error, lastReason *DisconnectReason) {
 	log.Info("started automatic reconnection handler")
-	delay := c.timeoutConfig.RetryBackOffWaitMinimum + time.Duration(rand.Intn(c.timeoutConfig.RetryBackOffRandomRange+1))*time.Second
+	c.setAutoReconnecting(true)
+	defer c.setAutoReconnecting(false)
+	strategy := c.getReconnectStrategy()
 	reconnectionAttempts := 1
 	for {
+		delay, ok := strategy.NextDelay(reconnectionAttempts, lastErr)
+		if !ok {
+			log.Infof("giving up reconnection after %d attempts", reconnectionAttempts-1)
+			c.setState(ConnectionStateEvent{State: ConnectionStateGaveUp, Attempt: reconnectionAttempts - 1, LastError: lastErr, DisconnectReason: lastReason})
+			return
+		}
+		c.setState(ConnectionStateEvent{State: ConnectionStateBackingOff, Attempt: reconnectionAttempts, Delay: delay, LastError: lastErr, DisconnectReason: lastReason})
 		// Wait before reconnecting
 		select {
 		case <-time.After(delay):
+		case <-c.reconnectNowC:
+			log.Info("reconnection triggered manually")
 		case <-c.reconnectC:
 			log.Info("automatic reconnection aborted")
+			c.setState(ConnectionStateEvent{State: ConnectionStateDisconnected, Attempt: reconnectionAttempts, LastError: lastErr, DisconnectReason: lastReason})
 			return
 		}
 
 		log.Info("reconnecting... attempt", reconnectionAttempts)
-		err := c.Start(c.url.String())
+		err := c.connectAttempt(reconnectionAttempts)
 		if err == nil {
 			// Re-connection was successful
 			log.Info("reconnected successfully to server")
@@ -268,40 +389,109 @@ func (c *client) handleReconnection() {
 			return
 		}
 		c.error(fmt.Errorf("reconnection failed: %w", err))
+		lastErr = err
+		lastReason = newHandshakeDisconnectReason(err)
+		// Attempts are counted across all endpoints, hence switching endpoint doesn't reset the backoff
+		reconnectionAttempts += 1
+	}
+}
+
+func (c *client) setAutoReconnecting(reconnecting bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.autoReconnecting = reconnecting
+}
 
-		if reconnectionAttempts < c.timeoutConfig.RetryBackOffRepeatTimes {
-			// Re-connection failed, double the delay
-			delay *= 2
-			delay += time.Duration(rand.Intn(c.timeoutConfig.RetryBackOffRandomRange+1)) * time.Second
+func (c *client) Reconnect() error {
+	if c.url.Host == "" {
+		return fmt.Errorf("cannot reconnect, client was never started")
+	}
+	c.mutex.Lock()
+	if c.autoReconnecting {
+		c.mutex.Unlock()
+		// Skip the backoff delay of the pending attempt
+		select {
+		case c.reconnectNowC <- struct{}{}:
+		default:
 		}
-		reconnectionAttempts += 1
+		return nil
+	}
+	var closedC chan struct{}
+	ws := c.webSocket
+	if ws != nil && ws.IsConnected() {
+		closedC = make(chan struct{})
+		c.closedC = closedC
 	}
+	writeWait := c.timeoutConfig.WriteWait
+	c.mutex.Unlock()
+	if closedC != nil {
+		log.Info("closing connection to server for reconnection")
+		err := ws.Close(websocket.CloseError{Code: websocket.CloseNormalClosure, Text: "reconnecting"})
+		if err == nil {
+			// Closing the connection is bounded by the write timeout
+			select {
+			case <-closedC:
+			case <-time.After(writeWait + time.Second):
+				return fmt.Errorf("timeout while closing connection for reconnection")
+			}
+		}
+	}
+	if err := c.connectAttempt(0); err != nil {
+		c.error(fmt.Errorf("reconnection failed: %w", err))
+		go c.handleReconnection(err, newHandshakeDisconnectReason(err))
+		return err
+	}
+	log.Info("reconnected successfully to server")
+	if c.onReconnected != nil {
+		c.onReconnected()
+	}
+	return nil
 }
 
 func (c *client) IsConnected() bool {
-	if c.webSocket == nil {
+	ws := c.getWebSocket()
+	if ws == nil {
 		return false
 	}
-	return c.webSocket.IsConnected()
+	return ws.IsConnected()
 }
 
 func (c *client) Write(data []byte) error {
-	if !c.IsConnected() {
+	ws := c.getWebSocket()
+	if ws == nil || !ws.IsConnected() {
 		return fmt.Errorf("client is currently not connected, cannot send data")
 	}
 	log.Debugf("queuing data for server")
-	return c.webSocket.Write(data)
+	return ws.Write(data)
 }
 
 func (c *client) StartWithRetries(urlStr string) {
 	err := c.Start(urlStr)
 	if err != nil {
 		log.Info("Connection error:", err)
-		c.handleReconnection()
+		c.handleReconnection(err, newHandshakeDisconnectReason(err))
 	}
 }
 
 func (c *client) Start(urlStr string) error {
+	c.mutex.Lock()
+	c.failover = nil
+	c.mutex.Unlock()
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		return err
+	}
+	c.url = *u
+	if err = c.connectAttempt(0); err != nil {
+		c.setState(ConnectionStateEvent{State: ConnectionStateDisconnected, LastError: err, DisconnectReason: newHandshakeDisconnectReason(err)})
+		return err
+	}
+	return nil
+}
+
+// connect attempts to open a connection to the passed URL.
+// If an endpoint is passed, its TLS configuration and credentials take precedence over the client configuration.
+func (c *client) connect(urlStr string, endpoint *ServerEndpoint) error {
 	u, err := url.Parse(urlStr)
 	if err != nil {
 		return err
@@ -311,18 +501,54 @@ func (c *client) Start(urlStr string) error {
 		c.reconnectC = make(chan struct{}, 1)
 	}
 
+	timeoutConfig := c.TimeoutConfig()
 	dialer := websocket.Dialer{
 		ReadBufferSize:   1024,
 		WriteBufferSize:  1024,
-		HandshakeTimeout: c.timeoutConfig.HandshakeTimeout,
+		HandshakeTimeout: timeoutConfig.HandshakeTimeout,
 		Subprotocols:     []string{},
 	}
 	for _, option := range c.dialOptions {
 		option(&dialer)
 	}
+	header := c.header
+	if endpoint != nil {
+		if endpoint.TLSConfig != nil {
+			dialer.TLSClientConfig = endpoint.TLSConfig
+		}
+		header = endpoint.basicAuthHeader(header)
+	}
+	if c.certificateProvider != nil {
+		dialer.TLSClientConfig = withClientCertificate(dialer.TLSClientConfig, c.certificateProvider)
+	}
+	if err = c.applySecurityProfile(u, &dialer, header); err != nil {
+		return err
+	}
+	dialer.EnableCompression = c.compression.Enabled
+	// Keep track of network traffic for the connection
+	var netConn *countingConn
+	netDial := dialer.NetDialContext
+	if c.netDialContext != nil {
+		netDial = c.netDialContext
+	} else if netDial == nil && dialer.NetDial != nil {
+		dial := dialer.NetDial
+		netDial = func(_ context.Context, network, addr string) (net.Conn, error) {
+			return dial(network, addr)
+		}
+	} else if netDial == nil {
+		netDial = (&net.Dialer{}).DialContext
+	}
+	if c.proxy != nil {
+		// Tunnel connections through the proxy, instead of relying on the dialer's own proxy support
+		netDial = proxyDialer(netDial, *u, c.proxy, c.proxyConfig)
+		dialer.Proxy = nil
+	}
+	dialer.NetDialContext = countingDialer(netDial, func(cc *countingConn) {
+		netConn = cc
+	})
 	// Connect
 	log.Info("connecting to server")
-	ws, resp, err := dialer.Dial(urlStr, c.header)
+	ws, resp, err := dialer.Dial(urlStr, header)
 	if err != nil {
 		if resp != nil {
 			httpError := HttpConnectionError{Message: err.Error(), HttpStatus: resp.Status, HttpCode: resp.StatusCode}
@@ -337,37 +563,58 @@ func (c *client) Start(urlStr string) error {
 		return err
 	}
 
+	// The TLS state is not exposed on the handshake response, hence it is read from the connection directly
+	tlsState := resp.TLS
+	if tlsConn, ok := ws.UnderlyingConn().(*tls.Conn); ok && tlsState == nil {
+		state := tlsConn.ConnectionState()
+		tlsState = &state
+	}
+	if err = c.securityProfile.checkTLSConnectionState(tlsState); err != nil {
+		_ = ws.Close()
+		return err
+	}
+
 	// The id of the charge point is the final path element
 	id := path.Base(u.Path)
 
 	// Create web socket, state is automatically set to connected
-	c.webSocket = newWebSocket(
+	wsConfig := NewDefaultWebSocketConfig(
+		timeoutConfig.WriteWait,
+		0,
+		timeoutConfig.PingPeriod,
+		timeoutConfig.PongWait,
+	)
+	wsConfig.ReadLimit = timeoutConfig.ReadLimit
+	wsConfig.Compression = c.compression
+	wsConfig.SecurityProfile = c.securityProfile
+	w := newWebSocket(
 		id,
 		ws,
-		resp.TLS,
-		NewDefaultWebSocketConfig(
-			c.timeoutConfig.WriteWait,
-			0,
-			c.timeoutConfig.PingPeriod,
-			c.timeoutConfig.PongWait,
-		),
+		tlsState,
+		wsConfig,
 		c.handleMessage,
 		c.handleDisconnect,
 		func(_ Channel, err error) {
 			c.error(err)
 		},
 	)
+	w.setNetworkInfo(dialer.EnableCompression && isCompressionNegotiated(resp.Header), netConn)
+	c.mutex.Lock()
+	w.reconnects = c.connectionCount
+	c.connectionCount++
+	c.webSocket = w
+	c.mutex.Unlock()
 	log.Infof("connected to server as %s", id)
 	// Start reader and write routine
-	c.webSocket.run()
+	w.run()
 	return nil
 }
 
 func (c *client) Stop() {
 	log.Infof("closing connection to server")
-	if c.IsConnected() {
+	if ws := c.getWebSocket(); ws != nil && ws.IsConnected() {
 		// Attempt to gracefully shut down the connection
-		err := c.webSocket.Close(websocket.CloseError{Code: websocket.CloseNormalClosure, Text: ""})
+		err := ws.Close(websocket.CloseError{Code: websocket.CloseNormalClosure, Text: ""})
 		if err != nil {
 			c.error(err)
 		}
@@ -410,14 +657,24 @@ func (c *client) handleMessage(_ Channel, data []byte) error {
 	return fmt.Errorf("no message handler set")
 }
 
-func (c *client) handleDisconnect(_ Channel, err error) {
+func (c *client) handleDisconnect(w Channel, err error) {
+	c.setState(ConnectionStateEvent{State: ConnectionStateDisconnected, LastError: err, DisconnectReason: w.DisconnectReason()})
 	if c.onDisconnected != nil {
 		// Notify upper layer of disconnect
 		c.onDisconnected(err)
 	}
+	c.mutex.Lock()
+	closedC := c.closedC
+	c.closedC = nil
+	c.mutex.Unlock()
+	if closedC != nil {
+		// Connection was closed for a manual reconnection
+		close(closedC)
+		return
+	}
 	if err != nil {
 		// Disconnect was forced, do reconnect
-		c.handleReconnection()
+		c.handleReconnection(err, w.DisconnectReason())
 	}
 }
 
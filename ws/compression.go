@@ -0,0 +1,233 @@
+package ws
+
+import (
+	"compress/flate"
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// Compression level used by default, favoring speed over compression ratio.
+	defaultCompressionLevel = flate.BestSpeed
+	// Messages smaller than this size (in bytes) are not compressed by default,
+	// since the deflate overhead would likely outweigh the gains.
+	defaultCompressionThreshold = 256
+	// Valid compression levels, as supported by the underlying websocket library.
+	minCompressionLevel = flate.HuffmanOnly
+	maxCompressionLevel = flate.BestCompression
+	// Name of the websocket extension used for compressing messages.
+	permessageDeflateExtension = "permessage-deflate"
+)
+
+// CompressionConfig contains the configuration parameters for the permessage-deflate websocket extension (RFC 7692).
+//
+// To enable compression, refer to the WithServerCompression and WithClientCompression options.
+// A default configuration may be created via the NewCompressionConfig function.
+type CompressionConfig struct {
+	Enabled   bool // If true, the permessage-deflate extension is negotiated with the peer during the handshake. Compression is only used if both endpoints support it.
+	Level     int  // The flate compression level to apply to outgoing messages. Valid levels range from flate.HuffmanOnly (-2) to flate.BestCompression (9).
+	Threshold int  // The minimum payload size in bytes, for an outgoing message to be compressed. Smaller messages are sent uncompressed. If set to 0, all messages are compressed.
+}
+
+// NewCompressionConfig creates a default compression configuration, with compression enabled.
+//
+// You may change fields arbitrarily and pass the struct to the WithServerCompression or WithClientCompression options.
+func NewCompressionConfig() CompressionConfig {
+	return CompressionConfig{
+		Enabled:   true,
+		Level:     defaultCompressionLevel,
+		Threshold: defaultCompressionThreshold,
+	}
+}
+
+func (c CompressionConfig) isValidLevel() bool {
+	return c.Level >= minCompressionLevel && c.Level <= maxCompressionLevel
+}
+
+// WithServerCompression enables the negotiation of the permessage-deflate extension on the server.
+// Compression will be used on all channels, for which the client supports the extension as well.
+//
+// Compression may be disabled on an individual channel via the SetCompressionEnabled method.
+func WithServerCompression(config CompressionConfig) ServerOpt {
+	return func(s *server) {
+		s.compression = config
+		s.upgrader.EnableCompression = config.Enabled
+	}
+}
+
+// WithClientCompression enables the negotiation of the permessage-deflate extension on the client.
+// Compression will be used only if the server supports the extension as well.
+func WithClientCompression(config CompressionConfig) ClientOpt {
+	return func(c *client) {
+		c.compression = config
+	}
+}
+
+// CompressionStats contains statistics about the messages exchanged on a single channel.
+//
+// Payload sizes refer to the uncompressed application messages, while wire sizes refer to the
+// raw bytes transmitted over the network connection (including websocket framing and, if used, TLS overhead).
+// The wire sizes are therefore an approximation of the effectively compressed data.
+type CompressionStats struct {
+	Negotiated         bool   // True, if the permessage-deflate extension was negotiated during the handshake.
+	MessagesCompressed uint64 // The amount of outgoing messages, which were sent compressed.
+	PayloadBytesIn     uint64 // The total size of all received messages, after decompression.
+	PayloadBytesOut    uint64 // The total size of all sent messages, before compression.
+	WireBytesIn        uint64 // The total amount of bytes read from the network connection.
+	WireBytesOut       uint64 // The total amount of bytes written to the network connection.
+}
+
+// IncomingRatio returns the ratio between bytes read from the network and the received payload.
+// A value lower than 1 indicates that the incoming data was effectively compressed.
+//
+// If no data was received or no network statistics are available, 0 is returned.
+func (s CompressionStats) IncomingRatio() float64 {
+	if s.PayloadBytesIn == 0 || s.WireBytesIn == 0 {
+		return 0
+	}
+	return float64(s.WireBytesIn) / float64(s.PayloadBytesIn)
+}
+
+// OutgoingRatio returns the ratio between bytes written to the network and the sent payload.
+// A value lower than 1 indicates that the outgoing data was effectively compressed.
+//
+// If no data was sent or no network statistics are available, 0 is returned.
+func (s CompressionStats) OutgoingRatio() float64 {
+	if s.PayloadBytesOut == 0 || s.WireBytesOut == 0 {
+		return 0
+	}
+	return float64(s.WireBytesOut) / float64(s.PayloadBytesOut)
+}
+
+// isCompressionNegotiated checks whether the permessage-deflate extension was accepted during the handshake.
+func isCompressionNegotiated(header http.Header) bool {
+	for _, value := range header.Values("Sec-Websocket-Extensions") {
+		for _, ext := range strings.Split(value, ",") {
+			name := strings.TrimSpace(strings.Split(ext, ";")[0])
+			if strings.EqualFold(name, permessageDeflateExtension) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// countingConn wraps a net.Conn and keeps track of the raw bytes read from and written to the connection.
+type countingConn struct {
+	net.Conn
+	bytesIn  uint64
+	bytesOut uint64
+}
+
+func newCountingConn(conn net.Conn) *countingConn {
+	return &countingConn{Conn: conn}
+}
+
+func (c *countingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	atomic.AddUint64(&c.bytesIn, uint64(n))
+	return n, err
+}
+
+func (c *countingConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	atomic.AddUint64(&c.bytesOut, uint64(n))
+	return n, err
+}
+
+// reset clears the counters, e.g. once the websocket handshake is completed.
+func (c *countingConn) reset() {
+	atomic.StoreUint64(&c.bytesIn, 0)
+	atomic.StoreUint64(&c.bytesOut, 0)
+}
+
+func (c *countingConn) BytesIn() uint64 {
+	return atomic.LoadUint64(&c.bytesIn)
+}
+
+func (c *countingConn) BytesOut() uint64 {
+	return atomic.LoadUint64(&c.bytesOut)
+}
+
+// countingListener wraps a net.Listener, so that every accepted connection is a countingConn.
+type countingListener struct {
+	net.Listener
+}
+
+func (l countingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return newCountingConn(conn), nil
+}
+
+// countingTLSListener wraps a net.Listener and performs the TLS handshake on top of a countingConn,
+// so that the counted bytes include the TLS overhead.
+//
+// The http.Server needs to receive the *tls.Conn itself, in order to populate the TLS state of requests.
+// The underlying countingConn is therefore tracked separately and retrieved via connContext.
+type countingTLSListener struct {
+	net.Listener
+	config *tls.Config
+	conns  sync.Map // *tls.Conn -> *countingConn
+}
+
+func newCountingTLSListener(listener net.Listener, config *tls.Config) *countingTLSListener {
+	return &countingTLSListener{Listener: listener, config: config}
+}
+
+func (l *countingTLSListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	cc := newCountingConn(conn)
+	tlsConn := tls.Server(cc, l.config)
+	l.conns.Store(tlsConn, cc)
+	return tlsConn, nil
+}
+
+// connContext stores the countingConn underlying a TLS connection within a context. To be used as http.Server.ConnContext.
+func (l *countingTLSListener) connContext(ctx context.Context, conn net.Conn) context.Context {
+	if cc, ok := l.conns.Load(conn); ok {
+		l.conns.Delete(conn)
+		return context.WithValue(ctx, countingConnKey{}, cc.(*countingConn))
+	}
+	return ctx
+}
+
+type countingConnKey struct{}
+
+// withCountingConn stores a countingConn within a context. To be used as http.Server.ConnContext.
+func withCountingConn(ctx context.Context, conn net.Conn) context.Context {
+	if cc, ok := conn.(*countingConn); ok {
+		return context.WithValue(ctx, countingConnKey{}, cc)
+	}
+	return ctx
+}
+
+// countingConnFromContext retrieves a countingConn from a context, if any.
+func countingConnFromContext(ctx context.Context) *countingConn {
+	cc, _ := ctx.Value(countingConnKey{}).(*countingConn)
+	return cc
+}
+
+// countingDialer wraps a dial function, so that every dialed connection is a countingConn.
+// The last dialed connection is passed to the onDial callback.
+func countingDialer(dial func(ctx context.Context, network, addr string) (net.Conn, error), onDial func(cc *countingConn)) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		cc := newCountingConn(conn)
+		onDial(cc)
+		return cc, nil
+	}
+}
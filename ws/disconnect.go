@@ -0,0 +1,129 @@
+package ws
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/gorilla/websocket"
+)
+
+// DisconnectInitiator describes which side of a channel caused the disconnection.
+type DisconnectInitiator int
+
+const (
+	// DisconnectInitiatorLocal means that the channel was closed by this endpoint,
+	// either on request of the application or because of a timeout (e.g. a missing pong).
+	DisconnectInitiatorLocal DisconnectInitiator = iota
+	// DisconnectInitiatorRemote means that the channel was closed by the peer, or that the connection was dropped by the network.
+	DisconnectInitiatorRemote
+)
+
+func (i DisconnectInitiator) String() string {
+	switch i {
+	case DisconnectInitiatorLocal:
+		return "local"
+	case DisconnectInitiatorRemote:
+		return "remote"
+	default:
+		return fmt.Sprintf("unknown disconnect initiator %d", int(i))
+	}
+}
+
+// DisconnectReason describes why a channel was closed, or why a connection couldn't be established.
+//
+// Some common cases can be told apart as follows:
+//   - normal closure: Code is websocket.CloseNormalClosure and Err is nil
+//   - ping/pong timeout: Timeout returns true
+//   - policy violation (e.g. duplicate connection): Code is websocket.ClosePolicyViolation
+//   - TCP drop: Code is websocket.CloseAbnormalClosure and Err contains the network error
+//   - failed connection attempt: Handshake is true and Err contains the dial, TLS handshake or upgrade error.
+//     If the server rejected the upgrade request, Err is an HttpConnectionError and Text contains the HTTP status.
+type DisconnectReason struct {
+	// The close code, as defined by RFC 6455.
+	// If the connection was dropped without a close handshake, the code is websocket.CloseAbnormalClosure.
+	Code int
+	// The text contained in the close frame, if any.
+	Text string
+	// Whether the channel was closed by this endpoint or by the peer.
+	Initiator DisconnectInitiator
+	// The underlying network error, which caused the disconnection. Nil if the channel was closed via a regular close handshake.
+	Err error
+	// Whether the connection couldn't be established in the first place,
+	// i.e. the TCP connection, the TLS handshake or the HTTP upgrade failed.
+	Handshake bool
+}
+
+// Timeout returns true if the channel was closed because of a read or write timeout, e.g. after a missing pong.
+func (r *DisconnectReason) Timeout() bool {
+	var netErr net.Error
+	return r.Err != nil && errors.As(r.Err, &netErr) && netErr.Timeout()
+}
+
+func (r *DisconnectReason) String() string {
+	s := fmt.Sprintf("closed by %v side with code %d", r.Initiator, r.Code)
+	if r.Handshake {
+		s = fmt.Sprintf("handshake failed on %v side", r.Initiator)
+	}
+	if r.Text != "" {
+		s = fmt.Sprintf("%s (%s)", s, r.Text)
+	}
+	if r.Err != nil {
+		s = fmt.Sprintf("%s: %v", s, r.Err)
+	}
+	return s
+}
+
+// newLocalDisconnectReason creates the reason for a channel, which was closed by the application.
+// writeErr contains the error that occurred while sending the close frame, if any.
+func newLocalDisconnectReason(closeErr websocket.CloseError, writeErr error) *DisconnectReason {
+	return &DisconnectReason{Code: closeErr.Code, Text: closeErr.Text, Initiator: DisconnectInitiatorLocal, Err: writeErr}
+}
+
+// newForcedDisconnectReason creates the reason for a channel, which was closed because of a failed read or write operation.
+func newForcedDisconnectReason(err error) *DisconnectReason {
+	var closeErr *websocket.CloseError
+	if errors.As(err, &closeErr) {
+		reason := &DisconnectReason{Code: closeErr.Code, Text: closeErr.Text, Initiator: DisconnectInitiatorRemote}
+		if closeErr.Code == websocket.CloseAbnormalClosure {
+			// No close frame was received, the connection was dropped
+			reason.Err = err
+		}
+		return reason
+	}
+	if errors.Is(err, websocket.ErrReadLimit) {
+		return &DisconnectReason{Code: websocket.CloseMessageTooBig, Initiator: DisconnectInitiatorLocal, Err: err}
+	}
+	reason := &DisconnectReason{Code: websocket.CloseAbnormalClosure, Initiator: DisconnectInitiatorRemote, Err: err}
+	if reason.Timeout() {
+		reason.Initiator = DisconnectInitiatorLocal
+	}
+	return reason
+}
+
+// newHandshakeDisconnectReason creates the reason for a connection, which couldn't be established.
+// Failures caused by this endpoint (timeouts, untrusted server certificates, security profile violations) are considered local.
+func newHandshakeDisconnectReason(err error) *DisconnectReason {
+	reason := &DisconnectReason{Code: websocket.CloseAbnormalClosure, Initiator: DisconnectInitiatorRemote, Err: err, Handshake: true}
+	var httpErr HttpConnectionError
+	if errors.As(err, &httpErr) {
+		// The server rejected the upgrade request
+		reason.Text = httpErr.HttpStatus
+		return reason
+	}
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	if reason.Timeout() || errors.Is(err, ErrSecurityProfileViolation) ||
+		errors.As(err, &unknownAuthorityErr) || errors.As(err, &hostnameErr) || errors.As(err, &invalidErr) {
+		reason.Initiator = DisconnectInitiatorLocal
+	}
+	return reason
+}
+
+func (w *webSocket) DisconnectReason() *DisconnectReason {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	return w.disconnectReason
+}
@@ -0,0 +1,165 @@
+package ws
+
+import (
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// ServerEndpoint describes a single server, which a client may connect to.
+//
+// Each endpoint may carry its own TLS configuration and basic auth credentials.
+// If omitted, the TLS configuration and credentials of the client are used.
+type ServerEndpoint struct {
+	// The URL of the websocket server.
+	//
+	// When used directly on a ws.Client, this is the full URL, e.g. wss://csms.example.com/ocpp/CS001.
+	// When passed to ocppj.Client or the OCPP facades, this is the base URL without the client identifier,
+	// e.g. wss://csms.example.com/ocpp, as the identifier is appended automatically.
+	URL string
+	// Optional TLS configuration to use for this endpoint. Overrides the TLS configuration of the client.
+	TLSConfig *tls.Config
+	// Optional basic auth credentials to use for this endpoint. Override the credentials set via SetBasicAuth.
+	Username string
+	Password string
+}
+
+// FailoverConfig contains the configuration for connecting to one out of several server endpoints.
+//
+// Endpoints are attempted in order of priority. Once an endpoint failed too many consecutive times,
+// the client switches to the next endpoint. After the last endpoint, the client starts over from the first one.
+//
+// The reconnect strategy of the client applies to all attempts across endpoints: switching to another endpoint
+// neither resets the backoff delay, nor the attempt count after which the client gives up.
+type FailoverConfig struct {
+	// The endpoints to connect to, ordered by priority. At least one endpoint is required.
+	Endpoints []ServerEndpoint
+	// The number of consecutive failed connection attempts, after which the client switches to the next endpoint.
+	// If zero or negative, the client switches after every failed attempt.
+	MaxAttemptsPerEndpoint int
+	// Optional callback, invoked whenever the client connected to a different endpoint than before.
+	// The endpoint URL passed to the callback is the full URL, including the client identifier if appended.
+	OnEndpointChanged func(endpoint ServerEndpoint, index int)
+}
+
+// failoverState keeps track of the active endpoint and of the failed attempts on it.
+type failoverState struct {
+	config         FailoverConfig
+	index          int
+	failures       int
+	connectedIndex int
+	mutex          sync.Mutex
+}
+
+func newFailoverState(config FailoverConfig) (*failoverState, error) {
+	if len(config.Endpoints) == 0 {
+		return nil, fmt.Errorf("failover configuration requires at least one endpoint")
+	}
+	if config.MaxAttemptsPerEndpoint <= 0 {
+		config.MaxAttemptsPerEndpoint = 1
+	}
+	return &failoverState{config: config, connectedIndex: -1}, nil
+}
+
+// current returns the endpoint to use for the next connection attempt.
+func (f *failoverState) current() (ServerEndpoint, int) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.config.Endpoints[f.index], f.index
+}
+
+// recordFailure keeps track of a failed connection attempt.
+// Returns true, if the client switched to the next endpoint.
+func (f *failoverState) recordFailure() bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.failures++
+	if f.failures < f.config.MaxAttemptsPerEndpoint {
+		return false
+	}
+	f.failures = 0
+	f.index = (f.index + 1) % len(f.config.Endpoints)
+	return len(f.config.Endpoints) > 1
+}
+
+// recordSuccess resets the failure count, after successfully connecting to the current endpoint.
+// Returns true, if the endpoint differs from the previously connected one.
+func (f *failoverState) recordSuccess() bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.failures = 0
+	changed := f.connectedIndex != f.index
+	f.connectedIndex = f.index
+	return changed
+}
+
+// basicAuthHeader returns a copy of the passed header, including the endpoint credentials (if any).
+func (e ServerEndpoint) basicAuthHeader(header http.Header) http.Header {
+	if e.Username == "" && e.Password == "" {
+		return header
+	}
+	result := header.Clone()
+	result.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(e.Username+":"+e.Password)))
+	return result
+}
+
+func (c *client) StartWithFailover(config FailoverConfig) error {
+	failover, err := newFailoverState(config)
+	if err != nil {
+		return err
+	}
+	c.mutex.Lock()
+	c.failover = failover
+	c.mutex.Unlock()
+	if err = c.connectAttempt(0); err != nil {
+		log.Info("Connection error:", err)
+		reason := newHandshakeDisconnectReason(err)
+		c.setState(ConnectionStateEvent{State: ConnectionStateDisconnected, LastError: err, DisconnectReason: reason})
+		c.handleReconnection(err, reason)
+	}
+	return nil
+}
+
+func (c *client) ActiveEndpoint() (ServerEndpoint, int, bool) {
+	c.mutex.Lock()
+	failover := c.failover
+	c.mutex.Unlock()
+	if failover == nil {
+		return ServerEndpoint{}, -1, false
+	}
+	endpoint, index := failover.current()
+	return endpoint, index, true
+}
+
+// connectAttempt performs a single connection attempt, either to the URL passed to Start,
+// or to the active endpoint if failover is configured.
+// The attempt number is only used for notifying connection state changes.
+func (c *client) connectAttempt(attempt int) error {
+	c.setState(ConnectionStateEvent{State: ConnectionStateConnecting, Attempt: attempt})
+	c.mutex.Lock()
+	failover := c.failover
+	c.mutex.Unlock()
+	if failover == nil {
+		err := c.connect(c.url.String(), nil)
+		if err == nil {
+			c.setState(ConnectionStateEvent{State: ConnectionStateConnected, Attempt: attempt})
+		}
+		return err
+	}
+	endpoint, index := failover.current()
+	err := c.connect(endpoint.URL, &endpoint)
+	if err != nil {
+		if failover.recordFailure() {
+			next, nextIndex := failover.current()
+			log.Infof("endpoint %d (%s) unreachable, switching to endpoint %d (%s)", index, endpoint.URL, nextIndex, next.URL)
+		}
+		return err
+	}
+	if failover.recordSuccess() && failover.config.OnEndpointChanged != nil {
+		failover.config.OnEndpointChanged(endpoint, index)
+	}
+	c.setState(ConnectionStateEvent{State: ConnectionStateConnected, Attempt: attempt})
+	return nil
+}
@@ -0,0 +1,405 @@
+package ws
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// FaultConfig contains the faults injected by a MemoryNetwork into all of its connections.
+// The zero value doesn't inject any faults.
+type FaultConfig struct {
+	// Latency is added to every write operation, including the websocket handshake.
+	Latency time.Duration
+	// DropRate is the probability, in the range [0, 1], for a data message to be silently discarded.
+	// Control frames (ping, pong, close) and the websocket handshake are never dropped.
+	DropRate float64
+	// RefuseConnections makes every dial fail, as if the server was unreachable.
+	// Open connections are not affected.
+	RefuseConnections bool
+}
+
+// MemoryNetwork is an in-memory transport for websocket servers and clients, allowing to wire both endpoints
+// within the same process, without opening any network sockets. This is mostly useful for fast, hermetic tests.
+//
+// Servers and clients created via the network behave exactly like regular ones (handshake, subprotocol negotiation,
+// basic auth, check-client handler, ...), since only the underlying network connection is replaced:
+//
+//	network := ws.NewMemoryNetwork()
+//	centralSystem := ocpp16.NewCentralSystem(nil, network.NewServer())
+//	go centralSystem.Start(8887, "/{ws}")
+//	chargePoint := ocpp16.NewChargePoint("CP-1", nil, network.NewClient())
+//	err := chargePoint.Start("ws://localhost:8887")
+//
+// Servers are addressed by port only: any host passed by a client is ignored.
+//
+// Faults may be injected at any time via SetFaults, while connections may be dropped via Disconnect and DisconnectAll.
+// A forcefully dropped connection is reported as a websocket.CloseAbnormalClosure on both ends.
+type MemoryNetwork struct {
+	listeners map[int]*memoryListener
+	conns     map[*memoryConnPair]struct{}
+	faults    FaultConfig
+	nextPort  int
+	mutex     sync.RWMutex
+}
+
+// NewMemoryNetwork creates a new, empty in-memory network.
+func NewMemoryNetwork() *MemoryNetwork {
+	return &MemoryNetwork{
+		listeners: map[int]*memoryListener{},
+		conns:     map[*memoryConnPair]struct{}{},
+		nextPort:  49152,
+	}
+}
+
+// NewServer creates a websocket server, which listens on the in-memory network when started.
+// Serve may still be invoked with any listener.
+func (n *MemoryNetwork) NewServer(opts ...ServerOpt) Server {
+	opts = append(opts, func(s *server) {
+		s.listen = n.Listen
+	})
+	return NewServer(opts...)
+}
+
+// NewClient creates a websocket client, which connects to servers on the in-memory network.
+func (n *MemoryNetwork) NewClient(opts ...ClientOpt) Client {
+	opts = append([]ClientOpt{WithClientNetDialContext(n.DialContext)}, opts...)
+	return NewClient(opts...)
+}
+
+// Listen creates a listener on the in-memory network. The address must be in the form "host:port".
+// The host is ignored. If the port is 0, a free port is picked.
+func (n *MemoryNetwork) Listen(_, address string) (net.Listener, error) {
+	port, err := memoryPort(address)
+	if err != nil {
+		return nil, err
+	}
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if port == 0 {
+		for n.listeners[n.nextPort] != nil {
+			n.nextPort++
+		}
+		port = n.nextPort
+		n.nextPort++
+	}
+	if _, ok := n.listeners[port]; ok {
+		return nil, fmt.Errorf("listen memory %v: address already in use", address)
+	}
+	l := &memoryListener{
+		network: n,
+		addr:    &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: port},
+		connC:   make(chan net.Conn),
+		closeC:  make(chan struct{}),
+	}
+	n.listeners[port] = l
+	return l, nil
+}
+
+// DialContext opens a connection to a listener on the in-memory network.
+// The signature matches NetDialContextFunc, so the function may be passed to WithClientNetDialContext.
+func (n *MemoryNetwork) DialContext(ctx context.Context, _, address string) (net.Conn, error) {
+	port, err := memoryPort(address)
+	if err != nil {
+		return nil, err
+	}
+	n.mutex.Lock()
+	l, ok := n.listeners[port]
+	refuse := n.faults.RefuseConnections
+	var localAddr *net.TCPAddr
+	if ok && !refuse {
+		localAddr = &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: n.nextPort}
+		n.nextPort++
+	}
+	n.mutex.Unlock()
+	if !ok || refuse {
+		return nil, &net.OpError{Op: "dial", Net: "memory", Err: fmt.Errorf("connection refused to %v", address)}
+	}
+	clientEnd, serverEnd := net.Pipe()
+	pair := &memoryConnPair{}
+	pair.client = &memoryConn{Conn: clientEnd, pair: pair, network: n, localAddr: localAddr, remoteAddr: l.addr}
+	pair.server = &memoryConn{Conn: serverEnd, pair: pair, network: n, localAddr: l.addr, remoteAddr: localAddr}
+	n.mutex.Lock()
+	n.conns[pair] = struct{}{}
+	n.mutex.Unlock()
+	select {
+	case l.connC <- pair.server:
+		return pair.client, nil
+	case <-l.closeC:
+		pair.close()
+		return nil, &net.OpError{Op: "dial", Net: "memory", Err: fmt.Errorf("connection refused to %v", address)}
+	case <-ctx.Done():
+		pair.close()
+		return nil, ctx.Err()
+	}
+}
+
+// SetFaults replaces the faults injected into all connections of the network.
+// The new configuration applies immediately, also to open connections.
+func (n *MemoryNetwork) SetFaults(faults FaultConfig) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.faults = faults
+}
+
+// Faults returns the faults currently injected by the network.
+func (n *MemoryNetwork) Faults() FaultConfig {
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+	return n.faults
+}
+
+// Disconnect forcefully drops the connection with the passed client address, without a close handshake.
+// The client address is the RemoteAddr of the server-side channel.
+//
+// Returns false if no open connection with the passed address exists.
+func (n *MemoryNetwork) Disconnect(addr net.Addr) bool {
+	n.mutex.RLock()
+	var target *memoryConnPair
+	for pair := range n.conns {
+		if pair.client.localAddr.String() == addr.String() {
+			target = pair
+			break
+		}
+	}
+	n.mutex.RUnlock()
+	if target == nil {
+		return false
+	}
+	target.close()
+	return true
+}
+
+// DisconnectAll forcefully drops all open connections, without a close handshake.
+// Returns the number of dropped connections.
+func (n *MemoryNetwork) DisconnectAll() int {
+	n.mutex.RLock()
+	pairs := make([]*memoryConnPair, 0, len(n.conns))
+	for pair := range n.conns {
+		pairs = append(pairs, pair)
+	}
+	n.mutex.RUnlock()
+	for _, pair := range pairs {
+		pair.close()
+	}
+	return len(pairs)
+}
+
+// ConnectionCount returns the number of open connections on the network.
+func (n *MemoryNetwork) ConnectionCount() int {
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+	return len(n.conns)
+}
+
+func (n *MemoryNetwork) removeConn(pair *memoryConnPair) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	delete(n.conns, pair)
+}
+
+func (n *MemoryNetwork) removeListener(l *memoryListener) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if n.listeners[l.addr.Port] == l {
+		delete(n.listeners, l.addr.Port)
+	}
+}
+
+// memoryPort extracts the port out of a "host:port" address.
+func memoryPort(address string) (int, error) {
+	_, p, err := net.SplitHostPort(address)
+	if err != nil {
+		return 0, err
+	}
+	port, err := strconv.Atoi(p)
+	if err != nil {
+		return 0, fmt.Errorf("invalid port in address %v: %w", address, err)
+	}
+	return port, nil
+}
+
+// memoryListener accepts connections dialed on a MemoryNetwork.
+type memoryListener struct {
+	network *MemoryNetwork
+	addr    *net.TCPAddr
+	connC   chan net.Conn
+	closeC  chan struct{}
+	once    sync.Once
+}
+
+func (l *memoryListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.connC:
+		return conn, nil
+	case <-l.closeC:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *memoryListener) Close() error {
+	l.once.Do(func() {
+		close(l.closeC)
+		l.network.removeListener(l)
+	})
+	return nil
+}
+
+func (l *memoryListener) Addr() net.Addr {
+	return l.addr
+}
+
+// memoryConnPair contains both ends of an in-memory connection.
+type memoryConnPair struct {
+	client *memoryConn
+	server *memoryConn
+	once   sync.Once
+}
+
+func (p *memoryConnPair) close() {
+	p.once.Do(func() {
+		_ = p.client.Conn.Close()
+		_ = p.server.Conn.Close()
+		p.client.network.removeConn(p)
+	})
+}
+
+// memoryConn is one end of an in-memory connection, injecting the faults configured on the network.
+// Addresses are reported as TCP addresses, so that the connection is indistinguishable from a regular one.
+type memoryConn struct {
+	net.Conn
+	pair       *memoryConnPair
+	network    *MemoryNetwork
+	localAddr  *net.TCPAddr
+	remoteAddr *net.TCPAddr
+	frames     frameTracker
+	writeMutex sync.Mutex
+}
+
+func (c *memoryConn) Write(b []byte) (int, error) {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+	faults := c.network.Faults()
+	if faults.Latency > 0 {
+		time.Sleep(faults.Latency)
+	}
+	dataFrame := c.frames.isDataFrame(b)
+	c.frames.advance(b)
+	if dataFrame && faults.DropRate > 0 && rand.Float64() < faults.DropRate {
+		// Pretend the message was sent
+		return len(b), nil
+	}
+	return c.Conn.Write(b)
+}
+
+// Close closes both ends of the connection, as it would happen with a real network connection.
+// The peer notices the closed connection on the next read.
+func (c *memoryConn) Close() error {
+	c.pair.close()
+	return nil
+}
+
+func (c *memoryConn) LocalAddr() net.Addr {
+	return c.localAddr
+}
+
+func (c *memoryConn) RemoteAddr() net.Addr {
+	return c.remoteAddr
+}
+
+// frameTracker follows the outgoing byte stream of a connection, in order to tell websocket data frames apart.
+// The stream starts with the HTTP handshake, which is skipped entirely.
+type frameTracker struct {
+	handshakeDone bool
+	handshakeTail []byte
+	// Bytes left of the frame currently being written
+	remaining uint64
+}
+
+var handshakeTerminator = []byte("\r\n\r\n")
+
+// isDataFrame returns true, if the passed write contains exactly one complete, unfragmented text or binary frame.
+// Writes containing partial frames are never considered data frames, since dropping them would corrupt the stream.
+func (t *frameTracker) isDataFrame(b []byte) bool {
+	if !t.handshakeDone || t.remaining > 0 {
+		return false
+	}
+	length, ok := frameLength(b)
+	if !ok || length != uint64(len(b)) {
+		return false
+	}
+	fin := b[0]&0x80 != 0
+	opCode := b[0] & 0x0f
+	return fin && (opCode == 1 || opCode == 2)
+}
+
+// advance moves the tracker past the passed bytes.
+func (t *frameTracker) advance(b []byte) {
+	for len(b) > 0 {
+		if !t.handshakeDone {
+			data := append(t.handshakeTail, b...)
+			i := bytes.Index(data, handshakeTerminator)
+			if i < 0 {
+				// Keep enough bytes to detect a terminator split across writes
+				if len(data) > len(handshakeTerminator) {
+					data = data[len(data)-len(handshakeTerminator):]
+				}
+				t.handshakeTail = append([]byte{}, data...)
+				return
+			}
+			t.handshakeDone = true
+			consumed := i + len(handshakeTerminator) - len(t.handshakeTail)
+			t.handshakeTail = nil
+			b = b[consumed:]
+			continue
+		}
+		if t.remaining == 0 {
+			length, ok := frameLength(b)
+			if !ok {
+				// Frame headers are always written at once, this should never happen
+				return
+			}
+			t.remaining = length
+		}
+		n := uint64(len(b))
+		if n > t.remaining {
+			n = t.remaining
+		}
+		t.remaining -= n
+		b = b[n:]
+	}
+}
+
+// frameLength parses the header at the beginning of b and returns the total length of the frame, including the header.
+func frameLength(b []byte) (uint64, bool) {
+	if len(b) < 2 {
+		return 0, false
+	}
+	headerLength := uint64(2)
+	payloadLength := uint64(b[1] & 0x7f)
+	switch payloadLength {
+	case 126:
+		if len(b) < 4 {
+			return 0, false
+		}
+		headerLength += 2
+		payloadLength = uint64(binary.BigEndian.Uint16(b[2:4]))
+	case 127:
+		if len(b) < 10 {
+			return 0, false
+		}
+		headerLength += 8
+		payloadLength = binary.BigEndian.Uint64(b[2:10])
+	}
+	if b[1]&0x80 != 0 {
+		// Masking key
+		headerLength += 4
+	}
+	return headerLength + payloadLength, true
+}
@@ -0,0 +1,212 @@
+package ws
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// ReconnectStrategy determines how long a client waits before each automatic reconnection attempt,
+// and when it stops trying altogether.
+//
+// Three strategies are provided out of the box: ExponentialBackoff, CappedBackoff and OCPPBackoff.
+// If no strategy is set, the client uses an OCPPBackoff built from its ClientTimeoutConfig.
+type ReconnectStrategy interface {
+	// NextDelay returns the delay to wait before the passed reconnection attempt, starting from 1.
+	// lastErr contains the reason for the previous failure (either the disconnection or the last failed attempt).
+	//
+	// If the function returns false, the client gives up reconnecting.
+	NextDelay(attempt int, lastErr error) (time.Duration, bool)
+}
+
+// ExponentialBackoff multiplies the delay by a constant factor after every failed attempt.
+// The delay is not bounded, refer to CappedBackoff for a bounded variant.
+type ExponentialBackoff struct {
+	// The delay before the first reconnection attempt.
+	InitialDelay time.Duration
+	// The factor the delay is multiplied by after every failed attempt. If lower than 1, a factor of 2 is used.
+	Multiplier float64
+	// The maximum random value to add to every delay. If zero, no jitter is added.
+	Jitter time.Duration
+	// The maximum number of reconnection attempts. If zero or negative, the client never gives up.
+	MaxAttempts int
+}
+
+func (b ExponentialBackoff) NextDelay(attempt int, _ error) (time.Duration, bool) {
+	if b.MaxAttempts > 0 && attempt > b.MaxAttempts {
+		return 0, false
+	}
+	return b.delay(attempt, math.MaxInt64) + jitter(b.Jitter), true
+}
+
+// delay computes the delay for the passed attempt, without jitter, bounded by maxDelay.
+func (b ExponentialBackoff) delay(attempt int, maxDelay time.Duration) time.Duration {
+	multiplier := b.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+	if attempt < 1 {
+		attempt = 1
+	}
+	delay := float64(b.InitialDelay) * math.Pow(multiplier, float64(attempt-1))
+	if delay >= float64(maxDelay) {
+		return maxDelay
+	}
+	return time.Duration(delay)
+}
+
+// CappedBackoff behaves like ExponentialBackoff, but never waits longer than MaxDelay (plus jitter).
+type CappedBackoff struct {
+	ExponentialBackoff
+	// The upper bound for the delay. If zero or negative, the delay is not bounded.
+	MaxDelay time.Duration
+}
+
+func (b CappedBackoff) NextDelay(attempt int, _ error) (time.Duration, bool) {
+	if b.MaxAttempts > 0 && attempt > b.MaxAttempts {
+		return 0, false
+	}
+	maxDelay := b.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = math.MaxInt64
+	}
+	return b.delay(attempt, maxDelay) + jitter(b.Jitter), true
+}
+
+// OCPPBackoff implements the reconnection back-off mechanism described by OCPP 2.0.1
+// (see the RetryBackOffWaitMinimum, RetryBackOffRandomRange and RetryBackOffRepeatTimes configuration variables).
+//
+// The first attempt is performed after WaitMinimum plus a random value of at most RandomRange.
+// After every failed attempt the previous back-off time is doubled, at most RepeatTimes times,
+// and a new random value is added. The client never gives up.
+type OCPPBackoff struct {
+	WaitMinimum time.Duration
+	RandomRange time.Duration
+	RepeatTimes int
+}
+
+// NewOCPPBackoff creates a back-off strategy from the retry parameters of a client timeout configuration.
+func NewOCPPBackoff(config ClientTimeoutConfig) OCPPBackoff {
+	return OCPPBackoff{
+		WaitMinimum: config.RetryBackOffWaitMinimum,
+		RandomRange: time.Duration(config.RetryBackOffRandomRange) * time.Second,
+		RepeatTimes: config.RetryBackOffRepeatTimes,
+	}
+}
+
+func (b OCPPBackoff) NextDelay(attempt int, _ error) (time.Duration, bool) {
+	doublings := attempt - 1
+	if doublings > b.RepeatTimes {
+		doublings = b.RepeatTimes
+	}
+	backoff := ExponentialBackoff{InitialDelay: b.WaitMinimum, Multiplier: 2}
+	return backoff.delay(doublings+1, math.MaxInt64) + jitter(b.RandomRange), true
+}
+
+// jitter returns a random duration in the interval [0, max].
+func jitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(max) + 1))
+}
+
+// WithReconnectStrategy sets the strategy used by the client for automatic reconnections.
+// If not set, the back-off parameters of the ClientTimeoutConfig are used (see OCPPBackoff).
+func WithReconnectStrategy(strategy ReconnectStrategy) ClientOpt {
+	return func(c *client) {
+		c.reconnectStrategy = strategy
+	}
+}
+
+// ConnectionState describes the state of a client's connection to the server.
+type ConnectionState int
+
+const (
+	// ConnectionStateDisconnected means that the client isn't connected and isn't attempting to connect.
+	ConnectionStateDisconnected ConnectionState = iota
+	// ConnectionStateConnecting means that a connection attempt is in progress.
+	ConnectionStateConnecting
+	// ConnectionStateConnected means that the connection to the server is established.
+	ConnectionStateConnected
+	// ConnectionStateBackingOff means that the client is waiting before the next reconnection attempt.
+	ConnectionStateBackingOff
+	// ConnectionStateGaveUp means that the reconnect strategy gave up. The client won't attempt to reconnect on its own.
+	ConnectionStateGaveUp
+)
+
+func (s ConnectionState) String() string {
+	switch s {
+	case ConnectionStateDisconnected:
+		return "disconnected"
+	case ConnectionStateConnecting:
+		return "connecting"
+	case ConnectionStateConnected:
+		return "connected"
+	case ConnectionStateBackingOff:
+		return "backing off"
+	case ConnectionStateGaveUp:
+		return "gave up"
+	default:
+		return fmt.Sprintf("unknown connection state %d", int(s))
+	}
+}
+
+// ConnectionStateEvent is emitted by a client whenever its connection state changes.
+type ConnectionStateEvent struct {
+	// The new state of the connection.
+	State ConnectionState
+	// The number of the reconnection attempt the event refers to.
+	// Zero refers to the initial connection attempt (or to a manual reconnection);
+	// for ConnectionStateGaveUp it contains the number of failed reconnection attempts.
+	Attempt int
+	// The back-off delay before the next attempt. Only set for ConnectionStateBackingOff.
+	Delay time.Duration
+	// The last error that occurred, e.g. the reason for a disconnection or for a failed attempt. May be nil.
+	LastError error
+	// The reason why the connection was closed, or why the last connection attempt failed.
+	// Set for ConnectionStateDisconnected, ConnectionStateBackingOff and ConnectionStateGaveUp,
+	// if the event was caused by the loss of an established connection or by a failed attempt (see DisconnectReason.Handshake).
+	DisconnectReason *DisconnectReason
+}
+
+// getReconnectStrategy returns the configured strategy, or the default strategy derived from the timeout configuration.
+func (c *client) getReconnectStrategy() ReconnectStrategy {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.reconnectStrategy != nil {
+		return c.reconnectStrategy
+	}
+	return NewOCPPBackoff(c.timeoutConfig)
+}
+
+func (c *client) SetReconnectStrategy(strategy ReconnectStrategy) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.reconnectStrategy = strategy
+}
+
+func (c *client) SetConnectionStateHandler(handler func(event ConnectionStateEvent)) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.onStateChanged = handler
+}
+
+func (c *client) ConnectionState() ConnectionStateEvent {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.state
+}
+
+// setState stores the new connection state and notifies the state handler, if any.
+func (c *client) setState(event ConnectionStateEvent) {
+	c.mutex.Lock()
+	c.state = event
+	handler := c.onStateChanged
+	c.mutex.Unlock()
+	log.Debugf("connection state changed to %v (attempt %d)", event.State, event.Attempt)
+	if handler != nil {
+		handler(event)
+	}
+}
@@ -0,0 +1,367 @@
+package ws
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// SubprotocolSelector picks the subprotocol for a new connection, out of the subprotocols requested by the client
+// and the subprotocols supported by the server (in the order they were added).
+// If none of the requested subprotocols is supported, an empty string must be returned.
+type SubprotocolSelector func(requested []string, supported []string) string
+
+// PreferClientSubprotocols picks the first subprotocol requested by the client, which is supported by the server.
+// This is the default behavior of a server.
+func PreferClientSubprotocols(requested []string, supported []string) string {
+	for _, r := range requested {
+		for _, s := range supported {
+			if r == s {
+				return r
+			}
+		}
+	}
+	return ""
+}
+
+// PreferServerSubprotocols picks the first subprotocol supported by the server, which was requested by the client.
+// Adding subprotocols to the server in descending order of preference (e.g. the newest protocol version first)
+// results in the highest mutually supported version being picked.
+func PreferServerSubprotocols(requested []string, supported []string) string {
+	for _, s := range supported {
+		for _, r := range requested {
+			if r == s {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// SubprotocolRouter dispatches the connections of a single websocket server to multiple routes,
+// based on the subprotocol negotiated with each client.
+//
+// Each route implements the Server interface and can be passed to any component expecting a websocket server,
+// e.g. to one OCPP endpoint per protocol version:
+//
+//	router := ws.NewSubprotocolRouter(ws.NewServer())
+//	csms := ocpp2.NewCSMS(nil, router.Route("ocpp2.0.1"))
+//	centralSystem := ocpp16.NewCentralSystem(nil, router.Route("ocpp1.6"))
+//
+// Routes must be created in descending order of preference: if a client requests several subprotocols,
+// the one of the earliest created route is picked.
+//
+// A route only sees the channels, which negotiated its subprotocol. Configuration functions
+// (e.g. SetTimeoutConfig or SetBasicAuthHandler) are shared and applied to the underlying server.
+// The lifecycle of the underlying server is controlled exclusively via the router:
+// Start, Serve, Stop and Shutdown have no effect on the underlying server when invoked on a route.
+type SubprotocolRouter struct {
+	server Server
+	routes map[string]*subprotocolRoute
+	order  []string
+	mutex  sync.RWMutex
+}
+
+// NewSubprotocolRouter creates a router on top of the passed websocket server.
+// The router takes over all handlers of the server, which must therefore not be set directly anymore.
+func NewSubprotocolRouter(server Server) *SubprotocolRouter {
+	r := &SubprotocolRouter{
+		server: server,
+		routes: map[string]*subprotocolRoute{},
+	}
+	server.SetSubprotocolSelector(PreferServerSubprotocols)
+	server.SetCheckClientHandler(r.checkClient)
+	server.SetNewClientHandler(r.onNewClient)
+	server.SetDisconnectedClientHandler(r.onDisconnected)
+	server.SetReplacedClientHandler(r.onReplaced)
+	server.SetMessageHandler(r.onMessage)
+	return r
+}
+
+// Route returns the route for the passed subprotocol, creating it if necessary.
+// The subprotocol is added to the supported subprotocols of the underlying server.
+func (r *SubprotocolRouter) Route(subprotocol string) Server {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if route, ok := r.routes[subprotocol]; ok {
+		return route
+	}
+	route := &subprotocolRoute{router: r, subprotocol: subprotocol}
+	r.routes[subprotocol] = route
+	r.order = append(r.order, subprotocol)
+	r.server.AddSupportedSubprotocol(subprotocol)
+	return route
+}
+
+// Server returns the underlying websocket server.
+func (r *SubprotocolRouter) Server() Server {
+	return r.server
+}
+
+// Start starts the underlying websocket server. Refer to Server.Start.
+func (r *SubprotocolRouter) Start(port int, listenPath string) {
+	r.server.Start(port, listenPath)
+}
+
+// Serve serves incoming connections on the passed listener. Refer to Server.Serve.
+func (r *SubprotocolRouter) Serve(listener net.Listener, listenPath string) {
+	r.server.Serve(listener, listenPath)
+}
+
+// Handler returns the http.Handler of the underlying websocket server. Refer to Server.Handler.
+func (r *SubprotocolRouter) Handler() http.Handler {
+	return r.server.Handler()
+}
+
+// Stop stops the underlying websocket server, closing all channels of all routes.
+func (r *SubprotocolRouter) Stop() {
+	r.server.Stop()
+}
+
+// Shutdown gracefully shuts down the underlying websocket server. Refer to Server.Shutdown.
+func (r *SubprotocolRouter) Shutdown(ctx context.Context) error {
+	return r.server.Shutdown(ctx)
+}
+
+func (r *SubprotocolRouter) route(subprotocol string) *subprotocolRoute {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.routes[subprotocol]
+}
+
+func (r *SubprotocolRouter) routeForChannel(ws Channel) *subprotocolRoute {
+	return r.route(ws.Stats().Subprotocol)
+}
+
+func (r *SubprotocolRouter) checkClient(id string, req *http.Request) bool {
+	r.mutex.RLock()
+	subprotocol := PreferServerSubprotocols(websocket.Subprotocols(req), r.order)
+	r.mutex.RUnlock()
+	route := r.route(subprotocol)
+	if route == nil {
+		// Unsupported subprotocols are rejected by the server after the upgrade
+		return true
+	}
+	handler := route.getHandlers().checkClient
+	if handler == nil {
+		return true
+	}
+	return handler(id, req)
+}
+
+func (r *SubprotocolRouter) onNewClient(ws Channel) {
+	if route := r.routeForChannel(ws); route != nil {
+		if handler := route.getHandlers().newClient; handler != nil {
+			handler(ws)
+		}
+	}
+}
+
+func (r *SubprotocolRouter) onDisconnected(ws Channel) {
+	if route := r.routeForChannel(ws); route != nil {
+		if handler := route.getHandlers().disconnected; handler != nil {
+			handler(ws)
+		}
+	}
+}
+
+func (r *SubprotocolRouter) onReplaced(ws Channel) {
+	if route := r.routeForChannel(ws); route != nil {
+		if handler := route.getHandlers().replaced; handler != nil {
+			handler(ws)
+		}
+	}
+}
+
+func (r *SubprotocolRouter) onMessage(ws Channel, data []byte) error {
+	route := r.routeForChannel(ws)
+	if route == nil {
+		return fmt.Errorf("no route for subprotocol of %s", ws.ID())
+	}
+	handler := route.getHandlers().message
+	if handler == nil {
+		return fmt.Errorf("no message handler set")
+	}
+	return handler(ws, data)
+}
+
+// routeHandlers contains the handlers set on a single route.
+type routeHandlers struct {
+	checkClient  CheckClientHandler
+	newClient    ConnectedHandler
+	disconnected func(ws Channel)
+	replaced     func(ws Channel)
+	message      MessageHandler
+}
+
+// subprotocolRoute is a view on the underlying server, restricted to the channels using a single subprotocol.
+type subprotocolRoute struct {
+	router      *SubprotocolRouter
+	subprotocol string
+	handlers    routeHandlers
+	mutex       sync.RWMutex
+}
+
+func (r *subprotocolRoute) getHandlers() routeHandlers {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.handlers
+}
+
+func (r *subprotocolRoute) Start(_ int, _ string) {
+	log.Debugf("ignoring start of route %s, the router controls the server lifecycle", r.subprotocol)
+}
+
+func (r *subprotocolRoute) Serve(_ net.Listener, _ string) {
+	log.Debugf("ignoring serve of route %s, the router controls the server lifecycle", r.subprotocol)
+}
+
+func (r *subprotocolRoute) Handler() http.Handler {
+	return r.router.server.Handler()
+}
+
+func (r *subprotocolRoute) Stop() {
+}
+
+// Shutdown waits until the passed context is done, then closes all channels of the route with a CloseGoingAway.
+// The underlying server keeps running.
+func (r *subprotocolRoute) Shutdown(ctx context.Context) error {
+	<-ctx.Done()
+	for _, c := range r.Channels() {
+		_ = r.router.server.StopConnection(c.ID(), websocket.CloseError{Code: websocket.CloseGoingAway, Text: "server shutting down"})
+	}
+	return nil
+}
+
+func (r *subprotocolRoute) StopConnection(id string, closeError websocket.CloseError) error {
+	if _, ok := r.GetChannel(id); !ok {
+		return fmt.Errorf("couldn't stop websocket connection. No connection with id %s is open", id)
+	}
+	return r.router.server.StopConnection(id, closeError)
+}
+
+func (r *subprotocolRoute) Errors() <-chan error {
+	return r.router.server.Errors()
+}
+
+func (r *subprotocolRoute) SetMessageHandler(handler MessageHandler) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.handlers.message = handler
+}
+
+func (r *subprotocolRoute) SetNewClientHandler(handler ConnectedHandler) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.handlers.newClient = handler
+}
+
+func (r *subprotocolRoute) SetDisconnectedClientHandler(handler func(ws Channel)) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.handlers.disconnected = handler
+}
+
+func (r *subprotocolRoute) SetReplacedClientHandler(handler func(ws Channel)) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.handlers.replaced = handler
+}
+
+func (r *subprotocolRoute) SetCheckClientHandler(handler CheckClientHandler) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.handlers.checkClient = handler
+}
+
+func (r *subprotocolRoute) SetTimeoutConfig(config ServerTimeoutConfig) {
+	r.router.server.SetTimeoutConfig(config)
+}
+
+func (r *subprotocolRoute) Write(webSocketId string, data []byte) error {
+	if _, ok := r.GetChannel(webSocketId); !ok {
+		return fmt.Errorf("couldn't write to websocket. No socket with id %v is open", webSocketId)
+	}
+	return r.router.server.Write(webSocketId, data)
+}
+
+// AddSupportedSubprotocol has no effect, since a route only supports its own subprotocol.
+func (r *subprotocolRoute) AddSupportedSubprotocol(subProto string) {
+	if subProto != r.subprotocol {
+		log.Errorf("cannot add subprotocol %s to route %s", subProto, r.subprotocol)
+	}
+}
+
+func (r *subprotocolRoute) SetChargePointIdResolver(resolver func(r *http.Request) (string, error)) {
+	r.router.server.SetChargePointIdResolver(resolver)
+}
+
+func (r *subprotocolRoute) SetBasicAuthHandler(handler func(username string, password string) bool) {
+	r.router.server.SetBasicAuthHandler(handler)
+}
+
+func (r *subprotocolRoute) SetSecurityProfile(profile SecurityProfile) {
+	r.router.server.SetSecurityProfile(profile)
+}
+
+func (r *subprotocolRoute) SecurityProfile() SecurityProfile {
+	return r.router.server.SecurityProfile()
+}
+
+// AuthenticateRequest performs the checks of the underlying server, followed by the check-client handler of the route.
+func (r *subprotocolRoute) AuthenticateRequest(id string, req *http.Request) (int, error) {
+	if status, err := r.router.server.AuthenticateRequest(id, req); err != nil {
+		return status, err
+	}
+	if handler := r.getHandlers().checkClient; handler != nil && !handler(id, req) {
+		return http.StatusUnauthorized, fmt.Errorf("client validation: invalid client")
+	}
+	return http.StatusOK, nil
+}
+
+func (r *subprotocolRoute) SetCheckOriginHandler(handler func(r *http.Request) bool) {
+	r.router.server.SetCheckOriginHandler(handler)
+}
+
+// SetSubprotocolSelector has no effect, since the router picks the subprotocol.
+func (r *subprotocolRoute) SetSubprotocolSelector(_ SubprotocolSelector) {
+	log.Errorf("cannot set subprotocol selector on route %s", r.subprotocol)
+}
+
+func (r *subprotocolRoute) SetFallbackHandler(handler http.Handler) {
+	r.router.server.SetFallbackHandler(handler)
+}
+
+func (r *subprotocolRoute) Addr() *net.TCPAddr {
+	return r.router.server.Addr()
+}
+
+func (r *subprotocolRoute) GetChannel(websocketId string) (Channel, bool) {
+	c, ok := r.router.server.GetChannel(websocketId)
+	if !ok || c.Stats().Subprotocol != r.subprotocol {
+		return nil, false
+	}
+	return c, true
+}
+
+func (r *subprotocolRoute) Channels() []Channel {
+	var result []Channel
+	for _, c := range r.router.server.Channels() {
+		if c.Stats().Subprotocol == r.subprotocol {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+func (r *subprotocolRoute) ChannelCount() int {
+	return len(r.Channels())
+}
+
+func (r *subprotocolRoute) AdmissionStats() AdmissionStats {
+	return r.router.server.AdmissionStats()
+}
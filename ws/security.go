@@ -0,0 +1,206 @@
+package ws
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// SecurityProfile defines one of the security profiles described in the OCPP security whitepaper.
+//
+// By default, no security profile is enforced and the TLS and authentication configuration
+// of servers and clients is entirely up to the user.
+type SecurityProfile int
+
+const (
+	// SecurityProfileNone doesn't enforce any security rules. This is the default.
+	SecurityProfileNone SecurityProfile = iota
+	// SecurityProfile1 requires HTTP Basic Authentication, without TLS.
+	// The basic auth username must match the charging station identity.
+	SecurityProfile1
+	// SecurityProfile2 requires TLS 1.2 or higher with server-side certificates, as well as HTTP Basic Authentication.
+	// The basic auth username must match the charging station identity.
+	SecurityProfile2
+	// SecurityProfile3 requires TLS 1.2 or higher with mutual authentication via client-side certificates.
+	// The common name of the client certificate must match the charging station identity.
+	SecurityProfile3
+)
+
+func (p SecurityProfile) String() string {
+	switch p {
+	case SecurityProfileNone:
+		return "none"
+	case SecurityProfile1:
+		return "profile 1 (basic auth)"
+	case SecurityProfile2:
+		return "profile 2 (TLS with basic auth)"
+	case SecurityProfile3:
+		return "profile 3 (TLS with client certificates)"
+	default:
+		return fmt.Sprintf("unknown security profile %d", int(p))
+	}
+}
+
+// IsValid returns true, if the security profile is known.
+func (p SecurityProfile) IsValid() bool {
+	return p >= SecurityProfileNone && p <= SecurityProfile3
+}
+
+// requiresBasicAuth returns true, if the profile mandates HTTP Basic Authentication.
+func (p SecurityProfile) requiresBasicAuth() bool {
+	return p == SecurityProfile1 || p == SecurityProfile2
+}
+
+// requiresTLS returns true, if the profile mandates a TLS connection.
+func (p SecurityProfile) requiresTLS() bool {
+	return p == SecurityProfile2 || p == SecurityProfile3
+}
+
+// ErrSecurityProfileViolation is returned whenever a connection doesn't satisfy the rules of the configured security profile.
+var ErrSecurityProfileViolation = errors.New("security profile violation")
+
+// SecurityProfileCipherSuites contains the TLS 1.2 cipher suites, which are mandated by the OCPP security whitepaper.
+//
+// TLS 1.3 cipher suites are not configurable and are always allowed.
+var SecurityProfileCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_RSA_WITH_AES_256_GCM_SHA384,
+}
+
+// WithServerSecurityProfile enforces the rules of an OCPP security profile on all incoming connections.
+//
+// For profiles 2 and 3, the server TLS configuration is restricted to TLS 1.2+ and the mandated cipher suites.
+// Profile 3 additionally requires and verifies client certificates.
+// A server certificate still needs to be configured via WithServerTLSConfig.
+//
+// For profiles 1 and 2, a basic auth handler must be set via SetBasicAuthHandler.
+func WithServerSecurityProfile(profile SecurityProfile) ServerOpt {
+	return func(s *server) {
+		s.securityProfile = profile
+	}
+}
+
+// WithClientSecurityProfile enforces the rules of an OCPP security profile when connecting to a server.
+//
+// For profiles 2 and 3, the client TLS configuration is restricted to TLS 1.2+ and the mandated cipher suites.
+// Profile 3 additionally requires a client certificate to be configured via WithClientTLSConfig.
+//
+// For profiles 1 and 2, basic auth credentials must be set via SetBasicAuth.
+func WithClientSecurityProfile(profile SecurityProfile) ClientOpt {
+	return func(c *client) {
+		c.securityProfile = profile
+	}
+}
+
+// hardenTLSConfig returns a copy of the passed TLS configuration, restricted to the settings mandated by the profile.
+// If the profile doesn't require TLS, the configuration is returned unchanged.
+func (p SecurityProfile) hardenTLSConfig(config *tls.Config, isServer bool) *tls.Config {
+	if !p.requiresTLS() {
+		return config
+	}
+	var hardened *tls.Config
+	if config != nil {
+		hardened = config.Clone()
+	} else {
+		hardened = &tls.Config{}
+	}
+	if hardened.MinVersion < tls.VersionTLS12 {
+		hardened.MinVersion = tls.VersionTLS12
+	}
+	hardened.CipherSuites = allowedCipherSuites(hardened.CipherSuites)
+	if isServer && p == SecurityProfile3 {
+		hardened.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return hardened
+}
+
+// allowedCipherSuites restricts the passed cipher suites to the ones mandated by the security profiles.
+// If none of the passed suites are allowed, the mandated suites are returned.
+func allowedCipherSuites(suites []uint16) []uint16 {
+	var result []uint16
+	for _, suite := range suites {
+		if isAllowedCipherSuite(suite) {
+			result = append(result, suite)
+		}
+	}
+	if len(result) == 0 {
+		result = append(result, SecurityProfileCipherSuites...)
+	}
+	return result
+}
+
+func isAllowedCipherSuite(suite uint16) bool {
+	for _, allowed := range SecurityProfileCipherSuites {
+		if suite == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// checkTLSConnectionState verifies that an established TLS connection satisfies the profile.
+func (p SecurityProfile) checkTLSConnectionState(state *tls.ConnectionState) error {
+	if !p.requiresTLS() {
+		return nil
+	}
+	if state == nil {
+		return fmt.Errorf("%w: %v requires TLS", ErrSecurityProfileViolation, p)
+	}
+	if state.Version < tls.VersionTLS12 {
+		return fmt.Errorf("%w: TLS version 0x%04x not allowed", ErrSecurityProfileViolation, state.Version)
+	}
+	if state.Version == tls.VersionTLS12 && !isAllowedCipherSuite(state.CipherSuite) {
+		return fmt.Errorf("%w: cipher suite %s not allowed", ErrSecurityProfileViolation, tls.CipherSuiteName(state.CipherSuite))
+	}
+	return nil
+}
+
+// checkBasicAuthIdentity verifies that basic auth credentials are present and bound to the charging station identity.
+func (p SecurityProfile) checkBasicAuthIdentity(id string, username string, ok bool) error {
+	if !p.requiresBasicAuth() {
+		return nil
+	}
+	if !ok {
+		return fmt.Errorf("%w: %v requires basic auth credentials", ErrSecurityProfileViolation, p)
+	}
+	if username != id {
+		return fmt.Errorf("%w: basic auth username %s doesn't match identity %s", ErrSecurityProfileViolation, username, id)
+	}
+	return nil
+}
+
+// checkCertificateIdentity verifies that a verified client certificate was presented, and that it is bound to the charging station identity.
+func (p SecurityProfile) checkCertificateIdentity(id string, state *tls.ConnectionState) error {
+	if p != SecurityProfile3 {
+		return nil
+	}
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return fmt.Errorf("%w: %v requires a verified client certificate", ErrSecurityProfileViolation, p)
+	}
+	cn := state.VerifiedChains[0][0].Subject.CommonName
+	if cn != id {
+		return fmt.Errorf("%w: client certificate common name %s doesn't match identity %s", ErrSecurityProfileViolation, cn, id)
+	}
+	return nil
+}
+
+// checkServerRequest verifies that an incoming HTTP request satisfies the profile.
+// If the request is rejected, the HTTP status code to return to the client is returned along with the error.
+func (p SecurityProfile) checkServerRequest(id string, r *http.Request) (int, error) {
+	if err := p.checkTLSConnectionState(r.TLS); err != nil {
+		return http.StatusForbidden, err
+	}
+	if err := p.checkCertificateIdentity(id, r.TLS); err != nil {
+		return http.StatusUnauthorized, err
+	}
+	username, _, ok := r.BasicAuth()
+	if err := p.checkBasicAuthIdentity(id, username, ok); err != nil {
+		return http.StatusUnauthorized, err
+	}
+	return http.StatusOK, nil
+}
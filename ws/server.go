@@ -8,6 +8,7 @@ import (
 	"net"
 	"net/http"
 	"path"
+	"sort"
 	"sync"
 	"time"
 
@@ -61,9 +62,34 @@ type Server interface {
 	//
 	// To stop a running server, call the Stop function.
 	Start(port int, listenPath string)
+	// Serve behaves like Start, but accepts incoming connections on the passed listener,
+	// instead of creating a new TCP listener on a port.
+	// This allows serving on any kind of listener, e.g. a unix socket or a listener handed over by the system.
+	//
+	// If the server was configured with a TLS certificate, TLS is performed on top of the passed listener.
+	// The listener is closed when the server is stopped.
+	//
+	// The function blocks forever, hence it is suggested to invoke it in a goroutine.
+	Serve(listener net.Listener, listenPath string)
+	// Handler returns an http.Handler, which upgrades incoming HTTP requests to websocket connections.
+	// The handler may be mounted on an existing HTTP server or router, on the desired upgrade path, e.g.:
+	//	mux.Handle("/ws/{id}", server.Handler())
+	//
+	// Accepted connections are handled exactly like connections accepted via Start.
+	// The lifecycle of the external HTTP server, including TLS termination, is up to the caller.
+	// Calling Stop or Shutdown closes all open websocket connections, but doesn't affect the external HTTP server.
+	Handler() http.Handler
 	// Shuts down a running websocket server.
 	// All open channels will be forcefully closed, and the previously called Start function will return.
 	Stop()
+	// Shutdown gracefully shuts down a running websocket server.
+	// The server stops accepting new connections right away, while open channels remain usable.
+	// The function then blocks until either all open channels were closed, or the passed context is done.
+	// Channels that are still open at that point are closed with a CloseGoingAway close frame.
+	//
+	// Once the function returns, the previously called Start function will return as well.
+	// An error is returned only if the underlying HTTP server couldn't be shut down properly.
+	Shutdown(ctx context.Context) error
 	// Closes a specific websocket connection.
 	StopConnection(id string, closeError websocket.CloseError) error
 	// Errors returns a channel for error messages. If it doesn't exist it es created.
@@ -82,6 +108,11 @@ type Server interface {
 	// Sets a callback function for all client disconnection events.
 	// Once a client is disconnected, it is not possible to read/write on the respective Channel any longer.
 	SetDisconnectedClientHandler(handler func(ws Channel))
+	// SetReplacedClientHandler sets a callback function, invoked whenever a channel was closed,
+	// because a new connection with the same ID replaced it (see DuplicateConnectionReplace).
+	//
+	// The callback is invoked right before the DisconnectedClientHandler for the same channel.
+	SetReplacedClientHandler(handler func(ws Channel))
 	// Set custom timeout configuration parameters. If not passed, a default ServerTimeoutConfig struct will be used.
 	//
 	// This function must be called before starting the server, otherwise it may lead to unexpected behavior.
@@ -104,6 +135,11 @@ type Server interface {
 	// The handler function is called whenever a new client attempts to connect, to check for credentials correctness.
 	// The handler must return true if the credentials were correct, false otherwise.
 	SetBasicAuthHandler(handler func(username string, password string) bool)
+	// SetSecurityProfile sets the OCPP security profile to enforce on all incoming connections.
+	// Refer to WithServerSecurityProfile for more information.
+	//
+	// The profile must be set before starting the server.
+	SetSecurityProfile(profile SecurityProfile)
 	// SetCheckOriginHandler sets a handler for incoming websocket connections, allowing to perform
 	// custom cross-origin checks.
 	//
@@ -124,6 +160,41 @@ type Server interface {
 	// If a connection with the given ID exists, it returns the corresponding webSocket instance.
 	// If no connection is found with the specified ID, it returns nil and a false flag.
 	GetChannel(websocketId string) (Channel, bool)
+	// Channels returns a snapshot of all active channels, sorted by their identifier.
+	Channels() []Channel
+	// ChannelCount returns the number of active channels.
+	ChannelCount() int
+	// SetSubprotocolSelector sets a custom strategy for picking the subprotocol of incoming connections.
+	// By default, the first subprotocol requested by the client, which is also supported by the server, is picked.
+	// Refer to PreferServerSubprotocols for an alternative strategy.
+	SetSubprotocolSelector(selector SubprotocolSelector)
+	// SetFallbackHandler sets a handler for HTTP requests received on the listen path, which aren't websocket upgrade requests.
+	// This allows serving a different protocol on the same port and path, e.g. SOAP requests via HTTP POST.
+	//
+	// Fallback requests are subject to admission control, but bypass the authentication of websocket clients
+	// (security profile, basic auth, check-client handler), since the client identity is usually part of the request body.
+	// The handler must authenticate every request itself, e.g. via AuthenticateRequest. By default, such requests are rejected.
+	SetFallbackHandler(handler http.Handler)
+	// AuthenticateRequest performs the same checks as for websocket upgrade requests on an HTTP request,
+	// on behalf of the passed client identity: the rules of the security profile, basic auth and the check-client handler.
+	// Useful for fallback handlers, which serve clients that don't keep a connection open.
+	//
+	// If the request is rejected, the HTTP status code to return to the client is returned along with the error.
+	AuthenticateRequest(id string, r *http.Request) (int, error)
+	// SecurityProfile returns the OCPP security profile enforced on incoming connections.
+	SecurityProfile() SecurityProfile
+	// AdmissionStats returns counters about admitted and rejected connections.
+	// If admission control wasn't enabled via WithServerAdmissionControl, all counters are zero.
+	AdmissionStats() AdmissionStats
+}
+
+// Connection counters of IDs, which stayed disconnected for longer than this, are evicted.
+const defaultConnectionCountRetention = 24 * time.Hour
+
+// connectionCount keeps track of how many connections were accepted for a single ID.
+type connectionCount struct {
+	connections    uint64
+	disconnectedAt time.Time // zero while the ID is connected
 }
 
 // Default implementation of a Websocket server.
@@ -141,16 +212,74 @@ type server struct {
 	tlsCertificatePath    string
 	tlsCertificateKey     string
 	timeoutConfig         ServerTimeoutConfig
+	compression           CompressionConfig
+	securityProfile       SecurityProfile
+	certificateProvider   CertificateProvider
 	upgrader              websocket.Upgrader
 	errC                  chan error
 	connMutex             sync.RWMutex
 	addr                  *net.TCPAddr
 	httpHandler           *mux.Router
+	// Handling of duplicate connections
+	duplicateConnectionPolicy  DuplicateConnectionPolicy
+	duplicateConnectionHandler DuplicateConnectionHandler
+	replacedHandler            func(ws Channel)
+	replacing                  map[*webSocket]struct{}
+	// Number of accepted connections per ID, used for counting reconnections
+	connectionCounts         map[string]*connectionCount
+	connectionCountRetention time.Duration
+	lastConnectionCountSweep time.Time
+	admission                *admissionControl
+	subprotocolSelector      SubprotocolSelector
+	// Opens the listener when starting the server, defaults to net.Listen
+	listen func(network, address string) (net.Listener, error)
+	// Handles requests, which aren't websocket upgrade requests
+	fallbackHandler http.Handler
 }
 
 // ServerOpt is a function that can be used to set options on a server during creation.
 type ServerOpt func(s *server)
 
+// DuplicateConnectionPolicy defines how the server handles an incoming connection,
+// if a connection with the same ID is already open.
+type DuplicateConnectionPolicy int
+
+const (
+	// DuplicateConnectionReject keeps the existing connection and closes the new connection
+	// with a ClosePolicyViolation. This is the default policy.
+	DuplicateConnectionReject DuplicateConnectionPolicy = iota
+	// DuplicateConnectionReplace closes the existing connection and accepts the new one instead.
+	//
+	// The existing channel is closed first, then the ReplacedClientHandler and DisconnectedClientHandler
+	// are invoked for the old channel. Only afterwards the NewClientHandler is invoked for the new channel.
+	DuplicateConnectionReplace
+)
+
+// DuplicateConnectionHandler is a callback, which decides how to handle an incoming connection request,
+// if a connection with the same ID is already open.
+// The callback receives the existing channel and the incoming HTTP request.
+type DuplicateConnectionHandler func(existing Channel, r *http.Request) DuplicateConnectionPolicy
+
+// WithDuplicateConnectionPolicy sets the policy to apply, whenever a client connects
+// while a connection with the same ID is still open.
+//
+// By default, new connections are rejected.
+func WithDuplicateConnectionPolicy(policy DuplicateConnectionPolicy) ServerOpt {
+	return func(s *server) {
+		s.duplicateConnectionPolicy = policy
+	}
+}
+
+// WithDuplicateConnectionHandler sets a callback, which decides on a per-connection basis,
+// whether an incoming connection should replace an existing connection with the same ID.
+//
+// If set, the handler takes precedence over the policy set via WithDuplicateConnectionPolicy.
+func WithDuplicateConnectionHandler(handler DuplicateConnectionHandler) ServerOpt {
+	return func(s *server) {
+		s.duplicateConnectionHandler = handler
+	}
+}
+
 // WithServerTLSConfig sets the TLS configuration for the server.
 // If the passed tlsConfig is nil, the client will not use TLS.
 func WithServerTLSConfig(certificatePath string, certificateKey string, tlsConfig *tls.Config) ServerOpt {
@@ -183,10 +312,14 @@ func WithServerTLSConfig(certificatePath string, certificateKey string, tlsConfi
 func NewServer(opts ...ServerOpt) Server {
 	router := mux.NewRouter()
 	s := &server{
-		httpServer:    &http.Server{},
-		timeoutConfig: NewServerTimeoutConfig(),
-		upgrader:      websocket.Upgrader{Subprotocols: []string{}},
-		httpHandler:   router,
+		connections:              make(map[string]*webSocket),
+		replacing:                make(map[*webSocket]struct{}),
+		connectionCounts:         make(map[string]*connectionCount),
+		connectionCountRetention: defaultConnectionCountRetention,
+		httpServer:               &http.Server{},
+		timeoutConfig:            NewServerTimeoutConfig(),
+		upgrader:                 websocket.Upgrader{Subprotocols: []string{}},
+		httpHandler:              router,
 		chargePointIdResolver: func(r *http.Request) (string, error) {
 			url := r.URL
 			return path.Base(url.Path), nil
@@ -214,6 +347,10 @@ func (s *server) SetDisconnectedClientHandler(handler func(ws Channel)) {
 	s.disconnectedHandler = handler
 }
 
+func (s *server) SetReplacedClientHandler(handler func(ws Channel)) {
+	s.replacedHandler = handler
+}
+
 func (s *server) SetTimeoutConfig(config ServerTimeoutConfig) {
 	s.timeoutConfig = config
 }
@@ -228,6 +365,29 @@ func (s *server) AddSupportedSubprotocol(subProto string) {
 	s.upgrader.Subprotocols = append(s.upgrader.Subprotocols, subProto)
 }
 
+func (s *server) SetSubprotocolSelector(selector SubprotocolSelector) {
+	s.subprotocolSelector = selector
+}
+
+func (s *server) SetFallbackHandler(handler http.Handler) {
+	s.fallbackHandler = handler
+}
+
+// selectSubprotocol picks the subprotocol for a new connection, out of the ones requested by the client.
+// If no subprotocols are configured on the server, all requested subprotocols are accepted.
+func (s *server) selectSubprotocol(requested []string) string {
+	if len(s.upgrader.Subprotocols) == 0 {
+		if len(requested) > 0 {
+			return requested[0]
+		}
+		return ""
+	}
+	if s.subprotocolSelector != nil {
+		return s.subprotocolSelector(requested, s.upgrader.Subprotocols)
+	}
+	return PreferClientSubprotocols(requested, s.upgrader.Subprotocols)
+}
+
 func (s *server) SetChargePointIdResolver(resolver func(r *http.Request) (string, error)) {
 	s.chargePointIdResolver = resolver
 }
@@ -236,6 +396,39 @@ func (s *server) SetBasicAuthHandler(handler func(username string, password stri
 	s.basicAuthHandler = handler
 }
 
+func (s *server) SetSecurityProfile(profile SecurityProfile) {
+	s.securityProfile = profile
+}
+
+func (s *server) SecurityProfile() SecurityProfile {
+	return s.securityProfile
+}
+
+func (s *server) AuthenticateRequest(id string, r *http.Request) (int, error) {
+	// Enforce security profile
+	if status, err := s.securityProfile.checkServerRequest(id, r); err != nil {
+		return status, fmt.Errorf("client %s rejected: %w", id, err)
+	}
+	// Handle client authentication
+	if s.basicAuthHandler == nil && s.securityProfile.requiresBasicAuth() {
+		return http.StatusUnauthorized, fmt.Errorf("%w: %v requires a basic auth handler", ErrSecurityProfileViolation, s.securityProfile)
+	}
+	if s.basicAuthHandler != nil {
+		username, password, ok := r.BasicAuth()
+		if ok {
+			ok = s.basicAuthHandler(username, password)
+		}
+		if !ok {
+			return http.StatusUnauthorized, fmt.Errorf("basic auth failed: credentials invalid")
+		}
+	}
+	// Custom client checks
+	if s.checkClientHandler != nil && !s.checkClientHandler(id, r) {
+		return http.StatusUnauthorized, fmt.Errorf("client validation: invalid client")
+	}
+	return http.StatusOK, nil
+}
+
 func (s *server) SetCheckOriginHandler(handler func(r *http.Request) bool) {
 	s.upgrader.CheckOrigin = handler
 }
@@ -263,6 +456,20 @@ func (s *server) AddHttpHandler(listenPath string, handler func(w http.ResponseW
 }
 
 func (s *server) Start(port int, listenPath string) {
+	addr := fmt.Sprintf(":%v", port)
+	listen := s.listen
+	if listen == nil {
+		listen = net.Listen
+	}
+	ln, err := listen("tcp", addr)
+	if err != nil {
+		s.error(fmt.Errorf("failed to listen: %w", err))
+		return
+	}
+	s.Serve(ln, listenPath)
+}
+
+func (s *server) Serve(listener net.Listener, listenPath string) {
 	s.connMutex.Lock()
 	s.connections = make(map[string]*webSocket)
 	s.connMutex.Unlock()
@@ -271,48 +478,137 @@ func (s *server) Start(port int, listenPath string) {
 		s.httpServer = &http.Server{}
 	}
 
-	addr := fmt.Sprintf(":%v", port)
-	s.httpServer.Addr = addr
+	s.httpServer.Addr = listener.Addr().String()
 
 	s.AddHttpHandler(listenPath, func(w http.ResponseWriter, r *http.Request) {
 		s.wsHandler(w, r)
 	})
 	s.httpServer.Handler = s.httpHandler
 
-	ln, err := net.Listen("tcp", addr)
-	if err != nil {
-		s.error(fmt.Errorf("failed to listen: %w", err))
-		return
+	if tcpAddr, ok := listener.Addr().(*net.TCPAddr); ok {
+		s.addr = tcpAddr
 	}
 
-	s.addr = ln.Addr().(*net.TCPAddr)
+	defer listener.Close()
 
-	defer ln.Close()
+	certificatePath, certificateKey := s.tlsCertificatePath, s.tlsCertificateKey
+	useTLS := certificatePath != "" && certificateKey != ""
+	// Certificates from a provider take precedence over static certificate files
+	if s.certificateProvider != nil {
+		s.httpServer.TLSConfig = withServerCertificate(s.httpServer.TLSConfig, s.certificateProvider)
+		certificatePath, certificateKey = "", ""
+		useTLS = true
+	}
+	// Enforce security profile on TLS configuration
+	if s.securityProfile.requiresTLS() {
+		tlsConfig := s.httpServer.TLSConfig
+		if !useTLS && (tlsConfig == nil || (len(tlsConfig.Certificates) == 0 && tlsConfig.GetCertificate == nil)) {
+			s.error(fmt.Errorf("%w: %v requires a server certificate", ErrSecurityProfileViolation, s.securityProfile))
+			return
+		}
+		s.httpServer.TLSConfig = s.securityProfile.hardenTLSConfig(tlsConfig, true)
+		useTLS = true
+	}
 
-	log.Infof("listening on tcp network %v", addr)
-	s.httpServer.RegisterOnShutdown(s.stopConnections)
-	if s.tlsCertificatePath != "" && s.tlsCertificateKey != "" {
-		err = s.httpServer.ServeTLS(ln, s.tlsCertificatePath, s.tlsCertificateKey)
+	log.Infof("listening on %v network %v", listener.Addr().Network(), listener.Addr().String())
+	// Admission checks are performed on every accepted connection, before the TLS handshake.
+	// A custom source IP resolver requires the request, hence the checks are performed by the handler instead.
+	if s.admission != nil && s.admission.config.SourceIPResolver == nil {
+		listener = admissionListener{Listener: listener, admission: s.admission, useTLS: useTLS}
+	}
+	// Keep track of network traffic for each connection. With TLS, the handshake is performed by the listener,
+	// on top of the counted connection, so that the TLS overhead is included.
+	var ln net.Listener
+	if useTLS {
+		tlsConfig, err := serverTLSConfig(s.httpServer.TLSConfig, certificatePath, certificateKey)
+		if err != nil {
+			s.error(fmt.Errorf("failed to listen: %w", err))
+			return
+		}
+		tlsListener := newCountingTLSListener(listener, tlsConfig)
+		s.httpServer.ConnContext = tlsListener.connContext
+		ln = tlsListener
 	} else {
-		err = s.httpServer.Serve(ln)
+		s.httpServer.ConnContext = withCountingConn
+		ln = countingListener{Listener: listener}
 	}
+	err := s.httpServer.Serve(ln)
 
 	if !errors.Is(err, http.ErrServerClosed) {
 		s.error(fmt.Errorf("failed to listen: %w", err))
 	}
 }
 
+// Returns the TLS configuration used for accepting connections, loading the certificate files if needed.
+// Follows the behavior of http.Server.ServeTLS.
+func serverTLSConfig(config *tls.Config, certificatePath string, certificateKey string) (*tls.Config, error) {
+	if config == nil {
+		config = &tls.Config{}
+	} else {
+		config = config.Clone()
+	}
+	if len(config.NextProtos) == 0 {
+		config.NextProtos = []string{"http/1.1"}
+	}
+	hasCertificate := len(config.Certificates) > 0 || config.GetCertificate != nil || config.GetConfigForClient != nil
+	if !hasCertificate || certificatePath != "" || certificateKey != "" {
+		certificate, err := tls.LoadX509KeyPair(certificatePath, certificateKey)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+	return config, nil
+}
+
+func (s *server) Handler() http.Handler {
+	return http.HandlerFunc(s.wsHandler)
+}
+
 func (s *server) Stop() {
 	log.Info("stopping websocket server")
 	err := s.httpServer.Shutdown(context.TODO())
 	if err != nil {
 		s.error(fmt.Errorf("shutdown failed: %w", err))
 	}
+	s.stopConnections(websocket.CloseError{Code: websocket.CloseNormalClosure, Text: ""})
+
+	if s.errC != nil {
+		close(s.errC)
+		s.errC = nil
+	}
+}
+
+func (s *server) Shutdown(ctx context.Context) error {
+	log.Info("shutting down websocket server")
+	// Stop accepting new connections. Hijacked websocket connections are not tracked by the http server.
+	var err error
+	if e := s.httpServer.Shutdown(ctx); e != nil && !errors.Is(e, context.Canceled) && !errors.Is(e, context.DeadlineExceeded) {
+		err = fmt.Errorf("shutdown failed: %w", e)
+		s.error(err)
+	}
+	// Wait for open channels to be closed, up until the context is done
+	ticker := time.NewTicker(shutdownPollInterval)
+	defer ticker.Stop()
+	for waiting := true; waiting && s.connectionCount() > 0; {
+		select {
+		case <-ctx.Done():
+			waiting = false
+		case <-ticker.C:
+		}
+	}
+	// Close remaining channels
+	if open := s.connectionCount(); open > 0 {
+		log.Infof("closing %d remaining connections", open)
+		s.stopConnections(websocket.CloseError{Code: websocket.CloseGoingAway, Text: "server shutting down"})
+		s.waitForConnectionsClosed()
+	}
 
 	if s.errC != nil {
 		close(s.errC)
 		s.errC = nil
 	}
+	return err
 }
 
 func (s *server) StopConnection(id string, closeError websocket.CloseError) error {
@@ -331,14 +627,63 @@ func (s *server) GetChannel(websocketId string) (Channel, bool) {
 	s.connMutex.RLock()
 	defer s.connMutex.RUnlock()
 	c, ok := s.connections[websocketId]
-	return c, ok
+	if !ok {
+		return nil, false
+	}
+	return c, true
 }
 
-func (s *server) stopConnections() {
+func (s *server) Channels() []Channel {
+	s.connMutex.RLock()
+	channels := make([]Channel, 0, len(s.connections))
+	for _, c := range s.connections {
+		channels = append(channels, c)
+	}
+	s.connMutex.RUnlock()
+	sort.Slice(channels, func(i, j int) bool {
+		return channels[i].ID() < channels[j].ID()
+	})
+	return channels
+}
+
+func (s *server) ChannelCount() int {
+	return s.connectionCount()
+}
+
+func (s *server) AdmissionStats() AdmissionStats {
+	if s.admission == nil {
+		return AdmissionStats{}
+	}
+	return s.admission.getStats()
+}
+
+func (s *server) stopConnections(closeError websocket.CloseError) {
 	s.connMutex.RLock()
 	defer s.connMutex.RUnlock()
 	for _, conn := range s.connections {
-		_ = conn.Close(websocket.CloseError{Code: websocket.CloseNormalClosure, Text: ""})
+		_ = conn.Close(closeError)
+	}
+}
+
+func (s *server) connectionCount() int {
+	s.connMutex.RLock()
+	defer s.connMutex.RUnlock()
+	return len(s.connections)
+}
+
+// waitForConnectionsClosed blocks until all connections were cleaned up after being closed.
+// Since a close frame is written with a deadline, the wait is bounded by the configured write wait.
+func (s *server) waitForConnectionsClosed() {
+	deadline := time.After(s.timeoutConfig.WriteWait + shutdownPollInterval)
+	ticker := time.NewTicker(shutdownPollInterval)
+	defer ticker.Stop()
+	for s.connectionCount() > 0 {
+		select {
+		case <-deadline:
+			log.Errorf("%d connections weren't closed properly during shutdown", s.connectionCount())
+			return
+		case <-ticker.C:
+		}
 	}
 }
 
@@ -353,7 +698,47 @@ func (s *server) Write(webSocketId string, data []byte) error {
 	return w.Write(data)
 }
 
+// admitRequest enforces the admission policies on an incoming request.
+// If the request is rejected, the response is written and false is returned.
+// Otherwise, the source IP for which a connection slot was reserved is returned, if any.
+func (s *server) admitRequest(w http.ResponseWriter, r *http.Request) (string, bool) {
+	if ac := admissionConnFromContext(r.Context()); ac != nil {
+		// Admission checks were already performed by the listener
+		if ac.rejection != nil {
+			w.Header().Set("Connection", "close")
+			ac.rejection.writeResponse(w)
+			return "", false
+		}
+		return "", true
+	}
+	if s.admission == nil {
+		return "", true
+	}
+	admissionIP, rejection := s.admission.admit(r)
+	if rejection != nil {
+		rejection.writeResponse(w)
+		return "", false
+	}
+	return admissionIP, true
+}
+
 func (s *server) wsHandler(w http.ResponseWriter, r *http.Request) {
+	// Enforce admission policies, before performing any further processing
+	admissionIP, ok := s.admitRequest(w, r)
+	if !ok {
+		return
+	}
+	admitted := false
+	defer func() {
+		if !admitted && s.admission != nil {
+			// Connection wasn't established, free up the reserved slot
+			s.admission.release(admissionIP)
+		}
+	}()
+	if s.fallbackHandler != nil && !websocket.IsWebSocketUpgrade(r) {
+		s.fallbackHandler.ServeHTTP(w, r)
+		return
+	}
 	responseHeader := http.Header{}
 	id, err := s.chargePointIdResolver(r)
 	if err != nil {
@@ -364,46 +749,18 @@ func (s *server) wsHandler(w http.ResponseWriter, r *http.Request) {
 	log.Debugf("handling new connection for %s from %s", id, r.RemoteAddr)
 	// Negotiate sub-protocol
 	clientSubProtocols := websocket.Subprotocols(r)
-	negotiatedSubProtocol := ""
-out:
-	for _, requestedProto := range clientSubProtocols {
-		if len(s.upgrader.Subprotocols) == 0 {
-			// All subProtocols are accepted, pick first
-			negotiatedSubProtocol = requestedProto
-			break
-		}
-		// Check if requested suprotocol is supported by server
-		for _, supportedProto := range s.upgrader.Subprotocols {
-			if requestedProto == supportedProto {
-				negotiatedSubProtocol = requestedProto
-				break out
-			}
-		}
-	}
+	negotiatedSubProtocol := s.selectSubprotocol(clientSubProtocols)
 	if negotiatedSubProtocol != "" {
 		responseHeader.Add("Sec-WebSocket-Protocol", negotiatedSubProtocol)
 	}
-	// Handle client authentication
-	if s.basicAuthHandler != nil {
-		username, password, ok := r.BasicAuth()
-		if ok {
-			ok = s.basicAuthHandler(username, password)
-		}
-		if !ok {
-			s.error(fmt.Errorf("basic auth failed: credentials invalid"))
+	// Authenticate client
+	if status, err := s.AuthenticateRequest(id, r); err != nil {
+		s.error(err)
+		if status == http.StatusUnauthorized {
 			w.Header().Set("WWW-Authenticate", `Basic realm="Restricted"`)
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
-			return
-		}
-	}
-	// Custom client checks
-	if s.checkClientHandler != nil {
-		ok := s.checkClientHandler(id, r)
-		if !ok {
-			s.error(fmt.Errorf("client validation: invalid client"))
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
-			return
 		}
+		http.Error(w, http.StatusText(status), status)
+		return
 	}
 
 	// Upgrade websocket
@@ -424,9 +781,36 @@ out:
 		return
 	}
 	// Check whether client exists
+	s.connMutex.RLock()
+	existing, exists := s.connections[id]
+	s.connMutex.RUnlock()
+	if exists {
+		policy := s.duplicateConnectionPolicy
+		if s.duplicateConnectionHandler != nil {
+			policy = s.duplicateConnectionHandler(existing, r)
+		}
+		if policy != DuplicateConnectionReplace {
+			// There is already a connection with the same ID. Close the new one immediately with a PolicyViolation.
+			s.error(fmt.Errorf("client %s already exists, closing duplicate client", id))
+			_ = conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "a connection with this ID already exists"),
+				time.Now().Add(s.timeoutConfig.WriteWait))
+			_ = conn.Close()
+			return
+		}
+		// Close the existing connection and wait for it to be cleaned up, before accepting the new one
+		if err = s.replaceConnection(existing); err != nil {
+			s.error(err)
+			_ = conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "existing connection couldn't be replaced"),
+				time.Now().Add(s.timeoutConfig.WriteWait))
+			_ = conn.Close()
+			return
+		}
+	}
 	s.connMutex.Lock()
-	// There is already a connection with the same ID. Close the new one immediately with a PolicyViolation.
-	if _, exists := s.connections[id]; exists {
+	// A connection with the same ID may have been opened concurrently. Close the new one immediately with a PolicyViolation.
+	if _, exists = s.connections[id]; exists {
 		s.connMutex.Unlock()
 		s.error(fmt.Errorf("client %s already exists, closing duplicate client", id))
 		_ = conn.WriteControl(websocket.CloseMessage,
@@ -436,21 +820,32 @@ out:
 		return
 	}
 	// Create web socket for client, state is automatically set to connected
+	wsConfig := NewDefaultWebSocketConfig(
+		s.timeoutConfig.WriteWait,
+		s.timeoutConfig.PingWait,
+		s.timeoutConfig.PingPeriod,
+		s.timeoutConfig.PongWait)
+	wsConfig.ReadLimit = s.timeoutConfig.ReadLimit
+	wsConfig.Compression = s.compression
+	wsConfig.SecurityProfile = s.securityProfile
 	ws := newWebSocket(
 		id,
 		conn,
 		r.TLS,
-		NewDefaultWebSocketConfig(
-			s.timeoutConfig.WriteWait,
-			s.timeoutConfig.PingWait,
-			s.timeoutConfig.PingPeriod,
-			s.timeoutConfig.PongWait),
+		wsConfig,
 		s.handleMessage,
 		s.handleDisconnect,
 		func(_ Channel, err error) {
 			s.error(err)
 		},
 	)
+	// Compression is accepted by the server, whenever the client offers it
+	compressionNegotiated := s.upgrader.EnableCompression && isCompressionNegotiated(r.Header)
+	ws.setNetworkInfo(compressionNegotiated, countingConnFromContext(r.Context()))
+	ws.reconnects = s.countConnection(ws.id)
+	ws.admissionIP = admissionIP
+	ws.cleanedUpC = make(chan struct{})
+	admitted = true
 	// Add new client
 	s.connections[ws.id] = ws
 	s.connMutex.Unlock()
@@ -473,10 +868,80 @@ func (s *server) handleMessage(w Channel, data []byte) error {
 func (s *server) handleDisconnect(w Channel, _ error) {
 	// server never attempts to auto-reconnect to client. Resources are simply freed up
 	s.connMutex.Lock()
-	delete(s.connections, w.ID())
+	ws, _ := w.(*webSocket)
+	if current, ok := s.connections[w.ID()]; ok && current == ws {
+		delete(s.connections, w.ID())
+		if count, ok := s.connectionCounts[w.ID()]; ok {
+			count.disconnectedAt = time.Now()
+		}
+	}
+	_, replaced := s.replacing[ws]
+	delete(s.replacing, ws)
 	s.connMutex.Unlock()
-	log.Infof("closed connection to %s", w.ID())
+	if s.admission != nil && ws != nil {
+		s.admission.release(ws.admissionIP)
+	}
+	log.Infof("closed connection to %s: %v", w.ID(), w.DisconnectReason())
+	if replaced && s.replacedHandler != nil {
+		s.replacedHandler(w)
+	}
 	if s.disconnectedHandler != nil {
 		s.disconnectedHandler(w)
 	}
+	if ws != nil && ws.cleanedUpC != nil {
+		// Signal that the connection was fully cleaned up
+		close(ws.cleanedUpC)
+	}
+}
+
+// countConnection increments the connection counter for the passed ID and returns the previous value.
+// Counters of IDs, which stayed disconnected for longer than the retention period, are periodically evicted.
+//
+// Must be invoked while holding the connection lock.
+func (s *server) countConnection(id string) uint64 {
+	now := time.Now()
+	if now.Sub(s.lastConnectionCountSweep) >= s.connectionCountRetention {
+		for countID, count := range s.connectionCounts {
+			if !count.disconnectedAt.IsZero() && now.Sub(count.disconnectedAt) >= s.connectionCountRetention {
+				delete(s.connectionCounts, countID)
+			}
+		}
+		s.lastConnectionCountSweep = now
+	}
+	count, ok := s.connectionCounts[id]
+	if !ok {
+		count = &connectionCount{}
+		s.connectionCounts[id] = count
+	}
+	previous := count.connections
+	count.connections++
+	count.disconnectedAt = time.Time{}
+	return previous
+}
+
+// replaceConnection closes an existing connection, which is about to be replaced by a new connection with the same ID.
+// The function blocks until the connection was cleaned up and all handlers were invoked.
+func (s *server) replaceConnection(w *webSocket) error {
+	log.Infof("replacing existing connection for %s", w.ID())
+	s.connMutex.Lock()
+	// The connection may have been closed in the meantime, in which case it isn't considered replaced
+	if current, ok := s.connections[w.ID()]; ok && current == w {
+		s.replacing[w] = struct{}{}
+	}
+	s.connMutex.Unlock()
+	err := w.Close(websocket.CloseError{Code: websocket.ClosePolicyViolation, Text: ErrConnectionReplaced.Error()})
+	if err != nil {
+		// Connection was already closed in the meantime
+		log.Debugf("existing connection for %s already closed: %v", w.ID(), err)
+	}
+	// Closing the connection is bounded by the write timeout
+	select {
+	case <-w.cleanedUpC:
+		return nil
+	case <-time.After(s.timeoutConfig.WriteWait + shutdownPollInterval):
+		s.connMutex.Lock()
+		delete(s.replacing, w)
+		s.connMutex.Unlock()
+		return fmt.Errorf("timeout while replacing existing connection for %s", w.ID())
+	}
 }
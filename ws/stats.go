@@ -0,0 +1,68 @@
+package ws
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// ConnectionStats contains statistics and liveness information about a single channel.
+//
+// Byte counters, both for the application payload and the raw bytes transmitted over the network,
+// are available via the CompressionStats method of the channel.
+type ConnectionStats struct {
+	ConnectedAt   time.Time     // The time the channel was established.
+	MessagesIn    uint64        // The amount of messages received from the peer.
+	MessagesOut   uint64        // The amount of messages sent to the peer.
+	LastMessageIn time.Time     // The time the last message was received from the peer. Zero if no message was received yet.
+	LastPingRTT   time.Duration // The round-trip time of the last ping/pong exchange. Only measured if the channel sends pings, zero otherwise.
+	LastPongIn    time.Time     // The time the last pong was received from the peer. Zero if no pong was received yet.
+	Reconnects    uint64        // The amount of channels with the same ID, which were established before this one (i.e. the amount of reconnections). Servers forget about IDs, which stayed disconnected for longer than 24 hours.
+	Subprotocol   string        // The subprotocol negotiated during the handshake, if any.
+}
+
+func (w *webSocket) Stats() ConnectionStats {
+	return ConnectionStats{
+		ConnectedAt:   w.connectedAt,
+		MessagesIn:    atomic.LoadUint64(&w.messagesIn),
+		MessagesOut:   atomic.LoadUint64(&w.messagesOut),
+		LastMessageIn: unixNanoToTime(atomic.LoadInt64(&w.lastMessageIn)),
+		LastPingRTT:   time.Duration(atomic.LoadInt64(&w.lastPingRTT)),
+		LastPongIn:    unixNanoToTime(atomic.LoadInt64(&w.lastPongIn)),
+		Reconnects:    w.reconnects,
+		Subprotocol:   w.subprotocol,
+	}
+}
+
+// recordMessageIn updates the statistics after a message was received.
+func (w *webSocket) recordMessageIn(size int) {
+	atomic.AddUint64(&w.messagesIn, 1)
+	atomic.AddUint64(&w.payloadBytesIn, uint64(size))
+	atomic.StoreInt64(&w.lastMessageIn, time.Now().UnixNano())
+}
+
+// recordMessageOut updates the statistics after a message was sent.
+func (w *webSocket) recordMessageOut(size int) {
+	atomic.AddUint64(&w.messagesOut, 1)
+	atomic.AddUint64(&w.payloadBytesOut, uint64(size))
+}
+
+// recordPingOut remembers when the last ping was sent, for measuring the round-trip time.
+func (w *webSocket) recordPingOut() {
+	atomic.StoreInt64(&w.lastPingOut, time.Now().UnixNano())
+}
+
+// recordPongIn updates the round-trip time, after receiving a pong for the last ping.
+func (w *webSocket) recordPongIn() {
+	now := time.Now().UnixNano()
+	atomic.StoreInt64(&w.lastPongIn, now)
+	if sent := atomic.LoadInt64(&w.lastPingOut); sent != 0 {
+		atomic.StoreInt64(&w.lastPingRTT, now-sent)
+	}
+}
+
+func unixNanoToTime(nanos int64) time.Time {
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
@@ -0,0 +1,78 @@
+package ws
+
+import "time"
+
+// ChannelTimeoutConfig contains the timeouts of a single channel, which may be changed while the channel is open.
+//
+// Refer to the TimeoutConfig and SetTimeoutConfig functions of a Channel.
+type ChannelTimeoutConfig struct {
+	WriteWait  time.Duration // The timeout for network write operations. After a timeout, the connection is closed.
+	ReadWait   time.Duration // The timeout for waiting for a message or a ping from the peer. If set to 0 and no pings are sent, reads never time out.
+	PingPeriod time.Duration // The interval for sending ping messages to the peer. If set to 0, no pings are sent.
+	PongWait   time.Duration // The timeout for waiting for a pong from the peer. Takes precedence over ReadWait, if pings are sent.
+}
+
+func (w *webSocket) TimeoutConfig() ChannelTimeoutConfig {
+	w.cfgMutex.RLock()
+	defer w.cfgMutex.RUnlock()
+	config := ChannelTimeoutConfig{
+		WriteWait: w.cfg.WriteWait,
+		ReadWait:  w.cfg.ReadWait,
+	}
+	if w.cfg.PingConfig != nil {
+		config.PingPeriod = w.cfg.PingConfig.PingPeriod
+		config.PongWait = w.cfg.PingConfig.PongWait
+	}
+	return config
+}
+
+func (w *webSocket) SetTimeoutConfig(config ChannelTimeoutConfig) {
+	w.cfgMutex.Lock()
+	w.cfg.WriteWait = config.WriteWait
+	w.cfg.ReadWait = config.ReadWait
+	if config.PingPeriod > 0 {
+		w.cfg.PingConfig = &PingConfig{PingPeriod: config.PingPeriod, PongWait: config.PongWait}
+	} else {
+		w.cfg.PingConfig = nil
+	}
+	w.cfgMutex.Unlock()
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	if w.connection == nil {
+		return
+	}
+	// Apply the new read timeout immediately, instead of waiting for the next message
+	_ = w.connection.SetReadDeadline(w.getReadTimeout())
+	// Notify the writePump, which restarts the ping ticker
+	select {
+	case w.configC <- struct{}{}:
+	default:
+	}
+	w.log.Debugf("timeouts for %s changed: ping period %v, pong wait %v, write wait %v", w.id, config.PingPeriod, config.PongWait, config.WriteWait)
+}
+
+// getWriteWait returns the current timeout for write operations.
+func (w *webSocket) getWriteWait() time.Duration {
+	w.cfgMutex.RLock()
+	defer w.cfgMutex.RUnlock()
+	return w.cfg.WriteWait
+}
+
+// getPingConfig returns the current ping configuration, or nil if no pings are sent.
+func (w *webSocket) getPingConfig() *PingConfig {
+	w.cfgMutex.RLock()
+	defer w.cfgMutex.RUnlock()
+	return w.cfg.PingConfig
+}
+
+// WithPingPeriod returns a copy of the configuration, using the passed ping period.
+// The pong wait is derived from the ping period, as in the default configuration
+// (e.g. a ping period of 54 seconds results in a pong wait of 60 seconds).
+// If the period is zero, pings are disabled and the pong wait is left untouched.
+func (c ClientTimeoutConfig) WithPingPeriod(period time.Duration) ClientTimeoutConfig {
+	c.PingPeriod = period
+	if period > 0 {
+		c.PongWait = (period * 10) / 9
+	}
+	return c
+}
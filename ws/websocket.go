@@ -6,9 +6,11 @@ package ws
 
 import (
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"net"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -18,6 +20,8 @@ import (
 const (
 	// Time allowed to write a message to the peer.
 	defaultWriteWait = 10 * time.Second
+	// Interval at which open connections are checked, while a server is shutting down.
+	shutdownPollInterval = 50 * time.Millisecond
 	// Time allowed to read the next pong message from the peer.
 	defaultPongWait = 60 * time.Second
 	// Time allowed to wait for a ping on the server, before closing a connection due to inactivity.
@@ -41,6 +45,9 @@ const (
 	defaultRetryBackOffWaitMinimum = 10 * time.Second
 )
 
+// ErrConnectionReplaced is used when closing a channel, which was replaced by a new connection with the same ID.
+var ErrConnectionReplaced = errors.New("connection replaced by a new connection with the same ID")
+
 // The internal verbose logger
 var log logging.Logger
 
@@ -65,6 +72,7 @@ type ServerTimeoutConfig struct {
 	PingWait   time.Duration // The timeout for waiting for a ping from the client. After a timeout, the connection is closed.
 	PingPeriod time.Duration // The interval for sending ping messages to a client. If set to 0, no pings are sent.
 	PongWait   time.Duration // The timeout for waiting for a pong from the server. After a timeout, the connection is closed. Needs to be set, if server is configured to send ping messages.
+	ReadLimit  int64         // The maximum size in bytes of a single incoming message. Larger messages cause the connection to be closed with a CloseMessageTooBig. If set to 0, no limit is enforced.
 }
 
 // NewServerTimeoutConfig creates a default timeout configuration for a websocket endpoint.
@@ -93,6 +101,7 @@ type ClientTimeoutConfig struct {
 	RetryBackOffRepeatTimes int
 	RetryBackOffRandomRange int
 	RetryBackOffWaitMinimum time.Duration
+	ReadLimit               int64 // The maximum size in bytes of a single incoming message. Larger messages cause the connection to be closed with a CloseMessageTooBig. If set to 0, no limit is enforced.
 }
 
 // NewClientTimeoutConfig creates a default timeout configuration for a websocket endpoint.
@@ -153,6 +162,32 @@ type Channel interface {
 	TLSConnectionState() *tls.ConnectionState
 	// IsConnected returns true if the connection to the peer is active, false if it was closed already.
 	IsConnected() bool
+	// CompressionStats returns statistics about the messages exchanged on the channel,
+	// which may be used to evaluate the effectiveness of compression.
+	CompressionStats() CompressionStats
+	// SetCompressionEnabled enables or disables the compression of outgoing messages on the channel.
+	// The function has no effect if the permessage-deflate extension wasn't negotiated during the handshake.
+	SetCompressionEnabled(enabled bool)
+	// SecurityProfile returns the OCPP security profile, which was enforced when establishing the channel.
+	SecurityProfile() SecurityProfile
+	// TimeoutConfig returns the timeouts currently used by the channel.
+	TimeoutConfig() ChannelTimeoutConfig
+	// SetTimeoutConfig changes the timeouts of the open channel, without reconnecting.
+	// The new ping period takes effect immediately, as does the new read timeout.
+	SetTimeoutConfig(config ChannelTimeoutConfig)
+	// DisconnectReason returns the reason why the channel was closed, or nil if the channel is still open.
+	DisconnectReason() *DisconnectReason
+	// Stats returns statistics and liveness information about the channel,
+	// such as message counters and the round-trip time of the last ping.
+	Stats() ConnectionStats
+	// SetAttribute attaches an arbitrary value to the channel, e.g. the tenant or site a charging station belongs to.
+	// Attributes live as long as the channel, and are still accessible after the channel was closed.
+	// Passing a nil value removes the attribute.
+	SetAttribute(key string, value interface{})
+	// Attribute returns the value of an attribute previously attached to the channel.
+	Attribute(key string) (interface{}, bool)
+	// Attributes returns a copy of all attributes attached to the channel.
+	Attributes() map[string]interface{}
 }
 
 // WebSocketConfig is a utility config struct for a single webSocket.
@@ -176,6 +211,15 @@ type WebSocketConfig struct {
 	ReadWait time.Duration
 	// Optional configuration for ping operations. If omitted, the websocket will not send any pings.
 	PingConfig *PingConfig
+	// The maximum size in bytes of a single message read from the connected peer.
+	// If a message exceeds the limit, the connection is closed with a CloseMessageTooBig close code.
+	// If ReadLimit is zero, no limit is enforced.
+	ReadLimit int64
+	// Optional compression configuration for outgoing messages.
+	// The configuration only takes effect if the permessage-deflate extension was negotiated during the handshake.
+	Compression CompressionConfig
+	// The OCPP security profile, which was enforced when establishing the connection.
+	SecurityProfile SecurityProfile
 	// Optional logger for the websocket. If omitted, the global logger is used.
 	Logger logging.Logger
 }
@@ -225,6 +269,16 @@ type message struct {
 //
 // Don't use a websocket directly, but refer to Server and Client.
 type webSocket struct {
+	// Traffic statistics, accessed atomically. Kept at the beginning of the struct for 64-bit alignment.
+	messagesCompressed uint64
+	payloadBytesIn     uint64
+	payloadBytesOut    uint64
+	messagesIn         uint64
+	messagesOut        uint64
+	lastMessageIn      int64 // unix nanoseconds
+	lastPingOut        int64 // unix nanoseconds
+	lastPongIn         int64 // unix nanoseconds
+	lastPingRTT        int64
 	connection         *websocket.Conn
 	mutex              sync.RWMutex
 	id                 string
@@ -234,10 +288,29 @@ type webSocket struct {
 	forceCloseC        chan error                // used by the readPump to notify a forcefully closed connection to the writePump.
 	tlsConnectionState *tls.ConnectionState
 	cfg                WebSocketConfig
+	cfgMutex           sync.RWMutex  // guards the timeouts in cfg, which may be changed while the websocket is running.
+	readLimit          int64         // the read limit applied to the connection, guarded by cfgMutex.
+	configC            chan struct{} // used to notify the writePump of changed timeouts.
 	log                logging.Logger
 	onClosed           DisconnectedHandler
 	onError            ErrorHandler
 	onMessage          MessageHandler
+	// Compression and traffic information
+	compressionNegotiated bool
+	writeCompression      int32
+	netConn               *countingConn
+	// Connection information
+	connectedAt time.Time
+	subprotocol string
+	reconnects  uint64
+	admissionIP string // source IP, for which a slot was reserved by the admission control
+	// Closed by the server, once the connection was cleaned up and all handlers were invoked
+	cleanedUpC chan struct{}
+	// Set once the channel was closed
+	disconnectReason *DisconnectReason
+	// Custom attributes, set by the application
+	attributes     map[string]interface{}
+	attributeMutex sync.RWMutex
 }
 
 func newWebSocket(id string, conn *websocket.Conn, tlsState *tls.ConnectionState, cfg WebSocketConfig, onMessage MessageHandler, onClosed DisconnectedHandler, onError ErrorHandler) *webSocket {
@@ -253,14 +326,31 @@ func newWebSocket(id string, conn *websocket.Conn, tlsState *tls.ConnectionState
 		pingC:              make(chan []byte, 1),
 		closeC:             make(chan websocket.CloseError, 1),
 		forceCloseC:        make(chan error, 1),
+		configC:            make(chan struct{}, 1),
 		onClosed:           onClosed,
 		onError:            onError,
 		onMessage:          onMessage,
+		connectedAt:        time.Now(),
+		subprotocol:        conn.Subprotocol(),
 	}
 	w.updateConfig(cfg)
 	return w
 }
 
+// setNetworkInfo passes information about the underlying network connection, gathered during the handshake.
+// Must be invoked before running the websocket.
+func (w *webSocket) setNetworkInfo(compressionNegotiated bool, netConn *countingConn) {
+	w.compressionNegotiated = compressionNegotiated
+	if compressionNegotiated && w.cfg.Compression.Enabled {
+		w.writeCompression = 1
+	}
+	w.netConn = netConn
+	if netConn != nil {
+		// Only track traffic from this point onwards, ignoring the handshake
+		netConn.reset()
+	}
+}
+
 // Retrieves the unique Identifier of the websocket (typically, the URL suffix).
 func (w *webSocket) ID() string {
 	return w.id
@@ -276,12 +366,52 @@ func (w *webSocket) TLSConnectionState() *tls.ConnectionState {
 	return w.tlsConnectionState
 }
 
+func (w *webSocket) SecurityProfile() SecurityProfile {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	return w.cfg.SecurityProfile
+}
+
 func (w *webSocket) IsConnected() bool {
 	w.mutex.RLock()
 	defer w.mutex.RUnlock()
 	return w.connection != nil
 }
 
+func (w *webSocket) CompressionStats() CompressionStats {
+	stats := CompressionStats{
+		Negotiated:         w.compressionNegotiated,
+		MessagesCompressed: atomic.LoadUint64(&w.messagesCompressed),
+		PayloadBytesIn:     atomic.LoadUint64(&w.payloadBytesIn),
+		PayloadBytesOut:    atomic.LoadUint64(&w.payloadBytesOut),
+	}
+	if w.netConn != nil {
+		stats.WireBytesIn = w.netConn.BytesIn()
+		stats.WireBytesOut = w.netConn.BytesOut()
+	}
+	return stats
+}
+
+func (w *webSocket) SetCompressionEnabled(enabled bool) {
+	if !w.compressionNegotiated {
+		return
+	}
+	if enabled {
+		atomic.StoreInt32(&w.writeCompression, 1)
+	} else {
+		atomic.StoreInt32(&w.writeCompression, 0)
+	}
+}
+
+// compressMessage decides whether an outgoing message should be compressed,
+// based on the current compression settings and the message size.
+func (w *webSocket) compressMessage(msg message) bool {
+	if atomic.LoadInt32(&w.writeCompression) == 0 {
+		return false
+	}
+	return len(msg.data) >= w.cfg.Compression.Threshold
+}
+
 func (w *webSocket) Write(data []byte) error {
 	return w.WriteManual(websocket.TextMessage, data)
 }
@@ -313,18 +443,39 @@ func (w *webSocket) Close(closeError websocket.CloseError) error {
 func (w *webSocket) updateConfig(cfg WebSocketConfig) {
 	w.mutex.Lock()
 	defer w.mutex.Unlock()
+	w.cfgMutex.Lock()
 	w.cfg = cfg
+	w.readLimit = cfg.ReadLimit
+	w.cfgMutex.Unlock()
 	// Update logger
 	if cfg.Logger != nil {
 		w.log = cfg.Logger
 	} else {
 		w.log = log
 	}
+	// Update read limit
+	w.connection.SetReadLimit(w.readLimit)
+	// Update compression level
+	if cfg.Compression.Enabled {
+		if !cfg.Compression.isValidLevel() {
+			log.Errorf("invalid compression level %d for %s, using default level", cfg.Compression.Level, w.id)
+		} else if err := w.connection.SetCompressionLevel(cfg.Compression.Level); err != nil {
+			log.Errorf("failed to set compression level for %s: %v", w.id, err)
+		}
+	}
 	// Update ping pong logic
 	w.initPingPong()
 }
 
+func (w *webSocket) getReadLimit() int64 {
+	w.cfgMutex.RLock()
+	defer w.cfgMutex.RUnlock()
+	return w.readLimit
+}
+
 func (w *webSocket) getReadTimeout() time.Time {
+	w.cfgMutex.RLock()
+	defer w.cfgMutex.RUnlock()
 	var wait time.Duration
 	// Prefer ping config, then read wait, then no timeout
 	if w.cfg.PingConfig != nil && w.cfg.PingConfig.PongWait > 0 {
@@ -339,27 +490,30 @@ func (w *webSocket) getReadTimeout() time.Time {
 }
 
 func (w *webSocket) initPingPong() {
+	// Handlers are always set, since timeouts may be changed while the websocket is running.
+	// Incoming pings are answered with pongs, incoming pongs are expected after sending pings.
 	conn := w.connection
-	if w.cfg.ReadWait > 0 {
-		// Expect pings, reply with pongs
-		conn.SetPingHandler(w.onPing)
-	} else {
-		conn.SetPingHandler(nil)
-	}
-	if w.cfg.PingConfig != nil {
-		// Optionally send pings, expect pongs
-		conn.SetPongHandler(w.onPong)
-	} else {
-		conn.SetPongHandler(nil)
-	}
+	conn.SetPingHandler(w.onPing)
+	conn.SetPongHandler(w.onPong)
 }
 
 func (w *webSocket) onPing(appData string) error {
-	conn := w.connection
 	w.log.Debugf("ping received from %s: %s", w.id, appData)
+	w.mutex.RLock()
+	conn := w.connection
+	if conn == nil {
+		w.mutex.RUnlock()
+		return nil
+	}
 	// Schedule pong message via dedicated channel
-	w.pingC <- []byte(appData)
-	w.log.Debugf("pong scheduled for %s", w.id)
+	select {
+	case w.pingC <- []byte(appData):
+		w.log.Debugf("pong scheduled for %s", w.id)
+	default:
+		// A pong is already scheduled, there is no need to reply to every ping
+		w.log.Debugf("pong already scheduled for %s", w.id)
+	}
+	w.mutex.RUnlock()
 	// Reset read interval after receiving a ping
 	return conn.SetReadDeadline(w.getReadTimeout())
 }
@@ -367,12 +521,14 @@ func (w *webSocket) onPing(appData string) error {
 func (w *webSocket) onPong(appData string) error {
 	conn := w.connection
 	w.log.Debugf("pong received from %s: %s", w.id, appData)
+	w.recordPongIn()
 	// Reset read interval after receiving a pong
 	return conn.SetReadDeadline(w.getReadTimeout())
 }
 
-func (w *webSocket) cleanup(err error) {
+func (w *webSocket) cleanup(reason *DisconnectReason, err error) {
 	w.mutex.Lock()
+	w.disconnectReason = reason
 	// Properly close the connection
 	if e := w.connection.Close(); e != nil {
 		log.Errorf("failed to close connection for %s: %v", w.id, e)
@@ -408,7 +564,9 @@ func (w *webSocket) readPump() {
 	for {
 		_, msg, err := conn.ReadMessage()
 		if err != nil {
-			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure, websocket.CloseNormalClosure) {
+			if errors.Is(err, websocket.ErrReadLimit) {
+				w.onError(w, fmt.Errorf("read failed for %s: message exceeds read limit of %d bytes", w.id, w.getReadLimit()))
+			} else if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure, websocket.CloseNormalClosure) {
 				w.onError(w, fmt.Errorf("read failed unexpectedly for %s: %w", w.id, err))
 			}
 			// Verify whether the disconnect was already dealt with
@@ -426,6 +584,7 @@ func (w *webSocket) readPump() {
 			return
 		}
 
+		w.recordMessageIn(len(msg))
 		// Forward message to handler.
 		// Errors during the handling don't interrupt the websocket routine but will be reported.
 		err = w.onMessage(w, msg)
@@ -439,34 +598,35 @@ func (w *webSocket) readPump() {
 // All actions and events are handled within this centralized control flow function.
 func (w *webSocket) writePump() {
 	conn := w.connection
-	ticker := newOptTicker(w.cfg.PingConfig)
+	ticker := newOptTicker(w.getPingConfig())
 
-	closure := func(err error) {
+	closure := func(reason *DisconnectReason, err error) {
 		ticker.Stop()
-		w.cleanup(err)
+		w.cleanup(reason, err)
 	}
 
 	for {
 		select {
 		case <-ticker.T():
 			// Send periodic ping
-			_ = conn.SetWriteDeadline(time.Now().Add(w.cfg.WriteWait))
+			_ = conn.SetWriteDeadline(time.Now().Add(w.getWriteWait()))
+			w.recordPingOut()
 			err := conn.WriteMessage(websocket.PingMessage, []byte{})
 			if err != nil {
 				w.onError(w, fmt.Errorf("failed to send ping message for %s: %w", w.id, err))
 				// Invoking cleanup, as socket was forcefully closed
-				closure(err)
+				closure(newForcedDisconnectReason(err), err)
 				return
 			}
 			log.Debugf("ping sent for %s", w.id)
 		case ping := <-w.pingC:
 			// Reply with pong message
-			_ = conn.SetWriteDeadline(time.Now().Add(w.cfg.WriteWait))
+			_ = conn.SetWriteDeadline(time.Now().Add(w.getWriteWait()))
 			err := conn.WriteMessage(websocket.PongMessage, ping)
 			if err != nil {
 				w.onError(w, fmt.Errorf("failed to send pong message %s: %w", w.id, err))
 				// Invoking cleanup, as socket was forcefully closed
-				closure(err)
+				closure(newForcedDisconnectReason(err), err)
 				return
 			}
 			log.Debugf("pong sent for %s: %s", w.id, string(ping))
@@ -479,15 +639,26 @@ func (w *webSocket) writePump() {
 				return
 			}
 			// Send data
-			_ = conn.SetWriteDeadline(time.Now().Add(w.cfg.WriteWait))
+			compress := w.compressMessage(msg)
+			conn.EnableWriteCompression(compress)
+			_ = conn.SetWriteDeadline(time.Now().Add(w.getWriteWait()))
 			err := conn.WriteMessage(msg.typ, msg.data)
 			if err != nil {
 				w.onError(w, fmt.Errorf("write failed for %s: %w", w.id, err))
 				// Invoking cleanup, as socket was forcefully closed
-				closure(err)
+				closure(newForcedDisconnectReason(err), err)
 				return
 			}
+			w.recordMessageOut(len(msg.data))
+			if compress {
+				atomic.AddUint64(&w.messagesCompressed, 1)
+			}
 			log.Debugf("written %d bytes to %s", len(msg.data), w.id)
+		case <-w.configC:
+			// Timeouts changed, restart the ping ticker with the new period
+			ticker.Stop()
+			ticker = newOptTicker(w.getPingConfig())
+			log.Debugf("updated timeouts for %s", w.id)
 		case closeErr := <-w.closeC:
 			// webSocket is being gracefully closed by user command
 			w.log.Debugf("closing connection for %s: %d - %s", w.id, closeErr.Code, closeErr.Text)
@@ -495,7 +666,7 @@ func (w *webSocket) writePump() {
 			err := conn.WriteControl(
 				websocket.CloseMessage,
 				websocket.FormatCloseMessage(closeErr.Code, closeErr.Text),
-				time.Now().Add(w.cfg.WriteWait))
+				time.Now().Add(w.getWriteWait()))
 			if err != nil {
 				// At this point the connection is considered to be forcefully closed,
 				// but we still continue with the intended flow.
@@ -503,7 +674,7 @@ func (w *webSocket) writePump() {
 			}
 			// Invoking cleanup, but signal that this is an intended operation,
 			// preventing automatic reconnection attempts.
-			closure(nil)
+			closure(newLocalDisconnectReason(closeErr, err), nil)
 			return
 		case closed, _ := <-w.forceCloseC:
 			if closed == nil {
@@ -512,7 +683,7 @@ func (w *webSocket) writePump() {
 			// webSocket is being forcefully closed, triggered by readPump encountering a failed read.
 			log.Debugf("handling forced close signal for %s, caused by: %v", w.id, closed.Error())
 			// Connection was forcefully closed, invoke cleanup
-			closure(closed)
+			closure(newForcedDisconnectReason(closed), closed)
 			return
 		}
 	}
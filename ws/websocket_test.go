@@ -1,19 +1,24 @@
 package ws
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/base64"
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"io"
 	"math/big"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
 	"path"
@@ -297,6 +302,242 @@ func (s *WebSocketSuite) TestWebsocketEcho() {
 	}
 }
 
+func (s *WebSocketSuite) TestWebsocketCompression() {
+	// Highly compressible message, above the threshold
+	largeMsg := []byte(strings.Repeat(`{"measurand":"Energy.Active.Import.Register","value":"1234"}`, 50))
+	smallMsg := []byte("Hello webSocket!")
+	receivedC := make(chan []byte, 2)
+	connectedC := make(chan Channel, 1)
+	s.server = newWebsocketServer(s.T(), func(data []byte) ([]byte, error) {
+		receivedC <- data
+		return nil, nil
+	})
+	s.server.SetNewClientHandler(func(ws Channel) {
+		connectedC <- ws
+	})
+	s.client = newWebsocketClient(s.T(), nil)
+	compressionConfig := NewCompressionConfig()
+	compressionConfig.Level = 9
+	compressionConfig.Threshold = 100
+	WithServerCompression(compressionConfig)(s.server)
+	WithClientCompression(compressionConfig)(s.client)
+	// Start server
+	go s.server.Start(serverPort, serverPath)
+	time.Sleep(100 * time.Millisecond)
+	// Connect client
+	host := fmt.Sprintf("localhost:%v", serverPort)
+	u := url.URL{Scheme: "ws", Host: host, Path: testPath}
+	err := s.client.Start(u.String())
+	s.Require().NoError(err)
+	serverChannel := <-connectedC
+	s.True(serverChannel.CompressionStats().Negotiated)
+	s.True(s.client.webSocket.CompressionStats().Negotiated)
+	// Send messages
+	err = s.client.Write(largeMsg)
+	s.Require().NoError(err)
+	err = s.client.Write(smallMsg)
+	s.Require().NoError(err)
+	for _, expected := range [][]byte{largeMsg, smallMsg} {
+		select {
+		case data := <-receivedC:
+			s.Equal(expected, data)
+		case <-time.After(1 * time.Second):
+			s.Fail("timeout waiting for message")
+		}
+	}
+	// Only the large message was compressed
+	clientStats := s.client.webSocket.CompressionStats()
+	s.Equal(uint64(1), clientStats.MessagesCompressed)
+	s.Equal(uint64(len(largeMsg)+len(smallMsg)), clientStats.PayloadBytesOut)
+	s.Less(clientStats.WireBytesOut, clientStats.PayloadBytesOut)
+	s.Greater(clientStats.OutgoingRatio(), 0.0)
+	s.Less(clientStats.OutgoingRatio(), 0.5)
+	serverStats := serverChannel.CompressionStats()
+	s.Equal(uint64(len(largeMsg)+len(smallMsg)), serverStats.PayloadBytesIn)
+	s.Equal(clientStats.WireBytesOut, serverStats.WireBytesIn)
+	s.Less(serverStats.IncomingRatio(), 0.5)
+	// Disable compression on client channel
+	s.client.webSocket.SetCompressionEnabled(false)
+	err = s.client.Write(largeMsg)
+	s.Require().NoError(err)
+	<-receivedC
+	s.Equal(uint64(1), s.client.webSocket.CompressionStats().MessagesCompressed)
+}
+
+func (s *WebSocketSuite) TestTLSWebsocketCompressionStats() {
+	largeMsg := []byte(strings.Repeat(`{"measurand":"Energy.Active.Import.Register","value":"1234"}`, 50))
+	receivedC := make(chan []byte, 1)
+	connectedC := make(chan Channel, 1)
+	s.server = newWebsocketServer(s.T(), func(data []byte) ([]byte, error) {
+		receivedC <- data
+		return nil, nil
+	})
+	s.server.SetNewClientHandler(func(ws Channel) {
+		connectedC <- ws
+	})
+	certFilename := "/tmp/cert.pem"
+	keyFilename := "/tmp/key.pem"
+	err := createTLSCertificate(certFilename, keyFilename, "localhost", nil, nil)
+	s.Require().NoError(err)
+	defer os.Remove(certFilename)
+	defer os.Remove(keyFilename)
+	s.server.tlsCertificatePath = certFilename
+	s.server.tlsCertificateKey = keyFilename
+	s.client = newWebsocketClient(s.T(), nil)
+	s.client.AddOption(func(dialer *websocket.Dialer) {
+		certPool := x509.NewCertPool()
+		data, err := os.ReadFile(certFilename)
+		s.NoError(err)
+		s.True(certPool.AppendCertsFromPEM(data))
+		dialer.TLSClientConfig = &tls.Config{RootCAs: certPool}
+	})
+	compressionConfig := NewCompressionConfig()
+	compressionConfig.Level = 9
+	WithServerCompression(compressionConfig)(s.server)
+	WithClientCompression(compressionConfig)(s.client)
+	// Start server
+	go s.server.Start(serverPort, serverPath)
+	time.Sleep(100 * time.Millisecond)
+	// Connect client
+	host := fmt.Sprintf("localhost:%v", serverPort)
+	u := url.URL{Scheme: "wss", Host: host, Path: testPath}
+	err = s.client.Start(u.String())
+	s.Require().NoError(err)
+	serverChannel := <-connectedC
+	s.Require().NotNil(serverChannel.TLSConnectionState())
+	err = s.client.Write(largeMsg)
+	s.Require().NoError(err)
+	select {
+	case data := <-receivedC:
+		s.Equal(largeMsg, data)
+	case <-time.After(1 * time.Second):
+		s.Fail("timeout waiting for message")
+	}
+	// Wire bytes are counted below the TLS layer on both endpoints
+	clientStats := s.client.webSocket.CompressionStats()
+	serverStats := serverChannel.CompressionStats()
+	s.True(serverStats.Negotiated)
+	s.Equal(uint64(len(largeMsg)), serverStats.PayloadBytesIn)
+	s.Greater(serverStats.WireBytesIn, uint64(0))
+	s.Equal(clientStats.WireBytesOut, serverStats.WireBytesIn)
+	s.Greater(serverStats.IncomingRatio(), 0.0)
+	s.Less(serverStats.IncomingRatio(), 0.5)
+}
+
+func (s *WebSocketSuite) TestWebsocketCompressionNotNegotiated() {
+	connectedC := make(chan Channel, 1)
+	s.server.SetNewClientHandler(func(ws Channel) {
+		connectedC <- ws
+	})
+	// Only the client supports compression
+	WithClientCompression(NewCompressionConfig())(s.client)
+	// Start server
+	go s.server.Start(serverPort, serverPath)
+	time.Sleep(100 * time.Millisecond)
+	// Connect client
+	host := fmt.Sprintf("localhost:%v", serverPort)
+	u := url.URL{Scheme: "ws", Host: host, Path: testPath}
+	err := s.client.Start(u.String())
+	s.Require().NoError(err)
+	serverChannel := <-connectedC
+	s.False(serverChannel.CompressionStats().Negotiated)
+	s.False(s.client.webSocket.CompressionStats().Negotiated)
+	// Messages are sent uncompressed
+	msg := []byte(strings.Repeat("a", 1024))
+	err = s.client.Write(msg)
+	s.Require().NoError(err)
+	time.Sleep(100 * time.Millisecond)
+	clientStats := s.client.webSocket.CompressionStats()
+	s.Equal(uint64(0), clientStats.MessagesCompressed)
+	s.Greater(clientStats.WireBytesOut, clientStats.PayloadBytesOut)
+}
+
+func (s *WebSocketSuite) TestWebsocketServeOnListener() {
+	msg := []byte("Hello webSocket!")
+	receivedC := make(chan []byte, 1)
+	s.server = newWebsocketServer(s.T(), func(data []byte) ([]byte, error) {
+		receivedC <- data
+		return nil, nil
+	})
+	// Listen on a random port, chosen by the caller
+	ln, err := net.Listen("tcp", "localhost:0")
+	s.Require().NoError(err)
+	go s.server.Serve(ln, serverPath)
+	time.Sleep(100 * time.Millisecond)
+	s.Equal(ln.Addr().(*net.TCPAddr).Port, s.server.Addr().Port)
+	// Connect client
+	u := url.URL{Scheme: "ws", Host: ln.Addr().String(), Path: testPath}
+	err = s.client.Start(u.String())
+	s.Require().NoError(err)
+	s.True(s.client.IsConnected())
+	c, ok := s.server.GetChannel(path.Base(testPath))
+	s.True(ok)
+	s.NotNil(c)
+	// Test message
+	err = s.client.Write(msg)
+	s.Require().NoError(err)
+	select {
+	case data := <-receivedC:
+		s.Equal(msg, data)
+	case <-time.After(1 * time.Second):
+		s.Fail("timeout waiting for message")
+	}
+}
+
+func (s *WebSocketSuite) TestWebsocketHandler() {
+	msg := []byte("Hello webSocket!")
+	receivedC := make(chan []byte, 1)
+	disconnectedC := make(chan struct{}, 1)
+	s.server = newWebsocketServer(s.T(), func(data []byte) ([]byte, error) {
+		receivedC <- data
+		return nil, nil
+	})
+	s.server.SetDisconnectedClientHandler(func(ws Channel) {
+		disconnectedC <- struct{}{}
+	})
+	// Mount handler on external http server
+	mux := http.NewServeMux()
+	mux.Handle("/ocpp/", s.server.Handler())
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	httpServer := httptest.NewServer(mux)
+	defer httpServer.Close()
+	// Regular http endpoint is unaffected
+	resp, err := http.Get(httpServer.URL + "/health")
+	s.Require().NoError(err)
+	s.Equal(http.StatusOK, resp.StatusCode)
+	_ = resp.Body.Close()
+	// Connect client
+	u, err := url.Parse(httpServer.URL)
+	s.Require().NoError(err)
+	u.Scheme = "ws"
+	u.Path = "/ocpp/testws"
+	err = s.client.Start(u.String())
+	s.Require().NoError(err)
+	c, ok := s.server.GetChannel("testws")
+	s.True(ok)
+	s.NotNil(c)
+	// Test message
+	err = s.client.Write(msg)
+	s.Require().NoError(err)
+	select {
+	case data := <-receivedC:
+		s.Equal(msg, data)
+	case <-time.After(1 * time.Second):
+		s.Fail("timeout waiting for message")
+	}
+	// Stopping the ws server closes the connection
+	s.server.Stop()
+	select {
+	case <-disconnectedC:
+	case <-time.After(1 * time.Second):
+		s.Fail("timeout waiting for disconnect")
+	}
+	_, ok = s.server.GetChannel("testws")
+	s.False(ok)
+}
+
 func (s *WebSocketSuite) TestWebsocketChargePointIdResolver() {
 	connected := make(chan string)
 	s.server = newWebsocketServer(s.T(), func(data []byte) ([]byte, error) {
@@ -536,6 +777,157 @@ func (s *WebSocketSuite) TestClientDuplicateConnection() {
 	s.True(ok)
 }
 
+func (s *WebSocketSuite) TestClientDuplicateConnectionReplace() {
+	s.server = newWebsocketServer(s.T(), nil)
+	WithDuplicateConnectionPolicy(DuplicateConnectionReplace)(s.server)
+	var mutex sync.Mutex
+	var events []string
+	var channels []Channel
+	connectedC := make(chan Channel, 2)
+	s.server.SetNewClientHandler(func(ws Channel) {
+		mutex.Lock()
+		events = append(events, "connected")
+		channels = append(channels, ws)
+		mutex.Unlock()
+		connectedC <- ws
+	})
+	s.server.SetReplacedClientHandler(func(ws Channel) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		events = append(events, "replaced")
+		s.Equal(channels[0], ws)
+	})
+	s.server.SetDisconnectedClientHandler(func(ws Channel) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if ws == channels[0] {
+			events = append(events, "disconnected")
+		}
+	})
+	// Start server
+	go s.server.Start(serverPort, serverPath)
+	time.Sleep(100 * time.Millisecond)
+	// Connect client 1
+	disconnectC := make(chan error, 1)
+	s.client = newWebsocketClient(s.T(), nil)
+	s.client.SetDisconnectedHandler(func(err error) {
+		s.client.SetDisconnectedHandler(nil)
+		disconnectC <- err
+	})
+	host := fmt.Sprintf("localhost:%v", serverPort)
+	u := url.URL{Scheme: "ws", Host: host, Path: testPath}
+	err := s.client.Start(u.String())
+	s.Require().NoError(err)
+	oldChannel := <-connectedC
+	// Connect client 2, replacing client 1
+	wsClient2 := newWebsocketClient(s.T(), nil)
+	err = wsClient2.Start(u.String())
+	s.Require().NoError(err)
+	defer wsClient2.Stop()
+	newChannel := <-connectedC
+	s.NotEqual(oldChannel, newChannel)
+	s.False(oldChannel.IsConnected())
+	s.True(newChannel.IsConnected())
+	// Client 1 was notified of the replacement
+	err = <-disconnectC
+	var wsErr *websocket.CloseError
+	s.Require().True(errors.As(err, &wsErr))
+	s.Equal(websocket.ClosePolicyViolation, wsErr.Code)
+	s.Equal(ErrConnectionReplaced.Error(), wsErr.Text)
+	// Handlers were invoked in the expected order
+	mutex.Lock()
+	s.Equal([]string{"connected", "replaced", "disconnected", "connected"}, events)
+	mutex.Unlock()
+	// The new connection is the active one
+	s.server.connMutex.RLock()
+	s.Equal(newChannel, s.server.connections[newChannel.ID()])
+	s.server.connMutex.RUnlock()
+}
+
+func (s *WebSocketSuite) TestClientDuplicateConnectionReplaceClosedConcurrently() {
+	s.server = newWebsocketServer(s.T(), nil)
+	disconnectedC := make(chan Channel, 1)
+	s.server.SetDisconnectedClientHandler(func(ws Channel) {
+		disconnectedC <- ws
+	})
+	s.server.SetReplacedClientHandler(func(ws Channel) {
+		s.Fail("unexpected replaced connection")
+	})
+	// The existing connection is closed, while the duplicate connection is being evaluated
+	WithDuplicateConnectionHandler(func(existing Channel, r *http.Request) DuplicateConnectionPolicy {
+		s.client.Stop()
+		s.Equal(existing, <-disconnectedC)
+		return DuplicateConnectionReplace
+	})(s.server)
+	connectedC := make(chan Channel, 2)
+	s.server.SetNewClientHandler(func(ws Channel) {
+		connectedC <- ws
+	})
+	// Start server
+	go s.server.Start(serverPort, serverPath)
+	time.Sleep(100 * time.Millisecond)
+	// Connect client 1
+	s.client = newWebsocketClient(s.T(), nil)
+	host := fmt.Sprintf("localhost:%v", serverPort)
+	u := url.URL{Scheme: "ws", Host: host, Path: testPath}
+	err := s.client.Start(u.String())
+	s.Require().NoError(err)
+	oldChannel := <-connectedC
+	// Connect client 2, which is accepted right away
+	wsClient2 := newWebsocketClient(s.T(), nil)
+	start := time.Now()
+	err = wsClient2.Start(u.String())
+	s.Require().NoError(err)
+	defer wsClient2.Stop()
+	newChannel := <-connectedC
+	s.Less(time.Since(start), s.server.timeoutConfig.WriteWait)
+	s.NotEqual(oldChannel, newChannel)
+	s.True(newChannel.IsConnected())
+	s.server.connMutex.RLock()
+	s.Equal(newChannel, s.server.connections[newChannel.ID()])
+	s.Empty(s.server.replacing)
+	s.server.connMutex.RUnlock()
+}
+
+func (s *WebSocketSuite) TestClientDuplicateConnectionHandler() {
+	s.server = newWebsocketServer(s.T(), nil)
+	handlerC := make(chan string, 1)
+	WithDuplicateConnectionHandler(func(existing Channel, r *http.Request) DuplicateConnectionPolicy {
+		handlerC <- existing.ID()
+		return DuplicateConnectionReject
+	})(s.server)
+	WithDuplicateConnectionPolicy(DuplicateConnectionReplace)(s.server)
+	s.server.SetReplacedClientHandler(func(ws Channel) {
+		s.Fail("unexpected replaced connection")
+	})
+	// Start server
+	go s.server.Start(serverPort, serverPath)
+	time.Sleep(100 * time.Millisecond)
+	// Connect client 1
+	s.client = newWebsocketClient(s.T(), nil)
+	host := fmt.Sprintf("localhost:%v", serverPort)
+	u := url.URL{Scheme: "ws", Host: host, Path: testPath}
+	err := s.client.Start(u.String())
+	s.Require().NoError(err)
+	// Try to connect client 2, which is rejected by the handler
+	disconnectC := make(chan error, 1)
+	wsClient2 := newWebsocketClient(s.T(), nil)
+	wsClient2.SetDisconnectedHandler(func(err error) {
+		wsClient2.SetDisconnectedHandler(nil)
+		disconnectC <- err
+	})
+	err = wsClient2.Start(u.String())
+	s.Require().NoError(err)
+	defer wsClient2.Stop()
+	s.Equal("testws", <-handlerC)
+	err = <-disconnectC
+	var wsErr *websocket.CloseError
+	s.Require().True(errors.As(err, &wsErr))
+	s.Equal(websocket.ClosePolicyViolation, wsErr.Code)
+	s.Equal("a connection with this ID already exists", wsErr.Text)
+	s.True(s.client.IsConnected())
+}
+
 func (s *WebSocketSuite) TestServerStopConnection() {
 	triggerC := make(chan struct{}, 1)
 	disconnectedClientC := make(chan struct{}, 1)
@@ -599,6 +991,62 @@ func (s *WebSocketSuite) TestServerStopConnection() {
 	// client will attempt to reconnect under the hood, but test finishes before this can happen
 }
 
+func (s *WebSocketSuite) TestServerShutdown() {
+	connectedC := make(chan struct{}, 1)
+	disconnectedClientC := make(chan error, 1)
+	disconnectedServerC := make(chan struct{}, 1)
+	s.server.SetNewClientHandler(func(ws Channel) {
+		connectedC <- struct{}{}
+	})
+	s.server.SetDisconnectedClientHandler(func(ws Channel) {
+		disconnectedServerC <- struct{}{}
+	})
+	s.client.SetDisconnectedHandler(func(err error) {
+		disconnectedClientC <- err
+	})
+	// Start server
+	go s.server.Start(serverPort, serverPath)
+	time.Sleep(100 * time.Millisecond)
+	// Connect client
+	host := fmt.Sprintf("localhost:%v", serverPort)
+	u := url.URL{Scheme: "ws", Host: host, Path: testPath}
+	err := s.client.Start(u.String())
+	s.Require().NoError(err)
+	_, ok := <-connectedC
+	s.True(ok)
+	// Shutdown server with grace period
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	shutdownC := make(chan error, 1)
+	go func() {
+		shutdownC <- s.server.Shutdown(ctx)
+	}()
+	time.Sleep(100 * time.Millisecond)
+	// Open connection is still usable, while new connections are refused
+	s.True(s.client.IsConnected())
+	wsClient2 := newWebsocketClient(s.T(), nil)
+	err = wsClient2.Start(u.String())
+	s.Error(err)
+	// Remaining connection is closed once the grace period expires
+	select {
+	case err = <-shutdownC:
+		s.NoError(err)
+	case <-time.After(1 * time.Second):
+		s.Fail("timeout waiting for server shutdown")
+	}
+	select {
+	case err = <-disconnectedClientC:
+		var closeErr *websocket.CloseError
+		s.Require().True(errors.As(err, &closeErr))
+		s.Equal(websocket.CloseGoingAway, closeErr.Code)
+	case <-time.After(1 * time.Second):
+		s.Fail("timeout waiting for client disconnect")
+	}
+	_, ok = <-disconnectedServerC
+	s.True(ok)
+	s.Empty(s.server.connections)
+}
+
 func (s *WebSocketSuite) TestWebsocketServerStopAllConnections() {
 	triggerC := make(chan struct{}, 1)
 	numClients := 5
@@ -1099,378 +1547,2018 @@ func (s *WebSocketSuite) TestInvalidClientTLSCertificate() {
 	s.Equal("remote error: tls: unknown certificate authority", netError.Error()) // tls.alertUnknownCA = 48
 }
 
-func (s *WebSocketSuite) TestUnsupportedSubProtocol() {
-	s.server.SetNewClientHandler(func(ws Channel) {
+func (s *WebSocketSuite) TestSecurityProfile1() {
+	authPassword := "testPassword"
+	s.server = newWebsocketServer(s.T(), nil)
+	WithServerSecurityProfile(SecurityProfile1)(s.server)
+	s.server.SetBasicAuthHandler(func(username string, password string) bool {
+		return password == authPassword
 	})
-	s.server.SetDisconnectedClientHandler(func(ws Channel) {
+	connected := make(chan Channel, 1)
+	s.server.SetNewClientHandler(func(ws Channel) {
+		connected <- ws
 	})
-	s.server.AddSupportedSubprotocol(defaultSubProtocol)
-	s.Len(s.server.upgrader.Subprotocols, 1)
-	// Test duplicate sub-protocol
-	s.server.AddSupportedSubprotocol(defaultSubProtocol)
-	s.Len(s.server.upgrader.Subprotocols, 1)
-	// Start server
+	// Run server
 	go s.server.Start(serverPort, serverPath)
 	time.Sleep(100 * time.Millisecond)
-
-	// Setup client
-	disconnectC := make(chan struct{})
-	s.client.SetDisconnectedHandler(func(err error) {
-		var wsErr *websocket.CloseError
-		ok := s.ErrorAs(err, &wsErr)
-		s.True(ok)
-		s.Equal(websocket.CloseProtocolError, wsErr.Code)
-		s.Equal("invalid or unsupported subprotocol", wsErr.Text)
-		s.client.SetDisconnectedHandler(nil)
-		close(disconnectC)
-	})
-	// Set invalid sub-protocol
-	s.client.AddOption(func(dialer *websocket.Dialer) {
-		dialer.Subprotocols = []string{"unsupportedSubProto"}
-	})
-	// Test
 	host := fmt.Sprintf("localhost:%v", serverPort)
 	u := url.URL{Scheme: "ws", Host: host, Path: testPath}
+	// Client without credentials is rejected before connecting
+	s.client = newWebsocketClient(s.T(), nil)
+	WithClientSecurityProfile(SecurityProfile1)(s.client)
 	err := s.client.Start(u.String())
-	s.NoError(err)
-	// Expect connection to be closed directly after start
+	s.ErrorIs(err, ErrSecurityProfileViolation)
+	// Username not matching the identity is rejected by the server
+	s.client = newWebsocketClient(s.T(), nil)
+	s.client.SetBasicAuth("otherId", authPassword)
+	err = s.client.Start(u.String())
+	var httpErr HttpConnectionError
+	s.Require().True(errors.As(err, &httpErr))
+	s.Equal(http.StatusUnauthorized, httpErr.HttpCode)
+	// Username matching the identity is accepted
+	s.client = newWebsocketClient(s.T(), nil)
+	WithClientSecurityProfile(SecurityProfile1)(s.client)
+	s.client.SetBasicAuth("testws", authPassword)
+	err = s.client.Start(u.String())
+	s.Require().NoError(err)
 	select {
-	case _, ok := <-disconnectC:
-		s.False(ok)
+	case channel := <-connected:
+		s.Equal(SecurityProfile1, channel.SecurityProfile())
 	case <-time.After(1 * time.Second):
-		s.Fail("timeout waiting for client disconnect")
+		s.Fail("timeout waiting for client to connect")
 	}
+	s.Equal(SecurityProfile1, s.client.webSocket.SecurityProfile())
 }
 
-func (s *WebSocketSuite) TestSetServerTimeoutConfig() {
-	disconnected := make(chan struct{})
-	s.server.SetNewClientHandler(func(ws Channel) {
-	})
-	s.server.SetDisconnectedClientHandler(func(ws Channel) {
-		// TODO: check for error with upcoming API
-		close(disconnected)
+func (s *WebSocketSuite) TestSecurityProfile2RequiresTLS() {
+	s.server = newWebsocketServer(s.T(), nil)
+	WithServerSecurityProfile(SecurityProfile2)(s.server)
+	s.server.SetBasicAuthHandler(func(username string, password string) bool {
+		return true
 	})
-	// Setting server timeout
-	config := NewServerTimeoutConfig()
-	pingWait := 400 * time.Millisecond
-	writeWait := 500 * time.Millisecond
-	config.PingWait = pingWait
-	config.WriteWait = writeWait
-	s.server.SetTimeoutConfig(config)
-	// Start server
+	errC := s.server.Errors()
+	// Server without certificate cannot be started
 	go s.server.Start(serverPort, serverPath)
-	time.Sleep(100 * time.Millisecond)
-	s.Equal(s.server.timeoutConfig.PingWait, pingWait)
-	s.Equal(s.server.timeoutConfig.WriteWait, writeWait)
-	// Run test
+	select {
+	case err := <-errC:
+		s.ErrorIs(err, ErrSecurityProfileViolation)
+	case <-time.After(1 * time.Second):
+		s.Fail("timeout waiting for server error")
+	}
+	// Client cannot connect over an insecure channel
+	s.client = newWebsocketClient(s.T(), nil)
+	WithClientSecurityProfile(SecurityProfile2)(s.client)
+	s.client.SetBasicAuth("testws", "testPassword")
 	host := fmt.Sprintf("localhost:%v", serverPort)
 	u := url.URL{Scheme: "ws", Host: host, Path: testPath}
 	err := s.client.Start(u.String())
-	s.NoError(err)
+	s.ErrorIs(err, ErrSecurityProfileViolation)
+}
+
+func (s *WebSocketSuite) TestSecurityProfile3() {
+	var ok bool
+	serverCertFilename := "/tmp/cert.pem"
+	serverKeyFilename := "/tmp/key.pem"
+	err := createTLSCertificate(serverCertFilename, serverKeyFilename, "localhost", nil, nil)
+	s.Require().NoError(err)
+	defer os.Remove(serverCertFilename)
+	defer os.Remove(serverKeyFilename)
+	// Client certificates are signed by a CA, trusted by the server
+	caCertFilename := "/tmp/ca.pem"
+	caKeyFilename := "/tmp/ca_key.pem"
+	ca, caKey, err := createCACertificate(caCertFilename, caKeyFilename)
+	s.Require().NoError(err)
+	defer os.Remove(caCertFilename)
+	defer os.Remove(caKeyFilename)
+	clientCertFilename := "/tmp/client.pem"
+	clientKeyFilename := "/tmp/client_key.pem"
+	err = createTLSCertificate(clientCertFilename, clientKeyFilename, "testws", ca, caKey)
+	s.Require().NoError(err)
+	defer os.Remove(clientCertFilename)
+	defer os.Remove(clientKeyFilename)
+	otherCertFilename := "/tmp/other.pem"
+	otherKeyFilename := "/tmp/other_key.pem"
+	err = createTLSCertificate(otherCertFilename, otherKeyFilename, "otherId", ca, caKey)
+	s.Require().NoError(err)
+	defer os.Remove(otherCertFilename)
+	defer os.Remove(otherKeyFilename)
+
+	// Create TLS server. The profile enforces client certificate verification.
+	clientCAs := x509.NewCertPool()
+	data, err := os.ReadFile(caCertFilename)
+	s.Require().NoError(err)
+	s.Require().True(clientCAs.AppendCertsFromPEM(data))
+	tlsServer := NewServer(
+		WithServerTLSConfig(serverCertFilename, serverKeyFilename, &tls.Config{
+			ClientCAs:  clientCAs,
+			MinVersion: tls.VersionTLS10,
+		}),
+		WithServerSecurityProfile(SecurityProfile3))
+	s.server, ok = tlsServer.(*server)
+	s.Require().True(ok)
+	connected := make(chan Channel, 1)
+	s.server.SetNewClientHandler(func(ws Channel) {
+		connected <- ws
+	})
+	go s.server.Start(serverPort, serverPath)
+	time.Sleep(100 * time.Millisecond)
+	// TLS configuration was restricted by the profile
+	s.Equal(uint16(tls.VersionTLS12), s.server.httpServer.TLSConfig.MinVersion)
+	s.Equal(tls.RequireAndVerifyClientCert, s.server.httpServer.TLSConfig.ClientAuth)
+	s.Equal(SecurityProfileCipherSuites, s.server.httpServer.TLSConfig.CipherSuites)
+
+	rootCAs := x509.NewCertPool()
+	data, err = os.ReadFile(serverCertFilename)
+	s.Require().NoError(err)
+	s.Require().True(rootCAs.AppendCertsFromPEM(data))
+	host := fmt.Sprintf("localhost:%v", serverPort)
+	u := url.URL{Scheme: "wss", Host: host, Path: testPath}
+	newClient := func(certFilename string, keyFilename string) *client {
+		cert, err := tls.LoadX509KeyPair(certFilename, keyFilename)
+		s.Require().NoError(err)
+		c, ok := NewClient(
+			WithClientTLSConfig(&tls.Config{RootCAs: rootCAs, Certificates: []tls.Certificate{cert}}),
+			WithClientSecurityProfile(SecurityProfile3)).(*client)
+		s.Require().True(ok)
+		c.SetRequestedSubProtocol(defaultSubProtocol)
+		return c
+	}
+	// Certificate not matching the identity is rejected
+	s.client = newClient(otherCertFilename, otherKeyFilename)
+	err = s.client.Start(u.String())
+	var httpErr HttpConnectionError
+	s.Require().True(errors.As(err, &httpErr))
+	s.Equal(http.StatusUnauthorized, httpErr.HttpCode)
+	// Certificate matching the identity is accepted
+	s.client = newClient(clientCertFilename, clientKeyFilename)
+	err = s.client.Start(u.String())
+	s.Require().NoError(err)
 	select {
-	case _, ok := <-disconnected:
-		s.False(ok)
+	case channel := <-connected:
+		s.Equal(SecurityProfile3, channel.SecurityProfile())
+		s.Require().NotNil(channel.TLSConnectionState())
+		s.GreaterOrEqual(channel.TLSConnectionState().Version, uint16(tls.VersionTLS12))
 	case <-time.After(1 * time.Second):
-		s.Fail("timeout waiting for client disconnect")
+		s.Fail("timeout waiting for client to connect")
 	}
 }
 
-func (s *WebSocketSuite) TestSetClientTimeoutConfig() {
-	disconnected := make(chan struct{})
+func (s *WebSocketSuite) TestSecurityProfile3RequiresClientCertificate() {
+	s.client = newWebsocketClient(s.T(), nil)
+	WithClientSecurityProfile(SecurityProfile3)(s.client)
+	host := fmt.Sprintf("localhost:%v", serverPort)
+	u := url.URL{Scheme: "wss", Host: host, Path: testPath}
+	err := s.client.Start(u.String())
+	s.ErrorIs(err, ErrSecurityProfileViolation)
+}
+
+func (s *WebSocketSuite) TestServerCertificateProvider() {
+	var ok bool
+	certFilename1 := "/tmp/cert1.pem"
+	keyFilename1 := "/tmp/key1.pem"
+	err := createTLSCertificate(certFilename1, keyFilename1, "localhost", nil, nil)
+	s.Require().NoError(err)
+	defer os.Remove(certFilename1)
+	defer os.Remove(keyFilename1)
+	certFilename2 := "/tmp/cert2.pem"
+	keyFilename2 := "/tmp/key2.pem"
+	err = createTLSCertificate(certFilename2, keyFilename2, "localhost", nil, nil)
+	s.Require().NoError(err)
+	defer os.Remove(certFilename2)
+	defer os.Remove(keyFilename2)
+	cert1, err := tls.LoadX509KeyPair(certFilename1, keyFilename1)
+	s.Require().NoError(err)
+	cert2, err := tls.LoadX509KeyPair(certFilename2, keyFilename2)
+	s.Require().NoError(err)
+
+	// Create TLS server with swappable certificate
+	provider := NewStaticCertificateProvider(&cert1)
+	tlsServer := NewServer(WithServerCertificateProvider(provider))
+	s.server, ok = tlsServer.(*server)
+	s.Require().True(ok)
+	go s.server.Start(serverPort, serverPath)
+	time.Sleep(100 * time.Millisecond)
+
+	rootCAs := x509.NewCertPool()
+	for _, filename := range []string{certFilename1, certFilename2} {
+		data, err := os.ReadFile(filename)
+		s.Require().NoError(err)
+		s.Require().True(rootCAs.AppendCertsFromPEM(data))
+	}
+	host := fmt.Sprintf("localhost:%v", serverPort)
+	u := url.URL{Scheme: "wss", Host: host, Path: testPath}
+	connect := func() *tls.ConnectionState {
+		c, ok := NewClient(WithClientTLSConfig(&tls.Config{RootCAs: rootCAs})).(*client)
+		s.Require().True(ok)
+		c.SetRequestedSubProtocol(defaultSubProtocol)
+		err := c.Start(u.String())
+		s.Require().NoError(err)
+		s.client = c
+		state := c.webSocket.TLSConnectionState()
+		s.Require().NotNil(state)
+		return state
+	}
+	// Server presents the first certificate
+	state := connect()
+	s.Equal(cert1.Certificate[0], state.PeerCertificates[0].Raw)
+	s.client.Stop()
+	// Swap certificate at runtime, without restarting the server
+	provider.SetCertificate(&cert2)
+	state = connect()
+	s.Equal(cert2.Certificate[0], state.PeerCertificates[0].Raw)
+}
+
+func (s *WebSocketSuite) TestClientCertificateProviderReconnect() {
+	var ok bool
+	serverCertFilename := "/tmp/cert.pem"
+	serverKeyFilename := "/tmp/key.pem"
+	err := createTLSCertificate(serverCertFilename, serverKeyFilename, "localhost", nil, nil)
+	s.Require().NoError(err)
+	defer os.Remove(serverCertFilename)
+	defer os.Remove(serverKeyFilename)
+	caCertFilename := "/tmp/ca.pem"
+	caKeyFilename := "/tmp/ca_key.pem"
+	ca, caKey, err := createCACertificate(caCertFilename, caKeyFilename)
+	s.Require().NoError(err)
+	defer os.Remove(caCertFilename)
+	defer os.Remove(caKeyFilename)
+	clientCertFilename := "/tmp/client.pem"
+	clientKeyFilename := "/tmp/client_key.pem"
+	err = createTLSCertificate(clientCertFilename, clientKeyFilename, "oldCertificate", ca, caKey)
+	s.Require().NoError(err)
+	defer os.Remove(clientCertFilename)
+	defer os.Remove(clientKeyFilename)
+
+	// Create TLS server, requiring client certificates
+	clientCAs := x509.NewCertPool()
+	data, err := os.ReadFile(caCertFilename)
+	s.Require().NoError(err)
+	s.Require().True(clientCAs.AppendCertsFromPEM(data))
+	tlsServer := NewServer(WithServerTLSConfig(serverCertFilename, serverKeyFilename, &tls.Config{
+		ClientCAs:  clientCAs,
+		ClientAuth: tls.RequireAndVerifyClientCert,
+	}))
+	s.server, ok = tlsServer.(*server)
+	s.Require().True(ok)
+	connectedC := make(chan string, 2)
 	s.server.SetNewClientHandler(func(ws Channel) {
+		connectedC <- ws.TLSConnectionState().PeerCertificates[0].Subject.CommonName
 	})
+	disconnectedC := make(chan struct{}, 1)
 	s.server.SetDisconnectedClientHandler(func(ws Channel) {
-		// TODO: check for error with upcoming API
-		close(disconnected)
+		disconnectedC <- struct{}{}
 	})
-	// Start server
 	go s.server.Start(serverPort, serverPath)
 	time.Sleep(100 * time.Millisecond)
-	// Run test
+
+	// Create TLS client, reading the client certificate from disk
+	provider, err := NewFileCertificateProvider(clientCertFilename, clientKeyFilename, 0)
+	s.Require().NoError(err)
+	rootCAs := x509.NewCertPool()
+	data, err = os.ReadFile(serverCertFilename)
+	s.Require().NoError(err)
+	s.Require().True(rootCAs.AppendCertsFromPEM(data))
+	s.client, ok = NewClient(
+		WithClientTLSConfig(&tls.Config{RootCAs: rootCAs}),
+		WithClientCertificateProvider(provider)).(*client)
+	s.Require().True(ok)
+	s.client.SetRequestedSubProtocol(defaultSubProtocol)
+	reconnectedC := make(chan struct{}, 1)
+	s.client.SetReconnectedHandler(func() {
+		reconnectedC <- struct{}{}
+	})
 	host := fmt.Sprintf("localhost:%v", serverPort)
-	u := url.URL{Scheme: "ws", Host: host, Path: testPath}
-	// Set client timeout
-	config := NewClientTimeoutConfig()
-	handshakeTimeout := 1 * time.Nanosecond // Very low timeout, handshake will fail
-	writeWait := 1 * time.Second
-	// Ping period > pong wait, this is a nonsensical config that will trigger a pong timeout
-	pingPeriod := 3 * time.Second
-	pongWait := 500 * time.Millisecond
-	config.PongWait = pongWait
-	config.HandshakeTimeout = handshakeTimeout
-	config.WriteWait = writeWait
-	config.PingPeriod = pingPeriod
-	s.client.SetTimeoutConfig(config)
-	// Start client and expect handshake error
-	err := s.client.Start(u.String())
-	var opError *net.OpError
-	ok := s.ErrorAs(err, &opError)
-	s.True(ok)
-	s.Equal("dial", opError.Op)
-	s.True(opError.Timeout())
-	s.Error(opError.Err, "i/o timeout")
-	// Reset handshake to reasonable value
-	config.HandshakeTimeout = defaultHandshakeTimeout
-	s.client.SetTimeoutConfig(config)
-	// Start client
+	u := url.URL{Scheme: "wss", Host: host, Path: testPath}
 	err = s.client.Start(u.String())
-	s.NoError(err)
-	s.Equal(s.client.timeoutConfig.PongWait, pongWait)
-	s.Equal(s.client.timeoutConfig.WriteWait, writeWait)
-	s.Equal(s.client.timeoutConfig.PingPeriod, pingPeriod)
+	s.Require().NoError(err)
+	s.Equal("oldCertificate", <-connectedC)
+	// Replace certificate on disk and reconnect
+	err = createTLSCertificate(clientCertFilename, clientKeyFilename, "newCertificate", ca, caKey)
+	s.Require().NoError(err)
+	later := time.Now().Add(time.Second)
+	s.Require().NoError(os.Chtimes(clientCertFilename, later, later))
+	s.Require().NoError(os.Chtimes(clientKeyFilename, later, later))
+	err = s.client.Reconnect()
+	s.Require().NoError(err)
 	select {
-	case _, closed := <-disconnected:
-		s.False(closed)
+	case <-disconnectedC:
 	case <-time.After(1 * time.Second):
-		s.Fail("timeout waiting for client disconnect")
+		s.Fail("timeout waiting for old connection to be closed")
+	}
+	s.Equal("newCertificate", <-connectedC)
+	s.True(s.client.IsConnected())
+	select {
+	case <-reconnectedC:
+	case <-time.After(1 * time.Second):
+		s.Fail("timeout waiting for reconnected handler")
 	}
 }
 
-func (s *WebSocketSuite) TestServerErrors() {
-	triggerC := make(chan struct{}, 1)
-	finishC := make(chan struct{}, 1)
-	defer close(finishC)
-	s.server.SetNewClientHandler(func(ws Channel) {
-		triggerC <- struct{}{}
-	})
-	// Intercept errors asynchronously
-	s.Nil(s.server.errC)
+func (s *WebSocketSuite) TestClientReconnectNotStarted() {
+	s.client = newWebsocketClient(s.T(), nil)
+	err := s.client.Reconnect()
+	s.Error(err)
+}
+
+// startConnectProxy starts a minimal HTTP CONNECT proxy, requiring the passed basic auth credentials.
+// Every received CONNECT request is passed to the returned channel.
+func startConnectProxy(t *testing.T, username string, password string) (net.Listener, chan *http.Request) {
+	ln, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	requestC := make(chan *http.Request, 10)
+	expectedAuth := "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
 	go func() {
 		for {
-			select {
-			case err, ok := <-s.server.Errors():
-				triggerC <- struct{}{}
-				if ok {
-					s.Error(err)
-				}
-			case <-finishC:
+			conn, err := ln.Accept()
+			if err != nil {
 				return
 			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				req, err := http.ReadRequest(bufio.NewReader(conn))
+				if err != nil {
+					return
+				}
+				requestC <- req
+				if req.Header.Get("Proxy-Authorization") != expectedAuth {
+					_, _ = conn.Write([]byte("HTTP/1.1 407 Proxy Authentication Required\r\nContent-Length: 0\r\n\r\n"))
+					return
+				}
+				target, err := net.Dial("tcp", req.Host)
+				if err != nil {
+					_, _ = conn.Write([]byte("HTTP/1.1 502 Bad Gateway\r\nContent-Length: 0\r\n\r\n"))
+					return
+				}
+				defer target.Close()
+				_, _ = conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+				go func() {
+					_, _ = io.Copy(target, conn)
+				}()
+				_, _ = io.Copy(conn, target)
+			}(conn)
 		}
 	}()
-	s.server.SetMessageHandler(func(ws Channel, data []byte) error {
-		return fmt.Errorf("this is a dummy error")
+	return ln, requestC
+}
+
+func (s *WebSocketSuite) TestClientProxy() {
+	s.server = newWebsocketServer(s.T(), nil)
+	connectedC := make(chan struct{}, 2)
+	s.server.SetNewClientHandler(func(ws Channel) {
+		connectedC <- struct{}{}
 	})
-	// Will trigger an out-of-bound error
-	time.Sleep(50 * time.Millisecond)
-	s.server.Stop()
-	r := <-triggerC
-	s.NotNil(r)
-	// Start server for real
-	s.server.httpServer = &http.Server{}
 	go s.server.Start(serverPort, serverPath)
 	time.Sleep(100 * time.Millisecond)
-	// Connect client
+	proxyLn, requestC := startConnectProxy(s.T(), "proxyUser", "proxyPassword")
+	defer proxyLn.Close()
 	host := fmt.Sprintf("localhost:%v", serverPort)
 	u := url.URL{Scheme: "ws", Host: host, Path: testPath}
+	// Invalid proxy credentials
+	s.client = newWebsocketClient(s.T(), nil)
+	WithClientProxy(ProxyConfig{URL: &url.URL{Scheme: "http", Host: proxyLn.Addr().String(), User: url.UserPassword("proxyUser", "invalid")}})(s.client)
 	err := s.client.Start(u.String())
-	s.NoError(err)
-	// Wait for new client callback
-	r = <-triggerC
-	s.NotNil(r)
-	// Send a dummy message and expect error on server side
-	err = s.client.Write([]byte("dummy message"))
-	s.NoError(err)
-	r = <-triggerC
-	s.NotNil(r)
-	// Send message to non-existing client
-	err = s.server.Write("fakeId", []byte("dummy response"))
-	s.Error(err)
-	// Send unexpected close message and wait for error to be thrown
-	err = s.client.webSocket.connection.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseUnsupportedData, ""))
-	s.NoError(err)
-	r = <-triggerC
-	s.NotNil(r)
-	// Stop and wait for errors channel cleanup
-	s.server.Stop()
-	r = <-triggerC
-	s.NotNil(r)
+	var httpErr HttpConnectionError
+	s.Require().True(errors.As(err, &httpErr))
+	s.Equal(http.StatusProxyAuthRequired, httpErr.HttpCode)
+	<-requestC
+	// Valid proxy credentials
+	s.client = newWebsocketClient(s.T(), nil)
+	WithClientProxy(ProxyConfig{URL: &url.URL{Scheme: "http", Host: proxyLn.Addr().String(), User: url.UserPassword("proxyUser", "proxyPassword")}})(s.client)
+	err = s.client.Start(u.String())
+	s.Require().NoError(err)
+	<-connectedC
+	req := <-requestC
+	s.Equal(http.MethodConnect, req.Method)
+	s.Equal(host, req.Host)
+	// Reconnections go through the proxy as well
+	err = s.client.Reconnect()
+	s.Require().NoError(err)
+	<-connectedC
+	req = <-requestC
+	s.Equal(http.MethodConnect, req.Method)
 }
 
-func (s *WebSocketSuite) TestClientErrors() {
-	triggerC := make(chan struct{}, 1)
-	finishC := make(chan struct{}, 1)
-	defer close(finishC)
+func (s *WebSocketSuite) TestClientProxyFromEnvironment() {
+	s.server = newWebsocketServer(s.T(), nil)
+	connectedC := make(chan struct{}, 1)
 	s.server.SetNewClientHandler(func(ws Channel) {
-		triggerC <- struct{}{}
-	})
-	s.client.SetMessageHandler(func(data []byte) error {
-		return fmt.Errorf("this is a dummy error")
+		connectedC <- struct{}{}
 	})
-	// Intercept errors asynchronously
-	s.Nil(s.client.errC)
-	go func() {
-		for {
-			select {
-			case err, ok := <-s.client.Errors():
-				triggerC <- struct{}{}
-				if ok {
-					s.Error(err)
-				}
-			case <-finishC:
-				return
-			}
-		}
-	}()
 	go s.server.Start(serverPort, serverPath)
 	time.Sleep(100 * time.Millisecond)
-	// Attempt to write a message without being connected
-	err := s.client.Write([]byte("dummy message"))
-	s.Error(err)
-	// Connect client
-	host := fmt.Sprintf("localhost:%v", serverPort)
-	u := url.URL{Scheme: "ws", Host: host, Path: testPath}
-	err = s.client.Start(u.String())
-	s.NoError(err)
-	// Wait for new client callback
-	r := <-triggerC
-	s.NotNil(r)
-	// Send a dummy message and expect error on client side
-	err = s.server.Write(path.Base(testPath), []byte("dummy message"))
-	s.NoError(err)
-	r = <-triggerC
-	s.NotNil(r)
-	// Send unexpected close message and wait for error to be thrown
-	conn := s.server.connections[path.Base(testPath)]
-	s.NotNil(conn)
-	err = conn.WriteManual(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseUnsupportedData, ""))
-	// err = conn.connection.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseUnsupportedData, ""))
-	s.NoError(err)
-	r = <-triggerC
-	s.NotNil(r)
-	// Stop server and client and wait for errors channel cleanup
-	s.server.Stop()
-	s.client.Stop()
-	r = <-triggerC
-	s.NotNil(r)
+	proxyLn, requestC := startConnectProxy(s.T(), "proxyUser", "proxyPassword")
+	defer proxyLn.Close()
+	// The environment is only evaluated once by the standard library, hence the proxy function is swapped directly
+	s.client = newWebsocketClient(s.T(), nil)
+	WithClientProxyFromEnvironment()(s.client)
+	s.NotNil(s.client.proxy)
+	s.client.proxy = func(r *http.Request) (*url.URL, error) {
+		s.Equal("http", r.URL.Scheme)
+		return &url.URL{Scheme: "http", Host: proxyLn.Addr().String(), User: url.UserPassword("proxyUser", "proxyPassword")}, nil
+	}
+	u := url.URL{Scheme: "ws", Host: fmt.Sprintf("localhost:%v", serverPort), Path: testPath}
+	err := s.client.Start(u.String())
+	s.Require().NoError(err)
+	<-connectedC
+	req := <-requestC
+	s.Equal(http.MethodConnect, req.Method)
 }
 
-// Utility functions
+// pipeListener is an in-memory net.Listener, accepting connections created via net.Pipe.
+type pipeListener struct {
+	connC  chan net.Conn
+	closeC chan struct{}
+	once   sync.Once
+}
 
-func createCACertificate(certificateFilename string, keyFilename string) (*x509.Certificate, *ecdsa.PrivateKey, error) {
-	// Generate ed25519 key-pair
-	privateKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
-	if err != nil {
-		return nil, nil, err
+func newPipeListener() *pipeListener {
+	return &pipeListener{connC: make(chan net.Conn), closeC: make(chan struct{})}
+}
+
+func (l *pipeListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.connC:
+		return conn, nil
+	case <-l.closeC:
+		return nil, net.ErrClosed
 	}
-	// Create CA
-	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
-	serialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
-	if err != nil {
-		return nil, nil, err
+}
+
+func (l *pipeListener) Close() error {
+	l.once.Do(func() { close(l.closeC) })
+	return nil
+}
+
+func (l *pipeListener) Addr() net.Addr {
+	return pipeAddr{}
+}
+
+func (l *pipeListener) DialContext(ctx context.Context, _, _ string) (net.Conn, error) {
+	clientConn, serverConn := net.Pipe()
+	select {
+	case l.connC <- serverConn:
+		return clientConn, nil
+	case <-l.closeC:
+		return nil, net.ErrClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
 	}
-	notBefore := time.Now()
-	notAfter := notBefore.Add(time.Hour * 24)
-	template := x509.Certificate{
-		SerialNumber: serialNumber,
-		Subject: pkix.Name{
-			Organization: []string{"ocpp-go"},
-			CommonName:   "ocpp-go-CA",
-		},
-		NotBefore:             notBefore,
-		NotAfter:              notAfter,
-		IsCA:                  true,
-		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
-		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
-		BasicConstraintsValid: true,
-		DNSNames:              []string{"localhost"},
-	}
-	derBytes, err := x509.CreateCertificate(rand.Reader, &template, &template, &privateKey.PublicKey, privateKey)
-	if err != nil {
-		return nil, nil, err
-	}
-	// Save certificate to disk
-	certOut, err := os.Create(certificateFilename)
-	if err != nil {
-		return nil, nil, err
-	}
-	defer certOut.Close()
-	err = pem.Encode(certOut, &pem.Block{Type: "CERTIFICATE", Bytes: derBytes})
-	if err != nil {
-		return nil, nil, err
-	}
-	// Save key to disk
-	keyOut, err := os.Create(keyFilename)
-	if err != nil {
-		return nil, nil, err
-	}
-	defer keyOut.Close()
-	privateBytes, err := x509.MarshalPKCS8PrivateKey(privateKey)
-	if err != nil {
-		return nil, nil, err
-	}
-	err = pem.Encode(keyOut, &pem.Block{Type: "PRIVATE KEY", Bytes: privateBytes})
-	if err != nil {
-		return nil, nil, err
-	}
-	return &template, privateKey, nil
 }
 
-func createTLSCertificate(certificateFilename string, keyFilename string, cn string, ca *x509.Certificate, caKey *ecdsa.PrivateKey) error {
-	// Generate ed25519 key-pair
-	privateKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
-	if err != nil {
-		return err
-	}
-	// Create self-signed certificate
-	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
-	serialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
-	if err != nil {
-		return err
-	}
-	notBefore := time.Now()
-	notAfter := notBefore.Add(time.Hour * 24)
-	keyUsage := x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature
-	extKeyUsage := []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth}
-	template := x509.Certificate{
-		SerialNumber: serialNumber,
-		Subject: pkix.Name{
-			Organization: []string{"ocpp-go"},
-			CommonName:   cn,
+type pipeAddr struct{}
+
+func (pipeAddr) Network() string { return "pipe" }
+func (pipeAddr) String() string  { return "pipe" }
+
+func (s *WebSocketSuite) TestClientNetDialContext() {
+	s.server = newWebsocketServer(s.T(), func(data []byte) ([]byte, error) {
+		return data, nil
+	})
+	connectedC := make(chan struct{}, 2)
+	s.server.SetNewClientHandler(func(ws Channel) {
+		connectedC <- struct{}{}
+	})
+	ln := newPipeListener()
+	go s.server.Serve(ln, serverPath)
+	// Client dials over an in-memory pipe
+	messageC := make(chan []byte, 1)
+	s.client = newWebsocketClient(s.T(), func(data []byte) ([]byte, error) {
+		messageC <- data
+		return nil, nil
+	})
+	WithClientNetDialContext(ln.DialContext)(s.client)
+	u := url.URL{Scheme: "ws", Host: "pipe", Path: testPath}
+	err := s.client.Start(u.String())
+	s.Require().NoError(err)
+	<-connectedC
+	err = s.client.Write([]byte("hello"))
+	s.Require().NoError(err)
+	s.Equal([]byte("hello"), <-messageC)
+	// The custom dialer is used for reconnections as well
+	err = s.client.Reconnect()
+	s.Require().NoError(err)
+	<-connectedC
+	s.True(s.client.IsConnected())
+}
+
+func (s *WebSocketSuite) TestClientFailover() {
+	s.server = newWebsocketServer(s.T(), nil)
+	s.server.SetBasicAuthHandler(func(username string, password string) bool {
+		return username == "backupUser" && password == "backupPassword"
+	})
+	connectedC := make(chan struct{}, 1)
+	s.server.SetNewClientHandler(func(ws Channel) {
+		connectedC <- struct{}{}
+	})
+	go s.server.Start(serverPort, serverPath)
+	time.Sleep(100 * time.Millisecond)
+	// The primary endpoint is unreachable
+	unreachable, err := net.Listen("tcp", "localhost:0")
+	s.Require().NoError(err)
+	unreachableAddr := unreachable.Addr().String()
+	s.Require().NoError(unreachable.Close())
+
+	s.client = newWebsocketClient(s.T(), nil)
+	timeoutConfig := NewClientTimeoutConfig()
+	timeoutConfig.RetryBackOffWaitMinimum = 10 * time.Millisecond
+	timeoutConfig.RetryBackOffRandomRange = 0
+	s.client.SetTimeoutConfig(timeoutConfig)
+	errC := s.client.Errors()
+	changedC := make(chan int, 1)
+	config := FailoverConfig{
+		Endpoints: []ServerEndpoint{
+			{URL: fmt.Sprintf("ws://%v%v", unreachableAddr, testPath), Username: "primaryUser", Password: "primaryPassword"},
+			{URL: fmt.Sprintf("ws://localhost:%v%v", serverPort, testPath), Username: "backupUser", Password: "backupPassword"},
+		},
+		MaxAttemptsPerEndpoint: 2,
+		OnEndpointChanged: func(endpoint ServerEndpoint, index int) {
+			changedC <- index
 		},
-		NotBefore:             notBefore,
-		NotAfter:              notAfter,
-		KeyUsage:              keyUsage,
-		ExtKeyUsage:           extKeyUsage,
-		BasicConstraintsValid: true,
-		DNSNames:              []string{cn},
 	}
-	var derBytes []byte
-	if ca != nil && caKey != nil {
-		// Certificate signed by CA
-		derBytes, err = x509.CreateCertificate(rand.Reader, &template, ca, &privateKey.PublicKey, caKey)
-	} else {
-		// Self-signed certificate
-		derBytes, err = x509.CreateCertificate(rand.Reader, &template, &template, &privateKey.PublicKey, privateKey)
+	go func() {
+		// Drain reconnection errors
+		for range errC {
+		}
+	}()
+	_, _, ok := s.client.ActiveEndpoint()
+	s.False(ok)
+	err = s.client.StartWithFailover(config)
+	s.Require().NoError(err)
+	// Client switched to the backup endpoint, using its credentials
+	select {
+	case <-connectedC:
+	case <-time.After(1 * time.Second):
+		s.Fail("timeout waiting for client to connect")
 	}
-	if err != nil {
-		return err
+	s.Equal(1, <-changedC)
+	endpoint, index, ok := s.client.ActiveEndpoint()
+	s.True(ok)
+	s.Equal(1, index)
+	s.Equal(config.Endpoints[1].URL, endpoint.URL)
+	s.True(s.client.IsConnected())
+	// Reconnections use the active endpoint
+	err = s.client.Reconnect()
+	s.Require().NoError(err)
+	<-connectedC
+	_, index, _ = s.client.ActiveEndpoint()
+	s.Equal(1, index)
+	s.Len(changedC, 0)
+}
+
+func (s *WebSocketSuite) TestClientFailoverGaveUp() {
+	// No endpoint is reachable
+	var endpoints []ServerEndpoint
+	for i := 0; i < 2; i++ {
+		unreachable, err := net.Listen("tcp", "localhost:0")
+		s.Require().NoError(err)
+		endpoints = append(endpoints, ServerEndpoint{URL: fmt.Sprintf("ws://%v%v", unreachable.Addr().String(), testPath)})
+		s.Require().NoError(unreachable.Close())
 	}
-	// Save certificate to disk
-	certOut, err := os.Create(certificateFilename)
-	if err != nil {
-		return err
+	s.client = newWebsocketClient(s.T(), nil)
+	WithReconnectStrategy(CappedBackoff{
+		ExponentialBackoff: ExponentialBackoff{InitialDelay: 10 * time.Millisecond, MaxAttempts: 3},
+		MaxDelay:           30 * time.Millisecond,
+	})(s.client)
+	errC := s.client.Errors()
+	go func() {
+		// Drain reconnection errors
+		for range errC {
+		}
+	}()
+	var delays []time.Duration
+	s.client.SetConnectionStateHandler(func(event ConnectionStateEvent) {
+		if event.State == ConnectionStateBackingOff {
+			delays = append(delays, event.Delay)
+		}
+	})
+	doneC := make(chan struct{})
+	go func() {
+		// Every failed attempt switches endpoint
+		_ = s.client.StartWithFailover(FailoverConfig{Endpoints: endpoints, MaxAttemptsPerEndpoint: 1})
+		close(doneC)
+	}()
+	select {
+	case <-doneC:
+	case <-time.After(1 * time.Second):
+		s.Fail("timeout waiting for client to give up")
+		return
 	}
-	defer certOut.Close()
-	err = pem.Encode(certOut, &pem.Block{Type: "CERTIFICATE", Bytes: derBytes})
-	if err != nil {
-		return err
+	s.False(s.client.IsConnected())
+	// The backoff grows across endpoints, up until the client gives up
+	s.Equal([]time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 30 * time.Millisecond}, delays)
+	state := s.client.ConnectionState()
+	s.Equal(ConnectionStateGaveUp, state.State)
+	s.Equal(3, state.Attempt)
+}
+
+func (s *WebSocketSuite) TestClientFailoverInvalidConfig() {
+	s.client = newWebsocketClient(s.T(), nil)
+	err := s.client.StartWithFailover(FailoverConfig{})
+	s.Error(err)
+}
+
+func (s *WebSocketSuite) TestUnsupportedSubProtocol() {
+	s.server.SetNewClientHandler(func(ws Channel) {
+	})
+	s.server.SetDisconnectedClientHandler(func(ws Channel) {
+	})
+	s.server.AddSupportedSubprotocol(defaultSubProtocol)
+	s.Len(s.server.upgrader.Subprotocols, 1)
+	// Test duplicate sub-protocol
+	s.server.AddSupportedSubprotocol(defaultSubProtocol)
+	s.Len(s.server.upgrader.Subprotocols, 1)
+	// Start server
+	go s.server.Start(serverPort, serverPath)
+	time.Sleep(100 * time.Millisecond)
+
+	// Setup client
+	disconnectC := make(chan struct{})
+	s.client.SetDisconnectedHandler(func(err error) {
+		var wsErr *websocket.CloseError
+		ok := s.ErrorAs(err, &wsErr)
+		s.True(ok)
+		s.Equal(websocket.CloseProtocolError, wsErr.Code)
+		s.Equal("invalid or unsupported subprotocol", wsErr.Text)
+		s.client.SetDisconnectedHandler(nil)
+		close(disconnectC)
+	})
+	// Set invalid sub-protocol
+	s.client.AddOption(func(dialer *websocket.Dialer) {
+		dialer.Subprotocols = []string{"unsupportedSubProto"}
+	})
+	// Test
+	host := fmt.Sprintf("localhost:%v", serverPort)
+	u := url.URL{Scheme: "ws", Host: host, Path: testPath}
+	err := s.client.Start(u.String())
+	s.NoError(err)
+	// Expect connection to be closed directly after start
+	select {
+	case _, ok := <-disconnectC:
+		s.False(ok)
+	case <-time.After(1 * time.Second):
+		s.Fail("timeout waiting for client disconnect")
 	}
-	// Save key to disk
-	keyOut, err := os.Create(keyFilename)
-	if err != nil {
-		return err
+}
+
+func (s *WebSocketSuite) TestSetServerTimeoutConfig() {
+	disconnected := make(chan struct{})
+	s.server.SetNewClientHandler(func(ws Channel) {
+	})
+	s.server.SetDisconnectedClientHandler(func(ws Channel) {
+		// TODO: check for error with upcoming API
+		close(disconnected)
+	})
+	// Setting server timeout
+	config := NewServerTimeoutConfig()
+	pingWait := 400 * time.Millisecond
+	writeWait := 500 * time.Millisecond
+	config.PingWait = pingWait
+	config.WriteWait = writeWait
+	s.server.SetTimeoutConfig(config)
+	// Start server
+	go s.server.Start(serverPort, serverPath)
+	time.Sleep(100 * time.Millisecond)
+	s.Equal(s.server.timeoutConfig.PingWait, pingWait)
+	s.Equal(s.server.timeoutConfig.WriteWait, writeWait)
+	// Run test
+	host := fmt.Sprintf("localhost:%v", serverPort)
+	u := url.URL{Scheme: "ws", Host: host, Path: testPath}
+	err := s.client.Start(u.String())
+	s.NoError(err)
+	select {
+	case _, ok := <-disconnected:
+		s.False(ok)
+	case <-time.After(1 * time.Second):
+		s.Fail("timeout waiting for client disconnect")
 	}
-	defer keyOut.Close()
-	privateBytes, err := x509.MarshalPKCS8PrivateKey(privateKey)
-	if err != nil {
-		return err
+}
+
+func (s *WebSocketSuite) TestServerReadLimit() {
+	readLimit := int64(32)
+	disconnectedClientC := make(chan error, 1)
+	s.server.SetMessageHandler(func(ws Channel, data []byte) error {
+		s.Fail("unexpected message received")
+		return nil
+	})
+	serverErrC := s.server.Errors()
+	s.client.SetDisconnectedHandler(func(err error) {
+		disconnectedClientC <- err
+	})
+	// Setting server read limit
+	config := NewServerTimeoutConfig()
+	config.ReadLimit = readLimit
+	s.server.SetTimeoutConfig(config)
+	// Start server
+	go s.server.Start(serverPort, serverPath)
+	time.Sleep(100 * time.Millisecond)
+	// Connect client
+	host := fmt.Sprintf("localhost:%v", serverPort)
+	u := url.URL{Scheme: "ws", Host: host, Path: testPath}
+	err := s.client.Start(u.String())
+	s.Require().NoError(err)
+	// Send oversized message
+	err = s.client.Write([]byte(strings.Repeat("x", int(readLimit)+1)))
+	s.Require().NoError(err)
+	select {
+	case err = <-serverErrC:
+		s.ErrorContains(err, fmt.Sprintf("message exceeds read limit of %d bytes", readLimit))
+	case <-time.After(1 * time.Second):
+		s.Fail("timeout waiting for server error")
 	}
-	err = pem.Encode(keyOut, &pem.Block{Type: "PRIVATE KEY", Bytes: privateBytes})
-	if err != nil {
-		return err
+	select {
+	case err = <-disconnectedClientC:
+		var closeErr *websocket.CloseError
+		s.Require().True(errors.As(err, &closeErr))
+		s.Equal(websocket.CloseMessageTooBig, closeErr.Code)
+	case <-time.After(1 * time.Second):
+		s.Fail("timeout waiting for client disconnect")
 	}
-	return nil
+}
+
+func (s *WebSocketSuite) TestSetClientTimeoutConfig() {
+	disconnected := make(chan struct{})
+	s.server.SetNewClientHandler(func(ws Channel) {
+	})
+	s.server.SetDisconnectedClientHandler(func(ws Channel) {
+		// TODO: check for error with upcoming API
+		close(disconnected)
+	})
+	// Start server
+	go s.server.Start(serverPort, serverPath)
+	time.Sleep(100 * time.Millisecond)
+	// Run test
+	host := fmt.Sprintf("localhost:%v", serverPort)
+	u := url.URL{Scheme: "ws", Host: host, Path: testPath}
+	// Set client timeout
+	config := NewClientTimeoutConfig()
+	handshakeTimeout := 1 * time.Nanosecond // Very low timeout, handshake will fail
+	writeWait := 1 * time.Second
+	// Ping period > pong wait, this is a nonsensical config that will trigger a pong timeout
+	pingPeriod := 3 * time.Second
+	pongWait := 500 * time.Millisecond
+	config.PongWait = pongWait
+	config.HandshakeTimeout = handshakeTimeout
+	config.WriteWait = writeWait
+	config.PingPeriod = pingPeriod
+	s.client.SetTimeoutConfig(config)
+	// Start client and expect handshake error
+	err := s.client.Start(u.String())
+	var opError *net.OpError
+	ok := s.ErrorAs(err, &opError)
+	s.True(ok)
+	s.Equal("dial", opError.Op)
+	s.True(opError.Timeout())
+	s.Error(opError.Err, "i/o timeout")
+	// Reset handshake to reasonable value
+	config.HandshakeTimeout = defaultHandshakeTimeout
+	s.client.SetTimeoutConfig(config)
+	// Start client
+	err = s.client.Start(u.String())
+	s.NoError(err)
+	s.Equal(s.client.timeoutConfig.PongWait, pongWait)
+	s.Equal(s.client.timeoutConfig.WriteWait, writeWait)
+	s.Equal(s.client.timeoutConfig.PingPeriod, pingPeriod)
+	select {
+	case _, closed := <-disconnected:
+		s.False(closed)
+	case <-time.After(1 * time.Second):
+		s.Fail("timeout waiting for client disconnect")
+	}
+}
+
+func (s *WebSocketSuite) TestServerErrors() {
+	triggerC := make(chan struct{}, 1)
+	finishC := make(chan struct{}, 1)
+	defer close(finishC)
+	s.server.SetNewClientHandler(func(ws Channel) {
+		triggerC <- struct{}{}
+	})
+	// Intercept errors asynchronously
+	s.Nil(s.server.errC)
+	go func() {
+		for {
+			select {
+			case err, ok := <-s.server.Errors():
+				triggerC <- struct{}{}
+				if ok {
+					s.Error(err)
+				}
+			case <-finishC:
+				return
+			}
+		}
+	}()
+	s.server.SetMessageHandler(func(ws Channel, data []byte) error {
+		return fmt.Errorf("this is a dummy error")
+	})
+	// Will trigger an out-of-bound error
+	time.Sleep(50 * time.Millisecond)
+	s.server.Stop()
+	r := <-triggerC
+	s.NotNil(r)
+	// Start server for real
+	s.server.httpServer = &http.Server{}
+	go s.server.Start(serverPort, serverPath)
+	time.Sleep(100 * time.Millisecond)
+	// Connect client
+	host := fmt.Sprintf("localhost:%v", serverPort)
+	u := url.URL{Scheme: "ws", Host: host, Path: testPath}
+	err := s.client.Start(u.String())
+	s.NoError(err)
+	// Wait for new client callback
+	r = <-triggerC
+	s.NotNil(r)
+	// Send a dummy message and expect error on server side
+	err = s.client.Write([]byte("dummy message"))
+	s.NoError(err)
+	r = <-triggerC
+	s.NotNil(r)
+	// Send message to non-existing client
+	err = s.server.Write("fakeId", []byte("dummy response"))
+	s.Error(err)
+	// Send unexpected close message and wait for error to be thrown
+	err = s.client.webSocket.connection.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseUnsupportedData, ""))
+	s.NoError(err)
+	r = <-triggerC
+	s.NotNil(r)
+	// Stop and wait for errors channel cleanup
+	s.server.Stop()
+	r = <-triggerC
+	s.NotNil(r)
+}
+
+func (s *WebSocketSuite) TestClientErrors() {
+	triggerC := make(chan struct{}, 1)
+	finishC := make(chan struct{}, 1)
+	defer close(finishC)
+	s.server.SetNewClientHandler(func(ws Channel) {
+		triggerC <- struct{}{}
+	})
+	s.client.SetMessageHandler(func(data []byte) error {
+		return fmt.Errorf("this is a dummy error")
+	})
+	// Intercept errors asynchronously
+	s.Nil(s.client.errC)
+	go func() {
+		for {
+			select {
+			case err, ok := <-s.client.Errors():
+				triggerC <- struct{}{}
+				if ok {
+					s.Error(err)
+				}
+			case <-finishC:
+				return
+			}
+		}
+	}()
+	go s.server.Start(serverPort, serverPath)
+	time.Sleep(100 * time.Millisecond)
+	// Attempt to write a message without being connected
+	err := s.client.Write([]byte("dummy message"))
+	s.Error(err)
+	// Connect client
+	host := fmt.Sprintf("localhost:%v", serverPort)
+	u := url.URL{Scheme: "ws", Host: host, Path: testPath}
+	err = s.client.Start(u.String())
+	s.NoError(err)
+	// Wait for new client callback
+	r := <-triggerC
+	s.NotNil(r)
+	// Send a dummy message and expect error on client side
+	err = s.server.Write(path.Base(testPath), []byte("dummy message"))
+	s.NoError(err)
+	r = <-triggerC
+	s.NotNil(r)
+	// Send unexpected close message and wait for error to be thrown
+	conn := s.server.connections[path.Base(testPath)]
+	s.NotNil(conn)
+	err = conn.WriteManual(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseUnsupportedData, ""))
+	// err = conn.connection.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseUnsupportedData, ""))
+	s.NoError(err)
+	r = <-triggerC
+	s.NotNil(r)
+	// Stop server and client and wait for errors channel cleanup
+	s.server.Stop()
+	s.client.Stop()
+	r = <-triggerC
+	s.NotNil(r)
+}
+
+// Utility functions
+
+func createCACertificate(certificateFilename string, keyFilename string) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	// Generate ed25519 key-pair
+	privateKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Create CA
+	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
+	serialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
+	if err != nil {
+		return nil, nil, err
+	}
+	notBefore := time.Now()
+	notAfter := notBefore.Add(time.Hour * 24)
+	template := x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			Organization: []string{"ocpp-go"},
+			CommonName:   "ocpp-go-CA",
+		},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		DNSNames:              []string{"localhost"},
+	}
+	derBytes, err := x509.CreateCertificate(rand.Reader, &template, &template, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Save certificate to disk
+	certOut, err := os.Create(certificateFilename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer certOut.Close()
+	err = pem.Encode(certOut, &pem.Block{Type: "CERTIFICATE", Bytes: derBytes})
+	if err != nil {
+		return nil, nil, err
+	}
+	// Save key to disk
+	keyOut, err := os.Create(keyFilename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer keyOut.Close()
+	privateBytes, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = pem.Encode(keyOut, &pem.Block{Type: "PRIVATE KEY", Bytes: privateBytes})
+	if err != nil {
+		return nil, nil, err
+	}
+	return &template, privateKey, nil
+}
+
+func createTLSCertificate(certificateFilename string, keyFilename string, cn string, ca *x509.Certificate, caKey *ecdsa.PrivateKey) error {
+	// Generate ed25519 key-pair
+	privateKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	// Create self-signed certificate
+	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
+	serialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
+	if err != nil {
+		return err
+	}
+	notBefore := time.Now()
+	notAfter := notBefore.Add(time.Hour * 24)
+	keyUsage := x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature
+	extKeyUsage := []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth}
+	template := x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			Organization: []string{"ocpp-go"},
+			CommonName:   cn,
+		},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              keyUsage,
+		ExtKeyUsage:           extKeyUsage,
+		BasicConstraintsValid: true,
+		DNSNames:              []string{cn},
+	}
+	var derBytes []byte
+	if ca != nil && caKey != nil {
+		// Certificate signed by CA
+		derBytes, err = x509.CreateCertificate(rand.Reader, &template, ca, &privateKey.PublicKey, caKey)
+	} else {
+		// Self-signed certificate
+		derBytes, err = x509.CreateCertificate(rand.Reader, &template, &template, &privateKey.PublicKey, privateKey)
+	}
+	if err != nil {
+		return err
+	}
+	// Save certificate to disk
+	certOut, err := os.Create(certificateFilename)
+	if err != nil {
+		return err
+	}
+	defer certOut.Close()
+	err = pem.Encode(certOut, &pem.Block{Type: "CERTIFICATE", Bytes: derBytes})
+	if err != nil {
+		return err
+	}
+	// Save key to disk
+	keyOut, err := os.Create(keyFilename)
+	if err != nil {
+		return err
+	}
+	defer keyOut.Close()
+	privateBytes, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return err
+	}
+	err = pem.Encode(keyOut, &pem.Block{Type: "PRIVATE KEY", Bytes: privateBytes})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *WebSocketSuite) TestReconnectStrategies() {
+	// Exponential backoff without upper bound
+	exponential := ExponentialBackoff{InitialDelay: 1 * time.Second, Multiplier: 3, MaxAttempts: 4}
+	expected := []time.Duration{1 * time.Second, 3 * time.Second, 9 * time.Second, 27 * time.Second}
+	for i, e := range expected {
+		delay, ok := exponential.NextDelay(i+1, nil)
+		s.True(ok)
+		s.Equal(e, delay)
+	}
+	_, ok := exponential.NextDelay(5, nil)
+	s.False(ok)
+	// Capped backoff with default multiplier and jitter
+	capped := CappedBackoff{ExponentialBackoff: ExponentialBackoff{InitialDelay: 1 * time.Second, Jitter: 500 * time.Millisecond}, MaxDelay: 5 * time.Second}
+	for attempt := 1; attempt <= 100; attempt++ {
+		delay, ok := capped.NextDelay(attempt, nil)
+		s.True(ok)
+		base := 5 * time.Second
+		if attempt < 4 {
+			base = time.Duration(1<<(attempt-1)) * time.Second
+		}
+		s.GreaterOrEqual(int64(delay), int64(base))
+		s.LessOrEqual(int64(delay), int64(base+500*time.Millisecond))
+	}
+	// OCPP backoff doubles the delay at most RepeatTimes times and never gives up
+	timeoutConfig := NewClientTimeoutConfig()
+	timeoutConfig.RetryBackOffWaitMinimum = 2 * time.Second
+	timeoutConfig.RetryBackOffRandomRange = 0
+	timeoutConfig.RetryBackOffRepeatTimes = 2
+	ocppBackoff := NewOCPPBackoff(timeoutConfig)
+	expected = []time.Duration{2 * time.Second, 4 * time.Second, 8 * time.Second, 8 * time.Second, 8 * time.Second}
+	for i, e := range expected {
+		delay, ok := ocppBackoff.NextDelay(i+1, nil)
+		s.True(ok)
+		s.Equal(e, delay)
+	}
+	ocppBackoff.RandomRange = 3 * time.Second
+	delay, ok := ocppBackoff.NextDelay(1, nil)
+	s.True(ok)
+	s.GreaterOrEqual(int64(delay), int64(2*time.Second))
+	s.LessOrEqual(int64(delay), int64(5*time.Second))
+}
+
+func (s *WebSocketSuite) TestClientConnectionStateGaveUp() {
+	// No server is listening
+	s.client = newWebsocketClient(s.T(), nil)
+	WithReconnectStrategy(ExponentialBackoff{InitialDelay: 10 * time.Millisecond, MaxAttempts: 2})(s.client)
+	errC := s.client.Errors()
+	go func() {
+		// Drain reconnection errors
+		for range errC {
+		}
+	}()
+	var events []ConnectionStateEvent
+	s.client.SetConnectionStateHandler(func(event ConnectionStateEvent) {
+		events = append(events, event)
+	})
+	host := fmt.Sprintf("localhost:%v", serverPort)
+	u := url.URL{Scheme: "ws", Host: host, Path: testPath}
+	doneC := make(chan struct{})
+	go func() {
+		s.client.StartWithRetries(u.String())
+		close(doneC)
+	}()
+	select {
+	case <-doneC:
+	case <-time.After(1 * time.Second):
+		s.Fail("timeout waiting for client to give up")
+		return
+	}
+	s.False(s.client.IsConnected())
+	expectedStates := []ConnectionState{
+		ConnectionStateConnecting,
+		ConnectionStateDisconnected,
+		ConnectionStateBackingOff,
+		ConnectionStateConnecting,
+		ConnectionStateBackingOff,
+		ConnectionStateConnecting,
+		ConnectionStateGaveUp,
+	}
+	expectedAttempts := []int{0, 0, 1, 1, 2, 2, 2}
+	s.Require().Len(events, len(expectedStates))
+	for i, event := range events {
+		s.Equal(expectedStates[i], event.State, event.State.String())
+		s.Equal(expectedAttempts[i], event.Attempt)
+	}
+	s.Equal(10*time.Millisecond, events[2].Delay)
+	s.Equal(20*time.Millisecond, events[4].Delay)
+	s.Error(events[1].LastError)
+	s.Error(events[6].LastError)
+	s.Equal(events[6], s.client.ConnectionState())
+}
+
+func (s *WebSocketSuite) TestClientConnectionStateReconnect() {
+	s.server = newWebsocketServer(s.T(), nil)
+	connectedC := make(chan struct{}, 1)
+	s.server.SetNewClientHandler(func(ws Channel) {
+		connectedC <- struct{}{}
+	})
+	go s.server.Start(serverPort, serverPath)
+	time.Sleep(100 * time.Millisecond)
+
+	s.client = newWebsocketClient(s.T(), nil)
+	s.client.SetReconnectStrategy(ExponentialBackoff{InitialDelay: 10 * time.Millisecond})
+	eventC := make(chan ConnectionStateEvent, 10)
+	s.client.SetConnectionStateHandler(func(event ConnectionStateEvent) {
+		eventC <- event
+	})
+	host := fmt.Sprintf("localhost:%v", serverPort)
+	u := url.URL{Scheme: "ws", Host: host, Path: testPath}
+	err := s.client.Start(u.String())
+	s.Require().NoError(err)
+	<-connectedC
+	s.Equal(ConnectionStateConnecting, (<-eventC).State)
+	s.Equal(ConnectionStateConnected, (<-eventC).State)
+	s.Equal(ConnectionStateConnected, s.client.ConnectionState().State)
+	// Server drops the connection, client backs off and reconnects
+	err = s.server.StopConnection(path.Base(testPath), websocket.CloseError{Code: websocket.CloseGoingAway, Text: "restart"})
+	s.Require().NoError(err)
+	<-connectedC
+	expectedStates := []ConnectionState{
+		ConnectionStateDisconnected,
+		ConnectionStateBackingOff,
+		ConnectionStateConnecting,
+		ConnectionStateConnected,
+	}
+	for _, expectedState := range expectedStates {
+		select {
+		case event := <-eventC:
+			s.Equal(expectedState, event.State, event.State.String())
+			if expectedState == ConnectionStateDisconnected {
+				s.Error(event.LastError)
+			} else {
+				s.Equal(1, event.Attempt)
+			}
+		case <-time.After(1 * time.Second):
+			s.Fail("timeout waiting for connection state event")
+			return
+		}
+	}
+	// Stopping the client is notified as well
+	s.client.Stop()
+	select {
+	case event := <-eventC:
+		s.Equal(ConnectionStateDisconnected, event.State)
+	case <-time.After(1 * time.Second):
+		s.Fail("timeout waiting for connection state event")
+	}
+}
+
+func (s *WebSocketSuite) TestConnectionStats() {
+	s.server = newWebsocketServer(s.T(), func(data []byte) ([]byte, error) {
+		return data, nil
+	})
+	s.server.AddSupportedSubprotocol(defaultSubProtocol)
+	serverTimeoutConfig := NewServerTimeoutConfig()
+	serverTimeoutConfig.PingPeriod = 50 * time.Millisecond
+	serverTimeoutConfig.PongWait = 1 * time.Second
+	s.server.SetTimeoutConfig(serverTimeoutConfig)
+	// The old channel may still be open on the server, when the client reconnects
+	WithDuplicateConnectionPolicy(DuplicateConnectionReplace)(s.server)
+	channelC := make(chan Channel, 1)
+	s.server.SetNewClientHandler(func(ws Channel) {
+		channelC <- ws
+	})
+	go s.server.Start(serverPort, serverPath)
+	time.Sleep(100 * time.Millisecond)
+
+	responseC := make(chan []byte, 1)
+	s.client = newWebsocketClient(s.T(), func(data []byte) ([]byte, error) {
+		responseC <- data
+		return nil, nil
+	})
+	host := fmt.Sprintf("localhost:%v", serverPort)
+	u := url.URL{Scheme: "ws", Host: host, Path: testPath}
+	startTime := time.Now()
+	err := s.client.Start(u.String())
+	s.Require().NoError(err)
+	channel := <-channelC
+	stats := channel.Stats()
+	s.False(stats.ConnectedAt.Before(startTime))
+	s.Equal(defaultSubProtocol, stats.Subprotocol)
+	s.Equal(uint64(0), stats.Reconnects)
+	s.Equal(uint64(0), stats.MessagesIn)
+	s.True(stats.LastMessageIn.IsZero())
+	// Exchange a message
+	message := []byte("hello")
+	err = s.client.Write(message)
+	s.Require().NoError(err)
+	s.Equal(message, <-responseC)
+	stats = channel.Stats()
+	s.Equal(uint64(1), stats.MessagesIn)
+	s.Equal(uint64(1), stats.MessagesOut)
+	compressionStats := channel.CompressionStats()
+	s.Equal(uint64(len(message)), compressionStats.PayloadBytesIn)
+	s.Equal(uint64(len(message)), compressionStats.PayloadBytesOut)
+	s.False(stats.LastMessageIn.Before(startTime))
+	clientStats := s.client.webSocket.Stats()
+	s.Equal(uint64(1), clientStats.MessagesIn)
+	s.Equal(uint64(1), clientStats.MessagesOut)
+	s.Equal(defaultSubProtocol, clientStats.Subprotocol)
+	// Wait for the server to measure the round-trip time of a ping
+	time.Sleep(200 * time.Millisecond)
+	stats = channel.Stats()
+	s.Greater(int64(stats.LastPingRTT), int64(0))
+	s.False(stats.LastPongIn.IsZero())
+	// Reconnections are counted per ID
+	err = s.client.Reconnect()
+	s.Require().NoError(err)
+	channel = <-channelC
+	s.Equal(uint64(1), channel.Stats().Reconnects)
+	s.Equal(uint64(0), channel.Stats().MessagesIn)
+	s.Equal(uint64(1), s.client.webSocket.Stats().Reconnects)
+}
+
+func (s *WebSocketSuite) TestConnectionCountEviction() {
+	s.server = newWebsocketServer(s.T(), nil)
+	s.server.connectionCountRetention = 50 * time.Millisecond
+	s.server.connMutex.Lock()
+	defer s.server.connMutex.Unlock()
+	s.Equal(uint64(0), s.server.countConnection("cs1"))
+	s.Equal(uint64(1), s.server.countConnection("cs1"))
+	s.Equal(uint64(0), s.server.countConnection("cs2"))
+	// cs1 disconnected a while ago, cs2 is still connected
+	s.server.connectionCounts["cs1"].disconnectedAt = time.Now().Add(-time.Second)
+	s.server.lastConnectionCountSweep = time.Now().Add(-time.Second)
+	s.Equal(uint64(0), s.server.countConnection("cs3"))
+	s.NotContains(s.server.connectionCounts, "cs1")
+	s.Contains(s.server.connectionCounts, "cs2")
+	s.Equal(uint64(0), s.server.countConnection("cs1"))
+	s.Equal(uint64(1), s.server.countConnection("cs2"))
+}
+
+func (s *WebSocketSuite) TestServerChannels() {
+	s.server = newWebsocketServer(s.T(), nil)
+	connectedC := make(chan Channel, 2)
+	s.server.SetNewClientHandler(func(ws Channel) {
+		ws.SetAttribute("tenant", "tenant-"+ws.ID())
+		connectedC <- ws
+	})
+	disconnectedC := make(chan Channel, 1)
+	s.server.SetDisconnectedClientHandler(func(ws Channel) {
+		disconnectedC <- ws
+	})
+	go s.server.Start(serverPort, serverPath)
+	time.Sleep(100 * time.Millisecond)
+	s.Empty(s.server.Channels())
+	s.Equal(0, s.server.ChannelCount())
+	_, ok := s.server.GetChannel("testws")
+	s.False(ok)
+
+	host := fmt.Sprintf("localhost:%v", serverPort)
+	clientErrC := make(chan error, 1)
+	s.client = newWebsocketClient(s.T(), nil)
+	s.client.SetDisconnectedHandler(func(err error) {
+		clientErrC <- err
+	})
+	err := s.client.Start(fmt.Sprintf("ws://%v%v", host, testPath))
+	s.Require().NoError(err)
+	<-connectedC
+	otherClient := newWebsocketClient(s.T(), nil)
+	err = otherClient.Start(fmt.Sprintf("ws://%v/ws/another", host))
+	s.Require().NoError(err)
+	defer otherClient.Stop()
+	<-connectedC
+	// List and look up channels
+	s.Equal(2, s.server.ChannelCount())
+	channels := s.server.Channels()
+	s.Require().Len(channels, 2)
+	s.Equal("another", channels[0].ID())
+	s.Equal("testws", channels[1].ID())
+	channel, ok := s.server.GetChannel("testws")
+	s.Require().True(ok)
+	// Attributes
+	value, ok := channel.Attribute("tenant")
+	s.True(ok)
+	s.Equal("tenant-testws", value)
+	channel.SetAttribute("site", 42)
+	s.Equal(map[string]interface{}{"tenant": "tenant-testws", "site": 42}, channel.Attributes())
+	channel.SetAttribute("site", nil)
+	_, ok = channel.Attribute("site")
+	s.False(ok)
+	// Disconnect with a custom code and reason
+	err = s.server.StopConnection("testws", websocket.CloseError{Code: websocket.ClosePolicyViolation, Text: "banned"})
+	s.Require().NoError(err)
+	disconnected := <-disconnectedC
+	s.Equal("testws", disconnected.ID())
+	// Attributes are still available after the disconnection
+	value, _ = disconnected.Attribute("tenant")
+	s.Equal("tenant-testws", value)
+	err = <-clientErrC
+	var closeErr *websocket.CloseError
+	s.Require().True(errors.As(err, &closeErr))
+	s.Equal(websocket.ClosePolicyViolation, closeErr.Code)
+	s.Equal("banned", closeErr.Text)
+	s.Equal(1, s.server.ChannelCount())
+	_, ok = s.server.GetChannel("testws")
+	s.False(ok)
+}
+
+func (s *WebSocketSuite) TestParseNetworks() {
+	networks, err := ParseNetworks("10.0.0.0/8", "192.168.1.10", "::1")
+	s.Require().NoError(err)
+	s.Require().Len(networks, 3)
+	s.True(networks[0].Contains(net.ParseIP("10.1.2.3")))
+	s.True(networks[1].Contains(net.ParseIP("192.168.1.10")))
+	s.False(networks[1].Contains(net.ParseIP("192.168.1.11")))
+	s.True(networks[2].Contains(net.ParseIP("::1")))
+	_, err = ParseNetworks("10.0.0.0/33")
+	s.Error(err)
+	_, err = ParseNetworks("not-an-ip")
+	s.Error(err)
+}
+
+func (s *WebSocketSuite) TestAdmissionNetworkLists() {
+	denied, err := ParseNetworks("127.0.0.0/8")
+	s.Require().NoError(err)
+	rejectedC := make(chan AdmissionRejectReason, 1)
+	s.server = newWebsocketServer(s.T(), nil)
+	WithServerAdmissionControl(AdmissionConfig{
+		DeniedNetworks: denied,
+		OnRejected: func(reason AdmissionRejectReason, sourceIP net.IP, r *http.Request) {
+			s.Equal("127.0.0.1", sourceIP.String())
+			s.Nil(r)
+			rejectedC <- reason
+		},
+	})(s.server)
+	go s.server.Start(serverPort, serverPath)
+	time.Sleep(100 * time.Millisecond)
+	s.client = newWebsocketClient(s.T(), nil)
+	host := fmt.Sprintf("localhost:%v", serverPort)
+	u := url.URL{Scheme: "ws", Host: host, Path: testPath}
+	// The connection is closed by the listener, before receiving the upgrade request
+	err = s.client.Start(u.String())
+	s.Require().Error(err)
+	_, ok := err.(HttpConnectionError)
+	s.False(ok)
+	s.Equal(AdmissionRejectDenied, <-rejectedC)
+	s.server.Stop()
+	// Only allow a network, which doesn't contain the client
+	allowed, err := ParseNetworks("10.0.0.0/8")
+	s.Require().NoError(err)
+	s.server = newWebsocketServer(s.T(), nil)
+	WithServerAdmissionControl(AdmissionConfig{AllowedNetworks: allowed})(s.server)
+	go s.server.Start(serverPort, serverPath)
+	time.Sleep(100 * time.Millisecond)
+	err = s.client.Start(u.String())
+	s.Require().Error(err)
+	stats := s.server.AdmissionStats()
+	s.Equal(uint64(1), stats.RejectedNotAllowed)
+	s.Equal(uint64(1), stats.Rejected())
+	s.Equal(uint64(0), stats.Admitted)
+}
+
+func (s *WebSocketSuite) TestAdmissionBeforeTLSHandshake() {
+	denied, err := ParseNetworks("127.0.0.0/8")
+	s.Require().NoError(err)
+	s.server = newWebsocketServer(s.T(), nil)
+	certFilename := "/tmp/cert.pem"
+	keyFilename := "/tmp/key.pem"
+	err = createTLSCertificate(certFilename, keyFilename, "localhost", nil, nil)
+	s.Require().NoError(err)
+	defer os.Remove(certFilename)
+	defer os.Remove(keyFilename)
+	s.server.tlsCertificatePath = certFilename
+	s.server.tlsCertificateKey = keyFilename
+	WithServerAdmissionControl(AdmissionConfig{DeniedNetworks: denied})(s.server)
+	go s.server.Start(serverPort, serverPath)
+	time.Sleep(100 * time.Millisecond)
+	// The connection is closed without waiting for a TLS client hello
+	conn, err := net.Dial("tcp", fmt.Sprintf("localhost:%v", serverPort))
+	s.Require().NoError(err)
+	defer conn.Close()
+	_ = conn.SetReadDeadline(time.Now().Add(1 * time.Second))
+	_, err = conn.Read(make([]byte, 1))
+	s.ErrorIs(err, io.EOF)
+	s.Equal(uint64(1), s.server.AdmissionStats().RejectedDenied)
+}
+
+func (s *WebSocketSuite) TestAdmissionSourceIPResolver() {
+	denied, err := ParseNetworks("10.0.0.0/8")
+	s.Require().NoError(err)
+	rejectedC := make(chan *http.Request, 1)
+	s.server = newWebsocketServer(s.T(), nil)
+	WithServerAdmissionControl(AdmissionConfig{
+		DeniedNetworks: denied,
+		SourceIPResolver: func(r *http.Request) (net.IP, error) {
+			return net.ParseIP(r.Header.Get("X-Forwarded-For")), nil
+		},
+		OnRejected: func(reason AdmissionRejectReason, sourceIP net.IP, r *http.Request) {
+			rejectedC <- r
+		},
+	})(s.server)
+	go s.server.Start(serverPort, serverPath)
+	time.Sleep(100 * time.Millisecond)
+	// Checks are performed on the request, as the source IP is only known then
+	s.client = newWebsocketClient(s.T(), nil)
+	s.client.SetHeaderValue("X-Forwarded-For", "10.1.2.3")
+	host := fmt.Sprintf("localhost:%v", serverPort)
+	u := url.URL{Scheme: "ws", Host: host, Path: testPath}
+	err = s.client.Start(u.String())
+	s.Require().Error(err)
+	httpErr, ok := err.(HttpConnectionError)
+	s.Require().True(ok)
+	s.Equal(http.StatusForbidden, httpErr.HttpCode)
+	s.NotNil(<-rejectedC)
+	s.client.SetHeaderValue("X-Forwarded-For", "192.168.1.10")
+	err = s.client.Start(u.String())
+	s.Require().NoError(err)
+	s.Equal(uint64(1), s.server.AdmissionStats().Admitted)
+}
+
+func (s *WebSocketSuite) TestAdmissionFallbackHandler() {
+	denied, err := ParseNetworks("10.0.0.0/8")
+	s.Require().NoError(err)
+	s.server = newWebsocketServer(s.T(), nil)
+	WithServerAdmissionControl(AdmissionConfig{
+		DeniedNetworks: denied,
+		SourceIPResolver: func(r *http.Request) (net.IP, error) {
+			return net.ParseIP(r.Header.Get("X-Forwarded-For")), nil
+		},
+	})(s.server)
+	fallbackC := make(chan struct{}, 1)
+	s.server.SetFallbackHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fallbackC <- struct{}{}
+	}))
+	go s.server.Start(serverPort, serverPath)
+	time.Sleep(100 * time.Millisecond)
+	// Requests served by the fallback handler are subject to admission control as well
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("http://localhost:%v%v", serverPort, testPath), nil)
+	s.Require().NoError(err)
+	req.Header.Set("X-Forwarded-For", "10.1.2.3")
+	resp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	_ = resp.Body.Close()
+	s.Equal(http.StatusForbidden, resp.StatusCode)
+	s.Len(fallbackC, 0)
+	req.Header.Set("X-Forwarded-For", "192.168.1.10")
+	resp, err = http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	_ = resp.Body.Close()
+	s.Equal(http.StatusOK, resp.StatusCode)
+	s.Len(fallbackC, 1)
+	stats := s.server.AdmissionStats()
+	s.Equal(uint64(1), stats.Admitted)
+	s.Equal(uint64(1), stats.RejectedDenied)
+}
+
+func (s *WebSocketSuite) TestAdmissionConnectionLimit() {
+	s.server = newWebsocketServer(s.T(), nil)
+	WithServerAdmissionControl(AdmissionConfig{MaxConnectionsPerIP: 1, RetryAfter: 5 * time.Second})(s.server)
+	disconnectedC := make(chan struct{}, 1)
+	s.server.SetDisconnectedClientHandler(func(ws Channel) {
+		disconnectedC <- struct{}{}
+	})
+	go s.server.Start(serverPort, serverPath)
+	time.Sleep(100 * time.Millisecond)
+	host := fmt.Sprintf("localhost:%v", serverPort)
+	s.client = newWebsocketClient(s.T(), nil)
+	err := s.client.Start(fmt.Sprintf("ws://%v%v", host, testPath))
+	s.Require().NoError(err)
+	// A second connection from the same IP is rejected
+	resp, err := http.Get(fmt.Sprintf("http://%v/ws/another", host))
+	s.Require().NoError(err)
+	_ = resp.Body.Close()
+	s.Equal(http.StatusServiceUnavailable, resp.StatusCode)
+	s.Equal("5", resp.Header.Get("Retry-After"))
+	s.Equal(uint64(1), s.server.AdmissionStats().RejectedConnectionLimit)
+	// Slot is freed up after disconnecting
+	s.client.Stop()
+	<-disconnectedC
+	otherClient := newWebsocketClient(s.T(), nil)
+	err = otherClient.Start(fmt.Sprintf("ws://%v/ws/another", host))
+	s.Require().NoError(err)
+	otherClient.Stop()
+	s.Equal(uint64(2), s.server.AdmissionStats().Admitted)
+}
+
+func (s *WebSocketSuite) TestAdmissionRateLimit() {
+	s.server = newWebsocketServer(s.T(), nil)
+	WithServerAdmissionControl(AdmissionConfig{HandshakeRate: 0.5, HandshakeBurst: 1})(s.server)
+	go s.server.Start(serverPort, serverPath)
+	time.Sleep(100 * time.Millisecond)
+	host := fmt.Sprintf("localhost:%v", serverPort)
+	s.client = newWebsocketClient(s.T(), nil)
+	err := s.client.Start(fmt.Sprintf("ws://%v%v", host, testPath))
+	s.Require().NoError(err)
+	// Burst is exhausted, the next handshake is only accepted after 2 seconds
+	resp, err := http.Get(fmt.Sprintf("http://%v/ws/another", host))
+	s.Require().NoError(err)
+	_ = resp.Body.Close()
+	s.Equal(http.StatusServiceUnavailable, resp.StatusCode)
+	s.Equal("2", resp.Header.Get("Retry-After"))
+	stats := s.server.AdmissionStats()
+	s.Equal(uint64(1), stats.Admitted)
+	s.Equal(uint64(1), stats.RejectedRateLimit)
+}
+
+func (s *WebSocketSuite) TestAdmissionRateLimitBeforeTLSHandshake() {
+	s.server = newWebsocketServer(s.T(), nil)
+	certFilename := "/tmp/cert.pem"
+	keyFilename := "/tmp/key.pem"
+	err := createTLSCertificate(certFilename, keyFilename, "localhost", nil, nil)
+	s.Require().NoError(err)
+	defer os.Remove(certFilename)
+	defer os.Remove(keyFilename)
+	s.server.tlsCertificatePath = certFilename
+	s.server.tlsCertificateKey = keyFilename
+	WithServerAdmissionControl(AdmissionConfig{HandshakeRate: 0.5, HandshakeBurst: 1})(s.server)
+	go s.server.Start(serverPort, serverPath)
+	time.Sleep(100 * time.Millisecond)
+	// The first connection consumes the burst
+	first, err := net.Dial("tcp", fmt.Sprintf("localhost:%v", serverPort))
+	s.Require().NoError(err)
+	defer first.Close()
+	time.Sleep(50 * time.Millisecond)
+	// Connections over the rate limit are closed without waiting for a TLS client hello
+	conn, err := net.Dial("tcp", fmt.Sprintf("localhost:%v", serverPort))
+	s.Require().NoError(err)
+	defer conn.Close()
+	_ = conn.SetReadDeadline(time.Now().Add(1 * time.Second))
+	_, err = conn.Read(make([]byte, 1))
+	s.ErrorIs(err, io.EOF)
+	stats := s.server.AdmissionStats()
+	s.Equal(uint64(1), stats.Admitted)
+	s.Equal(uint64(1), stats.RejectedRateLimit)
+}
+
+func (s *WebSocketSuite) TestTokenBucket() {
+	now := time.Now()
+	bucket := newTokenBucket(10, 2)
+	ok, _ := bucket.take(now)
+	s.True(ok)
+	ok, _ = bucket.take(now)
+	s.True(ok)
+	ok, wait := bucket.take(now)
+	s.False(ok)
+	s.Equal(100*time.Millisecond, wait)
+	// Tokens are refilled over time, up to the burst size
+	ok, _ = bucket.take(now.Add(100 * time.Millisecond))
+	s.True(ok)
+	ok, _ = bucket.take(now.Add(10 * time.Second))
+	s.True(ok)
+	ok, _ = bucket.take(now.Add(10 * time.Second))
+	s.True(ok)
+	ok, _ = bucket.take(now.Add(10 * time.Second))
+	s.False(ok)
+}
+
+func (s *WebSocketSuite) TestSubprotocolSelectors() {
+	supported := []string{"ocpp2.0.1", "ocpp1.6"}
+	s.Equal("ocpp1.6", PreferClientSubprotocols([]string{"ocpp1.6", "ocpp2.0.1"}, supported))
+	s.Equal("ocpp2.0.1", PreferServerSubprotocols([]string{"ocpp1.6", "ocpp2.0.1"}, supported))
+	s.Equal("ocpp1.6", PreferServerSubprotocols([]string{"ocpp1.5", "ocpp1.6"}, supported))
+	s.Equal("", PreferClientSubprotocols([]string{"ocpp1.5"}, supported))
+	s.Equal("", PreferServerSubprotocols(nil, supported))
+}
+
+func (s *WebSocketSuite) TestSubprotocolRouter() {
+	s.server = newWebsocketServer(s.T(), nil)
+	router := NewSubprotocolRouter(s.server)
+	newRoute := router.Route("ocpp2.0.1")
+	oldRoute := router.Route(defaultSubProtocol)
+	s.Equal(newRoute, router.Route("ocpp2.0.1"))
+	s.Equal([]string{"ocpp2.0.1", defaultSubProtocol}, s.server.upgrader.Subprotocols)
+	type routedMessage struct {
+		route string
+		id    string
+		data  string
+	}
+	messageC := make(chan routedMessage, 2)
+	connectedC := make(chan routedMessage, 2)
+	for name, route := range map[string]Server{"new": newRoute, "old": oldRoute} {
+		name := name
+		route.SetNewClientHandler(func(ws Channel) {
+			connectedC <- routedMessage{route: name, id: ws.ID()}
+		})
+		route.SetMessageHandler(func(ws Channel, data []byte) error {
+			messageC <- routedMessage{route: name, id: ws.ID(), data: string(data)}
+			return nil
+		})
+	}
+	go router.Start(serverPort, serverPath)
+	time.Sleep(100 * time.Millisecond)
+
+	host := fmt.Sprintf("localhost:%v", serverPort)
+	// The client offers both versions: the preferred route is picked
+	s.client = newWebsocketClient(s.T(), nil)
+	s.client.SetRequestedSubProtocol("ocpp2.0.1")
+	err := s.client.Start(fmt.Sprintf("ws://%v/ws/station", host))
+	s.Require().NoError(err)
+	s.Equal(routedMessage{route: "new", id: "station"}, <-connectedC)
+	// The client offers only the old version
+	oldClient := newWebsocketClient(s.T(), nil)
+	err = oldClient.Start(fmt.Sprintf("ws://%v/ws/chargepoint", host))
+	s.Require().NoError(err)
+	defer oldClient.Stop()
+	s.Equal(routedMessage{route: "old", id: "chargepoint"}, <-connectedC)
+	// Messages are dispatched to the respective route
+	s.Require().NoError(s.client.Write([]byte("hello")))
+	s.Equal(routedMessage{route: "new", id: "station", data: "hello"}, <-messageC)
+	s.Require().NoError(oldClient.Write([]byte("hi")))
+	s.Equal(routedMessage{route: "old", id: "chargepoint", data: "hi"}, <-messageC)
+	// Each route only sees its own channels
+	s.Equal(2, s.server.ChannelCount())
+	s.Equal(1, newRoute.ChannelCount())
+	s.Require().Len(oldRoute.Channels(), 1)
+	s.Equal("chargepoint", oldRoute.Channels()[0].ID())
+	_, ok := newRoute.GetChannel("chargepoint")
+	s.False(ok)
+	s.Error(newRoute.Write("chargepoint", []byte("wrong route")))
+	s.Error(oldRoute.StopConnection("station", websocket.CloseError{Code: websocket.CloseNormalClosure}))
+	// Stopping a route doesn't affect the underlying server
+	oldRoute.Stop()
+	s.Equal(2, s.server.ChannelCount())
+}
+
+func (s *WebSocketSuite) TestDisconnectReason() {
+	s.server = newWebsocketServer(s.T(), nil)
+	disconnectedC := make(chan Channel, 1)
+	s.server.SetDisconnectedClientHandler(func(ws Channel) {
+		disconnectedC <- ws
+	})
+	go s.server.Start(serverPort, serverPath)
+	time.Sleep(100 * time.Millisecond)
+	s.client = newWebsocketClient(s.T(), nil)
+	clientReasonC := make(chan *DisconnectReason, 1)
+	s.client.SetConnectionStateHandler(func(event ConnectionStateEvent) {
+		if event.State == ConnectionStateDisconnected && event.DisconnectReason != nil {
+			clientReasonC <- event.DisconnectReason
+		}
+	})
+	err := s.client.Start(fmt.Sprintf("ws://localhost:%v%v", serverPort, testPath))
+	s.Require().NoError(err)
+	channel, ok := s.server.GetChannel("testws")
+	s.Require().True(ok)
+	s.Nil(channel.DisconnectReason())
+	// Close from server side
+	err = s.server.StopConnection("testws", websocket.CloseError{Code: websocket.ClosePolicyViolation, Text: "banned"})
+	s.Require().NoError(err)
+	serverReason := (<-disconnectedC).DisconnectReason()
+	s.Require().NotNil(serverReason)
+	s.Equal(websocket.ClosePolicyViolation, serverReason.Code)
+	s.Equal("banned", serverReason.Text)
+	s.Equal(DisconnectInitiatorLocal, serverReason.Initiator)
+	s.NoError(serverReason.Err)
+	clientReason := <-clientReasonC
+	s.Equal(websocket.ClosePolicyViolation, clientReason.Code)
+	s.Equal("banned", clientReason.Text)
+	s.Equal(DisconnectInitiatorRemote, clientReason.Initiator)
+	s.NoError(clientReason.Err)
+	s.False(clientReason.Timeout())
+	s.Equal("closed by remote side with code 1008 (banned)", clientReason.String())
+}
+
+func (s *WebSocketSuite) TestDisconnectReasonTimeout() {
+	s.server = newWebsocketServer(s.T(), nil)
+	config := NewServerTimeoutConfig()
+	config.PingWait = 200 * time.Millisecond
+	s.server.SetTimeoutConfig(config)
+	disconnectedC := make(chan Channel, 1)
+	s.server.SetDisconnectedClientHandler(func(ws Channel) {
+		disconnectedC <- ws
+	})
+	go s.server.Start(serverPort, serverPath)
+	time.Sleep(100 * time.Millisecond)
+	// Client never sends pings
+	s.client = newWebsocketClient(s.T(), nil)
+	clientConfig := NewClientTimeoutConfig()
+	clientConfig.PingPeriod = 0
+	s.client.SetTimeoutConfig(clientConfig)
+	err := s.client.Start(fmt.Sprintf("ws://localhost:%v%v", serverPort, testPath))
+	s.Require().NoError(err)
+	serverReason := (<-disconnectedC).DisconnectReason()
+	s.Require().NotNil(serverReason)
+	s.Equal(websocket.CloseAbnormalClosure, serverReason.Code)
+	s.Equal(DisconnectInitiatorLocal, serverReason.Initiator)
+	s.Error(serverReason.Err)
+	s.True(serverReason.Timeout())
+}
+
+func (s *WebSocketSuite) TestNewForcedDisconnectReason() {
+	// Abnormal closure, i.e. the connection was dropped without a close frame
+	dropErr := &websocket.CloseError{Code: websocket.CloseAbnormalClosure, Text: "unexpected EOF"}
+	reason := newForcedDisconnectReason(dropErr)
+	s.Equal(websocket.CloseAbnormalClosure, reason.Code)
+	s.Equal(DisconnectInitiatorRemote, reason.Initiator)
+	s.Equal(dropErr, reason.Err)
+	// Close frame from peer
+	reason = newForcedDisconnectReason(&websocket.CloseError{Code: websocket.CloseNormalClosure})
+	s.Equal(websocket.CloseNormalClosure, reason.Code)
+	s.Equal(DisconnectInitiatorRemote, reason.Initiator)
+	s.NoError(reason.Err)
+	// Read limit exceeded
+	reason = newForcedDisconnectReason(websocket.ErrReadLimit)
+	s.Equal(websocket.CloseMessageTooBig, reason.Code)
+	s.Equal(DisconnectInitiatorLocal, reason.Initiator)
+	// Generic network error
+	netErr := errors.New("connection reset by peer")
+	reason = newForcedDisconnectReason(netErr)
+	s.Equal(websocket.CloseAbnormalClosure, reason.Code)
+	s.Equal(DisconnectInitiatorRemote, reason.Initiator)
+	s.Equal(netErr, reason.Err)
+	s.False(reason.Timeout())
+}
+
+func (s *WebSocketSuite) TestNewHandshakeDisconnectReason() {
+	// Upgrade rejected by the server
+	httpErr := HttpConnectionError{Message: "bad handshake", HttpStatus: "401 Unauthorized", HttpCode: http.StatusUnauthorized}
+	reason := newHandshakeDisconnectReason(httpErr)
+	s.True(reason.Handshake)
+	s.Equal(websocket.CloseAbnormalClosure, reason.Code)
+	s.Equal("401 Unauthorized", reason.Text)
+	s.Equal(DisconnectInitiatorRemote, reason.Initiator)
+	s.Equal(httpErr, reason.Err)
+	// Untrusted server certificate
+	reason = newHandshakeDisconnectReason(fmt.Errorf("tls: %w", x509.UnknownAuthorityError{}))
+	s.True(reason.Handshake)
+	s.Equal(DisconnectInitiatorLocal, reason.Initiator)
+	// Security profile violation
+	reason = newHandshakeDisconnectReason(fmt.Errorf("%w: TLS required", ErrSecurityProfileViolation))
+	s.Equal(DisconnectInitiatorLocal, reason.Initiator)
+	// Generic network error
+	netErr := errors.New("connection refused")
+	reason = newHandshakeDisconnectReason(netErr)
+	s.Equal(DisconnectInitiatorRemote, reason.Initiator)
+	s.Equal(netErr, reason.Err)
+	s.Equal("handshake failed on remote side: connection refused", reason.String())
+}
+
+func (s *WebSocketSuite) TestClientHandshakeDisconnectReason() {
+	s.server = newWebsocketServer(s.T(), nil)
+	s.server.SetBasicAuthHandler(func(username string, password string) bool {
+		return false
+	})
+	go s.server.Start(serverPort, serverPath)
+	time.Sleep(100 * time.Millisecond)
+	s.client = newWebsocketClient(s.T(), nil)
+	WithReconnectStrategy(ExponentialBackoff{InitialDelay: 10 * time.Millisecond, MaxAttempts: 1})(s.client)
+	errC := s.client.Errors()
+	go func() {
+		// Drain reconnection errors
+		for range errC {
+		}
+	}()
+	var events []ConnectionStateEvent
+	s.client.SetConnectionStateHandler(func(event ConnectionStateEvent) {
+		events = append(events, event)
+	})
+	s.client.StartWithRetries(fmt.Sprintf("ws://localhost:%v%v", serverPort, testPath))
+	// Every failed attempt carries the reason
+	s.Require().NotEmpty(events)
+	for _, event := range events {
+		if event.State == ConnectionStateConnecting {
+			continue
+		}
+		reason := event.DisconnectReason
+		s.Require().NotNil(reason, event.State.String())
+		s.True(reason.Handshake)
+		s.Equal(DisconnectInitiatorRemote, reason.Initiator)
+		s.Equal("401 Unauthorized", reason.Text)
+		var httpErr HttpConnectionError
+		s.Require().ErrorAs(reason.Err, &httpErr)
+		s.Equal(http.StatusUnauthorized, httpErr.HttpCode)
+	}
+	s.Equal(ConnectionStateGaveUp, s.client.ConnectionState().State)
+}
+
+func (s *WebSocketSuite) TestChannelSetTimeoutConfig() {
+	s.server = newWebsocketServer(s.T(), nil)
+	connectedC := make(chan Channel, 1)
+	s.server.SetNewClientHandler(func(ws Channel) {
+		connectedC <- ws
+	})
+	go s.server.Start(serverPort, serverPath)
+	time.Sleep(100 * time.Millisecond)
+	s.client = newWebsocketClient(s.T(), nil)
+	err := s.client.Start(fmt.Sprintf("ws://localhost:%v%v", serverPort, testPath))
+	s.Require().NoError(err)
+	channel := <-connectedC
+	// By default, the server doesn't send pings
+	config := channel.TimeoutConfig()
+	s.Equal(time.Duration(0), config.PingPeriod)
+	s.Equal(defaultPingWait, config.ReadWait)
+	// Enable pings on the open channel
+	config.PingPeriod = 50 * time.Millisecond
+	config.PongWait = time.Second
+	channel.SetTimeoutConfig(config)
+	s.Equal(config, channel.TimeoutConfig())
+	time.Sleep(200 * time.Millisecond)
+	s.False(channel.Stats().LastPongIn.IsZero())
+	s.True(channel.IsConnected())
+	// Disable pings again
+	config.PingPeriod = 0
+	channel.SetTimeoutConfig(config)
+	time.Sleep(100 * time.Millisecond)
+	lastPong := channel.Stats().LastPongIn
+	time.Sleep(150 * time.Millisecond)
+	s.Equal(lastPong, channel.Stats().LastPongIn)
+}
+
+func (s *WebSocketSuite) TestClientSetTimeoutConfigLive() {
+	s.server = newWebsocketServer(s.T(), nil)
+	go s.server.Start(serverPort, serverPath)
+	time.Sleep(100 * time.Millisecond)
+	s.client = newWebsocketClient(s.T(), nil)
+	err := s.client.Start(fmt.Sprintf("ws://localhost:%v%v", serverPort, testPath))
+	s.Require().NoError(err)
+	s.True(s.client.webSocket.Stats().LastPongIn.IsZero())
+	// Change the ping period of the open connection
+	config := s.client.TimeoutConfig().WithPingPeriod(50 * time.Millisecond)
+	s.Equal(50*time.Millisecond*10/9, config.PongWait)
+	// Leave enough room for scheduling delays, the derived pong wait is too tight for such a short period
+	config.PongWait = time.Second
+	s.client.SetTimeoutConfig(config)
+	s.Equal(config, s.client.TimeoutConfig())
+	channelConfig := s.client.webSocket.TimeoutConfig()
+	s.Equal(50*time.Millisecond, channelConfig.PingPeriod)
+	s.Equal(config.PongWait, channelConfig.PongWait)
+	time.Sleep(200 * time.Millisecond)
+	s.False(s.client.webSocket.Stats().LastPongIn.IsZero())
+	s.True(s.client.IsConnected())
+}
+
+func (s *WebSocketSuite) TestClientSetTimeoutConfigDuringReconnect() {
+	s.server = newWebsocketServer(s.T(), nil)
+	go s.server.Start(serverPort, serverPath)
+	time.Sleep(100 * time.Millisecond)
+	s.client = newWebsocketClient(s.T(), nil)
+	err := s.client.Start(fmt.Sprintf("ws://localhost:%v%v", serverPort, testPath))
+	s.Require().NoError(err)
+	// Timeouts may be changed concurrently to a reconnection
+	doneC := make(chan struct{})
+	go func() {
+		defer close(doneC)
+		for i := 0; i < 50; i++ {
+			config := s.client.TimeoutConfig()
+			config.WriteWait = time.Duration(i+1) * time.Second
+			s.client.SetTimeoutConfig(config)
+			_ = s.client.IsConnected()
+		}
+	}()
+	err = s.client.Reconnect()
+	s.Require().NoError(err)
+	<-doneC
+	s.True(s.client.IsConnected())
+	s.Equal(50*time.Second, s.client.TimeoutConfig().WriteWait)
+}
+
+func newMemoryWebsocketPair(t *testing.T, network *MemoryNetwork) (*server, *client) {
+	wsServer, ok := network.NewServer().(*server)
+	require.True(t, ok)
+	wsServer.AddSupportedSubprotocol(defaultSubProtocol)
+	wsClient, ok := network.NewClient().(*client)
+	require.True(t, ok)
+	wsClient.SetRequestedSubProtocol(defaultSubProtocol)
+	return wsServer, wsClient
+}
+
+func (s *WebSocketSuite) TestMemoryNetwork() {
+	network := NewMemoryNetwork()
+	s.server, s.client = newMemoryWebsocketPair(s.T(), network)
+	s.server.SetBasicAuthHandler(func(username string, password string) bool {
+		return username == "testUsername" && password == "testPassword"
+	})
+	checkedC := make(chan string, 1)
+	s.server.SetCheckClientHandler(func(id string, r *http.Request) bool {
+		checkedC <- id
+		return true
+	})
+	connectedC := make(chan Channel, 1)
+	s.server.SetNewClientHandler(func(ws Channel) {
+		connectedC <- ws
+	})
+	s.server.SetMessageHandler(func(ws Channel, data []byte) error {
+		return s.server.Write(ws.ID(), data)
+	})
+	messageC := make(chan []byte, 1)
+	s.client.SetMessageHandler(func(data []byte) error {
+		messageC <- data
+		return nil
+	})
+	go s.server.Start(serverPort, serverPath)
+	time.Sleep(50 * time.Millisecond)
+	s.Require().NotNil(s.server.Addr())
+	s.Equal(serverPort, s.server.Addr().Port)
+	// Wrong credentials are rejected by the server
+	s.client.SetBasicAuth("testUsername", "wrongPassword")
+	err := s.client.Start(fmt.Sprintf("ws://localhost:%v%v", serverPort, testPath))
+	s.Require().Error(err)
+	s.client.SetBasicAuth("testUsername", "testPassword")
+	err = s.client.Start(fmt.Sprintf("ws://localhost:%v%v", serverPort, testPath))
+	s.Require().NoError(err)
+	s.Equal("testws", <-checkedC)
+	ws := <-connectedC
+	s.Equal("testws", ws.ID())
+	s.Equal(defaultSubProtocol, ws.Stats().Subprotocol)
+	s.Equal("127.0.0.1", ws.RemoteAddr().(*net.TCPAddr).IP.String())
+	s.Equal(1, network.ConnectionCount())
+	err = s.client.Write([]byte("hello"))
+	s.Require().NoError(err)
+	s.Equal([]byte("hello"), <-messageC)
+	// A different port is unreachable
+	otherClient := network.NewClient()
+	err = otherClient.Start(fmt.Sprintf("ws://localhost:%v%v", serverPort+1, testPath))
+	s.Error(err)
+}
+
+func (s *WebSocketSuite) TestMemoryNetworkFaults() {
+	network := NewMemoryNetwork()
+	s.server, s.client = newMemoryWebsocketPair(s.T(), network)
+	connectedC := make(chan struct{}, 1)
+	s.server.SetNewClientHandler(func(ws Channel) {
+		connectedC <- struct{}{}
+	})
+	messageC := make(chan []byte, 2)
+	s.server.SetMessageHandler(func(ws Channel, data []byte) error {
+		messageC <- data
+		return nil
+	})
+	go s.server.Start(serverPort, serverPath)
+	time.Sleep(50 * time.Millisecond)
+	u := fmt.Sprintf("ws://localhost:%v%v", serverPort, testPath)
+	// Refused connections
+	network.SetFaults(FaultConfig{RefuseConnections: true})
+	err := s.client.Start(u)
+	s.Require().Error(err)
+	network.SetFaults(FaultConfig{})
+	err = s.client.Start(u)
+	s.Require().NoError(err)
+	<-connectedC
+	// All messages are dropped, while the connection stays open
+	network.SetFaults(FaultConfig{DropRate: 1})
+	err = s.client.Write([]byte("dropped"))
+	s.Require().NoError(err)
+	select {
+	case <-messageC:
+		s.Fail("unexpected message")
+	case <-time.After(100 * time.Millisecond):
+	}
+	s.True(s.client.IsConnected())
+	// Latency delays delivery
+	latency := 50 * time.Millisecond
+	network.SetFaults(FaultConfig{Latency: latency})
+	start := time.Now()
+	err = s.client.Write([]byte("delayed"))
+	s.Require().NoError(err)
+	s.Equal([]byte("delayed"), <-messageC)
+	s.GreaterOrEqual(int64(time.Since(start)), int64(latency))
+}
+
+func (s *WebSocketSuite) TestMemoryNetworkDisconnect() {
+	network := NewMemoryNetwork()
+	s.server, s.client = newMemoryWebsocketPair(s.T(), network)
+	connectedC := make(chan Channel, 1)
+	s.server.SetNewClientHandler(func(ws Channel) {
+		connectedC <- ws
+	})
+	serverDisconnectedC := make(chan Channel, 1)
+	s.server.SetDisconnectedClientHandler(func(ws Channel) {
+		serverDisconnectedC <- ws
+	})
+	clientDisconnectedC := make(chan error, 1)
+	s.client.SetDisconnectedHandler(func(err error) {
+		clientDisconnectedC <- err
+	})
+	timeoutConfig := NewClientTimeoutConfig()
+	timeoutConfig.RetryBackOffWaitMinimum = 10 * time.Millisecond
+	timeoutConfig.RetryBackOffRandomRange = 0
+	s.client.SetTimeoutConfig(timeoutConfig)
+	go s.server.Start(serverPort, serverPath)
+	time.Sleep(50 * time.Millisecond)
+	err := s.client.Start(fmt.Sprintf("ws://localhost:%v%v", serverPort, testPath))
+	s.Require().NoError(err)
+	ws := <-connectedC
+	// Unknown connections are ignored
+	s.False(network.Disconnect(&net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 1}))
+	s.True(network.Disconnect(ws.RemoteAddr()))
+	disconnected := <-serverDisconnectedC
+	s.Equal(websocket.CloseAbnormalClosure, disconnected.DisconnectReason().Code)
+	s.Equal(DisconnectInitiatorRemote, disconnected.DisconnectReason().Initiator)
+	<-clientDisconnectedC
+	s.Equal(0, network.ConnectionCount())
+	// The client reconnects automatically
+	<-connectedC
+	s.Equal(1, network.DisconnectAll())
+	<-serverDisconnectedC
 }
 
 func TestWebSockets(t *testing.T) {